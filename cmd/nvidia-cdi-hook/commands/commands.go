@@ -20,9 +20,13 @@ import (
 	"github.com/urfave/cli/v3"
 
 	"github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-cdi-hook/chmod"
+	copyfiles "github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-cdi-hook/copy-files"
+	nvidiasmi "github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-cdi-hook/create-nvidia-smi-wrapper"
 	symlinks "github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-cdi-hook/create-symlinks"
 	"github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-cdi-hook/cudacompat"
 	disabledevicenodemodification "github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-cdi-hook/disable-device-node-modification"
+	xorgconfig "github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-cdi-hook/generate-xorg-config"
+	resetgpus "github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-cdi-hook/reset-gpus"
 	ldcache "github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-cdi-hook/update-ldcache"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
 )
@@ -36,6 +40,10 @@ func New(logger logger.Interface) []*cli.Command {
 		chmod.NewCommand(logger),
 		cudacompat.NewCommand(logger),
 		disabledevicenodemodification.NewCommand(logger),
+		xorgconfig.NewCommand(logger),
+		copyfiles.NewCommand(logger),
+		resetgpus.NewCommand(logger),
+		nvidiasmi.NewCommand(logger),
 	}
 }
 
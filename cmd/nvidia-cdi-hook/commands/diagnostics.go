@@ -0,0 +1,113 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package commands
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/oci"
+)
+
+// A DiagnosticsRecorder captures the container state passed to a hook on
+// STDIN and the log output of the hook. If the hook fails and the recording
+// of hook diagnostics is enabled for the container, the output is recorded
+// in the container bundle.
+type DiagnosticsRecorder struct {
+	logger *logrus.Logger
+	state  lockedBuffer
+	output lockedBuffer
+}
+
+// lockedBuffer is a buffer that is safe for concurrent use.
+type lockedBuffer struct {
+	sync.Mutex
+	bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.Lock()
+	defer b.Unlock()
+	return b.Buffer.Write(p)
+}
+
+func (b *lockedBuffer) bytes() []byte {
+	b.Lock()
+	defer b.Unlock()
+	return bytes.Clone(b.Buffer.Bytes())
+}
+
+// NewDiagnosticsRecorder creates a recorder that captures the output of the
+// specified logger and the contents of STDIN. Since STDIN is replaced by a
+// pipe, this must be called before the container state is read.
+func NewDiagnosticsRecorder(logger *logrus.Logger) *DiagnosticsRecorder {
+	d := &DiagnosticsRecorder{
+		logger: logger,
+	}
+	logger.SetOutput(io.MultiWriter(logger.Out, &d.output))
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		logger.Debugf("Failed to create pipe for container state: %v", err)
+		return d
+	}
+	stdin := os.Stdin
+	os.Stdin = r
+	go func() {
+		defer w.Close()
+		// The state is captured before it is forwarded so that it is
+		// available once the hook has read it.
+		_, _ = io.Copy(io.MultiWriter(&d.state, w), stdin)
+	}()
+	return d
+}
+
+// Record records the specified error returned by a hook along with the
+// captured output. Diagnostics are only recorded if the container state
+// enables this.
+func (d *DiagnosticsRecorder) Record(args []string, hookErr error) {
+	if hookErr == nil {
+		return
+	}
+	state, err := oci.ReadContainerState(bytes.NewReader(d.state.bytes()))
+	if err != nil {
+		return
+	}
+	path := state.HookDiagnosticsFilePath()
+	if path == "" {
+		return
+	}
+	if err := oci.WriteHookDiagnostics(path, getHookName(args), hookErr, d.output.bytes()); err != nil {
+		d.logger.Warningf("Failed to record hook diagnostics: %v", err)
+	}
+}
+
+// getHookName returns the name of the hook from the specified command line
+// arguments. This is the first argument that is not a flag.
+func getHookName(args []string) string {
+	for _, arg := range args[1:] {
+		if !strings.HasPrefix(arg, "-") {
+			return arg
+		}
+	}
+	return "unknown"
+}
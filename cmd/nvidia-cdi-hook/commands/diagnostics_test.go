@@ -0,0 +1,75 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/oci"
+)
+
+func TestDiagnosticsRecorder(t *testing.T) {
+	testCases := []struct {
+		description      string
+		annotations      string
+		expectedContents string
+	}{
+		{
+			description: "diagnostics not enabled",
+			annotations: `{}`,
+		},
+		{
+			description: "diagnostics enabled",
+			annotations: `{"nvidia.com/hook-diagnostics": "ignored"}`,
+			expectedContents: `hook: create-symlinks
+error: failed to create link
+output:
+  some output
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			logger, _ := testlog.NewNullLogger()
+			bundleDir := t.TempDir()
+
+			d := &DiagnosticsRecorder{
+				logger: logger,
+			}
+			_, _ = fmt.Fprintf(&d.state, `{"ociVersion": "1.0.0", "bundle": %q, "annotations": %s}`, bundleDir, tc.annotations)
+			_, _ = d.output.Write([]byte("some output\n"))
+
+			d.Record([]string{"nvidia-cdi-hook", "--debug", "create-symlinks", "--link", "a::b"}, errors.New("failed to create link"))
+
+			contents, err := os.ReadFile(filepath.Join(bundleDir, oci.HookDiagnosticsFileName))
+			if tc.expectedContents == "" {
+				require.ErrorIs(t, err, os.ErrNotExist)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedContents, string(contents))
+		})
+	}
+}
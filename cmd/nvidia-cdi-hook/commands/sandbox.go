@@ -0,0 +1,103 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/syndtr/gocapability/capability"
+	"github.com/urfave/cli/v3"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/sandbox"
+)
+
+// sandboxProfiles defines the restrictions applied to each of the hooks if
+// hooks are sandboxed. Hooks that create mounts in the container (such as the
+// disable-device-node-modification hook) do not use a private mount
+// namespace.
+var sandboxProfiles = map[string]sandbox.Profile{
+	"chmod": {
+		Capabilities:  []capability.Cap{capability.CAP_DAC_OVERRIDE, capability.CAP_FOWNER},
+		PrivateMounts: true,
+	},
+	"copy-files": {
+		Capabilities:  []capability.Cap{capability.CAP_DAC_OVERRIDE, capability.CAP_FOWNER},
+		PrivateMounts: true,
+	},
+	"create-nvidia-smi-wrapper": {
+		Capabilities:  []capability.Cap{capability.CAP_DAC_OVERRIDE, capability.CAP_FOWNER},
+		PrivateMounts: true,
+	},
+	"create-symlinks": {
+		Capabilities:  []capability.Cap{capability.CAP_DAC_OVERRIDE, capability.CAP_FOWNER},
+		PrivateMounts: true,
+	},
+	"disable-device-node-modification": {
+		Capabilities: []capability.Cap{capability.CAP_DAC_OVERRIDE, capability.CAP_FOWNER, capability.CAP_SYS_ADMIN},
+	},
+	"enable-cuda-compat": {
+		Capabilities:  []capability.Cap{capability.CAP_DAC_OVERRIDE, capability.CAP_FOWNER},
+		PrivateMounts: true,
+	},
+	"generate-xorg-config": {
+		Capabilities:  []capability.Cap{capability.CAP_DAC_OVERRIDE, capability.CAP_FOWNER},
+		PrivateMounts: true,
+	},
+	"reset-gpus": {
+		Capabilities:  []capability.Cap{capability.CAP_DAC_OVERRIDE, capability.CAP_SYS_ADMIN},
+		PrivateMounts: true,
+	},
+	"update-ldcache": {
+		Capabilities:  []capability.Cap{capability.CAP_DAC_OVERRIDE, capability.CAP_FOWNER, capability.CAP_SYS_ADMIN, capability.CAP_SYS_CHROOT},
+		PrivateMounts: true,
+	},
+}
+
+// SandboxFlag returns the flag used to enable sandboxed hook execution.
+func SandboxFlag(destination *bool) cli.Flag {
+	return &cli.BoolFlag{
+		Name:        "sandbox",
+		Usage:       "Run the hook with reduced privileges",
+		Destination: destination,
+		Sources:     cli.EnvVars("NVIDIA_CTK_SANDBOX_HOOKS"),
+	}
+}
+
+// RunSandboxed runs the hook specified in the arguments of the command in a
+// sandbox. If the hook is run, an error containing the exit code of the
+// sandboxed process is returned to ensure that the hook is not run again
+// without restrictions. Hooks without a sandbox profile are run as is.
+func RunSandboxed(ctx context.Context, logger logger.Interface, cmd *cli.Command) (context.Context, error) {
+	if sandbox.IsSandboxed() {
+		return ctx, nil
+	}
+	name := cmd.Args().First()
+	profile, ok := sandboxProfiles[name]
+	if !ok {
+		logger.Debugf("No sandbox profile defined for hook %q", name)
+		return ctx, nil
+	}
+
+	exitCode, err := sandbox.Run(profile, os.Args[1:])
+	if err != nil {
+		return ctx, fmt.Errorf("failed to run hook %q in sandbox: %w", name, err)
+	}
+	return ctx, cli.Exit("", exitCode)
+}
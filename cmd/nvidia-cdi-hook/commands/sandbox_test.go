@@ -0,0 +1,38 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package commands
+
+import (
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSandboxProfilesMatchHooks(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	var names []string
+	for _, c := range New(logger) {
+		names = append(names, c.Name)
+	}
+
+	for name := range sandboxProfiles {
+		require.Contains(t, names, name)
+	}
+	require.Len(t, sandboxProfiles, len(names))
+}
@@ -0,0 +1,169 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package copyfiles
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/moby/sys/symlink"
+	"github.com/urfave/cli/v3"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/oci"
+)
+
+type command struct {
+	logger logger.Interface
+}
+
+type config struct {
+	files         []string
+	containerSpec string
+}
+
+// NewCommand constructs a hook command with the specified logger
+func NewCommand(logger logger.Interface) *cli.Command {
+	c := command{
+		logger: logger,
+	}
+	return c.build()
+}
+
+// build creates the copy-files command.
+func (m command) build() *cli.Command {
+	cfg := config{}
+
+	c := cli.Command{
+		Name:  "copy-files",
+		Usage: "A hook to copy files from the host into the container instead of bind-mounting them.",
+		Action: func(_ context.Context, cmd *cli.Command) error {
+			return m.run(&cfg)
+		},
+		Flags: []cli.Flag{
+			&cli.StringSliceFlag{
+				Name:        "file",
+				Usage:       "Specify a file to copy. The file is specified as hostPath::containerPath. If the file exists in the container root, it is replaced.",
+				Destination: &cfg.files,
+			},
+			// The following flags are testing-only flags.
+			&cli.StringFlag{
+				Name:        "container-spec",
+				Usage:       "Specify the path to the OCI container spec. If empty or '-' the spec will be read from STDIN. This is only intended for testing.",
+				Destination: &cfg.containerSpec,
+				Hidden:      true,
+			},
+		},
+	}
+
+	return &c
+}
+
+func (m command) run(cfg *config) error {
+	s, err := oci.LoadContainerState(cfg.containerSpec)
+	if err != nil {
+		return fmt.Errorf("failed to load container state: %v", err)
+	}
+
+	containerRoot, err := s.GetContainerRoot()
+	if err != nil {
+		return fmt.Errorf("failed to determined container root: %v", err)
+	}
+
+	for _, f := range cfg.files {
+		hostPath, containerPath, found := strings.Cut(f, "::")
+		if !found || hostPath == "" || containerPath == "" {
+			return fmt.Errorf("invalid file specification %v", f)
+		}
+		if err := m.copyFile(containerRoot, hostPath, containerPath); err != nil {
+			return fmt.Errorf("failed to copy %v: %w", f, err)
+		}
+	}
+	return nil
+}
+
+// copyFile copies the specified host file to the specified path in the
+// container root. The parent of the target path is resolved in the container
+// root so that symlinks in the container cannot redirect the copy to the host.
+func (m command) copyFile(containerRoot string, hostPath string, containerPath string) error {
+	resolvedParent, err := symlink.FollowSymlinkInScope(filepath.Join(containerRoot, filepath.Dir(containerPath)), containerRoot)
+	if err != nil {
+		return fmt.Errorf("failed to follow path for %v relative to %v: %w", containerPath, containerRoot, err)
+	}
+	if err := os.MkdirAll(resolvedParent, 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	target := filepath.Join(resolvedParent, filepath.Base(containerPath))
+
+	err = replace(target, func(tmp string) error {
+		return copyContents(hostPath, tmp)
+	})
+	if err != nil {
+		return err
+	}
+	m.logger.Debugf("Copied %v to %v", hostPath, target)
+	return nil
+}
+
+// replace creates a file at a temporary path next to the specified target
+// using the specified function and then renames it to the target. This
+// ensures that an existing file at the target is replaced atomically.
+func replace(target string, create func(string) error) error {
+	tmp := filepath.Join(filepath.Dir(target), "."+filepath.Base(target)+".nvct-tmp")
+	_ = os.Remove(tmp)
+	if err := create(tmp); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, target); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// copyContents copies the contents and file mode of the source file to the
+// specified destination.
+func copyContents(source string, destination string) error {
+	src, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("%v is not a regular file", source)
+	}
+
+	dst, err := os.OpenFile(destination, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		_ = dst.Close()
+		return err
+	}
+	return dst.Close()
+}
@@ -0,0 +1,83 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package copyfiles
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCopyFile(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	testCases := []struct {
+		description   string
+		existing      bool
+		containerLink string
+	}{
+		{
+			description: "file is copied",
+		},
+		{
+			description: "existing file is replaced",
+			existing:    true,
+		},
+		{
+			description:   "absolute symlink resolves to container root",
+			containerLink: "/",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			hostRoot := t.TempDir()
+			containerRoot := t.TempDir()
+
+			hostPath := filepath.Join(hostRoot, "libfoo.so.1")
+			require.NoError(t, os.WriteFile(hostPath, []byte("host"), 0755))
+
+			if tc.existing {
+				require.NoError(t, os.MkdirAll(filepath.Join(containerRoot, "lib"), 0755))
+				require.NoError(t, os.WriteFile(filepath.Join(containerRoot, "lib/libfoo.so.1"), []byte("container"), 0644))
+			}
+			expectedPath := filepath.Join(containerRoot, "lib/libfoo.so.1")
+			if tc.containerLink != "" {
+				require.NoError(t, os.Symlink(tc.containerLink, filepath.Join(containerRoot, "lib")))
+				expectedPath = filepath.Join(containerRoot, "libfoo.so.1")
+			}
+
+			c := command{logger: logger}
+			require.NoError(t, c.copyFile(containerRoot, hostPath, "/lib/libfoo.so.1"))
+
+			contents, err := os.ReadFile(expectedPath)
+			require.NoError(t, err)
+			require.Equal(t, "host", string(contents))
+
+			info, err := os.Stat(expectedPath)
+			require.NoError(t, err)
+			require.Equal(t, os.FileMode(0755), info.Mode().Perm())
+
+			hostInfo, err := os.Stat(hostPath)
+			require.NoError(t, err)
+			require.False(t, os.SameFile(hostInfo, info))
+		})
+	}
+}
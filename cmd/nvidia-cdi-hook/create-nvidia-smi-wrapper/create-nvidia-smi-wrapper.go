@@ -0,0 +1,210 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package nvidiasmi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/moby/sys/symlink"
+	"github.com/urfave/cli/v3"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/oci"
+)
+
+const (
+	defaultWrapperPath = "/usr/bin/nvidia-smi"
+)
+
+type command struct {
+	logger logger.Interface
+}
+
+type options struct {
+	path          string
+	nvidiaSMIPath string
+	devices       []string
+	containerSpec string
+}
+
+// NewCommand constructs a create-nvidia-smi-wrapper command with the specified logger
+func NewCommand(logger logger.Interface) *cli.Command {
+	c := command{
+		logger: logger,
+	}
+	return c.build()
+}
+
+// build the create-nvidia-smi-wrapper command
+func (m command) build() *cli.Command {
+	cfg := options{}
+
+	c := cli.Command{
+		Name:  "create-nvidia-smi-wrapper",
+		Usage: "Create a wrapper for nvidia-smi in the container that only reports on the specified GPUs",
+		Before: func(ctx context.Context, cmd *cli.Command) (context.Context, error) {
+			return ctx, m.validateFlags(&cfg)
+		},
+		Action: func(_ context.Context, cmd *cli.Command) error {
+			return m.run(&cfg)
+		},
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "path",
+				Usage:       "Specify the path of the wrapper in the container.",
+				Value:       defaultWrapperPath,
+				Destination: &cfg.path,
+			},
+			&cli.StringFlag{
+				Name:        "nvidia-smi",
+				Usage:       "Specify the path of the nvidia-smi executable in the container that is invoked by the wrapper.",
+				Destination: &cfg.nvidiaSMIPath,
+			},
+			&cli.StringSliceFlag{
+				Name:        "device",
+				Usage:       "Specify the UUID of a GPU that the output of nvidia-smi is restricted to.",
+				Destination: &cfg.devices,
+			},
+			&cli.StringFlag{
+				Name:        "container-spec",
+				Hidden:      true,
+				Usage:       "Specify the path to the OCI container spec. If empty or '-' the spec will be read from STDIN",
+				Destination: &cfg.containerSpec,
+			},
+		},
+	}
+
+	return &c
+}
+
+func (m command) validateFlags(cfg *options) error {
+	if !filepath.IsAbs(cfg.path) {
+		return fmt.Errorf("the wrapper path must be absolute: %q", cfg.path)
+	}
+	if !filepath.IsAbs(cfg.nvidiaSMIPath) {
+		return fmt.Errorf("the nvidia-smi path must be absolute: %q", cfg.nvidiaSMIPath)
+	}
+	if strings.ContainsAny(cfg.nvidiaSMIPath, "'\n") {
+		return fmt.Errorf("invalid nvidia-smi path: %q", cfg.nvidiaSMIPath)
+	}
+	if filepath.Clean(cfg.path) == filepath.Clean(cfg.nvidiaSMIPath) {
+		return fmt.Errorf("the wrapper path must differ from the nvidia-smi path")
+	}
+	if len(cfg.devices) == 0 {
+		return fmt.Errorf("at least one device must be specified")
+	}
+	for _, device := range cfg.devices {
+		if !isValidDevice(device) {
+			return fmt.Errorf("invalid device %q", device)
+		}
+	}
+	return nil
+}
+
+func (m command) run(cfg *options) error {
+	s, err := oci.LoadContainerState(cfg.containerSpec)
+	if err != nil {
+		return fmt.Errorf("failed to load container state: %w", err)
+	}
+
+	containerRoot, err := s.GetContainerRoot()
+	if err != nil {
+		return fmt.Errorf("failed to determined container root: %w", err)
+	}
+	if containerRoot == "" {
+		m.logger.Warningf("No container root detected")
+		return nil
+	}
+
+	return m.createWrapper(containerRoot, cfg)
+}
+
+// createWrapper writes the nvidia-smi wrapper to the specified path in the
+// container root. An existing file at this path is replaced.
+func (m command) createWrapper(containerRoot string, cfg *options) error {
+	wrapperPath, err := symlink.FollowSymlinkInScope(filepath.Join(containerRoot, cfg.path), containerRoot)
+	if err != nil {
+		return fmt.Errorf("failed to resolve wrapper path: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(wrapperPath), 0755); err != nil {
+		return fmt.Errorf("failed to create wrapper directory: %w", err)
+	}
+	// We remove an existing file instead of truncating it to ensure that a
+	// file that is hardlinked elsewhere is not modified.
+	if err := os.Remove(wrapperPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove existing file: %w", err)
+	}
+
+	m.logger.Infof("Creating nvidia-smi wrapper %v for devices %v", cfg.path, cfg.devices)
+	if err := os.WriteFile(wrapperPath, newWrapper(cfg.nvidiaSMIPath, cfg.devices), 0755); err != nil {
+		return fmt.Errorf("failed to write wrapper: %w", err)
+	}
+	return nil
+}
+
+// newWrapper returns the contents of a shell script that invokes the specified
+// nvidia-smi executable for the specified devices.
+// If no GPUs are selected explicitly (using -i or --id), the devices are added
+// to the arguments. Subcommands (e.g. nvidia-smi topo) and requests for help
+// are passed through as is since these do not accept the --id flag.
+func newWrapper(nvidiaSMIPath string, devices []string) []byte {
+	var b bytes.Buffer
+	b.WriteString("#!/bin/sh\n")
+	b.WriteString("# Generated by the NVIDIA Container Toolkit.\n")
+	b.WriteString("# Restricts the output of nvidia-smi to the GPUs injected into the container.\n")
+	fmt.Fprintf(&b, "NVIDIA_SMI='%s'\n", nvidiaSMIPath)
+	fmt.Fprintf(&b, "DEVICES='%s'\n", strings.Join(devices, ","))
+	b.WriteString(`
+case "$1" in
+"" | -*) ;;
+*) exec "$NVIDIA_SMI" "$@" ;;
+esac
+
+for arg in "$@"; do
+	case "$arg" in
+	-i* | --id | --id=* | -h | --help) exec "$NVIDIA_SMI" "$@" ;;
+	esac
+done
+
+exec "$NVIDIA_SMI" "$@" --id="$DEVICES"
+`)
+	return b.Bytes()
+}
+
+// isValidDevice checks whether the specified device can be safely included in
+// the generated wrapper. Device UUIDs and PCI bus IDs only contain
+// alphanumeric characters, dashes, colons, and periods.
+func isValidDevice(device string) bool {
+	if device == "" {
+		return false
+	}
+	for _, c := range device {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == ':', c == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,147 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package nvidiasmi
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateFlags(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	testCases := []struct {
+		description   string
+		options       options
+		expectedError bool
+	}{
+		{
+			description: "valid options",
+			options: options{
+				path:          "/usr/bin/nvidia-smi",
+				nvidiaSMIPath: "/usr/local/nvidia-container-toolkit/bin/nvidia-smi",
+				devices:       []string{"GPU-8f3c1a2b-0000-1111-2222-333344445555"},
+			},
+		},
+		{
+			description: "relative nvidia-smi path is invalid",
+			options: options{
+				path:          "/usr/bin/nvidia-smi",
+				nvidiaSMIPath: "nvidia-smi",
+				devices:       []string{"GPU-0"},
+			},
+			expectedError: true,
+		},
+		{
+			description: "identical paths are invalid",
+			options: options{
+				path:          "/usr/bin/nvidia-smi",
+				nvidiaSMIPath: "/usr/bin/nvidia-smi",
+				devices:       []string{"GPU-0"},
+			},
+			expectedError: true,
+		},
+		{
+			description: "no devices is invalid",
+			options: options{
+				path:          "/usr/bin/nvidia-smi",
+				nvidiaSMIPath: "/usr/local/nvidia-container-toolkit/bin/nvidia-smi",
+			},
+			expectedError: true,
+		},
+		{
+			description: "device with shell characters is invalid",
+			options: options{
+				path:          "/usr/bin/nvidia-smi",
+				nvidiaSMIPath: "/usr/local/nvidia-container-toolkit/bin/nvidia-smi",
+				devices:       []string{"GPU-0'; reboot"},
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			err := command{logger: logger}.validateFlags(&tc.options)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestWrapper(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	containerRoot := t.TempDir()
+	nvidiaSMIPath := filepath.Join(containerRoot, "nvidia-smi.real")
+	require.NoError(t, os.WriteFile(nvidiaSMIPath, []byte("#!/bin/sh\necho \"$@\"\n"), 0755))
+
+	cfg := &options{
+		path:          "/usr/bin/nvidia-smi",
+		nvidiaSMIPath: nvidiaSMIPath,
+		devices:       []string{"GPU-0", "GPU-1"},
+	}
+	require.NoError(t, command{logger: logger}.createWrapper(containerRoot, cfg))
+
+	wrapperPath := filepath.Join(containerRoot, "usr/bin/nvidia-smi")
+	info, err := os.Stat(wrapperPath)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0755), info.Mode().Perm())
+
+	testCases := []struct {
+		description  string
+		args         []string
+		expectedArgs string
+	}{
+		{
+			description:  "no arguments selects devices",
+			expectedArgs: "--id=GPU-0,GPU-1",
+		},
+		{
+			description:  "query selects devices",
+			args:         []string{"--query-gpu=uuid", "--format=csv"},
+			expectedArgs: "--query-gpu=uuid --format=csv --id=GPU-0,GPU-1",
+		},
+		{
+			description:  "explicit id is not modified",
+			args:         []string{"-q", "-i", "GPU-1"},
+			expectedArgs: "-q -i GPU-1",
+		},
+		{
+			description:  "subcommand is not modified",
+			args:         []string{"topo", "-m"},
+			expectedArgs: "topo -m",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			output, err := exec.Command(wrapperPath, tc.args...).Output()
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedArgs, strings.TrimSpace(string(output)))
+		})
+	}
+}
@@ -29,11 +29,12 @@ import (
 
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup/symlinks"
-	"github.com/NVIDIA/nvidia-container-toolkit/internal/oci"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/oci"
 )
 
 type command struct {
 	logger logger.Interface
+	dryRun bool
 }
 
 type config struct {
@@ -65,6 +66,11 @@ func (m command) build() *cli.Command {
 				Usage:       "Specify a specific link to create. The link is specified as target::link. If the link exists in the container root, it is removed.",
 				Destination: &cfg.links,
 			},
+			&cli.BoolFlag{
+				Name:        "dry-run",
+				Usage:       "Log the operations that would be performed without modifying the container root.",
+				Destination: &m.dryRun,
+			},
 			// The following flags are testing-only flags.
 			&cli.StringFlag{
 				Name:        "container-spec",
@@ -117,7 +123,11 @@ func (m command) run(_ *cli.Command, cfg *config) error {
 // If the specified link already exists and points to the same target, this
 // operation is a no-op.
 // If a file exists at the link path or the link points to a different target
-// this file is removed before creating the link.
+// this file is removed before creating the link. If the link is created
+// concurrently (e.g. by another hook acting on the same container root) and
+// points to the same target, this is not treated as an error.
+//
+// If dry-run is enabled, the operations are logged but not performed.
 //
 // Note that if the link path resolves to an absolute path oudside of the
 // specified root, this is treated as an absolute path in this root.
@@ -142,12 +152,23 @@ func (m command) createLink(containerRoot string, targetPath string, link string
 	}
 	resolvedLinkPath := filepath.Join(resolvedLinkParent, filepath.Base(linkPath))
 
+	if m.dryRun {
+		m.logger.Infof("Would symlink %v to %v", resolvedLinkPath, targetPath)
+		return nil
+	}
+
 	m.logger.Infof("Symlinking %v to %v", resolvedLinkPath, targetPath)
 	err = os.MkdirAll(filepath.Dir(resolvedLinkPath), 0755)
 	if err != nil {
 		return fmt.Errorf("failed to create directory: %v", err)
 	}
 	err = symlinks.ForceCreate(targetPath, resolvedLinkPath)
+	if errors.Is(err, os.ErrExist) {
+		if exists, _ := linkExists(targetPath, resolvedLinkPath); exists {
+			m.logger.Debugf("Link %s was created concurrently", resolvedLinkPath)
+			return nil
+		}
+	}
 	if err != nil {
 		return fmt.Errorf("failed to create symlink: %v", err)
 	}
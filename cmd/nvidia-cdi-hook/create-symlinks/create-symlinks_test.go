@@ -263,6 +263,48 @@ func TestCreateLinkOutOfBounds(t *testing.T) {
 	require.DirExists(t, filepath.Join(hostRoot, "libfoo.so"))
 }
 
+func TestCreateLinkDryRun(t *testing.T) {
+	tmpDir := t.TempDir()
+	containerRoot := filepath.Join(tmpDir, "/container-root")
+
+	require.NoError(t, makeFs(containerRoot, dirOrLink{path: "/lib/libfoo.so", target: "different-target"}))
+
+	c := getTestCommand()
+	c.dryRun = true
+
+	// nvidia-cdi-hook create-symlinks --dry-run --link libfoo.so.1::/lib/libfoo.so
+	err := c.createLink(containerRoot, "libfoo.so.1", "/lib/libfoo.so")
+	require.NoError(t, err)
+
+	target, err := symlinks.Resolve(filepath.Join(containerRoot, "/lib/libfoo.so"))
+	require.NoError(t, err)
+	require.Equal(t, "different-target", target)
+
+	// nvidia-cdi-hook create-symlinks --dry-run --link libbar.so.1::/lib/libbar.so
+	err = c.createLink(containerRoot, "libbar.so.1", "/lib/libbar.so")
+	require.NoError(t, err)
+
+	_, err = os.Lstat(filepath.Join(containerRoot, "/lib/libbar.so"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestCreateLinkIsIdempotent(t *testing.T) {
+	tmpDir := t.TempDir()
+	containerRoot := filepath.Join(tmpDir, "/container-root")
+
+	require.NoError(t, makeFs(containerRoot, dirOrLink{path: "/lib/"}))
+
+	for i := 0; i < 2; i++ {
+		// nvidia-cdi-hook create-symlinks --link libfoo.so.1::/lib/libfoo.so
+		err := getTestCommand().createLink(containerRoot, "libfoo.so.1", "/lib/libfoo.so")
+		require.NoError(t, err)
+
+		target, err := symlinks.Resolve(filepath.Join(containerRoot, "/lib/libfoo.so"))
+		require.NoError(t, err)
+		require.Equal(t, "libfoo.so.1", target)
+	}
+}
+
 type dirOrLink struct {
 	path   string
 	target string
@@ -0,0 +1,231 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package xorgconfig
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/moby/sys/symlink"
+	"github.com/urfave/cli/v3"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/info/proc"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/oci"
+)
+
+const (
+	defaultConfigFile = "/etc/X11/xorg.conf.d/90-nvidia-container.conf"
+)
+
+// defaultModulePaths are the standard Xorg module paths. Since specifying a
+// ModulePath overrides the default search path of the Xorg server, the paths
+// that exist in the container are appended to the generated config.
+var defaultModulePaths = []string{
+	"/usr/lib/xorg/modules",
+	"/usr/lib64/xorg/modules",
+	"/usr/X11R6/lib/modules",
+	"/usr/X11R6/lib64/modules",
+}
+
+type command struct {
+	logger logger.Interface
+}
+
+type options struct {
+	modulePaths   []string
+	busIDs        []string
+	configFile    string
+	containerSpec string
+}
+
+// NewCommand constructs a generate-xorg-config command with the specified logger
+func NewCommand(logger logger.Interface) *cli.Command {
+	c := command{
+		logger: logger,
+	}
+	return c.build()
+}
+
+// build the generate-xorg-config command
+func (m command) build() *cli.Command {
+	cfg := options{}
+
+	c := cli.Command{
+		Name:  "generate-xorg-config",
+		Usage: "Generate an xorg.conf snippet in the container for the injected NVIDIA Xorg driver modules",
+		Action: func(_ context.Context, cmd *cli.Command) error {
+			return m.run(cmd, &cfg)
+		},
+		Flags: []cli.Flag{
+			&cli.StringSliceFlag{
+				Name:        "module-path",
+				Usage:       "Specify a path in the container containing the NVIDIA Xorg driver modules.",
+				Destination: &cfg.modulePaths,
+			},
+			&cli.StringSliceFlag{
+				Name:        "busid",
+				Usage:       "Specify the PCI bus ID of a GPU to add a Device section for.",
+				Destination: &cfg.busIDs,
+			},
+			&cli.StringFlag{
+				Name:        "config-file",
+				Usage:       "Specify the path of the generated config file in the container. If the file exists, it is not modified.",
+				Value:       defaultConfigFile,
+				Destination: &cfg.configFile,
+			},
+			&cli.StringFlag{
+				Name:        "container-spec",
+				Hidden:      true,
+				Usage:       "Specify the path to the OCI container spec. If empty or '-' the spec will be read from STDIN",
+				Destination: &cfg.containerSpec,
+			},
+		},
+	}
+
+	return &c
+}
+
+func (m command) run(_ *cli.Command, cfg *options) error {
+	s, err := oci.LoadContainerState(cfg.containerSpec)
+	if err != nil {
+		return fmt.Errorf("failed to load container state: %w", err)
+	}
+
+	containerRoot, err := s.GetContainerRoot()
+	if err != nil {
+		return fmt.Errorf("failed to determined container root: %w", err)
+	}
+	if containerRoot == "" {
+		m.logger.Warningf("No container root detected")
+		return nil
+	}
+
+	return m.generateConfig(containerRoot, cfg)
+}
+
+// generateConfig writes the xorg.conf snippet to the config file in the
+// specified container root. An existing config file (e.g. one provided by
+// the container image) is never overwritten.
+func (m command) generateConfig(containerRoot string, cfg *options) error {
+	configPath, err := symlink.FollowSymlinkInScope(filepath.Join(containerRoot, cfg.configFile), containerRoot)
+	if err != nil {
+		return fmt.Errorf("failed to resolve config file path: %w", err)
+	}
+	if _, err := os.Stat(configPath); err == nil {
+		m.logger.Infof("Xorg config %v already exists; skipping", cfg.configFile)
+		return nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to check for existing config file: %w", err)
+	}
+
+	var modulePaths []string
+	modulePaths = append(modulePaths, cfg.modulePaths...)
+	for _, p := range defaultModulePaths {
+		resolved, err := symlink.FollowSymlinkInScope(filepath.Join(containerRoot, p), containerRoot)
+		if err != nil {
+			continue
+		}
+		if info, err := os.Stat(resolved); err == nil && info.IsDir() {
+			modulePaths = append(modulePaths, p)
+		}
+	}
+
+	contents, err := newXorgConfig(modulePaths, cfg.busIDs)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	m.logger.Infof("Writing Xorg config to %v", cfg.configFile)
+	if err := os.WriteFile(configPath, contents, 0644); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
+
+// newXorgConfig returns the contents of an xorg.conf snippet that adds the
+// specified module paths and a Device section using the nvidia driver for
+// each of the specified PCI bus IDs.
+func newXorgConfig(modulePaths []string, busIDs []string) ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString("# Generated by the NVIDIA Container Toolkit.\n")
+
+	seen := make(map[string]bool)
+	var paths []string
+	for _, p := range modulePaths {
+		if p == "" || seen[p] {
+			continue
+		}
+		seen[p] = true
+		paths = append(paths, p)
+	}
+	if len(paths) > 0 {
+		b.WriteString("\nSection \"Files\"\n")
+		for _, p := range paths {
+			fmt.Fprintf(&b, "    ModulePath \"%s\"\n", p)
+		}
+		b.WriteString("EndSection\n")
+	}
+
+	if len(busIDs) == 0 {
+		b.WriteString("\nSection \"Device\"\n    Identifier \"nvidia0\"\n    Driver \"nvidia\"\nEndSection\n")
+		return b.Bytes(), nil
+	}
+	for i, busID := range busIDs {
+		xorgBusID, err := toXorgBusID(busID)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&b, "\nSection \"Device\"\n    Identifier \"nvidia%d\"\n    Driver \"nvidia\"\n    BusID \"%s\"\nEndSection\n", i, xorgBusID)
+	}
+	return b.Bytes(), nil
+}
+
+// toXorgBusID converts a PCI bus ID of the form DOMAIN:BUS:DEVICE.FUNCTION
+// to the form used by Xorg. Xorg expects decimal values of the form
+// PCI:BUS@DOMAIN:DEVICE:FUNCTION where the domain may be omitted if it is 0.
+func toXorgBusID(busID string) (string, error) {
+	if !proc.IsPCIBusID(busID) {
+		return "", fmt.Errorf("invalid PCI bus ID %q", busID)
+	}
+	domainBusDevice, function, _ := strings.Cut(busID, ".")
+	parts := strings.Split(domainBusDevice, ":")
+
+	var values []uint64
+	for _, part := range append(parts, function) {
+		v, err := strconv.ParseUint(part, 16, 32)
+		if err != nil {
+			return "", fmt.Errorf("invalid PCI bus ID %q: %w", busID, err)
+		}
+		values = append(values, v)
+	}
+	domain, bus, device, fn := values[0], values[1], values[2], values[3]
+
+	if domain == 0 {
+		return fmt.Sprintf("PCI:%d:%d:%d", bus, device, fn), nil
+	}
+	return fmt.Sprintf("PCI:%d@%d:%d:%d", bus, domain, device, fn), nil
+}
@@ -0,0 +1,141 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package xorgconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToXorgBusID(t *testing.T) {
+	testCases := []struct {
+		busID         string
+		expected      string
+		expectedError bool
+	}{
+		{busID: "0000:01:00.0", expected: "PCI:1:0:0"},
+		{busID: "00000000:3B:00.1", expected: "PCI:59:0:1"},
+		{busID: "0001:af:1f.7", expected: "PCI:175@1:31:7"},
+		{busID: "GPU-0", expectedError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.busID, func(t *testing.T) {
+			xorgBusID, err := toXorgBusID(tc.busID)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, xorgBusID)
+		})
+	}
+}
+
+func TestGenerateConfig(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	testCases := []struct {
+		description      string
+		contents         map[string]string
+		directories      []string
+		modulePaths      []string
+		busIDs           []string
+		expectedContents string
+	}{
+		{
+			description: "no bus IDs adds generic device",
+			modulePaths: []string{"/usr/lib/x86_64-linux-gnu/nvidia/xorg"},
+			expectedContents: `# Generated by the NVIDIA Container Toolkit.
+
+Section "Files"
+    ModulePath "/usr/lib/x86_64-linux-gnu/nvidia/xorg"
+EndSection
+
+Section "Device"
+    Identifier "nvidia0"
+    Driver "nvidia"
+EndSection
+`,
+		},
+		{
+			description: "default module paths in container are appended",
+			directories: []string{"/usr/lib/xorg/modules"},
+			modulePaths: []string{"/usr/lib/x86_64-linux-gnu/nvidia/xorg", "/usr/lib/x86_64-linux-gnu/nvidia/xorg"},
+			busIDs:      []string{"0000:01:00.0", "0000:02:00.0"},
+			expectedContents: `# Generated by the NVIDIA Container Toolkit.
+
+Section "Files"
+    ModulePath "/usr/lib/x86_64-linux-gnu/nvidia/xorg"
+    ModulePath "/usr/lib/xorg/modules"
+EndSection
+
+Section "Device"
+    Identifier "nvidia0"
+    Driver "nvidia"
+    BusID "PCI:1:0:0"
+EndSection
+
+Section "Device"
+    Identifier "nvidia1"
+    Driver "nvidia"
+    BusID "PCI:2:0:0"
+EndSection
+`,
+		},
+		{
+			description: "existing config is not modified",
+			contents: map[string]string{
+				defaultConfigFile: "# from image\n",
+			},
+			modulePaths:      []string{"/usr/lib/x86_64-linux-gnu/nvidia/xorg"},
+			expectedContents: "# from image\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			containerRoot := t.TempDir()
+			for name, contents := range tc.contents {
+				target := filepath.Join(containerRoot, name)
+				require.NoError(t, os.MkdirAll(filepath.Dir(target), 0755))
+				require.NoError(t, os.WriteFile(target, []byte(contents), 0600))
+			}
+			for _, dir := range tc.directories {
+				require.NoError(t, os.MkdirAll(filepath.Join(containerRoot, dir), 0755))
+			}
+
+			c := command{
+				logger: logger,
+			}
+			cfg := &options{
+				modulePaths: tc.modulePaths,
+				busIDs:      tc.busIDs,
+				configFile:  defaultConfigFile,
+			}
+			require.NoError(t, c.generateConfig(containerRoot, cfg))
+
+			contents, err := os.ReadFile(filepath.Join(containerRoot, defaultConfigFile))
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedContents, string(contents))
+		})
+	}
+}
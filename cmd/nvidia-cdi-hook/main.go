@@ -36,6 +36,8 @@ type options struct {
 	Debug bool
 	// Quiet indicates whether the CLI is started in "quiet" mode
 	Quiet bool
+	// Sandbox indicates whether hooks are run with reduced privileges
+	Sandbox bool
 }
 
 func main() {
@@ -59,6 +61,9 @@ func main() {
 				logLevel = logrus.ErrorLevel
 			}
 			logger.SetLevel(logLevel)
+			if opts.Sandbox {
+				return commands.RunSandboxed(ctx, logger, cmd)
+			}
 			return ctx, nil
 		},
 		// We set the default action for the `nvidia-cdi-hook` command to issue a
@@ -90,12 +95,16 @@ func main() {
 				// TODO: Support for NVIDIA_CDI_QUIET is deprecated and NVIDIA_CTK_QUIET should be used instead.
 				Sources: cli.EnvVars("NVIDIA_CTK_QUIET", "NVIDIA_CDI_QUIET"),
 			},
+			commands.SandboxFlag(&opts.Sandbox),
 		},
 	}
 
+	diagnostics := commands.NewDiagnosticsRecorder(logger)
+
 	// Run the CLI
 	err := c.Run(context.Background(), os.Args)
 	if err != nil {
+		diagnostics.Record(os.Args, err)
 		logger.Errorf("%v", err)
 		os.Exit(1)
 	}
@@ -0,0 +1,243 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package resetgpus
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/urfave/cli/v3"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup"
+)
+
+const (
+	// ModeClean resets the clocks and accounting data of a GPU.
+	ModeClean = "clean"
+	// ModeReset performs a full reset of a GPU using nvidia-smi.
+	ModeReset = "reset"
+)
+
+type command struct {
+	logger logger.Interface
+}
+
+type config struct {
+	devices []string
+	mode    string
+}
+
+// NewCommand constructs a hook command with the specified logger
+func NewCommand(logger logger.Interface) *cli.Command {
+	c := command{
+		logger: logger,
+	}
+	return c.build()
+}
+
+// build creates the reset-gpus command.
+func (m command) build() *cli.Command {
+	cfg := config{}
+
+	c := cli.Command{
+		Name:  "reset-gpus",
+		Usage: "A hook to reset or clean the GPUs injected into a container before it is started. This must be run in the runtime namespace.",
+		Before: func(ctx context.Context, cmd *cli.Command) (context.Context, error) {
+			return ctx, validateFlags(&cfg)
+		},
+		Action: func(_ context.Context, cmd *cli.Command) error {
+			return m.run(nvml.New(), &cfg)
+		},
+		Flags: []cli.Flag{
+			&cli.StringSliceFlag{
+				Name:        "device",
+				Usage:       "Specify a GPU to reset by UUID or device minor number.",
+				Destination: &cfg.devices,
+			},
+			&cli.StringFlag{
+				Name:        "mode",
+				Usage:       "Specify how GPUs are reset. One of [clean, reset]. In clean mode the locked clocks and accounting data of the GPUs are reset. In reset mode the GPUs are reset using nvidia-smi --gpu-reset. GPUs that are in use or that have MIG enabled are skipped.",
+				Value:       ModeClean,
+				Destination: &cfg.mode,
+			},
+		},
+	}
+
+	return &c
+}
+
+func validateFlags(cfg *config) error {
+	switch cfg.mode {
+	case ModeClean, ModeReset:
+		return nil
+	}
+	return fmt.Errorf("invalid mode %q", cfg.mode)
+}
+
+func (m command) run(nvmllib nvml.Interface, cfg *config) error {
+	if len(cfg.devices) == 0 {
+		return nil
+	}
+
+	if ret := nvmllib.Init(); ret != nvml.SUCCESS {
+		return fmt.Errorf("failed to initialize NVML: %v", ret)
+	}
+	defer func() {
+		_ = nvmllib.Shutdown()
+	}()
+
+	for _, id := range cfg.devices {
+		device, err := getDevice(nvmllib, id)
+		if err != nil {
+			return err
+		}
+		if reason := skipReason(device); reason != "" {
+			m.logger.Warningf("Skipping %v of device %v: %v", cfg.mode, id, reason)
+			continue
+		}
+		switch cfg.mode {
+		case ModeClean:
+			err = m.clean(device)
+		case ModeReset:
+			err = m.reset(device)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to %v device %v: %w", cfg.mode, id, err)
+		}
+	}
+	return nil
+}
+
+// skipReason returns the reason why the specified device must not be reset
+// or cleaned, or an empty string if it can be. Since the settings of a device
+// apply to all its users, devices that are shared with other workloads (i.e.
+// that have running processes or MIG enabled) are skipped. Devices for which
+// this cannot be determined are also skipped.
+func skipReason(device nvml.Device) string {
+	currentMode, _, ret := device.GetMigMode()
+	switch {
+	case ret == nvml.ERROR_NOT_SUPPORTED:
+	case ret != nvml.SUCCESS:
+		return fmt.Sprintf("failed to get MIG mode: %v", ret)
+	case currentMode == nvml.DEVICE_MIG_ENABLE:
+		return "MIG is enabled"
+	}
+
+	for _, getProcesses := range []func() ([]nvml.ProcessInfo, nvml.Return){
+		device.GetComputeRunningProcesses,
+		device.GetGraphicsRunningProcesses,
+	} {
+		processes, ret := getProcesses()
+		if ret != nvml.SUCCESS {
+			return fmt.Sprintf("failed to get running processes: %v", ret)
+		}
+		if len(processes) > 0 {
+			return fmt.Sprintf("%d processes are running", len(processes))
+		}
+	}
+	return ""
+}
+
+// clean resets the settings of the specified device that a previous workload
+// may have changed. Operations that are not supported by the device are
+// skipped. The compute mode is not changed since this is typically set by
+// the administrator (e.g. for MPS).
+func (m command) clean(device nvml.Device) error {
+	operations := []struct {
+		description string
+		f           func() nvml.Return
+	}{
+		{"reset application clocks", device.ResetApplicationsClocks},
+		{"reset locked GPU clocks", device.ResetGpuLockedClocks},
+		{"reset locked memory clocks", device.ResetMemoryLockedClocks},
+		{"clear accounting data", device.ClearAccountingPids},
+	}
+	for _, operation := range operations {
+		ret := operation.f()
+		switch ret {
+		case nvml.SUCCESS:
+		case nvml.ERROR_NOT_SUPPORTED:
+			m.logger.Debugf("Skipping unsupported operation: %v", operation.description)
+		default:
+			return fmt.Errorf("failed to %v: %v", operation.description, ret)
+		}
+	}
+	return nil
+}
+
+// reset performs a full reset of the specified device using nvidia-smi. This
+// fails if there are processes using the device.
+func (m command) reset(device nvml.Device) error {
+	uuid, ret := device.GetUUID()
+	if ret != nvml.SUCCESS {
+		return fmt.Errorf("failed to get UUID: %v", ret)
+	}
+
+	nvidiaSMIPaths, err := lookup.NewExecutableLocator(m.logger, "/").Locate("nvidia-smi")
+	if err != nil {
+		return fmt.Errorf("failed to locate nvidia-smi: %w", err)
+	}
+
+	cmd := exec.Command(nvidiaSMIPaths[0], "--gpu-reset", "-i", uuid) //nolint:gosec // The UUID is returned by NVML.
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("nvidia-smi --gpu-reset failed: %w", err)
+	}
+	return nil
+}
+
+// getDevice returns the NVML device for the specified UUID or device minor
+// number.
+func getDevice(nvmllib nvml.Interface, id string) (nvml.Device, error) {
+	if strings.HasPrefix(id, "GPU-") {
+		device, ret := nvmllib.DeviceGetHandleByUUID(id)
+		if ret != nvml.SUCCESS {
+			return nil, fmt.Errorf("failed to get device %v: %v", id, ret)
+		}
+		return device, nil
+	}
+
+	minor, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid device %q", id)
+	}
+	count, ret := nvmllib.DeviceGetCount()
+	if ret != nvml.SUCCESS {
+		return nil, fmt.Errorf("failed to get device count: %v", ret)
+	}
+	for i := 0; i < count; i++ {
+		device, ret := nvmllib.DeviceGetHandleByIndex(i)
+		if ret != nvml.SUCCESS {
+			return nil, fmt.Errorf("failed to get device %d: %v", i, ret)
+		}
+		deviceMinor, ret := device.GetMinorNumber()
+		if ret != nvml.SUCCESS {
+			return nil, fmt.Errorf("failed to get minor number of device %d: %v", i, ret)
+		}
+		if deviceMinor == minor {
+			return device, nil
+		}
+	}
+	return nil, fmt.Errorf("no device with minor number %d found", minor)
+}
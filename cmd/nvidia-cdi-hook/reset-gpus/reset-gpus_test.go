@@ -0,0 +1,150 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package resetgpus
+
+import (
+	"testing"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/NVIDIA/go-nvml/pkg/nvml/mock"
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClean(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	testCases := []struct {
+		description     string
+		devices         []string
+		clearAccounting nvml.Return
+		migMode         int
+		processes       []nvml.ProcessInfo
+		expectedError   bool
+		expectedCleaned []int
+	}{
+		{
+			description:     "device by uuid",
+			devices:         []string{"GPU-1"},
+			clearAccounting: nvml.SUCCESS,
+			expectedCleaned: []int{1},
+		},
+		{
+			description:     "device by minor",
+			devices:         []string{"0"},
+			clearAccounting: nvml.SUCCESS,
+			expectedCleaned: []int{0},
+		},
+		{
+			description:     "unsupported operations are skipped",
+			devices:         []string{"0", "1"},
+			clearAccounting: nvml.ERROR_NOT_SUPPORTED,
+			expectedCleaned: []int{0, 1},
+		},
+		{
+			description:     "devices with MIG enabled are skipped",
+			devices:         []string{"0"},
+			clearAccounting: nvml.SUCCESS,
+			migMode:         nvml.DEVICE_MIG_ENABLE,
+		},
+		{
+			description:     "devices with running processes are skipped",
+			devices:         []string{"0"},
+			clearAccounting: nvml.SUCCESS,
+			processes:       []nvml.ProcessInfo{{Pid: 1234}},
+		},
+		{
+			description:     "failed operation returns error",
+			devices:         []string{"0"},
+			clearAccounting: nvml.ERROR_NO_PERMISSION,
+			expectedError:   true,
+		},
+		{
+			description:   "unknown minor returns error",
+			devices:       []string{"3"},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			var cleaned []int
+			newDevice := func(minor int) *mock.Device {
+				return &mock.Device{
+					GetMinorNumberFunc: func() (int, nvml.Return) {
+						return minor, nvml.SUCCESS
+					},
+					GetMigModeFunc: func() (int, int, nvml.Return) {
+						return tc.migMode, tc.migMode, nvml.SUCCESS
+					},
+					GetComputeRunningProcessesFunc: func() ([]nvml.ProcessInfo, nvml.Return) {
+						return tc.processes, nvml.SUCCESS
+					},
+					GetGraphicsRunningProcessesFunc: func() ([]nvml.ProcessInfo, nvml.Return) {
+						return nil, nvml.SUCCESS
+					},
+					ResetApplicationsClocksFunc: func() nvml.Return {
+						return nvml.ERROR_NOT_SUPPORTED
+					},
+					ResetGpuLockedClocksFunc: func() nvml.Return {
+						return nvml.SUCCESS
+					},
+					ResetMemoryLockedClocksFunc: func() nvml.Return {
+						return nvml.SUCCESS
+					},
+					ClearAccountingPidsFunc: func() nvml.Return {
+						if tc.clearAccounting == nvml.SUCCESS || tc.clearAccounting == nvml.ERROR_NOT_SUPPORTED {
+							cleaned = append(cleaned, minor)
+						}
+						return tc.clearAccounting
+					},
+				}
+			}
+			devices := []nvml.Device{newDevice(0), newDevice(1)}
+
+			nvmllib := &mock.Interface{
+				InitFunc: func() nvml.Return {
+					return nvml.SUCCESS
+				},
+				ShutdownFunc: func() nvml.Return {
+					return nvml.SUCCESS
+				},
+				DeviceGetCountFunc: func() (int, nvml.Return) {
+					return len(devices), nvml.SUCCESS
+				},
+				DeviceGetHandleByIndexFunc: func(n int) (nvml.Device, nvml.Return) {
+					return devices[n], nvml.SUCCESS
+				},
+				DeviceGetHandleByUUIDFunc: func(uuid string) (nvml.Device, nvml.Return) {
+					if uuid == "GPU-1" {
+						return devices[1], nvml.SUCCESS
+					}
+					return nil, nvml.ERROR_NOT_FOUND
+				},
+			}
+
+			c := command{logger: logger}
+			err := c.run(nvmllib, &config{devices: tc.devices, mode: ModeClean})
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.EqualValues(t, tc.expectedCleaned, cleaned)
+		})
+	}
+}
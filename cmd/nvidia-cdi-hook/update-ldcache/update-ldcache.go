@@ -28,11 +28,12 @@ import (
 
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/ldconfig"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
-	"github.com/NVIDIA/nvidia-container-toolkit/internal/oci"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/oci"
 )
 
 const (
-	reexecUpdateLdCacheCommandName = "reexec-update-ldcache"
+	reexecUpdateLdCacheCommandName  = "reexec-update-ldcache"
+	reexecUpdateLdSoConfCommandName = "reexec-update-ldsoconf"
 )
 
 type command struct {
@@ -41,12 +42,16 @@ type command struct {
 
 type options struct {
 	folders       []string
+	foldersFile   string
 	ldconfigPath  string
 	containerSpec string
+
+	skipLDCacheCreation bool
 }
 
 func init() {
 	reexec.Register(reexecUpdateLdCacheCommandName, updateLdCacheHandler)
+	reexec.Register(reexecUpdateLdSoConfCommandName, updateLdSoConfHandler)
 	if reexec.Init() {
 		os.Exit(0)
 	}
@@ -80,6 +85,12 @@ func (m command) build() *cli.Command {
 				Usage:       "Specify a folder to add to /etc/ld.so.conf before updating the ld cache",
 				Destination: &cfg.folders,
 			},
+			&cli.StringFlag{
+				Name: "folders-file",
+				Usage: "Specify a file containing the folders to add to /etc/ld.so.conf with one folder per line. " +
+					"This allows a large number of folders to be specified without exceeding argument limits.",
+				Destination: &cfg.foldersFile,
+			},
 			&cli.StringFlag{
 				Name:        "ldconfig-path",
 				Usage:       "Specify the path to the ldconfig program",
@@ -91,6 +102,12 @@ func (m command) build() *cli.Command {
 				Usage:       "Specify the path to the OCI container spec. If empty or '-' the spec will be read from STDIN",
 				Destination: &cfg.containerSpec,
 			},
+			&cli.BoolFlag{
+				Name: "skip-ldcache-creation",
+				Usage: "Only update the ld.so.conf.d config in the container and refresh the ldcache if it already exists. " +
+					"This reduces container startup time for images without an ldcache.",
+				Destination: &cfg.skipLDCacheCreation,
+			},
 		},
 	}
 
@@ -101,6 +118,9 @@ func (m command) validateFlags(_ *cli.Command, cfg *options) error {
 	if cfg.ldconfigPath == "" {
 		return errors.New("ldconfig-path must be specified")
 	}
+	if cfg.foldersFile == "-" {
+		return errors.New("folders-file cannot be read from STDIN since this is used for the container state")
+	}
 	return nil
 }
 
@@ -110,16 +130,26 @@ func (m command) run(_ *cli.Command, cfg *options) error {
 		return fmt.Errorf("failed to load container state: %v", err)
 	}
 
+	folders, err := cfg.getFolders()
+	if err != nil {
+		return err
+	}
+
 	containerRootDir, err := s.GetContainerRoot()
 	if err != nil || containerRootDir == "" || containerRootDir == "/" {
 		return fmt.Errorf("failed to determined container root: %v", err)
 	}
 
+	reexecCommandName := reexecUpdateLdCacheCommandName
+	if cfg.skipLDCacheCreation {
+		reexecCommandName = reexecUpdateLdSoConfCommandName
+	}
+
 	runner, err := ldconfig.NewRunner(
-		reexecUpdateLdCacheCommandName,
+		reexecCommandName,
 		cfg.ldconfigPath,
 		containerRootDir,
-		cfg.folders...,
+		folders...,
 	)
 	if err != nil {
 		return err
@@ -127,6 +157,21 @@ func (m command) run(_ *cli.Command, cfg *options) error {
 	return runner.Run()
 }
 
+// getFolders returns the folders specified on the command line and in the
+// folders file. Empty lines and lines starting with # in the folders file are
+// ignored and duplicate folders are removed.
+func (cfg *options) getFolders() ([]string, error) {
+	folders := cfg.folders
+	if cfg.foldersFile != "" {
+		fromFile, err := ldconfig.ReadFoldersFile(cfg.foldersFile)
+		if err != nil {
+			return nil, err
+		}
+		folders = append(folders, fromFile...)
+	}
+	return ldconfig.UniqueFolders(folders), nil
+}
+
 // updateLdCacheHandler wraps updateLdCache with error handling.
 func updateLdCacheHandler() {
 	if err := updateLdCache(os.Args); err != nil {
@@ -135,6 +180,14 @@ func updateLdCacheHandler() {
 	}
 }
 
+// updateLdSoConfHandler wraps updateLdSoConf with error handling.
+func updateLdSoConfHandler() {
+	if err := updateLdSoConf(os.Args); err != nil {
+		log.Printf("Error updating ld.so.conf: %v", err)
+		os.Exit(1)
+	}
+}
+
 // updateLdCache ensures that the ldcache in the container is updated to include
 // libraries that are mounted from the host.
 // It is invoked from a reexec'd handler and provides namespace isolation for
@@ -162,3 +215,26 @@ func updateLdCache(args []string) error {
 
 	return ldconfig.UpdateLDCache(args[3:]...)
 }
+
+// updateLdSoConf ensures that the ld.so.conf.d config in the container
+// includes the folders containing libraries that are mounted from the host.
+// The ldcache is only updated if it already exists in the container.
+// It is invoked from a reexec'd handler and the arguments are the same as for
+// updateLdCache.
+func updateLdSoConf(args []string) error {
+	if len(args) < 3 {
+		return fmt.Errorf("incorrect arguments: %v", args)
+	}
+	hostLdconfigPath := args[1]
+	containerRootDirPath := args[2]
+
+	ldconfig, err := ldconfig.New(
+		hostLdconfigPath,
+		containerRootDirPath,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to construct ldconfig runner: %w", err)
+	}
+
+	return ldconfig.UpdateLDSoConf(args[3:]...)
+}
@@ -0,0 +1,68 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package ldcache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetFolders(t *testing.T) {
+	foldersFile := filepath.Join(t.TempDir(), "folders")
+	require.NoError(t, os.WriteFile(foldersFile, []byte("# library folders\n/usr/lib64\n\n  /usr/lib/aarch64-linux-gnu/tegra/  \n/usr/lib/aarch64-linux-gnu\n"), 0600))
+
+	testCases := []struct {
+		description     string
+		options         options
+		expectedFolders []string
+		expectedError   bool
+	}{
+		{
+			description:     "folders from flags",
+			options:         options{folders: []string{"/usr/lib64", "/usr/lib"}},
+			expectedFolders: []string{"/usr/lib64", "/usr/lib"},
+		},
+		{
+			description: "folders from file are appended and deduplicated",
+			options: options{
+				folders:     []string{"/usr/lib64", "/usr/lib/aarch64-linux-gnu/tegra"},
+				foldersFile: foldersFile,
+			},
+			expectedFolders: []string{"/usr/lib64", "/usr/lib/aarch64-linux-gnu/tegra", "/usr/lib/aarch64-linux-gnu"},
+		},
+		{
+			description:   "missing file raises error",
+			options:       options{foldersFile: filepath.Join(t.TempDir(), "missing")},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			folders, err := tc.options.getFolders()
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.EqualValues(t, tc.expectedFolders, folders)
+		})
+	}
+}
@@ -20,6 +20,10 @@ func capabilityToCLI(cap string) string {
 		return "--display"
 	case "ngx":
 		return "--ngx"
+	case "profiling":
+		// The profiling capability is handled by the NVIDIA Container Runtime
+		// and has no equivalent nvidia-container-cli flag.
+		return ""
 	default:
 		log.Panicln("unknown driver capability:", cap)
 	}
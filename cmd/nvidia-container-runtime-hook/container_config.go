@@ -11,6 +11,7 @@ import (
 	"golang.org/x/mod/semver"
 
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/config/image"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/info/proc"
 )
 
 type nvidiaConfig struct {
@@ -59,10 +60,11 @@ type LinuxCapabilities struct {
 // We use pointers to structs, similarly to the latest version of runtime-spec:
 // https://github.com/opencontainers/runtime-spec/blob/v1.0.0/specs-go/config.go#L5-L28
 type Spec struct {
-	Version *string       `json:"ociVersion"`
-	Process *Process      `json:"process,omitempty"`
-	Root    *Root         `json:"root,omitempty"`
-	Mounts  []specs.Mount `json:"mounts,omitempty"`
+	Version     *string           `json:"ociVersion"`
+	Process     *Process          `json:"process,omitempty"`
+	Root        *Root             `json:"root,omitempty"`
+	Mounts      []specs.Mount     `json:"mounts,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
 }
 
 // HookState holds state information about the hook
@@ -206,6 +208,7 @@ func (hookConfig *hookConfig) getNvidiaConfig(image image.CUDA, privileged bool)
 		// empty devices means this is not a GPU container.
 		return nil
 	}
+	devices = resolvePCIBusIDs(devices)
 
 	var migConfigDevices string
 	if d := getMigConfigDevices(image); d != nil {
@@ -231,6 +234,9 @@ func (hookConfig *hookConfig) getNvidiaConfig(image image.CUDA, privileged bool)
 	if err != nil {
 		log.Panicln("failed to get requirements", err)
 	}
+	if err := image.CheckRequirementConflicts(); err != nil {
+		log.Panicln(err)
+	}
 
 	return &nvidiaConfig{
 		Devices:            devices,
@@ -242,6 +248,25 @@ func (hookConfig *hookConfig) getNvidiaConfig(image image.CUDA, privileged bool)
 	}
 }
 
+// resolvePCIBusIDs replaces the devices that are requested by PCI bus ID with
+// the UUIDs of the corresponding GPUs. The UUIDs are passed to the
+// nvidia-container-cli instead.
+func resolvePCIBusIDs(devices []string) []string {
+	var resolved []string
+	for _, d := range devices {
+		if !proc.IsPCIBusID(d) {
+			resolved = append(resolved, d)
+			continue
+		}
+		gpu, err := proc.GetGPUByPCIBusID("/", d)
+		if err != nil {
+			log.Panicln(fmt.Errorf("failed to resolve device %v: %w", d, err))
+		}
+		resolved = append(resolved, gpu.UUID)
+	}
+	return resolved
+}
+
 func (hookConfig *hookConfig) getContainerConfig() (config *containerConfig) {
 	hookConfig.Lock()
 	defer hookConfig.Unlock()
@@ -266,12 +291,14 @@ func (hookConfig *hookConfig) getContainerConfig() (config *containerConfig) {
 	privileged := isPrivileged(s)
 
 	i, err := image.New(
+		image.WithImageConfigEnvFromAnnotations(s.Annotations),
 		image.WithEnv(s.Process.Env),
 		image.WithMounts(s.Mounts),
 		image.WithPrivileged(privileged),
 		image.WithDisableRequire(hookConfig.DisableRequire),
 		image.WithAcceptDeviceListAsVolumeMounts(hookConfig.AcceptDeviceListAsVolumeMounts),
 		image.WithAcceptEnvvarUnprivileged(hookConfig.AcceptEnvvarUnprivileged),
+		image.WithVisibleDevicesAll(hookConfig.VisibleDevicesAll),
 		image.WithPreferredVisibleDevicesEnvVars(hookConfig.getSwarmResourceEnvvars()...),
 	)
 	if err != nil {
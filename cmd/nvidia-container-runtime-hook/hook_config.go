@@ -133,6 +133,7 @@ func (c *hookConfig) assertModeIsLegacy() error {
 		info.WithLogger(&logInterceptor{}),
 		info.WithImage(&c.containerConfig.Image),
 		info.WithDefaultMode(info.LegacyRuntimeMode),
+		info.WithPlatform(info.Platform(c.NVIDIAContainerRuntimeConfig.Platform)),
 	)
 
 	mode := mr.ResolveRuntimeMode(c.NVIDIAContainerRuntimeConfig.Mode)
@@ -17,6 +17,7 @@ import (
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/info"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/retry"
 )
 
 var (
@@ -63,6 +64,41 @@ func getRootfsPath(config *containerConfig) string {
 	return rootfs
 }
 
+// waitForDriver waits for the /dev/nvidiactl device node to be created if
+// discovery retries are configured. This allows containers that are started
+// while the driver is still being loaded (e.g. on a freshly started node) to
+// be created. If the device node does not exist after the configured number
+// of attempts, nvidia-container-cli is invoked regardless so that it can
+// report an actionable error.
+func waitForDriver(cfg *config.Config) {
+	retries := cfg.NVIDIAContainerRuntimeConfig.DiscoveryRetries
+	if retries.Attempts < 2 {
+		return
+	}
+	driverRoot := cfg.NVIDIAContainerCLIConfig.Root
+	if driverRoot == "" {
+		driverRoot = "/"
+	}
+	logger := &logInterceptor{}
+	if !info.UsesNVIDIAKernelModules(logger, driverRoot) {
+		return
+	}
+
+	nvidiactlPath := filepath.Join(driverRoot, "dev/nvidiactl")
+	retrier := retry.New(
+		retry.WithLogger(logger),
+		retry.WithAttempts(retries.Attempts),
+		retry.WithBackoff(retries.GetBackoff()),
+	)
+	err := retrier.Do("find "+nvidiactlPath, func() error {
+		_, err := os.Stat(nvidiactlPath)
+		return err
+	})
+	if err != nil {
+		log.Printf("%v", err)
+	}
+}
+
 func doPrestart() {
 	var err error
 
@@ -86,6 +122,8 @@ func doPrestart() {
 		log.Panicf("%v", err)
 	}
 
+	waitForDriver(hook.Config)
+
 	rootfs := getRootfsPath(container)
 
 	args := []string{getCLIPath(cli)}
@@ -139,7 +177,9 @@ func doPrestart() {
 		if len(cap) == 0 {
 			break
 		}
-		args = append(args, capabilityToCLI(cap))
+		if arg := capabilityToCLI(cap); arg != "" {
+			args = append(args, arg)
+		}
 	}
 
 	for _, req := range nvidia.Requirements {
@@ -201,3 +241,7 @@ type logInterceptor struct {
 func (l *logInterceptor) Infof(format string, args ...interface{}) {
 	log.Printf(format, args...)
 }
+
+func (l *logInterceptor) Warningf(format string, args ...interface{}) {
+	log.Printf(format, args...)
+}
@@ -167,6 +167,7 @@ func (a *app) Before(c *cli.Command, o *options) error {
 		toolkit.WithLogger(a.logger),
 		toolkit.WithSourceRoot(o.sourceRoot),
 		toolkit.WithToolkitRoot(o.toolkitRoot()),
+		toolkit.WithHostRoot(o.runtimeOptions.HostRootMount),
 	)
 	return a.validateFlags(c, o)
 }
@@ -50,7 +50,7 @@ func TestApp(t *testing.T) {
 			expectedToolkitConfig: `accept-nvidia-visible-devices-as-volume-mounts = false
 accept-nvidia-visible-devices-envvar-when-unprivileged = true
 disable-require = false
-supported-driver-capabilities = "compat32,compute,display,graphics,ngx,utility,video"
+supported-driver-capabilities = "compat32,compute,display,graphics,ngx,profiling,utility,video"
 swarm-resource = ""
 
 [nvidia-container-cli]
@@ -114,7 +114,7 @@ swarm-resource = ""
 			expectedToolkitConfig: `accept-nvidia-visible-devices-as-volume-mounts = false
 accept-nvidia-visible-devices-envvar-when-unprivileged = true
 disable-require = false
-supported-driver-capabilities = "compat32,compute,display,graphics,ngx,utility,video"
+supported-driver-capabilities = "compat32,compute,display,graphics,ngx,profiling,utility,video"
 swarm-resource = ""
 
 [nvidia-container-cli]
@@ -181,7 +181,7 @@ swarm-resource = ""
 			expectedToolkitConfig: `accept-nvidia-visible-devices-as-volume-mounts = false
 accept-nvidia-visible-devices-envvar-when-unprivileged = true
 disable-require = false
-supported-driver-capabilities = "compat32,compute,display,graphics,ngx,utility,video"
+supported-driver-capabilities = "compat32,compute,display,graphics,ngx,profiling,utility,video"
 swarm-resource = ""
 
 [nvidia-container-cli]
@@ -245,7 +245,7 @@ swarm-resource = ""
 			expectedToolkitConfig: `accept-nvidia-visible-devices-as-volume-mounts = false
 accept-nvidia-visible-devices-envvar-when-unprivileged = true
 disable-require = false
-supported-driver-capabilities = "compat32,compute,display,graphics,ngx,utility,video"
+supported-driver-capabilities = "compat32,compute,display,graphics,ngx,profiling,utility,video"
 swarm-resource = ""
 
 [nvidia-container-cli]
@@ -331,7 +331,7 @@ swarm-resource = ""
 			expectedToolkitConfig: `accept-nvidia-visible-devices-as-volume-mounts = false
 accept-nvidia-visible-devices-envvar-when-unprivileged = true
 disable-require = false
-supported-driver-capabilities = "compat32,compute,display,graphics,ngx,utility,video"
+supported-driver-capabilities = "compat32,compute,display,graphics,ngx,profiling,utility,video"
 swarm-resource = ""
 
 [nvidia-container-cli]
@@ -38,3 +38,11 @@ func WithSourceRoot(sourceRoot string) Option {
 		i.sourceRoot = sourceRoot
 	}
 }
+
+// WithHostRoot sets the path at which the host root is mounted. This is used
+// to create files that are expected at well-known locations on the host.
+func WithHostRoot(hostRoot string) Option {
+	return func(i *Installer) {
+		i.hostRoot = hostRoot
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/urfave/cli/v3"
@@ -28,6 +29,7 @@ import (
 
 	"github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk-installer/toolkit/installer"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/discover"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/system/nvdevices"
 	"github.com/NVIDIA/nvidia-container-toolkit/pkg/nvcdi"
@@ -218,6 +220,8 @@ type Installer struct {
 	sourceRoot string
 	// toolkitRoot specifies the destination path at which the toolkit is installed.
 	toolkitRoot string
+	// hostRoot specifies the path at which the host root is mounted.
+	hostRoot string
 }
 
 // NewInstaller creates an installer for the NVIDIA Container Toolkit.
@@ -329,6 +333,13 @@ func (t *Installer) Install(cli *cli.Command, opts *Options) error {
 	}
 
 	nvidiaCDIHookPath := filepath.Join(t.toolkitRoot, "nvidia-cdi-hook")
+	err = t.createStableHookSymlink(opts, nvidiaCDIHookPath)
+	if err != nil && !opts.ignoreErrors {
+		return fmt.Errorf("error creating nvidia-cdi-hook symlink: %v", err)
+	} else if err != nil {
+		t.logger.Errorf("Ignoring error: %v", fmt.Errorf("error creating nvidia-cdi-hook symlink: %v", err))
+	}
+
 	err = t.generateCDISpec(opts, nvidiaCDIHookPath)
 	if err != nil && !opts.ignoreErrors {
 		return fmt.Errorf("error generating CDI specification: %v", err)
@@ -487,6 +498,33 @@ func (t *Installer) createDeviceNodes(opts *Options) error {
 	return nil
 }
 
+// createStableHookSymlink creates the symlink to the installed nvidia-cdi-hook
+// at the well-known path on the host that is referenced by the generated hooks
+// if the stable-hook-paths feature is enabled. An existing symlink is
+// replaced, but other existing files are left as is.
+func (t *Installer) createStableHookSymlink(opts *Options, nvidiaCDIHookPath string) error {
+	if !slices.Contains(opts.optInFeatures, "stable-hook-paths") {
+		return nil
+	}
+
+	link := filepath.Join(t.hostRoot, discover.StableNvidiaCDIHookPath)
+	if info, err := os.Lstat(link); err == nil {
+		if info.Mode()&os.ModeSymlink == 0 {
+			t.logger.Warningf("Skipping creation of %v symlink: file exists", discover.StableNvidiaCDIHookPath)
+			return nil
+		}
+		if err := os.Remove(link); err != nil {
+			return fmt.Errorf("failed to remove existing symlink: %w", err)
+		}
+	}
+
+	t.logger.Infof("Creating symlink %v to %v", discover.StableNvidiaCDIHookPath, nvidiaCDIHookPath)
+	if err := os.MkdirAll(filepath.Dir(link), 0755); err != nil {
+		return fmt.Errorf("error creating directory: %w", err)
+	}
+	return os.Symlink(nvidiaCDIHookPath, link)
+}
+
 // generateCDISpec generates a CDI spec for use in management containers
 func (t *Installer) generateCDISpec(opts *Options, nvidiaCDIHookPath string) error {
 	if !opts.CDI.Enabled {
@@ -210,6 +210,76 @@ containerEdits:
 	}
 }
 
+func TestCreateStableHookSymlink(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	const nvidiaCDIHookPath = "/usr/local/nvidia/toolkit/nvidia-cdi-hook"
+
+	testCases := []struct {
+		description    string
+		optInFeatures  []string
+		existingTarget string
+		existingFile   bool
+		expectedTarget string
+	}{
+		{
+			description: "feature not enabled",
+		},
+		{
+			description:    "symlink is created",
+			optInFeatures:  []string{"stable-hook-paths"},
+			expectedTarget: nvidiaCDIHookPath,
+		},
+		{
+			description:    "existing symlink is replaced",
+			optInFeatures:  []string{"stable-hook-paths"},
+			existingTarget: "/usr/bin/nvidia-cdi-hook",
+			expectedTarget: nvidiaCDIHookPath,
+		},
+		{
+			description:   "existing file is not replaced",
+			optInFeatures: []string{"stable-hook-paths"},
+			existingFile:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			hostRoot := t.TempDir()
+			link := filepath.Join(hostRoot, "usr/libexec/nvidia-container-toolkit/nvidia-cdi-hook")
+			if tc.existingTarget != "" || tc.existingFile {
+				require.NoError(t, os.MkdirAll(filepath.Dir(link), 0755))
+			}
+			if tc.existingTarget != "" {
+				require.NoError(t, os.Symlink(tc.existingTarget, link))
+			}
+			if tc.existingFile {
+				require.NoError(t, os.WriteFile(link, nil, 0755))
+			}
+
+			ti := NewInstaller(
+				WithLogger(logger),
+				WithHostRoot(hostRoot),
+			)
+			err := ti.createStableHookSymlink(&Options{optInFeatures: tc.optInFeatures}, nvidiaCDIHookPath)
+			require.NoError(t, err)
+
+			switch {
+			case tc.expectedTarget != "":
+				target, err := os.Readlink(link)
+				require.NoError(t, err)
+				require.Equal(t, tc.expectedTarget, target)
+			case tc.existingFile:
+				info, err := os.Lstat(link)
+				require.NoError(t, err)
+				require.True(t, info.Mode().IsRegular())
+			default:
+				require.NoFileExists(t, link)
+			}
+		})
+	}
+}
+
 func requireWrappedExecutable(t *testing.T, toolkitRoot string, expectedExecutable string) {
 	requireExecutable(t, toolkitRoot, expectedExecutable)
 	requireExecutable(t, toolkitRoot, expectedExecutable+".real")
@@ -0,0 +1,285 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package cleanup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+	"tags.cncf.io/container-device-interface/pkg/cdi"
+	"tags.cncf.io/container-device-interface/pkg/parser"
+	"tags.cncf.io/container-device-interface/specs-go"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup/root"
+)
+
+type command struct {
+	logger logger.Interface
+}
+
+type config struct {
+	cdiSpecDirs []string
+	hostRoot    string
+	vendor      string
+	dryRun      bool
+}
+
+// NewCommand constructs a cdi cleanup command with the specified logger
+func NewCommand(logger logger.Interface) *cli.Command {
+	c := command{
+		logger: logger,
+	}
+	return c.build()
+}
+
+// build creates the CLI command
+func (m command) build() *cli.Command {
+	cfg := config{}
+
+	// Create the command
+	c := cli.Command{
+		Name:  "cleanup",
+		Usage: "Remove CDI specifications that reference devices or driver files that no longer exist on the host",
+		Before: func(ctx context.Context, cmd *cli.Command) (context.Context, error) {
+			return ctx, m.validateFlags(&cfg)
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			return m.run(&cfg)
+		},
+		Flags: []cli.Flag{
+			&cli.StringSliceFlag{
+				Name:        "spec-dir",
+				Usage:       "specify the directories to scan for CDI specifications",
+				Value:       cdi.DefaultSpecDirs,
+				Destination: &cfg.cdiSpecDirs,
+				Sources:     cli.EnvVars("NVIDIA_CTK_CDI_SPEC_DIRS"),
+			},
+			&cli.StringFlag{
+				Name:        "host-root",
+				Usage:       "specify the root at which the paths referenced in the CDI specifications are checked",
+				Value:       "/",
+				Destination: &cfg.hostRoot,
+				Sources:     cli.EnvVars("NVIDIA_CTK_CDI_CLEANUP_HOST_ROOT"),
+			},
+			&cli.StringFlag{
+				Name:        "vendor",
+				Aliases:     []string{"cdi-vendor"},
+				Usage:       "only consider CDI specifications for the specified vendor",
+				Value:       "nvidia.com",
+				Destination: &cfg.vendor,
+				Sources:     cli.EnvVars("NVIDIA_CTK_CDI_CLEANUP_VENDOR"),
+			},
+			&cli.BoolFlag{
+				Name:        "dry-run",
+				Usage:       "report the stale CDI specifications without removing them",
+				Destination: &cfg.dryRun,
+			},
+		},
+	}
+
+	return &c
+}
+
+func (m command) validateFlags(cfg *config) error {
+	if len(cfg.cdiSpecDirs) == 0 {
+		return errors.New("at least one CDI specification directory must be specified")
+	}
+	if err := parser.ValidateVendorName(cfg.vendor); err != nil {
+		return fmt.Errorf("invalid CDI vendor name: %v", err)
+	}
+	return nil
+}
+
+func (m command) run(cfg *config) error {
+	specFiles, err := getSpecFiles(cfg.cdiSpecDirs)
+	if err != nil {
+		return err
+	}
+
+	driverVersion := m.getDriverVersion(cfg.hostRoot)
+
+	var removed int
+	for _, specFile := range specFiles {
+		spec, err := cdi.ReadSpec(specFile, 0)
+		if err != nil {
+			m.logger.Warningf("Skipping invalid CDI specification %v: %v", specFile, err)
+			continue
+		}
+		if spec.GetVendor() != cfg.vendor {
+			m.logger.Debugf("Skipping CDI specification %v for vendor %v", specFile, spec.GetVendor())
+			continue
+		}
+
+		if !m.isStale(specFile, spec.Spec, cfg.hostRoot, driverVersion) {
+			m.logger.Debugf("CDI specification %v is up to date", specFile)
+			continue
+		}
+
+		if cfg.dryRun {
+			m.logger.Infof("Would remove %v", specFile)
+			continue
+		}
+		if err := os.Remove(specFile); err != nil {
+			return fmt.Errorf("failed to remove CDI specification %v: %w", specFile, err)
+		}
+		m.logger.Infof("Removed %v", specFile)
+		removed++
+	}
+
+	m.logger.Infof("Removed %d stale CDI specifications", removed)
+	return nil
+}
+
+// getDriverVersion returns the version of the driver installed at the
+// specified host root. If the version cannot be determined, an empty string is
+// returned and the driver versions of the specifications are not checked.
+func (m command) getDriverVersion(hostRoot string) string {
+	driver := root.New(
+		root.WithLogger(m.logger),
+		root.WithDriverRoot(hostRoot),
+	)
+	version, err := driver.Version()
+	if err != nil {
+		m.logger.Debugf("Failed to determine the installed driver version: %v", err)
+		return ""
+	}
+	return version
+}
+
+// isStale checks whether the specified CDI specification was generated for a
+// driver version other than the installed version or references device nodes
+// or driver files that do not exist at the host root.
+func (m command) isStale(specFile string, spec *specs.Spec, hostRoot string, driverVersion string) bool {
+	if specVersion := getSpecDriverVersion(spec); specVersion != "" && driverVersion != "" && specVersion != driverVersion {
+		m.logger.Infof("CDI specification %v was generated for driver version %v but %v is installed", specFile, specVersion, driverVersion)
+		return true
+	}
+
+	missing := getMissingPaths(hostRoot, spec)
+	if len(missing) > 0 {
+		m.logger.Infof("CDI specification %v references paths that do not exist: %v", specFile, strings.Join(missing, ", "))
+		return true
+	}
+	return false
+}
+
+// getSpecDriverVersion returns the driver version that the specified CDI
+// specification was generated for. This is taken from the
+// --host-driver-version argument of the CUDA compat hook or, if there is no
+// such hook, from the version suffix of the libcuda.so library that is
+// mounted. An empty string is returned if the version cannot be determined.
+func getSpecDriverVersion(spec *specs.Spec) string {
+	var edits []specs.ContainerEdits
+	edits = append(edits, spec.ContainerEdits)
+	for _, device := range spec.Devices {
+		edits = append(edits, device.ContainerEdits)
+	}
+
+	var libcudaVersion string
+	for _, e := range edits {
+		for _, hook := range e.Hooks {
+			for _, arg := range hook.Args {
+				if version, ok := strings.CutPrefix(arg, "--host-driver-version="); ok && version != "" {
+					return version
+				}
+			}
+		}
+		for _, mount := range e.Mounts {
+			if version, ok := strings.CutPrefix(filepath.Base(mount.HostPath), "libcuda.so."); ok && strings.Contains(version, ".") {
+				libcudaVersion = version
+			}
+		}
+	}
+	return libcudaVersion
+}
+
+// getSpecFiles returns the CDI specification files in the specified
+// directories. Directories that do not exist are ignored.
+func getSpecFiles(specDirs []string) ([]string, error) {
+	var specFiles []string
+	for _, dir := range specDirs {
+		for _, ext := range []string{".json", ".yaml", ".yml"} {
+			matches, err := filepath.Glob(filepath.Join(dir, "*"+ext))
+			if err != nil {
+				return nil, fmt.Errorf("failed to list CDI specifications in %v: %w", dir, err)
+			}
+			specFiles = append(specFiles, matches...)
+		}
+	}
+	return specFiles, nil
+}
+
+// getMissingPaths returns the device nodes and mounts referenced by the
+// specified CDI specification that do not exist at the host root. Since
+// driver libraries are mounted using their fully-versioned names, a driver
+// upgrade or downgrade results in the mounts of the previous version being
+// reported as missing. IPC sockets are ignored since these only exist while
+// the associated daemon (e.g. nvidia-persistenced) is running.
+func getMissingPaths(hostRoot string, spec *specs.Spec) []string {
+	var edits []specs.ContainerEdits
+	edits = append(edits, spec.ContainerEdits)
+	for _, device := range spec.Devices {
+		edits = append(edits, device.ContainerEdits)
+	}
+
+	var missing []string
+	seen := make(map[string]bool)
+	for _, e := range edits {
+		var paths []string
+		for _, dn := range e.DeviceNodes {
+			if dn.HostPath != "" {
+				paths = append(paths, dn.HostPath)
+			} else {
+				paths = append(paths, dn.Path)
+			}
+		}
+		for _, mount := range e.Mounts {
+			if !filepath.IsAbs(mount.HostPath) || isIPCPath(mount.HostPath) {
+				continue
+			}
+			paths = append(paths, mount.HostPath)
+		}
+
+		for _, path := range paths {
+			if seen[path] {
+				continue
+			}
+			seen[path] = true
+			if _, err := os.Lstat(filepath.Join(hostRoot, path)); err != nil {
+				missing = append(missing, path)
+			}
+		}
+	}
+	return missing
+}
+
+// isIPCPath checks whether the specified path refers to one of the IPC sockets
+// or directories of the NVIDIA daemons.
+func isIPCPath(path string) bool {
+	for _, suffix := range []string{"/nvidia-persistenced/socket", "/nvidia-fabricmanager/socket", "/nvidia-mps"} {
+		if strings.HasSuffix(path, suffix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package cleanup
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+const specTemplate = `---
+cdiVersion: 0.5.0
+kind: %s
+devices:
+- name: gpu0
+  containerEdits:
+    deviceNodes:
+    - path: /dev/nvidia0
+containerEdits:
+  mounts:
+  - hostPath: /usr/lib64/libcuda.so.%s
+    containerPath: /usr/lib64/libcuda.so.%s
+    options:
+    - ro
+  - hostPath: tmpfs
+    containerPath: /tmp
+    type: tmpfs
+%s`
+
+func TestCleanup(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	hostRoot := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(hostRoot, "dev"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(hostRoot, "dev/nvidia0"), nil, 0600))
+	require.NoError(t, os.MkdirAll(filepath.Join(hostRoot, "usr/lib64"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(hostRoot, "usr/lib64/libcuda.so.550.54.15"), nil, 0600))
+
+	testCases := []struct {
+		description     string
+		kind            string
+		driverVersion   string
+		extraEdits      string
+		dryRun          bool
+		expectedRemoved bool
+	}{
+		{
+			description:   "up-to-date spec is kept",
+			kind:          "nvidia.com/gpu",
+			driverVersion: "550.54.15",
+		},
+		{
+			description:     "spec for previous driver is removed",
+			kind:            "nvidia.com/gpu",
+			driverVersion:   "535.129.03",
+			expectedRemoved: true,
+		},
+		{
+			description:   "stale spec is kept for dry run",
+			kind:          "nvidia.com/gpu",
+			driverVersion: "535.129.03",
+			dryRun:        true,
+		},
+		{
+			description:   "missing socket is ignored",
+			kind:          "nvidia.com/gpu",
+			driverVersion: "550.54.15",
+			extraEdits: `  - hostPath: /run/nvidia-persistenced/socket
+    containerPath: /run/nvidia-persistenced/socket
+`,
+		},
+		{
+			description:   "spec for other driver version is removed",
+			kind:          "nvidia.com/gpu",
+			driverVersion: "550.54.15",
+			extraEdits: `  hooks:
+  - hookName: createContainer
+    path: /usr/bin/nvidia-cdi-hook
+    args:
+    - nvidia-cdi-hook
+    - enable-cuda-compat
+    - --host-driver-version=535.129.03
+`,
+			expectedRemoved: true,
+		},
+		{
+			description:   "spec for other vendor is ignored",
+			kind:          "example.com/device",
+			driverVersion: "535.129.03",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			specDir := t.TempDir()
+			specFile := filepath.Join(specDir, "spec.yaml")
+			contents := []byte(fmt.Sprintf(specTemplate, tc.kind, tc.driverVersion, tc.driverVersion, tc.extraEdits))
+			require.NoError(t, os.WriteFile(specFile, contents, 0600))
+
+			c := command{
+				logger: logger,
+			}
+			cfg := config{
+				cdiSpecDirs: []string{specDir, filepath.Join(specDir, "missing")},
+				hostRoot:    hostRoot,
+				vendor:      "nvidia.com",
+				dryRun:      tc.dryRun,
+			}
+			require.NoError(t, c.validateFlags(&cfg))
+			require.NoError(t, c.run(&cfg))
+
+			_, err := os.Stat(specFile)
+			if tc.expectedRemoved {
+				require.ErrorIs(t, err, os.ErrNotExist)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package convertcsv
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/platform-support/tegra/csv"
+)
+
+type command struct {
+	logger logger.Interface
+}
+
+type options struct {
+	input  string
+	output string
+}
+
+// NewCommand constructs a cdi convert-csv command with the specified logger
+func NewCommand(logger logger.Interface) *cli.Command {
+	c := command{
+		logger: logger,
+	}
+	return c.build()
+}
+
+// build creates the CLI command
+func (m command) build() *cli.Command {
+	opts := options{}
+
+	c := cli.Command{
+		Name:  "convert-csv",
+		Usage: "Convert a CSV mount spec file used on Tegra-based systems to the equivalent YAML mount spec file",
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			return m.run(&opts)
+		},
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "input",
+				Usage:       "Specify the CSV file to convert. If this is '-' the file is read from STDIN",
+				Value:       "-",
+				Destination: &opts.input,
+			},
+			&cli.StringFlag{
+				Name:        "output",
+				Usage:       "Specify the file to output the YAML mount spec file to. If this is '' the file is output to STDOUT",
+				Destination: &opts.output,
+			},
+		},
+	}
+
+	return &c
+}
+
+func (m command) run(opts *options) error {
+	input := os.Stdin
+	if opts.input != "-" {
+		f, err := os.Open(opts.input)
+		if err != nil {
+			return fmt.Errorf("failed to open input file: %w", err)
+		}
+		defer f.Close()
+		input = f
+	}
+
+	contents, err := m.convert(input)
+	if err != nil {
+		return fmt.Errorf("failed to convert CSV file: %w", err)
+	}
+
+	if opts.output == "" {
+		_, err := os.Stdout.Write(contents)
+		return err
+	}
+	return os.WriteFile(opts.output, contents, 0644)
+}
+
+// convert reads the CSV mount specs from the specified reader and returns the
+// equivalent YAML mount spec file. Invalid lines are reported and skipped as
+// is the case when the CSV file is processed by the runtime.
+func (m command) convert(r io.Reader) ([]byte, error) {
+	var mountSpecs []*csv.MountSpec
+
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		mountSpec, err := csv.NewMountSpecFromLine(line)
+		if err != nil {
+			m.logger.Warningf("Skipping invalid line %d: %v", n, err)
+			continue
+		}
+		mountSpecs = append(mountSpecs, mountSpec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return csv.ToYAML(mountSpecs)
+}
@@ -0,0 +1,52 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package convertcsv
+
+import (
+	"strings"
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvert(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	input := `dev, /dev/nvmap
+lib, /usr/lib/libnvmedia.so, video
+
+lib, /usr/lib/libnvmedia_iep.so, video
+invalid
+`
+
+	c := command{
+		logger: logger,
+	}
+	contents, err := c.convert(strings.NewReader(input))
+	require.NoError(t, err)
+	require.Equal(t, `mounts:
+    - type: dev
+      paths:
+        - /dev/nvmap
+    - type: lib
+      paths:
+        - /usr/lib/libnvmedia.so
+        - /usr/lib/libnvmedia_iep.so
+      capabilities: [video]
+`, string(contents))
+}
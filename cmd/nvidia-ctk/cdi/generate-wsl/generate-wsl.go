@@ -0,0 +1,304 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package generatewsl
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+	"tags.cncf.io/container-device-interface/specs-go"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/discover"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/edits"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/platform-support/wsl"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/nvcdi/spec"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/nvcdi/transform"
+)
+
+const (
+	allDeviceName = "all"
+
+	defaultNVIDIACDIHookPath = "/usr/bin/nvidia-cdi-hook"
+)
+
+type command struct {
+	logger logger.Interface
+}
+
+type options struct {
+	distro            string
+	distroRoot        string
+	driverStore       string
+	output            string
+	format            string
+	nvidiaCDIHookPath string
+	ldconfigPath      string
+	vendor            string
+	class             string
+}
+
+// NewCommand constructs a generate command that generates a CDI
+// specification for a WSL2 distribution from the Windows host.
+func NewCommand(logger logger.Interface) *cli.Command {
+	c := command{
+		logger: logger,
+	}
+	return c.build()
+}
+
+// build creates the CLI command
+func (m command) build() *cli.Command {
+	opts := options{}
+
+	c := cli.Command{
+		Name:  "generate",
+		Usage: "Generate a CDI specification for use with CDI-enabled runtimes in a WSL2 distribution",
+		Before: func(ctx context.Context, cmd *cli.Command) (context.Context, error) {
+			return ctx, m.validateFlags(&opts)
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			return m.run(&opts)
+		},
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "distro",
+				Usage:       "The name of the WSL2 distribution to generate the CDI specification for",
+				Destination: &opts.distro,
+			},
+			&cli.StringFlag{
+				Name:        "distro-root",
+				Usage:       "The path at which the root filesystem of the WSL2 distribution is accessible from the host. If this is '' \\\\wsl$\\<distro> is used",
+				Destination: &opts.distroRoot,
+			},
+			&cli.StringFlag{
+				Name:        "driver-store",
+				Usage:       "The path of the driver store in the WSL2 distribution. If this is '' the most recent driver store in " + wsl.DriverStoresPath + " is used",
+				Destination: &opts.driverStore,
+			},
+			&cli.StringFlag{
+				Name:        "output",
+				Usage:       "Specify the path in the WSL2 distribution to output the generated CDI specification to (e.g. /etc/cdi/nvidia.yaml). If this is '' the specification is output to STDOUT",
+				Destination: &opts.output,
+			},
+			&cli.StringFlag{
+				Name:        "format",
+				Usage:       "The output format for the generated spec [json | yaml]. This is only used if the output file has no extension.",
+				Value:       spec.FormatYAML,
+				Destination: &opts.format,
+			},
+			&cli.StringFlag{
+				Name:        "nvidia-cdi-hook-path",
+				Usage:       "Specify the path in the WSL2 distribution to use for the nvidia-cdi-hook in the generated CDI specification",
+				Value:       defaultNVIDIACDIHookPath,
+				Destination: &opts.nvidiaCDIHookPath,
+			},
+			&cli.StringFlag{
+				Name:        "ldconfig-path",
+				Usage:       "Specify the path to use for ldconfig in the generated CDI specification",
+				Destination: &opts.ldconfigPath,
+			},
+			&cli.StringFlag{
+				Name:        "vendor",
+				Aliases:     []string{"cdi-vendor"},
+				Usage:       "the vendor string to use for the generated CDI specification.",
+				Value:       "nvidia.com",
+				Destination: &opts.vendor,
+			},
+			&cli.StringFlag{
+				Name:        "class",
+				Aliases:     []string{"cdi-class"},
+				Usage:       "the class string to use for the generated CDI specification.",
+				Value:       "gpu",
+				Destination: &opts.class,
+			},
+		},
+	}
+
+	return &c
+}
+
+func (m command) validateFlags(opts *options) error {
+	if opts.distroRoot == "" {
+		if opts.distro == "" {
+			return fmt.Errorf("the WSL2 distribution must be specified")
+		}
+		opts.distroRoot = `\\wsl$\` + opts.distro
+	}
+	if opts.output != "" && !strings.HasPrefix(opts.output, "/") {
+		return fmt.Errorf("the output path %q must be an absolute path in the WSL2 distribution", opts.output)
+	}
+
+	switch opts.format {
+	case spec.FormatJSON:
+	case spec.FormatYAML:
+	default:
+		return fmt.Errorf("invalid output format: %v", opts.format)
+	}
+	return nil
+}
+
+func (m command) run(opts *options) error {
+	spec, err := m.generateSpec(opts)
+	if err != nil {
+		return fmt.Errorf("failed to generate CDI spec: %v", err)
+	}
+	m.logger.Infof("Generated CDI spec with version %v", spec.Raw().Version)
+
+	if opts.output == "" {
+		_, err := spec.WriteTo(os.Stdout)
+		if err != nil {
+			return fmt.Errorf("failed to write CDI spec to STDOUT: %v", err)
+		}
+		return nil
+	}
+
+	return spec.Save(filepath.Join(opts.distroRoot, opts.output))
+}
+
+// generateSpec generates a CDI specification for the WSL2 distribution. The
+// driver files are located through the root of the distribution on the host,
+// but all paths in the generated specification refer to the paths in the
+// distribution.
+func (m command) generateSpec(opts *options) (spec.Interface, error) {
+	driverStore := opts.driverStore
+	if driverStore == "" {
+		paths, err := wsl.FindDriverStorePaths(opts.distroRoot)
+		if err != nil {
+			return nil, err
+		}
+		selected, err := wsl.SelectDriverStorePath(m.logger, opts.distroRoot, paths)
+		if err != nil {
+			return nil, err
+		}
+		driverStore = selected
+	}
+	m.logger.Infof("Using WSL driver store path: %v", driverStore)
+
+	locator := &distroLocator{
+		root:    opts.distroRoot,
+		Locator: wsl.NewDriverStoreLocator(m.logger, opts.distroRoot, driverStore),
+	}
+	hookCreator := discover.NewHookCreator(
+		discover.WithNVIDIACDIHookPath(opts.nvidiaCDIHookPath),
+	)
+	driver := wsl.NewDriverDiscoverer(m.logger, locator, "/", hookCreator, opts.ldconfigPath)
+
+	commonEdits, err := edits.FromDiscoverer(driver)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create edits common for entities: %v", err)
+	}
+
+	// The DXG device node is not accessible from the host. Since missing
+	// device node information is filled in when the edits are applied, only
+	// the path is included.
+	deviceSpecs := []specs.Device{
+		{
+			Name: allDeviceName,
+			ContainerEdits: specs.ContainerEdits{
+				DeviceNodes: []*specs.DeviceNode{
+					{Path: wsl.DXGDeviceNode},
+				},
+			},
+		},
+	}
+
+	generated, err := spec.New(
+		spec.WithVendor(opts.vendor),
+		spec.WithClass(opts.class),
+		spec.WithDeviceSpecs(deviceSpecs),
+		spec.WithEdits(*commonEdits.ContainerEdits),
+		spec.WithFormat(opts.format),
+		spec.WithMergedDeviceOptions(
+			transform.WithName(allDeviceName),
+			transform.WithSkipIfExists(true),
+		),
+		spec.WithPermissions(0644),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := (slashPaths{}).Transform(generated.Raw()); err != nil {
+		return nil, fmt.Errorf("failed to transform paths: %w", err)
+	}
+	return generated, nil
+}
+
+// A distroLocator returns the located files relative to the root of a WSL2
+// distribution. This ensures that the located paths are the paths in the
+// distribution and not the paths through which the distribution is accessed
+// on the host.
+type distroLocator struct {
+	root string
+	lookup.Locator
+}
+
+func (l *distroLocator) Locate(pattern string) ([]string, error) {
+	located, err := l.Locator.Locate(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	root := filepath.Clean(l.root)
+	var paths []string
+	for _, p := range located {
+		paths = append(paths, filepath.ToSlash(strings.TrimPrefix(p, root)))
+	}
+	return paths, nil
+}
+
+// slashPaths converts the paths in a CDI specification to use forward
+// slashes. On a Windows host, paths that are constructed from the paths in
+// the distribution (e.g. the folders passed to the update-ldcache hook) use
+// the Windows path separator.
+type slashPaths struct{}
+
+var _ transform.Transformer = (*slashPaths)(nil)
+
+func (t slashPaths) Transform(spec *specs.Spec) error {
+	if spec == nil {
+		return nil
+	}
+	t.applyToEdits(&spec.ContainerEdits)
+	for i := range spec.Devices {
+		t.applyToEdits(&spec.Devices[i].ContainerEdits)
+	}
+	return nil
+}
+
+func (t slashPaths) applyToEdits(edits *specs.ContainerEdits) {
+	for _, dn := range edits.DeviceNodes {
+		dn.Path = filepath.ToSlash(dn.Path)
+		dn.HostPath = filepath.ToSlash(dn.HostPath)
+	}
+	for _, mount := range edits.Mounts {
+		mount.HostPath = filepath.ToSlash(mount.HostPath)
+		mount.ContainerPath = filepath.ToSlash(mount.ContainerPath)
+	}
+	for _, hook := range edits.Hooks {
+		hook.Path = filepath.ToSlash(hook.Path)
+		for i, arg := range hook.Args {
+			hook.Args[i] = filepath.ToSlash(arg)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package generatewsl
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateSpec(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	distroRoot := t.TempDir()
+	driverStore := filepath.Join(distroRoot, "usr/lib/wsl/drivers/nv_dispi.inf_amd64_1234")
+	require.NoError(t, os.MkdirAll(driverStore, 0755))
+	for _, file := range []string{
+		"libcuda.so.1.1",
+		"libcuda_loader.so",
+		"libnvidia-ptxjitcompiler.so.1",
+		"libnvidia-ml.so.1",
+		"libnvidia-ml_loader.so",
+		"libnvdxgdmal.so.1",
+		"nvcubins.bin",
+		"nvidia-smi",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(driverStore, file), nil, 0644))
+	}
+	wslLib := filepath.Join(distroRoot, "usr/lib/wsl/lib")
+	require.NoError(t, os.MkdirAll(wslLib, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(wslLib, "libdxcore.so"), nil, 0644))
+
+	opts := options{
+		distroRoot:        distroRoot,
+		output:            "/etc/cdi/nvidia.yaml",
+		format:            "yaml",
+		nvidiaCDIHookPath: defaultNVIDIACDIHookPath,
+		vendor:            "nvidia.com",
+		class:             "gpu",
+	}
+	c := command{logger: logger}
+	require.NoError(t, c.validateFlags(&opts))
+	require.NoError(t, c.run(&opts))
+
+	contents, err := os.ReadFile(filepath.Join(distroRoot, "etc/cdi/nvidia.yaml"))
+	require.NoError(t, err)
+	require.Equal(t, expectedSpec, string(contents))
+}
+
+func TestValidateFlags(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	testCases := []struct {
+		description        string
+		opts               options
+		expectedError      bool
+		expectedDistroRoot string
+	}{
+		{
+			description:   "distro is required",
+			opts:          options{format: "yaml"},
+			expectedError: true,
+		},
+		{
+			description:        "distro root is derived from distro",
+			opts:               options{distro: "Ubuntu", format: "yaml"},
+			expectedDistroRoot: `\\wsl$\Ubuntu`,
+		},
+		{
+			description:   "relative output is rejected",
+			opts:          options{distro: "Ubuntu", output: "nvidia.yaml", format: "yaml"},
+			expectedError: true,
+		},
+		{
+			description:   "invalid format is rejected",
+			opts:          options{distro: "Ubuntu", format: "toml"},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			c := command{logger: logger}
+			err := c.validateFlags(&tc.opts)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedDistroRoot, tc.opts.distroRoot)
+		})
+	}
+}
+
+const expectedSpec = `---
+cdiVersion: 0.3.0
+kind: nvidia.com/gpu
+devices:
+    - name: all
+      containerEdits:
+        deviceNodes:
+            - path: /dev/dxg
+containerEdits:
+    hooks:
+        - hookName: createContainer
+          path: /usr/bin/nvidia-cdi-hook
+          args:
+            - nvidia-cdi-hook
+            - create-symlinks
+            - --link
+            - /usr/lib/wsl/drivers/nv_dispi.inf_amd64_1234/nvidia-smi::/usr/bin/nvidia-smi
+          env:
+            - NVIDIA_CTK_DEBUG=false
+        - hookName: createContainer
+          path: /usr/bin/nvidia-cdi-hook
+          args:
+            - nvidia-cdi-hook
+            - update-ldcache
+            - --folder
+            - /usr/lib/wsl/drivers/nv_dispi.inf_amd64_1234
+            - --folder
+            - /usr/lib/wsl/lib
+          env:
+            - NVIDIA_CTK_DEBUG=false
+    mounts:
+        - hostPath: /usr/lib/wsl/lib/libdxcore.so
+          containerPath: /usr/lib/wsl/lib/libdxcore.so
+          options:
+            - ro
+            - nosuid
+            - nodev
+            - rbind
+            - rprivate
+        - hostPath: /usr/lib/wsl/drivers/nv_dispi.inf_amd64_1234/libcuda.so.1.1
+          containerPath: /usr/lib/wsl/drivers/nv_dispi.inf_amd64_1234/libcuda.so.1.1
+          options:
+            - ro
+            - nosuid
+            - nodev
+            - rbind
+            - rprivate
+        - hostPath: /usr/lib/wsl/drivers/nv_dispi.inf_amd64_1234/libcuda_loader.so
+          containerPath: /usr/lib/wsl/drivers/nv_dispi.inf_amd64_1234/libcuda_loader.so
+          options:
+            - ro
+            - nosuid
+            - nodev
+            - rbind
+            - rprivate
+        - hostPath: /usr/lib/wsl/drivers/nv_dispi.inf_amd64_1234/libnvdxgdmal.so.1
+          containerPath: /usr/lib/wsl/drivers/nv_dispi.inf_amd64_1234/libnvdxgdmal.so.1
+          options:
+            - ro
+            - nosuid
+            - nodev
+            - rbind
+            - rprivate
+        - hostPath: /usr/lib/wsl/drivers/nv_dispi.inf_amd64_1234/libnvidia-ml.so.1
+          containerPath: /usr/lib/wsl/drivers/nv_dispi.inf_amd64_1234/libnvidia-ml.so.1
+          options:
+            - ro
+            - nosuid
+            - nodev
+            - rbind
+            - rprivate
+        - hostPath: /usr/lib/wsl/drivers/nv_dispi.inf_amd64_1234/libnvidia-ml_loader.so
+          containerPath: /usr/lib/wsl/drivers/nv_dispi.inf_amd64_1234/libnvidia-ml_loader.so
+          options:
+            - ro
+            - nosuid
+            - nodev
+            - rbind
+            - rprivate
+        - hostPath: /usr/lib/wsl/drivers/nv_dispi.inf_amd64_1234/libnvidia-ptxjitcompiler.so.1
+          containerPath: /usr/lib/wsl/drivers/nv_dispi.inf_amd64_1234/libnvidia-ptxjitcompiler.so.1
+          options:
+            - ro
+            - nosuid
+            - nodev
+            - rbind
+            - rprivate
+        - hostPath: /usr/lib/wsl/drivers/nv_dispi.inf_amd64_1234/nvcubins.bin
+          containerPath: /usr/lib/wsl/drivers/nv_dispi.inf_amd64_1234/nvcubins.bin
+          options:
+            - ro
+            - nosuid
+            - nodev
+            - rbind
+            - rprivate
+        - hostPath: /usr/lib/wsl/drivers/nv_dispi.inf_amd64_1234/nvidia-smi
+          containerPath: /usr/lib/wsl/drivers/nv_dispi.inf_amd64_1234/nvidia-smi
+          options:
+            - ro
+            - nosuid
+            - nodev
+            - rbind
+            - rprivate
+`
@@ -35,6 +35,7 @@ import (
 	"github.com/NVIDIA/nvidia-container-toolkit/pkg/nvcdi"
 	"github.com/NVIDIA/nvidia-container-toolkit/pkg/nvcdi/spec"
 	"github.com/NVIDIA/nvidia-container-toolkit/pkg/nvcdi/transform"
+	transformroot "github.com/NVIDIA/nvidia-container-toolkit/pkg/nvcdi/transform/root"
 )
 
 const (
@@ -51,13 +52,20 @@ type options struct {
 	output               string
 	format               string
 	deviceNameStrategies []string
+	migStrategy          string
+	deviceOrder          string
 	driverRoot           string
+	driverArchive        string
+	driverArchiveGPUs    int
 	devRoot              string
 	nvidiaCDIHookPath    string
+	hookPaths            []string
 	ldconfigPath         string
 	mode                 string
 	vendor               string
 	class                string
+	watch                bool
+	compat32             bool
 
 	configSearchPaths  []string
 	librarySearchPaths []string
@@ -68,6 +76,10 @@ type options struct {
 		ignorePatterns []string
 	}
 
+	wsl struct {
+		driverStore string
+	}
+
 	// the following are used for dependency injection during spec generation.
 	nvmllib nvml.Interface
 }
@@ -95,6 +107,9 @@ func (m command) build() *cli.Command {
 			return ctx, m.validateFlags(cmd, &opts)
 		},
 		Action: func(ctx context.Context, cmd *cli.Command) error {
+			if opts.watch {
+				return m.watch(ctx, &opts)
+			}
 			return m.run(&opts)
 		},
 		Flags: []cli.Flag{
@@ -143,6 +158,25 @@ func (m command) build() *cli.Command {
 				Destination: &opts.deviceNameStrategies,
 				Sources:     cli.EnvVars("NVIDIA_CTK_CDI_GENERATE_DEVICE_NAME_STRATEGIES"),
 			},
+			&cli.StringFlag{
+				Name: "mig-strategy",
+				Usage: "Specify the MIG strategy of the NVIDIA Kubernetes device plugin to generate device names for. " +
+					"If this is set, the generated devices match the device IDs advertised by the device plugin for the " +
+					"strategy and only the index and uuid device name strategies are supported. One of [none | single | mixed]",
+				Destination: &opts.migStrategy,
+				Sources:     cli.EnvVars("NVIDIA_CTK_CDI_GENERATE_MIG_STRATEGY"),
+			},
+			&cli.StringFlag{
+				Name: "device-order",
+				Usage: "Specify the order in which GPUs are enumerated when generating index-based device names. " +
+					"If this is set to pci-bus-id, the CUDA_DEVICE_ORDER=PCI_BUS_ID envvar is also set in the generated spec " +
+					"so that CUDA applications enumerate devices in the same order. One of [nvml | pci-bus-id]",
+				Destination: &opts.deviceOrder,
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("NVIDIA_CTK_CDI_GENERATE_DEVICE_ORDER"),
+					m.config.ValueFrom("nvidia-container-runtime.modes.cdi.device-order"),
+				),
+			},
 			&cli.StringFlag{
 				Name:        "driver-root",
 				Usage:       "Specify the NVIDIA GPU driver root to use when discovering the entities that should be included in the CDI specification.",
@@ -152,6 +186,22 @@ func (m command) build() *cli.Command {
 					m.config.ValueFrom("nvidia-container-cli.root"),
 				),
 			},
+			&cli.StringFlag{
+				Name: "from-driver-archive",
+				Usage: "Generate the CDI specification for the extracted driver package at the specified path instead of the driver on the host. " +
+					"The driver package is expected to contain the driver files at their installed locations. " +
+					"Host paths in the generated CDI specification are relative to the driver-root.",
+				Destination: &opts.driverArchive,
+				Sources:     cli.EnvVars("NVIDIA_CTK_CDI_GENERATE_FROM_DRIVER_ARCHIVE"),
+			},
+			&cli.IntFlag{
+				Name: "driver-archive-gpu-count",
+				Usage: "Specify the number of GPUs on the system where a CDI specification generated from a driver archive is used. " +
+					"Device nodes are included for this number of GPUs since the GPUs are not queried.\n\tNote: This option only applies when --from-driver-archive is specified.",
+				Value:       1,
+				Destination: &opts.driverArchiveGPUs,
+				Sources:     cli.EnvVars("NVIDIA_CTK_CDI_GENERATE_DRIVER_ARCHIVE_GPU_COUNT"),
+			},
 			&cli.StringSliceFlag{
 				Name:        "library-search-path",
 				Usage:       "Specify the path to search for libraries when discovering the entities that should be included in the CDI specification.\n\tNote: This option only applies to CSV mode.",
@@ -167,6 +217,16 @@ func (m command) build() *cli.Command {
 				Destination: &opts.nvidiaCDIHookPath,
 				Sources:     cli.EnvVars("NVIDIA_CTK_CDI_HOOK_PATH"),
 			},
+			&cli.StringSliceFlag{
+				Name: "hook-path",
+				Usage: "Specify the path to the executable to use for a specific hook as HOOK_NAME=PATH " +
+					"(e.g. update-ldcache=/usr/libexec/nvidia/update-ldcache). " +
+					"Unless the executable is an nvidia-cdi-hook or nvidia-ctk binary, it is invoked with the hook arguments directly. " +
+					"Hooks that are not specified use the nvidia-cdi-hook path. " +
+					"This can be specified multiple times.",
+				Destination: &opts.hookPaths,
+				Sources:     cli.EnvVars("NVIDIA_CTK_CDI_GENERATE_HOOK_PATHS"),
+			},
 			&cli.StringFlag{
 				Name:        "ldconfig-path",
 				Usage:       "Specify the path to use for ldconfig in the generated CDI specification",
@@ -204,6 +264,13 @@ func (m command) build() *cli.Command {
 				Destination: &opts.csv.ignorePatterns,
 				Sources:     cli.EnvVars("NVIDIA_CTK_CDI_GENERATE_CSV_IGNORE_PATTERNS"),
 			},
+			&cli.StringFlag{
+				Name:        "wsl.driver-store",
+				Aliases:     []string{"driver-store"},
+				Usage:       "The path to the WSL2 driver store to use when generating the CDI specification in WSL mode. If this is not specified, the driver store for the loaded adapters is selected.",
+				Destination: &opts.wsl.driverStore,
+				Sources:     cli.EnvVars("NVIDIA_CTK_CDI_GENERATE_WSL_DRIVER_STORE"),
+			},
 			&cli.StringSliceFlag{
 				Name:    "disable-hook",
 				Aliases: []string{"disable-hooks"},
@@ -214,6 +281,23 @@ func (m command) build() *cli.Command {
 				Destination: &opts.disabledHooks,
 				Sources:     cli.EnvVars("NVIDIA_CTK_CDI_GENERATE_DISABLED_HOOKS"),
 			},
+			&cli.BoolFlag{
+				Name: "watch",
+				Usage: "continue running after the CDI specification has been generated and " +
+					"regenerate it whenever NVML reports a MIG configuration change. " +
+					"If no GPUs support MIG configuration change events, the MIG configuration is polled instead. " +
+					"This requires --output to be specified.",
+				Destination: &opts.watch,
+				Sources:     cli.EnvVars("NVIDIA_CTK_CDI_GENERATE_WATCH"),
+			},
+			&cli.BoolFlag{
+				Name: "compat32",
+				Usage: "include the 32-bit driver libraries of a multi-arch system in the " +
+					"generated CDI specification. This is required for 32-bit applications " +
+					"such as games run using Steam or Proton.",
+				Destination: &opts.compat32,
+				Sources:     cli.EnvVars("NVIDIA_CTK_CDI_GENERATE_COMPAT32"),
+			},
 		},
 	}
 
@@ -241,8 +325,32 @@ func (m command) validateFlags(c *cli.Command, opts *options) error {
 		}
 	}
 
+	opts.deviceOrder = strings.ToLower(opts.deviceOrder)
+	if !nvcdi.IsValidDeviceOrder(opts.deviceOrder) {
+		return fmt.Errorf("invalid device order: %v", opts.deviceOrder)
+	}
+
+	opts.migStrategy = strings.ToLower(opts.migStrategy)
+	if !nvcdi.IsValidMIGStrategy(opts.migStrategy) {
+		return fmt.Errorf("invalid MIG strategy: %v", opts.migStrategy)
+	}
+	if opts.migStrategy != "" {
+		// The device plugin uses either the device UUIDs or indices as IDs.
+		for _, strategy := range opts.deviceNameStrategies {
+			if strategy != nvcdi.DeviceNameStrategyIndex && strategy != nvcdi.DeviceNameStrategyUUID {
+				return fmt.Errorf("device name strategy %q is not supported with MIG strategy %q", strategy, opts.migStrategy)
+			}
+		}
+	}
+
 	opts.nvidiaCDIHookPath = config.ResolveNVIDIACDIHookPath(m.logger, opts.nvidiaCDIHookPath)
 
+	for _, hookPath := range opts.hookPaths {
+		if _, _, err := parseHookPath(hookPath); err != nil {
+			return err
+		}
+	}
+
 	if outputFileFormat := formatFromFilename(opts.output); outputFileFormat != "" {
 		m.logger.Debugf("Inferred output format as %q from output file name", outputFileFormat)
 		if !c.IsSet("format") {
@@ -252,6 +360,14 @@ func (m command) validateFlags(c *cli.Command, opts *options) error {
 		}
 	}
 
+	if opts.watch && opts.output == "" {
+		return fmt.Errorf("an output file must be specified when watching for MIG configuration changes")
+	}
+
+	if err := m.validateDriverArchive(opts); err != nil {
+		return err
+	}
+
 	if err := cdi.ValidateVendorName(opts.vendor); err != nil {
 		return fmt.Errorf("invalid CDI vendor name: %v", err)
 	}
@@ -261,6 +377,31 @@ func (m command) validateFlags(c *cli.Command, opts *options) error {
 	return nil
 }
 
+// validateDriverArchive validates the flags associated with generating a CDI
+// specification from an offline driver package.
+func (m command) validateDriverArchive(opts *options) error {
+	if opts.driverArchive == "" {
+		return nil
+	}
+	info, err := os.Stat(opts.driverArchive)
+	if err != nil {
+		return fmt.Errorf("invalid driver archive: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("invalid driver archive: %v is not a directory", opts.driverArchive)
+	}
+	if opts.mode != string(nvcdi.ModeAuto) {
+		return fmt.Errorf("mode %q is not supported when generating a CDI specification from a driver archive", opts.mode)
+	}
+	if opts.watch {
+		return fmt.Errorf("watching for MIG configuration changes is not supported when generating a CDI specification from a driver archive")
+	}
+	if opts.driverArchiveGPUs < 1 {
+		return fmt.Errorf("invalid GPU count %d; at least one GPU is required", opts.driverArchiveGPUs)
+	}
+	return nil
+}
+
 func (m command) run(opts *options) error {
 	spec, err := m.generateSpec(opts)
 	if err != nil {
@@ -301,18 +442,35 @@ func (m command) generateSpec(opts *options) (spec.Interface, error) {
 		deviceNamers = append(deviceNamers, deviceNamer)
 	}
 
+	driverRoot, devRoot, mode := opts.driverRoot, opts.devRoot, opts.mode
+	if opts.driverArchive != "" {
+		// The files in the driver archive are discovered and the driver root
+		// is the location of the driver on the system where the generated
+		// spec is used.
+		driverRoot = opts.driverArchive
+		if devRoot == "" {
+			devRoot = opts.getTargetDriverRoot()
+		}
+		mode = string(nvcdi.ModeDriverArchive)
+	}
+
 	cdiOptions := []nvcdi.Option{
 		nvcdi.WithLogger(m.logger),
-		nvcdi.WithDriverRoot(opts.driverRoot),
-		nvcdi.WithDevRoot(opts.devRoot),
+		nvcdi.WithDriverRoot(driverRoot),
+		nvcdi.WithDevRoot(devRoot),
 		nvcdi.WithNVIDIACDIHookPath(opts.nvidiaCDIHookPath),
 		nvcdi.WithLdconfigPath(opts.ldconfigPath),
 		nvcdi.WithDeviceNamers(deviceNamers...),
-		nvcdi.WithMode(opts.mode),
+		nvcdi.WithMIGStrategy(opts.migStrategy),
+		nvcdi.WithDeviceOrder(opts.deviceOrder),
+		nvcdi.WithCompat32Libraries(opts.compat32),
+		nvcdi.WithMode(mode),
 		nvcdi.WithConfigSearchPaths(opts.configSearchPaths),
 		nvcdi.WithLibrarySearchPaths(opts.librarySearchPaths),
 		nvcdi.WithCSVFiles(opts.csv.files),
 		nvcdi.WithCSVIgnorePatterns(opts.csv.ignorePatterns),
+		nvcdi.WithWSLDriverStorePath(opts.wsl.driverStore),
+		nvcdi.WithDriverArchiveGPUCount(opts.driverArchiveGPUs),
 		// We set the following to allow for dependency injection:
 		nvcdi.WithNvmlLib(opts.nvmllib),
 	}
@@ -320,6 +478,10 @@ func (m command) generateSpec(opts *options) (spec.Interface, error) {
 	for _, hook := range opts.disabledHooks {
 		cdiOptions = append(cdiOptions, nvcdi.WithDisabledHook(hook))
 	}
+	for _, hookPath := range opts.hookPaths {
+		name, path, _ := parseHookPath(hookPath)
+		cdiOptions = append(cdiOptions, nvcdi.WithHookPath(name, path))
+	}
 
 	cdilib, err := nvcdi.New(cdiOptions...)
 	if err != nil {
@@ -336,7 +498,7 @@ func (m command) generateSpec(opts *options) (spec.Interface, error) {
 		return nil, fmt.Errorf("failed to create edits common for entities: %v", err)
 	}
 
-	return spec.New(
+	generated, err := spec.New(
 		spec.WithVendor(opts.vendor),
 		spec.WithClass(opts.class),
 		spec.WithDeviceSpecs(deviceSpecs),
@@ -348,4 +510,40 @@ func (m command) generateSpec(opts *options) (spec.Interface, error) {
 		),
 		spec.WithPermissions(0644),
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.driverArchive != "" {
+		err := transformroot.New(
+			transformroot.WithRoot(opts.driverArchive),
+			transformroot.WithTargetRoot(opts.getTargetDriverRoot()),
+		).Transform(generated.Raw())
+		if err != nil {
+			return nil, fmt.Errorf("failed to transform driver archive paths: %w", err)
+		}
+	}
+
+	return generated, nil
+}
+
+// getTargetDriverRoot returns the driver root of the system where a CDI
+// specification generated from a driver archive is used.
+func (o *options) getTargetDriverRoot() string {
+	if o.driverRoot == "" {
+		return "/"
+	}
+	return o.driverRoot
+}
+
+// parseHookPath parses a hook path specified as HOOK_NAME=PATH.
+func parseHookPath(hookPath string) (string, string, error) {
+	name, path, found := strings.Cut(hookPath, "=")
+	if !found || name == "" || path == "" {
+		return "", "", fmt.Errorf("invalid hook path %q; expected HOOK_NAME=PATH", hookPath)
+	}
+	if !nvcdi.IsValidHookName(name) {
+		return "", "", fmt.Errorf("invalid hook path %q; unknown hook name %q", hookPath, name)
+	}
+	return name, path, nil
 }
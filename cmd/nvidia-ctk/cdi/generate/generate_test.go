@@ -402,3 +402,110 @@ containerEdits:
 		})
 	}
 }
+
+func TestGenerateSpecFromDriverArchive(t *testing.T) {
+	moduleRoot, err := test.GetModuleRoot()
+	require.NoError(t, err)
+
+	driverArchive := filepath.Join(moduleRoot, "testdata", "lookup", "rootfs-1")
+
+	logger, _ := testlog.NewNullLogger()
+	c := command{
+		logger: logger,
+	}
+
+	opts := options{
+		format:            "yaml",
+		mode:              "auto",
+		vendor:            "example.com",
+		class:             "device",
+		driverArchive:     driverArchive,
+		driverArchiveGPUs: 2,
+	}
+	require.NoError(t, c.validateFlags(nil, &opts))
+
+	spec, err := c.generateSpec(&opts)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	_, err = spec.WriteTo(&buf)
+	require.NoError(t, err)
+	// The driver files are discovered in the archive, but the host paths are
+	// relative to the target driver root. The device nodes are not discovered.
+	expectedSpec := `---
+cdiVersion: 0.5.0
+kind: example.com/device
+devices:
+    - name: all
+      containerEdits:
+        deviceNodes:
+            - path: /dev/nvidia-uvm
+              hostPath: /dev/nvidia-uvm
+            - path: /dev/nvidia-uvm-tools
+              hostPath: /dev/nvidia-uvm-tools
+            - path: /dev/nvidia0
+              hostPath: /dev/nvidia0
+            - path: /dev/nvidia1
+              hostPath: /dev/nvidia1
+            - path: /dev/nvidiactl
+              hostPath: /dev/nvidiactl
+containerEdits:
+    env:
+        - NVIDIA_CTK_LIBCUDA_DIR=/lib/x86_64-linux-gnu
+        - NVIDIA_VISIBLE_DEVICES=void
+    hooks:
+        - hookName: createContainer
+          path: /usr/bin/nvidia-cdi-hook
+          args:
+            - nvidia-cdi-hook
+            - create-symlinks
+            - --link
+            - libcuda.so.1::/lib/x86_64-linux-gnu/libcuda.so
+          env:
+            - NVIDIA_CTK_DEBUG=false
+        - hookName: createContainer
+          path: /usr/bin/nvidia-cdi-hook
+          args:
+            - nvidia-cdi-hook
+            - enable-cuda-compat
+            - --host-driver-version=999.88.77
+          env:
+            - NVIDIA_CTK_DEBUG=false
+        - hookName: createContainer
+          path: /usr/bin/nvidia-cdi-hook
+          args:
+            - nvidia-cdi-hook
+            - update-ldcache
+            - --folder
+            - /lib/x86_64-linux-gnu
+            - --folder
+            - /lib/x86_64-linux-gnu/vdpau
+          env:
+            - NVIDIA_CTK_DEBUG=false
+        - hookName: createContainer
+          path: /usr/bin/nvidia-cdi-hook
+          args:
+            - nvidia-cdi-hook
+            - disable-device-node-modification
+          env:
+            - NVIDIA_CTK_DEBUG=false
+    mounts:
+        - hostPath: /lib/x86_64-linux-gnu/libcuda.so.999.88.77
+          containerPath: /lib/x86_64-linux-gnu/libcuda.so.999.88.77
+          options:
+            - ro
+            - nosuid
+            - nodev
+            - rbind
+            - rprivate
+        - hostPath: /lib/x86_64-linux-gnu/vdpau/libvdpau_nvidia.so.999.88.77
+          containerPath: /lib/x86_64-linux-gnu/vdpau/libvdpau_nvidia.so.999.88.77
+          options:
+            - ro
+            - nosuid
+            - nodev
+            - rbind
+            - rprivate
+`
+	require.Equal(t, expectedSpec, buf.String())
+}
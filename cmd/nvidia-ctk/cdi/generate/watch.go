@@ -0,0 +1,280 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package generate
+
+import (
+	"context"
+	"fmt"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+)
+
+const (
+	// migEventWaitTimeoutMs is the time that we block waiting for NVML
+	// events before checking whether the watch has been cancelled.
+	migEventWaitTimeoutMs = 1000
+	// migConfigPollInterval is the interval at which the MIG configuration
+	// is queried if no GPUs support MIG configuration change events.
+	migConfigPollInterval = 10 * time.Second
+)
+
+// A migConfigWatcher listens for NVML events that indicate that the MIG
+// configuration of a GPU has changed. If no GPUs support these events, the
+// MIG configuration is polled instead.
+type migConfigWatcher struct {
+	logger   logger.Interface
+	nvmllib  nvml.Interface
+	eventSet nvml.EventSet
+
+	// polling indicates that the MIG configuration is polled since no GPUs
+	// support MIG configuration change events.
+	polling      bool
+	pollInterval time.Duration
+	// migConfig is the last MIG configuration seen while polling.
+	migConfig string
+}
+
+// newMIGConfigWatcher initializes NVML and registers for MIG configuration
+// change events on all GPUs that support them. If no GPUs support these
+// events, the watcher falls back to polling the MIG configuration.
+func newMIGConfigWatcher(logger logger.Interface, nvmllib nvml.Interface) (*migConfigWatcher, error) {
+	if nvmllib == nil {
+		nvmllib = nvml.New()
+	}
+	if ret := nvmllib.Init(); ret != nvml.SUCCESS {
+		return nil, fmt.Errorf("failed to initialize NVML: %v", ret)
+	}
+
+	w := &migConfigWatcher{
+		logger:       logger,
+		nvmllib:      nvmllib,
+		pollInterval: migConfigPollInterval,
+	}
+	if err := w.register(); err != nil {
+		_ = w.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *migConfigWatcher) register() error {
+	eventSet, ret := w.nvmllib.EventSetCreate()
+	if ret != nvml.SUCCESS {
+		return fmt.Errorf("failed to create NVML event set: %v", ret)
+	}
+	w.eventSet = eventSet
+
+	count, ret := w.nvmllib.DeviceGetCount()
+	if ret != nvml.SUCCESS {
+		return fmt.Errorf("failed to get device count: %v", ret)
+	}
+
+	var registered int
+	for i := 0; i < count; i++ {
+		device, ret := w.nvmllib.DeviceGetHandleByIndex(i)
+		if ret != nvml.SUCCESS {
+			return fmt.Errorf("failed to get device handle for GPU %d: %v", i, ret)
+		}
+		supported, ret := device.GetSupportedEventTypes()
+		if ret != nvml.SUCCESS {
+			w.logger.Warningf("Failed to get supported event types for GPU %d: %v", i, ret)
+			continue
+		}
+		if supported&nvml.EventMigConfigChange == 0 {
+			w.logger.Debugf("GPU %d does not support MIG configuration change events", i)
+			continue
+		}
+		if ret := device.RegisterEvents(nvml.EventMigConfigChange, eventSet); ret != nvml.SUCCESS {
+			return fmt.Errorf("failed to register for MIG configuration change events for GPU %d: %v", i, ret)
+		}
+		registered++
+	}
+
+	if registered == 0 {
+		migConfig, err := w.getMIGConfig()
+		if err != nil {
+			return err
+		}
+		w.polling = true
+		w.migConfig = migConfig
+		w.logger.Warningf("No GPUs support MIG configuration change events; polling for MIG configuration changes every %v", w.pollInterval)
+		return nil
+	}
+	w.logger.Infof("Watching %d GPU(s) for MIG configuration changes", registered)
+	return nil
+}
+
+// getMIGConfig returns a string representation of the MIG configuration of
+// all GPUs. This includes the current MIG mode of each GPU and the UUIDs of
+// its MIG devices.
+func (w *migConfigWatcher) getMIGConfig() (string, error) {
+	count, ret := w.nvmllib.DeviceGetCount()
+	if ret != nvml.SUCCESS {
+		return "", fmt.Errorf("failed to get device count: %v", ret)
+	}
+
+	var gpus []string
+	for i := 0; i < count; i++ {
+		device, ret := w.nvmllib.DeviceGetHandleByIndex(i)
+		if ret != nvml.SUCCESS {
+			return "", fmt.Errorf("failed to get device handle for GPU %d: %v", i, ret)
+		}
+		mode, _, ret := device.GetMigMode()
+		if ret == nvml.ERROR_NOT_SUPPORTED || (ret == nvml.SUCCESS && mode != nvml.DEVICE_MIG_ENABLE) {
+			gpus = append(gpus, fmt.Sprintf("%d:disabled", i))
+			continue
+		}
+		if ret != nvml.SUCCESS {
+			return "", fmt.Errorf("failed to get MIG mode for GPU %d: %v", i, ret)
+		}
+
+		migDevices := []string{fmt.Sprintf("%d:enabled", i)}
+		maxCount, ret := device.GetMaxMigDeviceCount()
+		if ret != nvml.SUCCESS {
+			return "", fmt.Errorf("failed to get MIG device count for GPU %d: %v", i, ret)
+		}
+		for j := 0; j < maxCount; j++ {
+			migDevice, ret := device.GetMigDeviceHandleByIndex(j)
+			if ret == nvml.ERROR_NOT_FOUND {
+				continue
+			}
+			if ret != nvml.SUCCESS {
+				return "", fmt.Errorf("failed to get MIG device %d of GPU %d: %v", j, i, ret)
+			}
+			uuid, ret := migDevice.GetUUID()
+			if ret != nvml.SUCCESS {
+				return "", fmt.Errorf("failed to get UUID of MIG device %d of GPU %d: %v", j, i, ret)
+			}
+			migDevices = append(migDevices, uuid)
+		}
+		gpus = append(gpus, strings.Join(migDevices, ","))
+	}
+	return strings.Join(gpus, ";"), nil
+}
+
+// Wait blocks until a MIG configuration change event is received or the
+// context is cancelled. The returned value indicates whether a change was
+// detected.
+func (w *migConfigWatcher) Wait(ctx context.Context) (bool, error) {
+	if w.polling {
+		return w.poll(ctx)
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return false, nil
+		default:
+		}
+
+		event, ret := w.eventSet.Wait(migEventWaitTimeoutMs)
+		switch ret {
+		case nvml.SUCCESS:
+		case nvml.ERROR_TIMEOUT:
+			continue
+		default:
+			return false, fmt.Errorf("failed to wait for NVML events: %v", ret)
+		}
+
+		if event.EventType&nvml.EventMigConfigChange == 0 {
+			continue
+		}
+		w.logger.Infof("Detected MIG configuration change (GPU instance %d, compute instance %d)", event.GpuInstanceId, event.ComputeInstanceId)
+		return true, nil
+	}
+}
+
+// poll blocks until the MIG configuration differs from the last MIG
+// configuration seen or the context is cancelled.
+func (w *migConfigWatcher) poll(ctx context.Context) (bool, error) {
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return false, nil
+		case <-ticker.C:
+		}
+
+		migConfig, err := w.getMIGConfig()
+		if err != nil {
+			return false, err
+		}
+		if migConfig == w.migConfig {
+			continue
+		}
+		w.migConfig = migConfig
+		w.logger.Infof("Detected MIG configuration change")
+		return true, nil
+	}
+}
+
+// Close releases the NVML event set and shuts down NVML.
+func (w *migConfigWatcher) Close() error {
+	if w.eventSet != nil {
+		if ret := w.eventSet.Free(); ret != nvml.SUCCESS {
+			w.logger.Warningf("Failed to free NVML event set: %v", ret)
+		}
+	}
+	if ret := w.nvmllib.Shutdown(); ret != nvml.SUCCESS {
+		return fmt.Errorf("failed to shutdown NVML: %v", ret)
+	}
+	return nil
+}
+
+// watch generates the CDI specification and regenerates it each time the MIG
+// configuration of a GPU changes. A new CDI library is constructed for each
+// generation so that no discovery results are reused across MIG changes.
+func (m command) watch(ctx context.Context, opts *options) error {
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	watcher, err := newMIGConfigWatcher(m.logger, opts.nvmllib)
+	if err != nil {
+		return fmt.Errorf("failed to watch for MIG configuration changes: %w", err)
+	}
+	defer func() {
+		if err := watcher.Close(); err != nil {
+			m.logger.Warningf("Failed to stop watching for MIG configuration changes: %v", err)
+		}
+	}()
+
+	if err := m.run(opts); err != nil {
+		return err
+	}
+
+	for {
+		changed, err := watcher.Wait(ctx)
+		if err != nil {
+			return err
+		}
+		if !changed {
+			m.logger.Infof("Stopped watching for MIG configuration changes")
+			return nil
+		}
+
+		m.logger.Infof("Regenerating CDI specification %v", opts.output)
+		if err := m.run(opts); err != nil {
+			m.logger.Errorf("Failed to regenerate CDI specification: %v", err)
+		}
+	}
+}
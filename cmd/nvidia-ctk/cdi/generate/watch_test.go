@@ -0,0 +1,207 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package generate
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/NVIDIA/go-nvml/pkg/nvml/mock"
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMIGConfigWatcher(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	testCases := []struct {
+		description     string
+		supportedEvents uint64
+		events          []nvml.EventData
+		expectedChanged bool
+	}{
+		{
+			description:     "MIG config change is detected",
+			supportedEvents: nvml.EventTypeXidCriticalError | nvml.EventMigConfigChange,
+			events: []nvml.EventData{
+				{EventType: nvml.EventMigConfigChange},
+			},
+			expectedChanged: true,
+		},
+		{
+			description:     "unrelated events are ignored",
+			supportedEvents: nvml.EventTypeXidCriticalError | nvml.EventMigConfigChange,
+			events: []nvml.EventData{
+				{EventType: nvml.EventTypeXidCriticalError},
+				{EventType: nvml.EventMigConfigChange},
+			},
+			expectedChanged: true,
+		},
+		{
+			description:     "cancelled context stops the watch",
+			supportedEvents: nvml.EventMigConfigChange,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			var registered []uint64
+			eventSet := &mock.EventSet{
+				WaitFunc: func(uint32) (nvml.EventData, nvml.Return) {
+					if len(tc.events) == 0 {
+						cancel()
+						return nvml.EventData{}, nvml.ERROR_TIMEOUT
+					}
+					event := tc.events[0]
+					tc.events = tc.events[1:]
+					return event, nvml.SUCCESS
+				},
+				FreeFunc: func() nvml.Return {
+					return nvml.SUCCESS
+				},
+			}
+			device := &mock.Device{
+				GetSupportedEventTypesFunc: func() (uint64, nvml.Return) {
+					return tc.supportedEvents, nvml.SUCCESS
+				},
+				RegisterEventsFunc: func(eventTypes uint64, _ nvml.EventSet) nvml.Return {
+					registered = append(registered, eventTypes)
+					return nvml.SUCCESS
+				},
+			}
+			nvmllib := &mock.Interface{
+				InitFunc: func() nvml.Return {
+					return nvml.SUCCESS
+				},
+				ShutdownFunc: func() nvml.Return {
+					return nvml.SUCCESS
+				},
+				EventSetCreateFunc: func() (nvml.EventSet, nvml.Return) {
+					return eventSet, nvml.SUCCESS
+				},
+				DeviceGetCountFunc: func() (int, nvml.Return) {
+					return 1, nvml.SUCCESS
+				},
+				DeviceGetHandleByIndexFunc: func(int) (nvml.Device, nvml.Return) {
+					return device, nvml.SUCCESS
+				},
+			}
+
+			w, err := newMIGConfigWatcher(logger, nvmllib)
+			require.NoError(t, err)
+			require.EqualValues(t, []uint64{nvml.EventMigConfigChange}, registered)
+
+			changed, err := w.Wait(ctx)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedChanged, changed)
+
+			require.NoError(t, w.Close())
+			require.Len(t, eventSet.FreeCalls(), 1)
+		})
+	}
+}
+
+func TestMIGConfigWatcherPolling(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	testCases := []struct {
+		description     string
+		migModes        []int
+		expectedChanged bool
+	}{
+		{
+			description:     "MIG mode change is detected",
+			migModes:        []int{nvml.DEVICE_MIG_DISABLE, nvml.DEVICE_MIG_DISABLE, nvml.DEVICE_MIG_ENABLE},
+			expectedChanged: true,
+		},
+		{
+			description: "cancelled context stops the watch",
+			migModes:    []int{nvml.DEVICE_MIG_DISABLE, nvml.DEVICE_MIG_DISABLE},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			migDevice := &mock.Device{
+				GetUUIDFunc: func() (string, nvml.Return) {
+					return "MIG-0", nvml.SUCCESS
+				},
+			}
+			device := &mock.Device{
+				GetSupportedEventTypesFunc: func() (uint64, nvml.Return) {
+					return nvml.EventTypeXidCriticalError, nvml.SUCCESS
+				},
+				GetMigModeFunc: func() (int, int, nvml.Return) {
+					if len(tc.migModes) == 0 {
+						cancel()
+						return nvml.DEVICE_MIG_DISABLE, nvml.DEVICE_MIG_DISABLE, nvml.SUCCESS
+					}
+					mode := tc.migModes[0]
+					tc.migModes = tc.migModes[1:]
+					return mode, mode, nvml.SUCCESS
+				},
+				GetMaxMigDeviceCountFunc: func() (int, nvml.Return) {
+					return 1, nvml.SUCCESS
+				},
+				GetMigDeviceHandleByIndexFunc: func(int) (nvml.Device, nvml.Return) {
+					return migDevice, nvml.SUCCESS
+				},
+			}
+			eventSet := &mock.EventSet{
+				FreeFunc: func() nvml.Return {
+					return nvml.SUCCESS
+				},
+			}
+			nvmllib := &mock.Interface{
+				InitFunc: func() nvml.Return {
+					return nvml.SUCCESS
+				},
+				ShutdownFunc: func() nvml.Return {
+					return nvml.SUCCESS
+				},
+				EventSetCreateFunc: func() (nvml.EventSet, nvml.Return) {
+					return eventSet, nvml.SUCCESS
+				},
+				DeviceGetCountFunc: func() (int, nvml.Return) {
+					return 1, nvml.SUCCESS
+				},
+				DeviceGetHandleByIndexFunc: func(int) (nvml.Device, nvml.Return) {
+					return device, nvml.SUCCESS
+				},
+			}
+
+			w, err := newMIGConfigWatcher(logger, nvmllib)
+			require.NoError(t, err)
+			require.True(t, w.polling)
+			w.pollInterval = time.Millisecond
+
+			changed, err := w.Wait(ctx)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedChanged, changed)
+
+			require.NoError(t, w.Close())
+		})
+	}
+}
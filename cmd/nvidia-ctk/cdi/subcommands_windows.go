@@ -0,0 +1,41 @@
+//go:build windows
+
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package cdi
+
+import (
+	"github.com/urfave/cli/v3"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/cdi/cleanup"
+	generatewsl "github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/cdi/generate-wsl"
+	"github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/cdi/list"
+	"github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/cdi/transform"
+)
+
+// subcommands returns the cdi subcommands supported on a Windows host.
+// Since the NVML and DXCore bindings are only available on Linux, the
+// generate subcommand generates a CDI specification for a WSL2 distribution
+// by inspecting the driver store that is available in the distribution.
+func (m command) subcommands() []*cli.Command {
+	return []*cli.Command{
+		cleanup.NewCommand(m.logger),
+		generatewsl.NewCommand(m.logger),
+		list.NewCommand(m.logger),
+		transform.NewCommand(m.logger),
+	}
+}
@@ -0,0 +1,54 @@
+//go:build !windows
+
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package main
+
+import (
+	cli "github.com/urfave/cli/v3"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/cdi"
+	"github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/config"
+	"github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/debug"
+	"github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/hook"
+	infoCLI "github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/info"
+	"github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/metrics"
+	"github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/policy"
+	"github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/preflight"
+	"github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/runtime"
+	"github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/serve"
+	"github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/system"
+	"github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/telemetry"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+)
+
+func getCommands(logger logger.Interface, configFilePath *string) []*cli.Command {
+	return []*cli.Command{
+		hook.NewCommand(logger),
+		runtime.NewCommand(logger),
+		infoCLI.NewCommand(logger, configFilePath),
+		cdi.NewCommand(logger, configFilePath),
+		system.NewCommand(logger, configFilePath),
+		config.NewCommand(logger),
+		debug.NewCommand(logger),
+		metrics.NewCommand(logger, configFilePath),
+		telemetry.NewCommand(logger, configFilePath),
+		serve.NewCommand(logger, configFilePath),
+		policy.NewCommand(logger, configFilePath),
+		preflight.NewCommand(logger),
+	}
+}
@@ -0,0 +1,41 @@
+//go:build windows
+
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package main
+
+import (
+	cli "github.com/urfave/cli/v3"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/cdi"
+	"github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/config"
+	infoCLI "github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/info"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+)
+
+// getCommands returns the reduced set of commands supported on a Windows host.
+// These allow CDI specifications in a WSL2 distribution to be inspected and
+// transformed using the distribution's UNC path (e.g. \\wsl$\Ubuntu\etc\cdi).
+// Commands that require access to the NVIDIA driver (e.g. cdi generate) are
+// not supported since the NVML and DXCore bindings are only available on Linux.
+func getCommands(logger logger.Interface, configFilePath *string) []*cli.Command {
+	return []*cli.Command{
+		infoCLI.NewCommand(logger, configFilePath),
+		cdi.NewCommand(logger, configFilePath),
+		config.NewCommand(logger),
+	}
+}
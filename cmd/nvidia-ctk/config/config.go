@@ -27,7 +27,9 @@ import (
 	"github.com/urfave/cli/v3"
 
 	createdefault "github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/config/create-default"
+	enablecdiinruntime "github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/config/enable-cdi-in-runtime"
 	"github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/config/flags"
+	"github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/config/migrate"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
 )
@@ -69,6 +71,8 @@ func (m command) build() *cli.Command {
 		},
 		Commands: []*cli.Command{
 			createdefault.NewCommand(m.logger),
+			enablecdiinruntime.NewCommand(m.logger),
+			migrate.NewCommand(m.logger),
 		},
 		Flags: []cli.Flag{
 			&cli.StringFlag{
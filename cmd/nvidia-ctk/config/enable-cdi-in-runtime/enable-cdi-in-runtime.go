@@ -0,0 +1,167 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package enablecdiinruntime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/urfave/cli/v3"
+	"tags.cncf.io/container-device-interface/pkg/cdi"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/config/engine"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/config/engine/containerd"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/config/engine/crio"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/config/engine/docker"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/config/toml"
+)
+
+const (
+	defaultContainerdConfigFilePath = "/etc/containerd/config.toml"
+	defaultCrioConfigFilePath       = "/etc/crio/crio.conf"
+	defaultDockerConfigFilePath     = "/etc/docker/daemon.json"
+)
+
+type command struct {
+	logger logger.Interface
+}
+
+type options struct {
+	runtime        string
+	configFilePath string
+	cdiSpecDirs    []string
+	dryRun         bool
+}
+
+// NewCommand constructs a command to enable CDI in a container engine with
+// the specified logger.
+func NewCommand(logger logger.Interface) *cli.Command {
+	c := command{
+		logger: logger,
+	}
+	return c.build()
+}
+
+func (m command) build() *cli.Command {
+	opts := options{}
+
+	c := cli.Command{
+		Name:  "enable-cdi-in-runtime",
+		Usage: "Update the config of a container engine to enable native CDI support",
+		Before: func(ctx context.Context, cmd *cli.Command) (context.Context, error) {
+			return ctx, m.validateFlags(&opts)
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			return m.run(&opts)
+		},
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "runtime",
+				Usage:       "the target container engine [containerd, crio, docker]",
+				Value:       "docker",
+				Destination: &opts.runtime,
+			},
+			&cli.StringFlag{
+				Name:        "config",
+				Usage:       "path to the config file for the target container engine",
+				Destination: &opts.configFilePath,
+			},
+			&cli.StringSliceFlag{
+				Name:        "cdi-spec-dirs",
+				Aliases:     []string{"spec-dir"},
+				Usage:       "the directories in which the container engine looks for CDI specifications",
+				Value:       cdi.DefaultSpecDirs,
+				Destination: &opts.cdiSpecDirs,
+			},
+			&cli.BoolFlag{
+				Name:        "dry-run",
+				Usage:       "update the config in-memory and output it to STDOUT",
+				Destination: &opts.dryRun,
+			},
+		},
+	}
+
+	return &c
+}
+
+func (m command) validateFlags(opts *options) error {
+	if opts.configFilePath == "" {
+		switch opts.runtime {
+		case "containerd":
+			opts.configFilePath = defaultContainerdConfigFilePath
+		case "crio":
+			opts.configFilePath = defaultCrioConfigFilePath
+		case "docker":
+			opts.configFilePath = defaultDockerConfigFilePath
+		default:
+			return fmt.Errorf("unrecognized runtime '%v'", opts.runtime)
+		}
+	}
+	if len(opts.cdiSpecDirs) == 0 {
+		return errors.New("at least one CDI specification directory must be specified")
+	}
+	return nil
+}
+
+func (m command) run(opts *options) error {
+	cfg, err := m.loadConfig(opts)
+	if err != nil {
+		return fmt.Errorf("unable to load config for runtime %v: %w", opts.runtime, err)
+	}
+
+	cfg.EnableCDI()
+	cfg.SetCDISpecDirs(opts.cdiSpecDirs...)
+
+	outputPath := opts.configFilePath
+	if opts.dryRun {
+		outputPath = ""
+	}
+	if _, err := cfg.Save(outputPath); err != nil {
+		return fmt.Errorf("unable to flush config: %w", err)
+	}
+
+	if outputPath != "" {
+		m.logger.Infof("Enabled CDI in %v", outputPath)
+		m.logger.Infof("It is recommended that %v daemon be restarted.", opts.runtime)
+	}
+	return nil
+}
+
+func (m command) loadConfig(opts *options) (engine.Interface, error) {
+	switch opts.runtime {
+	case "containerd":
+		return containerd.New(
+			containerd.WithLogger(m.logger),
+			containerd.WithPath(opts.configFilePath),
+			containerd.WithConfigSource(toml.FromFile(opts.configFilePath)),
+		)
+	case "crio":
+		return crio.New(
+			crio.WithLogger(m.logger),
+			crio.WithPath(opts.configFilePath),
+			crio.WithConfigSource(toml.FromFile(opts.configFilePath)),
+		)
+	case "docker":
+		return docker.New(
+			docker.WithLogger(m.logger),
+			docker.WithPath(opts.configFilePath),
+		)
+	}
+	return nil, fmt.Errorf("unrecognized runtime '%v'", opts.runtime)
+}
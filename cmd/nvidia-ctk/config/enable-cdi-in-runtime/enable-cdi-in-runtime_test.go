@@ -0,0 +1,109 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package enablecdiinruntime
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnableCDIInRuntime(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	testCases := []struct {
+		description    string
+		runtime        string
+		config         string
+		cdiSpecDirs    []string
+		expectedConfig string
+	}{
+		{
+			description: "docker config is updated",
+			runtime:     "docker",
+			config:      `{"runtimes": {"nvidia": {"path": "nvidia-container-runtime", "args": []}}}`,
+			cdiSpecDirs: []string{"/etc/cdi", "/var/run/cdi"},
+			expectedConfig: `{
+    "cdi-spec-dirs": [
+        "/etc/cdi",
+        "/var/run/cdi"
+    ],
+    "features": {
+        "cdi": true
+    },
+    "runtimes": {
+        "nvidia": {
+            "args": [],
+            "path": "nvidia-container-runtime"
+        }
+    }
+}`,
+		},
+		{
+			description: "containerd config is updated",
+			runtime:     "containerd",
+			config: `version = 2
+`,
+			cdiSpecDirs: []string{"/etc/cdi"},
+			expectedConfig: `version = 2
+
+[plugins]
+
+  [plugins."io.containerd.grpc.v1.cri"]
+    cdi_spec_dirs = ["/etc/cdi"]
+    enable_cdi = true
+`,
+		},
+		{
+			description: "crio config is updated",
+			runtime:     "crio",
+			config: `[crio]
+`,
+			cdiSpecDirs: []string{"/etc/cdi", "/var/run/cdi"},
+			expectedConfig: `
+[crio]
+
+  [crio.runtime]
+    cdi_spec_dirs = ["/etc/cdi", "/var/run/cdi"]
+    enable_cdi = true
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			configFile := filepath.Join(t.TempDir(), "config")
+			require.NoError(t, os.WriteFile(configFile, []byte(tc.config), 0600))
+
+			opts := options{
+				runtime:        tc.runtime,
+				configFilePath: configFile,
+				cdiSpecDirs:    tc.cdiSpecDirs,
+			}
+			c := command{logger: logger}
+			require.NoError(t, c.validateFlags(&opts))
+			require.NoError(t, c.run(&opts))
+
+			updated, err := os.ReadFile(configFile)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedConfig, string(updated))
+		})
+	}
+}
@@ -0,0 +1,112 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package migrate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/config/flags"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+)
+
+type command struct {
+	logger logger.Interface
+}
+
+// NewCommand constructs a config migrate command with the specified logger
+func NewCommand(logger logger.Interface) *cli.Command {
+	c := command{
+		logger: logger,
+	}
+	return c.build()
+}
+
+// build creates the CLI command
+func (m command) build() *cli.Command {
+	opts := flags.Options{}
+
+	c := cli.Command{
+		Name:  "migrate",
+		Usage: "Update the NVIDIA Container Toolkit configuration file to address deprecation and migration warnings",
+		Before: func(ctx context.Context, cmd *cli.Command) (context.Context, error) {
+			return ctx, opts.Validate()
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			return m.run(&opts)
+		},
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "config-file",
+				Aliases:     []string{"config", "c"},
+				Usage:       "Specify the config file to migrate.",
+				Value:       config.GetConfigFilePath(),
+				Destination: &opts.Config,
+			},
+			&cli.BoolFlag{
+				Name:        "in-place",
+				Aliases:     []string{"i"},
+				Usage:       "Modify the config file in-place",
+				Destination: &opts.InPlace,
+			},
+			&cli.StringFlag{
+				Name:        "output",
+				Aliases:     []string{"o"},
+				Usage:       "Specify the output file to write to; If not specified, the output is written to stdout",
+				Destination: &opts.Output,
+			},
+		},
+	}
+
+	return &c
+}
+
+func (m command) run(opts *flags.Options) error {
+	cfgToml, err := config.New(
+		config.WithConfigFile(opts.Config),
+		config.WithRequired(true),
+	)
+	if err != nil {
+		return fmt.Errorf("unable to load config: %w", err)
+	}
+
+	migrated := cfgToml.Migrate()
+	for _, w := range migrated {
+		m.logger.Infof("Addressed %v", w)
+	}
+	if len(migrated) == 0 {
+		m.logger.Infof("No migrations required")
+	}
+
+	if err := opts.EnsureOutputFolder(); err != nil {
+		return fmt.Errorf("failed to create output directory: %v", err)
+	}
+	output, err := opts.CreateOutput()
+	if err != nil {
+		return fmt.Errorf("failed to open output file: %v", err)
+	}
+	defer output.Close()
+
+	if _, err := cfgToml.Save(output); err != nil {
+		return fmt.Errorf("failed to save config: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,209 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/urfave/cli/v3"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/modifier/cdi"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/nvcdi"
+)
+
+const (
+	phaseDiscovery    = "discovery"
+	phaseModification = "modification"
+)
+
+type command struct {
+	logger logger.Interface
+}
+
+type options struct {
+	iterations        int
+	devices           []string
+	driverRoot        string
+	nvidiaCDIHookPath string
+}
+
+// NewCommand constructs a benchmark command with the specified logger
+func NewCommand(logger logger.Interface) *cli.Command {
+	c := command{
+		logger: logger,
+	}
+	return c.build()
+}
+
+// build the benchmark command
+func (m command) build() *cli.Command {
+	opts := options{}
+
+	c := cli.Command{
+		Name:  "benchmark",
+		Usage: "Measure the latency of the discovery and modification phases of the NVIDIA Container Runtime",
+		Before: func(ctx context.Context, cmd *cli.Command) (context.Context, error) {
+			return ctx, m.validateFlags(&opts)
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			return m.run(&opts)
+		},
+		Flags: []cli.Flag{
+			&cli.IntFlag{
+				Name:        "iterations",
+				Aliases:     []string{"n"},
+				Usage:       "The number of times to run the pipeline",
+				Value:       10,
+				Destination: &opts.iterations,
+			},
+			&cli.StringSliceFlag{
+				Name:        "device",
+				Usage:       "The device(s) to request in the synthetic container. This can be specified multiple times.",
+				Value:       []string{"all"},
+				Destination: &opts.devices,
+			},
+			&cli.StringFlag{
+				Name:        "driver-root",
+				Usage:       "The path to the driver root. `DRIVER_ROOT`/dev is searched for NVIDIA device nodes.",
+				Value:       "/",
+				Destination: &opts.driverRoot,
+				Sources:     cli.EnvVars("NVIDIA_DRIVER_ROOT", "DRIVER_ROOT"),
+			},
+			&cli.StringFlag{
+				Name:        "nvidia-cdi-hook-path",
+				Usage:       "Specify the path to use for the nvidia-cdi-hook in the generated hooks.",
+				Value:       "/usr/bin/nvidia-cdi-hook",
+				Destination: &opts.nvidiaCDIHookPath,
+			},
+		},
+	}
+
+	return &c
+}
+
+func (m command) validateFlags(opts *options) error {
+	if opts.iterations < 1 {
+		return fmt.Errorf("the number of iterations must be positive; got %d", opts.iterations)
+	}
+	if len(opts.devices) == 0 {
+		return fmt.Errorf("at least one device must be specified")
+	}
+	return nil
+}
+
+func (m command) run(opts *options) error {
+	timings := make(phaseTimings)
+	for i := 0; i < opts.iterations; i++ {
+		if err := m.runOnce(opts, timings); err != nil {
+			return fmt.Errorf("iteration %d failed: %w", i, err)
+		}
+	}
+
+	return timings.write(os.Stdout)
+}
+
+// runOnce runs the discovery and modification pipeline against a synthetic
+// OCI spec and records the duration of each phase.
+func (m command) runOnce(opts *options, timings phaseTimings) error {
+	start := time.Now()
+	cdilib, err := nvcdi.New(
+		nvcdi.WithLogger(m.logger),
+		nvcdi.WithDriverRoot(opts.driverRoot),
+		nvcdi.WithNVIDIACDIHookPath(opts.nvidiaCDIHookPath),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to construct CDI library: %w", err)
+	}
+	cdiSpec, err := cdilib.GetSpec(opts.devices...)
+	if err != nil {
+		return fmt.Errorf("failed to generate CDI spec: %w", err)
+	}
+	timings.add(phaseDiscovery, time.Since(start))
+
+	start = time.Now()
+	modifier, err := cdi.New(
+		cdi.WithLogger(m.logger),
+		cdi.WithSpec(cdiSpec.Raw()),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to construct CDI modifier: %w", err)
+	}
+	if err := modifier.Modify(newSyntheticSpec()); err != nil {
+		return fmt.Errorf("failed to modify OCI spec: %w", err)
+	}
+	timings.add(phaseModification, time.Since(start))
+
+	return nil
+}
+
+// newSyntheticSpec returns a minimal OCI spec to which modifications can be
+// applied.
+func newSyntheticSpec() *specs.Spec {
+	return &specs.Spec{
+		Version: specs.Version,
+		Process: &specs.Process{
+			Args: []string{"nvidia-smi"},
+			Env:  []string{"NVIDIA_VISIBLE_DEVICES=all"},
+		},
+		Root: &specs.Root{
+			Path: "rootfs",
+		},
+		Linux: &specs.Linux{},
+	}
+}
+
+// phaseTimings records the durations of each iteration by phase.
+type phaseTimings map[string][]time.Duration
+
+func (t phaseTimings) add(phase string, d time.Duration) {
+	t[phase] = append(t[phase], d)
+}
+
+// write outputs the p50 and p95 latencies for each phase.
+func (t phaseTimings) write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PHASE\tITERATIONS\tP50\tP95")
+	for _, phase := range []string{phaseDiscovery, phaseModification} {
+		durations := t[phase]
+		fmt.Fprintf(tw, "%s\t%d\t%v\t%v\n", phase, len(durations), percentile(durations, 50), percentile(durations, 95))
+	}
+	return tw.Flush()
+}
+
+// percentile returns the p-th percentile of the specified durations using the
+// nearest-rank method.
+func percentile(durations []time.Duration, p int) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	sorted := slices.Clone(durations)
+	slices.Sort(sorted)
+
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
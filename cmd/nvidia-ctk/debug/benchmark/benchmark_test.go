@@ -0,0 +1,63 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package benchmark
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPercentile(t *testing.T) {
+	testCases := []struct {
+		description string
+		durations   []time.Duration
+		p           int
+		expected    time.Duration
+	}{
+		{
+			description: "no durations",
+			p:           50,
+			expected:    0,
+		},
+		{
+			description: "single duration",
+			durations:   []time.Duration{3},
+			p:           95,
+			expected:    3,
+		},
+		{
+			description: "p50 of unsorted durations",
+			durations:   []time.Duration{5, 1, 4, 2, 3},
+			p:           50,
+			expected:    3,
+		},
+		{
+			description: "p95 of ten durations",
+			durations:   []time.Duration{10, 9, 8, 7, 6, 5, 4, 3, 2, 1},
+			p:           95,
+			expected:    10,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			require.Equal(t, tc.expected, percentile(tc.durations, tc.p))
+		})
+	}
+}
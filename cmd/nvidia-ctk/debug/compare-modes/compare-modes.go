@@ -0,0 +1,335 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package comparemodes
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+	"tags.cncf.io/container-device-interface/specs-go"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/platform-support/tegra/csv"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/nvcdi"
+)
+
+const (
+	// modeLegacy refers to the discovery performed by the
+	// nvidia-container-cli in the legacy mode of the NVIDIA Container Runtime.
+	modeLegacy = "legacy"
+)
+
+type command struct {
+	logger logger.Interface
+}
+
+type options struct {
+	modes                  []string
+	devices                []string
+	driverRoot             string
+	nvidiaCDIHookPath      string
+	nvidiaContainerCLIPath string
+	csvFiles               []string
+}
+
+// NewCommand constructs a compare-modes command with the specified logger
+func NewCommand(logger logger.Interface) *cli.Command {
+	c := command{
+		logger: logger,
+	}
+	return c.build()
+}
+
+// build the compare-modes command
+func (m command) build() *cli.Command {
+	opts := options{}
+
+	c := cli.Command{
+		Name:  "compare-modes",
+		Usage: "Compare the device nodes and mounts that are discovered for a container in different modes",
+		Before: func(ctx context.Context, cmd *cli.Command) (context.Context, error) {
+			return ctx, m.validateFlags(&opts)
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			return m.run(ctx, &opts)
+		},
+		Flags: []cli.Flag{
+			&cli.StringSliceFlag{
+				Name:        "mode",
+				Usage:       "The modes to compare. The first mode is used as the reference for the other modes. One of legacy, nvml, or csv.",
+				Value:       []string{modeLegacy, string(nvcdi.ModeNvml), string(nvcdi.ModeCSV)},
+				Destination: &opts.modes,
+			},
+			&cli.StringSliceFlag{
+				Name:        "device",
+				Usage:       "The device(s) to request. This can be specified multiple times.",
+				Value:       []string{"all"},
+				Destination: &opts.devices,
+			},
+			&cli.StringFlag{
+				Name:        "driver-root",
+				Usage:       "The path to the driver root. `DRIVER_ROOT`/dev is searched for NVIDIA device nodes.",
+				Value:       "/",
+				Destination: &opts.driverRoot,
+				Sources:     cli.EnvVars("NVIDIA_DRIVER_ROOT", "DRIVER_ROOT"),
+			},
+			&cli.StringFlag{
+				Name:        "nvidia-cdi-hook-path",
+				Usage:       "Specify the path to use for the nvidia-cdi-hook in the generated hooks.",
+				Value:       "/usr/bin/nvidia-cdi-hook",
+				Destination: &opts.nvidiaCDIHookPath,
+			},
+			&cli.StringFlag{
+				Name:        "nvidia-container-cli-path",
+				Usage:       "Specify the path of the nvidia-container-cli used for the legacy mode.",
+				Value:       "nvidia-container-cli",
+				Destination: &opts.nvidiaContainerCLIPath,
+			},
+			&cli.StringSliceFlag{
+				Name:        "csv.file",
+				Usage:       "The path to the list of CSV files to use in CSV mode.",
+				Value:       csv.DefaultFileList(),
+				Destination: &opts.csvFiles,
+			},
+		},
+	}
+
+	return &c
+}
+
+func (m command) validateFlags(opts *options) error {
+	if len(opts.modes) < 2 {
+		return fmt.Errorf("at least two modes must be specified")
+	}
+	for _, mode := range opts.modes {
+		switch mode {
+		case modeLegacy, string(nvcdi.ModeNvml), string(nvcdi.ModeCSV):
+		default:
+			return fmt.Errorf("unsupported mode %q", mode)
+		}
+	}
+	if len(opts.devices) == 0 {
+		return fmt.Errorf("at least one device must be specified")
+	}
+	return nil
+}
+
+func (m command) run(ctx context.Context, opts *options) error {
+	discovered := make(map[string][]string)
+	failures := make(map[string]error)
+	for _, mode := range opts.modes {
+		contents, err := m.getContents(ctx, opts, mode)
+		if err != nil {
+			m.logger.Warningf("Failed to discover container contents in %v mode: %v", mode, err)
+			failures[mode] = err
+			continue
+		}
+		discovered[mode] = contents
+	}
+
+	if err := writeComparison(os.Stdout, opts.modes, discovered, failures); err != nil {
+		return err
+	}
+	if len(failures) > 0 {
+		var failed []string
+		for _, mode := range opts.modes {
+			if failures[mode] != nil {
+				failed = append(failed, mode)
+			}
+		}
+		return fmt.Errorf("failed to discover container contents in modes %v", failed)
+	}
+	return nil
+}
+
+// getContents returns the device nodes and mounts discovered in the
+// specified mode.
+func (m command) getContents(ctx context.Context, opts *options, mode string) ([]string, error) {
+	if mode == modeLegacy {
+		//nolint:gosec // The path of the nvidia-container-cli is specified by the user running the command.
+		cmd := exec.CommandContext(ctx, opts.nvidiaContainerCLIPath, "--root", opts.driverRoot, "list", "--device", strings.Join(opts.devices, ","))
+		output, err := cmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf("failed to run %v: %w", cmd, err)
+		}
+		return getLegacyContents(opts.driverRoot, output), nil
+	}
+
+	cdilib, err := nvcdi.New(
+		nvcdi.WithLogger(m.logger),
+		nvcdi.WithMode(mode),
+		nvcdi.WithDriverRoot(opts.driverRoot),
+		nvcdi.WithNVIDIACDIHookPath(opts.nvidiaCDIHookPath),
+		nvcdi.WithCSVFiles(opts.csvFiles),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct CDI library: %w", err)
+	}
+	cdiSpec, err := cdilib.GetSpec(opts.devices...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate CDI spec: %w", err)
+	}
+	return getCDIContents(cdiSpec.Raw()), nil
+}
+
+// getLegacyContents returns the device nodes and mounts from the output of
+// nvidia-container-cli list. Each line of the output is the host path of a
+// device node, a library, a binary, or an IPC socket. Since the CDI modes
+// report the paths in the container, the driver root is stripped from the
+// host paths.
+func getLegacyContents(driverRoot string, output []byte) []string {
+	var contents []string
+	for _, line := range strings.Split(string(output), "\n") {
+		path := strings.TrimSpace(line)
+		if path == "" {
+			continue
+		}
+		path = stripDriverRoot(driverRoot, path)
+		if strings.HasPrefix(path, "/dev/") {
+			contents = append(contents, "device "+path)
+			continue
+		}
+		contents = append(contents, "mount "+path)
+	}
+	return normalize(contents)
+}
+
+// stripDriverRoot returns the path in the container for the specified host
+// path below the driver root.
+func stripDriverRoot(driverRoot string, path string) string {
+	rel, err := filepath.Rel(driverRoot, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, "../") {
+		return path
+	}
+	return filepath.Join("/", rel)
+}
+
+// getCDIContents returns the device nodes, mounts, hooks, and envvars of the
+// common edits and the edits of all devices in the specified CDI spec.
+func getCDIContents(spec *specs.Spec) []string {
+	edits := []specs.ContainerEdits{spec.ContainerEdits}
+	for _, d := range spec.Devices {
+		edits = append(edits, d.ContainerEdits)
+	}
+
+	var contents []string
+	for _, e := range edits {
+		for _, dn := range e.DeviceNodes {
+			contents = append(contents, "device "+dn.Path)
+		}
+		for _, mount := range e.Mounts {
+			contents = append(contents, "mount "+mount.ContainerPath)
+		}
+		for _, hook := range e.Hooks {
+			contents = append(contents, fmt.Sprintf("hook %s %s", hook.HookName, strings.Join(hook.Args, " ")))
+		}
+		for _, env := range e.Env {
+			contents = append(contents, "env "+env)
+		}
+	}
+	return normalize(contents)
+}
+
+// withoutHooksAndEnv returns the specified contents without hooks and
+// envvars. These are not reported for the legacy mode, where the
+// nvidia-container-cli updates the container directly.
+func withoutHooksAndEnv(contents []string) []string {
+	var filtered []string
+	for _, c := range contents {
+		if strings.HasPrefix(c, "hook ") || strings.HasPrefix(c, "env ") {
+			continue
+		}
+		filtered = append(filtered, c)
+	}
+	return filtered
+}
+
+// normalize sorts the specified contents and removes duplicates.
+func normalize(contents []string) []string {
+	slices.Sort(contents)
+	return slices.Compact(contents)
+}
+
+// writeComparison writes the differences between the contents discovered in
+// the first mode and each of the other modes to the specified writer. If
+// discovery failed for a mode, the failure is reported instead. Hooks and
+// envvars are not compared if one of the modes is the legacy mode.
+func writeComparison(w io.Writer, modes []string, discovered map[string][]string, failures map[string]error) error {
+	reference := modes[0]
+	for _, mode := range modes[1:] {
+		if _, err := fmt.Fprintf(w, "--- %s\n+++ %s\n", reference, mode); err != nil {
+			return err
+		}
+		if failures[reference] != nil || failures[mode] != nil {
+			for _, m := range []string{reference, mode} {
+				if failures[m] == nil {
+					continue
+				}
+				if _, err := fmt.Fprintf(w, " (%s failed: %v)\n", m, failures[m]); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		a, b := discovered[reference], discovered[mode]
+		if reference == modeLegacy || mode == modeLegacy {
+			a, b = withoutHooksAndEnv(a), withoutHooksAndEnv(b)
+		}
+		removed, added := diff(a, b)
+		if len(removed) == 0 && len(added) == 0 {
+			if _, err := fmt.Fprintln(w, " (no differences)"); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, c := range removed {
+			if _, err := fmt.Fprintf(w, "-%s\n", c); err != nil {
+				return err
+			}
+		}
+		for _, c := range added {
+			if _, err := fmt.Fprintf(w, "+%s\n", c); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// diff returns the entries that are only in a and the entries that are only
+// in b. Both inputs are expected to be normalized.
+func diff(a []string, b []string) ([]string, []string) {
+	var removed, added []string
+	for _, c := range a {
+		if _, found := slices.BinarySearch(b, c); !found {
+			removed = append(removed, c)
+		}
+	}
+	for _, c := range b {
+		if _, found := slices.BinarySearch(a, c); !found {
+			added = append(added, c)
+		}
+	}
+	return removed, added
+}
@@ -0,0 +1,134 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package comparemodes
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"tags.cncf.io/container-device-interface/specs-go"
+)
+
+func TestGetLegacyContents(t *testing.T) {
+	expected := []string{
+		"device /dev/nvidia0",
+		"device /dev/nvidiactl",
+		"mount /run/nvidia-persistenced/socket",
+		"mount /usr/bin/nvidia-smi",
+		"mount /usr/lib/x86_64-linux-gnu/libcuda.so.570.00",
+	}
+
+	testCases := []struct {
+		description string
+		driverRoot  string
+		output      string
+	}{
+		{
+			description: "host driver root",
+			driverRoot:  "/",
+			output: `/dev/nvidiactl
+/dev/nvidia0
+/usr/bin/nvidia-smi
+/usr/lib/x86_64-linux-gnu/libcuda.so.570.00
+/run/nvidia-persistenced/socket
+
+`,
+		},
+		{
+			description: "driver root is stripped",
+			driverRoot:  "/run/nvidia/driver",
+			output: `/run/nvidia/driver/dev/nvidiactl
+/run/nvidia/driver/dev/nvidia0
+/run/nvidia/driver/usr/bin/nvidia-smi
+/run/nvidia/driver/usr/lib/x86_64-linux-gnu/libcuda.so.570.00
+/run/nvidia-persistenced/socket
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			require.EqualValues(t, expected, getLegacyContents(tc.driverRoot, []byte(tc.output)))
+		})
+	}
+}
+
+func TestGetCDIContents(t *testing.T) {
+	spec := &specs.Spec{
+		ContainerEdits: specs.ContainerEdits{
+			DeviceNodes: []*specs.DeviceNode{{Path: "/dev/nvidiactl"}},
+			Mounts: []*specs.Mount{
+				{HostPath: "/driver-root/usr/bin/nvidia-smi", ContainerPath: "/usr/bin/nvidia-smi"},
+			},
+			Hooks: []*specs.Hook{{HookName: "createContainer", Path: "/usr/bin/nvidia-cdi-hook", Args: []string{"nvidia-cdi-hook", "update-ldcache"}}},
+			Env:   []string{"NVIDIA_CTK_LIBCUDA_DIR=/usr/lib64"},
+		},
+		Devices: []specs.Device{
+			{
+				Name:           "0",
+				ContainerEdits: specs.ContainerEdits{DeviceNodes: []*specs.DeviceNode{{Path: "/dev/nvidia0"}}},
+			},
+			{
+				Name:           "all",
+				ContainerEdits: specs.ContainerEdits{DeviceNodes: []*specs.DeviceNode{{Path: "/dev/nvidia0"}}},
+			},
+		},
+	}
+	require.EqualValues(t, []string{
+		"device /dev/nvidia0",
+		"device /dev/nvidiactl",
+		"env NVIDIA_CTK_LIBCUDA_DIR=/usr/lib64",
+		"hook createContainer nvidia-cdi-hook update-ldcache",
+		"mount /usr/bin/nvidia-smi",
+	}, getCDIContents(spec))
+}
+
+func TestWriteComparison(t *testing.T) {
+	discovered := map[string][]string{
+		"legacy": {"device /dev/nvidia0", "mount /usr/bin/nvidia-smi"},
+		"nvml":   {"device /dev/nvidia0", "hook createContainer nvidia-cdi-hook update-ldcache", "mount /usr/bin/nvidia-debugdump", "mount /usr/bin/nvidia-smi"},
+		"csv":    {"device /dev/nvidia0", "mount /usr/bin/nvidia-smi"},
+	}
+
+	var b bytes.Buffer
+	require.NoError(t, writeComparison(&b, []string{"nvml", "legacy", "csv"}, discovered, nil))
+	require.Equal(t, `--- nvml
++++ legacy
+-mount /usr/bin/nvidia-debugdump
+--- nvml
++++ csv
+-hook createContainer nvidia-cdi-hook update-ldcache
+-mount /usr/bin/nvidia-debugdump
+`, b.String())
+
+	b.Reset()
+	require.NoError(t, writeComparison(&b, []string{"legacy", "csv"}, discovered, nil))
+	require.Equal(t, "--- legacy\n+++ csv\n (no differences)\n", b.String())
+
+	b.Reset()
+	failures := map[string]error{"csv": errors.New("no CSV files found")}
+	require.NoError(t, writeComparison(&b, []string{"legacy", "csv", "nvml"}, discovered, failures))
+	require.Equal(t, `--- legacy
++++ csv
+ (csv failed: no CSV files found)
+--- legacy
++++ nvml
++mount /usr/bin/nvidia-debugdump
+`, b.String())
+}
@@ -39,10 +39,18 @@ func NewCommand(logger logger.Interface) *cli.Command {
 
 // build
 func (m hookCommand) build() *cli.Command {
+	var sandbox bool
+
 	// Create the 'hook' subcommand
 	hook := cli.Command{
 		Name:  "hook",
 		Usage: "A collection of hooks that may be injected into an OCI spec",
+		Before: func(ctx context.Context, cmd *cli.Command) (context.Context, error) {
+			if sandbox {
+				return commands.RunSandboxed(ctx, m.logger, cmd)
+			}
+			return ctx, nil
+		},
 		// We set the default action for the `hook` subcommand to issue a
 		// warning and exit with no error.
 		// This means that if an unsupported hook is run, a container will not fail
@@ -55,6 +63,9 @@ func (m hookCommand) build() *cli.Command {
 			return nil
 		},
 		Commands: commands.New(m.logger),
+		Flags: []cli.Flag{
+			commands.SandboxFlag(&sandbox),
+		},
 	}
 
 	return &hook
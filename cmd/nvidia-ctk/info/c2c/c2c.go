@@ -0,0 +1,135 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package c2c
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/urfave/cli/v3"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+)
+
+type command struct {
+	logger logger.Interface
+}
+
+type options struct {
+	devRoot string
+}
+
+// getC2cModeInfo returns the C2C mode info for the specified device. This is
+// overridden in tests since the returned handler cannot be mocked.
+var getC2cModeInfo = func(device nvml.Device) (nvml.C2cModeInfo_v1, nvml.Return) {
+	return device.GetC2cModeInfoV().V1()
+}
+
+// NewCommand constructs an info c2c command with the specified logger
+func NewCommand(logger logger.Interface) *cli.Command {
+	c := command{
+		logger: logger,
+	}
+	return c.build()
+}
+
+// build creates the CLI command
+func (m command) build() *cli.Command {
+	opts := options{}
+
+	c := cli.Command{
+		Name:  "c2c",
+		Usage: "Show the C2C topology and extended GPU memory (EGM) devices on Grace Hopper systems",
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			return m.run(os.Stdout, nvml.New(), &opts)
+		},
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "dev-root",
+				Usage:       "Specify the root where the EGM device nodes are located.",
+				Value:       "/",
+				Destination: &opts.devRoot,
+			},
+		},
+	}
+
+	return &c
+}
+
+// run outputs whether the chip-to-chip (C2C) interconnect is enabled for each
+// GPU as well as the NUMA node of the GPU memory.
+func (m command) run(w io.Writer, nvmllib nvml.Interface, opts *options) error {
+	if ret := nvmllib.Init(); ret != nvml.SUCCESS {
+		return fmt.Errorf("failed to initialize NVML: %v", ret)
+	}
+	defer func() {
+		_ = nvmllib.Shutdown()
+	}()
+
+	count, ret := nvmllib.DeviceGetCount()
+	if ret != nvml.SUCCESS {
+		return fmt.Errorf("failed to get device count: %v", ret)
+	}
+	for i := 0; i < count; i++ {
+		device, ret := nvmllib.DeviceGetHandleByIndex(i)
+		if ret != nvml.SUCCESS {
+			return fmt.Errorf("failed to get device %d: %v", i, ret)
+		}
+		uuid, ret := device.GetUUID()
+		if ret != nvml.SUCCESS {
+			return fmt.Errorf("failed to get UUID of device %d: %v", i, ret)
+		}
+
+		fmt.Fprintf(w, "GPU %d: %v\n", i, uuid)
+		c2cModeInfo, ret := getC2cModeInfo(device)
+		switch ret {
+		case nvml.SUCCESS:
+			fmt.Fprintf(w, "  c2c enabled: %v\n", c2cModeInfo.IsC2cEnabled != 0)
+		default:
+			m.logger.Debugf("Failed to get C2C mode info for device %d: %v", i, ret)
+			fmt.Fprintf(w, "  c2c enabled: unknown\n")
+		}
+		numaNode, ret := device.GetNumaNodeId()
+		switch ret {
+		case nvml.SUCCESS:
+			fmt.Fprintf(w, "  memory numa node: %d\n", numaNode)
+		default:
+			m.logger.Debugf("Failed to get NUMA node for device %d: %v", i, ret)
+			fmt.Fprintf(w, "  memory numa node: none\n")
+		}
+	}
+
+	egmDevices, err := filepath.Glob(filepath.Join(opts.devRoot, "dev", "egm*"))
+	if err != nil {
+		return fmt.Errorf("failed to locate EGM devices: %w", err)
+	}
+	if len(egmDevices) == 0 {
+		fmt.Fprintf(w, "egm devices: none\n")
+	}
+	for _, egmDevice := range egmDevices {
+		path, err := filepath.Rel(opts.devRoot, egmDevice)
+		if err != nil {
+			return fmt.Errorf("failed to get path of EGM device %v: %w", egmDevice, err)
+		}
+		fmt.Fprintf(w, "egm device: /%v\n", path)
+	}
+	return nil
+}
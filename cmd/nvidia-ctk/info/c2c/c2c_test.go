@@ -0,0 +1,73 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package c2c
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/NVIDIA/go-nvml/pkg/nvml/mock"
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	devRoot := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(devRoot, "dev"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(devRoot, "dev/egm4"), nil, 0644))
+
+	getC2cModeInfo = func(device nvml.Device) (nvml.C2cModeInfo_v1, nvml.Return) {
+		return nvml.C2cModeInfo_v1{IsC2cEnabled: 1}, nvml.SUCCESS
+	}
+
+	device := &mock.Device{
+		GetUUIDFunc: func() (string, nvml.Return) {
+			return "GPU-0", nvml.SUCCESS
+		},
+		GetNumaNodeIdFunc: func() (int, nvml.Return) {
+			return 1, nvml.SUCCESS
+		},
+	}
+	nvmllib := &mock.Interface{
+		InitFunc: func() nvml.Return {
+			return nvml.SUCCESS
+		},
+		ShutdownFunc: func() nvml.Return {
+			return nvml.SUCCESS
+		},
+		DeviceGetCountFunc: func() (int, nvml.Return) {
+			return 1, nvml.SUCCESS
+		},
+		DeviceGetHandleByIndexFunc: func(n int) (nvml.Device, nvml.Return) {
+			return device, nvml.SUCCESS
+		},
+	}
+
+	c := command{logger: logger}
+
+	var buf bytes.Buffer
+	require.NoError(t, c.run(&buf, nvmllib, &options{devRoot: devRoot}))
+	require.Equal(t,
+		"GPU 0: GPU-0\n  c2c enabled: true\n  memory numa node: 1\negm device: /dev/egm4\n",
+		buf.String(),
+	)
+}
@@ -0,0 +1,165 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package csv
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config/image"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/platform-support/tegra"
+	tegracsv "github.com/NVIDIA/nvidia-container-toolkit/internal/platform-support/tegra/csv"
+)
+
+type command struct {
+	logger logger.Interface
+}
+
+type options struct {
+	files              []string
+	ignorePatterns     []string
+	driverRoot         string
+	devRoot            string
+	librarySearchPaths []string
+	driverCapabilities string
+	explain            bool
+}
+
+// NewCommand constructs an info csv command with the specified logger
+func NewCommand(logger logger.Interface) *cli.Command {
+	c := command{
+		logger: logger,
+	}
+	return c.build()
+}
+
+// build creates the CLI command
+func (m command) build() *cli.Command {
+	opts := options{}
+
+	c := cli.Command{
+		Name:  "csv",
+		Usage: "Show how the entries in the CSV files used on Tegra-based systems are resolved",
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			return m.run(os.Stdout, &opts)
+		},
+		Flags: []cli.Flag{
+			&cli.StringSliceFlag{
+				Name:        "csv.file",
+				Aliases:     []string{"file"},
+				Usage:       "The path to the CSV files to process.",
+				Value:       tegracsv.DefaultFileList(),
+				Destination: &opts.files,
+			},
+			&cli.StringSliceFlag{
+				Name:        "csv.ignore-pattern",
+				Usage:       "specify a pattern the CSV mount specifications.",
+				Destination: &opts.ignorePatterns,
+			},
+			&cli.StringFlag{
+				Name:        "driver-root",
+				Usage:       "Specify the root at which the entries in the CSV files are resolved.",
+				Value:       "/",
+				Destination: &opts.driverRoot,
+				Sources:     cli.EnvVars("NVIDIA_CTK_DRIVER_ROOT"),
+			},
+			&cli.StringFlag{
+				Name:        "dev-root",
+				Usage:       "Specify the root where `/dev` is located. If this is not specified, the driver-root is assumed.",
+				Destination: &opts.devRoot,
+				Sources:     cli.EnvVars("NVIDIA_CTK_DEV_ROOT"),
+			},
+			&cli.StringSliceFlag{
+				Name:        "library-search-path",
+				Usage:       "Specify the path to search for libraries.",
+				Destination: &opts.librarySearchPaths,
+			},
+			&cli.StringFlag{
+				Name:        "driver-capabilities",
+				Usage:       "Only consider the entries required for the specified comma-separated driver capabilities. If this is not specified, all entries are considered.",
+				Destination: &opts.driverCapabilities,
+			},
+			&cli.BoolFlag{
+				Name:        "explain",
+				Usage:       "Show how each entry in the CSV files was resolved or the reason it was skipped.",
+				Destination: &opts.explain,
+			},
+		},
+	}
+
+	return &c
+}
+
+func (m command) run(w io.Writer, opts *options) error {
+	var driverCapabilities image.DriverCapabilities
+	if opts.driverCapabilities != "" {
+		driverCapabilities = image.NewDriverCapabilities(opts.driverCapabilities)
+	}
+
+	explanations := tegra.Explain(
+		tegra.WithLogger(m.logger),
+		tegra.WithDriverRoot(opts.driverRoot),
+		tegra.WithDevRoot(opts.devRoot),
+		tegra.WithCSVFiles(opts.files),
+		tegra.WithLibrarySearchPaths(opts.librarySearchPaths...),
+		tegra.WithIngorePatterns(opts.ignorePatterns...),
+		tegra.WithDriverCapabilities(driverCapabilities),
+	)
+
+	for _, explanation := range explanations {
+		fmt.Fprintf(w, "%v:\n", explanation.Filename)
+		if explanation.Err != nil {
+			fmt.Fprintf(w, "  error: %v\n", explanation.Err)
+			continue
+		}
+		if !opts.explain {
+			fmt.Fprintf(w, "  %v\n", summarize(explanation.Entries))
+			continue
+		}
+		for _, entry := range explanation.Entries {
+			fmt.Fprintf(w, "  %v\n", describe(entry))
+		}
+	}
+	return nil
+}
+
+// summarize returns the number of entries for each result.
+func summarize(entries []tegra.EntryExplanation) string {
+	counts := make(map[tegra.EntryResult]int)
+	for _, entry := range entries {
+		counts[entry.Result]++
+	}
+	var parts []string
+	for _, result := range []tegra.EntryResult{tegra.EntryDevice, tegra.EntryMount, tegra.EntrySymlink, tegra.EntrySkipped} {
+		parts = append(parts, fmt.Sprintf("%v: %d", result, counts[result]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// describe returns a single-line description of how an entry was processed.
+func describe(entry tegra.EntryExplanation) string {
+	if entry.Result == tegra.EntrySkipped {
+		return fmt.Sprintf("%v -> skipped: %v", strings.TrimSpace(entry.Line), entry.Reason)
+	}
+	return fmt.Sprintf("%v -> %v: %v", strings.TrimSpace(entry.Line), entry.Result, strings.Join(entry.Resolved, ", "))
+}
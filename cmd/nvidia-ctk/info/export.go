@@ -0,0 +1,90 @@
+//go:build !windows
+
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package info
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/modifier"
+)
+
+type options struct {
+	export string
+}
+
+func (m command) flags(opts *options) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name: "export",
+			Usage: "Export a discovery manifest to the specified file (or - for stdout). " +
+				"The NVIDIA Container Runtime can be configured to use this manifest instead of probing the system when creating containers.",
+			Destination: &opts.export,
+		},
+	}
+}
+
+func (m command) run(cmd *cli.Command, opts *options) error {
+	if opts.export == "" {
+		return cli.ShowSubcommandHelp(cmd)
+	}
+
+	cfg, err := m.loadConfig()
+	if err != nil {
+		return err
+	}
+
+	discoveryManifest, err := modifier.NewDiscoveryManifest(m.logger, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to generate discovery manifest: %w", err)
+	}
+
+	var output io.Writer = os.Stdout
+	if opts.export != "-" {
+		f, err := os.Create(opts.export)
+		if err != nil {
+			return fmt.Errorf("failed to create discovery manifest file: %w", err)
+		}
+		defer f.Close()
+		output = f
+	}
+	if err := discoveryManifest.Write(output); err != nil {
+		return fmt.Errorf("failed to write discovery manifest: %w", err)
+	}
+	return nil
+}
+
+// loadConfig loads the toolkit config from the specified or default path.
+func (m command) loadConfig() (*config.Config, error) {
+	configFilePath := config.GetConfigFilePath()
+	if m.configFilePath != nil && *m.configFilePath != "" {
+		configFilePath = *m.configFilePath
+	}
+	configToml, err := config.New(
+		config.WithConfigFile(configFilePath),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	return configToml.Config()
+}
@@ -0,0 +1,33 @@
+//go:build windows
+
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package info
+
+import (
+	"github.com/urfave/cli/v3"
+)
+
+type options struct{}
+
+func (m command) flags(_ *options) []cli.Flag {
+	return nil
+}
+
+func (m command) run(cmd *cli.Command, _ *options) error {
+	return cli.ShowSubcommandHelp(cmd)
+}
@@ -17,29 +17,40 @@
 package info
 
 import (
+	"context"
+
 	"github.com/urfave/cli/v3"
 
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
 )
 
 type command struct {
-	logger logger.Interface
+	logger         logger.Interface
+	configFilePath *string
 }
 
 // NewCommand constructs an info command with the specified logger
-func NewCommand(logger logger.Interface) *cli.Command {
+func NewCommand(logger logger.Interface, configFilePath *string) *cli.Command {
 	c := command{
-		logger: logger,
+		logger:         logger,
+		configFilePath: configFilePath,
 	}
 	return c.build()
 }
 
 // build
 func (m command) build() *cli.Command {
+	opts := options{}
+
 	// Create the 'info' command
 	info := cli.Command{
 		Name:  "info",
 		Usage: "Provide information about the system",
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			return m.run(cmd, &opts)
+		},
+		Flags:    m.flags(&opts),
+		Commands: m.subcommands(),
 	}
 
 	return &info
@@ -0,0 +1,83 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package nvpmodel
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/platform-support/tegra/nvpmodel"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/requirements"
+)
+
+type command struct {
+	logger logger.Interface
+}
+
+type options struct {
+	root string
+}
+
+// NewCommand constructs an info nvpmodel command with the specified logger
+func NewCommand(logger logger.Interface) *cli.Command {
+	c := command{
+		logger: logger,
+	}
+	return c.build()
+}
+
+// build creates the CLI command
+func (m command) build() *cli.Command {
+	opts := options{}
+
+	c := cli.Command{
+		Name:  "nvpmodel",
+		Usage: "Show the current nvpmodel power mode on Tegra-based systems",
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			return m.run(os.Stdout, &opts)
+		},
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "root",
+				Usage:       "Specify the root at which the nvpmodel config and status files are located.",
+				Value:       "/",
+				Destination: &opts.root,
+			},
+		},
+	}
+
+	return &c
+}
+
+// run outputs the current power mode as well as the property that can be
+// used to assert it using NVIDIA_REQUIRE_* envvars.
+func (m command) run(w io.Writer, opts *options) error {
+	powerMode, err := nvpmodel.GetPowerMode(opts.root)
+	if err != nil {
+		return fmt.Errorf("failed to get power mode: %w", err)
+	}
+
+	fmt.Fprintf(w, "power mode: %v\n", powerMode.Name)
+	fmt.Fprintf(w, "id: %d\n", powerMode.ID)
+	fmt.Fprintf(w, "requirement property: %v=%v\n", requirements.NVPMODEL, powerMode.Name)
+	return nil
+}
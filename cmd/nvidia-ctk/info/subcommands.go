@@ -0,0 +1,37 @@
+//go:build !windows
+
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package info
+
+import (
+	"github.com/urfave/cli/v3"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/info/c2c"
+	"github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/info/csv"
+	"github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/info/nvpmodel"
+	"github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/info/topology"
+)
+
+func (m command) subcommands() []*cli.Command {
+	return []*cli.Command{
+		c2c.NewCommand(m.logger),
+		csv.NewCommand(m.logger),
+		nvpmodel.NewCommand(m.logger),
+		topology.NewCommand(m.logger),
+	}
+}
@@ -0,0 +1,36 @@
+//go:build windows
+
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package info
+
+import (
+	"github.com/urfave/cli/v3"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/info/csv"
+	"github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/info/nvpmodel"
+)
+
+// subcommands returns the info subcommands supported on a Windows host.
+// The c2c and topology subcommands are not supported since these query the
+// GPUs using NVML.
+func (m command) subcommands() []*cli.Command {
+	return []*cli.Command{
+		csv.NewCommand(m.logger),
+		nvpmodel.NewCommand(m.logger),
+	}
+}
@@ -0,0 +1,316 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package topology
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/bits"
+	"os"
+	"strings"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/urfave/cli/v3"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/info/proc"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+)
+
+const (
+	formatText = "text"
+	formatJSON = "json"
+)
+
+// numaNodeSetSize is the size of the NUMA node set (in units of unsigned
+// longs) that is requested when querying the memory affinity of a GPU.
+const numaNodeSetSize = 4
+
+type command struct {
+	logger logger.Interface
+}
+
+type options struct {
+	format string
+}
+
+// Topology describes the interconnect topology of the GPUs on a node.
+type Topology struct {
+	GPUs  []GPU  `json:"gpus"`
+	Links []Link `json:"links"`
+}
+
+// A GPU describes a single GPU and its NUMA affinity.
+type GPU struct {
+	Index    int    `json:"index"`
+	UUID     string `json:"uuid"`
+	PCIBusID string `json:"pciBusID"`
+	// NUMANodes lists the NUMA nodes that the GPU has memory affinity with.
+	NUMANodes []int `json:"numaNodes,omitempty"`
+	// NVSwitchLinks is the number of active NVLinks that connect the GPU to
+	// a device that is not one of the listed GPUs, such as an NVSwitch.
+	NVSwitchLinks int `json:"nvswitchLinks,omitempty"`
+}
+
+// A Link describes how a pair of GPUs is connected.
+type Link struct {
+	GPUs [2]int `json:"gpus"`
+	// PCIe is the closest common ancestor of the GPUs in the PCIe topology.
+	// One of internal, single, multiple, hostbridge, node, or system.
+	PCIe string `json:"pcie"`
+	// NVLinks is the number of active NVLinks that directly connect the GPUs.
+	NVLinks int `json:"nvlinks,omitempty"`
+}
+
+// NewCommand constructs an info topology command with the specified logger
+func NewCommand(logger logger.Interface) *cli.Command {
+	c := command{
+		logger: logger,
+	}
+	return c.build()
+}
+
+// build creates the CLI command
+func (m command) build() *cli.Command {
+	opts := options{}
+
+	c := cli.Command{
+		Name:  "topology",
+		Usage: "Show the NVLink, PCIe, and NUMA topology of the GPUs on the system",
+		Before: func(ctx context.Context, cmd *cli.Command) (context.Context, error) {
+			return ctx, m.validateFlags(&opts)
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			return m.run(os.Stdout, nvml.New(), &opts)
+		},
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "format",
+				Usage:       "The output format. One of [text | json]",
+				Value:       formatText,
+				Destination: &opts.format,
+			},
+		},
+	}
+
+	return &c
+}
+
+func (m command) validateFlags(opts *options) error {
+	switch opts.format {
+	case formatText, formatJSON:
+		return nil
+	}
+	return fmt.Errorf("unsupported format: %q", opts.format)
+}
+
+// run queries the GPU topology using NVML and writes it in the requested
+// format.
+func (m command) run(w io.Writer, nvmllib nvml.Interface, opts *options) error {
+	if ret := nvmllib.Init(); ret != nvml.SUCCESS {
+		return fmt.Errorf("failed to initialize NVML: %v", ret)
+	}
+	defer func() {
+		_ = nvmllib.Shutdown()
+	}()
+
+	topology, err := m.getTopology(nvmllib)
+	if err != nil {
+		return err
+	}
+
+	switch opts.format {
+	case formatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(topology)
+	default:
+		topology.writeText(w)
+		return nil
+	}
+}
+
+func (m command) getTopology(nvmllib nvml.Interface) (*Topology, error) {
+	count, ret := nvmllib.DeviceGetCount()
+	if ret != nvml.SUCCESS {
+		return nil, fmt.Errorf("failed to get device count: %v", ret)
+	}
+
+	var devices []nvml.Device
+	topology := &Topology{
+		GPUs:  []GPU{},
+		Links: []Link{},
+	}
+	gpuIndexByBusID := make(map[string]int)
+	for i := 0; i < count; i++ {
+		device, ret := nvmllib.DeviceGetHandleByIndex(i)
+		if ret != nvml.SUCCESS {
+			return nil, fmt.Errorf("failed to get device %d: %v", i, ret)
+		}
+		uuid, ret := device.GetUUID()
+		if ret != nvml.SUCCESS {
+			return nil, fmt.Errorf("failed to get UUID of device %d: %v", i, ret)
+		}
+		pciInfo, ret := device.GetPciInfo()
+		if ret != nvml.SUCCESS {
+			return nil, fmt.Errorf("failed to get PCI info of device %d: %v", i, ret)
+		}
+		busID := busIDToString(pciInfo.BusId)
+
+		devices = append(devices, device)
+		gpuIndexByBusID[busID] = i
+		topology.GPUs = append(topology.GPUs, GPU{
+			Index:     i,
+			UUID:      uuid,
+			PCIBusID:  busID,
+			NUMANodes: m.getNUMANodes(i, device),
+		})
+	}
+
+	nvlinks := make(map[[2]int]int)
+	for i, device := range devices {
+		for _, remoteBusID := range m.getNVLinkPeers(i, device) {
+			j, ok := gpuIndexByBusID[remoteBusID]
+			if !ok {
+				topology.GPUs[i].NVSwitchLinks++
+				continue
+			}
+			if i < j {
+				nvlinks[[2]int{i, j}]++
+			}
+		}
+	}
+
+	for i := range devices {
+		for j := i + 1; j < len(devices); j++ {
+			level, ret := devices[i].GetTopologyCommonAncestor(devices[j])
+			if ret != nvml.SUCCESS {
+				return nil, fmt.Errorf("failed to get common ancestor of devices %d and %d: %v", i, j, ret)
+			}
+			topology.Links = append(topology.Links, Link{
+				GPUs:    [2]int{i, j},
+				PCIe:    topologyLevelName(level),
+				NVLinks: nvlinks[[2]int{i, j}],
+			})
+		}
+	}
+
+	return topology, nil
+}
+
+// getNUMANodes returns the NUMA nodes that the specified device has memory
+// affinity with. If the affinity cannot be determined, nil is returned.
+func (m command) getNUMANodes(index int, device nvml.Device) []int {
+	affinity, ret := device.GetMemoryAffinity(numaNodeSetSize, nvml.AFFINITY_SCOPE_NODE)
+	if ret != nvml.SUCCESS {
+		m.logger.Debugf("Failed to get memory affinity for device %d: %v", index, ret)
+		return nil
+	}
+	var nodes []int
+	for i, mask := range affinity {
+		for mask != 0 {
+			bit := bits.TrailingZeros(mask)
+			nodes = append(nodes, i*bits.UintSize+bit)
+			mask &^= 1 << bit
+		}
+	}
+	return nodes
+}
+
+// getNVLinkPeers returns the PCI bus IDs of the remote end of each active
+// NVLink of the specified device. A bus ID is repeated for each link to the
+// same remote device.
+func (m command) getNVLinkPeers(index int, device nvml.Device) []string {
+	var peers []string
+	for link := 0; link < nvml.NVLINK_MAX_LINKS; link++ {
+		state, ret := device.GetNvLinkState(link)
+		if ret != nvml.SUCCESS {
+			if ret != nvml.ERROR_NOT_SUPPORTED && ret != nvml.ERROR_INVALID_ARGUMENT {
+				m.logger.Debugf("Failed to get state of NVLink %d for device %d: %v", link, index, ret)
+			}
+			continue
+		}
+		if state != nvml.FEATURE_ENABLED {
+			continue
+		}
+		remote, ret := device.GetNvLinkRemotePciInfo(link)
+		if ret != nvml.SUCCESS {
+			m.logger.Debugf("Failed to get remote PCI info of NVLink %d for device %d: %v", link, index, ret)
+			continue
+		}
+		peers = append(peers, busIDToString(remote.BusId))
+	}
+	return peers
+}
+
+// writeText writes a human-readable representation of the topology.
+func (t *Topology) writeText(w io.Writer) {
+	for _, gpu := range t.GPUs {
+		fmt.Fprintf(w, "GPU %d: %v\n", gpu.Index, gpu.UUID)
+		fmt.Fprintf(w, "  pci bus id: %v\n", gpu.PCIBusID)
+		numaNodes := "unknown"
+		if len(gpu.NUMANodes) > 0 {
+			var nodes []string
+			for _, node := range gpu.NUMANodes {
+				nodes = append(nodes, fmt.Sprintf("%d", node))
+			}
+			numaNodes = strings.Join(nodes, ",")
+		}
+		fmt.Fprintf(w, "  numa nodes: %v\n", numaNodes)
+		if gpu.NVSwitchLinks > 0 {
+			fmt.Fprintf(w, "  nvswitch links: %d\n", gpu.NVSwitchLinks)
+		}
+	}
+	for _, link := range t.Links {
+		fmt.Fprintf(w, "GPU %d <-> GPU %d: pcie=%v nvlinks=%d\n", link.GPUs[0], link.GPUs[1], link.PCIe, link.NVLinks)
+	}
+}
+
+// topologyLevelName returns the name used for the specified topology level in
+// the output.
+func topologyLevelName(level nvml.GpuTopologyLevel) string {
+	switch level {
+	case nvml.TOPOLOGY_INTERNAL:
+		return "internal"
+	case nvml.TOPOLOGY_SINGLE:
+		return "single"
+	case nvml.TOPOLOGY_MULTIPLE:
+		return "multiple"
+	case nvml.TOPOLOGY_HOSTBRIDGE:
+		return "hostbridge"
+	case nvml.TOPOLOGY_NODE:
+		return "node"
+	case nvml.TOPOLOGY_SYSTEM:
+		return "system"
+	default:
+		return "unknown"
+	}
+}
+
+// busIDToString converts the null-terminated bus ID returned by NVML to its
+// normalized string representation.
+func busIDToString(busID [32]int8) string {
+	var b strings.Builder
+	for _, c := range busID {
+		if c == 0 {
+			break
+		}
+		b.WriteByte(byte(c))
+	}
+	return proc.NormalizePCIBusID(b.String())
+}
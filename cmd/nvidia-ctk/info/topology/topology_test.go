@@ -0,0 +1,187 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package topology
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/NVIDIA/go-nvml/pkg/nvml/mock"
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func newPciInfo(busID string) nvml.PciInfo {
+	var info nvml.PciInfo
+	for i, c := range busID {
+		info.BusId[i] = int8(c)
+	}
+	return info
+}
+
+// newMockDevice creates a mock device with the specified bus ID and whose
+// NVLinks are connected to the specified remote bus IDs.
+func newMockDevice(uuid string, busID string, numaMask uint, nvlinks ...string) *mock.Device {
+	return &mock.Device{
+		GetUUIDFunc: func() (string, nvml.Return) {
+			return uuid, nvml.SUCCESS
+		},
+		GetPciInfoFunc: func() (nvml.PciInfo, nvml.Return) {
+			return newPciInfo(busID), nvml.SUCCESS
+		},
+		GetMemoryAffinityFunc: func(n int, scope nvml.AffinityScope) ([]uint, nvml.Return) {
+			if numaMask == 0 {
+				return nil, nvml.ERROR_NOT_SUPPORTED
+			}
+			affinity := make([]uint, n)
+			affinity[0] = numaMask
+			return affinity, nvml.SUCCESS
+		},
+		GetNvLinkStateFunc: func(link int) (nvml.EnableState, nvml.Return) {
+			if link >= len(nvlinks) {
+				return nvml.FEATURE_DISABLED, nvml.SUCCESS
+			}
+			return nvml.FEATURE_ENABLED, nvml.SUCCESS
+		},
+		GetNvLinkRemotePciInfoFunc: func(link int) (nvml.PciInfo, nvml.Return) {
+			return newPciInfo(nvlinks[link]), nvml.SUCCESS
+		},
+	}
+}
+
+func newMockInterface(devices ...*mock.Device) *mock.Interface {
+	levels := map[[2]string]nvml.GpuTopologyLevel{}
+	for _, d := range devices {
+		d.GetTopologyCommonAncestorFunc = func(other nvml.Device) (nvml.GpuTopologyLevel, nvml.Return) {
+			a, _ := d.GetUUID()
+			b, _ := other.GetUUID()
+			if level, ok := levels[[2]string{a, b}]; ok {
+				return level, nvml.SUCCESS
+			}
+			return nvml.TOPOLOGY_SYSTEM, nvml.SUCCESS
+		}
+	}
+	levels[[2]string{"GPU-0", "GPU-1"}] = nvml.TOPOLOGY_HOSTBRIDGE
+
+	return &mock.Interface{
+		InitFunc: func() nvml.Return {
+			return nvml.SUCCESS
+		},
+		ShutdownFunc: func() nvml.Return {
+			return nvml.SUCCESS
+		},
+		DeviceGetCountFunc: func() (int, nvml.Return) {
+			return len(devices), nvml.SUCCESS
+		},
+		DeviceGetHandleByIndexFunc: func(n int) (nvml.Device, nvml.Return) {
+			return devices[n], nvml.SUCCESS
+		},
+	}
+}
+
+func TestRun(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	testCases := []struct {
+		description      string
+		devices          []*mock.Device
+		expectedTopology Topology
+	}{
+		{
+			description: "single GPU",
+			devices: []*mock.Device{
+				newMockDevice("GPU-0", "00000000:3B:00.0", 0b1),
+			},
+			expectedTopology: Topology{
+				GPUs: []GPU{
+					{Index: 0, UUID: "GPU-0", PCIBusID: "0000:3b:00.0", NUMANodes: []int{0}},
+				},
+				Links: []Link{},
+			},
+		},
+		{
+			description: "GPUs connected by NVLink",
+			devices: []*mock.Device{
+				newMockDevice("GPU-0", "00000000:3B:00.0", 0b1, "00000000:86:00.0", "00000000:86:00.0"),
+				newMockDevice("GPU-1", "00000000:86:00.0", 0b10, "00000000:3B:00.0", "00000000:3B:00.0"),
+				newMockDevice("GPU-2", "00000000:AF:00.0", 0),
+			},
+			expectedTopology: Topology{
+				GPUs: []GPU{
+					{Index: 0, UUID: "GPU-0", PCIBusID: "0000:3b:00.0", NUMANodes: []int{0}},
+					{Index: 1, UUID: "GPU-1", PCIBusID: "0000:86:00.0", NUMANodes: []int{1}},
+					{Index: 2, UUID: "GPU-2", PCIBusID: "0000:af:00.0"},
+				},
+				Links: []Link{
+					{GPUs: [2]int{0, 1}, PCIe: "hostbridge", NVLinks: 2},
+					{GPUs: [2]int{0, 2}, PCIe: "system"},
+					{GPUs: [2]int{1, 2}, PCIe: "system"},
+				},
+			},
+		},
+		{
+			description: "GPUs connected by NVSwitch",
+			devices: []*mock.Device{
+				newMockDevice("GPU-0", "00000000:3B:00.0", 0b11, "00000000:C1:00.0", "00000000:C2:00.0"),
+				newMockDevice("GPU-1", "00000000:86:00.0", 0b11, "00000000:C1:00.0", "00000000:C2:00.0"),
+			},
+			expectedTopology: Topology{
+				GPUs: []GPU{
+					{Index: 0, UUID: "GPU-0", PCIBusID: "0000:3b:00.0", NUMANodes: []int{0, 1}, NVSwitchLinks: 2},
+					{Index: 1, UUID: "GPU-1", PCIBusID: "0000:86:00.0", NUMANodes: []int{0, 1}, NVSwitchLinks: 2},
+				},
+				Links: []Link{
+					{GPUs: [2]int{0, 1}, PCIe: "hostbridge"},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			c := command{logger: logger}
+
+			var buf bytes.Buffer
+			require.NoError(t, c.run(&buf, newMockInterface(tc.devices...), &options{format: formatJSON}))
+
+			var topology Topology
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &topology))
+			require.EqualValues(t, tc.expectedTopology, topology)
+		})
+	}
+}
+
+func TestRunText(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	nvmllib := newMockInterface(
+		newMockDevice("GPU-0", "00000000:3B:00.0", 0b1, "00000000:86:00.0"),
+		newMockDevice("GPU-1", "00000000:86:00.0", 0, "00000000:3B:00.0"),
+	)
+	c := command{logger: logger}
+
+	var buf bytes.Buffer
+	require.NoError(t, c.run(&buf, nvmllib, &options{format: formatText}))
+	require.Equal(t,
+		"GPU 0: GPU-0\n  pci bus id: 0000:3b:00.0\n  numa nodes: 0\n"+
+			"GPU 1: GPU-1\n  pci bus id: 0000:86:00.0\n  numa nodes: unknown\n"+
+			"GPU 0 <-> GPU 1: pcie=hostbridge nvlinks=1\n",
+		buf.String(),
+	)
+}
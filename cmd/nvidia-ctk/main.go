@@ -22,14 +22,7 @@ import (
 
 	"github.com/sirupsen/logrus"
 
-	"github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/cdi"
-	"github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/config"
-	"github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/hook"
-	infoCLI "github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/info"
-	"github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/runtime"
-	"github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/system"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/info"
-	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
 
 	cli "github.com/urfave/cli/v3"
 )
@@ -104,14 +97,3 @@ func main() {
 		os.Exit(1)
 	}
 }
-
-func getCommands(logger logger.Interface, configFilePath *string) []*cli.Command {
-	return []*cli.Command{
-		hook.NewCommand(logger),
-		runtime.NewCommand(logger),
-		infoCLI.NewCommand(logger),
-		cdi.NewCommand(logger, configFilePath),
-		system.NewCommand(logger),
-		config.NewCommand(logger),
-	}
-}
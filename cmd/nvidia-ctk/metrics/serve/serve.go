@@ -0,0 +1,194 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package serve
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/urfave/cli/v3"
+	"tags.cncf.io/container-device-interface/pkg/cdi"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/listener"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup/root"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/metrics"
+)
+
+const (
+	defaultAddress = "unix:///run/nvidia-container-toolkit/metrics.sock"
+	// socketMode is the mode of the unix socket that metrics are served on.
+	// This allows members of the group of the process to scrape the metrics.
+	socketMode  = 0660
+	metricsPath = "/metrics"
+)
+
+type command struct {
+	logger         logger.Interface
+	configFilePath *string
+}
+
+type options struct {
+	address         string
+	metricsFilePath string
+	driverRoot      string
+	cdiSpecDirs     []string
+}
+
+// NewCommand constructs a metrics serve command with the specified logger
+func NewCommand(logger logger.Interface, configFilePath *string) *cli.Command {
+	c := command{
+		logger:         logger,
+		configFilePath: configFilePath,
+	}
+	return c.build()
+}
+
+// build the serve command
+func (m command) build() *cli.Command {
+	opts := options{}
+
+	c := cli.Command{
+		Name:  "serve",
+		Usage: "Serve NVIDIA Container Toolkit metrics in the Prometheus text format",
+		Before: func(ctx context.Context, cmd *cli.Command) (context.Context, error) {
+			return ctx, m.validateFlags(&opts)
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			return m.run(ctx, &opts)
+		},
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "address",
+				Usage:       "The address to serve metrics on. This is either a unix socket (unix:///path/to/socket) or a TCP address (tcp://host:port).",
+				Value:       defaultAddress,
+				Destination: &opts.address,
+				Sources:     cli.EnvVars("NVIDIA_CTK_METRICS_ADDRESS"),
+			},
+			&cli.StringFlag{
+				Name:        "metrics-file",
+				Usage:       "The file in which the NVIDIA Container Runtime records metrics. If this is not specified, the nvidia-container-runtime.metrics-file config option is used.",
+				Destination: &opts.metricsFilePath,
+				Sources:     cli.EnvVars("NVIDIA_CTK_METRICS_FILE"),
+			},
+			&cli.StringFlag{
+				Name:        "driver-root",
+				Usage:       "The path to the driver root. This is used to determine whether CDI specs are stale.",
+				Value:       "/",
+				Destination: &opts.driverRoot,
+				Sources:     cli.EnvVars("NVIDIA_DRIVER_ROOT", "DRIVER_ROOT"),
+			},
+			&cli.StringSliceFlag{
+				Name:        "spec-dir",
+				Usage:       "specify the directories to scan for CDI specifications",
+				Value:       cdi.DefaultSpecDirs,
+				Destination: &opts.cdiSpecDirs,
+				Sources:     cli.EnvVars("NVIDIA_CTK_CDI_SPEC_DIRS"),
+			},
+		},
+	}
+
+	return &c
+}
+
+func (m command) validateFlags(opts *options) error {
+	if _, _, err := listener.ParseAddress(opts.address); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m command) run(ctx context.Context, opts *options) error {
+	configFilePath := m.getConfigFilePath()
+	if opts.metricsFilePath == "" {
+		opts.metricsFilePath = m.getMetricsFilePath(configFilePath)
+	}
+
+	collector := metrics.NewCollector(
+		metrics.WithLogger(m.logger),
+		metrics.WithStore(metrics.NewStore(opts.metricsFilePath)),
+		metrics.WithDriver(root.New(
+			root.WithLogger(m.logger),
+			root.WithDriverRoot(opts.driverRoot),
+		)),
+		metrics.WithCDISpecDirs(opts.cdiSpecDirs...),
+		metrics.WithConfigFile(configFilePath),
+	)
+
+	l, err := listener.Listen(opts.address, socketMode)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(metricsPath, collector)
+	server := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	m.logger.Infof("Serving metrics on %v%v", opts.address, metricsPath)
+	if err := server.Serve(l); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("failed to serve metrics: %w", err)
+	}
+	return nil
+}
+
+// getConfigFilePath returns the path to the config file for the toolkit.
+func (m command) getConfigFilePath() string {
+	if m.configFilePath != nil && *m.configFilePath != "" {
+		return *m.configFilePath
+	}
+	return config.GetConfigFilePath()
+}
+
+// getMetricsFilePath returns the metrics file configured for the NVIDIA
+// Container Runtime, falling back to the default path if this is not set.
+func (m command) getMetricsFilePath(configFilePath string) string {
+	configToml, err := config.New(
+		config.WithConfigFile(configFilePath),
+	)
+	if err != nil {
+		m.logger.Warningf("Failed to load config: %v", err)
+		return metrics.DefaultFilePath
+	}
+	cfg, err := configToml.Config()
+	if err != nil {
+		m.logger.Warningf("Failed to load config: %v", err)
+		return metrics.DefaultFilePath
+	}
+	if cfg.NVIDIAContainerRuntimeConfig.MetricsFilePath == "" {
+		return metrics.DefaultFilePath
+	}
+	return cfg.NVIDIAContainerRuntimeConfig.MetricsFilePath
+}
@@ -0,0 +1,274 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package check
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/policy"
+)
+
+type command struct {
+	logger         logger.Interface
+	configFilePath *string
+}
+
+type options struct {
+	podFile     string
+	env         []string
+	privileged  bool
+	annotations []string
+	mounts      []string
+}
+
+// pod represents the subset of a Kubernetes Pod (or PodSpec) that is
+// relevant to the policy.
+type pod struct {
+	Metadata struct {
+		Annotations map[string]string `json:"annotations"`
+	} `json:"metadata"`
+	Spec *podSpec `json:"spec"`
+	podSpec
+}
+
+type podSpec struct {
+	InitContainers []container `json:"initContainers"`
+	Containers     []container `json:"containers"`
+}
+
+type container struct {
+	Name string `json:"name"`
+	Env  []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	} `json:"env"`
+	SecurityContext *struct {
+		Privileged *bool `json:"privileged"`
+	} `json:"securityContext"`
+	VolumeMounts []struct {
+		MountPath string `json:"mountPath"`
+	} `json:"volumeMounts"`
+}
+
+// containerResult is the result of the policy check for a named container.
+type containerResult struct {
+	Container string `json:"container,omitempty"`
+	Allowed   bool   `json:"allowed"`
+	*policy.Result
+}
+
+// NewCommand constructs a policy check command with the specified logger
+func NewCommand(logger logger.Interface, configFilePath *string) *cli.Command {
+	c := command{
+		logger:         logger,
+		configFilePath: configFilePath,
+	}
+	return c.build()
+}
+
+// build the check command
+func (m command) build() *cli.Command {
+	opts := options{}
+
+	c := cli.Command{
+		Name:  "check",
+		Usage: "Check whether the GPU requests of a pod or container are allowed by the policy of the node",
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			return m.run(cmd.Writer, &opts)
+		},
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "pod",
+				Usage:       "A file containing a Kubernetes Pod or PodSpec in JSON format. Use - to read from stdin. If this is not specified, a single container is described using the --env, --privileged, --annotation, and --mount flags.",
+				Destination: &opts.podFile,
+			},
+			&cli.StringSliceFlag{
+				Name:        "env",
+				Usage:       "An envvar (NAME=VALUE) of the container. This should include envvars defined in the image.",
+				Destination: &opts.env,
+			},
+			&cli.BoolFlag{
+				Name:        "privileged",
+				Usage:       "Indicate that the container is privileged",
+				Destination: &opts.privileged,
+			},
+			&cli.StringSliceFlag{
+				Name:        "annotation",
+				Usage:       "An annotation (KEY=VALUE) of the container",
+				Destination: &opts.annotations,
+			},
+			&cli.StringSliceFlag{
+				Name:        "mount",
+				Usage:       "The path of a volume mount in the container",
+				Destination: &opts.mounts,
+			},
+		},
+	}
+
+	return &c
+}
+
+func (m command) run(w io.Writer, opts *options) error {
+	configFilePath := ""
+	if m.configFilePath != nil {
+		configFilePath = *m.configFilePath
+	}
+	p, err := policy.Load(configFilePath)
+	if err != nil {
+		return err
+	}
+
+	containers, err := m.getContainers(opts)
+	if err != nil {
+		return err
+	}
+
+	var results []containerResult
+	var rejected []string
+	for _, c := range containers {
+		result, err := p.Check(c.Container)
+		if err != nil {
+			return fmt.Errorf("failed to check container %q: %w", c.name, err)
+		}
+		results = append(results, containerResult{
+			Container: c.name,
+			Allowed:   result.Allowed(),
+			Result:    result,
+		})
+		if !result.Allowed() {
+			rejected = append(rejected, c.name)
+		}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(results); err != nil {
+		return fmt.Errorf("failed to write results: %w", err)
+	}
+
+	if len(rejected) > 0 {
+		return fmt.Errorf("containers %q are not allowed by the policy", rejected)
+	}
+	return nil
+}
+
+// getContainers returns the containers to check. The order of the containers
+// in the pod is maintained.
+func (m command) getContainers(opts *options) (namedContainers, error) {
+	if opts.podFile == "" {
+		annotations, err := parseKeyValues(opts.annotations)
+		if err != nil {
+			return nil, err
+		}
+		return namedContainers{{
+			Container: policy.Container{
+				Env:         opts.env,
+				Privileged:  opts.privileged,
+				Annotations: annotations,
+				Mounts:      opts.mounts,
+			},
+		}}, nil
+	}
+
+	contents, err := readFile(opts.podFile)
+	if err != nil {
+		return nil, err
+	}
+	return parsePod(contents)
+}
+
+// namedContainer associates a policy container with its name in a pod.
+type namedContainer struct {
+	name string
+	policy.Container
+}
+
+type namedContainers []namedContainer
+
+// parsePod parses the containers from a Kubernetes Pod or PodSpec in JSON
+// format. Envvars that reference other sources (i.e. valueFrom) are treated as
+// being empty.
+func parsePod(contents []byte) (namedContainers, error) {
+	var p pod
+	if err := json.Unmarshal(contents, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse pod: %w", err)
+	}
+	spec := &p.podSpec
+	if p.Spec != nil {
+		spec = p.Spec
+	}
+
+	var containers namedContainers
+	for _, c := range append(spec.InitContainers, spec.Containers...) {
+		var env []string
+		for _, e := range c.Env {
+			env = append(env, e.Name+"="+e.Value)
+		}
+		var mounts []string
+		for _, v := range c.VolumeMounts {
+			mounts = append(mounts, v.MountPath)
+		}
+		containers = append(containers, namedContainer{
+			name: c.Name,
+			Container: policy.Container{
+				Env:         env,
+				Privileged:  c.SecurityContext != nil && c.SecurityContext.Privileged != nil && *c.SecurityContext.Privileged,
+				Annotations: p.Metadata.Annotations,
+				Mounts:      mounts,
+			},
+		})
+	}
+	if len(containers) == 0 {
+		return nil, fmt.Errorf("no containers found in pod")
+	}
+	return containers, nil
+}
+
+// parseKeyValues parses a list of KEY=VALUE pairs.
+func parseKeyValues(keyValues []string) (map[string]string, error) {
+	if len(keyValues) == 0 {
+		return nil, nil
+	}
+	result := make(map[string]string)
+	for _, kv := range keyValues {
+		key, value, found := strings.Cut(kv, "=")
+		if !found || key == "" {
+			return nil, fmt.Errorf("invalid key-value pair %q", kv)
+		}
+		result[key] = value
+	}
+	return result, nil
+}
+
+func readFile(path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %v: %w", path, err)
+	}
+	return contents, nil
+}
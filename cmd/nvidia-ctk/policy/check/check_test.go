@@ -0,0 +1,98 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package check
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/policy"
+)
+
+func TestParsePod(t *testing.T) {
+	testCases := []struct {
+		description        string
+		contents           string
+		expectedContainers namedContainers
+		expectedError      bool
+	}{
+		{
+			description: "pod",
+			contents: `{
+				"metadata": {"annotations": {"foo": "bar"}},
+				"spec": {
+					"initContainers": [{"name": "init"}],
+					"containers": [{
+						"name": "cuda",
+						"env": [{"name": "NVIDIA_VISIBLE_DEVICES", "value": "0"}, {"name": "FROM_SECRET", "valueFrom": {}}],
+						"securityContext": {"privileged": true},
+						"volumeMounts": [{"mountPath": "/var/run/nvidia-container-devices/1"}]
+					}]
+				}
+			}`,
+			expectedContainers: namedContainers{
+				{
+					name: "init",
+					Container: policy.Container{
+						Annotations: map[string]string{"foo": "bar"},
+					},
+				},
+				{
+					name: "cuda",
+					Container: policy.Container{
+						Env:         []string{"NVIDIA_VISIBLE_DEVICES=0", "FROM_SECRET="},
+						Privileged:  true,
+						Annotations: map[string]string{"foo": "bar"},
+						Mounts:      []string{"/var/run/nvidia-container-devices/1"},
+					},
+				},
+			},
+		},
+		{
+			description: "pod spec",
+			contents:    `{"containers": [{"name": "cuda", "securityContext": {"privileged": false}}]}`,
+			expectedContainers: namedContainers{
+				{
+					name: "cuda",
+				},
+			},
+		},
+		{
+			description:   "no containers",
+			contents:      `{"spec": {}}`,
+			expectedError: true,
+		},
+		{
+			description:   "invalid json",
+			contents:      `containers: []`,
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			containers, err := parsePod([]byte(tc.contents))
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.EqualValues(t, tc.expectedContainers, containers)
+		})
+	}
+}
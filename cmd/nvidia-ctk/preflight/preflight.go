@@ -0,0 +1,389 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package preflight
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config/image"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/librarygroups"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/injectionreport"
+)
+
+const (
+	formatText = "text"
+	formatJSON = "json"
+)
+
+const (
+	statusPass = "pass"
+	statusFail = "fail"
+	statusWarn = "warn"
+)
+
+var gpuDeviceNodePattern = regexp.MustCompile(`^nvidia[0-9]+$`)
+
+type command struct {
+	logger logger.Interface
+}
+
+type options struct {
+	root            string
+	injectionReport string
+	requireReport   bool
+	devices         []string
+	gpus            int
+	libraries       []string
+	env             []string
+	noCapabilities  bool
+	format          string
+
+	// environ is the environment of the container that is checked.
+	environ []string
+}
+
+// A Result is the result of a single preflight check.
+type Result struct {
+	// Check describes what was checked (e.g. "device /dev/nvidiactl").
+	Check string `json:"check"`
+	// Status is one of pass, fail, or warn.
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// NewCommand constructs a preflight command with the specified logger
+func NewCommand(logger logger.Interface) *cli.Command {
+	c := command{
+		logger: logger,
+	}
+	return c.build()
+}
+
+// build creates the CLI command
+func (m command) build() *cli.Command {
+	opts := options{}
+
+	c := cli.Command{
+		Name:  "preflight",
+		Usage: "Check that the expected GPU devices, libraries, and envvars are available in a container",
+		Description: "This command is intended to be run as an init container or entrypoint step. " +
+			"It exits with a non-zero exit code if any of the checks fail.",
+		Before: func(ctx context.Context, cmd *cli.Command) (context.Context, error) {
+			return ctx, m.validateFlags(&opts)
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			opts.environ = os.Environ()
+			return m.run(cmd.Writer, &opts)
+		},
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "root",
+				Usage:       "The root of the container filesystem that is checked",
+				Value:       "/",
+				Destination: &opts.root,
+			},
+			&cli.StringFlag{
+				Name:        "injection-report",
+				Usage:       "The path to the injection report written by the NVIDIA Container Runtime. If the report exists, the devices and libraries listed in it are checked.",
+				Value:       injectionreport.ContainerPath,
+				Destination: &opts.injectionReport,
+			},
+			&cli.BoolFlag{
+				Name:        "require-injection-report",
+				Usage:       "Fail if the injection report does not exist",
+				Destination: &opts.requireReport,
+			},
+			&cli.StringSliceFlag{
+				Name:        "device",
+				Usage:       "The path of a device node that is expected to exist",
+				Destination: &opts.devices,
+			},
+			&cli.IntFlag{
+				Name:        "gpus",
+				Usage:       "The minimum number of GPU device nodes (/dev/nvidiaN) that are expected to exist",
+				Destination: &opts.gpus,
+			},
+			&cli.StringSliceFlag{
+				Name:        "library",
+				Usage:       "The name of a library (e.g. libcuda.so.1) that is expected to be available",
+				Destination: &opts.libraries,
+			},
+			&cli.StringSliceFlag{
+				Name:        "env",
+				Usage:       "An envvar that is expected to be set. Specify NAME=VALUE to also check its value.",
+				Destination: &opts.env,
+			},
+			&cli.BoolFlag{
+				Name:        "no-driver-capabilities",
+				Usage:       "Do not check that libraries are available for the driver capabilities requested in NVIDIA_DRIVER_CAPABILITIES",
+				Destination: &opts.noCapabilities,
+			},
+			&cli.StringFlag{
+				Name:        "format",
+				Usage:       "The output format. One of [text | json]",
+				Value:       formatText,
+				Destination: &opts.format,
+			},
+		},
+	}
+
+	return &c
+}
+
+func (m command) validateFlags(opts *options) error {
+	switch opts.format {
+	case formatText, formatJSON:
+	default:
+		return fmt.Errorf("unsupported format: %q", opts.format)
+	}
+	if opts.gpus < 0 {
+		return fmt.Errorf("invalid number of GPUs: %d", opts.gpus)
+	}
+	return nil
+}
+
+// run performs the preflight checks and writes the results in the requested
+// format. An error is returned if any of the checks fail.
+func (m command) run(w io.Writer, opts *options) error {
+	results := m.check(opts)
+
+	if err := writeResults(w, opts.format, results); err != nil {
+		return err
+	}
+
+	var failed int
+	for _, r := range results {
+		if r.Status == statusFail {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d preflight checks failed", failed, len(results))
+	}
+	return nil
+}
+
+// check performs all preflight checks. The checks of the injection report
+// are performed first, followed by the explicitly requested checks.
+func (m command) check(opts *options) []Result {
+	var results []Result
+	results = append(results, m.checkInjectionReport(opts)...)
+
+	for _, device := range opts.devices {
+		results = append(results, checkDevice(opts.root, device))
+	}
+	if opts.gpus > 0 {
+		results = append(results, checkGPUs(opts.root, opts.gpus))
+	}
+
+	libraries := lookup.NewLibraryLocator(
+		lookup.WithLogger(m.logger),
+		lookup.WithRoot(opts.root),
+	)
+	for _, library := range opts.libraries {
+		results = append(results, checkLibrary(libraries, library))
+	}
+
+	environ := getEnvMap(opts.environ)
+	for _, envvar := range opts.env {
+		results = append(results, checkEnvvar(environ, envvar))
+	}
+
+	if !opts.noCapabilities {
+		results = append(results, m.checkDriverCapabilities(libraries, environ)...)
+	}
+	return results
+}
+
+// checkInjectionReport checks that the devices and libraries listed in the
+// injection report exist. Entries that were skipped while creating the
+// container are reported as warnings.
+func (m command) checkInjectionReport(opts *options) []Result {
+	check := "injection report " + opts.injectionReport
+	report, err := injectionreport.Load(filepath.Join(opts.root, opts.injectionReport))
+	if errors.Is(err, os.ErrNotExist) && !opts.requireReport {
+		m.logger.Debugf("Skipping injection report checks: %v", err)
+		return nil
+	}
+	if err != nil {
+		return []Result{{Check: check, Status: statusFail, Message: err.Error()}}
+	}
+
+	results := []Result{{Check: check, Status: statusPass}}
+	for _, device := range report.Devices {
+		results = append(results, checkDevice(opts.root, device.Path))
+	}
+	for _, library := range report.Libraries {
+		results = append(results, checkFile("library "+library.Path, opts.root, library.Path))
+	}
+	for _, skipped := range report.Skipped {
+		results = append(results, Result{Check: "injection", Status: statusWarn, Message: skipped})
+	}
+	return results
+}
+
+// checkDriverCapabilities checks that at least one library of the library
+// group of each requested driver capability is available. The checks are
+// skipped if no devices are requested in the container environment.
+func (m command) checkDriverCapabilities(libraries lookup.Locator, environ map[string]string) []Result {
+	switch environ[image.EnvVarNvidiaVisibleDevices] {
+	case "", "void", image.NoneDeviceName:
+		return nil
+	}
+
+	capabilities := image.NewDriverCapabilities(environ[image.EnvVarNvidiaDriverCapabilities])
+	switch {
+	case len(capabilities) == 0:
+		capabilities = image.DefaultDriverCapabilities
+	case capabilities.IsAll():
+		capabilities = image.SupportedDriverCapabilities
+	}
+
+	groups := librarygroups.Default()
+	var results []Result
+	for _, c := range capabilities.List() {
+		patterns := groups[image.DriverCapability(c)]
+		if len(patterns) == 0 {
+			continue
+		}
+		check := "driver capability " + c
+		if slices.ContainsFunc(patterns, func(pattern string) bool {
+			located, err := libraries.Locate(pattern)
+			return err == nil && len(located) > 0
+		}) {
+			results = append(results, Result{Check: check, Status: statusPass})
+			continue
+		}
+		results = append(results, Result{
+			Check:   check,
+			Status:  statusFail,
+			Message: fmt.Sprintf("none of the libraries %v were found", patterns),
+		})
+	}
+	return results
+}
+
+// checkDevice checks that the specified path is a device node.
+func checkDevice(root string, device string) Result {
+	check := "device " + device
+	info, err := os.Stat(filepath.Join(root, device))
+	if err != nil {
+		return Result{Check: check, Status: statusFail, Message: err.Error()}
+	}
+	if info.Mode()&os.ModeDevice == 0 {
+		return Result{Check: check, Status: statusFail, Message: "not a device node"}
+	}
+	return Result{Check: check, Status: statusPass}
+}
+
+// checkGPUs checks that at least the specified number of /dev/nvidiaN device
+// nodes exist.
+func checkGPUs(root string, expected int) Result {
+	check := fmt.Sprintf("at least %d GPU device nodes", expected)
+	entries, err := os.ReadDir(filepath.Join(root, "dev"))
+	if err != nil {
+		return Result{Check: check, Status: statusFail, Message: err.Error()}
+	}
+
+	var found []string
+	for _, entry := range entries {
+		if gpuDeviceNodePattern.MatchString(entry.Name()) {
+			found = append(found, "/dev/"+entry.Name())
+		}
+	}
+	if len(found) < expected {
+		return Result{Check: check, Status: statusFail, Message: fmt.Sprintf("found %d: %v", len(found), found)}
+	}
+	return Result{Check: check, Status: statusPass, Message: fmt.Sprintf("found %d", len(found))}
+}
+
+// checkFile checks that the specified path exists.
+func checkFile(check string, root string, path string) Result {
+	if _, err := os.Stat(filepath.Join(root, path)); err != nil {
+		return Result{Check: check, Status: statusFail, Message: err.Error()}
+	}
+	return Result{Check: check, Status: statusPass}
+}
+
+// checkLibrary checks that the specified library can be located.
+func checkLibrary(libraries lookup.Locator, library string) Result {
+	check := "library " + library
+	located, err := libraries.Locate(library)
+	if err != nil || len(located) == 0 {
+		return Result{Check: check, Status: statusFail, Message: "not found"}
+	}
+	return Result{Check: check, Status: statusPass, Message: located[0]}
+}
+
+// checkEnvvar checks that an envvar is set. If the expected envvar is
+// specified as NAME=VALUE, the value is also checked.
+func checkEnvvar(environ map[string]string, expected string) Result {
+	name, value, hasValue := strings.Cut(expected, "=")
+	check := "envvar " + name
+	actual, ok := environ[name]
+	switch {
+	case !ok:
+		return Result{Check: check, Status: statusFail, Message: "not set"}
+	case hasValue && actual != value:
+		return Result{Check: check, Status: statusFail, Message: fmt.Sprintf("expected %q; got %q", value, actual)}
+	}
+	return Result{Check: check, Status: statusPass}
+}
+
+func getEnvMap(environ []string) map[string]string {
+	envMap := make(map[string]string)
+	for _, envvar := range environ {
+		name, value, _ := strings.Cut(envvar, "=")
+		envMap[name] = value
+	}
+	return envMap
+}
+
+func writeResults(w io.Writer, format string, results []Result) error {
+	if format == formatJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(results); err != nil {
+			return fmt.Errorf("failed to write results: %w", err)
+		}
+		return nil
+	}
+
+	for _, r := range results {
+		line := fmt.Sprintf("[%s] %s", strings.ToUpper(r.Status), r.Check)
+		if r.Message != "" {
+			line += ": " + r.Message
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return fmt.Errorf("failed to write results: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package preflight
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/injectionreport"
+)
+
+func TestPreflight(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "dev"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "usr/lib64"), 0755))
+	require.NoError(t, os.Symlink("/dev/null", filepath.Join(root, "dev/nvidiactl")))
+	require.NoError(t, os.Symlink("/dev/null", filepath.Join(root, "dev/nvidia0")))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "dev/nvidia1"), nil, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "usr/lib64/libcuda.so.1"), nil, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "usr/lib64/libnvidia-ml.so.1"), nil, 0644))
+
+	reportDir := filepath.Join(root, "run/nvidia-container-toolkit")
+	require.NoError(t, os.MkdirAll(reportDir, 0755))
+	report := &injectionreport.Report{
+		Devices: []injectionreport.Device{
+			{Path: "/dev/nvidiactl"},
+			{Path: "/dev/nvidia0"},
+		},
+		Libraries: []injectionreport.Library{
+			{Path: "/usr/lib64/libcuda.so.1"},
+		},
+		Skipped: []string{"Could not locate libnvidia-missing.so"},
+	}
+	require.NoError(t, report.Save(reportDir))
+	reportPath := "/run/nvidia-container-toolkit/" + injectionreport.FileName
+
+	testCases := []struct {
+		description     string
+		options         options
+		expectedResults []Result
+		expectedError   bool
+	}{
+		{
+			description: "missing injection report is ignored",
+			options: options{
+				injectionReport: "/missing.json",
+				noCapabilities:  true,
+			},
+		},
+		{
+			description: "missing injection report fails if required",
+			options: options{
+				injectionReport: "/missing.json",
+				requireReport:   true,
+				noCapabilities:  true,
+			},
+			expectedResults: []Result{
+				{Check: "injection report /missing.json", Status: statusFail, Message: "failed to read injection report: open " + root + "/missing.json: no such file or directory"},
+			},
+			expectedError: true,
+		},
+		{
+			description: "injection report entries are checked",
+			options: options{
+				injectionReport: reportPath,
+				noCapabilities:  true,
+			},
+			expectedResults: []Result{
+				{Check: "injection report " + reportPath, Status: statusPass},
+				{Check: "device /dev/nvidiactl", Status: statusPass},
+				{Check: "device /dev/nvidia0", Status: statusPass},
+				{Check: "library /usr/lib64/libcuda.so.1", Status: statusPass},
+				{Check: "injection", Status: statusWarn, Message: "Could not locate libnvidia-missing.so"},
+			},
+		},
+		{
+			description: "devices are checked",
+			options: options{
+				injectionReport: "/missing.json",
+				devices:         []string{"/dev/nvidia0", "/dev/nvidia1", "/dev/nvidia2"},
+				noCapabilities:  true,
+			},
+			expectedResults: []Result{
+				{Check: "device /dev/nvidia0", Status: statusPass},
+				{Check: "device /dev/nvidia1", Status: statusFail, Message: "not a device node"},
+				{Check: "device /dev/nvidia2", Status: statusFail, Message: "stat " + root + "/dev/nvidia2: no such file or directory"},
+			},
+			expectedError: true,
+		},
+		{
+			description: "number of GPUs is checked",
+			options: options{
+				injectionReport: "/missing.json",
+				gpus:            3,
+				noCapabilities:  true,
+			},
+			expectedResults: []Result{
+				{Check: "at least 3 GPU device nodes", Status: statusFail, Message: "found 2: [/dev/nvidia0 /dev/nvidia1]"},
+			},
+			expectedError: true,
+		},
+		{
+			description: "libraries are checked",
+			options: options{
+				injectionReport: "/missing.json",
+				libraries:       []string{"libcuda.so.1", "libnvidia-encode.so.1"},
+				noCapabilities:  true,
+			},
+			expectedResults: []Result{
+				{Check: "library libcuda.so.1", Status: statusPass, Message: root + "/usr/lib64/libcuda.so.1"},
+				{Check: "library libnvidia-encode.so.1", Status: statusFail, Message: "not found"},
+			},
+			expectedError: true,
+		},
+		{
+			description: "envvars are checked",
+			options: options{
+				injectionReport: "/missing.json",
+				env:             []string{"NVIDIA_VISIBLE_DEVICES", "NVIDIA_DRIVER_CAPABILITIES=all", "MISSING"},
+				noCapabilities:  true,
+				environ:         []string{"NVIDIA_VISIBLE_DEVICES=all", "NVIDIA_DRIVER_CAPABILITIES=compute"},
+			},
+			expectedResults: []Result{
+				{Check: "envvar NVIDIA_VISIBLE_DEVICES", Status: statusPass},
+				{Check: "envvar NVIDIA_DRIVER_CAPABILITIES", Status: statusFail, Message: `expected "all"; got "compute"`},
+				{Check: "envvar MISSING", Status: statusFail, Message: "not set"},
+			},
+			expectedError: true,
+		},
+		{
+			description: "default driver capabilities are checked",
+			options: options{
+				injectionReport: "/missing.json",
+				environ:         []string{"NVIDIA_VISIBLE_DEVICES=all"},
+			},
+			expectedResults: []Result{
+				{Check: "driver capability compute", Status: statusPass},
+				{Check: "driver capability utility", Status: statusPass},
+			},
+		},
+		{
+			description: "missing driver capability libraries fail",
+			options: options{
+				injectionReport: "/missing.json",
+				environ:         []string{"NVIDIA_VISIBLE_DEVICES=all", "NVIDIA_DRIVER_CAPABILITIES=compute,ngx"},
+			},
+			expectedResults: []Result{
+				{Check: "driver capability compute", Status: statusPass},
+				{Check: "driver capability ngx", Status: statusFail, Message: "none of the libraries [libnvidia-ngx.so.*] were found"},
+			},
+			expectedError: true,
+		},
+		{
+			description: "driver capabilities are not checked without devices",
+			options: options{
+				injectionReport: "/missing.json",
+				environ:         []string{"NVIDIA_VISIBLE_DEVICES=void", "NVIDIA_DRIVER_CAPABILITIES=ngx"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			m := command{
+				logger: logger,
+			}
+			tc.options.root = root
+
+			require.EqualValues(t, tc.expectedResults, m.check(&tc.options))
+
+			err := m.run(&bytes.Buffer{}, &tc.options)
+			if tc.expectedError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestWriteResults(t *testing.T) {
+	results := []Result{
+		{Check: "device /dev/nvidia0", Status: statusPass},
+		{Check: "library libcuda.so.1", Status: statusFail, Message: "not found"},
+	}
+
+	buffer := &bytes.Buffer{}
+	require.NoError(t, writeResults(buffer, formatText, results))
+	require.Equal(t, "[PASS] device /dev/nvidia0\n[FAIL] library libcuda.so.1: not found\n", buffer.String())
+}
@@ -24,28 +24,15 @@ import (
 	"github.com/urfave/cli/v3"
 
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
-	"github.com/NVIDIA/nvidia-container-toolkit/pkg/config/engine"
 	"github.com/NVIDIA/nvidia-container-toolkit/pkg/config/engine/containerd"
 	"github.com/NVIDIA/nvidia-container-toolkit/pkg/config/engine/crio"
-	"github.com/NVIDIA/nvidia-container-toolkit/pkg/config/engine/docker"
 	"github.com/NVIDIA/nvidia-container-toolkit/pkg/config/ocihook"
 	"github.com/NVIDIA/nvidia-container-toolkit/pkg/config/toml"
 )
 
 const (
-	defaultRuntime = "docker"
-
-	// defaultNVIDIARuntimeName is the default name to use in configs for the NVIDIA Container Runtime
-	defaultNVIDIARuntimeName = "nvidia"
-	// defaultNVIDIARuntimeExecutable is the default NVIDIA Container Runtime executable file name
-	defaultNVIDIARuntimeExecutable          = "nvidia-container-runtime"
-	defaultNVIDIARuntimeExpecutablePath     = "/usr/bin/nvidia-container-runtime"
 	defaultNVIDIARuntimeHookExpecutablePath = "/usr/bin/nvidia-container-runtime-hook"
 
-	defaultContainerdConfigFilePath = "/etc/containerd/config.toml"
-	defaultCrioConfigFilePath       = "/etc/crio/crio.conf"
-	defaultDockerConfigFilePath     = "/etc/docker/daemon.json"
-
 	defaultConfigSource = configSourceFile
 	configSourceCommand = "command"
 	configSourceFile    = "file"
@@ -66,25 +53,14 @@ func NewCommand(logger logger.Interface) *cli.Command {
 // config defines the options that can be set for the CLI through config files,
 // environment variables, or command line config
 type config struct {
-	dryRun         bool
-	runtime        string
-	configFilePath string
-	executablePath string
-	configSource   string
-	mode           string
-	hookFilePath   string
-
-	nvidiaRuntime struct {
-		name         string
-		path         string
-		hookPath     string
-		setAsDefault bool
-	}
-
-	// cdi-specific options
-	cdi struct {
-		enabled bool
-	}
+	EngineConfig
+
+	dryRun                bool
+	executablePath        string
+	configSource          string
+	mode                  string
+	hookFilePath          string
+	nvidiaRuntimeHookPath string
 }
 
 func (m command) build() *cli.Command {
@@ -109,14 +85,14 @@ func (m command) build() *cli.Command {
 			},
 			&cli.StringFlag{
 				Name:        "runtime",
-				Usage:       "the target runtime engine; one of [containerd, crio, docker]",
-				Value:       defaultRuntime,
-				Destination: &config.runtime,
+				Usage:       "the target runtime engine; one of [buildah, buildkit, containerd, crio, docker]",
+				Value:       DefaultRuntime,
+				Destination: &config.Runtime,
 			},
 			&cli.StringFlag{
 				Name:        "config",
 				Usage:       "path to the config file for the target runtime",
-				Destination: &config.configFilePath,
+				Destination: &config.ConfigFilePath,
 			},
 			&cli.StringFlag{
 				Name:        "executable-path",
@@ -142,33 +118,33 @@ func (m command) build() *cli.Command {
 			&cli.StringFlag{
 				Name:        "nvidia-runtime-name",
 				Usage:       "specify the name of the NVIDIA runtime that will be added",
-				Value:       defaultNVIDIARuntimeName,
-				Destination: &config.nvidiaRuntime.name,
+				Value:       DefaultNVIDIARuntimeName,
+				Destination: &config.NVIDIARuntime.Name,
 			},
 			&cli.StringFlag{
 				Name:        "nvidia-runtime-path",
 				Aliases:     []string{"runtime-path"},
 				Usage:       "specify the path to the NVIDIA runtime executable",
-				Value:       defaultNVIDIARuntimeExecutable,
-				Destination: &config.nvidiaRuntime.path,
+				Value:       DefaultNVIDIARuntimeExecutable,
+				Destination: &config.NVIDIARuntime.Path,
 			},
 			&cli.StringFlag{
 				Name:        "nvidia-runtime-hook-path",
 				Usage:       "specify the path to the NVIDIA Container Runtime hook executable",
 				Value:       defaultNVIDIARuntimeHookExpecutablePath,
-				Destination: &config.nvidiaRuntime.hookPath,
+				Destination: &config.nvidiaRuntimeHookPath,
 			},
 			&cli.BoolFlag{
 				Name:        "nvidia-set-as-default",
 				Aliases:     []string{"set-as-default"},
 				Usage:       "set the NVIDIA runtime as the default runtime",
-				Destination: &config.nvidiaRuntime.setAsDefault,
+				Destination: &config.NVIDIARuntime.SetAsDefault,
 			},
 			&cli.BoolFlag{
 				Name:        "cdi.enabled",
 				Aliases:     []string{"cdi.enable", "enable-cdi"},
 				Usage:       "Enable CDI in the configured runtime",
-				Destination: &config.cdi.enabled,
+				Destination: &config.CDIEnabled,
 			},
 		},
 	}
@@ -177,50 +153,38 @@ func (m command) build() *cli.Command {
 }
 
 func (m command) validateFlags(config *config) error {
+	if config.Runtime == "buildah" {
+		// buildah only supports the injection of the NVIDIA Container Runtime
+		// Hook through OCI hooks.
+		if config.mode != "" && config.mode != "oci-hook" {
+			m.logger.Warningf("Ignoring unsupported config mode for %v: %q", config.Runtime, config.mode)
+		}
+		config.mode = "oci-hook"
+	}
 	if config.mode == "oci-hook" {
-		if !filepath.IsAbs(config.nvidiaRuntime.hookPath) {
-			return fmt.Errorf("the NVIDIA runtime hook path %q is not an absolute path", config.nvidiaRuntime.hookPath)
+		if !filepath.IsAbs(config.nvidiaRuntimeHookPath) {
+			return fmt.Errorf("the NVIDIA runtime hook path %q is not an absolute path", config.nvidiaRuntimeHookPath)
 		}
 		return nil
 	}
 	if config.mode != "" && config.mode != "config-file" {
-		m.logger.Warningf("Ignoring unsupported config mode for %v: %q", config.runtime, config.mode)
+		m.logger.Warningf("Ignoring unsupported config mode for %v: %q", config.Runtime, config.mode)
 	}
 	config.mode = "config-file"
 
-	switch config.runtime {
-	case "containerd", "crio", "docker":
-		break
-	default:
-		return fmt.Errorf("unrecognized runtime '%v'", config.runtime)
-	}
-
-	switch config.runtime {
-	case "containerd", "crio":
-		if config.nvidiaRuntime.path == defaultNVIDIARuntimeExecutable {
-			config.nvidiaRuntime.path = defaultNVIDIARuntimeExpecutablePath
-		}
-		if !filepath.IsAbs(config.nvidiaRuntime.path) {
-			return fmt.Errorf("the NVIDIA runtime path %q is not an absolute path", config.nvidiaRuntime.path)
-		}
-	}
-
-	if config.runtime != "containerd" && config.runtime != "docker" {
-		if config.cdi.enabled {
-			m.logger.Warningf("Ignoring cdi.enabled flag for %v", config.runtime)
-		}
-		config.cdi.enabled = false
+	if err := config.EngineConfig.Validate(m.logger); err != nil {
+		return err
 	}
 
-	if config.executablePath != "" && config.runtime == "docker" {
-		m.logger.Warningf("Ignoring executable-path=%q flag for %v", config.executablePath, config.runtime)
+	if config.executablePath != "" && config.Runtime == "docker" {
+		m.logger.Warningf("Ignoring executable-path=%q flag for %v", config.executablePath, config.Runtime)
 		config.executablePath = ""
 	}
 
 	switch config.configSource {
 	case configSourceCommand:
-		if config.runtime == "docker" {
-			m.logger.Warningf("A %v Config Source is not supported for %v; using %v", config.configSource, config.runtime, configSourceFile)
+		if config.Runtime == "buildkit" || config.Runtime == "docker" {
+			m.logger.Warningf("A %v Config Source is not supported for %v; using %v", config.configSource, config.Runtime, configSourceFile)
 			config.configSource = configSourceFile
 		}
 	case configSourceFile:
@@ -229,17 +193,6 @@ func (m command) validateFlags(config *config) error {
 		return fmt.Errorf("unrecognized Config Source: %v", config.configSource)
 	}
 
-	if config.configFilePath == "" {
-		switch config.runtime {
-		case "containerd":
-			config.configFilePath = defaultContainerdConfigFilePath
-		case "crio":
-			config.configFilePath = defaultCrioConfigFilePath
-		case "docker":
-			config.configFilePath = defaultDockerConfigFilePath
-		}
-	}
-
 	return nil
 }
 
@@ -261,43 +214,13 @@ func (m command) configureConfigFile(config *config) error {
 		return err
 	}
 
-	var cfg engine.Interface
-	switch config.runtime {
-	case "containerd":
-		cfg, err = containerd.New(
-			containerd.WithLogger(m.logger),
-			containerd.WithPath(config.configFilePath),
-			containerd.WithConfigSource(configSource),
-		)
-	case "crio":
-		cfg, err = crio.New(
-			crio.WithLogger(m.logger),
-			crio.WithPath(config.configFilePath),
-			crio.WithConfigSource(configSource),
-		)
-	case "docker":
-		cfg, err = docker.New(
-			docker.WithLogger(m.logger),
-			docker.WithPath(config.configFilePath),
-		)
-	default:
-		err = fmt.Errorf("unrecognized runtime '%v'", config.runtime)
-	}
-	if err != nil || cfg == nil {
-		return fmt.Errorf("unable to load config for runtime %v: %v", config.runtime, err)
-	}
-
-	err = cfg.AddRuntime(
-		config.nvidiaRuntime.name,
-		config.nvidiaRuntime.path,
-		config.nvidiaRuntime.setAsDefault,
-	)
+	cfg, err := config.Load(m.logger, configSource)
 	if err != nil {
-		return fmt.Errorf("unable to update config: %v", err)
+		return err
 	}
 
-	if config.cdi.enabled {
-		cfg.EnableCDI()
+	if err := config.Update(cfg); err != nil {
+		return err
 	}
 
 	outputPath := config.getOutputConfigPath()
@@ -312,7 +235,7 @@ func (m command) configureConfigFile(config *config) error {
 		} else {
 			m.logger.Infof("Wrote updated config to %v", outputPath)
 		}
-		m.logger.Infof("It is recommended that %v daemon be restarted.", config.runtime)
+		m.logger.Infof("It is recommended that %v daemon be restarted.", config.Runtime)
 	}
 
 	return nil
@@ -324,7 +247,7 @@ func (c *config) resolveConfigSource() (toml.Loader, error) {
 	case configSourceCommand:
 		return c.getCommandConfigSource(), nil
 	case configSourceFile:
-		return toml.FromFile(c.configFilePath), nil
+		return toml.FromFile(c.ConfigFilePath), nil
 	default:
 		return nil, fmt.Errorf("unrecognized config source: %s", c.configSource)
 	}
@@ -332,7 +255,7 @@ func (c *config) resolveConfigSource() (toml.Loader, error) {
 
 // getConfigSourceCommand returns the default cli command to fetch the current runtime config
 func (c *config) getCommandConfigSource() toml.Loader {
-	switch c.runtime {
+	switch c.Runtime {
 	case "containerd":
 		return containerd.CommandLineSource("", c.executablePath)
 	case "crio":
@@ -346,12 +269,12 @@ func (c *config) getOutputConfigPath() string {
 	if c.dryRun {
 		return ""
 	}
-	return c.configFilePath
+	return c.ConfigFilePath
 }
 
 // configureOCIHook creates and configures the OCI hook for the NVIDIA runtime
 func (m *command) configureOCIHook(config *config) error {
-	err := ocihook.CreateHook(config.hookFilePath, config.nvidiaRuntime.hookPath)
+	err := ocihook.CreateHook(config.hookFilePath, config.nvidiaRuntimeHookPath)
 	if err != nil {
 		return fmt.Errorf("error creating OCI hook: %v", err)
 	}
@@ -0,0 +1,170 @@
+/**
+# Copyright (c) 2022, NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package configure
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/config/engine"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/config/engine/buildkit"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/config/engine/containerd"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/config/engine/crio"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/config/engine/docker"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/config/toml"
+)
+
+const (
+	// DefaultRuntime is the default container engine to configure.
+	DefaultRuntime = "docker"
+
+	// DefaultNVIDIARuntimeName is the default name to use in configs for the NVIDIA Container Runtime
+	DefaultNVIDIARuntimeName = "nvidia"
+	// DefaultNVIDIARuntimeExecutable is the default NVIDIA Container Runtime executable file name
+	DefaultNVIDIARuntimeExecutable     = "nvidia-container-runtime"
+	defaultNVIDIARuntimeExecutablePath = "/usr/bin/nvidia-container-runtime"
+
+	defaultBuildkitConfigFilePath   = "/etc/buildkit/buildkitd.toml"
+	defaultContainerdConfigFilePath = "/etc/containerd/config.toml"
+	defaultCrioConfigFilePath       = "/etc/crio/crio.conf"
+	defaultDockerConfigFilePath     = "/etc/docker/daemon.json"
+)
+
+// An EngineConfig selects the config file of a container engine and defines
+// the NVIDIA runtime entry in this config. This is shared by the commands that
+// update and verify these configs.
+type EngineConfig struct {
+	// Runtime is the container engine; one of buildkit, containerd, crio, or
+	// docker.
+	Runtime        string
+	ConfigFilePath string
+	NVIDIARuntime  NVIDIARuntime
+	// CDIEnabled indicates whether CDI is enabled in the container engine.
+	CDIEnabled bool
+}
+
+// NVIDIARuntime defines the NVIDIA runtime entry in a container engine config.
+type NVIDIARuntime struct {
+	Name         string
+	Path         string
+	SetAsDefault bool
+}
+
+// Validate checks whether the engine config is valid and applies the defaults
+// for the selected container engine.
+func (c *EngineConfig) Validate(logger logger.Interface) error {
+	switch c.Runtime {
+	case "buildkit", "containerd", "crio", "docker":
+		break
+	default:
+		return fmt.Errorf("unrecognized runtime '%v'", c.Runtime)
+	}
+
+	if c.Runtime == "buildkit" && !c.NVIDIARuntime.SetAsDefault {
+		return fmt.Errorf("the buildkitd OCI worker only supports a single runtime; specify --set-as-default")
+	}
+
+	switch c.Runtime {
+	case "buildkit", "containerd", "crio":
+		if c.NVIDIARuntime.Path == DefaultNVIDIARuntimeExecutable {
+			c.NVIDIARuntime.Path = defaultNVIDIARuntimeExecutablePath
+		}
+		if !filepath.IsAbs(c.NVIDIARuntime.Path) {
+			return fmt.Errorf("the NVIDIA runtime path %q is not an absolute path", c.NVIDIARuntime.Path)
+		}
+	}
+
+	if c.Runtime != "buildkit" && c.Runtime != "containerd" && c.Runtime != "crio" && c.Runtime != "docker" {
+		if c.CDIEnabled {
+			logger.Warningf("Ignoring cdi.enabled flag for %v", c.Runtime)
+		}
+		c.CDIEnabled = false
+	}
+
+	if c.ConfigFilePath == "" {
+		switch c.Runtime {
+		case "buildkit":
+			c.ConfigFilePath = defaultBuildkitConfigFilePath
+		case "containerd":
+			c.ConfigFilePath = defaultContainerdConfigFilePath
+		case "crio":
+			c.ConfigFilePath = defaultCrioConfigFilePath
+		case "docker":
+			c.ConfigFilePath = defaultDockerConfigFilePath
+		}
+	}
+
+	return nil
+}
+
+// Load loads the config of the selected container engine from the specified
+// source. The source is ignored for docker, where the config is always read
+// from the config file.
+func (c *EngineConfig) Load(logger logger.Interface, configSource toml.Loader) (engine.Interface, error) {
+	var cfg engine.Interface
+	var err error
+	switch c.Runtime {
+	case "buildkit":
+		cfg, err = buildkit.New(
+			buildkit.WithLogger(logger),
+			buildkit.WithPath(c.ConfigFilePath),
+			buildkit.WithConfigSource(configSource),
+		)
+	case "containerd":
+		cfg, err = containerd.New(
+			containerd.WithLogger(logger),
+			containerd.WithPath(c.ConfigFilePath),
+			containerd.WithConfigSource(configSource),
+		)
+	case "crio":
+		cfg, err = crio.New(
+			crio.WithLogger(logger),
+			crio.WithPath(c.ConfigFilePath),
+			crio.WithConfigSource(configSource),
+		)
+	case "docker":
+		cfg, err = docker.New(
+			docker.WithLogger(logger),
+			docker.WithPath(c.ConfigFilePath),
+		)
+	default:
+		err = fmt.Errorf("unrecognized runtime '%v'", c.Runtime)
+	}
+	if err != nil || cfg == nil {
+		return nil, fmt.Errorf("unable to load config for runtime %v: %v", c.Runtime, err)
+	}
+	return cfg, nil
+}
+
+// Update adds the NVIDIA runtime to the specified container engine config and
+// enables CDI if requested.
+func (c *EngineConfig) Update(cfg engine.Interface) error {
+	err := cfg.AddRuntime(
+		c.NVIDIARuntime.Name,
+		c.NVIDIARuntime.Path,
+		c.NVIDIARuntime.SetAsDefault,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to update config: %v", err)
+	}
+
+	if c.CDIEnabled {
+		cfg.EnableCDI()
+	}
+	return nil
+}
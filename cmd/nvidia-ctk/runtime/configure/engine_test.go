@@ -0,0 +1,99 @@
+/**
+# Copyright (c) 2022, NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package configure
+
+import (
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngineConfigValidate(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	testCases := []struct {
+		description    string
+		config         EngineConfig
+		expectedError  bool
+		expectedConfig EngineConfig
+	}{
+		{
+			description: "unrecognized runtime",
+			config: EngineConfig{
+				Runtime: "unknown",
+			},
+			expectedError: true,
+		},
+		{
+			description: "docker defaults",
+			config: EngineConfig{
+				Runtime:       "docker",
+				NVIDIARuntime: NVIDIARuntime{Name: "nvidia", Path: DefaultNVIDIARuntimeExecutable},
+				CDIEnabled:    true,
+			},
+			expectedConfig: EngineConfig{
+				Runtime:        "docker",
+				ConfigFilePath: "/etc/docker/daemon.json",
+				NVIDIARuntime:  NVIDIARuntime{Name: "nvidia", Path: DefaultNVIDIARuntimeExecutable},
+				CDIEnabled:     true,
+			},
+		},
+		{
+			description: "crio uses absolute path and keeps cdi enabled",
+			config: EngineConfig{
+				Runtime:       "crio",
+				NVIDIARuntime: NVIDIARuntime{Name: "nvidia", Path: DefaultNVIDIARuntimeExecutable},
+				CDIEnabled:    true,
+			},
+			expectedConfig: EngineConfig{
+				Runtime:        "crio",
+				ConfigFilePath: "/etc/crio/crio.conf",
+				NVIDIARuntime:  NVIDIARuntime{Name: "nvidia", Path: "/usr/bin/nvidia-container-runtime"},
+				CDIEnabled:     true,
+			},
+		},
+		{
+			description: "relative containerd runtime path is rejected",
+			config: EngineConfig{
+				Runtime:       "containerd",
+				NVIDIARuntime: NVIDIARuntime{Name: "nvidia", Path: "bin/nvidia-container-runtime"},
+			},
+			expectedError: true,
+		},
+		{
+			description: "buildkit requires set-as-default",
+			config: EngineConfig{
+				Runtime:       "buildkit",
+				NVIDIARuntime: NVIDIARuntime{Name: "nvidia", Path: DefaultNVIDIARuntimeExecutable},
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			err := tc.config.Validate(logger)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.EqualValues(t, tc.expectedConfig, tc.config)
+		})
+	}
+}
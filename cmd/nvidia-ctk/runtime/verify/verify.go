@@ -0,0 +1,261 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package verify
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/urfave/cli/v3"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/runtime/configure"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/injections"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup/root"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/config/engine"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/config/toml"
+)
+
+type command struct {
+	logger logger.Interface
+	output io.Writer
+}
+
+// NewCommand constructs a verify command with the specified logger
+func NewCommand(logger logger.Interface) *cli.Command {
+	c := command{
+		logger: logger,
+		output: os.Stdout,
+	}
+	return c.build()
+}
+
+// config defines the options that can be set for the CLI through config files,
+// environment variables, or command line config
+type config struct {
+	configure.EngineConfig
+
+	containers struct {
+		enabled               bool
+		injectedLibrariesFile string
+		driverRoot            string
+	}
+}
+
+func (m command) build() *cli.Command {
+	config := config{}
+
+	verify := cli.Command{
+		Name: "verify",
+		Usage: "Verify that the specified container engine config contains the expected NVIDIA runtime entries " +
+			"or, if --containers is specified, that the driver libraries injected into running containers match the host driver",
+		Before: func(ctx context.Context, cmd *cli.Command) (context.Context, error) {
+			return ctx, m.validateFlags(&config)
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			return m.run(&config)
+		},
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "runtime",
+				Usage:       "the target runtime engine; one of [buildkit, containerd, crio, docker]",
+				Value:       configure.DefaultRuntime,
+				Destination: &config.Runtime,
+			},
+			&cli.StringFlag{
+				Name:        "config",
+				Usage:       "path to the config file for the target runtime",
+				Destination: &config.ConfigFilePath,
+			},
+			&cli.StringFlag{
+				Name:        "nvidia-runtime-name",
+				Usage:       "specify the name of the expected NVIDIA runtime",
+				Value:       configure.DefaultNVIDIARuntimeName,
+				Destination: &config.NVIDIARuntime.Name,
+			},
+			&cli.StringFlag{
+				Name:        "nvidia-runtime-path",
+				Aliases:     []string{"runtime-path"},
+				Usage:       "specify the expected path to the NVIDIA runtime executable",
+				Value:       configure.DefaultNVIDIARuntimeExecutable,
+				Destination: &config.NVIDIARuntime.Path,
+			},
+			&cli.BoolFlag{
+				Name:        "nvidia-set-as-default",
+				Aliases:     []string{"set-as-default"},
+				Usage:       "expect the NVIDIA runtime to be set as the default runtime",
+				Destination: &config.NVIDIARuntime.SetAsDefault,
+			},
+			&cli.BoolFlag{
+				Name:        "cdi.enabled",
+				Aliases:     []string{"cdi.enable", "enable-cdi"},
+				Usage:       "expect CDI to be enabled in the configured runtime",
+				Destination: &config.CDIEnabled,
+			},
+			&cli.BoolFlag{
+				Name:        "containers",
+				Usage:       "verify that the driver libraries injected into running containers match the host driver instead of verifying the container engine config",
+				Destination: &config.containers.enabled,
+			},
+			&cli.StringFlag{
+				Name:        "injected-libraries-file",
+				Usage:       "the file in which the NVIDIA Container Runtime records the injected driver libraries (see the nvidia-container-runtime.injected-libraries-file config option)",
+				Value:       injections.DefaultFilePath,
+				Destination: &config.containers.injectedLibrariesFile,
+				Sources:     cli.EnvVars("NVIDIA_CTK_INJECTED_LIBRARIES_FILE"),
+			},
+			&cli.StringFlag{
+				Name:        "driver-root",
+				Usage:       "the path to the driver root used to determine the current driver version",
+				Value:       "/",
+				Destination: &config.containers.driverRoot,
+				Sources:     cli.EnvVars("NVIDIA_DRIVER_ROOT", "DRIVER_ROOT"),
+			},
+		},
+	}
+
+	return &verify
+}
+
+func (m command) validateFlags(config *config) error {
+	if config.containers.enabled {
+		return nil
+	}
+	return config.EngineConfig.Validate(m.logger)
+}
+
+// run compares the current container engine config to the config that
+// would be generated by the nvidia-ctk runtime configure command and checks
+// that the NVIDIA runtime executable exists. An error is returned if drift is
+// detected.
+func (m command) run(config *config) error {
+	if config.containers.enabled {
+		return m.verifyContainers(config)
+	}
+
+	current, err := config.Load(m.logger, toml.FromFile(config.ConfigFilePath))
+	if err != nil {
+		return err
+	}
+	expected, err := config.Load(m.logger, toml.FromFile(config.ConfigFilePath))
+	if err != nil {
+		return err
+	}
+	if err := config.Update(expected); err != nil {
+		return fmt.Errorf("unable to construct expected config: %w", err)
+	}
+
+	var driftDetected bool
+	diff, err := getDiff(config.ConfigFilePath, current.String(), expected.String())
+	if err != nil {
+		return fmt.Errorf("failed to compare configs: %w", err)
+	}
+	if diff != "" {
+		fmt.Fprint(m.output, diff)
+		driftDetected = true
+	}
+
+	if err := checkRuntimeBinary(current, config.NVIDIARuntime.Name); err != nil {
+		m.logger.Errorf("%v", err)
+		driftDetected = true
+	}
+
+	if driftDetected {
+		return fmt.Errorf("drift detected in %v config %v", config.Runtime, config.ConfigFilePath)
+	}
+	m.logger.Infof("The %v config %v contains the expected NVIDIA runtime entries", config.Runtime, config.ConfigFilePath)
+	return nil
+}
+
+// verifyContainers checks whether the driver libraries injected into running
+// containers still match the host driver. An error is returned if one or more
+// containers need to be restarted.
+func (m command) verifyContainers(config *config) error {
+	state, err := injections.NewStore(config.containers.injectedLibrariesFile).Load()
+	if err != nil {
+		return err
+	}
+
+	driver := root.New(
+		root.WithLogger(m.logger),
+		root.WithDriverRoot(config.containers.driverRoot),
+	)
+	driverVersion, err := driver.Version()
+	if err != nil {
+		m.logger.Warningf("Could not determine the driver version: %v", err)
+	}
+
+	var bundles []string
+	for bundle := range state.Containers {
+		bundles = append(bundles, bundle)
+	}
+	sort.Strings(bundles)
+
+	var driftDetected int
+	for _, bundle := range bundles {
+		container := state.Containers[bundle]
+		reasons := container.Drift(driverVersion)
+		if len(reasons) == 0 {
+			continue
+		}
+		driftDetected++
+		fmt.Fprintf(m.output, "%v:\n", bundle)
+		for _, reason := range reasons {
+			fmt.Fprintf(m.output, "  %v\n", reason)
+		}
+	}
+
+	if driftDetected > 0 {
+		return fmt.Errorf("%d of %d running containers require a restart to use the current driver", driftDetected, len(bundles))
+	}
+	m.logger.Infof("The driver libraries injected into %d running containers match the host driver", len(bundles))
+	return nil
+}
+
+// checkRuntimeBinary checks that the executable referenced by the specified
+// runtime in the config exists.
+func checkRuntimeBinary(cfg engine.Interface, name string) error {
+	runtimeConfig, err := cfg.GetRuntimeConfig(name)
+	if err != nil {
+		return fmt.Errorf("failed to get config for runtime %q: %w", name, err)
+	}
+	binaryPath := runtimeConfig.GetBinaryPath()
+	if binaryPath == "" {
+		return fmt.Errorf("no executable configured for runtime %q", name)
+	}
+	if _, err := exec.LookPath(binaryPath); err != nil {
+		return fmt.Errorf("executable %q for runtime %q not found: %w", binaryPath, name, err)
+	}
+	return nil
+}
+
+// getDiff returns a unified diff between the current and expected configs.
+// An empty string is returned if there is no difference.
+func getDiff(path string, current string, expected string) (string, error) {
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(current),
+		B:        difflib.SplitLines(expected),
+		FromFile: path,
+		ToFile:   path + " (expected)",
+		Context:  3,
+	})
+}
@@ -0,0 +1,174 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package verify
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/injections"
+)
+
+func TestVerifyDocker(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	runtimePath := filepath.Join(t.TempDir(), "nvidia-container-runtime")
+	require.NoError(t, os.WriteFile(runtimePath, []byte("#!/bin/sh\n"), 0755))
+
+	testCases := []struct {
+		description    string
+		daemonJSON     string
+		runtimePath    string
+		setAsDefault   bool
+		expectedError  bool
+		expectedOutput string
+	}{
+		{
+			description: "configured runtime has no drift",
+			daemonJSON: `{
+    "runtimes": {
+        "nvidia": {
+            "args": [],
+            "path": "` + runtimePath + `"
+        }
+    }
+}`,
+		},
+		{
+			description: "missing default runtime is detected",
+			daemonJSON: `{
+    "runtimes": {
+        "nvidia": {
+            "args": [],
+            "path": "` + runtimePath + `"
+        }
+    }
+}`,
+			setAsDefault:  true,
+			expectedError: true,
+			expectedOutput: `+    "default-runtime": "nvidia",
+`,
+		},
+		{
+			description:   "missing runtime is detected",
+			daemonJSON:    `{}`,
+			expectedError: true,
+			expectedOutput: `+    "runtimes": {
+`,
+		},
+		{
+			description: "missing executable is detected",
+			daemonJSON: `{
+    "runtimes": {
+        "nvidia": {
+            "args": [],
+            "path": "/not/a/valid/path"
+        }
+    }
+}`,
+			runtimePath:   "/not/a/valid/path",
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			configFilePath := filepath.Join(t.TempDir(), "daemon.json")
+			require.NoError(t, os.WriteFile(configFilePath, []byte(tc.daemonJSON), 0644))
+
+			cfg := &config{}
+			cfg.Runtime = "docker"
+			cfg.ConfigFilePath = configFilePath
+			cfg.NVIDIARuntime.Name = "nvidia"
+			cfg.NVIDIARuntime.Path = runtimePath
+			if tc.runtimePath != "" {
+				cfg.NVIDIARuntime.Path = tc.runtimePath
+			}
+			cfg.NVIDIARuntime.SetAsDefault = tc.setAsDefault
+
+			output := &bytes.Buffer{}
+			c := command{
+				logger: logger,
+				output: output,
+			}
+			err := c.run(cfg)
+			if tc.expectedError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Contains(t, output.String(), tc.expectedOutput)
+		})
+	}
+}
+
+func TestVerifyContainers(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	libraryDir := t.TempDir()
+	library := filepath.Join(libraryDir, "libcuda.so.550.54.15")
+	require.NoError(t, os.WriteFile(library, nil, 0644))
+
+	testCases := []struct {
+		description    string
+		libraries      []string
+		expectedError  bool
+		expectedOutput string
+	}{
+		{
+			description: "existing libraries have no drift",
+			libraries:   []string{library},
+		},
+		{
+			description:    "removed library is detected",
+			libraries:      []string{library, filepath.Join(libraryDir, "libnvidia-ml.so.550.54.15")},
+			expectedError:  true,
+			expectedOutput: "libnvidia-ml.so.550.54.15 no longer exists on the host",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			bundle := t.TempDir()
+			injectedLibrariesFile := filepath.Join(t.TempDir(), "injected-libraries.json")
+			require.NoError(t, injections.NewStore(injectedLibrariesFile).RecordContainer(bundle, "", tc.libraries))
+
+			cfg := &config{}
+			cfg.containers.enabled = true
+			cfg.containers.injectedLibrariesFile = injectedLibrariesFile
+			cfg.containers.driverRoot = t.TempDir()
+
+			output := &bytes.Buffer{}
+			c := command{
+				logger: logger,
+				output: output,
+			}
+			err := c.run(cfg)
+			if tc.expectedError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Contains(t, output.String(), tc.expectedOutput)
+		})
+	}
+}
@@ -0,0 +1,181 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package serve
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+
+	ociSpecs "github.com/opencontainers/runtime-spec/specs-go"
+	"tags.cncf.io/container-device-interface/specs-go"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+)
+
+const (
+	// specPath is the path on which an OCI spec is accepted and the
+	// modified OCI spec is returned.
+	specPath = "/v1/spec"
+	// cdiSpecPath is the path on which a list of devices is accepted and the
+	// CDI spec containing the edits for these devices is returned.
+	cdiSpecPath = "/v1/cdi-spec"
+
+	maxRequestSize = 16 << 20
+)
+
+// CDISpecRequest is the request accepted on the /v1/cdi-spec path.
+type CDISpecRequest struct {
+	// Devices are the device identifiers (e.g. indices, UUIDs, or all) for
+	// which CDI edits are requested.
+	Devices []string `json:"devices"`
+}
+
+type modifySpecFunc func(*config.Config, *ociSpecs.Spec, bool) error
+
+type getCDISpecFunc func(*config.Config, ...string) (*specs.Spec, error)
+
+type api struct {
+	logger     logger.Interface
+	cfg        *config.Config
+	modifySpec modifySpecFunc
+	getCDISpec getCDISpecFunc
+
+	// Requests are serialized since the underlying discovery (e.g. NVML) is
+	// not guaranteed to be safe for concurrent use.
+	sync.Mutex
+}
+
+// newAPI creates the HTTP handler for the API.
+func newAPI(logger logger.Interface, cfg *config.Config, modifySpec modifySpecFunc, getCDISpec getCDISpecFunc) http.Handler {
+	a := &api{
+		logger:     logger,
+		cfg:        cfg,
+		modifySpec: modifySpec,
+		getCDISpec: getCDISpec,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(specPath, a.handleSpec)
+	mux.HandleFunc(cdiSpecPath, a.handleCDISpec)
+	return mux
+}
+
+func (a *api) handleSpec(w http.ResponseWriter, r *http.Request) {
+	var spec ociSpecs.Spec
+	if !a.decodeRequest(w, r, &spec) {
+		return
+	}
+	rootless, err := parseRootless(r)
+	if err != nil {
+		a.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	a.Lock()
+	defer a.Unlock()
+	if err := a.modifySpec(a.config(), &spec, rootless); err != nil {
+		a.writeError(w, http.StatusInternalServerError, fmt.Errorf("failed to modify OCI spec: %w", err))
+		return
+	}
+	a.writeResponse(w, &spec)
+}
+
+func (a *api) handleCDISpec(w http.ResponseWriter, r *http.Request) {
+	var request CDISpecRequest
+	if !a.decodeRequest(w, r, &request) {
+		return
+	}
+	if len(request.Devices) == 0 {
+		a.writeError(w, http.StatusBadRequest, fmt.Errorf("no devices requested"))
+		return
+	}
+
+	a.Lock()
+	defer a.Unlock()
+	cdiSpec, err := a.getCDISpec(a.config(), request.Devices...)
+	if err != nil {
+		a.writeError(w, http.StatusInternalServerError, fmt.Errorf("failed to generate CDI spec: %w", err))
+		return
+	}
+	a.writeResponse(w, cdiSpec)
+}
+
+// config returns a copy of the config for a single request. This is required
+// since the runtime mode resolution updates the config that is passed.
+// Since requests are handled by a long-lived process, the settings that cause
+// side effects on the host are disabled. This means that modifier plugins are
+// not run, kernel modules are not loaded, and the device node helper is not
+// used to create device nodes.
+func (a *api) config() *config.Config {
+	cfg := *a.cfg
+	cfg.NVIDIAContainerRuntimeConfig.ModifierPlugins.Pre = nil
+	cfg.NVIDIAContainerRuntimeConfig.ModifierPlugins.Post = nil
+	cfg.NVIDIAContainerRuntimeConfig.Modes.CDI.LoadKernelModules = false
+	cfg.NVIDIAContainerRuntimeConfig.Modes.CDI.DeviceNodeHelperSocket = ""
+	return &cfg
+}
+
+// parseRootless returns the value of the rootless query parameter of the
+// request. This indicates whether the container is created by a rootless
+// container engine and is false if not specified.
+func parseRootless(r *http.Request) (bool, error) {
+	value := r.URL.Query().Get("rootless")
+	if value == "" {
+		return false, nil
+	}
+	rootless, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid rootless value %q: %w", value, err)
+	}
+	return rootless, nil
+}
+
+// decodeRequest decodes the JSON body of a POST request into the specified
+// value. If the request is invalid an error response is written and false is
+// returned.
+func (a *api) decodeRequest(w http.ResponseWriter, r *http.Request, v any) bool {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		a.writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %v not allowed", r.Method))
+		return false
+	}
+	decoder := json.NewDecoder(io.LimitReader(r.Body, maxRequestSize))
+	if err := decoder.Decode(v); err != nil {
+		a.writeError(w, http.StatusBadRequest, fmt.Errorf("failed to decode request: %w", err))
+		return false
+	}
+	return true
+}
+
+func (a *api) writeResponse(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		a.logger.Warningf("Failed to write response: %v", err)
+	}
+}
+
+func (a *api) writeError(w http.ResponseWriter, status int, err error) {
+	a.logger.Warningf("%v", err)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
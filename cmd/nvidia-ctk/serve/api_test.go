@@ -0,0 +1,227 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package serve
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	ociSpecs "github.com/opencontainers/runtime-spec/specs-go"
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+	"tags.cncf.io/container-device-interface/specs-go"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
+)
+
+func TestAPI(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	modifySpec := func(cfg *config.Config, spec *ociSpecs.Spec, rootless bool) error {
+		if spec.Process == nil {
+			return fmt.Errorf("missing process")
+		}
+		if err := assertNoSideEffects(cfg); err != nil {
+			return err
+		}
+		// Updates to the config must not leak between requests.
+		cfg.NVIDIAContainerRuntimeConfig.Mode = "cdi"
+		spec.Process.Env = append(spec.Process.Env, "MODIFIED=true")
+		if rootless {
+			spec.Process.Env = append(spec.Process.Env, "ROOTLESS=true")
+		}
+		return nil
+	}
+	getCDISpec := func(cfg *config.Config, devices ...string) (*specs.Spec, error) {
+		if err := assertNoSideEffects(cfg); err != nil {
+			return nil, err
+		}
+		cdiSpec := &specs.Spec{
+			Version: "0.5.0",
+			Kind:    "runtime.nvidia.com/gpu",
+		}
+		for _, device := range devices {
+			if device == "missing" {
+				return nil, fmt.Errorf("unknown device %v", device)
+			}
+			cdiSpec.Devices = append(cdiSpec.Devices, specs.Device{Name: device})
+		}
+		return cdiSpec, nil
+	}
+
+	testCases := []struct {
+		description      string
+		method           string
+		path             string
+		body             string
+		expectedStatus   int
+		expectedResponse string
+	}{
+		{
+			description:      "spec is modified",
+			method:           http.MethodPost,
+			path:             specPath,
+			body:             `{"ociVersion":"1.0.0","process":{"env":["FOO=bar"]}}`,
+			expectedStatus:   http.StatusOK,
+			expectedResponse: `{"ociVersion":"1.0.0","process":{"user":{"uid":0,"gid":0},"env":["FOO=bar","MODIFIED=true"],"cwd":""}}`,
+		},
+		{
+			description:      "rootless is taken from the request",
+			method:           http.MethodPost,
+			path:             specPath + "?rootless=true",
+			body:             `{"ociVersion":"1.0.0","process":{"env":["FOO=bar"]}}`,
+			expectedStatus:   http.StatusOK,
+			expectedResponse: `{"ociVersion":"1.0.0","process":{"user":{"uid":0,"gid":0},"env":["FOO=bar","MODIFIED=true","ROOTLESS=true"],"cwd":""}}`,
+		},
+		{
+			description:    "invalid rootless is rejected",
+			method:         http.MethodPost,
+			path:           specPath + "?rootless=maybe",
+			body:           `{"ociVersion":"1.0.0","process":{"env":["FOO=bar"]}}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			description:    "modification error is returned",
+			method:         http.MethodPost,
+			path:           specPath,
+			body:           `{"ociVersion":"1.0.0"}`,
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			description:    "invalid spec is rejected",
+			method:         http.MethodPost,
+			path:           specPath,
+			body:           `{"ociVersion":`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			description:    "get is not allowed",
+			method:         http.MethodGet,
+			path:           specPath,
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			description:      "cdi spec is returned for devices",
+			method:           http.MethodPost,
+			path:             cdiSpecPath,
+			body:             `{"devices":["0","all"]}`,
+			expectedStatus:   http.StatusOK,
+			expectedResponse: `{"cdiVersion":"0.5.0","kind":"runtime.nvidia.com/gpu","devices":[{"name":"0","containerEdits":{}},{"name":"all","containerEdits":{}}],"containerEdits":{}}`,
+		},
+		{
+			description:    "no devices is rejected",
+			method:         http.MethodPost,
+			path:           cdiSpecPath,
+			body:           `{"devices":[]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			description:    "cdi spec error is returned",
+			method:         http.MethodPost,
+			path:           cdiSpecPath,
+			body:           `{"devices":["missing"]}`,
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.NVIDIAContainerRuntimeConfig.Mode = "auto"
+			cfg.NVIDIAContainerRuntimeConfig.ModifierPlugins.Pre = []string{"/usr/local/bin/plugin"}
+			cfg.NVIDIAContainerRuntimeConfig.Modes.CDI.LoadKernelModules = true
+			cfg.NVIDIAContainerRuntimeConfig.Modes.CDI.DeviceNodeHelperSocket = "/run/nvidia-container-toolkit/device-node-helper.sock"
+
+			handler := newAPI(logger, cfg, modifySpec, getCDISpec)
+
+			request := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+			require.Equal(t, "auto", cfg.NVIDIAContainerRuntimeConfig.Mode)
+			if tc.expectedStatus != http.StatusOK {
+				var response map[string]string
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.NotEmpty(t, response["error"])
+				return
+			}
+			require.JSONEq(t, tc.expectedResponse, recorder.Body.String())
+		})
+	}
+}
+
+// assertNoSideEffects checks that the settings that cause side effects on the
+// host are disabled for API requests.
+func assertNoSideEffects(cfg *config.Config) error {
+	if len(cfg.NVIDIAContainerRuntimeConfig.ModifierPlugins.Pre) > 0 {
+		return fmt.Errorf("unexpected modifier plugins")
+	}
+	if cfg.NVIDIAContainerRuntimeConfig.Modes.CDI.LoadKernelModules {
+		return fmt.Errorf("unexpected kernel module loading")
+	}
+	if cfg.NVIDIAContainerRuntimeConfig.Modes.CDI.DeviceNodeHelperSocket != "" {
+		return fmt.Errorf("unexpected device node helper")
+	}
+	return nil
+}
+
+func TestParseAddress(t *testing.T) {
+	testCases := []struct {
+		description   string
+		address       string
+		expectedPath  string
+		expectedError bool
+	}{
+		{
+			description:  "unix socket",
+			address:      "unix:///run/nvidia-container-toolkit/api.sock",
+			expectedPath: "/run/nvidia-container-toolkit/api.sock",
+		},
+		{
+			description:   "tcp address is not supported",
+			address:       "tcp://:9402",
+			expectedError: true,
+		},
+		{
+			description:   "address without scheme is not supported",
+			address:       "/run/nvidia-container-toolkit/api.sock",
+			expectedError: true,
+		},
+		{
+			description:   "empty address",
+			address:       "unix://",
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			path, err := parseAddress(tc.address)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedPath, path)
+		})
+	}
+}
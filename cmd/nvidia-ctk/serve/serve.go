@@ -0,0 +1,190 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package serve
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
+
+	ociSpecs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/urfave/cli/v3"
+	"tags.cncf.io/container-device-interface/specs-go"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/listener"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup/root"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/modifier"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/runtime"
+)
+
+const (
+	defaultAddress = "unix:///run/nvidia-container-toolkit/api.sock"
+)
+
+type command struct {
+	logger         logger.Interface
+	configFilePath *string
+}
+
+type options struct {
+	address string
+	mode    string
+}
+
+// NewCommand constructs a serve command with the specified logger
+func NewCommand(logger logger.Interface, configFilePath *string) *cli.Command {
+	c := command{
+		logger:         logger,
+		configFilePath: configFilePath,
+	}
+	return c.build()
+}
+
+// build the serve command
+func (m command) build() *cli.Command {
+	opts := options{}
+
+	c := cli.Command{
+		Name:  "serve",
+		Usage: "Serve a local API that returns the modifications that the NVIDIA Container Runtime makes to OCI specs",
+		Description: "Unlike the NVIDIA Container Runtime, the API does not apply modifications that require the container " +
+			"bundle or the low-level runtime and does not run modifier plugins, load kernel modules, or create device nodes. " +
+			"Since the server is not the container engine, rootless containers are indicated using the rootless=true query parameter.",
+		Before: func(ctx context.Context, cmd *cli.Command) (context.Context, error) {
+			return ctx, m.validateFlags(&opts)
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			return m.run(ctx, &opts)
+		},
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "address",
+				Usage:       "The unix socket to serve the API on (unix:///path/to/socket).",
+				Value:       defaultAddress,
+				Destination: &opts.address,
+				Sources:     cli.EnvVars("NVIDIA_CTK_SERVE_ADDRESS"),
+			},
+			&cli.StringFlag{
+				Name:        "mode",
+				Usage:       "Override the nvidia-container-runtime.mode specified in the config.",
+				Destination: &opts.mode,
+				Sources:     cli.EnvVars("NVIDIA_CTK_SERVE_MODE"),
+			},
+		},
+	}
+
+	return &c
+}
+
+func (m command) validateFlags(opts *options) error {
+	if _, err := parseAddress(opts.address); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m command) run(ctx context.Context, opts *options) error {
+	cfg, err := m.loadConfig()
+	if err != nil {
+		return err
+	}
+	if opts.mode != "" {
+		cfg.NVIDIAContainerRuntimeConfig.Mode = opts.mode
+	}
+	//nolint:staticcheck  // TODO(elezar): We should swith the nvidia-container-runtime from using nvidia-ctk to using nvidia-cdi-hook.
+	cfg.NVIDIACTKConfig.Path = config.ResolveNVIDIACTKPath(&logger.NullLogger{}, cfg.NVIDIACTKConfig.Path)
+	cfg.NVIDIAContainerRuntimeHookConfig.Path = config.ResolveNVIDIAContainerRuntimeHookPath(&logger.NullLogger{}, cfg.NVIDIAContainerRuntimeHookConfig.Path)
+
+	driver := root.New(
+		root.WithLogger(m.logger),
+		root.WithDriverRoot(cfg.NVIDIAContainerCLIConfig.Root),
+	)
+
+	handler := newAPI(
+		m.logger,
+		cfg,
+		func(cfg *config.Config, spec *ociSpecs.Spec, rootless bool) error {
+			return runtime.ModifySpec(m.logger, cfg, spec, driver, rootless)
+		},
+		func(cfg *config.Config, devices ...string) (*specs.Spec, error) {
+			return modifier.GetAutomaticCDISpec(m.logger, cfg, devices...)
+		},
+	)
+
+	// The socket is only accessible by the owner.
+	l, err := listener.Listen(opts.address, 0600)
+	if err != nil {
+		return err
+	}
+
+	server := &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	m.logger.Infof("Serving API on %v", opts.address)
+	if err := server.Serve(l); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("failed to serve API: %w", err)
+	}
+	return nil
+}
+
+// loadConfig loads the toolkit config from the specified config file or the
+// default location.
+func (m command) loadConfig() (*config.Config, error) {
+	configFilePath := config.GetConfigFilePath()
+	if m.configFilePath != nil && *m.configFilePath != "" {
+		configFilePath = *m.configFilePath
+	}
+	configToml, err := config.New(
+		config.WithConfigFile(configFilePath),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	return configToml.Config()
+}
+
+// parseAddress returns the path of the unix socket for the specified address.
+// Since the API allows the modifications for arbitrary specs to be queried,
+// only local unix sockets are supported.
+func parseAddress(address string) (string, error) {
+	network, path, err := listener.ParseAddress(address)
+	if err != nil {
+		return "", err
+	}
+	if network != "unix" {
+		return "", fmt.Errorf("unsupported address %q: only unix sockets are supported", address)
+	}
+	return path, nil
+}
@@ -40,6 +40,8 @@ type options struct {
 	control bool
 
 	loadKernelModules bool
+
+	helperSocket string
 }
 
 // NewCommand constructs a command sub-command with the specified logger
@@ -91,6 +93,12 @@ func (m command) build() *cli.Command {
 				Usage:       "load the NVIDIA Kernel Modules before creating devices nodes",
 				Destination: &opts.loadKernelModules,
 			},
+			&cli.StringFlag{
+				Name:        "helper-socket",
+				Usage:       "request the creation of device nodes from the device node helper listening on the specified socket instead of creating them directly. In this case the dev-root of the helper is used.",
+				Destination: &opts.helperSocket,
+				Sources:     cli.EnvVars("NVIDIA_CTK_DEVICE_NODE_HELPER_SOCKET"),
+			},
 			&cli.BoolFlag{
 				Name:        "dry-run",
 				Usage:       "if set, the command will not perform any operations",
@@ -129,6 +137,7 @@ func (m command) run(opts *options) error {
 			nvdevices.WithLogger(m.logger),
 			nvdevices.WithDryRun(opts.dryRun),
 			nvdevices.WithDevRoot(opts.devRoot),
+			nvdevices.WithHelperSocket(opts.helperSocket),
 		)
 		if err != nil {
 			return err
@@ -0,0 +1,141 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package devicenodehelper
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/listener"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/system/nvdevices"
+)
+
+// listenFDsStart is the first file descriptor passed by systemd socket
+// activation.
+const listenFDsStart = 3
+
+type command struct {
+	logger logger.Interface
+}
+
+type options struct {
+	socket  string
+	devRoot string
+	dryRun  bool
+}
+
+// NewCommand constructs a device-node-helper sub-command with the specified logger
+func NewCommand(logger logger.Interface) *cli.Command {
+	c := command{
+		logger: logger,
+	}
+	return c.build()
+}
+
+// build
+func (m command) build() *cli.Command {
+	opts := options{}
+
+	c := cli.Command{
+		Name:  "device-node-helper",
+		Usage: "Run a privileged helper that creates NVIDIA device nodes on behalf of unprivileged clients",
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			return m.run(ctx, &opts)
+		},
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "socket",
+				Usage:       "the path to the unix socket on which requests are accepted. This is ignored if the socket is passed by systemd socket activation.",
+				Value:       nvdevices.DefaultHelperSocketPath,
+				Destination: &opts.socket,
+				Sources:     cli.EnvVars("NVIDIA_CTK_DEVICE_NODE_HELPER_SOCKET"),
+			},
+			&cli.StringFlag{
+				Name:        "dev-root",
+				Usage:       "specify the root where `/dev` is located.",
+				Value:       "/",
+				Destination: &opts.devRoot,
+				Sources:     cli.EnvVars("NVIDIA_DEV_ROOT", "DEV_ROOT"),
+			},
+			&cli.BoolFlag{
+				Name:        "dry-run",
+				Usage:       "if set, the helper will not create any device nodes",
+				Destination: &opts.dryRun,
+				Sources:     cli.EnvVars("DRY_RUN"),
+			},
+		},
+	}
+
+	return &c
+}
+
+func (m command) run(ctx context.Context, opts *options) error {
+	devices, err := nvdevices.New(
+		nvdevices.WithLogger(m.logger),
+		nvdevices.WithDryRun(opts.dryRun),
+		nvdevices.WithDevRoot(opts.devRoot),
+	)
+	if err != nil {
+		return err
+	}
+
+	listener, err := m.getListener(opts.socket)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+
+	m.logger.Infof("Creating device nodes at %v on request", opts.devRoot)
+	return devices.ServeHelper(listener)
+}
+
+// getListener returns the listener passed by systemd socket activation or
+// creates a listener on the specified socket if none was passed.
+func (m command) getListener(socket string) (net.Listener, error) {
+	if pid, _ := strconv.Atoi(os.Getenv("LISTEN_PID")); pid == os.Getpid() {
+		if fds, _ := strconv.Atoi(os.Getenv("LISTEN_FDS")); fds >= 1 {
+			m.logger.Infof("Using socket passed by systemd")
+			listener, err := net.FileListener(os.NewFile(listenFDsStart, "systemd-socket"))
+			if err != nil {
+				return nil, fmt.Errorf("failed to use systemd socket: %w", err)
+			}
+			return listener, nil
+		}
+	}
+
+	// Access to the helper is controlled by the permissions of the socket.
+	l, err := listener.Listen("unix://"+socket, 0660)
+	if err != nil {
+		return nil, err
+	}
+	m.logger.Infof("Listening on %v", socket)
+	return l, nil
+}
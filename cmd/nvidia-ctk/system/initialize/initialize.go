@@ -0,0 +1,233 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package initialize
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+
+	"github.com/urfave/cli/v3"
+	"tags.cncf.io/container-device-interface/pkg/cdi"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/cdi/generate"
+	"github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/runtime/configure"
+	"github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/runtime/verify"
+	devchar "github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/system/create-dev-char-symlinks"
+	devicenodes "github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/system/create-device-nodes"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+)
+
+const (
+	defaultCDIOutput = "/etc/cdi/nvidia.yaml"
+	runtimeNone      = "none"
+)
+
+type command struct {
+	logger         logger.Interface
+	configFilePath *string
+}
+
+type options struct {
+	driverRoot   string
+	cdiOutput    string
+	runtime      string
+	cdiEnabled   bool
+	setAsDefault bool
+}
+
+// A step is a single stage of the node initialization. Each step runs an
+// existing nvidia-ctk command with the specified arguments.
+type step struct {
+	name    string
+	command *cli.Command
+	args    []string
+}
+
+// NewCommand constructs a system initialize command with the specified logger
+func NewCommand(logger logger.Interface, configFilePath *string) *cli.Command {
+	c := command{
+		logger:         logger,
+		configFilePath: configFilePath,
+	}
+	return c.build()
+}
+
+// build creates the CLI command
+func (m command) build() *cli.Command {
+	opts := options{}
+
+	c := cli.Command{
+		Name:  "initialize",
+		Usage: "Prepare a node for running GPU containers by creating device nodes and /dev/char symlinks, generating a CDI specification, and configuring the container engine",
+		Before: func(ctx context.Context, cmd *cli.Command) (context.Context, error) {
+			return ctx, m.validateFlags(&opts)
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			return m.run(ctx, &opts)
+		},
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "driver-root",
+				Usage:       "the path to the driver root",
+				Value:       "/",
+				Destination: &opts.driverRoot,
+				Sources:     cli.EnvVars("NVIDIA_DRIVER_ROOT", "DRIVER_ROOT"),
+			},
+			&cli.StringFlag{
+				Name:        "cdi-output",
+				Usage:       "the file to write the generated CDI specification to",
+				Value:       defaultCDIOutput,
+				Destination: &opts.cdiOutput,
+			},
+			&cli.StringFlag{
+				Name:        "runtime",
+				Usage:       "the container engine to configure; one of [containerd, crio, docker, none]. If this is none, no container engine is configured",
+				Value:       "docker",
+				Destination: &opts.runtime,
+			},
+			&cli.BoolFlag{
+				Name:        "cdi.enabled",
+				Aliases:     []string{"enable-cdi"},
+				Usage:       "enable CDI in the configured container engine",
+				Destination: &opts.cdiEnabled,
+			},
+			&cli.BoolFlag{
+				Name:        "nvidia-set-as-default",
+				Aliases:     []string{"set-as-default"},
+				Usage:       "set the NVIDIA runtime as the default runtime of the configured container engine",
+				Destination: &opts.setAsDefault,
+			},
+		},
+	}
+
+	return &c
+}
+
+func (m command) validateFlags(opts *options) error {
+	if !slices.Contains([]string{"containerd", "crio", "docker", runtimeNone}, opts.runtime) {
+		return fmt.Errorf("unsupported runtime %q", opts.runtime)
+	}
+	if !filepath.IsAbs(opts.cdiOutput) {
+		return fmt.Errorf("the CDI output %q is not an absolute path", opts.cdiOutput)
+	}
+	return nil
+}
+
+func (m command) run(ctx context.Context, opts *options) error {
+	for i, s := range m.getSteps(opts) {
+		m.logger.Infof("Step %d: %v", i+1, s.name)
+		if err := s.command.Run(ctx, append([]string{s.command.Name}, s.args...)); err != nil {
+			return fmt.Errorf("failed to %v: %w", s.name, err)
+		}
+	}
+
+	m.logger.Infof("Step: verify the generated CDI specification")
+	if err := verifyCDISpec(opts.cdiOutput); err != nil {
+		return fmt.Errorf("failed to verify the generated CDI specification: %w", err)
+	}
+
+	if opts.runtime != runtimeNone {
+		m.logger.Infof("The %v configuration was updated; restart %v to apply the changes", opts.runtime, opts.runtime)
+	}
+	m.logger.Infof("Node initialized successfully")
+	return nil
+}
+
+// getSteps returns the steps that are run to initialize the node. The
+// container engine config is verified after it has been updated.
+func (m command) getSteps(opts *options) []step {
+	steps := []step{
+		{
+			name:    "create device nodes",
+			command: devicenodes.NewCommand(m.logger),
+			args: []string{
+				"--root=" + opts.driverRoot,
+				"--control-devices",
+				"--load-kernel-modules",
+			},
+		},
+		{
+			name:    "create /dev/char symlinks",
+			command: devchar.NewCommand(m.logger),
+			args: []string{
+				"--driver-root=" + opts.driverRoot,
+				"--create-all",
+			},
+		},
+		{
+			name:    "generate the CDI specification",
+			command: generate.NewCommand(m.logger, m.configFilePath),
+			args: []string{
+				"--driver-root=" + opts.driverRoot,
+				"--output=" + opts.cdiOutput,
+			},
+		},
+	}
+	if opts.runtime == runtimeNone {
+		return steps
+	}
+
+	var runtimeArgs []string
+	runtimeArgs = append(runtimeArgs, "--runtime="+opts.runtime)
+	if opts.cdiEnabled {
+		runtimeArgs = append(runtimeArgs, "--cdi.enabled")
+	}
+	if opts.setAsDefault {
+		runtimeArgs = append(runtimeArgs, "--nvidia-set-as-default")
+	}
+
+	return append(steps,
+		step{
+			name:    "configure the container engine",
+			command: configure.NewCommand(m.logger),
+			args:    runtimeArgs,
+		},
+		step{
+			name:    "verify the container engine config",
+			command: verify.NewCommand(m.logger),
+			args:    runtimeArgs,
+		},
+	)
+}
+
+// verifyCDISpec checks that the specified CDI specification can be loaded
+// and that it defines at least one device.
+func verifyCDISpec(path string) error {
+	cache, err := cdi.NewCache(
+		cdi.WithAutoRefresh(false),
+		cdi.WithSpecDirs(filepath.Dir(path)),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create CDI cache: %w", err)
+	}
+	_ = cache.Refresh()
+
+	for p, errs := range cache.GetErrors() {
+		if p == path && len(errs) > 0 {
+			return fmt.Errorf("invalid CDI specification: %w", errs[0])
+		}
+	}
+
+	for _, spec := range cache.GetVendorSpecs("nvidia.com") {
+		if spec.GetPath() == path && len(spec.Devices) > 0 {
+			return nil
+		}
+	}
+	return fmt.Errorf("no devices defined in %v", path)
+}
@@ -0,0 +1,127 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package initialize
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSteps(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	type expectedStep struct {
+		command string
+		args    []string
+	}
+
+	baseSteps := []expectedStep{
+		{command: "create-device-nodes", args: []string{"--root=/driver-root", "--control-devices", "--load-kernel-modules"}},
+		{command: "create-dev-char-symlinks", args: []string{"--driver-root=/driver-root", "--create-all"}},
+		{command: "generate", args: []string{"--driver-root=/driver-root", "--output=/etc/cdi/nvidia.yaml"}},
+	}
+
+	testCases := []struct {
+		description string
+		opts        options
+		expected    []expectedStep
+	}{
+		{
+			description: "no runtime",
+			opts: options{
+				driverRoot: "/driver-root",
+				cdiOutput:  "/etc/cdi/nvidia.yaml",
+				runtime:    runtimeNone,
+			},
+			expected: baseSteps,
+		},
+		{
+			description: "runtime is configured and verified",
+			opts: options{
+				driverRoot:   "/driver-root",
+				cdiOutput:    "/etc/cdi/nvidia.yaml",
+				runtime:      "containerd",
+				cdiEnabled:   true,
+				setAsDefault: true,
+			},
+			expected: append(baseSteps,
+				expectedStep{command: "configure", args: []string{"--runtime=containerd", "--cdi.enabled", "--nvidia-set-as-default"}},
+				expectedStep{command: "verify", args: []string{"--runtime=containerd", "--cdi.enabled", "--nvidia-set-as-default"}},
+			),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			c := command{
+				logger: logger,
+			}
+
+			var steps []expectedStep
+			for _, s := range c.getSteps(&tc.opts) {
+				steps = append(steps, expectedStep{command: s.command.Name, args: s.args})
+			}
+			require.EqualValues(t, tc.expected, steps)
+		})
+	}
+}
+
+func TestVerifyCDISpec(t *testing.T) {
+	testCases := []struct {
+		description   string
+		contents      string
+		expectedError bool
+	}{
+		{
+			description: "valid spec",
+			contents: `cdiVersion: 0.5.0
+kind: nvidia.com/gpu
+devices:
+- name: all
+  containerEdits:
+    deviceNodes:
+    - path: /dev/nvidia0
+`,
+		},
+		{
+			description: "spec without devices",
+			contents: `cdiVersion: 0.5.0
+kind: nvidia.com/gpu
+devices: []
+`,
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "nvidia.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tc.contents), 0600))
+
+			err := verifyCDISpec(path)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -21,17 +21,21 @@ import (
 
 	devchar "github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/system/create-dev-char-symlinks"
 	devicenodes "github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/system/create-device-nodes"
+	devicenodehelper "github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/system/device-node-helper"
+	"github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/system/initialize"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
 )
 
 type command struct {
-	logger logger.Interface
+	logger         logger.Interface
+	configFilePath *string
 }
 
 // NewCommand constructs a runtime command with the specified logger
-func NewCommand(logger logger.Interface) *cli.Command {
+func NewCommand(logger logger.Interface, configFilePath *string) *cli.Command {
 	c := command{
-		logger: logger,
+		logger:         logger,
+		configFilePath: configFilePath,
 	}
 	return c.build()
 }
@@ -44,6 +48,8 @@ func (m command) build() *cli.Command {
 		Commands: []*cli.Command{
 			devchar.NewCommand(m.logger),
 			devicenodes.NewCommand(m.logger),
+			devicenodehelper.NewCommand(m.logger),
+			initialize.NewCommand(m.logger, m.configFilePath),
 		},
 	}
 
@@ -0,0 +1,183 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package send
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/info"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup/root"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/telemetry"
+)
+
+const defaultTimeout = 10 * time.Second
+
+type command struct {
+	logger         logger.Interface
+	configFilePath *string
+}
+
+type options struct {
+	endpoint          string
+	telemetryFilePath string
+	driverRoot        string
+	timeout           time.Duration
+	dryRun            bool
+}
+
+// NewCommand constructs a telemetry send command with the specified logger
+func NewCommand(logger logger.Interface, configFilePath *string) *cli.Command {
+	c := command{
+		logger:         logger,
+		configFilePath: configFilePath,
+	}
+	return c.build()
+}
+
+// build the send command
+func (m command) build() *cli.Command {
+	opts := options{}
+
+	c := cli.Command{
+		Name:  "send",
+		Usage: "Send the aggregate usage statistics recorded by the NVIDIA Container Runtime to the configured endpoint. This requires telemetry to be enabled in the config.",
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			return m.run(ctx, &opts)
+		},
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "endpoint",
+				Usage:       "The URL to send the statistics to. If this is not specified, the telemetry.endpoint config option is used.",
+				Destination: &opts.endpoint,
+				Sources:     cli.EnvVars("NVIDIA_CTK_TELEMETRY_ENDPOINT"),
+			},
+			&cli.StringFlag{
+				Name:        "telemetry-file",
+				Usage:       "The file in which the NVIDIA Container Runtime records statistics. If this is not specified, the telemetry.file config option is used.",
+				Destination: &opts.telemetryFilePath,
+				Sources:     cli.EnvVars("NVIDIA_CTK_TELEMETRY_FILE"),
+			},
+			&cli.StringFlag{
+				Name:        "driver-root",
+				Usage:       "The path to the driver root. This is used to determine the driver version.",
+				Value:       "/",
+				Destination: &opts.driverRoot,
+				Sources:     cli.EnvVars("NVIDIA_DRIVER_ROOT", "DRIVER_ROOT"),
+			},
+			&cli.DurationFlag{
+				Name:        "timeout",
+				Usage:       "The timeout for sending the statistics",
+				Value:       defaultTimeout,
+				Destination: &opts.timeout,
+			},
+			&cli.BoolFlag{
+				Name:        "dry-run",
+				Usage:       "Print the report that would be sent instead of sending it. The recorded statistics are not reset.",
+				Destination: &opts.dryRun,
+			},
+		},
+	}
+
+	return &c
+}
+
+func (m command) run(ctx context.Context, opts *options) error {
+	cfg, err := m.loadConfig()
+	if err != nil {
+		return err
+	}
+	if !cfg.Telemetry.Enabled {
+		return errors.New("telemetry is not enabled; set telemetry.enabled in the config to opt in")
+	}
+	if opts.endpoint == "" {
+		opts.endpoint = cfg.Telemetry.Endpoint
+	}
+	if opts.telemetryFilePath == "" {
+		opts.telemetryFilePath = cfg.Telemetry.FilePath
+	}
+	if opts.telemetryFilePath == "" {
+		opts.telemetryFilePath = telemetry.DefaultFilePath
+	}
+
+	store := telemetry.NewStore(opts.telemetryFilePath)
+	report := &telemetry.Report{
+		ToolkitVersion: info.GetVersion(),
+		DriverVersion:  m.getDriverVersion(opts.driverRoot),
+	}
+
+	if opts.dryRun {
+		counts, err := store.Load()
+		if err != nil {
+			return err
+		}
+		report.Counts = *counts
+		return json.NewEncoder(os.Stdout).Encode(report)
+	}
+
+	if opts.endpoint == "" {
+		return errors.New("no telemetry endpoint specified")
+	}
+
+	client := &http.Client{Timeout: opts.timeout}
+	err = store.Flush(func(counts *telemetry.Counts) error {
+		report.Counts = *counts
+		return telemetry.Send(ctx, client, opts.endpoint, report)
+	})
+	if err != nil {
+		return err
+	}
+	m.logger.Infof("Sent usage statistics to %v", opts.endpoint)
+	return nil
+}
+
+// loadConfig loads the toolkit config from the specified or default path.
+func (m command) loadConfig() (*config.Config, error) {
+	configFilePath := config.GetConfigFilePath()
+	if m.configFilePath != nil && *m.configFilePath != "" {
+		configFilePath = *m.configFilePath
+	}
+	configToml, err := config.New(
+		config.WithConfigFile(configFilePath),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	return configToml.Config()
+}
+
+func (m command) getDriverVersion(driverRoot string) string {
+	driver := root.New(
+		root.WithLogger(m.logger),
+		root.WithDriverRoot(driverRoot),
+	)
+	version, err := driver.Version()
+	if err != nil {
+		m.logger.Debugf("Failed to determine driver version: %v", err)
+		return ""
+	}
+	return version
+}
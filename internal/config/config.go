@@ -28,6 +28,7 @@ import (
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/config/image"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/warnings"
 )
 
 const (
@@ -64,6 +65,11 @@ type Config struct {
 	AcceptEnvvarUnprivileged       bool   `toml:"accept-nvidia-visible-devices-envvar-when-unprivileged"`
 	AcceptDeviceListAsVolumeMounts bool   `toml:"accept-nvidia-visible-devices-as-volume-mounts"`
 	SupportedDriverCapabilities    string `toml:"supported-driver-capabilities"`
+	// VisibleDevicesAll optionally defines the comma-separated list of devices
+	// that are injected when a container requests all devices through the
+	// NVIDIA_VISIBLE_DEVICES environment variable. This allows, for example,
+	// a display GPU to be excluded from containers without modifying images.
+	VisibleDevicesAll string `toml:"visible-devices-all,omitempty"`
 
 	NVIDIAContainerCLIConfig         ContainerCLIConfig `toml:"nvidia-container-cli"`
 	NVIDIACTKConfig                  CTKConfig          `toml:"nvidia-ctk"`
@@ -72,6 +78,9 @@ type Config struct {
 
 	// Features allows for finer control over optional features.
 	Features features `toml:"features,omitempty"`
+
+	// Telemetry configures the opt-in reporting of anonymous usage statistics.
+	Telemetry TelemetryConfig `toml:"telemetry,omitempty"`
 }
 
 // GetConfigFilePath returns the path to the config file for the configured system
@@ -89,15 +98,59 @@ func GetConfigFilePath() string {
 
 // GetConfig sets up the config struct. Values are read from a toml file
 // or set via the environment.
+// If the process was started by a rootless container engine, the per-user
+// config file is merged over the system config file. This allows users to
+// adjust settings such as the log level or the mode without requiring root.
 func GetConfig() (*Config, error) {
+	cfg, _, err := GetConfigWithWarnings()
+	return cfg, err
+}
+
+// GetConfigWithWarnings loads the config in the same way as GetConfig and
+// additionally returns the deprecation and migration warnings for the loaded
+// config.
+func GetConfigWithWarnings() (*Config, []warnings.Warning, error) {
+	configFilePath, userConfigFilePath := getConfigFilePaths()
+
 	cfg, err := New(
-		WithConfigFile(GetConfigFilePath()),
+		WithConfigFile(configFilePath),
+		WithOverlayFile(userConfigFilePath),
 	)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return cfg.Config()
+	c, err := cfg.Config()
+	if err != nil {
+		return nil, nil, err
+	}
+	return c, cfg.Warnings(), nil
+}
+
+// GetUserConfigOverlayFilePath returns the path to the per-user config file
+// that is merged over the system config when the config is loaded using
+// GetConfig. An empty string is returned if no per-user config file is
+// applied.
+func GetUserConfigOverlayFilePath() string {
+	_, userConfigFilePath := getConfigFilePaths()
+	if userConfigFilePath == "" {
+		return ""
+	}
+	if _, err := os.Stat(userConfigFilePath); err != nil {
+		return ""
+	}
+	return userConfigFilePath
+}
+
+// getConfigFilePaths returns the path to the config file and to the per-user
+// config file that is merged over it. The per-user config file is only
+// applicable if the process was started by a rootless container engine and
+// the config file path is not overridden.
+func getConfigFilePaths() (string, string) {
+	if os.Getenv(FilePathOverrideEnvVar) == "" && isRootless() {
+		return filepath.Join("/etc", RelativeFilePath), GetUserConfigFilePath()
+	}
+	return GetConfigFilePath(), ""
 }
 
 // GetDefault defines the default values for the config
@@ -145,6 +198,9 @@ func (c *Config) assertValid() error {
 	if err != nil {
 		return errors.Join(err, errInvalidConfig)
 	}
+	if err := c.NVIDIAContainerRuntimeConfig.assertValid(); err != nil {
+		return errors.Join(err, errInvalidConfig)
+	}
 	return nil
 }
 
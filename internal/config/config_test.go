@@ -71,7 +71,7 @@ func TestGetConfig(t *testing.T) {
 			description: "empty config is default",
 			expectedConfig: &Config{
 				AcceptEnvvarUnprivileged:    true,
-				SupportedDriverCapabilities: "compat32,compute,display,graphics,ngx,utility,video",
+				SupportedDriverCapabilities: "compat32,compute,display,graphics,ngx,profiling,utility,video",
 				NVIDIAContainerCLIConfig: ContainerCLIConfig{
 					Root:      "",
 					LoadKmods: true,
@@ -179,7 +179,7 @@ func TestGetConfig(t *testing.T) {
 			},
 			expectedConfig: &Config{
 				AcceptEnvvarUnprivileged:    true,
-				SupportedDriverCapabilities: "compat32,compute,display,graphics,ngx,utility,video",
+				SupportedDriverCapabilities: "compat32,compute,display,graphics,ngx,profiling,utility,video",
 				NVIDIAContainerCLIConfig: ContainerCLIConfig{
 					Ldconfig:  "/foo/bar/ldconfig",
 					LoadKmods: true,
@@ -296,7 +296,7 @@ func TestGetConfig(t *testing.T) {
 			distIdsLike: []string{"suse", "opensuse"},
 			expectedConfig: &Config{
 				AcceptEnvvarUnprivileged:    true,
-				SupportedDriverCapabilities: "compat32,compute,display,graphics,ngx,utility,video",
+				SupportedDriverCapabilities: "compat32,compute,display,graphics,ngx,profiling,utility,video",
 				NVIDIAContainerCLIConfig: ContainerCLIConfig{
 					Root:      "",
 					LoadKmods: true,
@@ -338,7 +338,7 @@ func TestGetConfig(t *testing.T) {
 			},
 			expectedConfig: &Config{
 				AcceptEnvvarUnprivileged:    true,
-				SupportedDriverCapabilities: "compat32,compute,display,graphics,ngx,utility,video",
+				SupportedDriverCapabilities: "compat32,compute,display,graphics,ngx,profiling,utility,video",
 				NVIDIAContainerCLIConfig: ContainerCLIConfig{
 					Root:      "",
 					LoadKmods: true,
@@ -437,6 +437,28 @@ func TestAssertValid(t *testing.T) {
 				},
 			},
 		},
+		{
+			description: "invalid discovery retry backoff is invalid",
+			config: &Config{
+				NVIDIAContainerRuntimeConfig: RuntimeConfig{
+					DiscoveryRetries: discoveryRetriesConfig{
+						Backoff: "soon",
+					},
+				},
+			},
+			expectedError: errInvalidConfig,
+		},
+		{
+			description: "invalid modifier plugin timeout is invalid",
+			config: &Config{
+				NVIDIAContainerRuntimeConfig: RuntimeConfig{
+					ModifierPlugins: modifierPluginsConfig{
+						Timeout: "forever",
+					},
+				},
+			},
+			expectedError: errInvalidConfig,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -476,3 +498,60 @@ func setGetLdConfigPathForTest() func() {
 		getLdConfigPath = previous
 	}
 }
+
+func TestGetUserConfigOverlayFilePath(t *testing.T) {
+	testCases := []struct {
+		description    string
+		rootless       bool
+		userConfig     bool
+		configOverride bool
+		expected       bool
+	}{
+		{
+			description: "not rootless",
+			userConfig:  true,
+		},
+		{
+			description: "rootless without user config",
+			rootless:    true,
+		},
+		{
+			description: "rootless with user config",
+			rootless:    true,
+			userConfig:  true,
+			expected:    true,
+		},
+		{
+			description:    "config file path override",
+			rootless:       true,
+			userConfig:     true,
+			configOverride: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			defer func(original func() bool) { isRootless = original }(isRootless)
+			isRootless = func() bool { return tc.rootless }
+
+			testDir := t.TempDir()
+			t.Setenv(configRootOverride, testDir)
+			t.Setenv(FilePathOverrideEnvVar, "")
+			if tc.configOverride {
+				t.Setenv(FilePathOverrideEnvVar, filepath.Join(testDir, "config.toml"))
+			}
+
+			filename := filepath.Join(testDir, RelativeFilePath)
+			if tc.userConfig {
+				require.NoError(t, os.MkdirAll(filepath.Dir(filename), 0755))
+				require.NoError(t, os.WriteFile(filename, nil, 0600))
+			}
+
+			var expected string
+			if tc.expected {
+				expected = filename
+			}
+			require.Equal(t, expected, GetUserConfigOverlayFilePath())
+		})
+	}
+}
@@ -0,0 +1,89 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/warnings"
+)
+
+// A deprecatedKey is a config option that is no longer supported. If a
+// replacement is specified, the value of the option is migrated to the
+// replacement.
+type deprecatedKey struct {
+	key         string
+	replacement string
+}
+
+var deprecatedKeys = []deprecatedKey{
+	{
+		key: "nvidia-container-runtime.experimental",
+	},
+	{
+		key:         "nvidia-container-runtime.discover-mode",
+		replacement: "nvidia-container-runtime.mode",
+	},
+}
+
+// Warnings returns the deprecation and migration warnings for the config.
+func (t *Toml) Warnings() []warnings.Warning {
+	if t == nil {
+		return nil
+	}
+
+	var ws []warnings.Warning
+	for _, d := range deprecatedKeys {
+		if t.Get(d.key) == nil {
+			continue
+		}
+		w := warnings.Warning{
+			ID:          d.key,
+			Kind:        warnings.DeprecatedConfig,
+			Message:     fmt.Sprintf("the %v config option is no longer supported", d.key),
+			Remediation: "remove the option or run 'nvidia-ctk config migrate'",
+		}
+		if d.replacement != "" {
+			w.Message = fmt.Sprintf("the %v config option has been replaced by %v", d.key, d.replacement)
+			w.Remediation = fmt.Sprintf("set %v instead or run 'nvidia-ctk config migrate'", d.replacement)
+		}
+		ws = append(ws, w)
+	}
+
+	return ws
+}
+
+// Migrate updates the config to address the warnings returned by Warnings.
+// Deprecated options are removed, with their values being moved to their
+// replacement if this is not already set. The warnings that were addressed are
+// returned.
+func (t *Toml) Migrate() []warnings.Warning {
+	ws := t.Warnings()
+
+	for _, d := range deprecatedKeys {
+		value := t.Get(d.key)
+		if value == nil {
+			continue
+		}
+		if d.replacement != "" && t.Get(d.replacement) == nil {
+			t.Set(d.replacement, value)
+		}
+		_ = t.Delete(d.key)
+	}
+
+	return ws
+}
@@ -0,0 +1,98 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWarnings(t *testing.T) {
+	testCases := []struct {
+		description     string
+		contents        string
+		expectedIDs     []string
+		expectedMigrate map[string]interface{}
+	}{
+		{
+			description: "no warnings",
+			contents: `
+[nvidia-container-runtime]
+mode = "cdi"
+`,
+			expectedMigrate: map[string]interface{}{
+				"nvidia-container-runtime.mode": "cdi",
+			},
+		},
+		{
+			description: "deprecated keys are migrated",
+			contents: `
+[features]
+sandbox-hooks = true
+
+[nvidia-container-runtime]
+discover-mode = "legacy"
+experimental = true
+`,
+			expectedIDs: []string{"nvidia-container-runtime.experimental", "nvidia-container-runtime.discover-mode"},
+			expectedMigrate: map[string]interface{}{
+				"features.sandbox-hooks":                 true,
+				"nvidia-container-runtime.mode":          "legacy",
+				"nvidia-container-runtime.discover-mode": nil,
+				"nvidia-container-runtime.experimental":  nil,
+			},
+		},
+		{
+			description: "replacement is not overwritten",
+			contents: `
+[features]
+sandbox-hooks = true
+
+[nvidia-container-runtime]
+discover-mode = "legacy"
+mode = "cdi"
+`,
+			expectedIDs: []string{"nvidia-container-runtime.discover-mode"},
+			expectedMigrate: map[string]interface{}{
+				"nvidia-container-runtime.mode":          "cdi",
+				"nvidia-container-runtime.discover-mode": nil,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			cfg, err := loadConfigTomlFrom(strings.NewReader(tc.contents))
+			require.NoError(t, err)
+
+			var ids []string
+			for _, w := range cfg.Warnings() {
+				ids = append(ids, w.ID)
+			}
+			require.EqualValues(t, tc.expectedIDs, ids)
+
+			require.Len(t, cfg.Migrate(), len(tc.expectedIDs))
+			require.Empty(t, cfg.Warnings())
+
+			for key, value := range tc.expectedMigrate {
+				require.Equal(t, value, cfg.Get(key), key)
+			}
+		})
+	}
+}
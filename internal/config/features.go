@@ -25,6 +25,11 @@ type features struct {
 	// If this feature flag is not set to 'true' only host-rooted config paths
 	// (i.e. paths starting with an '@' are considered valid)
 	AllowLDConfigFromContainer *feature `toml:"allow-ldconfig-from-container,omitempty"`
+	// AnnotateInjectedDevices sets the com.nvidia.devices.injected annotation
+	// in the OCI specification of containers that GPUs are injected into. This
+	// allows monitoring agents to map containers to GPUs without inspecting
+	// the container environment.
+	AnnotateInjectedDevices *feature `toml:"annotate-injected-devices,omitempty"`
 	// DisableCUDACompatLibHook, when enabled skips the injection of a specific
 	// hook to process CUDA compatibility libraries.
 	//
@@ -34,6 +39,31 @@ type features struct {
 	// DisableImexChannelCreation ensures that the implicit creation of
 	// requested IMEX channels is skipped when invoking the nvidia-container-cli.
 	DisableImexChannelCreation *feature `toml:"disable-imex-channel-creation,omitempty"`
+	// DisableLDCacheHook removes the update-ldcache hook from the OCI spec and
+	// instead adds the folders of the injected libraries to LD_LIBRARY_PATH in
+	// the container. This reduces the startup latency for images that are
+	// known to honor LD_LIBRARY_PATH (e.g. statically linked workloads that
+	// only dlopen the driver libraries). This can be overridden for a
+	// container using the NVIDIA_DISABLE_LDCACHE_HOOK envvar or the
+	// nvidia.com/disable-ldcache-hook annotation.
+	DisableLDCacheHook *feature `toml:"disable-ldcache-hook,omitempty"`
+	// EnableEGM injects the extended GPU memory (EGM) device nodes of Grace
+	// Hopper systems into containers and ensures that the CPU-less NUMA nodes
+	// of the GPU memory are included in the allowed memory nodes of containers
+	// with a restricted cpuset. This applies to CDI specifications generated at
+	// runtime.
+	EnableEGM *feature `toml:"enable-egm,omitempty"`
+	// GenerateXorgConfig generates an xorg.conf snippet in containers that
+	// request the display driver capability. The snippet configures the
+	// injected NVIDIA Xorg driver modules so that a containerized Xorg server
+	// can be started without the driver being installed in the image.
+	GenerateXorgConfig *feature `toml:"generate-xorg-config,omitempty"`
+	// HookDiagnostics records the output of failed NVIDIA CDI hooks in the
+	// bundle of a container and includes the first error in the error
+	// returned by the NVIDIA Container Runtime. Note that this requires the
+	// low-level runtime to be run as a child process of the NVIDIA Container
+	// Runtime when a container is created.
+	HookDiagnostics *feature `toml:"hook-diagnostics,omitempty"`
 	// IgnoreImexChannelRequests configures the NVIDIA Container Toolkit to
 	// ignore IMEX channel requests through the NVIDIA_IMEX_CHANNELS envvar or
 	// volume mounts.
@@ -42,6 +72,82 @@ type features struct {
 	// possibly bypassing other checks by an orchestration system such as
 	// kubernetes.
 	IgnoreImexChannelRequests *feature `toml:"ignore-imex-channel-requests,omitempty"`
+	// InjectDriverParams mounts the parameters of the loaded NVIDIA kernel
+	// modules (the parameters folders of the nvidia, nvidia_uvm, and
+	// nvidia_modeset modules in /sys/module) read-only into containers. These are required by CUDA debugging and
+	// profiling tools. This applies to CDI specifications generated at runtime.
+	InjectDriverParams *feature `toml:"inject-driver-params,omitempty"`
+	// InjectGPUSysfs mounts the /sys/bus/pci/devices and /sys/class/drm
+	// entries of the injected GPUs read-only into containers. This allows
+	// topology discovery and power monitoring tools to be used without
+	// mounting all of the host sysfs. This applies to CDI specifications
+	// generated at runtime.
+	InjectGPUSysfs *feature `toml:"inject-gpu-sysfs,omitempty"`
+	// MaskUnrequestedGPUDeviceNodes masks the /dev/nvidiaN, /dev/nvidia-caps,
+	// and /dev/dri device nodes of GPUs that were not requested in containers
+	// that have access to all host devices (e.g. privileged containers or
+	// containers that mount the host /dev). The masked device nodes are
+	// replaced by /dev/null so that the visible GPUs match the request.
+	MaskUnrequestedGPUDeviceNodes *feature `toml:"mask-unrequested-gpu-device-nodes,omitempty"`
+	// MaskUnrequestedGPUProcEntries masks the /proc/driver/nvidia/gpus
+	// entries of GPUs that are not injected into a container. This ensures
+	// that a container with a subset of the GPUs on a system cannot enumerate
+	// sibling devices through procfs.
+	// Note that the masked folders are replaced by empty folders and their
+	// names (i.e. the PCI bus IDs) remain visible in the container.
+	MaskUnrequestedGPUProcEntries *feature `toml:"mask-unrequested-gpu-proc-entries,omitempty"`
+	// MPSSharing configures containers with the nvidia.com/gpu.share
+	// annotation as clients of the CUDA Multi-Process Service (MPS). The
+	// annotation value sets the percentage of the GPU threads that the
+	// container may use and the MPS pipe directory of the host is mounted into
+	// the container. The MPS control daemon must be started separately.
+	MPSSharing *feature `toml:"mps-sharing,omitempty"`
+	// NestedContainers prepares containers that run nested GPU containers
+	// (e.g. docker-in-docker or sysbox) by mounting the toolkit config file
+	// of the host into the container. The toolkit executables and libraries
+	// of the host are mounted if the container image does not contain them.
+	// This applies to containers run by the low-level runtimes configured
+	// as nested-containers-runtimes (sysbox-runc by default).
+	NestedContainers *feature `toml:"nested-containers,omitempty"`
+	// SandboxHooks runs the injected NVIDIA CDI hooks with reduced
+	// privileges. The hooks set no_new_privs, drop the capabilities that they
+	// do not require, and run in a private mount namespace where the hook
+	// does not create mounts in the container.
+	SandboxHooks *feature `toml:"sandbox-hooks,omitempty"`
+	// StableHookPaths uses a well-known path for the nvidia-cdi-hook in the
+	// injected hooks instead of the path configured on the host. This ensures
+	// that identical containers produce identical specs across nodes where the
+	// toolkit is installed at different locations.
+	StableHookPaths *feature `toml:"stable-hook-paths,omitempty"`
+	// SetInjectionSummaryEnvvars sets the NVIDIA_INJECTED_DEVICES and
+	// NVIDIA_TOOLKIT_VERSION envvars in containers that GPUs are injected into.
+	// This allows diagnostic tools in the container to check what was
+	// injected by the NVIDIA Container Toolkit without access to the host.
+	SetInjectionSummaryEnvvars *feature `toml:"set-injection-summary-envvars,omitempty"`
+	// SkipLDCacheCreation configures the update-ldcache hook to only add the
+	// injected library folders to /etc/ld.so.conf.d in the container and to
+	// only refresh the ldcache if the container already includes one.
+	// This reduces the startup latency for large images at the cost of
+	// injected libraries not being in the ldcache for containers without one.
+	SkipLDCacheCreation *feature `toml:"skip-ldcache-creation,omitempty"`
+	// WrapNvidiaSMI replaces nvidia-smi in containers that GPUs are injected
+	// into by a wrapper that restricts the default output of nvidia-smi to
+	// the injected GPUs. This is cosmetic and does not isolate GPUs since the
+	// wrapped executable can still be invoked directly. This applies to the
+	// mounts in the OCI spec and has no effect in the legacy mode.
+	WrapNvidiaSMI *feature `toml:"wrap-nvidia-smi,omitempty"`
+	// WriteDeviceMap writes a mapping between the GPU ordinals in a container
+	// and the GPUs on the host to the nvidia-device-map.json file in the
+	// bundle of the container. This allows tools such as monitoring agents or
+	// checkpoint / restore tooling to correlate device indices in a container
+	// with host GPUs.
+	WriteDeviceMap *feature `toml:"write-device-map,omitempty"`
+	// WriteInjectionReport mounts a JSON report of the injected devices and
+	// libraries, the skipped entries, and the warnings raised while modifying
+	// the container at /run/nvidia-container-toolkit/injection.json in the
+	// container. Failures to write the report do not prevent the container
+	// from being created.
+	WriteInjectionReport *feature `toml:"write-injection-report,omitempty"`
 }
 
 type feature bool
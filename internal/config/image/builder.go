@@ -17,6 +17,7 @@
 package image
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -25,9 +26,15 @@ import (
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
 )
 
+// ImageConfigEnvAnnotation is the annotation that can be used to provide the
+// environment variables of the image config as a JSON array of KEY=VALUE
+// strings.
+const ImageConfigEnvAnnotation = "nvidia.com/image-config-env"
+
 type builder struct {
 	CUDA
 
+	defaultEnv     map[string]string
 	disableRequire bool
 }
 
@@ -59,8 +66,28 @@ func New(opt ...Option) (CUDA, error) {
 
 // build creates a CUDA image from the builder.
 func (b builder) build() (CUDA, error) {
+	// Variables without a recorded source are from the container environment.
+	if len(b.defaultEnv) > 0 {
+		b.envSources = make(map[string]EnvSource)
+		env := make(map[string]string)
+		for key, value := range b.defaultEnv {
+			env[key] = value
+			b.envSources[key] = EnvSourceImageConfig
+		}
+		for key, value := range b.env {
+			env[key] = value
+			if value != b.defaultEnv[key] {
+				b.envSources[key] = EnvSourceRuntimeOverride
+			}
+		}
+		b.env = env
+	}
 	if b.disableRequire {
 		b.env[EnvVarNvidiaDisableRequire] = "true"
+		if b.envSources == nil {
+			b.envSources = make(map[string]EnvSource)
+		}
+		b.envSources[EnvVarNvidiaDisableRequire] = EnvSourceToolkitConfig
 	}
 
 	return b.CUDA, nil
@@ -94,6 +121,40 @@ func WithAnnotationsPrefixes(annotationsPrefixes []string) Option {
 	}
 }
 
+// WithDefaultEnv sets default environment variables for the CUDA image. These
+// are typically the environment variables defined in the image config and are
+// only used if the same variable is not set in the process environment (see
+// WithEnv and WithEnvMap).
+func WithDefaultEnv(env []string) Option {
+	return func(b *builder) error {
+		envmap, err := parseEnv(env)
+		if err != nil {
+			return err
+		}
+		b.defaultEnv = envmap
+		return nil
+	}
+}
+
+// WithImageConfigEnvFromAnnotations sets the default environment variables
+// for the CUDA image from the ImageConfigEnvAnnotation, if present. The OCI
+// runtime specification does not include the image config, so an engine or
+// orchestrator that wants the image config to be distinguished from variables
+// set when the container is created must provide it using this annotation.
+func WithImageConfigEnvFromAnnotations(annotations map[string]string) Option {
+	return func(b *builder) error {
+		value, ok := annotations[ImageConfigEnvAnnotation]
+		if !ok {
+			return nil
+		}
+		var env []string
+		if err := json.Unmarshal([]byte(value), &env); err != nil {
+			return fmt.Errorf("invalid %v annotation: %w", ImageConfigEnvAnnotation, err)
+		}
+		return WithDefaultEnv(env)(b)
+	}
+}
+
 // WithDisableRequire sets the disable require option.
 func WithDisableRequire(disableRequire bool) Option {
 	return func(b *builder) error {
@@ -106,18 +167,27 @@ func WithDisableRequire(disableRequire bool) Option {
 // Note that this also overwrites the values set with WithEnvMap.
 func WithEnv(env []string) Option {
 	return func(b *builder) error {
-		envmap := make(map[string]string)
-		for _, e := range env {
-			parts := strings.SplitN(e, "=", 2)
-			if len(parts) != 2 {
-				return fmt.Errorf("invalid environment variable: %v", e)
-			}
-			envmap[parts[0]] = parts[1]
+		envmap, err := parseEnv(env)
+		if err != nil {
+			return err
 		}
 		return WithEnvMap(envmap)(b)
 	}
 }
 
+// parseEnv converts a list of strings of the form ENVVAR=VALUE to a map.
+func parseEnv(env []string) (map[string]string, error) {
+	envmap := make(map[string]string)
+	for _, e := range env {
+		parts := strings.SplitN(e, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid environment variable: %v", e)
+		}
+		envmap[parts[0]] = parts[1]
+	}
+	return envmap, nil
+}
+
 // WithEnvMap sets the environment variable map to use when creating the CUDA image.
 // Note that this also overwrites the values set with WithEnv.
 func WithEnvMap(env map[string]string) Option {
@@ -152,6 +222,23 @@ func WithPreferredVisibleDevicesEnvVars(preferredVisibleDeviceEnvVars ...string)
 	}
 }
 
+// WithVisibleDevicesAll sets the devices that a request for all devices
+// through environment variables is mapped to. The devices are specified as a
+// comma-separated list. If this is empty, all devices are injected.
+func WithVisibleDevicesAll(visibleDevicesAll string) Option {
+	return func(b *builder) error {
+		b.visibleDevicesAll = nil
+		for _, d := range strings.Split(visibleDevicesAll, ",") {
+			trimmed := strings.TrimSpace(d)
+			if trimmed == "" {
+				continue
+			}
+			b.visibleDevicesAll = append(b.visibleDevicesAll, trimmed)
+		}
+		return nil
+	}
+}
+
 // WithPrivileged sets whether an image is privileged or not.
 func WithPrivileged(isPrivileged bool) Option {
 	return func(b *builder) error {
@@ -26,15 +26,16 @@ type DriverCapability string
 
 // Constants for the supported driver capabilities
 const (
-	DriverCapabilityAll      DriverCapability = "all"
-	DriverCapabilityNone     DriverCapability = "none"
-	DriverCapabilityCompat32 DriverCapability = "compat32"
-	DriverCapabilityCompute  DriverCapability = "compute"
-	DriverCapabilityDisplay  DriverCapability = "display"
-	DriverCapabilityGraphics DriverCapability = "graphics"
-	DriverCapabilityNgx      DriverCapability = "ngx"
-	DriverCapabilityUtility  DriverCapability = "utility"
-	DriverCapabilityVideo    DriverCapability = "video"
+	DriverCapabilityAll       DriverCapability = "all"
+	DriverCapabilityNone      DriverCapability = "none"
+	DriverCapabilityCompat32  DriverCapability = "compat32"
+	DriverCapabilityCompute   DriverCapability = "compute"
+	DriverCapabilityDisplay   DriverCapability = "display"
+	DriverCapabilityGraphics  DriverCapability = "graphics"
+	DriverCapabilityNgx       DriverCapability = "ngx"
+	DriverCapabilityProfiling DriverCapability = "profiling"
+	DriverCapabilityUtility   DriverCapability = "utility"
+	DriverCapabilityVideo     DriverCapability = "video"
 )
 
 var (
@@ -44,7 +45,7 @@ var (
 	// DefaultDriverCapabilities sets the value for driver capabilities if no value is set.
 	DefaultDriverCapabilities = NewDriverCapabilities("utility,compute")
 	// SupportedDriverCapabilities defines the set of all supported driver capabilities.
-	SupportedDriverCapabilities = NewDriverCapabilities("compute,compat32,graphics,utility,video,display,ngx")
+	SupportedDriverCapabilities = NewDriverCapabilities("compute,compat32,graphics,utility,video,display,ngx,profiling")
 )
 
 // NewDriverCapabilities creates a set of driver capabilities from the specified capabilities
@@ -45,6 +45,7 @@ type CUDA struct {
 
 	annotations  map[string]string
 	env          map[string]string
+	envSources   map[string]EnvSource
 	isPrivileged bool
 	mounts       []specs.Mount
 
@@ -52,10 +53,15 @@ type CUDA struct {
 	acceptDeviceListAsVolumeMounts bool
 	acceptEnvvarUnprivileged       bool
 	preferredVisibleDeviceEnvVars  []string
+	// visibleDevicesAll defines the devices that a request for all devices
+	// through environment variables is mapped to.
+	visibleDevicesAll []string
 }
 
 // NewCUDAImageFromSpec creates a CUDA image from the input OCI runtime spec.
 // The process environment is read (if present) to construc the CUDA Image.
+// Variables in the process environment take precedence over defaults
+// specified using WithDefaultEnv or the ImageConfigEnvAnnotation.
 func NewCUDAImageFromSpec(spec *specs.Spec, opts ...Option) (CUDA, error) {
 	if spec == nil {
 		return New(opts...)
@@ -68,6 +74,7 @@ func NewCUDAImageFromSpec(spec *specs.Spec, opts ...Option) (CUDA, error) {
 
 	specOpts := []Option{
 		WithAnnotations(spec.Annotations),
+		WithImageConfigEnvFromAnnotations(spec.Annotations),
 		WithEnv(env),
 		WithMounts(spec.Mounts),
 		WithPrivileged(IsPrivileged((*OCISpec)(spec))),
@@ -114,20 +121,13 @@ func (i CUDA) GetRequirements() ([]string, error) {
 		return nil, nil
 	}
 
-	// All variables with the "NVIDIA_REQUIRE_" prefix are passed to nvidia-container-cli
-	var requirements []string
-	for name, value := range i.env {
-		if strings.HasPrefix(name, NvidiaRequirePrefix) && !strings.HasPrefix(name, EnvVarNvidiaRequireJetpack) {
-			requirements = append(requirements, value)
-		}
+	sourced, err := i.GetSourcedRequirements()
+	if err != nil {
+		return nil, err
 	}
-	if i.IsLegacy() {
-		v, err := i.legacyVersion()
-		if err != nil {
-			return nil, fmt.Errorf("failed to get version: %v", err)
-		}
-		cudaRequire := fmt.Sprintf("cuda>=%s", v)
-		requirements = append(requirements, cudaRequire)
+	var requirements []string
+	for _, r := range sourced {
+		requirements = append(requirements, r.Value)
 	}
 	return requirements, nil
 }
@@ -174,6 +174,11 @@ func (i CUDA) devicesFromEnvvars(envVars ...string) []string {
 		devices = []string{"void"}
 	}
 
+	if slices.Contains(devices, "all") && len(i.visibleDevicesAll) > 0 {
+		i.logger.Debugf("Mapping request for all devices to %v", i.visibleDevicesAll)
+		devices = i.visibleDevicesAll
+	}
+
 	return NewVisibleDevices(devices...).List()
 }
 
@@ -189,6 +194,18 @@ func (i CUDA) GetDriverCapabilities() DriverCapabilities {
 	return capabilities
 }
 
+// WithDriverCapabilities returns a copy of the image with the requested
+// driver capabilities replaced by the specified capabilities.
+func (i CUDA) WithDriverCapabilities(capabilities DriverCapabilities) CUDA {
+	env := make(map[string]string, len(i.env)+1)
+	for key, value := range i.env {
+		env[key] = value
+	}
+	env[EnvVarNvidiaDriverCapabilities] = capabilities.String()
+	i.env = env
+	return i
+}
+
 func (i CUDA) legacyVersion() (string, error) {
 	cudaVersion := i.env[EnvVarCudaVersion]
 	majorMinor, err := parseMajorMinorVersion(cudaVersion)
@@ -250,14 +267,18 @@ func (i CUDA) visibleEnvVars() []string {
 }
 
 // VisibleDevices returns a list of devices requested in the container image.
-// If volume mount requests are enabled these are returned if requested,
-// otherwise device requests through environment variables are considered.
-// In cases where environment variable requests required privileged containers,
-// such devices requests are ignored.
+// Device requests are considered in the following order of precedence with
+// only the requests from the first matching source being returned:
+//  1. CDI device requests in annotations with a configured prefix.
+//  2. Volume mount requests, if enabled.
+//  3. Requests through environment variables. In cases where environment
+//     variable requests require privileged containers, such devices requests
+//     are ignored.
 func (i CUDA) VisibleDevices() []string {
 	// If annotation device requests are present, these are preferred.
 	annotationDeviceRequests := i.cdiDeviceRequestsFromAnnotations()
 	if len(annotationDeviceRequests) > 0 {
+		i.logIgnoredEnvvarRequests("annotations")
 		return annotationDeviceRequests
 	}
 
@@ -265,6 +286,7 @@ func (i CUDA) VisibleDevices() []string {
 	if i.acceptDeviceListAsVolumeMounts {
 		volumeMountDeviceRequests := i.visibleDevicesFromMounts()
 		if len(volumeMountDeviceRequests) > 0 {
+			i.logIgnoredEnvvarRequests("volume mounts")
 			return volumeMountDeviceRequests
 		}
 	}
@@ -290,6 +312,17 @@ func (i CUDA) VisibleDevices() []string {
 	return nil
 }
 
+// logIgnoredEnvvarRequests logs the device requests through environment
+// variables that are ignored because devices are also requested through a
+// source with a higher precedence.
+func (i CUDA) logIgnoredEnvvarRequests(source string) {
+	envVarDeviceRequests := i.visibleDevicesFromEnvVar()
+	if len(envVarDeviceRequests) == 0 {
+		return
+	}
+	i.logger.Debugf("Ignoring devices %v requested by environment variable(s) %v since devices are requested through %v", envVarDeviceRequests, i.visibleEnvVars(), source)
+}
+
 // cdiDeviceRequestsFromAnnotations returns a list of devices specified in the
 // annotations.
 // Keys starting with the specified prefixes are considered and expected to
@@ -94,6 +94,68 @@ func TestNewCUDAImageFromSpec(t *testing.T) {
 				acceptEnvvarUnprivileged: true,
 			},
 		},
+		{
+			description: "process env takes precedence over default env",
+			spec: &specs.Spec{
+				Process: &specs.Process{
+					Env: []string{"NVIDIA_VISIBLE_DEVICES=0"},
+				},
+			},
+			options: []Option{
+				WithDefaultEnv([]string{"NVIDIA_VISIBLE_DEVICES=all", "NVIDIA_DRIVER_CAPABILITIES=compute"}),
+			},
+			expected: CUDA{
+				logger: logger,
+				env: map[string]string{
+					"NVIDIA_VISIBLE_DEVICES":     "0",
+					"NVIDIA_DRIVER_CAPABILITIES": "compute",
+				},
+				envSources: map[string]EnvSource{
+					"NVIDIA_VISIBLE_DEVICES":     EnvSourceRuntimeOverride,
+					"NVIDIA_DRIVER_CAPABILITIES": EnvSourceImageConfig,
+				},
+				acceptEnvvarUnprivileged: true,
+			},
+		},
+		{
+			description: "image config env is read from annotation",
+			spec: &specs.Spec{
+				Process: &specs.Process{
+					Env: []string{"NVIDIA_REQUIRE_CUDA=cuda>=12.0", "NVIDIA_REQUIRE_BRAND=brand=tesla"},
+				},
+				Annotations: map[string]string{
+					ImageConfigEnvAnnotation: `["NVIDIA_REQUIRE_CUDA=cuda>=11.0", "NVIDIA_REQUIRE_BRAND=brand=tesla"]`,
+				},
+			},
+			expected: CUDA{
+				logger: logger,
+				annotations: map[string]string{
+					ImageConfigEnvAnnotation: `["NVIDIA_REQUIRE_CUDA=cuda>=11.0", "NVIDIA_REQUIRE_BRAND=brand=tesla"]`,
+				},
+				env: map[string]string{
+					"NVIDIA_REQUIRE_CUDA":  "cuda>=12.0",
+					"NVIDIA_REQUIRE_BRAND": "brand=tesla",
+				},
+				envSources: map[string]EnvSource{
+					"NVIDIA_REQUIRE_CUDA":  EnvSourceRuntimeOverride,
+					"NVIDIA_REQUIRE_BRAND": EnvSourceImageConfig,
+				},
+				acceptEnvvarUnprivileged: true,
+			},
+		},
+		{
+			description: "default env is used if spec has no process",
+			spec:        &specs.Spec{},
+			options: []Option{
+				WithDefaultEnv([]string{"NVIDIA_VISIBLE_DEVICES=all"}),
+			},
+			expected: CUDA{
+				logger:                   logger,
+				env:                      map[string]string{"NVIDIA_VISIBLE_DEVICES": "all"},
+				envSources:               map[string]EnvSource{"NVIDIA_VISIBLE_DEVICES": EnvSourceImageConfig},
+				acceptEnvvarUnprivileged: true,
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -224,6 +286,7 @@ func TestGetDevicesFromEnvvar(t *testing.T) {
 	var tests = []struct {
 		description                   string
 		preferredVisibleDeviceEnvVars []string
+		visibleDevicesAll             string
 		env                           map[string]string
 		expectedDevices               []string
 	}{
@@ -416,6 +479,37 @@ func TestGetDevicesFromEnvvar(t *testing.T) {
 			},
 			expectedDevices: []string{anotherGPUID},
 		},
+		{
+			description:       "'all' NVIDIA_VISIBLE_DEVICES is mapped to visible-devices-all",
+			visibleDevicesAll: "0, 1",
+			env: map[string]string{
+				EnvVarNvidiaVisibleDevices: "all",
+			},
+			expectedDevices: []string{"0", "1"},
+		},
+		{
+			description:       "'all' is mapped to visible-devices-all for legacy image",
+			visibleDevicesAll: "1",
+			env: map[string]string{
+				EnvVarCudaVersion: "9.0",
+			},
+			expectedDevices: []string{"1"},
+		},
+		{
+			description:       "explicit devices are not mapped by visible-devices-all",
+			visibleDevicesAll: "1",
+			env: map[string]string{
+				EnvVarNvidiaVisibleDevices: gpuID,
+			},
+			expectedDevices: []string{gpuID},
+		},
+		{
+			description:       "'void' NVIDIA_VISIBLE_DEVICES is not mapped by visible-devices-all",
+			visibleDevicesAll: "1",
+			env: map[string]string{
+				EnvVarNvidiaVisibleDevices: "all,void",
+			},
+		},
 	}
 
 	for _, tc := range tests {
@@ -426,6 +520,7 @@ func TestGetDevicesFromEnvvar(t *testing.T) {
 				WithAcceptDeviceListAsVolumeMounts(false),
 				WithAcceptEnvvarUnprivileged(false),
 				WithPreferredVisibleDevicesEnvVars(tc.preferredVisibleDeviceEnvVars...),
+				WithVisibleDevicesAll(tc.visibleDevicesAll),
 			)
 
 			require.NoError(t, err)
@@ -790,6 +885,47 @@ func TestCDIDeviceRequestsFromAnnotations(t *testing.T) {
 	}
 }
 
+func TestVisibleDevicesPrecedence(t *testing.T) {
+	testCases := []struct {
+		description     string
+		annotations     map[string]string
+		mounts          []specs.Mount
+		expectedDevices []string
+	}{
+		{
+			description:     "envvar requests are used if no other requests are present",
+			expectedDevices: []string{"GPU2"},
+		},
+		{
+			description:     "volume mount requests take precedence over envvar requests",
+			mounts:          makeTestMounts("GPU1"),
+			expectedDevices: []string{"GPU1"},
+		},
+		{
+			description: "annotation requests take precedence over other requests",
+			annotations: map[string]string{
+				"prefix/foo": "example.com/device=bar",
+			},
+			mounts:          makeTestMounts("GPU1"),
+			expectedDevices: []string{"example.com/device=bar"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			image, err := New(
+				WithAnnotationsPrefixes([]string{"prefix/"}),
+				WithAnnotations(tc.annotations),
+				WithAcceptDeviceListAsVolumeMounts(true),
+				WithMounts(tc.mounts),
+				WithEnvMap(map[string]string{EnvVarNvidiaVisibleDevices: "GPU2"}),
+			)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedDevices, image.VisibleDevices())
+		})
+	}
+}
+
 func makeTestMounts(paths ...string) []specs.Mount {
 	var mounts []specs.Mount
 	for _, path := range paths {
@@ -20,6 +20,10 @@ import (
 	"strings"
 )
 
+// NoneDeviceName is the device ID that selects no devices. Requesting this
+// device injects the driver libraries and utilities, but no device nodes.
+const NoneDeviceName = "none"
+
 // VisibleDevices represents the devices selected in a container image
 // through the NVIDIA_VISIBLE_DEVICES or other environment variables
 type VisibleDevices interface {
@@ -38,7 +42,7 @@ func NewVisibleDevices(envvars ...string) VisibleDevices {
 		if envvar == "all" {
 			return all{}
 		}
-		if envvar == "none" {
+		if envvar == NoneDeviceName {
 			return none{}
 		}
 		if envvar == "" || envvar == "void" {
@@ -0,0 +1,117 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package image
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/requirements"
+)
+
+// An EnvSource describes where an environment variable of the image was set.
+type EnvSource string
+
+const (
+	// EnvSourceContainer indicates that the variable was set in the container
+	// environment. This is used if the image config is not known and the
+	// origin of a variable cannot be determined more precisely.
+	EnvSourceContainer = EnvSource("container environment")
+	// EnvSourceImageConfig indicates that the variable was set in the image
+	// config.
+	EnvSourceImageConfig = EnvSource("image config")
+	// EnvSourceRuntimeOverride indicates that the variable was set or
+	// overridden when the container was created.
+	EnvSourceRuntimeOverride = EnvSource("runtime override")
+	// EnvSourceToolkitConfig indicates that the variable was set because of
+	// the NVIDIA Container Toolkit config.
+	EnvSourceToolkitConfig = EnvSource("toolkit config")
+)
+
+// EnvSource returns the source of the specified environment variable.
+func (i CUDA) EnvSource(key string) EnvSource {
+	if source, ok := i.envSources[key]; ok {
+		return source
+	}
+	return EnvSourceContainer
+}
+
+// GetSourcedRequirements returns the requirements from all NVIDIA_REQUIRE_
+// environment variables along with the variable that defines each of them.
+// Note that NVIDIA_DISABLE_REQUIRE is not taken into account.
+func (i CUDA) GetSourcedRequirements() ([]requirements.Requirement, error) {
+	// All variables with the "NVIDIA_REQUIRE_" prefix are passed to nvidia-container-cli
+	var names []string
+	for name := range i.env {
+		if strings.HasPrefix(name, NvidiaRequirePrefix) && !strings.HasPrefix(name, EnvVarNvidiaRequireJetpack) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var sourced []requirements.Requirement
+	for _, name := range names {
+		sourced = append(sourced, requirements.Requirement{
+			Value:  i.env[name],
+			Source: i.describeEnvvar(name),
+		})
+	}
+	if i.IsLegacy() {
+		v, err := i.legacyVersion()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get version: %v", err)
+		}
+		sourced = append(sourced, requirements.Requirement{
+			Value:  fmt.Sprintf("cuda>=%s", v),
+			Source: i.describeEnvvar(EnvVarCudaVersion),
+		})
+	}
+	return sourced, nil
+}
+
+// CheckRequirementConflicts returns an error if the requirements of the image
+// contradict each other. If the requirements are disabled, the requirements
+// that are ignored are logged instead.
+func (i CUDA) CheckRequirementConflicts() error {
+	sourced, err := i.GetSourcedRequirements()
+	if err != nil {
+		return err
+	}
+	if i.HasDisableRequire() {
+		for _, r := range sourced {
+			i.logger.Infof("Ignoring requirement %v from %v since %v is set", r.Value, r.Source, i.describeEnvvar(EnvVarNvidiaDisableRequire))
+		}
+		return nil
+	}
+
+	conflicts := requirements.FindConflicts(sourced)
+	if len(conflicts) == 0 {
+		return nil
+	}
+	var descriptions []string
+	for _, c := range conflicts {
+		descriptions = append(descriptions, c.String())
+	}
+	return fmt.Errorf("conflicting requirements: %v", strings.Join(descriptions, "; "))
+}
+
+// describeEnvvar returns the name of the specified environment variable along
+// with its source.
+func (i CUDA) describeEnvvar(key string) string {
+	return fmt.Sprintf("%v (%v)", key, i.EnvSource(key))
+}
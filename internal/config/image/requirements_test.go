@@ -0,0 +1,105 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package image
+
+import (
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckRequirementConflicts(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	testCases := []struct {
+		description   string
+		options       []Option
+		expectedError string
+	}{
+		{
+			description: "no conflicts",
+			options: []Option{
+				WithEnv([]string{"NVIDIA_REQUIRE_CUDA=cuda>=12.0", "NVIDIA_REQUIRE_DRIVER=driver>=550"}),
+			},
+		},
+		{
+			description: "conflict in container environment",
+			options: []Option{
+				WithEnv([]string{"NVIDIA_REQUIRE_CUDA=cuda>=12.0", "NVIDIA_REQUIRE_OLD=cuda<11.0"}),
+			},
+			expectedError: "conflicting requirements: no cuda satisfies all of " +
+				"cuda>=12.0 from NVIDIA_REQUIRE_CUDA (container environment), " +
+				"cuda<11.0 from NVIDIA_REQUIRE_OLD (container environment)",
+		},
+		{
+			description: "conflict between image config and runtime override",
+			options: []Option{
+				WithDefaultEnv([]string{"NVIDIA_REQUIRE_CUDA=cuda>=12.0", "NVIDIA_REQUIRE_BRAND=brand=tesla"}),
+				WithEnv([]string{"NVIDIA_REQUIRE_BRAND=brand=tesla", "NVIDIA_REQUIRE_OLD=cuda<11.0"}),
+			},
+			expectedError: "conflicting requirements: no cuda satisfies all of " +
+				"cuda>=12.0 from NVIDIA_REQUIRE_CUDA (image config), " +
+				"cuda<11.0 from NVIDIA_REQUIRE_OLD (runtime override)",
+		},
+		{
+			description: "legacy requirement is included",
+			options: []Option{
+				WithEnv([]string{"CUDA_VERSION=12.2", "NVIDIA_REQUIRE_OLD=cuda<11.0"}),
+			},
+			expectedError: "conflicting requirements: no cuda satisfies all of " +
+				"cuda>=12.2 from CUDA_VERSION (container environment), " +
+				"cuda<11.0 from NVIDIA_REQUIRE_OLD (container environment)",
+		},
+		{
+			description: "conflicts are ignored if requirements are disabled",
+			options: []Option{
+				WithEnv([]string{"NVIDIA_REQUIRE_CUDA=cuda>=12.0", "NVIDIA_REQUIRE_OLD=cuda<11.0"}),
+				WithDisableRequire(true),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			i, err := New(append(tc.options, WithLogger(logger))...)
+			require.NoError(t, err)
+
+			err = i.CheckRequirementConflicts()
+			if tc.expectedError == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tc.expectedError)
+		})
+	}
+}
+
+func TestEnvSource(t *testing.T) {
+	i, err := New(
+		WithDefaultEnv([]string{"FROM_IMAGE=a", "OVERRIDDEN=b", "UNCHANGED=c"}),
+		WithEnv([]string{"OVERRIDDEN=d", "UNCHANGED=c", "ADDED=e"}),
+		WithDisableRequire(true),
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, EnvSourceImageConfig, i.EnvSource("FROM_IMAGE"))
+	require.Equal(t, EnvSourceRuntimeOverride, i.EnvSource("OVERRIDDEN"))
+	require.Equal(t, EnvSourceImageConfig, i.EnvSource("UNCHANGED"))
+	require.Equal(t, EnvSourceRuntimeOverride, i.EnvSource("ADDED"))
+	require.Equal(t, EnvSourceToolkitConfig, i.EnvSource(EnvVarNvidiaDisableRequire))
+}
@@ -0,0 +1,50 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package image
+
+import (
+	"slices"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/warnings"
+)
+
+// Warnings returns the warnings for the legacy environment variable
+// conventions that the image relies on.
+func (i CUDA) Warnings() []warnings.Warning {
+	if !i.IsLegacy() {
+		return nil
+	}
+
+	var ws []warnings.Warning
+	if !i.HasDisableRequire() {
+		ws = append(ws, warnings.Warning{
+			ID:          "legacy-cuda-version-requirement",
+			Kind:        warnings.LegacyEnvvar,
+			Message:     "the CUDA requirement of the container is derived from " + EnvVarCudaVersion + " since " + EnvVarNvidiaRequireCuda + " is not set",
+			Remediation: "set " + EnvVarNvidiaRequireCuda + " in the image to specify the CUDA requirement explicitly",
+		})
+	}
+	if !slices.ContainsFunc(i.visibleEnvVars(), i.HasEnvvar) {
+		ws = append(ws, warnings.Warning{
+			ID:          "legacy-implicit-all-devices",
+			Kind:        warnings.LegacyEnvvar,
+			Message:     "all GPUs are injected into the container since " + EnvVarNvidiaVisibleDevices + " is not set and " + EnvVarCudaVersion + " is set",
+			Remediation: "set " + EnvVarNvidiaVisibleDevices + " to request GPUs explicitly",
+		})
+	}
+	return ws
+}
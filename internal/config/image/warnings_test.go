@@ -0,0 +1,66 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package image
+
+import (
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWarnings(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	testCases := []struct {
+		description string
+		env         []string
+		expectedIDs []string
+	}{
+		{
+			description: "non-legacy image",
+			env:         []string{"CUDA_VERSION=12.4", "NVIDIA_REQUIRE_CUDA=cuda>=12.4"},
+		},
+		{
+			description: "legacy image without visible devices",
+			env:         []string{"CUDA_VERSION=9.0"},
+			expectedIDs: []string{"legacy-cuda-version-requirement", "legacy-implicit-all-devices"},
+		},
+		{
+			description: "legacy image with visible devices",
+			env:         []string{"CUDA_VERSION=9.0", "NVIDIA_VISIBLE_DEVICES=0"},
+			expectedIDs: []string{"legacy-cuda-version-requirement"},
+		},
+		{
+			description: "legacy image with requirements disabled",
+			env:         []string{"CUDA_VERSION=9.0", "NVIDIA_VISIBLE_DEVICES=0", "NVIDIA_DISABLE_REQUIRE=true"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			image, err := New(WithLogger(logger), WithEnv(tc.env))
+			require.NoError(t, err)
+
+			var ids []string
+			for _, w := range image.Warnings() {
+				ids = append(ids, w.ID)
+			}
+			require.EqualValues(t, tc.expectedIDs, ids)
+		})
+	}
+}
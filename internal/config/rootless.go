@@ -0,0 +1,71 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// isRootless is used to determine whether the current process was started by
+// a rootless container engine. This is a variable to allow it to be
+// overridden in tests.
+var isRootless = runningRootless
+
+// IsRootless returns true if the current process was started by a rootless
+// container engine.
+func IsRootless() bool {
+	return isRootless()
+}
+
+// runningRootless returns true if the process is running as a non-root user
+// or in a user namespace that does not map the full range of host IDs as is
+// the case for rootless container engines such as rootless Podman or Docker.
+func runningRootless() bool {
+	if os.Geteuid() != 0 {
+		return true
+	}
+
+	uidMap, err := os.ReadFile("/proc/self/uid_map")
+	if err != nil {
+		return false
+	}
+	return !isInitialUIDMap(string(uidMap))
+}
+
+// isInitialUIDMap checks whether the specified uid_map contents correspond to
+// those of the initial user namespace.
+func isInitialUIDMap(uidMap string) bool {
+	fields := strings.Fields(uidMap)
+	return len(fields) == 3 && fields[0] == "0" && fields[1] == "0" && fields[2] == "4294967295"
+}
+
+// GetUserConfigFilePath returns the path to the per-user config file. This is
+// read from $XDG_CONFIG_HOME if set and from $HOME/.config otherwise. An empty
+// string is returned if the path cannot be determined.
+func GetUserConfigFilePath() string {
+	configRoot := os.Getenv(configRootOverride)
+	if configRoot == "" {
+		home, err := os.UserHomeDir()
+		if err != nil || home == "" {
+			return ""
+		}
+		configRoot = filepath.Join(home, ".config")
+	}
+	return filepath.Join(configRoot, RelativeFilePath)
+}
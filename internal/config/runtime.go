@@ -16,15 +16,230 @@
 
 package config
 
+import (
+	"fmt"
+	"time"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/units"
+)
+
 // RuntimeConfig stores the config options for the NVIDIA Container Runtime
 type RuntimeConfig struct {
 	DebugFilePath string `toml:"debug"`
 	// LogLevel defines the logging level for the application
 	LogLevel string `toml:"log-level"`
 	// Runtimes defines the candidates for the low-level runtime
-	Runtimes []string    `toml:"runtimes"`
-	Mode     string      `toml:"mode"`
-	Modes    modesConfig `toml:"modes"`
+	Runtimes []string `toml:"runtimes"`
+	// MinimumRuntimeVersion optionally defines the minimum version of the
+	// selected low-level runtime. The version is determined by parsing the
+	// output of the runtime's --version flag when a container is created.
+	MinimumRuntimeVersion string      `toml:"minimum-runtime-version,omitempty"`
+	Mode                  string      `toml:"mode"`
+	Modes                 modesConfig `toml:"modes"`
+	// Platform optionally overrides the platform detection used to resolve
+	// the "auto" mode. Supported values are "auto", "tegra", and "desktop".
+	Platform string `toml:"platform,omitempty"`
+	// ModifierPlugins defines external executables that are used to modify
+	// the OCI runtime specification before or after the NVIDIA modifications
+	// are applied.
+	ModifierPlugins modifierPluginsConfig `toml:"modifier-plugins,omitempty"`
+	// MetricsFilePath optionally defines the file to which the NVIDIA
+	// Container Runtime records injection counters and errors. These are
+	// exposed as Prometheus metrics by the nvidia-ctk metrics serve command.
+	// If this is empty, no metrics are recorded.
+	MetricsFilePath string `toml:"metrics-file,omitempty"`
+	// InjectedLibrariesFilePath optionally defines the file in which the
+	// NVIDIA Container Runtime records the driver libraries that are mounted
+	// into each container. This is used by the nvidia-ctk runtime verify
+	// --containers command to detect containers that require a restart after
+	// a driver upgrade. If this is empty, no libraries are recorded.
+	InjectedLibrariesFilePath string `toml:"injected-libraries-file,omitempty"`
+	// ComputeCacheTmpfsSize optionally defines the size (e.g. 256m) of a tmpfs
+	// that is mounted at ~/.nv/ComputeCache in containers that request GPUs.
+	// This ensures that the CUDA JIT cache can be written for containers with
+	// a read-only root filesystem. If this is empty, no tmpfs is mounted.
+	ComputeCacheTmpfsSize string `toml:"compute-cache-tmpfs-size,omitempty"`
+	// ComputeCacheHostPath optionally defines a directory on the host that
+	// persists the CUDA JIT cache across containers. A subdirectory for each
+	// (host) user is bind-mounted at ~/.nv/ComputeCache in containers that
+	// request GPUs and set the nvidia.com/persistent-compute-cache=true
+	// annotation. For these containers, this takes precedence over
+	// ComputeCacheTmpfsSize.
+	//
+	// Since the annotation can be set by users, the cache is only isolated
+	// by the host user and, for containers in Kubernetes pods, the pod
+	// namespace. Outside of Kubernetes, all opted-in containers that run as
+	// the same host user (e.g. root containers without a user namespace)
+	// share a cache and can modify the kernels that the others load. This
+	// should only be configured if these containers trust each other.
+	ComputeCacheHostPath string `toml:"compute-cache-host-path,omitempty"`
+	// ComputeCacheMaxSize optionally limits the size (e.g. 1g) of the CUDA JIT
+	// cache by setting the CUDA_CACHE_MAXSIZE envvar in containers for which
+	// a compute cache is mounted. The envvar is not overridden if set by the
+	// container.
+	ComputeCacheMaxSize string `toml:"compute-cache-max-size,omitempty"`
+	// ScrubEnvvars optionally defines the envvars that are removed from the
+	// container environment after all modifications have been applied. Both
+	// envvar names and shell patterns such as NVIDIA_* are supported. This
+	// is ignored in legacy mode.
+	ScrubEnvvars []string `toml:"scrub-envvars,omitempty"`
+	// DiscoveryRetries optionally configures retries for the discovery of
+	// driver files and devices when generating CDI specifications at runtime.
+	DiscoveryRetries discoveryRetriesConfig `toml:"discovery-retries,omitempty"`
+	// InjectionStrategy defines how driver libraries are made available in
+	// the container. Supported values are bind (the default) and copy. With
+	// copy, library bind mounts are replaced by files copied into the
+	// container root filesystem unless the root filesystem is read-only. This
+	// is ignored in legacy mode.
+	InjectionStrategy string `toml:"injection-strategy,omitempty"`
+	// GPUReset optionally resets the GPUs injected into a container before the
+	// container is started. Supported values are clean (reset the compute mode,
+	// locked clocks, and accounting data) and reset (a full GPU reset). This is
+	// intended for nodes where GPUs are dedicated to a single container.
+	GPUReset string `toml:"gpu-reset,omitempty"`
+	// ExistingHooks configures how NVIDIA Container Runtime hooks that are
+	// already present in the OCI runtime specification (e.g. hooks inserted
+	// by docker --gpus) are handled. This is ignored in legacy mode.
+	ExistingHooks existingHooksConfig `toml:"existing-hooks,omitempty"`
+	// CheckpointDirectory optionally specifies a host directory for CUDA
+	// checkpoint artifacts. Containers that request this through the
+	// nvidia.com/checkpoint-directory annotation get a per-pod (or
+	// per-container) subdirectory of this directory mounted at the same path
+	// in the container. The path is set in the NVIDIA_CHECKPOINT_DIR envvar.
+	CheckpointDirectory string `toml:"checkpoint-directory,omitempty"`
+	// MPSPipeDirectory optionally overrides the pipe directory of the CUDA
+	// Multi-Process Service (MPS) control daemon on the host. This is used if
+	// the mps-sharing feature is enabled and defaults to /tmp/nvidia-mps.
+	MPSPipeDirectory string `toml:"mps-pipe-directory,omitempty"`
+	// MaxDevices optionally limits the number of devices that a single
+	// container may request. Containers that request more devices, or all
+	// devices, are rejected. If this is 0, the number of devices is not
+	// limited.
+	MaxDevices int `toml:"max-devices,omitempty"`
+	// MissingCapabilityPolicy optionally defines how requested driver
+	// capabilities (e.g. video) are handled if none of the libraries for the
+	// capability are found on the host. Supported values are fail (the
+	// container is rejected) and downgrade (the remaining capabilities are
+	// injected and the nvidia.com/downgraded-driver-capabilities annotation is
+	// set). Capabilities that are not included are not checked.
+	MissingCapabilityPolicy map[string]string `toml:"missing-capability-policy,omitempty"`
+	// ResourceConstrained reduces the overhead of the NVIDIA Container Runtime
+	// on embedded systems such as Jetson devices. The platform is not probed
+	// using NVML or the CUDA driver, only the static CSV-based discovery is
+	// supported, and modifiers that are not required to inject the requested
+	// devices (e.g. to collect diagnostics or record metrics) are skipped.
+	// The CUDA version, compute capability, and device memory requirements of
+	// an image are not checked, and the hybrid CSV mode is not supported.
+	ResourceConstrained bool `toml:"resource-constrained,omitempty"`
+	// AdditionalDeviceNodes optionally defines the patterns of device nodes
+	// (e.g. /dev/vfio/*) that are injected in addition to the device nodes
+	// discovered by the NVIDIA Container Toolkit. The device nodes are keyed
+	// by the driver capability that they are required for, with the "all"
+	// capability indicating that these are always injected. This allows
+	// device nodes of out-of-tree drivers to be injected without code changes.
+	AdditionalDeviceNodes map[string][]string `toml:"additional-device-nodes,omitempty"`
+	// FilterLibrariesByCapability indicates whether the driver libraries
+	// injected for CDI specifications generated at runtime (including those
+	// generated from CSV files) are filtered by the driver capabilities
+	// requested by a container. Libraries are assigned to driver capabilities
+	// using library groups.
+	FilterLibrariesByCapability bool `toml:"filter-libraries-by-capability,omitempty"`
+	// LibraryGroupsFile optionally defines a TOML file that overrides the
+	// default library groups for one or more driver capabilities. These
+	// groups are also used to detect missing driver capabilities.
+	LibraryGroupsFile string `toml:"library-groups-file,omitempty"`
+	// NestedContainersRuntimes optionally defines the low-level runtimes
+	// (e.g. sysbox-runc) for which containers are prepared for running nested
+	// GPU containers if the nested-containers feature is enabled. These are
+	// matched against the prefix of the base name of the low-level runtime.
+	// If this is empty, only containers run by sysbox-runc are prepared.
+	NestedContainersRuntimes []string `toml:"nested-containers-runtimes,omitempty"`
+}
+
+// assertValid checks the runtime config for values that would otherwise
+// cause the creation of each container to fail.
+func (c *RuntimeConfig) assertValid() error {
+	if c.ComputeCacheMaxSize != "" {
+		if _, err := units.ParseSize(c.ComputeCacheMaxSize); err != nil {
+			return fmt.Errorf("invalid compute-cache-max-size: %w", err)
+		}
+	}
+	if c.ResourceConstrained && c.Modes.CSV.Hybrid {
+		return fmt.Errorf("the hybrid CSV mode is not supported if resource-constrained is enabled")
+	}
+	if c.DiscoveryRetries.Backoff != "" {
+		if _, err := time.ParseDuration(c.DiscoveryRetries.Backoff); err != nil {
+			return fmt.Errorf("invalid discovery-retries.backoff: %w", err)
+		}
+	}
+	if c.ModifierPlugins.Timeout != "" {
+		if _, err := time.ParseDuration(c.ModifierPlugins.Timeout); err != nil {
+			return fmt.Errorf("invalid modifier-plugins.timeout: %w", err)
+		}
+	}
+	return nil
+}
+
+// existingHooksConfig defines the policy for existing NVIDIA Container Runtime
+// hooks.
+type existingHooksConfig struct {
+	// Policy is one of remove (the default), keep-if-compatible, or fail.
+	Policy string `toml:"policy,omitempty"`
+	// Patterns optionally defines additional shell patterns (e.g.
+	// nvidia-container-runtime-hook.*) that identify NVIDIA Container Runtime
+	// hooks. These are matched against the base names of the hook path and
+	// arguments.
+	Patterns []string `toml:"patterns,omitempty"`
+}
+
+// discoveryRetriesConfig defines how discovery operations that fail due to
+// transient conditions (e.g. a driver that is still loading) are retried.
+type discoveryRetriesConfig struct {
+	// Attempts defines the maximum number of times discovery is attempted.
+	// If this is less than 2, discovery is not retried.
+	Attempts int `toml:"attempts,omitempty"`
+	// Backoff defines the time (e.g. 500ms) to wait before the first retry.
+	// The time is doubled for each subsequent retry up to a maximum of 5s.
+	Backoff string `toml:"backoff,omitempty"`
+}
+
+// GetBackoff returns the time to wait before the first retry. Since the
+// backoff is validated when the config is loaded, an invalid value is treated
+// as unset.
+func (c discoveryRetriesConfig) GetBackoff() time.Duration {
+	backoff, _ := time.ParseDuration(c.Backoff)
+	return backoff
+}
+
+// modifierPluginsConfig defines the modifier plugins to apply.
+// Each plugin is an executable that receives the JSON-encoded OCI runtime
+// specification on STDIN and outputs the modified specification to STDOUT.
+// Plugins are applied in the order that they are specified. Fields in the
+// output of a plugin that are not supported by the runtime-spec version of
+// the NVIDIA Container Runtime are dropped.
+type modifierPluginsConfig struct {
+	// Pre defines the plugins applied before the NVIDIA modifications.
+	Pre []string `toml:"pre,omitempty"`
+	// Post defines the plugins applied after the NVIDIA modifications.
+	Post []string `toml:"post,omitempty"`
+	// Timeout defines the time (e.g. 5s) after which a plugin is killed and
+	// the creation of the container fails. This is 10s if not specified.
+	Timeout string `toml:"timeout,omitempty"`
+}
+
+// defaultModifierPluginTimeout is the time after which a plugin is killed if
+// no timeout is configured.
+const defaultModifierPluginTimeout = 10 * time.Second
+
+// GetTimeout returns the time after which a plugin is killed. Since the
+// timeout is validated when the config is loaded, an invalid value is treated
+// as unset.
+func (c modifierPluginsConfig) GetTimeout() time.Duration {
+	timeout, err := time.ParseDuration(c.Timeout)
+	if err != nil || timeout <= 0 {
+		return defaultModifierPluginTimeout
+	}
+	return timeout
 }
 
 // modesConfig defines (optional) per-mode configs
@@ -41,10 +256,39 @@ type cdiModeConfig struct {
 	DefaultKind string `toml:"default-kind"`
 	// AnnotationPrefixes sets the allowed prefixes for CDI annotation-based device injection
 	AnnotationPrefixes []string `toml:"annotation-prefixes"`
+	// LoadKernelModules indicates whether an attempt should be made to load
+	// the NVIDIA kernel modules if these are not loaded when generating CDI
+	// specifications at runtime. The kernel modules are not checked on WSL and
+	// Tegra-based systems.
+	LoadKernelModules bool `toml:"load-kernel-modules,omitempty"`
+	// DeviceNodeHelperSocket optionally sets the socket of a device node
+	// helper started using nvidia-ctk system device-node-helper (or the
+	// nvidia-device-node-helper.socket systemd unit). If set, the helper is
+	// used to create missing control device nodes when generating CDI
+	// specifications at runtime.
+	DeviceNodeHelperSocket string `toml:"device-node-helper-socket,omitempty"`
+	// DeviceOrder sets the order in which GPUs are enumerated when resolving
+	// device indices for CDI specifications generated at runtime. One of
+	// "nvml" (the default) or "pci-bus-id".
+	DeviceOrder string `toml:"device-order,omitempty"`
+	// DiscoveryManifest optionally defines a discovery manifest generated by
+	// the nvidia-ctk info --export command. If set, the CDI specifications
+	// for the requested devices are constructed from the manifest instead of
+	// probing the system when a container is created.
+	DiscoveryManifest string `toml:"discovery-manifest,omitempty"`
 }
 
 type csvModeConfig struct {
+	// MountSpecPath is a comma-separated list of directories containing CSV
+	// files. Files in later directories override those with the same name in
+	// earlier directories.
 	MountSpecPath string `toml:"mount-spec-path"`
+	// Hybrid enables support for systems with both an integrated (iGPU) and
+	// a discrete (dGPU) GPU. If enabled, the iGPU is requested using the
+	// "igpu" device name and injected based on the CSV mount specs, while
+	// other requested devices refer to dGPUs and are injected using CDI
+	// specifications generated from NVML.
+	Hybrid bool `toml:"hybrid,omitempty"`
 }
 
 type legacyModeConfig struct {
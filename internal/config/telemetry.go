@@ -0,0 +1,31 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package config
+
+// TelemetryConfig stores the options for the opt-in reporting of anonymous
+// usage statistics. Telemetry is disabled by default.
+type TelemetryConfig struct {
+	// Enabled enables the recording of aggregate usage statistics by the
+	// NVIDIA Container Runtime and the reporting of these statistics by the
+	// nvidia-ctk telemetry send command.
+	Enabled bool `toml:"enabled,omitempty"`
+	// Endpoint is the URL to which the aggregate statistics are reported.
+	Endpoint string `toml:"endpoint,omitempty"`
+	// FilePath optionally overrides the file in which the statistics are
+	// aggregated between reports.
+	FilePath string `toml:"file,omitempty"`
+}
@@ -30,8 +30,9 @@ import (
 type Toml toml.Tree
 
 type options struct {
-	configFile string
-	required   bool
+	configFile  string
+	overlayFile string
+	required    bool
 }
 
 // Option is a functional option for loading TOML config files.
@@ -44,6 +45,15 @@ func WithConfigFile(configFile string) Option {
 	}
 }
 
+// WithOverlayFile sets a config file that is merged over the loaded config.
+// Values that are set in the overlay file take precedence. The overlay file is
+// optional and is ignored if it does not exist.
+func WithOverlayFile(overlayFile string) Option {
+	return func(o *options) {
+		o.overlayFile = overlayFile
+	}
+}
+
 // WithRequired sets the required option.
 // If this is set to true, a failure to open the specified file is treated as an error
 func WithRequired(required bool) Option {
@@ -59,7 +69,14 @@ func New(opts ...Option) (*Toml, error) {
 		opt(o)
 	}
 
-	return o.loadConfigToml()
+	t, err := o.loadConfigToml()
+	if err != nil {
+		return nil, err
+	}
+	if err := t.mergeFile(o.overlayFile); err != nil {
+		return nil, err
+	}
+	return t, nil
 }
 
 func (o options) loadConfigToml() (*Toml, error) {
@@ -106,6 +123,45 @@ func loadConfigTomlFrom(reader io.Reader) (*Toml, error) {
 	return (*Toml)(tree), nil
 }
 
+// mergeFile merges the contents of the specified file over the toml tree.
+// If the file does not exist, the tree is not modified.
+func (t *Toml) mergeFile(filename string) error {
+	if filename == "" {
+		return nil
+	}
+	overlayFile, err := os.Open(filename)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to load overlay config file: %w", err)
+	}
+	defer overlayFile.Close()
+
+	overlay, err := loadConfigTomlFrom(overlayFile)
+	if err != nil {
+		return fmt.Errorf("failed to parse overlay config file %v: %w", filename, err)
+	}
+	mergeTrees((*toml.Tree)(t), (*toml.Tree)(overlay), nil)
+	return nil
+}
+
+// mergeTrees recursively sets the values of the overlay tree in the base
+// tree. Tables present in both trees are merged, while all other values in
+// the overlay replace those in the base.
+func mergeTrees(base *toml.Tree, overlay *toml.Tree, prefix []string) {
+	for _, key := range overlay.Keys() {
+		path := append(append([]string{}, prefix...), key)
+		value := overlay.GetPath([]string{key})
+		if subtree, ok := value.(*toml.Tree); ok {
+			if _, ok := base.GetPath(path).(*toml.Tree); ok {
+				mergeTrees(base, subtree, path)
+				continue
+			}
+		}
+		base.SetPath(path, value)
+	}
+}
+
 // Config returns the typed config associated with the toml tree.
 func (t *Toml) Config() (*Config, error) {
 	cfg, err := t.configNoOverrides()
@@ -18,6 +18,8 @@ package config
 
 import (
 	"bytes"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -44,7 +46,7 @@ func TestTomlSave(t *testing.T) {
 #accept-nvidia-visible-devices-as-volume-mounts = false
 #accept-nvidia-visible-devices-envvar-when-unprivileged = true
 disable-require = false
-supported-driver-capabilities = "compat32,compute,display,graphics,ngx,utility,video"
+supported-driver-capabilities = "compat32,compute,display,graphics,ngx,profiling,utility,video"
 #swarm-resource = "DOCKER_RESOURCE_GPU"
 
 [nvidia-container-cli]
@@ -241,6 +243,29 @@ func TestConfigFromToml(t *testing.T) {
 			},
 			expectedError: errInvalidConfig,
 		},
+		{
+			description: "invalid compute cache max size raises error",
+			contents: map[string]interface{}{
+				"nvidia-container-runtime": map[string]interface{}{
+					"compute-cache-max-size": "lots",
+				},
+			},
+			expectedError: errInvalidConfig,
+		},
+		{
+			description: "hybrid csv mode on resource-constrained system raises error",
+			contents: map[string]interface{}{
+				"nvidia-container-runtime": map[string]interface{}{
+					"resource-constrained": true,
+					"modes": map[string]interface{}{
+						"csv": map[string]interface{}{
+							"hybrid": true,
+						},
+					},
+				},
+			},
+			expectedError: errInvalidConfig,
+		},
 		{
 			description: "feature allows ldconfig override",
 			contents: map[string]interface{}{
@@ -278,3 +303,69 @@ func fromMap(c map[string]interface{}) *Toml {
 func createEmpty() *Toml {
 	return fromMap(nil)
 }
+
+func TestNewWithOverlayFile(t *testing.T) {
+	testCases := []struct {
+		description     string
+		system          string
+		overlay         string
+		expectedDebug   string
+		expectedMode    string
+		expectedRuntime []string
+	}{
+		{
+			description:     "missing overlay uses system config",
+			system:          "[nvidia-container-runtime]\nmode = \"cdi\"\nruntimes = [\"crun\"]\n",
+			expectedDebug:   "/dev/null",
+			expectedMode:    "cdi",
+			expectedRuntime: []string{"crun"},
+		},
+		{
+			description:     "overlay values take precedence",
+			system:          "[nvidia-container-runtime]\nmode = \"cdi\"\nruntimes = [\"crun\"]\n",
+			overlay:         "[nvidia-container-runtime]\ndebug = \"/tmp/runtime.log\"\nmode = \"legacy\"\n",
+			expectedDebug:   "/tmp/runtime.log",
+			expectedMode:    "legacy",
+			expectedRuntime: []string{"crun"},
+		},
+		{
+			description:     "overlay is merged over defaults",
+			overlay:         "[nvidia-container-runtime]\nmode = \"cdi\"\n",
+			expectedDebug:   "/dev/null",
+			expectedMode:    "cdi",
+			expectedRuntime: []string{"runc", "crun"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			testDir := t.TempDir()
+			systemFile := filepath.Join(testDir, "system.toml")
+			overlayFile := filepath.Join(testDir, "user.toml")
+			if tc.system != "" {
+				require.NoError(t, os.WriteFile(systemFile, []byte(tc.system), 0600))
+			}
+			if tc.overlay != "" {
+				require.NoError(t, os.WriteFile(overlayFile, []byte(tc.overlay), 0600))
+			}
+
+			tomlConfig, err := New(
+				WithConfigFile(systemFile),
+				WithOverlayFile(overlayFile),
+			)
+			require.NoError(t, err)
+
+			cfg, err := tomlConfig.configNoOverrides()
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedDebug, cfg.NVIDIAContainerRuntimeConfig.DebugFilePath)
+			require.Equal(t, tc.expectedMode, cfg.NVIDIAContainerRuntimeConfig.Mode)
+			require.EqualValues(t, tc.expectedRuntime, cfg.NVIDIAContainerRuntimeConfig.Runtimes)
+		})
+	}
+}
+
+func TestIsInitialUIDMap(t *testing.T) {
+	require.True(t, isInitialUIDMap("         0          0 4294967295\n"))
+	require.False(t, isInitialUIDMap("         0       1000          1\n         1     100000      65536\n"))
+	require.False(t, isInitialUIDMap(""))
+}
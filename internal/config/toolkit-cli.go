@@ -19,4 +19,10 @@ package config
 // CTKConfig stores the config options for the NVIDIA Container Toolkit CLI (nvidia-ctk)
 type CTKConfig struct {
 	Path string `toml:"path"`
+	// HookPaths optionally overrides the executable used for specific hooks
+	// such as update-ldcache, create-symlinks, or chmod. This allows
+	// distributions that package these helpers separately to be supported.
+	// Hooks that are not included use the executable specified by Path. The
+	// keys must be the names of predefined hooks.
+	HookPaths map[string]string `toml:"hook-paths,omitempty"`
 }
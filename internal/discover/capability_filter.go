@@ -0,0 +1,71 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package discover
+
+import (
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config/image"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/librarygroups"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+)
+
+// capabilityFilter filters the mounts of a discoverer by the driver
+// capabilities that they are required for.
+type capabilityFilter struct {
+	Discover
+	logger       logger.Interface
+	groups       librarygroups.Groups
+	capabilities image.DriverCapabilities
+}
+
+var _ Discover = (*capabilityFilter)(nil)
+
+// NewCapabilityFilter creates a discoverer that only returns the mounts of the
+// specified discoverer that are required for the specified driver
+// capabilities. The library groups assign mounts to driver capabilities (e.g.
+// compute, utility, video, graphics, or ngx) based on their file names.
+// Mounts that are not in any of the groups are always returned. If no groups
+// or capabilities are specified, the discoverer is returned as is.
+func NewCapabilityFilter(logger logger.Interface, d Discover, groups librarygroups.Groups, capabilities image.DriverCapabilities) Discover {
+	if d == nil || groups == nil || capabilities == nil {
+		return d
+	}
+	return &capabilityFilter{
+		Discover:     d,
+		logger:       logger,
+		groups:       groups,
+		capabilities: capabilities,
+	}
+}
+
+// Mounts returns the mounts that are required for the requested driver
+// capabilities.
+func (d *capabilityFilter) Mounts() ([]Mount, error) {
+	mounts, err := d.Discover.Mounts()
+	if err != nil {
+		return nil, err
+	}
+
+	var selected []Mount
+	for _, mount := range mounts {
+		if !d.groups.IsRequiredFor(mount.Path, d.capabilities) {
+			d.logger.Debugf("Skipping %v; not required for driver capabilities %v", mount.Path, d.capabilities)
+			continue
+		}
+		selected = append(selected, mount)
+	}
+	return selected, nil
+}
@@ -0,0 +1,103 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package discover
+
+import (
+	"fmt"
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config/image"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/librarygroups"
+)
+
+func TestCapabilityFilter(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	mounts := []Mount{
+		{Path: "/usr/lib/aarch64-linux-gnu/tegra/libcuda.so.1.1", HostPath: "/usr/lib/aarch64-linux-gnu/tegra/libcuda.so.1.1"},
+		{Path: "/usr/lib/aarch64-linux-gnu/tegra/libnvidia-ml.so.1", HostPath: "/usr/lib/aarch64-linux-gnu/tegra/libnvidia-ml.so.1"},
+		{Path: "/usr/lib/aarch64-linux-gnu/tegra/libnvidia-unclassified.so.1", HostPath: "/usr/lib/aarch64-linux-gnu/tegra/libnvidia-unclassified.so.1"},
+	}
+	groups := librarygroups.Groups{
+		image.DriverCapabilityCompute: {"libcuda.so.*"},
+		image.DriverCapabilityUtility: {"libnvidia-ml.so.*"},
+	}
+
+	testCases := []struct {
+		description    string
+		groups         librarygroups.Groups
+		capabilities   image.DriverCapabilities
+		mountsError    error
+		expectedMounts []Mount
+		expectedError  error
+	}{
+		{
+			description:    "no groups returns all mounts",
+			capabilities:   image.NewDriverCapabilities("compute"),
+			expectedMounts: mounts,
+		},
+		{
+			description:    "no capabilities returns all mounts",
+			groups:         groups,
+			expectedMounts: mounts,
+		},
+		{
+			description:  "unrequested libraries are filtered",
+			groups:       groups,
+			capabilities: image.NewDriverCapabilities("compute"),
+			expectedMounts: []Mount{
+				mounts[0],
+				mounts[2],
+			},
+		},
+		{
+			description:    "all capabilities returns all mounts",
+			groups:         groups,
+			capabilities:   image.NewDriverCapabilities("all"),
+			expectedMounts: mounts,
+		},
+		{
+			description:   "mounts error is returned",
+			groups:        groups,
+			capabilities:  image.NewDriverCapabilities("compute"),
+			mountsError:   fmt.Errorf("failed"),
+			expectedError: fmt.Errorf("failed"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			d := &DiscoverMock{
+				MountsFunc: func() ([]Mount, error) {
+					if tc.mountsError != nil {
+						return nil, tc.mountsError
+					}
+					return mounts, nil
+				},
+			}
+
+			filtered := NewCapabilityFilter(logger, d, tc.groups, tc.capabilities)
+
+			m, err := filtered.Mounts()
+			require.EqualValues(t, tc.expectedError, err)
+			require.EqualValues(t, tc.expectedMounts, m)
+		})
+	}
+}
@@ -33,6 +33,9 @@ type Mount struct {
 	HostPath string
 	Path     string
 	Options  []string
+	// Type is the filesystem type of the mount. If this is empty, the mount
+	// is a bind mount of the host path.
+	Type string
 }
 
 // Hook represents a discovered hook.
@@ -0,0 +1,92 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package discover
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+)
+
+type dpuDiscoverer struct {
+	None
+	logger  logger.Interface
+	devRoot string
+	devices Discover
+}
+
+// NewDPUDiscoverer creates a discoverer for the device nodes and mounts that
+// are required by DOCA and GPUDirect RDMA applications on systems with
+// BlueField DPUs or converged accelerators. This extends the MOFED devices
+// with the management datagram device nodes and a hugetlbfs mount used by
+// DPDK-based applications such as DOCA GPUNetIO.
+func NewDPUDiscoverer(logger logger.Interface, devRoot string) (Discover, error) {
+	mofed, err := NewMOFEDDiscoverer(logger, devRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	umad := NewCharDeviceDiscoverer(
+		logger,
+		devRoot,
+		[]string{
+			"/dev/infiniband/umad*",
+		},
+	)
+
+	d := dpuDiscoverer{
+		logger:  logger,
+		devRoot: devRoot,
+		devices: Merge(mofed, umad),
+	}
+
+	return &d, nil
+}
+
+// Devices discovers the RDMA device nodes of the DPU.
+func (d *dpuDiscoverer) Devices() ([]Device, error) {
+	return d.devices.Devices()
+}
+
+// Mounts returns a hugetlbfs mount at /dev/hugepages if hugepages are
+// configured on the host (i.e. /dev/hugepages exists). A new hugetlbfs
+// instance is mounted for each container instead of the hugetlbfs of the host
+// so that containers cannot access the hugepages of other containers. Note
+// that the hugepages are still allocated from the pool of the host and should
+// be limited using the hugetlb cgroup controller.
+// If no devices are discovered the discovered mounts are empty.
+func (d *dpuDiscoverer) Mounts() ([]Mount, error) {
+	devices, err := d.Devices()
+	if err != nil || len(devices) == 0 {
+		d.logger.Debugf("No RDMA devices detected; skipping detection of mounts")
+		return nil, nil
+	}
+
+	if info, err := os.Stat(filepath.Join(d.devRoot, "/dev/hugepages")); err != nil || !info.IsDir() {
+		d.logger.Debugf("No hugepages configured; skipping hugetlbfs mount")
+		return nil, nil
+	}
+
+	hugepages := Mount{
+		HostPath: "nodev",
+		Path:     "/dev/hugepages",
+		Type:     "hugetlbfs",
+		Options:  []string{"nosuid", "nodev", "mode=1777"},
+	}
+	return []Mount{hugepages}, nil
+}
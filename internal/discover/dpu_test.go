@@ -0,0 +1,92 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package discover
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDPUDiscoverer(t *testing.T) {
+	t.Setenv("__NVCT_TESTING_DEVICES_ARE_FILES", "true")
+	logger, _ := testlog.NewNullLogger()
+
+	testCases := []struct {
+		description     string
+		files           []string
+		dirs            []string
+		expectedDevices []string
+		expectedMounts  []string
+	}{
+		{
+			description: "no rdma devices",
+			dirs:        []string{"dev/hugepages"},
+		},
+		{
+			description:     "rdma devices without hugepages",
+			files:           []string{"dev/infiniband/uverbs0", "dev/infiniband/uverbs1", "dev/infiniband/rdma_cm"},
+			expectedDevices: []string{"/dev/infiniband/uverbs0", "/dev/infiniband/uverbs1", "/dev/infiniband/rdma_cm"},
+		},
+		{
+			description:     "rdma devices with hugepages",
+			files:           []string{"dev/infiniband/uverbs0", "dev/infiniband/umad0"},
+			dirs:            []string{"dev/hugepages"},
+			expectedDevices: []string{"/dev/infiniband/uverbs0", "/dev/infiniband/umad0"},
+			expectedMounts:  []string{"/dev/hugepages"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			devRoot := t.TempDir()
+			for _, dir := range tc.dirs {
+				require.NoError(t, os.MkdirAll(filepath.Join(devRoot, dir), 0755))
+			}
+			for _, file := range tc.files {
+				require.NoError(t, os.MkdirAll(filepath.Join(devRoot, filepath.Dir(file)), 0755))
+				require.NoError(t, os.WriteFile(filepath.Join(devRoot, file), nil, 0600))
+			}
+
+			d, err := NewDPUDiscoverer(logger, devRoot)
+			require.NoError(t, err)
+
+			devices, err := d.Devices()
+			require.NoError(t, err)
+			var devicePaths []string
+			for _, device := range devices {
+				require.Equal(t, filepath.Join(devRoot, device.Path), device.HostPath)
+				devicePaths = append(devicePaths, device.Path)
+			}
+			require.ElementsMatch(t, tc.expectedDevices, devicePaths)
+
+			mounts, err := d.Mounts()
+			require.NoError(t, err)
+			var mountPaths []string
+			for _, mount := range mounts {
+				// The hugetlbfs of the host is not shared with containers.
+				require.Equal(t, "hugetlbfs", mount.Type)
+				require.Equal(t, "nodev", mount.HostPath)
+				mountPaths = append(mountPaths, mount.Path)
+			}
+			require.ElementsMatch(t, tc.expectedMounts, mountPaths)
+		})
+	}
+}
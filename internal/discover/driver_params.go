@@ -0,0 +1,47 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package discover
+
+import (
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup"
+)
+
+// NewDriverParamsDiscoverer creates a discoverer for the folders that expose
+// the parameters of the loaded NVIDIA kernel modules. These are required by
+// CUDA debugging and profiling tools and are mounted read-only. Since the
+// parameters are those of the running kernel, root is the root of the host and
+// not the driver root.
+//
+// Note that /proc/driver/nvidia/params is not included since low-level
+// runtimes such as runc reject bind mounts to /proc. This file is already
+// visible in the container's procfs.
+func NewDriverParamsDiscoverer(logger logger.Interface, root string) Discover {
+	return NewMounts(
+		logger,
+		lookup.NewDirectoryLocator(
+			lookup.WithLogger(logger),
+			lookup.WithRoot(root),
+		),
+		root,
+		[]string{
+			"/sys/module/nvidia/parameters",
+			"/sys/module/nvidia_uvm/parameters",
+			"/sys/module/nvidia_modeset/parameters",
+		},
+	)
+}
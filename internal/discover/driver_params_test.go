@@ -0,0 +1,56 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package discover
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDriverParamsDiscoverer(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "proc/driver/nvidia"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "proc/driver/nvidia/params"), []byte("ResmanDebugLevel: 4294967295\n"), 0444))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "sys/module/nvidia/parameters"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "sys/module/nvidia_uvm/parameters"), 0755))
+
+	mounts, err := NewDriverParamsDiscoverer(logger, root).Mounts()
+	require.NoError(t, err)
+
+	options := []string{"ro", "nosuid", "nodev", "rbind", "rprivate"}
+	require.EqualValues(t,
+		[]Mount{
+			{
+				HostPath: filepath.Join(root, "sys/module/nvidia/parameters"),
+				Path:     "/sys/module/nvidia/parameters",
+				Options:  options,
+			},
+			{
+				HostPath: filepath.Join(root, "sys/module/nvidia_uvm/parameters"),
+				Path:     "/sys/module/nvidia_uvm/parameters",
+				Options:  options,
+			},
+		},
+		mounts,
+	)
+}
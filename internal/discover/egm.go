@@ -0,0 +1,78 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package discover
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+)
+
+type egmDevices struct {
+	None
+	logger    logger.Interface
+	devRoot   string
+	sysfsRoot string
+	busID     string
+}
+
+// NewEGMDiscoverer creates a discoverer for the extended GPU memory (EGM)
+// device nodes on Grace Hopper systems that are associated with the GPU with
+// the specified PCI bus ID. These allow the GPU to access the memory of the
+// Grace CPU as GPU memory. The GPUs associated with an EGM device are listed
+// in the gpu_devices attribute of the device in sysfs. Since sysfs describes
+// the running kernel, sysfsRoot is the root of the host and not the driver
+// root.
+func NewEGMDiscoverer(logger logger.Interface, devRoot string, sysfsRoot string, busID string) Discover {
+	return &egmDevices{
+		logger:    logger,
+		devRoot:   devRoot,
+		sysfsRoot: sysfsRoot,
+		busID:     busID,
+	}
+}
+
+// Devices returns the EGM device nodes that are associated with the GPU.
+func (d *egmDevices) Devices() ([]Device, error) {
+	attributes, err := filepath.Glob(filepath.Join(d.sysfsRoot, "/sys/class/egm/egm*/gpu_devices"))
+	if err != nil {
+		return nil, err
+	}
+
+	var required []string
+	for _, attribute := range attributes {
+		contents, err := os.ReadFile(attribute)
+		if err != nil {
+			d.logger.Warningf("Failed to read %v: %v", attribute, err)
+			continue
+		}
+		for _, busID := range strings.Fields(string(contents)) {
+			if strings.EqualFold(busID, d.busID) {
+				required = append(required, filepath.Join("/dev", filepath.Base(filepath.Dir(attribute))))
+				break
+			}
+		}
+	}
+	if len(required) == 0 {
+		d.logger.Debugf("No EGM devices found for GPU %v", d.busID)
+		return nil, nil
+	}
+
+	return NewCharDeviceDiscoverer(d.logger, d.devRoot, required).Devices()
+}
@@ -0,0 +1,82 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package discover
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEGMDiscoverer(t *testing.T) {
+	t.Setenv("__NVCT_TESTING_DEVICES_ARE_FILES", "true")
+	logger, _ := testlog.NewNullLogger()
+
+	testCases := []struct {
+		description     string
+		gpuDevices      map[string]string
+		busID           string
+		expectedDevices []string
+	}{
+		{
+			description: "no egm devices",
+			busID:       "0009:01:00.0",
+		},
+		{
+			description: "only the egm device of the gpu is returned",
+			gpuDevices: map[string]string{
+				"egm4": "0009:01:00.0\n",
+				"egm5": "0019:01:00.0\n",
+			},
+			busID:           "0009:01:00.0",
+			expectedDevices: []string{"/dev/egm4"},
+		},
+		{
+			description: "gpu without egm device",
+			gpuDevices: map[string]string{
+				"egm5": "0019:01:00.0\n",
+			},
+			busID: "0009:01:00.0",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			root := t.TempDir()
+			for name, contents := range tc.gpuDevices {
+				egmClassPath := filepath.Join(root, "sys/class/egm", name)
+				require.NoError(t, os.MkdirAll(egmClassPath, 0755))
+				require.NoError(t, os.WriteFile(filepath.Join(egmClassPath, "gpu_devices"), []byte(contents), 0644))
+				require.NoError(t, os.MkdirAll(filepath.Join(root, "dev"), 0755))
+				require.NoError(t, os.WriteFile(filepath.Join(root, "dev", name), nil, 0600))
+			}
+
+			d := NewEGMDiscoverer(logger, root, root, tc.busID)
+
+			devices, err := d.Devices()
+			require.NoError(t, err)
+			var devicePaths []string
+			for _, device := range devices {
+				devicePaths = append(devicePaths, device.Path)
+			}
+			require.ElementsMatch(t, tc.expectedDevices, devicePaths)
+		})
+	}
+}
@@ -0,0 +1,70 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package discover
+
+import (
+	"path/filepath"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup"
+)
+
+type gpuSysfs struct {
+	None
+	logger logger.Interface
+	root   string
+	busID  string
+}
+
+// NewGPUSysfsDiscoverer creates a discoverer for the sysfs folders of the GPU
+// with the specified PCI bus ID. This includes the /sys/bus/pci/devices entry
+// of the GPU and the /sys/class/drm entries of its DRM cards. These are
+// required by tools that discover the topology or monitor the power of a GPU
+// and are mounted read-only. Since sysfs describes the running kernel, root
+// is the root of the host and not the driver root.
+func NewGPUSysfsDiscoverer(logger logger.Interface, root string, busID string) Discover {
+	return &gpuSysfs{
+		logger: logger,
+		root:   root,
+		busID:  busID,
+	}
+}
+
+// Mounts returns the read-only mounts for the sysfs folders of the GPU.
+func (d *gpuSysfs) Mounts() ([]Mount, error) {
+	pciDevice := filepath.Join("/sys/bus/pci/devices", d.busID)
+	required := []string{pciDevice}
+
+	cards, err := filepath.Glob(filepath.Join(d.root, pciDevice, "drm", "card[0-9]*"))
+	if err != nil {
+		return nil, err
+	}
+	for _, card := range cards {
+		required = append(required, filepath.Join("/sys/class/drm", filepath.Base(card)))
+	}
+
+	mounts := NewMounts(
+		d.logger,
+		lookup.NewDirectoryLocator(
+			lookup.WithLogger(d.logger),
+			lookup.WithRoot(d.root),
+		),
+		d.root,
+		required,
+	)
+	return mounts.Mounts()
+}
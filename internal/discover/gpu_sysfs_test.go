@@ -0,0 +1,66 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package discover
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGPUSysfsDiscoverer(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	root := t.TempDir()
+	for _, dir := range []string{
+		"sys/bus/pci/devices/0000:3b:00.0/drm/card1",
+		"sys/bus/pci/devices/0000:3b:00.0/drm/renderD128",
+		"sys/bus/pci/devices/0000:86:00.0/drm/card2",
+		"sys/class/drm/card1",
+		"sys/class/drm/card2",
+		"sys/class/drm/renderD128",
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, dir), 0755))
+	}
+
+	mounts, err := NewGPUSysfsDiscoverer(logger, root, "0000:3b:00.0").Mounts()
+	require.NoError(t, err)
+
+	options := []string{"ro", "nosuid", "nodev", "rbind", "rprivate"}
+	require.EqualValues(t,
+		[]Mount{
+			{
+				HostPath: filepath.Join(root, "sys/bus/pci/devices/0000:3b:00.0"),
+				Path:     "/sys/bus/pci/devices/0000:3b:00.0",
+				Options:  options,
+			},
+			{
+				HostPath: filepath.Join(root, "sys/class/drm/card1"),
+				Path:     "/sys/class/drm/card1",
+				Options:  options,
+			},
+		},
+		mounts,
+	)
+
+	mounts, err = NewGPUSysfsDiscoverer(logger, root, "0000:af:00.0").Mounts()
+	require.NoError(t, err)
+	require.Empty(t, mounts)
+}
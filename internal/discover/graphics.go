@@ -21,6 +21,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/config/image"
@@ -120,10 +121,6 @@ func newGraphicsLibrariesDiscoverer(logger logger.Interface, driver *root.Driver
 	if err != nil {
 		return nil, fmt.Errorf("failed to get driver version: %w", err)
 	}
-	cudaLibRoot, err := driver.GetLibcudaParentDir()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get libcuda.so parent directory: %w", err)
-	}
 
 	libraries := NewMounts(
 		logger,
@@ -145,7 +142,31 @@ func newGraphicsLibrariesDiscoverer(logger logger.Interface, driver *root.Driver
 		},
 	)
 
-	xorgLibraries := NewMounts(
+	xorgLibraries, err := newXorgModulesDiscoverer(logger, driver)
+	if err != nil {
+		return nil, err
+	}
+
+	return &graphicsDriverLibraries{
+		Discover:    Merge(libraries, xorgLibraries),
+		logger:      logger,
+		hookCreator: hookCreator,
+	}, nil
+}
+
+// newXorgModulesDiscoverer creates a discoverer for the NVIDIA Xorg driver
+// modules.
+func newXorgModulesDiscoverer(logger logger.Interface, driver *root.Driver) (Discover, error) {
+	cudaVersionPattern, err := driver.Version()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get driver version: %w", err)
+	}
+	cudaLibRoot, err := driver.GetLibcudaParentDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get libcuda.so parent directory: %w", err)
+	}
+
+	xorgModules := NewMounts(
 		logger,
 		lookup.NewFileLocator(
 			lookup.WithLogger(logger),
@@ -159,12 +180,7 @@ func newGraphicsLibrariesDiscoverer(logger logger.Interface, driver *root.Driver
 			"libglxserver_nvidia.so." + cudaVersionPattern,
 		},
 	)
-
-	return &graphicsDriverLibraries{
-		Discover:    Merge(libraries, xorgLibraries),
-		logger:      logger,
-		hookCreator: hookCreator,
-	}, nil
+	return xorgModules, nil
 }
 
 // Mounts discovers the required libraries and filters out libnvidia-allocator.so.
@@ -379,38 +395,65 @@ func newDRMDeviceDiscoverer(logger logger.Interface, devices image.VisibleDevice
 
 // newDRMDeviceFilter creates a filter that matches DRM devices nodes for the visible devices.
 func newDRMDeviceFilter(devices image.VisibleDevices, devRoot string) (Filter, error) {
+	selectedBusIds, err := getSelectedBusIDs(devices, devRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := make(selectDeviceByPath)
+	for _, busID := range selectedBusIds {
+		drmDeviceNodes, err := drm.GetDeviceNodesByBusID(busID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to determine DRM devices for %v: %v", busID, err)
+		}
+		for _, drmDeviceNode := range drmDeviceNodes {
+			filter[drmDeviceNode] = true
+		}
+	}
+
+	return filter, nil
+}
+
+// getSelectedBusIDs returns the PCI bus IDs of the NVIDIA GPUs matching the
+// visible devices. Devices can be selected by UUID, by PCI bus ID, or by index.
+// As is the case for NVML, indices are assigned to the GPUs in PCI bus ID
+// order. Since only NVIDIA GPUs are considered, the DRM devices of other GPUs
+// such as an iGPU are never selected.
+func getSelectedBusIDs(devices image.VisibleDevices, devRoot string) ([]string, error) {
+	// The information files are returned in lexical order of their bus IDs.
 	gpuInformationPaths, err := proc.GetInformationFilePaths(devRoot)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read GPU information: %v", err)
 	}
 
 	var selectedBusIds []string
-	for _, f := range gpuInformationPaths {
+	for i, f := range gpuInformationPaths {
 		info, err := proc.ParseGPUInformationFile(f)
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse %v: %v", f, err)
 		}
 		uuid := info[proc.GPUInfoGPUUUID]
 		busID := info[proc.GPUInfoBusLocation]
-		minor := info[proc.GPUInfoDeviceMinor]
 
-		if devices.Has(minor) || devices.Has(uuid) || devices.Has(busID) {
+		if devices.Has(strconv.Itoa(i)) || devices.Has(uuid) || hasPCIBusID(devices, busID) {
 			selectedBusIds = append(selectedBusIds, busID)
 		}
 	}
+	return selectedBusIds, nil
+}
 
-	filter := make(selectDeviceByPath)
-	for _, busID := range selectedBusIds {
-		drmDeviceNodes, err := drm.GetDeviceNodesByBusID(busID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to determine DRM devices for %v: %v", busID, err)
-		}
-		for _, drmDeviceNode := range drmDeviceNodes {
-			filter[drmDeviceNode] = true
+// hasPCIBusID checks whether the visible devices include the specified PCI bus
+// ID in any of its supported forms.
+func hasPCIBusID(devices image.VisibleDevices, busID string) bool {
+	if devices.Has(busID) {
+		return true
+	}
+	for _, d := range devices.List() {
+		if proc.IsPCIBusID(d) && proc.NormalizePCIBusID(d) == busID {
+			return true
 		}
 	}
-
-	return filter, nil
+	return false
 }
 
 // selectDeviceByPath is a filter that allows devices to be selected by the path
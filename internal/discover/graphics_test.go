@@ -17,10 +17,14 @@
 package discover
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	testlog "github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config/image"
 )
 
 func TestGraphicsLibrariesDiscoverer(t *testing.T) {
@@ -163,3 +167,60 @@ func TestGraphicsLibrariesDiscoverer(t *testing.T) {
 		})
 	}
 }
+
+func TestGetSelectedBusIDs(t *testing.T) {
+	devRoot := t.TempDir()
+	// The minor numbers are deliberately not in PCI bus ID order.
+	for busID, information := range map[string]string{
+		"0000:3b:00.0": "GPU UUID:        GPU-1\nBus Location:    0000:3b:00.0\nDevice Minor:    0\n",
+		"0000:1a:00.0": "GPU UUID:        GPU-0\nBus Location:    0000:1a:00.0\nDevice Minor:    1\n",
+	} {
+		dir := filepath.Join(devRoot, "proc/driver/nvidia/gpus", busID)
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "information"), []byte(information), 0644))
+	}
+
+	testCases := []struct {
+		description    string
+		devices        []string
+		expectedBusIDs []string
+	}{
+		{
+			description:    "all selects all GPUs",
+			devices:        []string{"all"},
+			expectedBusIDs: []string{"0000:1a:00.0", "0000:3b:00.0"},
+		},
+		{
+			description:    "index follows PCI bus ID order",
+			devices:        []string{"0"},
+			expectedBusIDs: []string{"0000:1a:00.0"},
+		},
+		{
+			description:    "select by UUID",
+			devices:        []string{"GPU-1"},
+			expectedBusIDs: []string{"0000:3b:00.0"},
+		},
+		{
+			description:    "select by bus ID",
+			devices:        []string{"0000:3b:00.0"},
+			expectedBusIDs: []string{"0000:3b:00.0"},
+		},
+		{
+			description:    "select by NVML-formatted bus ID",
+			devices:        []string{"00000000:3B:00.0"},
+			expectedBusIDs: []string{"0000:3b:00.0"},
+		},
+		{
+			description: "MIG devices do not select DRM devices",
+			devices:     []string{"0:1"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			busIDs, err := getSelectedBusIDs(image.NewVisibleDevices(tc.devices...), devRoot)
+			require.NoError(t, err)
+			require.EqualValues(t, tc.expectedBusIDs, busIDs)
+		})
+	}
+}
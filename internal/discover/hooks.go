@@ -33,6 +33,12 @@ const (
 	// A ChmodHook is used to set the file mode of the specified paths.
 	// Deprecated: The chmod hook is deprecated and will be removed in a future release.
 	ChmodHook = HookName("chmod")
+	// A CopyFilesHook is used to copy files from the host into
+	// the container instead of bind-mounting them.
+	CopyFilesHook = HookName("copy-files")
+	// A CreateNvidiaSMIWrapperHook is used to create a wrapper for nvidia-smi
+	// in the container that only reports on the injected GPUs.
+	CreateNvidiaSMIWrapperHook = HookName("create-nvidia-smi-wrapper")
 	// A CreateSymlinksHook is used to create symlinks in the container.
 	CreateSymlinksHook = HookName("create-symlinks")
 	// DisableDeviceNodeModificationHook refers to the hook used to ensure that
@@ -40,16 +46,45 @@ const (
 	// container.
 	// Added in v1.17.8
 	DisableDeviceNodeModificationHook = HookName("disable-device-node-modification")
+	// A GenerateXorgConfigHook is used to generate an xorg.conf snippet for
+	// the injected NVIDIA Xorg driver modules in the container.
+	GenerateXorgConfigHook = HookName("generate-xorg-config")
 	// An EnableCudaCompatHook is used to enabled CUDA Forward Compatibility.
 	// Added in v1.17.5
 	EnableCudaCompatHook = HookName("enable-cuda-compat")
+	// A ResetGPUsHook is used to reset the GPUs injected into a container
+	// before it is started.
+	ResetGPUsHook = HookName("reset-gpus")
 	// An UpdateLDCacheHook is the hook used to update the ldcache in the
 	// container. This allows injected libraries to be discoverable.
 	UpdateLDCacheHook = HookName("update-ldcache")
 
 	defaultNvidiaCDIHookPath = "/usr/bin/nvidia-cdi-hook"
+	// StableNvidiaCDIHookPath is the well-known path used for the
+	// nvidia-cdi-hook if stable hook paths are requested. This is expected
+	// to be a symlink to the installed nvidia-cdi-hook created at install
+	// time.
+	StableNvidiaCDIHookPath = "/usr/libexec/nvidia-container-toolkit/nvidia-cdi-hook"
 )
 
+// IsValidHookName checks whether the specified name refers to one of the
+// predefined hooks. The special AllHooks name is not considered valid.
+func IsValidHookName[T string | HookName](name T) bool {
+	switch HookName(name) {
+	case ChmodHook,
+		CopyFilesHook,
+		CreateNvidiaSMIWrapperHook,
+		CreateSymlinksHook,
+		DisableDeviceNodeModificationHook,
+		GenerateXorgConfigHook,
+		EnableCudaCompatHook,
+		ResetGPUsHook,
+		UpdateLDCacheHook:
+		return true
+	}
+	return false
+}
+
 var _ Discover = (*Hook)(nil)
 
 // Devices returns an empty list of devices for a Hook discoverer.
@@ -81,10 +116,15 @@ type Option func(*cdiHookCreator)
 
 type cdiHookCreator struct {
 	nvidiaCDIHookPath string
-	disabledHooks     map[HookName]bool
+	// hookPaths stores per-hook overrides of the nvidia-cdi-hook path.
+	hookPaths     map[HookName]string
+	disabledHooks map[HookName]bool
 
-	fixedArgs    []string
 	debugLogging bool
+
+	skipLDCacheCreation bool
+	sandboxHooks        bool
+	stableHookPaths     bool
 }
 
 // An allDisabledHookCreator is a HookCreator that does not create any hooks.
@@ -117,9 +157,52 @@ func WithNVIDIACDIHookPath(nvidiaCDIHookPath string) Option {
 	}
 }
 
+// WithHookPath sets the path to the executable used for the specified hook.
+// This overrides the nvidia-cdi-hook path for this hook and allows
+// distributions to package the hooks as separate executables. If the
+// executable is an nvidia-cdi-hook or nvidia-ctk binary, the hook is invoked
+// as a subcommand. Otherwise the executable is expected to implement only the
+// specified hook and is invoked with the hook arguments directly.
+// This can be specified multiple times.
+func WithHookPath(name HookName, path string) Option {
+	return func(c *cdiHookCreator) {
+		if path == "" {
+			return
+		}
+		c.hookPaths[name] = path
+	}
+}
+
+// WithSkipLDCacheCreation configures the update-ldcache hook to only update
+// the ld.so.conf.d config in a container and refresh an existing ldcache
+// instead of always running ldconfig to create one.
+func WithSkipLDCacheCreation(skipLDCacheCreation bool) Option {
+	return func(c *cdiHookCreator) {
+		c.skipLDCacheCreation = skipLDCacheCreation
+	}
+}
+
+// WithSandboxedHooks configures the created hooks to run with reduced
+// privileges.
+func WithSandboxedHooks(sandboxHooks bool) Option {
+	return func(c *cdiHookCreator) {
+		c.sandboxHooks = sandboxHooks
+	}
+}
+
+// WithStableHookPaths configures the created hooks to reference the
+// nvidia-cdi-hook at a well-known path instead of the configured path. Paths
+// for individual hooks set using WithHookPath are not affected.
+func WithStableHookPaths(stableHookPaths bool) Option {
+	return func(c *cdiHookCreator) {
+		c.stableHookPaths = stableHookPaths
+	}
+}
+
 func NewHookCreator(opts ...Option) HookCreator {
 	cdiHookCreator := &cdiHookCreator{
 		nvidiaCDIHookPath: defaultNvidiaCDIHookPath,
+		hookPaths:         make(map[HookName]string),
 		disabledHooks:     make(map[HookName]bool),
 	}
 	for _, opt := range opts {
@@ -130,8 +213,6 @@ func NewHookCreator(opts ...Option) HookCreator {
 		return &allDisabledHookCreator{}
 	}
 
-	cdiHookCreator.fixedArgs = getFixedArgsForCDIHookCLI(cdiHookCreator.nvidiaCDIHookPath)
-
 	return cdiHookCreator
 }
 
@@ -142,11 +223,17 @@ func (c cdiHookCreator) Create(name HookName, args ...string) *Hook {
 		return nil
 	}
 
+	env := []string{fmt.Sprintf("NVIDIA_CTK_DEBUG=%v", c.debugLogging)}
+	if c.sandboxHooks {
+		env = append(env, "NVIDIA_CTK_SANDBOX_HOOKS=true")
+	}
+
+	path := c.getHookPath(name)
 	return &Hook{
 		Lifecycle: cdi.CreateContainerHook,
-		Path:      c.nvidiaCDIHookPath,
-		Args:      append(c.requiredArgs(name), c.transformArgs(name, args...)...),
-		Env:       []string{fmt.Sprintf("NVIDIA_CTK_DEBUG=%v", c.debugLogging)},
+		Path:      path,
+		Args:      append(c.requiredArgs(path, name), c.transformArgs(name, args...)...),
+		Env:       env,
 	}
 }
 
@@ -169,8 +256,35 @@ func (c cdiHookCreator) isDisabled(name HookName, args ...string) bool {
 	return false
 }
 
-func (c cdiHookCreator) requiredArgs(name HookName) []string {
-	return append(c.fixedArgs, string(name))
+// getHookPath returns the path to the executable for the specified hook.
+func (c cdiHookCreator) getHookPath(name HookName) string {
+	if path, ok := c.hookPaths[name]; ok {
+		return path
+	}
+	if c.stableHookPaths {
+		return StableNvidiaCDIHookPath
+	}
+	return c.nvidiaCDIHookPath
+}
+
+// requiredArgs returns the leading arguments for the specified hook. For an
+// executable that only implements the specified hook, this is only the name of
+// the executable.
+func (c cdiHookCreator) requiredArgs(path string, name HookName) []string {
+	if _, ok := c.hookPaths[name]; ok && !isCDIHookCLI(path) {
+		return []string{filepath.Base(path)}
+	}
+	return append(getFixedArgsForCDIHookCLI(path), string(name))
+}
+
+// isCDIHookCLI checks whether the specified path refers to an executable that
+// implements all hooks as subcommands.
+func isCDIHookCLI(path string) bool {
+	switch filepath.Base(path) {
+	case "nvidia-cdi-hook", "nvidia-ctk":
+		return true
+	}
+	return false
 }
 
 func (c cdiHookCreator) transformArgs(name HookName, args ...string) []string {
@@ -187,6 +301,11 @@ func (c cdiHookCreator) transformArgs(name HookName, args ...string) []string {
 			transformedArgs = append(transformedArgs, "--path", arg)
 		}
 		return transformedArgs
+	case UpdateLDCacheHook:
+		if c.skipLDCacheCreation {
+			return append(args, "--skip-ldcache-creation")
+		}
+		return args
 	default:
 		return args
 	}
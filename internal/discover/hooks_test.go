@@ -0,0 +1,159 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package discover
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHookCreatorWithHookPath(t *testing.T) {
+	testCases := []struct {
+		description  string
+		options      []Option
+		hookName     HookName
+		args         []string
+		expectedHook *Hook
+	}{
+		{
+			description: "default hook path",
+			options: []Option{
+				WithNVIDIACDIHookPath("/usr/bin/nvidia-cdi-hook"),
+			},
+			hookName: UpdateLDCacheHook,
+			expectedHook: &Hook{
+				Lifecycle: "createContainer",
+				Path:      "/usr/bin/nvidia-cdi-hook",
+				Args:      []string{"nvidia-cdi-hook", "update-ldcache"},
+				Env:       []string{"NVIDIA_CTK_DEBUG=false"},
+			},
+		},
+		{
+			description: "hook path overrides nvidia-cdi-hook path",
+			options: []Option{
+				WithNVIDIACDIHookPath("/usr/bin/nvidia-cdi-hook"),
+				WithHookPath(CreateSymlinksHook, "/usr/libexec/nvidia/create-symlinks"),
+			},
+			hookName: CreateSymlinksHook,
+			args:     []string{"target::link"},
+			expectedHook: &Hook{
+				Lifecycle: "createContainer",
+				Path:      "/usr/libexec/nvidia/create-symlinks",
+				Args:      []string{"create-symlinks", "--link", "target::link"},
+				Env:       []string{"NVIDIA_CTK_DEBUG=false"},
+			},
+		},
+		{
+			description: "hook path to nvidia-cdi-hook invokes hook as subcommand",
+			options: []Option{
+				WithNVIDIACDIHookPath("/usr/bin/nvidia-ctk"),
+				WithHookPath(UpdateLDCacheHook, "/opt/nvidia/bin/nvidia-cdi-hook"),
+			},
+			hookName: UpdateLDCacheHook,
+			expectedHook: &Hook{
+				Lifecycle: "createContainer",
+				Path:      "/opt/nvidia/bin/nvidia-cdi-hook",
+				Args:      []string{"nvidia-cdi-hook", "update-ldcache"},
+				Env:       []string{"NVIDIA_CTK_DEBUG=false"},
+			},
+		},
+		{
+			description: "sandboxed hooks set envvar",
+			options: []Option{
+				WithNVIDIACDIHookPath("/usr/bin/nvidia-cdi-hook"),
+				WithSandboxedHooks(true),
+			},
+			hookName: UpdateLDCacheHook,
+			expectedHook: &Hook{
+				Lifecycle: "createContainer",
+				Path:      "/usr/bin/nvidia-cdi-hook",
+				Args:      []string{"nvidia-cdi-hook", "update-ldcache"},
+				Env:       []string{"NVIDIA_CTK_DEBUG=false", "NVIDIA_CTK_SANDBOX_HOOKS=true"},
+			},
+		},
+		{
+			description: "stable hook paths use well-known path",
+			options: []Option{
+				WithNVIDIACDIHookPath("/usr/local/nvidia/toolkit/nvidia-ctk"),
+				WithStableHookPaths(true),
+			},
+			hookName: UpdateLDCacheHook,
+			expectedHook: &Hook{
+				Lifecycle: "createContainer",
+				Path:      "/usr/libexec/nvidia-container-toolkit/nvidia-cdi-hook",
+				Args:      []string{"nvidia-cdi-hook", "update-ldcache"},
+				Env:       []string{"NVIDIA_CTK_DEBUG=false"},
+			},
+		},
+		{
+			description: "hook path for other hook is ignored",
+			options: []Option{
+				WithNVIDIACDIHookPath("/usr/bin/nvidia-ctk"),
+				WithHookPath(CreateSymlinksHook, "/usr/libexec/nvidia/create-symlinks"),
+			},
+			hookName: UpdateLDCacheHook,
+			expectedHook: &Hook{
+				Lifecycle: "createContainer",
+				Path:      "/usr/bin/nvidia-ctk",
+				Args:      []string{"nvidia-ctk", "hook", "update-ldcache"},
+				Env:       []string{"NVIDIA_CTK_DEBUG=false"},
+			},
+		},
+		{
+			description: "empty hook path is ignored",
+			options: []Option{
+				WithNVIDIACDIHookPath("/usr/bin/nvidia-cdi-hook"),
+				WithHookPath(UpdateLDCacheHook, ""),
+			},
+			hookName: UpdateLDCacheHook,
+			expectedHook: &Hook{
+				Lifecycle: "createContainer",
+				Path:      "/usr/bin/nvidia-cdi-hook",
+				Args:      []string{"nvidia-cdi-hook", "update-ldcache"},
+				Env:       []string{"NVIDIA_CTK_DEBUG=false"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			hookCreator := NewHookCreator(tc.options...)
+			hook := hookCreator.Create(tc.hookName, tc.args...)
+			require.EqualValues(t, tc.expectedHook, hook)
+		})
+	}
+}
+
+func TestIsValidHookName(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expected bool
+	}{
+		{name: "update-ldcache", expected: true},
+		{name: "create-symlinks", expected: true},
+		{name: "all"},
+		{name: ""},
+		{name: "update-ld-cache"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, IsValidHookName(tc.name))
+		})
+	}
+}
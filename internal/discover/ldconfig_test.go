@@ -31,15 +31,15 @@ const (
 
 func TestLDCacheUpdateHook(t *testing.T) {
 	logger, _ := testlog.NewNullLogger()
-	hookCreator := NewHookCreator(WithNVIDIACDIHookPath(testNvidiaCDIHookPath))
 
 	testCases := []struct {
-		description   string
-		ldconfigPath  string
-		mounts        []Mount
-		mountError    error
-		expectedError error
-		expectedHooks []Hook
+		description         string
+		ldconfigPath        string
+		skipLDCacheCreation bool
+		mounts              []Mount
+		mountError          error
+		expectedError       error
+		expectedHooks       []Hook
 	}{
 		{
 			description: "empty mounts",
@@ -111,6 +111,23 @@ func TestLDCacheUpdateHook(t *testing.T) {
 				},
 			},
 		},
+		{
+			description:         "skip ldcache creation is passed",
+			skipLDCacheCreation: true,
+			mounts: []Mount{
+				{
+					Path: "/usr/local/lib/libfoo.so",
+				},
+			},
+			expectedHooks: []Hook{
+				{
+					Lifecycle: "createContainer",
+					Path:      testNvidiaCDIHookPath,
+					Args:      []string{"nvidia-cdi-hook", "update-ldcache", "--folder", "/usr/local/lib", "--skip-ldcache-creation"},
+					Env:       []string{"NVIDIA_CTK_DEBUG=false"},
+				},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -120,6 +137,10 @@ func TestLDCacheUpdateHook(t *testing.T) {
 					return tc.mounts, tc.mountError
 				},
 			}
+			hookCreator := NewHookCreator(
+				WithNVIDIACDIHookPath(testNvidiaCDIHookPath),
+				WithSkipLDCacheCreation(tc.skipLDCacheCreation),
+			)
 			d, err := NewLDCacheUpdateHook(logger, mountMock, hookCreator, tc.ldconfigPath)
 			require.NoError(t, err)
 
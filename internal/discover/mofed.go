@@ -18,7 +18,8 @@ package discover
 
 import "github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
 
-// NewMOFEDDiscoverer creates a discoverer for MOFED devices.
+// NewMOFEDDiscoverer creates a discoverer for MOFED devices. This is extended
+// by the DPU discoverer (see NewDPUDiscoverer).
 func NewMOFEDDiscoverer(logger logger.Interface, devRoot string) (Discover, error) {
 	devices := NewCharDeviceDiscoverer(
 		logger,
@@ -46,7 +46,7 @@ func (d *mountsToContainerPath) Mounts() ([]Mount, error) {
 		}
 		candidates, err := d.locator.Locate(target)
 		if err != nil {
-			d.logger.Warningf("Could not locate %v: %v", target, err)
+			logger.Skippedf(d.logger, "Could not locate %v: %v", target, err)
 			continue
 		}
 		if len(candidates) == 0 {
@@ -64,7 +64,7 @@ func (d *mounts) Mounts() ([]Mount, error) {
 		d.logger.Debugf("Locating %v", candidate)
 		located, err := d.lookup.Locate(candidate)
 		if err != nil {
-			d.logger.Warningf("Could not locate %v: %v", candidate, err)
+			logger.Skippedf(d.logger, "Could not locate %v: %v", candidate, err)
 			continue
 		}
 		if len(located) == 0 {
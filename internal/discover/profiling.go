@@ -0,0 +1,71 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package discover
+
+import (
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup"
+)
+
+// cudaProfilingLibrarySearchPaths defines the locations at which the
+// libraries required by profiling tools are installed as part of the CUDA
+// Toolkit.
+var cudaProfilingLibrarySearchPaths = []string{
+	"/usr/local/cuda/extras/CUPTI/lib64",
+	"/usr/local/cuda/lib64",
+	"/usr/local/cuda/targets/x86_64-linux/lib",
+	"/usr/local/cuda/targets/sbsa-linux/lib",
+}
+
+// NewProfilingDiscoverer creates a discoverer for the libraries required by
+// profiling tools such as Nsight Systems and Nsight Compute. These are CUPTI
+// and the NVIDIA Perf SDK (libnvperf) libraries that are installed as part of
+// the CUDA Toolkit and are located relative to the specified host root. A hook
+// to update the ldcache for the injected libraries is also included.
+func NewProfilingDiscoverer(logger logger.Interface, hostRoot string, hookCreator HookCreator, ldconfigPath string) (Discover, error) {
+	libraries := NewMounts(
+		logger,
+		lookup.First(
+			lookup.NewSymlinkLocator(
+				lookup.WithLogger(logger),
+				lookup.WithRoot(hostRoot),
+				lookup.WithSearchPaths(cudaProfilingLibrarySearchPaths...),
+			),
+			lookup.NewLibraryLocator(
+				lookup.WithLogger(logger),
+				lookup.WithRoot(hostRoot),
+			),
+		),
+		hostRoot,
+		[]string{
+			"libcupti.so.*",
+			"libnvperf_host.so",
+			"libnvperf_target.so",
+			"libpcsamplingutil.so",
+		},
+	)
+
+	ldcacheUpdates, err := NewLDCacheUpdateHook(logger, libraries, hookCreator, ldconfigPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return Merge(
+		libraries,
+		ldcacheUpdates,
+	), nil
+}
@@ -0,0 +1,89 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package discover
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config/image"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup/root"
+)
+
+type xorgConfig struct {
+	None
+	logger      logger.Interface
+	hookCreator HookCreator
+	modules     Discover
+	busIDs      func() ([]string, error)
+}
+
+// NewXorgConfigDiscoverer creates a discoverer for a hook that generates an
+// xorg.conf snippet in the container. The snippet adds the paths of the
+// injected NVIDIA Xorg driver modules to the module path of the Xorg server and
+// adds a Device section for each of the specified visible devices.
+func NewXorgConfigDiscoverer(logger logger.Interface, devices image.VisibleDevices, driver *root.Driver, devRoot string, hookCreator HookCreator) (Discover, error) {
+	modules, err := newXorgModulesDiscoverer(logger, driver)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct discoverer for Xorg modules: %w", err)
+	}
+
+	d := &xorgConfig{
+		logger:      logger,
+		hookCreator: hookCreator,
+		modules:     modules,
+		busIDs: func() ([]string, error) {
+			return getSelectedBusIDs(devices, devRoot)
+		},
+	}
+	return d, nil
+}
+
+// Hooks returns a hook to generate the xorg.conf snippet in the container. If
+// no Xorg driver modules are discovered, no hook is returned.
+func (d *xorgConfig) Hooks() ([]Hook, error) {
+	modules, err := d.modules.Mounts()
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover Xorg modules: %w", err)
+	}
+	if len(modules) == 0 {
+		d.logger.Warningf("No NVIDIA Xorg driver modules found; skipping Xorg config generation")
+		return nil, nil
+	}
+
+	var args []string
+	seen := make(map[string]bool)
+	for _, m := range modules {
+		dir := filepath.Dir(m.Path)
+		if seen[dir] {
+			continue
+		}
+		seen[dir] = true
+		args = append(args, "--module-path", dir)
+	}
+
+	busIDs, err := d.busIDs()
+	if err != nil {
+		d.logger.Warningf("Failed to determine PCI bus IDs of requested devices: %v", err)
+	}
+	for _, busID := range busIDs {
+		args = append(args, "--busid", busID)
+	}
+
+	return d.hookCreator.Create(GenerateXorgConfigHook, args...).Hooks()
+}
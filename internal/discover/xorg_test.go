@@ -0,0 +1,99 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package discover
+
+import (
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestXorgConfigDiscoverer(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+	hookCreator := NewHookCreator()
+
+	testCases := []struct {
+		description   string
+		modules       []Mount
+		busIDs        []string
+		expectedHooks []Hook
+	}{
+		{
+			description: "no modules returns no hook",
+			busIDs:      []string{"0000:01:00.0"},
+		},
+		{
+			description: "module paths and bus IDs are added",
+			modules: []Mount{
+				{Path: "/usr/lib/x86_64-linux-gnu/nvidia/xorg/nvidia_drv.so"},
+				{Path: "/usr/lib/x86_64-linux-gnu/nvidia/xorg/libglxserver_nvidia.so.123.45.67"},
+			},
+			busIDs: []string{"0000:01:00.0"},
+			expectedHooks: []Hook{
+				{
+					Lifecycle: "createContainer",
+					Path:      "/usr/bin/nvidia-cdi-hook",
+					Args: []string{"nvidia-cdi-hook", "generate-xorg-config",
+						"--module-path", "/usr/lib/x86_64-linux-gnu/nvidia/xorg",
+						"--busid", "0000:01:00.0",
+					},
+					Env: []string{"NVIDIA_CTK_DEBUG=false"},
+				},
+			},
+		},
+		{
+			description: "modules in different directories",
+			modules: []Mount{
+				{Path: "/usr/lib/xorg/modules/drivers/nvidia_drv.so"},
+				{Path: "/usr/lib/xorg/modules/extensions/libglxserver_nvidia.so.123.45.67"},
+			},
+			expectedHooks: []Hook{
+				{
+					Lifecycle: "createContainer",
+					Path:      "/usr/bin/nvidia-cdi-hook",
+					Args: []string{"nvidia-cdi-hook", "generate-xorg-config",
+						"--module-path", "/usr/lib/xorg/modules/drivers",
+						"--module-path", "/usr/lib/xorg/modules/extensions",
+					},
+					Env: []string{"NVIDIA_CTK_DEBUG=false"},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			d := &xorgConfig{
+				logger:      logger,
+				hookCreator: hookCreator,
+				modules: &DiscoverMock{
+					MountsFunc: func() ([]Mount, error) {
+						return tc.modules, nil
+					},
+				},
+				busIDs: func() ([]string, error) {
+					return tc.busIDs, nil
+				},
+			}
+
+			hooks, err := d.Hooks()
+			require.NoError(t, err)
+			require.EqualValues(t, tc.expectedHooks, hooks)
+		})
+	}
+}
@@ -42,6 +42,7 @@ func (d mount) toSpec() *specs.Mount {
 		HostPath:      d.HostPath,
 		ContainerPath: d.Path,
 		Options:       d.Options,
+		Type:          d.Type,
 	}
 
 	return &s
@@ -0,0 +1,159 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package edits
+
+import (
+	"os"
+	"path/filepath"
+
+	ociSpecs "github.com/opencontainers/runtime-spec/specs-go"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/oci"
+)
+
+// rootlessModifier updates the device nodes in an OCI spec so that they can be
+// used in rootless containers.
+type rootlessModifier struct {
+	logger logger.Interface
+	// devRoot is the root at which the device nodes are available on the
+	// host.
+	devRoot string
+	// existingDevices are the paths of the device nodes that were requested
+	// before the spec was modified by the NVIDIA Container Runtime. These
+	// were added by the container engine or the user and are not modified.
+	existingDevices map[string]bool
+	// stat is used to query the device nodes on the host. This allows it to be
+	// overridden in tests.
+	stat func(string) (os.FileInfo, error)
+}
+
+var _ oci.SpecModifier = (*rootlessModifier)(nil)
+
+// NewRootlessModifier creates a modifier for containers that are created by a
+// rootless container engine such as rootless Podman or Docker. In a user
+// namespace, device nodes cannot be created (mknod) and device cgroup rules
+// cannot be applied. The modifier thus replaces the device nodes in the spec
+// by bind mounts of the corresponding host device nodes and removes the
+// device cgroup rules that allow access to them. Only the device nodes that
+// are injected by the NVIDIA Container Runtime are replaced; the device nodes
+// that are already present in the specified (unmodified) spec are left as is.
+//
+// The permissions of the host device nodes cannot be changed from a user
+// namespace and are not adjusted. World-accessible (0666) device nodes on the
+// host are thus a prerequisite for using them in rootless containers.
+// A nil modifier is returned if the runtime is not rootless.
+func NewRootlessModifier(logger logger.Interface, rootless bool, devRoot string, rawSpec *ociSpecs.Spec) oci.SpecModifier {
+	if !rootless {
+		return nil
+	}
+	existingDevices := make(map[string]bool)
+	if rawSpec != nil && rawSpec.Linux != nil {
+		for _, device := range rawSpec.Linux.Devices {
+			existingDevices[filepath.Clean(device.Path)] = true
+		}
+	}
+	return &rootlessModifier{
+		logger:          logger,
+		devRoot:         devRoot,
+		existingDevices: existingDevices,
+		stat:            os.Stat,
+	}
+}
+
+// Modify replaces the injected device nodes in the spec by bind mounts of the
+// corresponding device nodes below the dev root on the host. Device nodes
+// that do not exist on the host are not modified.
+func (m *rootlessModifier) Modify(spec *ociSpecs.Spec) error {
+	if spec == nil || spec.Linux == nil || len(spec.Linux.Devices) == 0 {
+		return nil
+	}
+
+	mounted := make(map[string]bool)
+	for _, mount := range spec.Mounts {
+		mounted[filepath.Clean(mount.Destination)] = true
+	}
+
+	var devices []ociSpecs.LinuxDevice
+	var converted []ociSpecs.LinuxDevice
+	for _, device := range spec.Linux.Devices {
+		if m.existingDevices[filepath.Clean(device.Path)] {
+			devices = append(devices, device)
+			continue
+		}
+		hostPath := filepath.Join(m.devRoot, device.Path)
+		info, err := m.stat(hostPath)
+		if err != nil || info.Mode()&os.ModeDevice == 0 {
+			m.logger.Warningf("Not replacing device node %v by a bind mount; no device node found on the host", device.Path)
+			devices = append(devices, device)
+			continue
+		}
+		// In a user namespace the host device node is owned by an unmapped
+		// user. Its permissions can thus not be changed and access is only
+		// possible if it is accessible to all users. This is a prerequisite
+		// that is only reported here.
+		if info.Mode().Perm()&0006 != 0006 {
+			m.logger.Warningf("Device node %v has mode %v on the host and may not be accessible in the container", device.Path, info.Mode().Perm())
+		}
+		converted = append(converted, device)
+
+		if mounted[filepath.Clean(device.Path)] {
+			continue
+		}
+		m.logger.Debugf("Replacing device node %v by a bind mount", device.Path)
+		spec.Mounts = append(spec.Mounts, ociSpecs.Mount{
+			Destination: device.Path,
+			Type:        "bind",
+			Source:      hostPath,
+			Options:     []string{"bind", "rw", "nosuid", "noexec"},
+		})
+		mounted[filepath.Clean(device.Path)] = true
+	}
+	spec.Linux.Devices = devices
+
+	if spec.Linux.Resources != nil {
+		spec.Linux.Resources.Devices = removeDeviceCgroupRules(spec.Linux.Resources.Devices, converted)
+	}
+	return nil
+}
+
+// removeDeviceCgroupRules removes the rules that allow access to the specified
+// devices.
+func removeDeviceCgroupRules(rules []ociSpecs.LinuxDeviceCgroup, devices []ociSpecs.LinuxDevice) []ociSpecs.LinuxDeviceCgroup {
+	var filtered []ociSpecs.LinuxDeviceCgroup
+	for _, rule := range rules {
+		if rule.Allow && matchesDevice(rule, devices) {
+			continue
+		}
+		filtered = append(filtered, rule)
+	}
+	return filtered
+}
+
+// matchesDevice checks whether the specified rule applies to one of the
+// specified devices. Rules with wildcards are not matched.
+func matchesDevice(rule ociSpecs.LinuxDeviceCgroup, devices []ociSpecs.LinuxDevice) bool {
+	if rule.Major == nil || rule.Minor == nil {
+		return false
+	}
+	for _, device := range devices {
+		if rule.Type == device.Type && *rule.Major == device.Major && *rule.Minor == device.Minor {
+			return true
+		}
+	}
+	return false
+}
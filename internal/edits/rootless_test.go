@@ -0,0 +1,247 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package edits
+
+import (
+	"io/fs"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	ociSpecs "github.com/opencontainers/runtime-spec/specs-go"
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+// fileInfo is a minimal os.FileInfo implementation for host device nodes.
+type fileInfo struct {
+	mode os.FileMode
+}
+
+func (f fileInfo) Name() string       { return "" }
+func (f fileInfo) Size() int64        { return 0 }
+func (f fileInfo) Mode() os.FileMode  { return f.mode }
+func (f fileInfo) ModTime() time.Time { return time.Time{} }
+func (f fileInfo) IsDir() bool        { return false }
+func (f fileInfo) Sys() any           { return nil }
+
+func TestRootlessModifier(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	hostDevices := map[string]os.FileMode{
+		"/dev/nvidiactl": os.ModeDevice | os.ModeCharDevice | 0666,
+		"/dev/nvidia0":   os.ModeDevice | os.ModeCharDevice | 0666,
+		"/dev/nvidia1":   os.ModeDevice | os.ModeCharDevice | 0600,
+	}
+	stat := func(path string) (os.FileInfo, error) {
+		mode, ok := hostDevices[strings.TrimPrefix(path, "/host")]
+		if !ok {
+			return nil, fs.ErrNotExist
+		}
+		return fileInfo{mode: mode}, nil
+	}
+
+	int64Ptr := func(i int64) *int64 {
+		return &i
+	}
+	bindMount := func(path string) ociSpecs.Mount {
+		return ociSpecs.Mount{
+			Destination: path,
+			Type:        "bind",
+			Source:      path,
+			Options:     []string{"bind", "rw", "nosuid", "noexec"},
+		}
+	}
+
+	testCases := []struct {
+		description     string
+		devRoot         string
+		existingDevices map[string]bool
+		spec            *ociSpecs.Spec
+		expectedSpec    *ociSpecs.Spec
+	}{
+		{
+			description:  "spec without devices is not modified",
+			spec:         &ociSpecs.Spec{Linux: &ociSpecs.Linux{}},
+			expectedSpec: &ociSpecs.Spec{Linux: &ociSpecs.Linux{}},
+		},
+		{
+			description: "devices are replaced by bind mounts",
+			spec: &ociSpecs.Spec{
+				Linux: &ociSpecs.Linux{
+					Devices: []ociSpecs.LinuxDevice{
+						{Path: "/dev/nvidiactl", Type: "c", Major: 195, Minor: 255},
+						{Path: "/dev/nvidia0", Type: "c", Major: 195, Minor: 0},
+						{Path: "/dev/nvidia1", Type: "c", Major: 195, Minor: 1},
+					},
+					Resources: &ociSpecs.LinuxResources{
+						Devices: []ociSpecs.LinuxDeviceCgroup{
+							{Allow: false, Access: "rwm"},
+							{Allow: true, Type: "c", Major: int64Ptr(195), Minor: int64Ptr(255), Access: "rwm"},
+							{Allow: true, Type: "c", Major: int64Ptr(195), Minor: int64Ptr(0), Access: "rwm"},
+							{Allow: true, Type: "c", Major: int64Ptr(195), Minor: int64Ptr(1), Access: "rwm"},
+							{Allow: true, Type: "c", Major: int64Ptr(1), Minor: int64Ptr(3), Access: "rwm"},
+						},
+					},
+				},
+			},
+			expectedSpec: &ociSpecs.Spec{
+				Mounts: []ociSpecs.Mount{
+					bindMount("/dev/nvidiactl"),
+					bindMount("/dev/nvidia0"),
+					bindMount("/dev/nvidia1"),
+				},
+				Linux: &ociSpecs.Linux{
+					Resources: &ociSpecs.LinuxResources{
+						Devices: []ociSpecs.LinuxDeviceCgroup{
+							{Allow: false, Access: "rwm"},
+							{Allow: true, Type: "c", Major: int64Ptr(1), Minor: int64Ptr(3), Access: "rwm"},
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "devices missing on the host are not replaced",
+			spec: &ociSpecs.Spec{
+				Linux: &ociSpecs.Linux{
+					Devices: []ociSpecs.LinuxDevice{
+						{Path: "/dev/nvidia0", Type: "c", Major: 195, Minor: 0},
+						{Path: "/dev/nvidia-caps/nvidia-cap1", Type: "c", Major: 235, Minor: 1},
+					},
+					Resources: &ociSpecs.LinuxResources{
+						Devices: []ociSpecs.LinuxDeviceCgroup{
+							{Allow: true, Type: "c", Major: int64Ptr(195), Minor: int64Ptr(0), Access: "rwm"},
+							{Allow: true, Type: "c", Major: int64Ptr(235), Minor: int64Ptr(1), Access: "rwm"},
+						},
+					},
+				},
+			},
+			expectedSpec: &ociSpecs.Spec{
+				Mounts: []ociSpecs.Mount{
+					bindMount("/dev/nvidia0"),
+				},
+				Linux: &ociSpecs.Linux{
+					Devices: []ociSpecs.LinuxDevice{
+						{Path: "/dev/nvidia-caps/nvidia-cap1", Type: "c", Major: 235, Minor: 1},
+					},
+					Resources: &ociSpecs.LinuxResources{
+						Devices: []ociSpecs.LinuxDeviceCgroup{
+							{Allow: true, Type: "c", Major: int64Ptr(235), Minor: int64Ptr(1), Access: "rwm"},
+						},
+					},
+				},
+			},
+		},
+		{
+			description:     "devices requested by the engine are not replaced",
+			existingDevices: map[string]bool{"/dev/nvidia1": true},
+			spec: &ociSpecs.Spec{
+				Linux: &ociSpecs.Linux{
+					Devices: []ociSpecs.LinuxDevice{
+						{Path: "/dev/nvidia0", Type: "c", Major: 195, Minor: 0},
+						{Path: "/dev/nvidia1", Type: "c", Major: 195, Minor: 1},
+					},
+					Resources: &ociSpecs.LinuxResources{
+						Devices: []ociSpecs.LinuxDeviceCgroup{
+							{Allow: true, Type: "c", Major: int64Ptr(195), Minor: int64Ptr(0), Access: "rwm"},
+							{Allow: true, Type: "c", Major: int64Ptr(195), Minor: int64Ptr(1), Access: "rwm"},
+						},
+					},
+				},
+			},
+			expectedSpec: &ociSpecs.Spec{
+				Mounts: []ociSpecs.Mount{
+					bindMount("/dev/nvidia0"),
+				},
+				Linux: &ociSpecs.Linux{
+					Devices: []ociSpecs.LinuxDevice{
+						{Path: "/dev/nvidia1", Type: "c", Major: 195, Minor: 1},
+					},
+					Resources: &ociSpecs.LinuxResources{
+						Devices: []ociSpecs.LinuxDeviceCgroup{
+							{Allow: true, Type: "c", Major: int64Ptr(195), Minor: int64Ptr(1), Access: "rwm"},
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "device nodes are mounted from the dev root",
+			devRoot:     "/host",
+			spec: &ociSpecs.Spec{
+				Linux: &ociSpecs.Linux{
+					Devices: []ociSpecs.LinuxDevice{
+						{Path: "/dev/nvidia0", Type: "c", Major: 195, Minor: 0},
+					},
+				},
+			},
+			expectedSpec: &ociSpecs.Spec{
+				Mounts: []ociSpecs.Mount{
+					{
+						Destination: "/dev/nvidia0",
+						Type:        "bind",
+						Source:      "/host/dev/nvidia0",
+						Options:     []string{"bind", "rw", "nosuid", "noexec"},
+					},
+				},
+				Linux: &ociSpecs.Linux{},
+			},
+		},
+		{
+			description: "existing mounts are not duplicated",
+			spec: &ociSpecs.Spec{
+				Mounts: []ociSpecs.Mount{
+					{Destination: "/dev/nvidia0", Type: "bind", Source: "/dev/nvidia0"},
+				},
+				Linux: &ociSpecs.Linux{
+					Devices: []ociSpecs.LinuxDevice{
+						{Path: "/dev/nvidia0", Type: "c", Major: 195, Minor: 0},
+					},
+				},
+			},
+			expectedSpec: &ociSpecs.Spec{
+				Mounts: []ociSpecs.Mount{
+					{Destination: "/dev/nvidia0", Type: "bind", Source: "/dev/nvidia0"},
+				},
+				Linux: &ociSpecs.Linux{},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			m := &rootlessModifier{
+				logger:          logger,
+				devRoot:         tc.devRoot,
+				existingDevices: tc.existingDevices,
+				stat:            stat,
+			}
+
+			require.NoError(t, m.Modify(tc.spec))
+			require.EqualValues(t, tc.expectedSpec, tc.spec)
+		})
+	}
+}
+
+func TestNewRootlessModifier(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	require.Nil(t, NewRootlessModifier(logger, false, "/", nil))
+	require.NotNil(t, NewRootlessModifier(logger, true, "/", nil))
+}
@@ -1,3 +1,5 @@
+//go:build !windows
+
 /**
 # Copyright (c) NVIDIA CORPORATION.  All rights reserved.
 #
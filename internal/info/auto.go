@@ -1,3 +1,5 @@
+//go:build !windows
+
 /**
 # Copyright (c) 2022, NVIDIA CORPORATION.  All rights reserved.
 #
@@ -56,6 +58,10 @@ type modeResolver struct {
 	image             *image.CUDA
 	propertyExtractor info.PropertyExtractor
 	defaultMode       RuntimeMode
+	platform          Platform
+	engine            Engine
+
+	dockerConfigFilePath string
 }
 
 type Option func(*modeResolver)
@@ -66,6 +72,23 @@ func WithDefaultMode(defaultMode RuntimeMode) Option {
 	}
 }
 
+// WithPlatform sets the platform used to resolve the runtime mode. If this is
+// not set, or set to "auto", the platform is detected.
+func WithPlatform(platform Platform) Option {
+	return func(mr *modeResolver) {
+		mr.platform = platform
+	}
+}
+
+// WithEngine sets the container engine that invokes the runtime. If the
+// engine is known, the runtime mode on non-Tegra platforms is selected based
+// on whether the engine supports CDI.
+func WithEngine(engine Engine) Option {
+	return func(mr *modeResolver) {
+		mr.engine = engine
+	}
+}
+
 func WithLogger(logger logger.Interface) Option {
 	return func(mr *modeResolver) {
 		mr.logger = logger
@@ -86,7 +109,8 @@ func WithPropertyExtractor(propertyExtractor info.PropertyExtractor) Option {
 
 func NewRuntimeModeResolver(opts ...Option) RuntimeModeResolver {
 	r := &modeResolver{
-		defaultMode: JitCDIRuntimeMode,
+		defaultMode:          JitCDIRuntimeMode,
+		dockerConfigFilePath: getDockerConfigFilePath(),
 	}
 	for _, opt := range opts {
 		opt(r)
@@ -121,16 +145,51 @@ func (m *modeResolver) ResolveRuntimeMode(mode string) (rmode RuntimeMode) {
 		return CDIRuntimeMode
 	}
 
+	platform, err := m.platform.toInfoPlatform()
+	if err != nil {
+		m.logger.Warningf("Ignoring platform override: %v", err)
+		platform = info.PlatformAuto
+	}
+
+	propertyExtractor := m.propertyExtractor
+	if propertyExtractor == nil {
+		propertyExtractor = info.New(info.WithLogger(m.logger))
+	}
+
 	nvinfo := info.New(
 		info.WithLogger(m.logger),
-		info.WithPropertyExtractor(m.propertyExtractor),
+		info.WithPlatform(platform),
+		info.WithPropertyExtractor(
+			newSBSAPropertyExtractor(
+				newTegraPropertyExtractor(propertyExtractor),
+			),
+		),
 	)
 
 	switch nvinfo.ResolvePlatform() {
 	case info.PlatformNVML, info.PlatformWSL:
-		return m.defaultMode
+		return m.resolveModeForEngine()
 	case info.PlatformTegra:
 		return CSVRuntimeMode
 	}
-	return m.defaultMode
+	return m.resolveModeForEngine()
+}
+
+// resolveModeForEngine selects a CDI-based mode if the invoking engine
+// supports CDI. If the engine is not known or does not support CDI, the
+// default mode is used. Since the jit-cdi mode does not require CDI support in
+// the engine, this is also the default for engines without CDI support.
+func (m *modeResolver) resolveModeForEngine() RuntimeMode {
+	if m.engine == EngineUnknown {
+		return m.defaultMode
+	}
+	if !m.engine.supportsCDI(m.dockerConfigFilePath) {
+		m.logger.Debugf("Engine %v does not support CDI; using default mode", m.engine)
+		return m.defaultMode
+	}
+	m.logger.Debugf("Engine %v supports CDI", m.engine)
+	if m.defaultMode == CDIRuntimeMode || m.defaultMode == JitCDIRuntimeMode {
+		return m.defaultMode
+	}
+	return JitCDIRuntimeMode
 }
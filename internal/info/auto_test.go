@@ -1,3 +1,5 @@
+//go:build !windows
+
 /**
 # Copyright (c) 2022, NVIDIA CORPORATION.  All rights reserved.
 #
@@ -33,6 +35,7 @@ func TestResolveAutoMode(t *testing.T) {
 	testCases := []struct {
 		description  string
 		mode         string
+		platform     Platform
 		expectedMode string
 		info         map[string]bool
 		envmap       map[string]string
@@ -204,6 +207,56 @@ func TestResolveAutoMode(t *testing.T) {
 			},
 			expectedMode: "cdi",
 		},
+		{
+			description: "tegra platform override resolves to csv",
+			mode:        "auto",
+			platform:    PlatformTegra,
+			info: map[string]bool{
+				"nvml":  true,
+				"tegra": false,
+				"nvgpu": false,
+			},
+			expectedMode: "csv",
+		},
+		{
+			description: "desktop platform override resolves to jit-cdi",
+			mode:        "auto",
+			platform:    PlatformDesktop,
+			info: map[string]bool{
+				"nvml":  false,
+				"tegra": true,
+				"nvgpu": true,
+			},
+			expectedMode: "jit-cdi",
+		},
+		{
+			description: "auto platform override detects platform",
+			mode:        "auto",
+			platform:    PlatformAuto,
+			info: map[string]bool{
+				"nvml":  false,
+				"tegra": true,
+				"nvgpu": false,
+			},
+			expectedMode: "csv",
+		},
+		{
+			description: "invalid platform override detects platform",
+			mode:        "auto",
+			platform:    "invalid",
+			info: map[string]bool{
+				"nvml":  true,
+				"tegra": false,
+				"nvgpu": false,
+			},
+			expectedMode: "jit-cdi",
+		},
+		{
+			description:  "platform override is ignored for explicit mode",
+			mode:         "legacy",
+			platform:     PlatformTegra,
+			expectedMode: "legacy",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -244,6 +297,7 @@ func TestResolveAutoMode(t *testing.T) {
 				WithLogger(logger),
 				WithImage(&image),
 				WithPropertyExtractor(properties),
+				WithPlatform(tc.platform),
 			)
 			mode := mr.ResolveRuntimeMode(tc.mode)
 			require.EqualValues(t, tc.expectedMode, mode)
@@ -17,22 +17,31 @@
 package drm
 
 import (
-	"fmt"
 	"path/filepath"
+	"strings"
 )
 
-// GetDeviceNodesByBusID returns the DRM devices associated with the specified PCI bus ID
+// GetDeviceNodesByBusID returns the DRM card and render nodes associated with
+// the specified PCI bus ID.
 func GetDeviceNodesByBusID(busID string) ([]string, error) {
-	drmRoot := filepath.Join("/sys/bus/pci/devices", busID, "drm")
-	matches, err := filepath.Glob(fmt.Sprintf("%s/*", drmRoot))
-	if err != nil {
-		return nil, err
-	}
+	return getDeviceNodesByBusID("/sys", busID)
+}
+
+func getDeviceNodesByBusID(sysfsRoot string, busID string) ([]string, error) {
+	drmRoot := filepath.Join(sysfsRoot, "bus/pci/devices", strings.ToLower(busID), "drm")
 
 	var drmDeviceNodes []string
-	for _, m := range matches {
-		drmDeviceNode := filepath.Join("/dev/dri", filepath.Base(m))
-		drmDeviceNodes = append(drmDeviceNodes, drmDeviceNode)
+	// Only the card and render nodes are considered. Other entries such as
+	// legacy control nodes are not injected.
+	for _, pattern := range []string{"card*", "renderD*"} {
+		matches, err := filepath.Glob(filepath.Join(drmRoot, pattern))
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range matches {
+			drmDeviceNode := filepath.Join("/dev/dri", filepath.Base(m))
+			drmDeviceNodes = append(drmDeviceNodes, drmDeviceNode)
+		}
 	}
 
 	return drmDeviceNodes, nil
@@ -0,0 +1,67 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package drm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetDeviceNodesByBusID(t *testing.T) {
+	sysfsRoot := t.TempDir()
+	for _, entry := range []string{
+		"bus/pci/devices/0000:3b:00.0/drm/card1",
+		"bus/pci/devices/0000:3b:00.0/drm/renderD129",
+		"bus/pci/devices/0000:3b:00.0/drm/controlD65",
+		"bus/pci/devices/0000:00:02.0/drm/card0",
+		"bus/pci/devices/0000:00:02.0/drm/renderD128",
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Join(sysfsRoot, entry), 0755))
+	}
+
+	testCases := []struct {
+		description   string
+		busID         string
+		expectedNodes []string
+	}{
+		{
+			description:   "card and render nodes are returned",
+			busID:         "0000:3b:00.0",
+			expectedNodes: []string{"/dev/dri/card1", "/dev/dri/renderD129"},
+		},
+		{
+			description:   "bus ID is case insensitive",
+			busID:         "0000:3B:00.0",
+			expectedNodes: []string{"/dev/dri/card1", "/dev/dri/renderD129"},
+		},
+		{
+			description: "device without DRM nodes",
+			busID:       "0000:5e:00.0",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			nodes, err := getDeviceNodesByBusID(sysfsRoot, tc.busID)
+			require.NoError(t, err)
+			require.EqualValues(t, tc.expectedNodes, nodes)
+		})
+	}
+}
@@ -0,0 +1,126 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package info
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+)
+
+// An Engine is the container engine that invokes the NVIDIA Container
+// Runtime.
+type Engine string
+
+const (
+	// EngineUnknown is used if the container engine could not be detected.
+	EngineUnknown = Engine("")
+	// EngineContainerd refers to containerd, including the CRI plugin.
+	EngineContainerd = Engine("containerd")
+	// EngineCRIO refers to CRI-O.
+	EngineCRIO = Engine("cri-o")
+	// EngineDocker refers to the Docker daemon.
+	EngineDocker = Engine("docker")
+	// EnginePodman refers to Podman.
+	EnginePodman = Engine("podman")
+)
+
+const defaultDockerConfigFilePath = "/etc/docker/daemon.json"
+
+// getDockerConfigFilePath returns the path to the config file of the Docker
+// daemon that invokes the runtime. For rootless Docker, the daemon runs as the
+// current user and reads its config from the XDG config directory.
+func getDockerConfigFilePath() string {
+	if os.Geteuid() == 0 {
+		return defaultDockerConfigFilePath
+	}
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return defaultDockerConfigFilePath
+		}
+		configHome = filepath.Join(home, ".config")
+	}
+	return filepath.Join(configHome, "docker", "daemon.json")
+}
+
+// DetectEngine detects the container engine that created the specified OCI
+// runtime specification from the annotations and hooks that the engines are
+// known to add.
+func DetectEngine(spec *specs.Spec) Engine {
+	if spec == nil {
+		return EngineUnknown
+	}
+
+	if spec.Annotations["io.container.manager"] == "libpod" {
+		return EnginePodman
+	}
+	for key := range spec.Annotations {
+		switch {
+		case strings.HasPrefix(key, "io.kubernetes.cri-o."):
+			return EngineCRIO
+		case strings.HasPrefix(key, "io.kubernetes.cri."),
+			strings.HasPrefix(key, "nerdctl/"):
+			return EngineContainerd
+		}
+	}
+
+	if spec.Hooks != nil {
+		for _, hook := range append(spec.Hooks.Prestart, spec.Hooks.CreateRuntime...) {
+			for _, arg := range hook.Args {
+				if arg == "libnetwork-setkey" {
+					return EngineDocker
+				}
+			}
+		}
+	}
+
+	return EngineUnknown
+}
+
+// supportsCDI checks whether the specified engine supports the injection of
+// CDI devices. Since CDI support is optional for Docker, the features in
+// the Docker daemon config are checked.
+func (e Engine) supportsCDI(dockerConfigFilePath string) bool {
+	switch e {
+	case EngineContainerd, EngineCRIO, EnginePodman:
+		return true
+	case EngineDocker:
+		return dockerCDIFeatureEnabled(dockerConfigFilePath)
+	}
+	return false
+}
+
+// dockerCDIFeatureEnabled checks whether the cdi feature is enabled in the
+// specified Docker daemon config.
+func dockerCDIFeatureEnabled(configFilePath string) bool {
+	contents, err := os.ReadFile(configFilePath)
+	if err != nil {
+		return false
+	}
+	var config struct {
+		Features map[string]bool `json:"features"`
+	}
+	if err := json.Unmarshal(contents, &config); err != nil {
+		return false
+	}
+	return config.Features["cdi"]
+}
@@ -0,0 +1,160 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package info
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectEngine(t *testing.T) {
+	testCases := []struct {
+		description    string
+		spec           *specs.Spec
+		expectedEngine Engine
+	}{
+		{
+			description:    "nil spec is unknown",
+			expectedEngine: EngineUnknown,
+		},
+		{
+			description:    "empty spec is unknown",
+			spec:           &specs.Spec{},
+			expectedEngine: EngineUnknown,
+		},
+		{
+			description: "podman is detected from container manager",
+			spec: &specs.Spec{
+				Annotations: map[string]string{"io.container.manager": "libpod"},
+			},
+			expectedEngine: EnginePodman,
+		},
+		{
+			description: "cri-o is detected from annotations",
+			spec: &specs.Spec{
+				Annotations: map[string]string{"io.kubernetes.cri-o.ContainerType": "container"},
+			},
+			expectedEngine: EngineCRIO,
+		},
+		{
+			description: "containerd is detected from cri annotations",
+			spec: &specs.Spec{
+				Annotations: map[string]string{"io.kubernetes.cri.container-type": "container"},
+			},
+			expectedEngine: EngineContainerd,
+		},
+		{
+			description: "docker is detected from libnetwork hook",
+			spec: &specs.Spec{
+				Hooks: &specs.Hooks{
+					Prestart: []specs.Hook{
+						{Path: "/proc/1234/exe", Args: []string{"libnetwork-setkey", "-exec-root=/var/run/docker", "abc", "def"}},
+					},
+				},
+			},
+			expectedEngine: EngineDocker,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			require.Equal(t, tc.expectedEngine, DetectEngine(tc.spec))
+		})
+	}
+}
+
+func TestResolveModeForEngine(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	dockerConfigDir := t.TempDir()
+	dockerCDIConfig := filepath.Join(dockerConfigDir, "cdi.json")
+	require.NoError(t, os.WriteFile(dockerCDIConfig, []byte(`{"features": {"cdi": true}}`), 0644))
+	dockerNoCDIConfig := filepath.Join(dockerConfigDir, "no-cdi.json")
+	require.NoError(t, os.WriteFile(dockerNoCDIConfig, []byte(`{"runtimes": {}}`), 0644))
+
+	testCases := []struct {
+		description          string
+		engine               Engine
+		defaultMode          RuntimeMode
+		dockerConfigFilePath string
+		expectedMode         RuntimeMode
+	}{
+		{
+			description:  "unknown engine uses default mode",
+			engine:       EngineUnknown,
+			defaultMode:  JitCDIRuntimeMode,
+			expectedMode: JitCDIRuntimeMode,
+		},
+		{
+			description:  "cdi engine uses jit-cdi mode",
+			engine:       EngineContainerd,
+			defaultMode:  LegacyRuntimeMode,
+			expectedMode: JitCDIRuntimeMode,
+		},
+		{
+			description:  "cdi engine uses cdi default mode",
+			engine:       EnginePodman,
+			defaultMode:  CDIRuntimeMode,
+			expectedMode: CDIRuntimeMode,
+		},
+		{
+			description:          "docker without cdi feature uses default mode",
+			engine:               EngineDocker,
+			defaultMode:          JitCDIRuntimeMode,
+			dockerConfigFilePath: dockerNoCDIConfig,
+			expectedMode:         JitCDIRuntimeMode,
+		},
+		{
+			description:          "docker with missing config uses default mode",
+			engine:               EngineDocker,
+			defaultMode:          JitCDIRuntimeMode,
+			dockerConfigFilePath: filepath.Join(dockerConfigDir, "missing.json"),
+			expectedMode:         JitCDIRuntimeMode,
+		},
+		{
+			description:          "docker without cdi feature uses legacy default mode",
+			engine:               EngineDocker,
+			defaultMode:          LegacyRuntimeMode,
+			dockerConfigFilePath: dockerNoCDIConfig,
+			expectedMode:         LegacyRuntimeMode,
+		},
+		{
+			description:          "docker with cdi feature uses jit-cdi mode",
+			engine:               EngineDocker,
+			defaultMode:          JitCDIRuntimeMode,
+			dockerConfigFilePath: dockerCDIConfig,
+			expectedMode:         JitCDIRuntimeMode,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			m := &modeResolver{
+				logger:               logger,
+				engine:               tc.engine,
+				defaultMode:          tc.defaultMode,
+				dockerConfigFilePath: tc.dockerConfigFilePath,
+			}
+			require.Equal(t, tc.expectedMode, m.resolveModeForEngine())
+		})
+	}
+}
@@ -0,0 +1,175 @@
+//go:build !windows
+
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package info
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/NVIDIA/go-nvlib/pkg/nvlib/info"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+)
+
+// A Platform is used to override the platform detection when resolving the
+// runtime mode.
+type Platform string
+
+const (
+	// PlatformAuto detects the platform from the properties of the system.
+	PlatformAuto = Platform("auto")
+	// PlatformTegra forces the platform to be treated as a Tegra-based system.
+	PlatformTegra = Platform("tegra")
+	// PlatformDesktop forces the platform to be treated as an NVML-based
+	// (discrete GPU) system.
+	PlatformDesktop = Platform("desktop")
+)
+
+// toInfoPlatform converts the platform to the corresponding go-nvlib platform.
+func (p Platform) toInfoPlatform() (info.Platform, error) {
+	switch p {
+	case "", PlatformAuto:
+		return info.PlatformAuto, nil
+	case PlatformTegra:
+		return info.PlatformTegra, nil
+	case PlatformDesktop:
+		return info.PlatformNVML, nil
+	}
+	return info.PlatformUnknown, fmt.Errorf("unsupported platform %q", p)
+}
+
+// UsesNVIDIAKernelModules checks whether the GPUs on the system with the
+// specified root are managed by the nvidia and nvidia-uvm kernel modules. This
+// is not the case on WSL, where GPUs are accessed through DXCore, and on
+// Tegra-based systems, where the nvgpu kernel module is used instead.
+func UsesNVIDIAKernelModules(logger logger.Interface, root string) bool {
+	propertyExtractor := newTegraPropertyExtractor(
+		info.New(
+			info.WithLogger(logger),
+			info.WithRoot(root),
+		),
+	)
+	if hasDXCore, reason := propertyExtractor.HasDXCore(); hasDXCore {
+		logger.Debugf("Not using NVIDIA kernel modules on WSL: %v", reason)
+		return false
+	}
+	if isTegra, reason := propertyExtractor.HasTegraFiles(); isTegra {
+		logger.Debugf("Not using NVIDIA kernel modules on Tegra-based system: %v", reason)
+		return false
+	}
+	return true
+}
+
+// deviceTreeCompatiblePaths lists the files that contain the device tree
+// compatible strings for the system.
+var deviceTreeCompatiblePaths = []string{
+	"/proc/device-tree/compatible",
+	"/sys/firmware/devicetree/base/compatible",
+}
+
+const (
+	// nvgpuModulePath exists if the nvgpu kernel module used for the
+	// integrated GPUs of Tegra-based systems is loaded.
+	nvgpuModulePath = "/sys/module/nvgpu"
+	// nvidiaDriverVersionPath exists if the nvidia kernel module used for
+	// discrete GPUs is loaded.
+	nvidiaDriverVersionPath = "/proc/driver/nvidia/version"
+)
+
+// tegraPropertyExtractor extends a property extractor to also check the
+// device tree compatible strings and the loaded kernel driver when detecting
+// Tegra-based systems. This allows Tegra-based systems to be detected in cases
+// where the files checked by the wrapped extractor are not available (e.g.
+// when sysfs is not mounted in a container) and prevents systems with
+// discrete GPUs and no device tree (e.g. SBSA servers) from being detected as
+// Tegra-based systems because of stray tegra-based files.
+type tegraPropertyExtractor struct {
+	info.PropertyExtractor
+	compatiblePaths         []string
+	nvgpuModulePath         string
+	nvidiaDriverVersionPath string
+}
+
+func newTegraPropertyExtractor(propertyExtractor info.PropertyExtractor) *tegraPropertyExtractor {
+	return &tegraPropertyExtractor{
+		PropertyExtractor:       propertyExtractor,
+		compatiblePaths:         deviceTreeCompatiblePaths,
+		nvgpuModulePath:         nvgpuModulePath,
+		nvidiaDriverVersionPath: nvidiaDriverVersionPath,
+	}
+}
+
+// HasTegraFiles returns true if the device tree is compatible with an NVIDIA
+// Tegra SoC. If a device tree is found, this is authoritative and a device
+// tree that is not compatible with an NVIDIA Tegra SoC is reported as a
+// non-Tegra system even if the wrapped extractor detects tegra-based files
+// (e.g. an /etc/nv_tegra_release file included in a container image).
+//
+// If no device tree is found, the wrapped extractor is used. Tegra-based files
+// are ignored if the nvidia kernel module is loaded and the nvgpu kernel
+// module is not, since the GPUs of the system are then discrete GPUs.
+func (e *tegraPropertyExtractor) HasTegraFiles() (bool, string) {
+	for _, path := range e.compatiblePaths {
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		if isTegraCompatible(contents) {
+			return true, fmt.Sprintf("%v is compatible with nvidia,tegra", path)
+		}
+		return false, fmt.Sprintf("%v is not compatible with nvidia,tegra", path)
+	}
+
+	hasTegraFiles, reason := e.PropertyExtractor.HasTegraFiles()
+	if !hasTegraFiles {
+		return false, reason
+	}
+	if pathExists(e.nvgpuModulePath) {
+		return true, fmt.Sprintf("%v; nvgpu kernel module is loaded", reason)
+	}
+	if pathExists(e.nvidiaDriverVersionPath) {
+		return false, fmt.Sprintf("ignoring tegra-based files (%v); nvidia kernel module is loaded without nvgpu kernel module", reason)
+	}
+	return true, reason
+}
+
+func pathExists(path string) bool {
+	if path == "" {
+		return false
+	}
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// IsTegraSystem returns true if the system is detected as a Tegra-based system.
+func (e *tegraPropertyExtractor) IsTegraSystem() (bool, string) {
+	return e.HasTegraFiles()
+}
+
+// isTegraCompatible checks whether the NUL-separated device tree compatible
+// strings contain an NVIDIA Tegra SoC entry.
+func isTegraCompatible(contents []byte) bool {
+	for _, compatible := range bytes.Split(contents, []byte{0}) {
+		if bytes.HasPrefix(bytes.ToLower(compatible), []byte("nvidia,tegra")) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,128 @@
+//go:build !windows
+
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package info
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NVIDIA/go-nvlib/pkg/nvlib/info"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTegraPropertyExtractor(t *testing.T) {
+	testCases := []struct {
+		description   string
+		hasTegraFiles bool
+		compatible    []byte
+		nvgpuModule   bool
+		nvidiaDriver  bool
+		expected      bool
+	}{
+		{
+			description:   "tegra files detected",
+			hasTegraFiles: true,
+			expected:      true,
+		},
+		{
+			description: "no tegra files and no device tree",
+			expected:    false,
+		},
+		{
+			description: "tegra compatible device tree",
+			compatible:  []byte("nvidia,p3737-0000+p3701-0000\x00nvidia,tegra234\x00nvidia,tegra23x\x00"),
+			expected:    true,
+		},
+		{
+			description: "non-tegra compatible device tree",
+			compatible:  []byte("linux,dummy-virt\x00"),
+			expected:    false,
+		},
+		{
+			description:   "non-tegra compatible device tree overrides tegra files",
+			hasTegraFiles: true,
+			compatible:    []byte("linux,dummy-virt\x00"),
+			expected:      false,
+		},
+		{
+			description:   "sbsa server with nvidia driver ignores tegra files",
+			hasTegraFiles: true,
+			nvidiaDriver:  true,
+			expected:      false,
+		},
+		{
+			description:  "sbsa server with nvidia driver and no tegra files",
+			nvidiaDriver: true,
+			expected:     false,
+		},
+		{
+			description:   "tegra files with nvgpu driver",
+			hasTegraFiles: true,
+			nvgpuModule:   true,
+			expected:      true,
+		},
+		{
+			description:   "tegra files with nvgpu and nvidia drivers",
+			hasTegraFiles: true,
+			nvgpuModule:   true,
+			nvidiaDriver:  true,
+			expected:      true,
+		},
+		{
+			description:  "tegra compatible device tree with nvidia driver",
+			compatible:   []byte("nvidia,p3960-0000\x00nvidia,tegra264\x00"),
+			nvidiaDriver: true,
+			expected:     true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			root := t.TempDir()
+			compatiblePath := filepath.Join(root, "compatible")
+			if tc.compatible != nil {
+				require.NoError(t, os.WriteFile(compatiblePath, tc.compatible, 0644))
+			}
+			nvgpuModulePath := filepath.Join(root, "sys/module/nvgpu")
+			if tc.nvgpuModule {
+				require.NoError(t, os.MkdirAll(nvgpuModulePath, 0755))
+			}
+			nvidiaDriverVersionPath := filepath.Join(root, "proc/driver/nvidia/version")
+			if tc.nvidiaDriver {
+				require.NoError(t, os.MkdirAll(filepath.Dir(nvidiaDriverVersionPath), 0755))
+				require.NoError(t, os.WriteFile(nvidiaDriverVersionPath, []byte("NVRM version: NVIDIA UNIX Open Kernel Module for aarch64  570.124.06\n"), 0644))
+			}
+
+			e := &tegraPropertyExtractor{
+				PropertyExtractor: &info.PropertyExtractorMock{
+					HasTegraFilesFunc: func() (bool, string) {
+						return tc.hasTegraFiles, "tegra"
+					},
+				},
+				compatiblePaths:         []string{compatiblePath},
+				nvgpuModulePath:         nvgpuModulePath,
+				nvidiaDriverVersionPath: nvidiaDriverVersionPath,
+			}
+
+			hasTegraFiles, _ := e.HasTegraFiles()
+			require.Equal(t, tc.expected, hasTegraFiles)
+		})
+	}
+}
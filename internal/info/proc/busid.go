@@ -0,0 +1,85 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package proc
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// pciBusIDPattern matches PCI bus IDs of the form DOMAIN:BUS:DEVICE.FUNCTION
+// where the domain is specified as 4 or 8 hexadecimal digits. The latter is
+// the form returned by NVML.
+var pciBusIDPattern = regexp.MustCompile(`^([0-9a-fA-F]{4}|[0-9a-fA-F]{8}):[0-9a-fA-F]{2}:[0-9a-fA-F]{2}\.[0-7]$`)
+
+// IsPCIBusID checks whether the specified device identifier is a PCI bus ID.
+func IsPCIBusID(id string) bool {
+	return pciBusIDPattern.MatchString(id)
+}
+
+// NormalizePCIBusID converts the specified PCI bus ID to the form used for the
+// /proc/driver/nvidia/gpus and /sys/bus/pci/devices entries. That is, the ID is
+// converted to lowercase and a domain of 8 digits is shortened to 4 digits.
+func NormalizePCIBusID(busID string) string {
+	id := strings.ToLower(busID)
+	if domain, rest, ok := strings.Cut(id, ":"); ok && len(domain) == 8 && strings.HasPrefix(domain, "0000") {
+		id = domain[4:] + ":" + rest
+	}
+	return id
+}
+
+// A GPU represents the information about a GPU that is associated with its PCI
+// bus ID.
+type GPU struct {
+	// Index is the index of the GPU when the GPUs are ordered by PCI bus ID.
+	// This matches the default NVML device ordering.
+	Index int
+	// UUID is the UUID of the GPU.
+	UUID string
+	// BusID is the normalized PCI bus ID of the GPU.
+	BusID string
+}
+
+// GetGPUByPCIBusID returns the GPU with the specified PCI bus ID as read from
+// the GPU information files at the specified root.
+func GetGPUByPCIBusID(root string, busID string) (*GPU, error) {
+	normalized := NormalizePCIBusID(busID)
+
+	// The information files are returned in lexical order of their bus IDs.
+	paths, err := GetInformationFilePaths(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read GPU information: %w", err)
+	}
+	for i, path := range paths {
+		if filepath.Base(filepath.Dir(path)) != normalized {
+			continue
+		}
+		info, err := ParseGPUInformationFile(path)
+		if err != nil {
+			return nil, err
+		}
+		gpu := &GPU{
+			Index: i,
+			UUID:  info[GPUInfoGPUUUID],
+			BusID: normalized,
+		}
+		return gpu, nil
+	}
+	return nil, fmt.Errorf("no NVIDIA GPU found with PCI bus ID %v", busID)
+}
@@ -0,0 +1,95 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package proc
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsPCIBusID(t *testing.T) {
+	testCases := []struct {
+		id       string
+		expected bool
+	}{
+		{id: "0000:65:00.0", expected: true},
+		{id: "00000000:65:00.0", expected: true},
+		{id: "0000:3B:00.1", expected: true},
+		{id: "0", expected: false},
+		{id: "0:1", expected: false},
+		{id: "65:00.0", expected: false},
+		{id: "GPU-12345678-1234-1234-1234-123456789abc", expected: false},
+		{id: "0000:65:00.8", expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.id, func(t *testing.T) {
+			require.Equal(t, tc.expected, IsPCIBusID(tc.id))
+		})
+	}
+}
+
+func TestGetGPUByPCIBusID(t *testing.T) {
+	root := t.TempDir()
+	for busID, uuid := range map[string]string{
+		"0000:65:00.0": "GPU-1",
+		"0000:1a:00.0": "GPU-0",
+	} {
+		dir := filepath.Join(root, "proc/driver/nvidia/gpus", busID)
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		contents := "GPU UUID:        " + uuid + "\nBus Location:    " + busID + "\n"
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "information"), []byte(contents), 0644))
+	}
+
+	testCases := []struct {
+		description   string
+		busID         string
+		expectedGPU   *GPU
+		expectedError bool
+	}{
+		{
+			description: "bus ID is found",
+			busID:       "0000:65:00.0",
+			expectedGPU: &GPU{Index: 1, UUID: "GPU-1", BusID: "0000:65:00.0"},
+		},
+		{
+			description: "NVML formatted bus ID is found",
+			busID:       "00000000:1A:00.0",
+			expectedGPU: &GPU{Index: 0, UUID: "GPU-0", BusID: "0000:1a:00.0"},
+		},
+		{
+			description:   "unknown bus ID",
+			busID:         "0000:b3:00.0",
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			gpu, err := GetGPUByPCIBusID(root, tc.busID)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.EqualValues(t, tc.expectedGPU, gpu)
+		})
+	}
+}
@@ -0,0 +1,104 @@
+//go:build !windows
+
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package info
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/NVIDIA/go-nvlib/pkg/nvlib/info"
+)
+
+const (
+	nvidiaPCIVendorID = "0x10de"
+	pciDevicesPath    = "/sys/bus/pci/devices"
+)
+
+// sbsaPropertyExtractor extends a property extractor to detect arm64 SBSA
+// servers (e.g. Grace Hopper systems) with discrete NVIDIA GPUs. On such
+// systems the GPUs are NVML-managed PCI devices, meaning that these should
+// not be treated as Tegra-based systems even if NVML cannot be loaded when
+// the platform is resolved.
+type sbsaPropertyExtractor struct {
+	info.PropertyExtractor
+	arch           string
+	pciDevicesPath string
+}
+
+// HasNvml returns true if the wrapped extractor detects NVML or if the system
+// is an arm64 system with discrete NVIDIA GPUs.
+func (e *sbsaPropertyExtractor) HasNvml() (bool, string) {
+	hasNvml, reason := e.PropertyExtractor.HasNvml()
+	if hasNvml {
+		return true, reason
+	}
+	if isSBSA, sbsaReason := e.isSBSASystem(); isSBSA {
+		return true, sbsaReason
+	}
+	return false, reason
+}
+
+// isSBSASystem checks whether the system is an arm64 system with at least
+// one discrete NVIDIA GPU. The integrated GPUs of Tegra-based systems are not
+// PCI devices and are not considered.
+func (e *sbsaPropertyExtractor) isSBSASystem() (bool, string) {
+	if e.arch != "arm64" {
+		return false, fmt.Sprintf("architecture %v is not arm64", e.arch)
+	}
+
+	devices, err := os.ReadDir(e.pciDevicesPath)
+	if err != nil {
+		return false, fmt.Sprintf("failed to read PCI devices: %v", err)
+	}
+	for _, device := range devices {
+		devicePath := filepath.Join(e.pciDevicesPath, device.Name())
+		if readSysfsValue(filepath.Join(devicePath, "vendor")) != nvidiaPCIVendorID {
+			continue
+		}
+		if isDisplayController(readSysfsValue(filepath.Join(devicePath, "class"))) {
+			return true, fmt.Sprintf("arm64 system with discrete GPU %v", device.Name())
+		}
+	}
+	return false, "no discrete NVIDIA GPUs found on arm64 system"
+}
+
+// isDisplayController checks whether the specified PCI class is a VGA
+// compatible (0x0300) or 3D (0x0302) controller.
+func isDisplayController(class string) bool {
+	return strings.HasPrefix(class, "0x0300") || strings.HasPrefix(class, "0x0302")
+}
+
+func readSysfsValue(path string) string {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(string(contents)))
+}
+
+func newSBSAPropertyExtractor(propertyExtractor info.PropertyExtractor) *sbsaPropertyExtractor {
+	return &sbsaPropertyExtractor{
+		PropertyExtractor: propertyExtractor,
+		arch:              runtime.GOARCH,
+		pciDevicesPath:    pciDevicesPath,
+	}
+}
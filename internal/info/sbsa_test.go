@@ -0,0 +1,100 @@
+//go:build !windows
+
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package info
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NVIDIA/go-nvlib/pkg/nvlib/info"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSBSAPropertyExtractor(t *testing.T) {
+	type pciDevice struct {
+		vendor string
+		class  string
+	}
+	testCases := []struct {
+		description string
+		arch        string
+		hasNvml     bool
+		devices     map[string]pciDevice
+		expected    bool
+	}{
+		{
+			description: "nvml detected",
+			arch:        "arm64",
+			hasNvml:     true,
+			expected:    true,
+		},
+		{
+			description: "arm64 with discrete GPU",
+			arch:        "arm64",
+			devices: map[string]pciDevice{
+				"0000:00:00.0": {vendor: "0x10de", class: "0x060400"},
+				"0009:01:00.0": {vendor: "0x10DE", class: "0x030200"},
+			},
+			expected: true,
+		},
+		{
+			description: "arm64 without NVIDIA display controllers",
+			arch:        "arm64",
+			devices: map[string]pciDevice{
+				"0000:00:00.0": {vendor: "0x10de", class: "0x060400"},
+				"0001:01:00.0": {vendor: "0x15b3", class: "0x020000"},
+			},
+			expected: false,
+		},
+		{
+			description: "amd64 with discrete GPU",
+			arch:        "amd64",
+			devices: map[string]pciDevice{
+				"0000:01:00.0": {vendor: "0x10de", class: "0x030000"},
+			},
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			devicesPath := t.TempDir()
+			for name, device := range tc.devices {
+				devicePath := filepath.Join(devicesPath, name)
+				require.NoError(t, os.MkdirAll(devicePath, 0755))
+				require.NoError(t, os.WriteFile(filepath.Join(devicePath, "vendor"), []byte(device.vendor+"\n"), 0644))
+				require.NoError(t, os.WriteFile(filepath.Join(devicePath, "class"), []byte(device.class+"\n"), 0644))
+			}
+
+			e := &sbsaPropertyExtractor{
+				PropertyExtractor: &info.PropertyExtractorMock{
+					HasNvmlFunc: func() (bool, string) {
+						return tc.hasNvml, "nvml"
+					},
+				},
+				arch:           tc.arch,
+				pciDevicesPath: devicesPath,
+			}
+
+			hasNvml, _ := e.HasNvml()
+			require.Equal(t, tc.expected, hasNvml)
+		})
+	}
+}
@@ -25,6 +25,11 @@ var version = "unknown"
 // and will be populated by the Makefile
 var gitCommit = ""
 
+// GetVersion returns the version of the NVIDIA Container Toolkit.
+func GetVersion() string {
+	return version
+}
+
 // GetVersionParts returns the different version components
 func GetVersionParts() []string {
 	v := []string{version}
@@ -0,0 +1,38 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package injections
+
+import (
+	"fmt"
+	"os"
+)
+
+// Drift returns the reasons why the driver libraries injected into the
+// container no longer match the host driver. The specified driver version is
+// the current version of the host driver and is ignored if empty.
+func (c *Container) Drift(driverVersion string) []string {
+	var reasons []string
+	if c.DriverVersion != "" && driverVersion != "" && c.DriverVersion != driverVersion {
+		reasons = append(reasons, fmt.Sprintf("driver version changed from %v to %v", c.DriverVersion, driverVersion))
+	}
+	for _, library := range c.Libraries {
+		if _, err := os.Stat(library); err != nil {
+			reasons = append(reasons, fmt.Sprintf("library %v no longer exists on the host", library))
+		}
+	}
+	return reasons
+}
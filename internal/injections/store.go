@@ -0,0 +1,116 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package injections
+
+import (
+	"os"
+	"sort"
+	"time"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lockedfile"
+)
+
+// DefaultFilePath is the default path of the file used to store the driver
+// libraries injected into containers by the NVIDIA Container Runtime.
+const DefaultFilePath = "/run/nvidia-container-toolkit/injected-libraries.json"
+
+// State represents the driver libraries injected into containers.
+type State struct {
+	// Containers are the containers that driver libraries were injected
+	// into, keyed by the path of the container bundle.
+	Containers map[string]Container `json:"containers,omitempty"`
+}
+
+// A Container records the driver libraries injected into a container.
+type Container struct {
+	// Bundle is the path of the container bundle.
+	Bundle string `json:"bundle"`
+	// Timestamp is the unix time at which the container was created.
+	Timestamp int64 `json:"timestamp"`
+	// DriverVersion is the version of the host driver at the time the
+	// container was created. This is empty if the version could not be
+	// determined.
+	DriverVersion string `json:"driverVersion,omitempty"`
+	// Libraries are the host paths of the injected driver libraries.
+	Libraries []string `json:"libraries,omitempty"`
+}
+
+// A Store persists the driver libraries injected into containers to a file.
+type Store struct {
+	file *lockedfile.JSONFile[State]
+	now  func() time.Time
+	// isRunning checks whether the container with the specified bundle
+	// still exists.
+	isRunning func(string) bool
+}
+
+// NewStore creates a store for the specified file.
+func NewStore(path string) *Store {
+	return &Store{
+		file:      lockedfile.NewJSONFile[State](path, "injected libraries"),
+		now:       time.Now,
+		isRunning: bundleExists,
+	}
+}
+
+// Load returns the recorded containers that still exist. If the file does
+// not exist, an empty state is returned.
+func (s *Store) Load() (*State, error) {
+	state, err := s.file.Load()
+	if err != nil {
+		return nil, err
+	}
+	s.prune(state)
+	return state, nil
+}
+
+// RecordContainer records the driver libraries injected into the container
+// with the specified bundle. Containers that no longer exist are removed from
+// the stored state.
+func (s *Store) RecordContainer(bundle string, driverVersion string, libraries []string) error {
+	libraries = append([]string{}, libraries...)
+	sort.Strings(libraries)
+	return s.file.Update(func(state *State) error {
+		s.prune(state)
+		if state.Containers == nil {
+			state.Containers = make(map[string]Container)
+		}
+		state.Containers[bundle] = Container{
+			Bundle:        bundle,
+			Timestamp:     s.now().Unix(),
+			DriverVersion: driverVersion,
+			Libraries:     libraries,
+		}
+		return nil
+	})
+}
+
+// prune removes the containers that no longer exist from the state.
+func (s *Store) prune(state *State) {
+	for bundle := range state.Containers {
+		if !s.isRunning(bundle) {
+			delete(state.Containers, bundle)
+		}
+	}
+}
+
+// bundleExists checks whether the specified container bundle exists. Bundles
+// are removed by the container engine when a container is deleted.
+func bundleExists(bundle string) bool {
+	info, err := os.Stat(bundle)
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,113 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package injections
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	bundles := map[string]bool{
+		"/bundle/a": true,
+		"/bundle/b": true,
+	}
+	store := NewStore(filepath.Join(t.TempDir(), "state", "injected-libraries.json"))
+	store.now = func() time.Time {
+		return time.Unix(1000, 0)
+	}
+	store.isRunning = func(bundle string) bool {
+		return bundles[bundle]
+	}
+
+	state, err := store.Load()
+	require.NoError(t, err)
+	require.EqualValues(t, &State{}, state)
+
+	require.NoError(t, store.RecordContainer("/bundle/a", "550.54.15", []string{"/lib/libcuda.so.550.54.15", "/lib/libnvidia-ml.so.550.54.15"}))
+	require.NoError(t, store.RecordContainer("/bundle/b", "550.54.15", []string{"/lib/libnvidia-ml.so.550.54.15"}))
+
+	// Containers that no longer exist are not returned.
+	delete(bundles, "/bundle/b")
+
+	state, err = store.Load()
+	require.NoError(t, err)
+	require.EqualValues(t,
+		&State{
+			Containers: map[string]Container{
+				"/bundle/a": {
+					Bundle:        "/bundle/a",
+					Timestamp:     1000,
+					DriverVersion: "550.54.15",
+					Libraries:     []string{"/lib/libcuda.so.550.54.15", "/lib/libnvidia-ml.so.550.54.15"},
+				},
+			},
+		},
+		state,
+	)
+}
+
+func TestContainerDrift(t *testing.T) {
+	library := filepath.Join(t.TempDir(), "libcuda.so.550.54.15")
+	require.NoError(t, os.WriteFile(library, nil, 0644))
+	missing := filepath.Join(t.TempDir(), "libnvidia-ml.so.550.54.15")
+
+	testCases := []struct {
+		description     string
+		container       Container
+		driverVersion   string
+		expectedReasons []string
+	}{
+		{
+			description: "matching driver has no drift",
+			container: Container{
+				DriverVersion: "550.54.15",
+				Libraries:     []string{library},
+			},
+			driverVersion: "550.54.15",
+		},
+		{
+			description: "unknown driver version is ignored",
+			container: Container{
+				DriverVersion: "550.54.15",
+				Libraries:     []string{library},
+			},
+		},
+		{
+			description: "changed driver version and removed library are reported",
+			container: Container{
+				DriverVersion: "550.54.15",
+				Libraries:     []string{library, missing},
+			},
+			driverVersion: "570.86.10",
+			expectedReasons: []string{
+				"driver version changed from 550.54.15 to 570.86.10",
+				"library " + missing + " no longer exists on the host",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			require.EqualValues(t, tc.expectedReasons, tc.container.Drift(tc.driverVersion))
+		})
+	}
+}
@@ -24,7 +24,6 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
-	"syscall"
 	"unsafe"
 
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
@@ -111,12 +110,7 @@ func New(logger logger.Interface, root string) (LDCache, error) {
 	}
 	defer f.Close()
 
-	fi, err := f.Stat()
-	if err != nil {
-		return nil, err
-	}
-	d, err := syscall.Mmap(int(f.Fd()), 0, int(fi.Size()),
-		syscall.PROT_READ, syscall.MAP_PRIVATE)
+	d, err := mapFile(f)
 	if err != nil {
 		return nil, err
 	}
@@ -131,7 +125,7 @@ func New(logger logger.Interface, root string) (LDCache, error) {
 }
 
 func (c *ldcache) Close() error {
-	return syscall.Munmap(c.data)
+	return unmapFile(c.data)
 }
 
 func (c *ldcache) Magic() string {
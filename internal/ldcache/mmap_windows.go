@@ -0,0 +1,35 @@
+//go:build windows
+
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package ldcache
+
+import (
+	"io"
+	"os"
+)
+
+// mapFile reads the contents of the specified file into memory since mmap is
+// not available on this platform.
+func mapFile(f *os.File) ([]byte, error) {
+	return io.ReadAll(f)
+}
+
+// unmapFile is a no-op since the data is not memory mapped.
+func unmapFile(data []byte) error {
+	return nil
+}
@@ -0,0 +1,79 @@
+/**
+# SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+# SPDX-License-Identifier: Apache-2.0
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package ldconfig
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// UniqueFolders returns the specified folders with duplicates removed.
+// Folders are cleaned before they are compared.
+func UniqueFolders(folders []string) []string {
+	var unique []string
+	seen := make(map[string]bool)
+	for _, folder := range folders {
+		folder = filepath.Clean(folder)
+		if seen[folder] {
+			continue
+		}
+		seen[folder] = true
+		unique = append(unique, folder)
+	}
+	return unique
+}
+
+// ReadFoldersFile reads the folders from the specified file with one folder
+// per line. Empty lines and lines starting with # are ignored.
+func ReadFoldersFile(filename string) ([]string, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open folders file: %w", err)
+	}
+	defer file.Close()
+
+	var folders []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		folders = append(folders, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read folders file: %w", err)
+	}
+	return folders, nil
+}
+
+// WriteFoldersFile writes the specified folders to a file with one folder per
+// line so that these can be read using ReadFoldersFile.
+func WriteFoldersFile(filename string, folders []string) error {
+	var contents strings.Builder
+	for _, folder := range folders {
+		contents.WriteString(folder + "\n")
+	}
+	if err := os.WriteFile(filename, []byte(contents.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write folders file: %w", err)
+	}
+	return nil
+}
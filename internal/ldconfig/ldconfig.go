@@ -69,6 +69,8 @@ func New(ldconfigPath string, inRoot string) (*Ldconfig, error) {
 	return l, nil
 }
 
+// UpdateLDCache updates the ldcache in the root to include the specified
+// directories. If no ldcache exists in the root, a new one is created.
 func (l *Ldconfig) UpdateLDCache(directories ...string) error {
 	ldconfigPath, err := l.prepareRoot()
 	if err != nil {
@@ -103,6 +105,60 @@ func (l *Ldconfig) UpdateLDCache(directories ...string) error {
 	return SafeExec(ldconfigPath, args, nil)
 }
 
+// UpdateLDSoConf creates a config file in /etc/ld.so.conf.d in the root that
+// includes the specified directories. The ldcache is only refreshed if it
+// already exists in the root. This skips the (potentially slow) creation of an
+// ldcache for roots that do not include one.
+// If the /etc/ld.so.conf file in the root does not include the files in
+// /etc/ld.so.conf.d, the directories are also passed to ldconfig directly to
+// ensure that these are added to the ldcache.
+func (l *Ldconfig) UpdateLDSoConf(directories ...string) error {
+	ldconfigPath, err := l.prepareRoot()
+	if err != nil {
+		return err
+	}
+
+	if err := createLdsoconfdFile(ldsoconfdFilenamePattern, directories...); err != nil {
+		return fmt.Errorf("failed to update ld.so.conf.d: %w", err)
+	}
+
+	if !l.ldcacheExists() {
+		return nil
+	}
+
+	args := []string{
+		filepath.Base(ldconfigPath),
+		"-f", "/etc/ld.so.conf",
+		"-C", "/etc/ld.so.cache",
+	}
+	if !ldsoconfIncludesConfd("/etc/ld.so.conf") {
+		args = append(args, directories...)
+	}
+	return SafeExec(ldconfigPath, args, nil)
+}
+
+// ldsoconfIncludesConfd checks whether the specified ld.so.conf file includes
+// the config files in the ld.so.conf.d directory. A missing or unreadable file
+// is treated as not including these.
+func ldsoconfIncludesConfd(ldsoconfPath string) bool {
+	contents, err := os.ReadFile(ldsoconfPath)
+	if err != nil {
+		return false
+	}
+	for _, line := range strings.Split(string(contents), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] != "include" {
+			continue
+		}
+		for _, pattern := range fields[1:] {
+			if strings.Contains(pattern, "ld.so.conf.d/") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (l *Ldconfig) prepareRoot() (string, error) {
 	// To prevent leaking the parent proc filesystem, we create a new proc mount
 	// in the specified root.
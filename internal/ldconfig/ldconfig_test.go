@@ -0,0 +1,70 @@
+/**
+# SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+# SPDX-License-Identifier: Apache-2.0
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package ldconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLdsoconfIncludesConfd(t *testing.T) {
+	testCases := []struct {
+		description string
+		contents    *string
+		expected    bool
+	}{
+		{
+			description: "missing file",
+		},
+		{
+			description: "empty file",
+			contents:    ptr(""),
+		},
+		{
+			description: "relative include",
+			contents:    ptr("include ld.so.conf.d/*.conf\n"),
+			expected:    true,
+		},
+		{
+			description: "absolute include",
+			contents:    ptr("# comment\ninclude /etc/ld.so.conf.d/*.conf\n"),
+			expected:    true,
+		},
+		{
+			description: "commented include",
+			contents:    ptr("# include /etc/ld.so.conf.d/*.conf\n/usr/local/lib\n"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			ldsoconfPath := filepath.Join(t.TempDir(), "ld.so.conf")
+			if tc.contents != nil {
+				require.NoError(t, os.WriteFile(ldsoconfPath, []byte(*tc.contents), 0600))
+			}
+			require.Equal(t, tc.expected, ldsoconfIncludesConfd(ldsoconfPath))
+		})
+	}
+}
+
+func ptr[T any](x T) *T {
+	return &x
+}
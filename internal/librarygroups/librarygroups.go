@@ -0,0 +1,128 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package librarygroups
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/pelletier/go-toml"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config/image"
+)
+
+//go:embed default.toml
+var defaultGroupsData []byte
+
+// Groups maps driver capabilities to the shell patterns of the driver
+// libraries that are required for these capabilities.
+type Groups map[image.DriverCapability][]string
+
+// Default returns the library groups shipped with the NVIDIA Container
+// Toolkit.
+func Default() Groups {
+	groups, err := Parse(defaultGroupsData)
+	if err != nil {
+		panic(fmt.Sprintf("invalid default library groups: %v", err))
+	}
+	return groups
+}
+
+// Load returns the default library groups with the groups defined in the
+// specified file applied. The groups for a capability in the file replace
+// the default groups for that capability. If no file is specified, the
+// default groups are returned.
+func Load(path string) (Groups, error) {
+	groups := Default()
+	if path == "" {
+		return groups, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read library groups: %w", err)
+	}
+	overrides, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %v: %w", path, err)
+	}
+	for capability, patterns := range overrides {
+		groups[capability] = patterns
+	}
+	return groups, nil
+}
+
+// Parse parses the library groups from the specified TOML data.
+func Parse(data []byte) (Groups, error) {
+	var raw map[string][]string
+	if err := toml.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	groups := make(Groups)
+	for c, patterns := range raw {
+		capability := image.DriverCapability(c)
+		if capability == "all" || !image.SupportedDriverCapabilities.Has(capability) {
+			return nil, fmt.Errorf("unsupported driver capability %q", c)
+		}
+		for _, pattern := range patterns {
+			if _, err := filepath.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("invalid pattern %q for driver capability %q: %w", pattern, c, err)
+			}
+		}
+		groups[capability] = patterns
+	}
+	return groups, nil
+}
+
+// Capabilities returns the driver capabilities that the specified library
+// is required for. The returned list is sorted.
+func (g Groups) Capabilities(library string) []image.DriverCapability {
+	name := filepath.Base(library)
+
+	var capabilities []image.DriverCapability
+	for capability, patterns := range g {
+		for _, pattern := range patterns {
+			if match, _ := filepath.Match(pattern, name); match {
+				capabilities = append(capabilities, capability)
+				break
+			}
+		}
+	}
+	slices.Sort(capabilities)
+	return capabilities
+}
+
+// IsRequiredFor checks whether the specified library is required for the
+// specified driver capabilities. Libraries that are not in any of the groups
+// are always required. This ensures that libraries added in new driver
+// versions are injected even if they have not been classified yet.
+func (g Groups) IsRequiredFor(library string, capabilities image.DriverCapabilities) bool {
+	required := g.Capabilities(library)
+	if len(required) == 0 {
+		return true
+	}
+	for _, capability := range required {
+		if capabilities.Has(capability) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package librarygroups
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config/image"
+)
+
+func TestDefault(t *testing.T) {
+	groups := Default()
+	require.Contains(t, groups, image.DriverCapabilityCompute)
+	require.Contains(t, groups, image.DriverCapabilityUtility)
+
+	require.EqualValues(t, []image.DriverCapability{image.DriverCapabilityCompute}, groups.Capabilities("/usr/lib64/libcuda.so.570.86.15"))
+	require.EqualValues(t, []image.DriverCapability{image.DriverCapabilityUtility}, groups.Capabilities("libnvidia-ml.so.570.86.15"))
+	require.Empty(t, groups.Capabilities("libnvidia-new.so.570.86.15"))
+}
+
+func TestIsRequiredFor(t *testing.T) {
+	groups := Groups{
+		image.DriverCapabilityCompute:  {"libcuda.so.*"},
+		image.DriverCapabilityGraphics: {"libnvidia-shared.so.*"},
+		image.DriverCapabilityVideo:    {"libnvidia-shared.so.*", "libnvcuvid.so.*"},
+	}
+
+	testCases := []struct {
+		description  string
+		library      string
+		capabilities string
+		expected     bool
+	}{
+		{
+			description:  "library in requested group",
+			library:      "/usr/lib64/libcuda.so.570.86.15",
+			capabilities: "compute,utility",
+			expected:     true,
+		},
+		{
+			description:  "library not in requested group",
+			library:      "/usr/lib64/libnvcuvid.so.570.86.15",
+			capabilities: "compute,utility",
+			expected:     false,
+		},
+		{
+			description:  "library in one of multiple groups",
+			library:      "/usr/lib64/libnvidia-shared.so.570.86.15",
+			capabilities: "video",
+			expected:     true,
+		},
+		{
+			description:  "unclassified library is always required",
+			library:      "/usr/lib64/libnvidia-new.so.570.86.15",
+			capabilities: "utility",
+			expected:     true,
+		},
+		{
+			description:  "all capabilities",
+			library:      "/usr/lib64/libnvcuvid.so.570.86.15",
+			capabilities: "all",
+			expected:     true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			require.Equal(t, tc.expected, groups.IsRequiredFor(tc.library, image.NewDriverCapabilities(tc.capabilities)))
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	groups, err := Load("")
+	require.NoError(t, err)
+	require.EqualValues(t, Default(), groups)
+
+	path := filepath.Join(t.TempDir(), "library-groups.toml")
+	require.NoError(t, os.WriteFile(path, []byte(`video = ["libnvidia-newcodec.so.*"]`), 0600))
+
+	groups, err = Load(path)
+	require.NoError(t, err)
+	require.EqualValues(t, []string{"libnvidia-newcodec.so.*"}, groups[image.DriverCapabilityVideo])
+	require.EqualValues(t, Default()[image.DriverCapabilityCompute], groups[image.DriverCapabilityCompute])
+
+	_, err = Load(filepath.Join(t.TempDir(), "missing.toml"))
+	require.Error(t, err)
+}
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		description   string
+		data          string
+		expected      Groups
+		expectedError bool
+	}{
+		{
+			description: "valid groups",
+			data:        "compute = [\"libcuda.so.*\"]\nngx = []\n",
+			expected: Groups{
+				image.DriverCapabilityCompute: {"libcuda.so.*"},
+				image.DriverCapabilityNgx:     {},
+			},
+		},
+		{
+			description:   "unsupported capability",
+			data:          `foo = ["libfoo.so.*"]`,
+			expectedError: true,
+		},
+		{
+			description:   "all is not a group",
+			data:          `all = ["libfoo.so.*"]`,
+			expectedError: true,
+		},
+		{
+			description:   "invalid pattern",
+			data:          `compute = ["libcuda.so.["]`,
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			groups, err := Parse([]byte(tc.data))
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.EqualValues(t, tc.expected, groups)
+		})
+	}
+}
@@ -0,0 +1,103 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package listener
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// Listen creates a listener for the specified address. This is either a unix
+// socket (unix:///path/to/socket) or a TCP address (tcp://host:port).
+// For unix sockets, a stale socket is removed before listening and the socket
+// is created with the specified mode. An error is returned if the path exists
+// and is not a socket. Since the umask of the process is
+// updated while the socket is created, Listen should not be called
+// concurrently with other operations that create files.
+func Listen(address string, socketMode os.FileMode) (net.Listener, error) {
+	network, addr, err := ParseAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	if network != "unix" {
+		listener, err := net.Listen(network, addr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to listen on %v: %w", address, err)
+		}
+		return listener, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(addr), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create socket directory: %w", err)
+	}
+	if err := removeStaleSocket(addr); err != nil {
+		return nil, err
+	}
+
+	// The umask is set before the socket is created so that the socket is
+	// never accessible with broader permissions than requested.
+	oldMask := unix.Umask(int(^socketMode.Perm() & 0777))
+	listener, err := net.Listen(network, addr)
+	unix.Umask(oldMask)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %v: %w", address, err)
+	}
+	return listener, nil
+}
+
+// removeStaleSocket removes the socket at the specified path. Since the path
+// may have been specified incorrectly, other files are not removed.
+func removeStaleSocket(path string) error {
+	info, err := os.Lstat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check for stale socket: %w", err)
+	}
+	if info.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("%v exists and is not a socket", path)
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove stale socket: %w", err)
+	}
+	return nil
+}
+
+// ParseAddress splits the specified address into a network and an address.
+// Addresses without a scheme are treated as TCP addresses.
+func ParseAddress(address string) (string, string, error) {
+	scheme, addr, found := strings.Cut(address, "://")
+	if !found {
+		scheme, addr = "tcp", address
+	}
+	switch scheme {
+	case "tcp", "unix":
+	default:
+		return "", "", fmt.Errorf("unsupported address scheme %q", scheme)
+	}
+	if addr == "" {
+		return "", "", fmt.Errorf("invalid address %q", address)
+	}
+	return scheme, addr, nil
+}
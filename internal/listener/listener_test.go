@@ -0,0 +1,115 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package listener
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAddress(t *testing.T) {
+	testCases := []struct {
+		description     string
+		address         string
+		expectedNetwork string
+		expectedAddress string
+		expectedError   bool
+	}{
+		{
+			description:     "tcp address",
+			address:         "tcp://:9401",
+			expectedNetwork: "tcp",
+			expectedAddress: ":9401",
+		},
+		{
+			description:     "address without scheme is tcp",
+			address:         "localhost:9401",
+			expectedNetwork: "tcp",
+			expectedAddress: "localhost:9401",
+		},
+		{
+			description:     "unix socket",
+			address:         "unix:///run/nvidia-container-toolkit/metrics.sock",
+			expectedNetwork: "unix",
+			expectedAddress: "/run/nvidia-container-toolkit/metrics.sock",
+		},
+		{
+			description:   "unsupported scheme",
+			address:       "udp://:9401",
+			expectedError: true,
+		},
+		{
+			description:   "empty address",
+			address:       "unix://",
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			network, address, err := ParseAddress(tc.address)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedNetwork, network)
+			require.Equal(t, tc.expectedAddress, address)
+		})
+	}
+}
+
+func TestListenUnixSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run", "test.sock")
+	// A stale socket is removed.
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	stale, err := net.ListenUnix("unix", &net.UnixAddr{Name: path, Net: "unix"})
+	require.NoError(t, err)
+	stale.SetUnlinkOnClose(false)
+	require.NoError(t, stale.Close())
+
+	listener, err := Listen("unix://"+path, 0600)
+	require.NoError(t, err)
+	defer listener.Close()
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.ModeSocket, info.Mode().Type())
+	require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
+
+func TestListenDoesNotRemoveOtherFiles(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.toml")
+	require.NoError(t, os.WriteFile(file, []byte("contents"), 0644))
+	link := filepath.Join(dir, "link.sock")
+	require.NoError(t, os.Symlink(file, link))
+
+	for _, path := range []string{file, link, dir} {
+		_, err := Listen("unix://"+path, 0600)
+		require.Error(t, err, path)
+	}
+
+	contents, err := os.ReadFile(file)
+	require.NoError(t, err)
+	require.Equal(t, "contents", string(contents))
+	require.FileExists(t, link)
+}
@@ -0,0 +1,130 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package lockedfile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/sys/unix"
+)
+
+// A JSONFile stores a value as JSON in a file. Since each invocation of the
+// NVIDIA Container Runtime is a separate process, access to the file is
+// serialized using an advisory lock on the file.
+type JSONFile[T any] struct {
+	path string
+	// description describes the contents of the file in error messages.
+	description string
+}
+
+// NewJSONFile creates a JSON file at the specified path. The description is
+// used in error messages (e.g. "metrics").
+func NewJSONFile[T any](path string, description string) *JSONFile[T] {
+	return &JSONFile[T]{
+		path:        path,
+		description: description,
+	}
+}
+
+// Load returns the stored value while holding a shared lock on the file. If
+// the file does not exist, the zero value is returned.
+func (f *JSONFile[T]) Load() (*T, error) {
+	file, err := os.Open(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return new(T), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s file: %w", f.description, err)
+	}
+	defer file.Close()
+
+	if err := unix.Flock(int(file.Fd()), unix.LOCK_SH); err != nil {
+		return nil, fmt.Errorf("failed to lock %s file: %w", f.description, err)
+	}
+	defer func() {
+		_ = unix.Flock(int(file.Fd()), unix.LOCK_UN)
+	}()
+
+	contents, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s file: %w", f.description, err)
+	}
+	return f.decode(contents)
+}
+
+// Update applies the specified function to the stored value while holding an
+// exclusive lock on the file. If the function returns an error, the stored
+// value is not updated. A corrupt file is reset instead of failing every
+// update.
+func (f *JSONFile[T]) Update(updateFn func(*T) error) error {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s directory: %w", f.description, err)
+	}
+	file, err := os.OpenFile(f.path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s file: %w", f.description, err)
+	}
+	defer file.Close()
+
+	if err := unix.Flock(int(file.Fd()), unix.LOCK_EX); err != nil {
+		return fmt.Errorf("failed to lock %s file: %w", f.description, err)
+	}
+	defer func() {
+		_ = unix.Flock(int(file.Fd()), unix.LOCK_UN)
+	}()
+
+	contents, err := io.ReadAll(file)
+	if err != nil {
+		return fmt.Errorf("failed to read %s file: %w", f.description, err)
+	}
+	value, err := f.decode(contents)
+	if err != nil {
+		value = new(T)
+	}
+
+	if err := updateFn(value); err != nil {
+		return err
+	}
+
+	updated, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", f.description, err)
+	}
+	if err := file.Truncate(0); err != nil {
+		return fmt.Errorf("failed to truncate %s file: %w", f.description, err)
+	}
+	if _, err := file.WriteAt(updated, 0); err != nil {
+		return fmt.Errorf("failed to write %s file: %w", f.description, err)
+	}
+	return nil
+}
+
+func (f *JSONFile[T]) decode(contents []byte) (*T, error) {
+	value := new(T)
+	if len(contents) == 0 {
+		return value, nil
+	}
+	if err := json.Unmarshal(contents, value); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", f.description, err)
+	}
+	return value, nil
+}
@@ -0,0 +1,75 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package lockedfile
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type counter struct {
+	Count int `json:"count"`
+}
+
+func TestJSONFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "counter.json")
+	f := NewJSONFile[counter](path, "counter")
+
+	value, err := f.Load()
+	require.NoError(t, err)
+	require.EqualValues(t, &counter{}, value)
+
+	increment := func(c *counter) error {
+		c.Count++
+		return nil
+	}
+	require.NoError(t, f.Update(increment))
+	require.NoError(t, f.Update(increment))
+
+	// A failed update does not modify the stored value.
+	err = f.Update(func(c *counter) error {
+		c.Count = 100
+		return errors.New("failed")
+	})
+	require.Error(t, err)
+
+	value, err = f.Load()
+	require.NoError(t, err)
+	require.EqualValues(t, &counter{Count: 2}, value)
+}
+
+func TestJSONFileResetsCorruptContents(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "counter.json")
+	require.NoError(t, os.WriteFile(path, []byte("not-json"), 0644))
+
+	f := NewJSONFile[counter](path, "counter")
+	_, err := f.Load()
+	require.Error(t, err)
+
+	require.NoError(t, f.Update(func(c *counter) error {
+		c.Count++
+		return nil
+	}))
+
+	value, err := f.Load()
+	require.NoError(t, err)
+	require.EqualValues(t, &counter{Count: 1}, value)
+}
@@ -0,0 +1,101 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package logger
+
+import (
+	"fmt"
+	"sync"
+)
+
+// WarningRecorder is a logger that records the warnings that are logged in
+// addition to forwarding all messages to the wrapped logger.
+type WarningRecorder struct {
+	Interface
+
+	sync.Mutex
+	warnings []string
+	skipped  []string
+}
+
+var _ Interface = (*WarningRecorder)(nil)
+
+// NewWarningRecorder creates a logger that records the warnings logged to the
+// specified logger.
+func NewWarningRecorder(logger Interface) *WarningRecorder {
+	return &WarningRecorder{
+		Interface: logger,
+	}
+}
+
+// Warning records the warning and forwards it to the wrapped logger.
+func (l *WarningRecorder) Warning(args ...interface{}) {
+	l.record(fmt.Sprint(args...))
+	l.Interface.Warning(args...)
+}
+
+// Warningf records the warning and forwards it to the wrapped logger.
+func (l *WarningRecorder) Warningf(format string, args ...interface{}) {
+	l.record(fmt.Sprintf(format, args...))
+	l.Interface.Warningf(format, args...)
+}
+
+// Skippedf records an entry that was not injected and forwards the message as
+// a warning to the wrapped logger. Skipped entries are not included in the
+// recorded warnings.
+func (l *WarningRecorder) Skippedf(format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	l.Lock()
+	l.skipped = append(l.skipped, message)
+	l.Unlock()
+	l.Interface.Warningf(format, args...)
+}
+
+// Skipped returns the skipped entries that have been recorded.
+func (l *WarningRecorder) Skipped() []string {
+	l.Lock()
+	defer l.Unlock()
+	return append([]string(nil), l.skipped...)
+}
+
+// Warnings returns the warnings that have been recorded.
+func (l *WarningRecorder) Warnings() []string {
+	l.Lock()
+	defer l.Unlock()
+	return append([]string(nil), l.warnings...)
+}
+
+func (l *WarningRecorder) record(message string) {
+	l.Lock()
+	defer l.Unlock()
+	l.warnings = append(l.warnings, message)
+}
+
+// skippedEntryRecorder is implemented by loggers that record entries that
+// were not injected separately from other warnings.
+type skippedEntryRecorder interface {
+	Skippedf(string, ...interface{})
+}
+
+// Skippedf logs a warning that an entry was not injected. If the specified
+// logger records skipped entries, the entry is recorded as skipped.
+func Skippedf(l Interface, format string, args ...interface{}) {
+	if r, ok := l.(skippedEntryRecorder); ok {
+		r.Skippedf(format, args...)
+		return
+	}
+	l.Warningf(format, args...)
+}
@@ -16,6 +16,8 @@
 
 package lookup
 
+import "runtime"
+
 // NewLibraryLocator creates a library locator using the specified options.
 func NewLibraryLocator(opts ...Option) Locator {
 	b := newBuilder(opts...)
@@ -30,19 +32,7 @@ func NewLibraryLocator(opts ...Option) Locator {
 	}
 
 	opts = append(opts,
-		WithSearchPaths([]string{
-			"/",
-			"/usr/lib64",
-			"/usr/lib/x86_64-linux-gnu",
-			"/usr/lib/aarch64-linux-gnu",
-			"/usr/lib/x86_64-linux-gnu/nvidia/current",
-			"/usr/lib/aarch64-linux-gnu/nvidia/current",
-			"/lib64",
-			"/lib/x86_64-linux-gnu",
-			"/lib/aarch64-linux-gnu",
-			"/lib/x86_64-linux-gnu/nvidia/current",
-			"/lib/aarch64-linux-gnu/nvidia/current",
-		}...),
+		WithSearchPaths(defaultLibrarySearchPaths(runtime.GOARCH)...),
 	)
 	// We construct a symlink locator for expected library locations.
 	symlinkLocator := NewSymlinkLocator(opts...)
@@ -53,3 +43,32 @@ func NewLibraryLocator(opts ...Option) Locator {
 	)
 	return l
 }
+
+// defaultLibrarySearchPaths returns the default library search paths for the
+// specified architecture. The multiarch paths of other architectures are not
+// included for known architectures, which ensures that libraries for a
+// foreign architecture (e.g. x86_64 libraries on an arm64 SBSA server) are
+// not selected.
+func defaultLibrarySearchPaths(arch string) []string {
+	var tuples []string
+	switch arch {
+	case "amd64":
+		tuples = []string{"x86_64-linux-gnu"}
+	case "arm64":
+		tuples = []string{"aarch64-linux-gnu"}
+	default:
+		tuples = []string{"x86_64-linux-gnu", "aarch64-linux-gnu"}
+	}
+
+	searchPaths := []string{"/"}
+	for _, prefix := range []string{"/usr/lib", "/lib"} {
+		searchPaths = append(searchPaths, prefix+"64")
+		for _, tuple := range tuples {
+			searchPaths = append(searchPaths, prefix+"/"+tuple)
+		}
+		for _, tuple := range tuples {
+			searchPaths = append(searchPaths, prefix+"/"+tuple+"/nvidia/current")
+		}
+	}
+	return searchPaths
+}
@@ -136,3 +136,61 @@ func TestLibraryLocator(t *testing.T) {
 		})
 	}
 }
+
+func TestDefaultLibrarySearchPaths(t *testing.T) {
+	testCases := []struct {
+		description string
+		arch        string
+		expected    []string
+	}{
+		{
+			description: "amd64",
+			arch:        "amd64",
+			expected: []string{
+				"/",
+				"/usr/lib64",
+				"/usr/lib/x86_64-linux-gnu",
+				"/usr/lib/x86_64-linux-gnu/nvidia/current",
+				"/lib64",
+				"/lib/x86_64-linux-gnu",
+				"/lib/x86_64-linux-gnu/nvidia/current",
+			},
+		},
+		{
+			description: "arm64",
+			arch:        "arm64",
+			expected: []string{
+				"/",
+				"/usr/lib64",
+				"/usr/lib/aarch64-linux-gnu",
+				"/usr/lib/aarch64-linux-gnu/nvidia/current",
+				"/lib64",
+				"/lib/aarch64-linux-gnu",
+				"/lib/aarch64-linux-gnu/nvidia/current",
+			},
+		},
+		{
+			description: "other architectures include all paths",
+			arch:        "ppc64le",
+			expected: []string{
+				"/",
+				"/usr/lib64",
+				"/usr/lib/x86_64-linux-gnu",
+				"/usr/lib/aarch64-linux-gnu",
+				"/usr/lib/x86_64-linux-gnu/nvidia/current",
+				"/usr/lib/aarch64-linux-gnu/nvidia/current",
+				"/lib64",
+				"/lib/x86_64-linux-gnu",
+				"/lib/aarch64-linux-gnu",
+				"/lib/x86_64-linux-gnu/nvidia/current",
+				"/lib/aarch64-linux-gnu/nvidia/current",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			require.EqualValues(t, tc.expected, defaultLibrarySearchPaths(tc.arch))
+		})
+	}
+}
@@ -27,6 +27,9 @@ type options struct {
 	// configSearchPaths specified explicit search paths for discovering driver config files.
 	configSearchPaths []string
 	versioner         Versioner
+	// kernelModuleVersioner is used to select the driver libraries if
+	// multiple versions are found.
+	kernelModuleVersioner Versioner
 }
 
 type Option func(*options)
@@ -60,3 +63,12 @@ func WithVersioner(versioner Versioner) Option {
 		o.versioner = versioner
 	}
 }
+
+// WithKernelModuleVersioner sets the versioner that is used to determine the
+// version of the loaded kernel module. This is used to select the driver
+// libraries if multiple versions are found.
+func WithKernelModuleVersioner(versioner Versioner) Option {
+	return func(o *options) {
+		o.kernelModuleVersioner = versioner
+	}
+}
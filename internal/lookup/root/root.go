@@ -42,6 +42,9 @@ type Driver struct {
 	version string
 	// libcudasoPath caches the path to libcuda.so.VERSION.
 	libcudasoPath string
+	// kernelModuleVersioner determines the version of the loaded kernel
+	// module.
+	kernelModuleVersioner Versioner
 }
 
 // New creates a new Driver root using the specified options.
@@ -53,6 +56,9 @@ func New(opts ...Option) *Driver {
 	if o.logger == nil {
 		o.logger = logger.New()
 	}
+	if o.kernelModuleVersioner == nil {
+		o.kernelModuleVersioner = KernelModuleVersion("")
+	}
 
 	var driverVersion string
 	if o.versioner != nil {
@@ -70,6 +76,8 @@ func New(opts ...Option) *Driver {
 		configSearchPaths:  o.configSearchPaths,
 		version:            driverVersion,
 		libcudasoPath:      "",
+
+		kernelModuleVersioner: o.kernelModuleVersioner,
 	}
 
 	return d
@@ -149,7 +157,7 @@ func (r *Driver) updateInfo() error {
 	if err != nil {
 		return fmt.Errorf("failed to locate libcuda.so: %w", err)
 	}
-	libcudaPath := libCudaPaths[0]
+	libcudaPath := r.selectLibcuda(libCudaPaths)
 
 	version := strings.TrimPrefix(filepath.Base(libcudaPath), "libcuda.so.")
 	if version == "" {
@@ -157,13 +165,70 @@ func (r *Driver) updateInfo() error {
 	}
 
 	if r.version != "" && r.version != version {
-		return fmt.Errorf("unexpected version detected: %v != %v", r.version, version)
+		// The driver may have been upgraded since the version was cached. We
+		// invalidate the cached values so that these are detected again.
+		cachedVersion := r.version
+		r.invalidate()
+		return fmt.Errorf("unexpected version detected: %v != %v", cachedVersion, version)
 	}
 	r.version = version
 	r.libcudasoPath = r.RelativeToRoot(libcudaPath)
 	return nil
 }
 
+// selectLibcuda selects a libcuda.so path from the specified candidates. If
+// no version is cached and the candidates include several versions (e.g. due
+// to leftovers of previous driver installations in the ldcache), the version
+// that matches the loaded kernel module is selected and the stale versions
+// are reported. Otherwise the first candidate is selected.
+func (r *Driver) selectLibcuda(candidates []string) string {
+	if r.version != "" || len(candidates) == 1 {
+		return candidates[0]
+	}
+
+	var versions []string
+	byVersion := make(map[string]string)
+	for _, candidate := range candidates {
+		version := strings.TrimPrefix(filepath.Base(candidate), "libcuda.so.")
+		if _, ok := byVersion[version]; ok {
+			continue
+		}
+		byVersion[version] = candidate
+		versions = append(versions, version)
+	}
+	if len(versions) == 1 {
+		return candidates[0]
+	}
+
+	kernelModuleVersion, err := r.kernelModuleVersioner.Version()
+	if err != nil {
+		r.logger.Warningf("Found libcuda.so versions %v but could not determine the kernel module version: %v; using %v", versions, err, candidates[0])
+		return candidates[0]
+	}
+	selected, ok := byVersion[kernelModuleVersion]
+	if !ok {
+		r.logger.Warningf("Found libcuda.so versions %v but none matches kernel module version %v; using %v", versions, kernelModuleVersion, candidates[0])
+		return candidates[0]
+	}
+
+	var stale []string
+	for _, version := range versions {
+		if version != kernelModuleVersion {
+			stale = append(stale, byVersion[version])
+		}
+	}
+	r.logger.Warningf("Ignoring stale driver libraries %v; using %v which matches the kernel module version", stale, selected)
+	return selected
+}
+
+// invalidate clears the cached driver version and libcuda.so path. This
+// ensures that these are detected again on the next call that requires them.
+// The caller is expected to hold the lock.
+func (r *Driver) invalidate() {
+	r.version = ""
+	r.libcudasoPath = ""
+}
+
 // RelativeToRoot returns the specified path relative to the driver root.
 func (r *Driver) RelativeToRoot(path string) string {
 	if r.Root == "" || r.Root == "/" {
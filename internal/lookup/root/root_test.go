@@ -17,6 +17,7 @@
 package root
 
 import (
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -79,3 +80,139 @@ func TestDriverLibrariesLocate(t *testing.T) {
 		}
 	}
 }
+
+func TestDriverVersionInvalidate(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	rootfs := t.TempDir()
+	libDir := filepath.Join(rootfs, "/usr/lib64")
+	require.NoError(t, os.MkdirAll(libDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(libDir, "libcuda.so.999.88.78"), nil, 0644))
+	require.NoError(t, os.Symlink("libcuda.so.999.88.78", filepath.Join(libDir, "libcuda.so.999.88.77")))
+
+	// The cached version refers to a library that now resolves to an upgraded
+	// driver.
+	driver := New(
+		WithLogger(logger),
+		WithDriverRoot(rootfs),
+		WithVersioner(staticVersioner{version: "999.88.77"}),
+	)
+
+	_, err := driver.GetLibcudasoPath()
+	require.ErrorContains(t, err, "unexpected version detected")
+
+	version, err := driver.Version()
+	require.NoError(t, err)
+	require.Equal(t, "999.88.78", version)
+}
+
+// staticVersioner returns the specified version or error.
+type staticVersioner struct {
+	version string
+	err     error
+}
+
+func (v staticVersioner) Version() (string, error) {
+	return v.version, v.err
+}
+
+func TestDriverVersionSelectsKernelModuleVersion(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	testCases := []struct {
+		description           string
+		libraries             []string
+		kernelModuleVersioner Versioner
+		expectedVersion       string
+	}{
+		{
+			description:           "single version is used",
+			libraries:             []string{"libcuda.so.570.124.06"},
+			kernelModuleVersioner: staticVersioner{version: "999.88.77"},
+			expectedVersion:       "570.124.06",
+		},
+		{
+			description:           "kernel module version is selected",
+			libraries:             []string{"libcuda.so.550.54.15", "libcuda.so.570.124.06", "libcuda.so.575.51.03"},
+			kernelModuleVersioner: staticVersioner{version: "570.124.06"},
+			expectedVersion:       "570.124.06",
+		},
+		{
+			description:           "unknown kernel module version selects first",
+			libraries:             []string{"libcuda.so.550.54.15", "libcuda.so.570.124.06"},
+			kernelModuleVersioner: staticVersioner{err: os.ErrNotExist},
+			expectedVersion:       "550.54.15",
+		},
+		{
+			description:           "unmatched kernel module version selects first",
+			libraries:             []string{"libcuda.so.550.54.15", "libcuda.so.570.124.06"},
+			kernelModuleVersioner: staticVersioner{version: "999.88.77"},
+			expectedVersion:       "550.54.15",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			rootfs := t.TempDir()
+			libDir := filepath.Join(rootfs, "/usr/lib64")
+			require.NoError(t, os.MkdirAll(libDir, 0755))
+			for _, library := range tc.libraries {
+				require.NoError(t, os.WriteFile(filepath.Join(libDir, library), nil, 0644))
+			}
+
+			driver := New(
+				WithLogger(logger),
+				WithDriverRoot(rootfs),
+				WithKernelModuleVersioner(tc.kernelModuleVersioner),
+			)
+
+			version, err := driver.Version()
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedVersion, version)
+
+			libcudasoPath, err := driver.GetLibcudasoPath()
+			require.NoError(t, err)
+			require.Equal(t, "/usr/lib64/libcuda.so."+tc.expectedVersion, libcudasoPath)
+		})
+	}
+}
+
+func TestKernelModuleVersion(t *testing.T) {
+	testCases := []struct {
+		description     string
+		contents        string
+		expectedVersion string
+		expectedError   bool
+	}{
+		{
+			description:     "proprietary kernel module",
+			contents:        "NVRM version: NVIDIA UNIX x86_64 Kernel Module  570.124.06  Sun Feb 23 02:21:52 UTC 2025\nGCC version:  gcc version 12.2.0 (Debian 12.2.0-14)\n",
+			expectedVersion: "570.124.06",
+		},
+		{
+			description:     "open kernel module",
+			contents:        "NVRM version: NVIDIA UNIX Open Kernel Module for x86_64  575.51.03  Release Build  (dvs-builder@U16-I3-B03-4-3)  Tue Apr 15 12:43:53 UTC 2025\n",
+			expectedVersion: "575.51.03",
+		},
+		{
+			description:   "invalid contents",
+			contents:      "GCC version:  gcc version 12.2.0 (Debian 12.2.0-14)\n",
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "version")
+			require.NoError(t, os.WriteFile(path, []byte(tc.contents), 0644))
+
+			version, err := KernelModuleVersion(path).Version()
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedVersion, version)
+		})
+	}
+}
@@ -17,7 +17,23 @@
 
 package root
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+)
+
+// procDriverVersionPath is the path of the file that reports the version of
+// the loaded NVIDIA kernel module.
+const procDriverVersionPath = "/proc/driver/nvidia/version"
+
+// kernelModuleVersionPattern matches the version in the NVRM line of the
+// kernel module version file. For example:
+//
+//	NVRM version: NVIDIA UNIX x86_64 Kernel Module  570.124.06  Sun Feb 23 ...
+//	NVRM version: NVIDIA UNIX Open Kernel Module for x86_64  570.124.06  Release Build ...
+var kernelModuleVersionPattern = regexp.MustCompile(`(?m)^NVRM version:.*?\s(\d+\.\d+(?:\.\d+)?)\s`)
 
 type Versioner interface {
 	Version() (string, error)
@@ -47,3 +63,29 @@ func (f firstVersion) Version() (string, error) {
 
 	return "", errs
 }
+
+// KernelModuleVersion returns a Versioner that reads the version of the
+// loaded NVIDIA kernel module from the specified file. If no file is
+// specified, /proc/driver/nvidia/version is used.
+func KernelModuleVersion(path string) Versioner {
+	if path == "" {
+		path = procDriverVersionPath
+	}
+	return kernelModuleVersion(path)
+}
+
+type kernelModuleVersion string
+
+var _ Versioner = (kernelModuleVersion)("")
+
+func (p kernelModuleVersion) Version() (string, error) {
+	contents, err := os.ReadFile(string(p))
+	if err != nil {
+		return "", fmt.Errorf("failed to read kernel module version: %w", err)
+	}
+	matches := kernelModuleVersionPattern.FindSubmatch(contents)
+	if matches == nil {
+		return "", fmt.Errorf("failed to parse kernel module version from %v", string(p))
+	}
+	return string(matches[1]), nil
+}
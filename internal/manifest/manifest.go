@@ -0,0 +1,128 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/NVIDIA/go-nvlib/pkg/nvlib/device"
+	"tags.cncf.io/container-device-interface/specs-go"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config/image"
+)
+
+// Version is the version of the discovery manifest format.
+const Version = "v1"
+
+// A Manifest records the result of discovering the driver files and devices
+// on a system. This allows the CDI specifications for the devices requested
+// by a container to be constructed without probing the system.
+type Manifest struct {
+	// Version is the version of the manifest format.
+	Version string `json:"version"`
+	// DriverVersion is the version of the driver at the time the manifest
+	// was generated.
+	DriverVersion string `json:"driverVersion,omitempty"`
+	// Spec is a CDI specification that includes each device under its index
+	// and its UUID.
+	Spec *specs.Spec `json:"spec"`
+}
+
+// Load reads the manifest at the specified path.
+func Load(path string) (*Manifest, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read discovery manifest: %w", err)
+	}
+	m := &Manifest{}
+	if err := json.Unmarshal(contents, m); err != nil {
+		return nil, fmt.Errorf("failed to decode discovery manifest: %w", err)
+	}
+	if m.Version != Version {
+		return nil, fmt.Errorf("unsupported discovery manifest version %q", m.Version)
+	}
+	if m.Spec == nil {
+		return nil, fmt.Errorf("discovery manifest does not contain a CDI specification")
+	}
+	return m, nil
+}
+
+// Write writes the manifest as JSON to the specified writer.
+func (m *Manifest) Write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(m)
+}
+
+// GetSpec returns a CDI specification containing the common edits and the
+// devices with the specified IDs. The special ID "all" selects all devices and
+// the special ID "none" selects no devices.
+func (m *Manifest) GetSpec(ids ...string) (*specs.Spec, error) {
+	devicesByName := make(map[string]specs.Device)
+	for _, d := range m.Spec.Devices {
+		devicesByName[d.Name] = d
+	}
+
+	selected := make(map[string]bool)
+	var devices []specs.Device
+	add := func(d specs.Device) {
+		if selected[d.Name] {
+			return
+		}
+		selected[d.Name] = true
+		devices = append(devices, d)
+	}
+
+	for _, id := range ids {
+		switch id {
+		case image.NoneDeviceName:
+		case "all":
+			for _, d := range m.allDevices() {
+				add(d)
+			}
+		default:
+			d, ok := devicesByName[id]
+			if !ok {
+				return nil, fmt.Errorf("device %q not found in discovery manifest", id)
+			}
+			add(d)
+		}
+	}
+
+	spec := *m.Spec
+	spec.Devices = devices
+	return &spec, nil
+}
+
+// allDevices returns a single entry for each device in the manifest. Since
+// each device is included under its index and its UUID, the devices named by
+// UUID are returned if present.
+func (m *Manifest) allDevices() []specs.Device {
+	var devices []specs.Device
+	for _, d := range m.Spec.Devices {
+		if device.Identifier(d.Name).IsUUID() {
+			devices = append(devices, d)
+		}
+	}
+	if len(devices) == 0 {
+		return m.Spec.Devices
+	}
+	return devices
+}
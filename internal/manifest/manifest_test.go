@@ -0,0 +1,127 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package manifest
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"tags.cncf.io/container-device-interface/specs-go"
+)
+
+const (
+	testGPUUUID = "GPU-b1028956-cfa2-0990-bf4a-5da9abb51763"
+	testMIGUUID = "MIG-b1028956-cfa2-0990-bf4a-5da9abb51764"
+)
+
+func TestManifestGetSpec(t *testing.T) {
+	gpu := specs.ContainerEdits{DeviceNodes: []*specs.DeviceNode{{Path: "/dev/nvidia0"}}}
+	mig := specs.ContainerEdits{DeviceNodes: []*specs.DeviceNode{{Path: "/dev/nvidia-caps/nvidia-cap21"}}}
+	common := specs.ContainerEdits{Env: []string{"NVIDIA_VISIBLE_DEVICES=void"}}
+
+	m := &Manifest{
+		Version: Version,
+		Spec: &specs.Spec{
+			Version: "0.5.0",
+			Kind:    "runtime.nvidia.com/gpu",
+			Devices: []specs.Device{
+				{Name: "0", ContainerEdits: gpu},
+				{Name: testGPUUUID, ContainerEdits: gpu},
+				{Name: "1:0", ContainerEdits: mig},
+				{Name: testMIGUUID, ContainerEdits: mig},
+			},
+			ContainerEdits: common,
+		},
+	}
+
+	testCases := []struct {
+		description     string
+		ids             []string
+		expectedError   bool
+		expectedDevices []specs.Device
+	}{
+		{
+			description: "none selects no devices",
+			ids:         []string{"none"},
+		},
+		{
+			description: "index and uuid select devices once",
+			ids:         []string{"0", testGPUUUID, "1:0", "0"},
+			expectedDevices: []specs.Device{
+				{Name: "0", ContainerEdits: gpu},
+				{Name: testGPUUUID, ContainerEdits: gpu},
+				{Name: "1:0", ContainerEdits: mig},
+			},
+		},
+		{
+			description: "all selects devices by uuid",
+			ids:         []string{"all"},
+			expectedDevices: []specs.Device{
+				{Name: testGPUUUID, ContainerEdits: gpu},
+				{Name: testMIGUUID, ContainerEdits: mig},
+			},
+		},
+		{
+			description:   "unknown device returns error",
+			ids:           []string{"2"},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			spec, err := m.GetSpec(tc.ids...)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.EqualValues(t, tc.expectedDevices, spec.Devices)
+			require.EqualValues(t, common, spec.ContainerEdits)
+			require.Equal(t, "runtime.nvidia.com/gpu", spec.Kind)
+		})
+	}
+}
+
+func TestManifestRoundTrip(t *testing.T) {
+	m := &Manifest{
+		Version:       Version,
+		DriverVersion: "550.54.15",
+		Spec: &specs.Spec{
+			Version: "0.5.0",
+			Kind:    "runtime.nvidia.com/gpu",
+			Devices: []specs.Device{{Name: "0"}},
+		},
+	}
+
+	buffer := &bytes.Buffer{}
+	require.NoError(t, m.Write(buffer))
+
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	require.NoError(t, os.WriteFile(path, buffer.Bytes(), 0644))
+
+	loaded, err := Load(path)
+	require.NoError(t, err)
+	require.EqualValues(t, m, loaded)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"version": "v0", "spec": {}}`), 0644))
+	_, err = Load(path)
+	require.Error(t, err)
+}
@@ -0,0 +1,267 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package metrics
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup/root"
+)
+
+const metricPrefix = "nvidia_container_toolkit_"
+
+// A Collector gathers the metrics for the NVIDIA Container Toolkit and
+// exposes them in the Prometheus text format.
+type Collector struct {
+	logger         logger.Interface
+	store          *Store
+	driver         *root.Driver
+	cdiSpecDirs    []string
+	configFilePath string
+	// configHash is the hash of the config file when the collector was
+	// created. This is used to detect config drift.
+	configHash string
+	now        func() time.Time
+}
+
+// Option is a function that configures a Collector.
+type Option func(*Collector)
+
+// WithLogger sets the logger for the collector.
+func WithLogger(logger logger.Interface) Option {
+	return func(c *Collector) {
+		c.logger = logger
+	}
+}
+
+// WithStore sets the store from which the runtime metrics are read.
+func WithStore(store *Store) Option {
+	return func(c *Collector) {
+		c.store = store
+	}
+}
+
+// WithDriver sets the driver used to determine whether CDI specs are stale.
+func WithDriver(driver *root.Driver) Option {
+	return func(c *Collector) {
+		c.driver = driver
+	}
+}
+
+// WithCDISpecDirs sets the directories containing the CDI specs to report on.
+func WithCDISpecDirs(dirs ...string) Option {
+	return func(c *Collector) {
+		c.cdiSpecDirs = dirs
+	}
+}
+
+// WithConfigFile sets the config file that is checked for drift.
+func WithConfigFile(path string) Option {
+	return func(c *Collector) {
+		c.configFilePath = path
+	}
+}
+
+// NewCollector creates a collector with the specified options.
+func NewCollector(opts ...Option) *Collector {
+	c := &Collector{
+		now: time.Now,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.logger == nil {
+		c.logger = &logger.NullLogger{}
+	}
+	if c.store == nil {
+		c.store = NewStore(DefaultFilePath)
+	}
+	if c.configFilePath != "" {
+		c.configHash = hashFile(c.configFilePath)
+	}
+	return c
+}
+
+// ServeHTTP writes the collected metrics as the HTTP response.
+func (c *Collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var buf bytes.Buffer
+	if err := c.Write(&buf); err != nil {
+		c.logger.Warningf("Failed to collect metrics: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = w.Write(buf.Bytes())
+}
+
+// Write writes the collected metrics to the specified writer in the
+// Prometheus text format.
+func (c *Collector) Write(w io.Writer) error {
+	state, err := c.store.Load()
+	if err != nil {
+		return err
+	}
+
+	m := &metricWriter{w: w}
+	m.family("injections_total", "counter", "The number of containers modified by the NVIDIA Container Runtime.")
+	m.sample("injections_total", nil, float64(state.Injections))
+
+	m.family("errors_total", "counter", "The number of failed invocations of the NVIDIA Container Runtime.")
+	m.sample("errors_total", nil, float64(state.Errors))
+
+	if state.LastErrorClass != "" {
+		m.family("last_error_timestamp_seconds", "gauge", "The unix time of the most recent NVIDIA Container Runtime error.")
+		m.sample("last_error_timestamp_seconds", nil, float64(state.LastErrorTimestamp))
+
+		m.family("last_error_info", "gauge", "The failure class of the most recent NVIDIA Container Runtime error.")
+		m.sample("last_error_info", map[string]string{"class": state.LastErrorClass}, 1)
+	}
+
+	specs := c.getCDISpecs()
+	if len(specs) > 0 {
+		driverModTime := c.getDriverModTime()
+		m.family("cdi_spec_age_seconds", "gauge", "The time since the CDI spec was last modified.")
+		for _, spec := range specs {
+			m.sample("cdi_spec_age_seconds", map[string]string{"path": spec.path}, c.now().Sub(spec.modTime).Seconds())
+		}
+		m.family("cdi_spec_stale", "gauge", "Whether the CDI spec is older than the installed NVIDIA driver.")
+		for _, spec := range specs {
+			m.sample("cdi_spec_stale", map[string]string{"path": spec.path}, boolToFloat(spec.modTime.Before(driverModTime)))
+		}
+	}
+
+	if c.configFilePath != "" {
+		m.family("config_drift", "gauge", "Whether the config file has changed since the exporter was started.")
+		m.sample("config_drift", map[string]string{"path": c.configFilePath}, boolToFloat(hashFile(c.configFilePath) != c.configHash))
+	}
+
+	return m.err
+}
+
+type cdiSpecFile struct {
+	path    string
+	modTime time.Time
+}
+
+// getCDISpecs returns the CDI spec files in the configured spec directories.
+func (c *Collector) getCDISpecs() []cdiSpecFile {
+	var specs []cdiSpecFile
+	for _, dir := range c.cdiSpecDirs {
+		for _, pattern := range []string{"*.yaml", "*.json"} {
+			paths, _ := filepath.Glob(filepath.Join(dir, pattern))
+			for _, path := range paths {
+				info, err := os.Stat(path)
+				if err != nil {
+					c.logger.Debugf("Failed to stat %v: %v", path, err)
+					continue
+				}
+				specs = append(specs, cdiSpecFile{path: path, modTime: info.ModTime()})
+			}
+		}
+	}
+	sort.Slice(specs, func(i, j int) bool {
+		return specs[i].path < specs[j].path
+	})
+	return specs
+}
+
+// getDriverModTime returns the modification time of the NVML library of the
+// installed driver. A zero time is returned if this cannot be determined.
+func (c *Collector) getDriverModTime() time.Time {
+	if c.driver == nil {
+		return time.Time{}
+	}
+	libs, err := c.driver.Libraries().Locate("libnvidia-ml.so.1")
+	if err != nil || len(libs) == 0 {
+		c.logger.Debugf("Failed to locate NVML library: %v", err)
+		return time.Time{}
+	}
+	info, err := os.Stat(libs[0])
+	if err != nil {
+		c.logger.Debugf("Failed to stat %v: %v", libs[0], err)
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+// hashFile returns the hex-encoded SHA256 hash of the specified file or an
+// empty string if the file cannot be read.
+func hashFile(path string) string {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(contents))
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// metricWriter writes metrics in the Prometheus text format.
+// The first write error is recorded and subsequent writes are skipped.
+type metricWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (m *metricWriter) family(name string, metricType string, help string) {
+	m.printf("# HELP %s%s %s\n", metricPrefix, name, help)
+	m.printf("# TYPE %s%s %s\n", metricPrefix, name, metricType)
+}
+
+func (m *metricWriter) sample(name string, labels map[string]string, value float64) {
+	var formattedLabels []string
+	for k, v := range labels {
+		formattedLabels = append(formattedLabels, fmt.Sprintf("%s=\"%s\"", k, escapeLabelValue(v)))
+	}
+	sort.Strings(formattedLabels)
+
+	var labelString string
+	if len(formattedLabels) > 0 {
+		labelString = "{" + strings.Join(formattedLabels, ",") + "}"
+	}
+	m.printf("%s%s%s %v\n", metricPrefix, name, labelString, value)
+}
+
+func (m *metricWriter) printf(format string, args ...interface{}) {
+	if m.err != nil {
+		return
+	}
+	_, m.err = fmt.Fprintf(m.w, format, args...)
+}
+
+// escapeLabelValue escapes a label value as required by the Prometheus text
+// format.
+func escapeLabelValue(v string) string {
+	return labelValueReplacer.Replace(v)
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
@@ -0,0 +1,73 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectorWrite(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+	dir := t.TempDir()
+
+	now := time.Unix(10000, 0)
+
+	store := NewStore(filepath.Join(dir, "runtime-metrics.json"))
+	store.now = func() time.Time { return now }
+	require.NoError(t, store.RecordInjection())
+	require.NoError(t, store.RecordError("modifier"))
+
+	specDir := filepath.Join(dir, "cdi")
+	require.NoError(t, os.MkdirAll(specDir, 0755))
+	specPath := filepath.Join(specDir, "nvidia.yaml")
+	require.NoError(t, os.WriteFile(specPath, nil, 0644))
+	require.NoError(t, os.Chtimes(specPath, now.Add(-time.Minute), now.Add(-time.Minute)))
+
+	configPath := filepath.Join(dir, "config.toml")
+	require.NoError(t, os.WriteFile(configPath, []byte("[nvidia-container-runtime]\n"), 0644))
+
+	c := NewCollector(
+		WithLogger(logger),
+		WithStore(store),
+		WithCDISpecDirs(specDir),
+		WithConfigFile(configPath),
+	)
+	c.now = func() time.Time { return now }
+
+	var output strings.Builder
+	require.NoError(t, c.Write(&output))
+	require.Contains(t, output.String(), "nvidia_container_toolkit_injections_total 1\n")
+	require.Contains(t, output.String(), "nvidia_container_toolkit_errors_total 1\n")
+	require.Contains(t, output.String(), "nvidia_container_toolkit_last_error_timestamp_seconds 10000\n")
+	require.Contains(t, output.String(), `nvidia_container_toolkit_last_error_info{class="modifier"} 1`+"\n")
+	require.Contains(t, output.String(), `nvidia_container_toolkit_cdi_spec_age_seconds{path="`+specPath+`"} 60`+"\n")
+	require.Contains(t, output.String(), `nvidia_container_toolkit_cdi_spec_stale{path="`+specPath+`"} 0`+"\n")
+	require.Contains(t, output.String(), `nvidia_container_toolkit_config_drift{path="`+configPath+`"} 0`+"\n")
+
+	require.NoError(t, os.WriteFile(configPath, []byte("[nvidia-container-runtime]\nmode = \"cdi\"\n"), 0644))
+
+	output.Reset()
+	require.NoError(t, c.Write(&output))
+	require.Contains(t, output.String(), `nvidia_container_toolkit_config_drift{path="`+configPath+`"} 1`+"\n")
+}
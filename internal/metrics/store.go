@@ -0,0 +1,81 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package metrics
+
+import (
+	"time"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lockedfile"
+)
+
+// DefaultFilePath is the default path of the file used to store the metrics
+// recorded by the NVIDIA Container Runtime.
+const DefaultFilePath = "/run/nvidia-container-toolkit/runtime-metrics.json"
+
+// State represents the metrics recorded by the NVIDIA Container Runtime.
+type State struct {
+	// Injections is the number of containers that were modified.
+	Injections uint64 `json:"injections"`
+	// Errors is the number of invocations that failed.
+	Errors uint64 `json:"errors"`
+	// LastErrorClass is the failure class (e.g. modifier) of the most recent
+	// error. Error messages are not recorded since these are unbounded and
+	// may contain identifying information such as paths or container IDs.
+	LastErrorClass string `json:"lastErrorClass,omitempty"`
+	// LastErrorTimestamp is the unix time of the most recent error.
+	LastErrorTimestamp int64 `json:"lastErrorTimestamp,omitempty"`
+}
+
+// A Store persists the metrics recorded by the NVIDIA Container Runtime to a
+// file.
+type Store struct {
+	file *lockedfile.JSONFile[State]
+	now  func() time.Time
+}
+
+// NewStore creates a store for the specified file.
+func NewStore(path string) *Store {
+	return &Store{
+		file: lockedfile.NewJSONFile[State](path, "metrics"),
+		now:  time.Now,
+	}
+}
+
+// Load returns the recorded metrics. If the file does not exist, an empty
+// state is returned.
+func (s *Store) Load() (*State, error) {
+	return s.file.Load()
+}
+
+// RecordInjection increments the number of modified containers.
+func (s *Store) RecordInjection() error {
+	return s.file.Update(func(state *State) error {
+		state.Injections++
+		return nil
+	})
+}
+
+// RecordError increments the number of failed invocations and records the
+// specified failure class as the class of the most recent error.
+func (s *Store) RecordError(class string) error {
+	return s.file.Update(func(state *State) error {
+		state.Errors++
+		state.LastErrorClass = class
+		state.LastErrorTimestamp = s.now().Unix()
+		return nil
+	})
+}
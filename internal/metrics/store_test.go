@@ -0,0 +1,69 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "metrics", "runtime-metrics.json"))
+	store.now = func() time.Time {
+		return time.Unix(1000, 0)
+	}
+
+	state, err := store.Load()
+	require.NoError(t, err)
+	require.EqualValues(t, &State{}, state)
+
+	require.NoError(t, store.RecordInjection())
+	require.NoError(t, store.RecordInjection())
+	require.NoError(t, store.RecordError("oci-spec"))
+	require.NoError(t, store.RecordError("modifier"))
+
+	state, err = store.Load()
+	require.NoError(t, err)
+	require.EqualValues(t,
+		&State{
+			Injections:         2,
+			Errors:             2,
+			LastErrorClass:     "modifier",
+			LastErrorTimestamp: 1000,
+		},
+		state,
+	)
+}
+
+func TestStoreResetsCorruptState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "runtime-metrics.json")
+	require.NoError(t, os.WriteFile(path, []byte("not-json"), 0644))
+
+	store := NewStore(path)
+	_, err := store.Load()
+	require.Error(t, err)
+
+	require.NoError(t, store.RecordInjection())
+
+	state, err := store.Load()
+	require.NoError(t, err)
+	require.EqualValues(t, &State{Injections: 1}, state)
+}
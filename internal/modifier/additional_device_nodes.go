@@ -0,0 +1,104 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config/image"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/discover"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/oci"
+)
+
+// NewAdditionalDeviceNodesModifier creates a modifier that injects the
+// additional device nodes defined in the config. This allows the device nodes
+// of out-of-tree drivers (e.g. nvidia-vgpu-vfio) that are not discovered by
+// the NVIDIA Container Toolkit to be injected into containers that request
+// GPUs. The device nodes for a driver capability are only injected if the
+// capability is requested, with those for the "all" capability always being
+// injected. A nil modifier is returned if no additional device nodes are
+// defined or no devices are requested.
+func NewAdditionalDeviceNodesModifier(logger logger.Interface, cfg *config.Config, container image.CUDA) (oci.SpecModifier, error) {
+	patterns, err := getAdditionalDeviceNodes(logger, cfg, container)
+	if err != nil {
+		return nil, err
+	}
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+
+	d := discover.NewCharDeviceDiscoverer(
+		logger,
+		cfg.NVIDIAContainerCLIConfig.Root,
+		patterns,
+	)
+	return NewModifierFromDiscoverer(logger, d)
+}
+
+// getAdditionalDeviceNodes returns the patterns of the additional device
+// nodes that are required for the driver capabilities requested by the
+// container.
+func getAdditionalDeviceNodes(logger logger.Interface, cfg *config.Config, container image.CUDA) ([]string, error) {
+	deviceNodes := cfg.NVIDIAContainerRuntimeConfig.AdditionalDeviceNodes
+	if len(deviceNodes) == 0 {
+		return nil, nil
+	}
+	if err := validateAdditionalDeviceNodes(deviceNodes); err != nil {
+		return nil, err
+	}
+	if len(container.VisibleDevices()) == 0 {
+		return nil, nil
+	}
+
+	requested := getRequestedDriverCapabilities(cfg, container)
+
+	var capabilities []string
+	for c := range deviceNodes {
+		capabilities = append(capabilities, c)
+	}
+	slices.Sort(capabilities)
+
+	var patterns []string
+	for _, c := range capabilities {
+		if c != string(image.DriverCapabilityAll) && !requested.Has(image.DriverCapability(c)) {
+			logger.Debugf("Skipping additional device nodes %v; driver capability %v not requested", deviceNodes[c], c)
+			continue
+		}
+		patterns = append(patterns, deviceNodes[c]...)
+	}
+	return patterns, nil
+}
+
+// validateAdditionalDeviceNodes checks that the additional device nodes are
+// associated with supported driver capabilities and are located in /dev.
+func validateAdditionalDeviceNodes(deviceNodes map[string][]string) error {
+	for c, patterns := range deviceNodes {
+		if c != string(image.DriverCapabilityAll) && !image.SupportedDriverCapabilities.Has(image.DriverCapability(c)) {
+			return fmt.Errorf("unsupported driver capability %q for additional device nodes", c)
+		}
+		for _, pattern := range patterns {
+			if !strings.HasPrefix(pattern, "/dev/") {
+				return fmt.Errorf("invalid additional device node %q: must be in /dev", pattern)
+			}
+		}
+	}
+	return nil
+}
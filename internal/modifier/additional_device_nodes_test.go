@@ -0,0 +1,101 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config/image"
+)
+
+func TestGetAdditionalDeviceNodes(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	testCases := []struct {
+		description   string
+		deviceNodes   map[string][]string
+		env           []string
+		expectedError bool
+		expected      []string
+	}{
+		{
+			description: "no additional device nodes",
+			env:         []string{"NVIDIA_VISIBLE_DEVICES=all"},
+		},
+		{
+			description: "no devices requested",
+			deviceNodes: map[string][]string{"all": {"/dev/vfio/*"}},
+		},
+		{
+			description: "all device nodes are always injected",
+			deviceNodes: map[string][]string{"all": {"/dev/vfio/*"}},
+			env:         []string{"NVIDIA_VISIBLE_DEVICES=all", "NVIDIA_DRIVER_CAPABILITIES=utility"},
+			expected:    []string{"/dev/vfio/*"},
+		},
+		{
+			description: "device nodes for requested capabilities are injected",
+			deviceNodes: map[string][]string{
+				"compute": {"/dev/nvidia-vgpu*"},
+				"video":   {"/dev/vfio/vfio"},
+			},
+			env:      []string{"NVIDIA_VISIBLE_DEVICES=all", "NVIDIA_DRIVER_CAPABILITIES=compute,utility"},
+			expected: []string{"/dev/nvidia-vgpu*"},
+		},
+		{
+			description: "default capabilities are considered",
+			deviceNodes: map[string][]string{"compute": {"/dev/nvidia-vgpu*"}},
+			env:         []string{"NVIDIA_VISIBLE_DEVICES=all"},
+			expected:    []string{"/dev/nvidia-vgpu*"},
+		},
+		{
+			description:   "unsupported capability is an error",
+			deviceNodes:   map[string][]string{"invalid": {"/dev/vfio/*"}},
+			env:           []string{"NVIDIA_VISIBLE_DEVICES=all"},
+			expectedError: true,
+		},
+		{
+			description:   "device nodes outside of /dev are an error",
+			deviceNodes:   map[string][]string{"all": {"/tmp/vfio"}},
+			env:           []string{"NVIDIA_VISIBLE_DEVICES=all"},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			cfg := &config.Config{
+				NVIDIAContainerRuntimeConfig: config.RuntimeConfig{
+					AdditionalDeviceNodes: tc.deviceNodes,
+				},
+			}
+			container, err := image.New(image.WithLogger(logger), image.WithEnv(tc.env))
+			require.NoError(t, err)
+
+			patterns, err := getAdditionalDeviceNodes(logger, cfg, container)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.EqualValues(t, tc.expected, patterns)
+		})
+	}
+}
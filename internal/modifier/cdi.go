@@ -17,17 +17,24 @@
 package modifier
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
 	"strings"
 
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
 	"tags.cncf.io/container-device-interface/pkg/parser"
+	"tags.cncf.io/container-device-interface/specs-go"
 
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/config/image"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup/root"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/modifier/cdi"
-	"github.com/NVIDIA/nvidia-container-toolkit/internal/oci"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/retry"
 	"github.com/NVIDIA/nvidia-container-toolkit/pkg/nvcdi"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/nvcdi/spec"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/oci"
 )
 
 const (
@@ -62,7 +69,12 @@ func NewCDIModifier(logger logger.Interface, cfg *config.Config, image image.CUD
 		return nil, fmt.Errorf("requesting a CDI device with vendor 'runtime.nvidia.com' is not supported when requesting other CDI devices")
 	}
 	if len(automaticDevices) > 0 {
-		automaticModifier, err := newAutomaticCDISpecModifier(logger, cfg, automaticDevices)
+		options := []nvcdi.Option{
+			withCompat32Libraries(image),
+			nvcdi.WithDriverCapabilities(image.GetDriverCapabilities().String()),
+		}
+		options = append(options, withLibraryFilter(cfg)...)
+		automaticModifier, err := newAutomaticCDISpecModifier(logger, cfg, automaticDevices, options...)
 		if err == nil {
 			return automaticModifier, nil
 		}
@@ -102,6 +114,10 @@ func (c *cdiDeviceRequestor) DeviceRequests() []string {
 	}
 	var devices []string
 	for _, name := range c.image.VisibleDevices() {
+		// NVIDIA_VISIBLE_DEVICES=none is represented as an empty device.
+		if name == "" {
+			name = image.NoneDeviceName
+		}
 		if !parser.IsQualifiedName(name) {
 			name = fmt.Sprintf("%s=%s", c.defaultKind, name)
 		}
@@ -126,7 +142,7 @@ func filterAutomaticDevices(devices []string) []string {
 	return automatic
 }
 
-func newAutomaticCDISpecModifier(logger logger.Interface, cfg *config.Config, devices []string) (oci.SpecModifier, error) {
+func newAutomaticCDISpecModifier(logger logger.Interface, cfg *config.Config, devices []string, opts ...nvcdi.Option) (oci.SpecModifier, error) {
 	logger.Debugf("Generating in-memory CDI specs for devices %v", devices)
 
 	var identifiers []string
@@ -134,30 +150,223 @@ func newAutomaticCDISpecModifier(logger logger.Interface, cfg *config.Config, de
 		identifiers = append(identifiers, strings.TrimPrefix(device, automaticDevicePrefix))
 	}
 
-	cdilib, err := nvcdi.New(
+	cdiSpec, err := getAutomaticCDISpec(logger, cfg, identifiers, opts...)
+	if err != nil {
+		return nil, err
+	}
+	cdiDeviceRequestor, err := cdi.New(
+		cdi.WithLogger(logger),
+		cdi.WithSpec(cdiSpec),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct CDI modifier: %w", err)
+	}
+
+	return cdiDeviceRequestor, nil
+}
+
+// GetAutomaticCDISpec returns the CDI specification that would be generated
+// at runtime for the specified device identifiers. The identifiers may
+// optionally include the runtime.nvidia.com/gpu= prefix.
+func GetAutomaticCDISpec(logger logger.Interface, cfg *config.Config, devices ...string) (*specs.Spec, error) {
+	var identifiers []string
+	for _, device := range devices {
+		identifiers = append(identifiers, strings.TrimPrefix(device, automaticDevicePrefix))
+	}
+	return getAutomaticCDISpec(logger, cfg, identifiers)
+}
+
+// getAutomaticCDISpec returns a CDI specification for the specified device
+// identifiers. If a discovery manifest is configured this is used, otherwise
+// the specification is generated by discovering the driver and devices.
+func getAutomaticCDISpec(logger logger.Interface, cfg *config.Config, identifiers []string, opts ...nvcdi.Option) (*specs.Spec, error) {
+	driverVersion := getDriverVersion(logger, cfg.NVIDIAContainerCLIConfig.Root)
+	if manifestPath := cfg.NVIDIAContainerRuntimeConfig.Modes.CDI.DiscoveryManifest; manifestPath != "" {
+		manifestSpec, err := getDiscoveryManifestSpec(logger, manifestPath, driverVersion, identifiers)
+		if err == nil {
+			return manifestSpec, nil
+		}
+		logger.Warningf("Failed to use discovery manifest %v: %v; falling back to discovery", manifestPath, err)
+	}
+
+	retrier := newDiscoveryRetrier(logger, cfg)
+	cdilibOptions := automaticCDILibOptions(logger, cfg)
+	getSpec := func() (spec.Interface, error) {
+		cdilib, err := nvcdi.New(append(cdilibOptions, opts...)...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to construct CDI library: %w", err)
+		}
+
+		cdiSpec, err := cdilib.GetSpec(identifiers...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate CDI spec: %w", err)
+		}
+		return cdiSpec, nil
+	}
+
+	var cdiSpec spec.Interface
+	err := retrier.Do("generate CDI spec", func() error {
+		if err := checkKernelModules(logger, cfg); err != nil {
+			return err
+		}
+		generated, currentVersion, err := withDriverVersionCheck(logger, cfg.NVIDIAContainerCLIConfig.Root, driverVersion, getSpec)
+		driverVersion = currentVersion
+		if err != nil {
+			return err
+		}
+		cdiSpec = generated
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cdiSpec.Raw(), nil
+}
+
+// automaticCDILibOptions returns the nvcdi options used to generate CDI
+// specifications at runtime.
+func automaticCDILibOptions(logger logger.Interface, cfg *config.Config) []nvcdi.Option {
+	cdilibOptions := []nvcdi.Option{
 		nvcdi.WithLogger(logger),
 		nvcdi.WithNVIDIACDIHookPath(cfg.NVIDIACTKConfig.Path),
 		nvcdi.WithDriverRoot(cfg.NVIDIAContainerCLIConfig.Root),
 		nvcdi.WithVendor(automaticDeviceVendor),
 		nvcdi.WithClass(automaticDeviceClass),
+		nvcdi.WithFeatureFlags(nvcdiFeatureFlags(cfg)...),
+		nvcdi.WithDeviceOrder(cfg.NVIDIAContainerRuntimeConfig.Modes.CDI.DeviceOrder),
+	}
+	return append(cdilibOptions, nvcdiHookPathOptions(cfg)...)
+}
+
+// errDriverVersionChanged is returned if the driver version changes while a
+// CDI spec is being generated (e.g. during a rolling driver upgrade).
+var errDriverVersionChanged = errors.New("driver version changed during discovery")
+
+// newDiscoveryRetrier returns a retrier for the discovery of driver files and
+// devices as configured in the specified config. This allows transient
+// conditions such as a driver that is still loading to be survived. If
+// retries are not configured, discovery is only retried once if the driver
+// version changed while the spec was being generated.
+func newDiscoveryRetrier(logger logger.Interface, cfg *config.Config) *retry.Retrier {
+	retries := cfg.NVIDIAContainerRuntimeConfig.DiscoveryRetries
+
+	attempts := retries.Attempts
+	isTransient := isTransientDiscoveryError
+	if attempts < 2 {
+		attempts = 2
+		isTransient = func(err error) bool {
+			return errors.Is(err, errDriverVersionChanged)
+		}
+	}
+
+	return retry.New(
+		retry.WithLogger(logger),
+		retry.WithAttempts(attempts),
+		retry.WithBackoff(retries.GetBackoff()),
+		retry.WithIsTransient(isTransient),
 	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to construct CDI library: %w", err)
+}
+
+// isTransientDiscoveryError checks whether the specified error may be due to
+// a driver that is still being installed or loaded, or device nodes that have
+// not yet been created. Other errors are not retried.
+func isTransientDiscoveryError(err error) bool {
+	switch {
+	case errors.Is(err, errDriverVersionChanged),
+		errors.Is(err, errKernelModulesNotLoaded),
+		errors.Is(err, fs.ErrNotExist),
+		errors.Is(err, nvml.ERROR_DRIVER_NOT_LOADED),
+		errors.Is(err, nvml.ERROR_LIBRARY_NOT_FOUND),
+		errors.Is(err, nvml.ERROR_UNINITIALIZED):
+		return true
 	}
+	return false
+}
 
-	spec, err := cdilib.GetSpec(identifiers...)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate CDI spec: %w", err)
+// withDriverVersionCheck calls the specified function to generate a CDI spec.
+// If the driver version differs from the specified initial version after the
+// spec is generated, an errDriverVersionChanged error is returned since the
+// spec may not be consistent with the installed driver. The current driver
+// version is returned so that this can be used as the initial version of a
+// subsequent attempt.
+func withDriverVersionCheck(logger logger.Interface, driverRoot string, initialVersion string, getSpec func() (spec.Interface, error)) (spec.Interface, string, error) {
+	cdiSpec, err := getSpec()
+
+	currentVersion := getDriverVersion(logger, driverRoot)
+	if currentVersion != initialVersion {
+		return nil, currentVersion, fmt.Errorf("%w: from %q to %q", errDriverVersionChanged, initialVersion, currentVersion)
 	}
-	cdiDeviceRequestor, err := cdi.New(
-		cdi.WithLogger(logger),
-		cdi.WithSpec(spec.Raw()),
+	return cdiSpec, currentVersion, err
+}
+
+// getDriverVersion returns the version of the driver installed at the
+// specified root. Since a new driver root is constructed, the returned
+// version is not cached.
+func getDriverVersion(logger logger.Interface, driverRoot string) string {
+	driver := root.New(
+		root.WithLogger(logger),
+		root.WithDriverRoot(driverRoot),
 	)
+	version, err := driver.Version()
 	if err != nil {
-		return nil, fmt.Errorf("failed to construct CDI modifier: %w", err)
+		logger.Debugf("Failed to determine driver version: %v", err)
+		return ""
 	}
+	return version
+}
 
-	return cdiDeviceRequestor, nil
+// withCompat32Libraries returns an nvcdi option that includes the 32-bit
+// driver libraries if the compat32 driver capability is requested.
+func withCompat32Libraries(container image.CUDA) nvcdi.Option {
+	return nvcdi.WithCompat32Libraries(container.GetDriverCapabilities().Has(image.DriverCapabilityCompat32))
+}
+
+// withLibraryFilter returns the nvcdi options that filter the injected driver
+// libraries by the driver capabilities requested by the container if this is
+// enabled in the specified config. The driver capabilities must be set
+// separately using nvcdi.WithDriverCapabilities.
+func withLibraryFilter(cfg *config.Config) []nvcdi.Option {
+	if !cfg.NVIDIAContainerRuntimeConfig.FilterLibrariesByCapability {
+		return nil
+	}
+	return []nvcdi.Option{
+		nvcdi.WithLibraryFilter(cfg.NVIDIAContainerRuntimeConfig.LibraryGroupsFile),
+	}
+}
+
+// nvcdiFeatureFlags returns the nvcdi feature flags associated with the
+// features enabled in the specified config.
+func nvcdiFeatureFlags(cfg *config.Config) []nvcdi.FeatureFlag {
+	var featureFlags []nvcdi.FeatureFlag
+	if cfg.Features.SkipLDCacheCreation.IsEnabled() {
+		featureFlags = append(featureFlags, nvcdi.FeatureSkipLDCacheCreation)
+	}
+	if cfg.Features.SandboxHooks.IsEnabled() {
+		featureFlags = append(featureFlags, nvcdi.FeatureSandboxHooks)
+	}
+	if cfg.Features.StableHookPaths.IsEnabled() {
+		featureFlags = append(featureFlags, nvcdi.FeatureStableHookPaths)
+	}
+	if cfg.Features.InjectDriverParams.IsEnabled() {
+		featureFlags = append(featureFlags, nvcdi.FeatureInjectDriverParams)
+	}
+	if cfg.Features.InjectGPUSysfs.IsEnabled() {
+		featureFlags = append(featureFlags, nvcdi.FeatureInjectGPUSysfs)
+	}
+	if cfg.Features.EnableEGM.IsEnabled() {
+		featureFlags = append(featureFlags, nvcdi.FeatureEnableEGM)
+	}
+	return featureFlags
+}
+
+// nvcdiHookPathOptions returns the nvcdi options for the per-hook executable
+// paths in the config.
+func nvcdiHookPathOptions(cfg *config.Config) []nvcdi.Option {
+	var opts []nvcdi.Option
+	for name, path := range cfg.NVIDIACTKConfig.HookPaths {
+		opts = append(opts, nvcdi.WithHookPath(name, path))
+	}
+	return opts
 }
 
 type deduplicatedDeviceRequestor struct {
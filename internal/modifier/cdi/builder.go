@@ -23,7 +23,7 @@ import (
 	"tags.cncf.io/container-device-interface/specs-go"
 
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
-	"github.com/NVIDIA/nvidia-container-toolkit/internal/oci"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/oci"
 )
 
 type builder struct {
@@ -73,6 +73,7 @@ func (m builder) build() (oci.SpecModifier, error) {
 		logger:   m.logger,
 		registry: registry,
 		devices:  m.devices,
+		procRoot: "/",
 	}
 
 	return modifier, nil
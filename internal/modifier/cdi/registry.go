@@ -19,12 +19,17 @@ package cdi
 import (
 	"errors"
 	"fmt"
+	"slices"
+	"strconv"
 
 	"github.com/opencontainers/runtime-spec/specs-go"
 	"tags.cncf.io/container-device-interface/pkg/cdi"
+	"tags.cncf.io/container-device-interface/pkg/parser"
 
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config/image"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/info/proc"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
-	"github.com/NVIDIA/nvidia-container-toolkit/internal/oci"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/oci"
 )
 
 // fromRegistry represents the modifications performed using a CDI registry.
@@ -32,6 +37,9 @@ type fromRegistry struct {
 	logger   logger.Interface
 	registry *cdi.Cache
 	devices  []string
+	// procRoot is the root at which the GPU information files used to
+	// resolve PCI bus IDs are found.
+	procRoot string
 }
 
 var _ oci.SpecModifier = (*fromRegistry)(nil)
@@ -42,8 +50,17 @@ func (m fromRegistry) Modify(spec *specs.Spec) error {
 		m.logger.Debugf("The following error was triggered when refreshing the CDI registry: %v", err)
 	}
 
-	m.logger.Debugf("Injecting devices using CDI: %v", m.devices)
-	unresolvedDevices, err := m.registry.InjectDevices(spec, m.devices...)
+	m.devices = m.resolvePCIBusIDs()
+	devices, noneKinds := m.splitNoneDevices()
+	if err := m.injectCommonEdits(spec, noneKinds); err != nil {
+		return err
+	}
+	if len(devices) == 0 {
+		return nil
+	}
+
+	m.logger.Debugf("Injecting devices using CDI: %v", devices)
+	unresolvedDevices, err := m.registry.InjectDevices(spec, devices...)
 	if unresolvedDevices != nil {
 		m.logger.Warningf("could not resolve CDI devices: %v", unresolvedDevices)
 	}
@@ -64,3 +81,89 @@ func (m fromRegistry) Modify(spec *specs.Spec) error {
 
 	return nil
 }
+
+// splitNoneDevices separates requests for the special "none" device from the
+// other requested devices. The kinds for which only the "none" device is
+// requested are returned. If a spec defines a device named "none", this is
+// injected as a regular device instead.
+func (m fromRegistry) splitNoneDevices() ([]string, []string) {
+	var devices []string
+	var noneKinds []string
+	requestedKinds := make(map[string]bool)
+	for _, device := range m.devices {
+		vendor, class, name := parser.ParseDevice(device)
+		kind := vendor + "/" + class
+		if name != image.NoneDeviceName || m.registry.GetDevice(device) != nil {
+			devices = append(devices, device)
+			requestedKinds[kind] = true
+			continue
+		}
+		if !slices.Contains(noneKinds, kind) {
+			noneKinds = append(noneKinds, kind)
+		}
+	}
+
+	// If other devices of the same kind are requested, the common edits
+	// are already injected for these devices.
+	noneKinds = slices.DeleteFunc(noneKinds, func(kind string) bool {
+		return requestedKinds[kind]
+	})
+	return devices, noneKinds
+}
+
+// injectCommonEdits applies the common edits of the CDI specs for each of the
+// specified kinds to the OCI spec without injecting any devices.
+func (m fromRegistry) injectCommonEdits(spec *specs.Spec, kinds []string) error {
+	for _, kind := range kinds {
+		vendor, class := parser.ParseQualifier(kind)
+
+		var found bool
+		for _, cdiSpec := range m.registry.GetVendorSpecs(vendor) {
+			if cdiSpec.GetClass() != class {
+				continue
+			}
+			found = true
+			m.logger.Debugf("Injecting common edits from CDI spec %v", cdiSpec.GetPath())
+			if err := cdiSpec.ApplyEdits(spec); err != nil {
+				return fmt.Errorf("failed to apply common edits from %v: %w", cdiSpec.GetPath(), err)
+			}
+		}
+		if !found {
+			return fmt.Errorf("failed to inject CDI devices: no CDI spec found for %v=%v", kind, image.NoneDeviceName)
+		}
+	}
+	return nil
+}
+
+// resolvePCIBusIDs replaces devices requested by PCI bus ID that are not
+// defined in the CDI specs with the devices named by the UUID or index of the
+// corresponding GPU. This allows devices to be requested by PCI bus ID
+// regardless of the naming strategy used to generate the CDI specs.
+func (m fromRegistry) resolvePCIBusIDs() []string {
+	var devices []string
+	for _, device := range m.devices {
+		vendor, class, name := parser.ParseDevice(device)
+		if !proc.IsPCIBusID(name) || m.registry.GetDevice(device) != nil {
+			devices = append(devices, device)
+			continue
+		}
+		gpu, err := proc.GetGPUByPCIBusID(m.procRoot, name)
+		if err != nil {
+			m.logger.Warningf("Failed to resolve PCI bus ID %v: %v", name, err)
+			devices = append(devices, device)
+			continue
+		}
+
+		resolved := device
+		for _, candidate := range []string{gpu.UUID, strconv.Itoa(gpu.Index)} {
+			qualified := parser.QualifiedName(vendor, class, candidate)
+			if m.registry.GetDevice(qualified) != nil {
+				resolved = qualified
+				break
+			}
+		}
+		m.logger.Debugf("Resolved device %v to %v", device, resolved)
+		devices = append(devices, resolved)
+	}
+	return devices
+}
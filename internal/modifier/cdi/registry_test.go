@@ -0,0 +1,147 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package cdi
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+const testSpec = `---
+cdiVersion: 0.5.0
+kind: nvidia.com/gpu
+devices:
+- name: "0"
+  containerEdits:
+    env:
+    - GPU=0
+containerEdits:
+  env:
+  - COMMON=1
+`
+
+func TestFromRegistryNoneDevice(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	specDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(specDir, "nvidia.yaml"), []byte(testSpec), 0600))
+
+	testCases := []struct {
+		description   string
+		devices       []string
+		expectedError bool
+		expectedEnv   []string
+	}{
+		{
+			description: "none device injects common edits only",
+			devices:     []string{"nvidia.com/gpu=none"},
+			expectedEnv: []string{"COMMON=1"},
+		},
+		{
+			description: "none device with other devices of the same kind",
+			devices:     []string{"nvidia.com/gpu=none", "nvidia.com/gpu=0"},
+			expectedEnv: []string{"COMMON=1", "GPU=0"},
+		},
+		{
+			description:   "none device for unknown kind is an error",
+			devices:       []string{"example.com/device=none"},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			m, err := New(
+				WithLogger(logger),
+				WithSpecDirs(specDir),
+				WithDevices(tc.devices...),
+			)
+			require.NoError(t, err)
+
+			spec := &specs.Spec{
+				Process: &specs.Process{},
+			}
+			err = m.Modify(spec)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.EqualValues(t, tc.expectedEnv, spec.Process.Env)
+		})
+	}
+}
+
+func TestFromRegistryPCIBusID(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	specDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(specDir, "nvidia.yaml"), []byte(testSpec), 0600))
+
+	procRoot := t.TempDir()
+	informationDir := filepath.Join(procRoot, "proc/driver/nvidia/gpus/0000:65:00.0")
+	require.NoError(t, os.MkdirAll(informationDir, 0755))
+	information := "GPU UUID:        GPU-0\nBus Location:    0000:65:00.0\n"
+	require.NoError(t, os.WriteFile(filepath.Join(informationDir, "information"), []byte(information), 0644))
+
+	testCases := []struct {
+		description   string
+		devices       []string
+		expectedError bool
+		expectedEnv   []string
+	}{
+		{
+			description: "bus ID is resolved to device name",
+			devices:     []string{"nvidia.com/gpu=0000:65:00.0"},
+			expectedEnv: []string{"COMMON=1", "GPU=0"},
+		},
+		{
+			description:   "unknown bus ID is unresolvable",
+			devices:       []string{"nvidia.com/gpu=0000:b3:00.0"},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			m, err := New(
+				WithLogger(logger),
+				WithSpecDirs(specDir),
+				WithDevices(tc.devices...),
+			)
+			require.NoError(t, err)
+			registry := m.(fromRegistry)
+			registry.procRoot = procRoot
+
+			spec := &specs.Spec{
+				Process: &specs.Process{},
+			}
+			err = registry.Modify(spec)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.EqualValues(t, tc.expectedEnv, spec.Process.Env)
+		})
+	}
+}
@@ -17,13 +17,21 @@
 package modifier
 
 import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
 	"github.com/opencontainers/runtime-spec/specs-go"
 	testlog "github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/require"
 
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/config/image"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/nvcdi/spec"
 )
 
 func TestDeviceRequests(t *testing.T) {
@@ -82,6 +90,18 @@ func TestDeviceRequests(t *testing.T) {
 			},
 			expectedDevices: []string{"runtime.nvidia.com/gpu=all"},
 		},
+		{
+			description: "none in envvar is requested as none device",
+			input: cdiDeviceRequestor{
+				defaultKind: "nvidia.com/gpu",
+			},
+			spec: &specs.Spec{
+				Process: &specs.Process{
+					Env: []string{"NVIDIA_VISIBLE_DEVICES=none"},
+				},
+			},
+			expectedDevices: []string{"nvidia.com/gpu=none"},
+		},
 		{
 			description: "no matching annotations",
 			prefixes:    []string{"not-prefix/"},
@@ -170,3 +190,171 @@ func TestDeviceRequests(t *testing.T) {
 		})
 	}
 }
+
+func TestWithDriverVersionCheck(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	testCases := []struct {
+		description     string
+		upgradeDriver   bool
+		expectedError   error
+		expectedVersion string
+	}{
+		{
+			description:     "unchanged driver version",
+			expectedVersion: "999.88.77",
+		},
+		{
+			description:     "changed driver version is an error",
+			upgradeDriver:   true,
+			expectedError:   errDriverVersionChanged,
+			expectedVersion: "999.88.78",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			driverRoot := t.TempDir()
+			libDir := filepath.Join(driverRoot, "/usr/lib64")
+			require.NoError(t, os.MkdirAll(libDir, 0755))
+			require.NoError(t, os.WriteFile(filepath.Join(libDir, "libcuda.so.999.88.77"), nil, 0644))
+
+			getSpec := func() (spec.Interface, error) {
+				if tc.upgradeDriver {
+					require.NoError(t, os.Rename(filepath.Join(libDir, "libcuda.so.999.88.77"), filepath.Join(libDir, "libcuda.so.999.88.78")))
+				}
+				return nil, nil
+			}
+
+			_, currentVersion, err := withDriverVersionCheck(logger, driverRoot, "999.88.77", getSpec)
+			require.ErrorIs(t, err, tc.expectedError)
+			require.Equal(t, tc.expectedVersion, currentVersion)
+		})
+	}
+}
+
+func TestCDIModifierFromAnnotations(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	specDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(specDir, "example.yaml"), []byte(`---
+cdiVersion: 0.5.0
+kind: example.com/device
+devices:
+- name: foo
+  containerEdits:
+    env:
+    - FOO=injected
+`), 0644))
+
+	cfg := &config.Config{}
+	cfg.NVIDIAContainerRuntimeConfig.Modes.CDI.SpecDirs = []string{specDir}
+	cfg.NVIDIAContainerRuntimeConfig.Modes.CDI.DefaultKind = "nvidia.com/gpu"
+
+	spec := &specs.Spec{
+		Process: &specs.Process{},
+		Annotations: map[string]string{
+			"cdi.k8s.io/example": "example.com/device=foo",
+		},
+	}
+
+	image, err := image.NewCUDAImageFromSpec(
+		spec,
+		image.WithAnnotationsPrefixes([]string{"cdi.k8s.io/"}),
+	)
+	require.NoError(t, err)
+
+	m, err := NewCDIModifier(logger, cfg, image, false)
+	require.NoError(t, err)
+	require.NotNil(t, m)
+
+	require.NoError(t, m.Modify(spec))
+	require.Contains(t, spec.Process.Env, "FOO=injected")
+}
+
+func TestWithLibraryFilter(t *testing.T) {
+	testCases := []struct {
+		description     string
+		filter          bool
+		expectedOptions int
+	}{
+		{
+			description: "filter disabled",
+		},
+		{
+			description:     "filter enabled",
+			filter:          true,
+			expectedOptions: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.NVIDIAContainerRuntimeConfig.FilterLibrariesByCapability = tc.filter
+
+			options := withLibraryFilter(cfg)
+			require.Len(t, options, tc.expectedOptions)
+		})
+	}
+}
+
+func TestNewDiscoveryRetrier(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	errOther := errors.New("other")
+
+	testCases := []struct {
+		description   string
+		attempts      int
+		err           error
+		expectedCalls int
+	}{
+		{
+			description:   "driver version change is retried once by default",
+			err:           errDriverVersionChanged,
+			expectedCalls: 2,
+		},
+		{
+			description:   "transient error is not retried by default",
+			err:           fs.ErrNotExist,
+			expectedCalls: 1,
+		},
+		{
+			description:   "transient error is retried",
+			attempts:      3,
+			err:           fmt.Errorf("failed to initialize NVML: %w", nvml.ERROR_DRIVER_NOT_LOADED),
+			expectedCalls: 3,
+		},
+		{
+			description:   "missing kernel modules are retried",
+			attempts:      3,
+			err:           errKernelModulesNotLoaded,
+			expectedCalls: 3,
+		},
+		{
+			description:   "other error is not retried",
+			attempts:      3,
+			err:           errOther,
+			expectedCalls: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.NVIDIAContainerRuntimeConfig.DiscoveryRetries.Attempts = tc.attempts
+			cfg.NVIDIAContainerRuntimeConfig.DiscoveryRetries.Backoff = "1ms"
+
+			retrier := newDiscoveryRetrier(logger, cfg)
+
+			var calls int
+			err := retrier.Do("generate CDI spec", func() error {
+				calls++
+				return tc.err
+			})
+			require.ErrorIs(t, err, tc.err)
+			require.Equal(t, tc.expectedCalls, calls)
+		})
+	}
+}
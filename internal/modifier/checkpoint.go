@@ -0,0 +1,165 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/oci"
+)
+
+const (
+	// CheckpointDirectoryAnnotation requests that a checkpoint directory is
+	// mounted into a container.
+	CheckpointDirectoryAnnotation = "nvidia.com/checkpoint-directory"
+	// CheckpointNameAnnotation optionally sets the name of the subdirectory of
+	// the checkpoint directory that is mounted into a container. Since this
+	// name does not change when a container is restored from a checkpoint,
+	// this allows the restored container to access the checkpoint artifacts.
+	CheckpointNameAnnotation = "nvidia.com/checkpoint-name"
+	// checkpointDirectoryEnvvar is set to the path of the checkpoint directory
+	// in the container.
+	checkpointDirectoryEnvvar = "NVIDIA_CHECKPOINT_DIR"
+)
+
+// podUIDAnnotations are the annotations that container engines use to
+// specify the UID of the Kubernetes pod that a container belongs to.
+var podUIDAnnotations = []string{
+	"io.kubernetes.pod.uid",
+	"io.kubernetes.cri.sandbox-uid",
+}
+
+// checkpointDirectoryMounter mounts a subdirectory of the configured
+// checkpoint directory into containers that request this.
+type checkpointDirectoryMounter struct {
+	logger    logger.Interface
+	hostPath  string
+	bundleDir string
+}
+
+var _ oci.SpecModifier = (*checkpointDirectoryMounter)(nil)
+
+// NewCheckpointDirectoryMounter creates a modifier that mounts a directory for
+// CUDA checkpoint artifacts into containers that request this through the
+// nvidia.com/checkpoint-directory annotation. Each pod (or each container
+// outside of Kubernetes) is assigned its own subdirectory of the configured
+// host directory so that containers cannot access the artifacts of other
+// containers. Containers that are restored from a checkpoint share the
+// subdirectory of the checkpointed container if both specify the same
+// nvidia.com/checkpoint-name annotation.
+// A nil modifier is returned if no checkpoint directory is configured.
+func NewCheckpointDirectoryMounter(logger logger.Interface, cfg *config.Config, bundleDir string) oci.SpecModifier {
+	hostPath := cfg.NVIDIAContainerRuntimeConfig.CheckpointDirectory
+	if hostPath == "" {
+		return nil
+	}
+	return &checkpointDirectoryMounter{
+		logger:    logger,
+		hostPath:  hostPath,
+		bundleDir: bundleDir,
+	}
+}
+
+// Modify mounts the checkpoint directory of the container at the configured
+// path and sets NVIDIA_CHECKPOINT_DIR to this path. Since a container that
+// requests a checkpoint directory relies on its artifacts being persisted, an
+// error is returned if the configured directory does not exist.
+func (m *checkpointDirectoryMounter) Modify(spec *specs.Spec) error {
+	if spec == nil || spec.Annotations[CheckpointDirectoryAnnotation] != "true" {
+		return nil
+	}
+
+	if !filepath.IsAbs(m.hostPath) {
+		return fmt.Errorf("checkpoint directory %q is not an absolute path", m.hostPath)
+	}
+	hostPath := filepath.Clean(m.hostPath)
+	info, err := os.Stat(hostPath)
+	if err != nil {
+		return fmt.Errorf("invalid checkpoint directory: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("checkpoint directory %v is not a directory", hostPath)
+	}
+
+	name, err := m.getSubdirectoryName(spec)
+	if err != nil {
+		return err
+	}
+
+	var uid, gid uint32
+	if spec.Process != nil {
+		var uidMappings, gidMappings []specs.LinuxIDMapping
+		if spec.Linux != nil {
+			uidMappings = spec.Linux.UIDMappings
+			gidMappings = spec.Linux.GIDMappings
+		}
+		uid = getHostID(spec.Process.User.UID, uidMappings)
+		gid = getHostID(spec.Process.User.GID, gidMappings)
+	}
+
+	source := filepath.Join(hostPath, name)
+	if err := createUserDir(source, uid, gid); err != nil {
+		return fmt.Errorf("failed to create checkpoint directory: %w", err)
+	}
+
+	m.logger.Debugf("Mounting checkpoint directory %v at %v", source, hostPath)
+	spec.Mounts = append(spec.Mounts, specs.Mount{
+		Destination: hostPath,
+		Type:        "bind",
+		Source:      source,
+		Options:     []string{"rw", "nosuid", "nodev", "rbind", "rprivate"},
+	})
+	if spec.Process != nil {
+		spec.Process.Env = setEnvvar(spec.Process.Env, checkpointDirectoryEnvvar, hostPath)
+	}
+	return nil
+}
+
+// getSubdirectoryName returns the name of the subdirectory of the checkpoint
+// directory for the specified container. This is the checkpoint name if
+// specified. Otherwise this is the UID of the pod if the container belongs to
+// a Kubernetes pod and the name of the bundle directory (i.e. the container
+// ID) otherwise. Note that the pod UID and container ID change when a
+// container is restored into a new pod or container.
+func (m *checkpointDirectoryMounter) getSubdirectoryName(spec *specs.Spec) (string, error) {
+	name := spec.Annotations[CheckpointNameAnnotation]
+	if name == "" {
+		for _, annotation := range podUIDAnnotations {
+			if uid := spec.Annotations[annotation]; uid != "" {
+				name = uid
+				break
+			}
+		}
+	}
+	if name == "" {
+		bundleDir, err := filepath.Abs(m.bundleDir)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve bundle directory: %w", err)
+		}
+		name = filepath.Base(bundleDir)
+	}
+	if name == "." || name == ".." || name == string(filepath.Separator) || filepath.Base(name) != name {
+		return "", fmt.Errorf("invalid checkpoint directory name %q", name)
+	}
+	return name, nil
+}
@@ -0,0 +1,138 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckpointDirectoryMounter(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	uid := uint32(os.Getuid())
+	gid := uint32(os.Getgid())
+
+	testCases := []struct {
+		description      string
+		missingDirectory bool
+		annotations      map[string]string
+		expectedSubdir   string
+		expectedError    bool
+	}{
+		{
+			description: "container without annotation is not modified",
+		},
+		{
+			description:    "container uses bundle name",
+			annotations:    map[string]string{CheckpointDirectoryAnnotation: "true"},
+			expectedSubdir: "container-id",
+		},
+		{
+			description: "pod uid is used",
+			annotations: map[string]string{
+				CheckpointDirectoryAnnotation: "true",
+				"io.kubernetes.pod.uid":       "pod-uid",
+			},
+			expectedSubdir: "pod-uid",
+		},
+		{
+			description: "checkpoint name is used",
+			annotations: map[string]string{
+				CheckpointDirectoryAnnotation: "true",
+				CheckpointNameAnnotation:      "training-job",
+				"io.kubernetes.pod.uid":       "pod-uid",
+			},
+			expectedSubdir: "training-job",
+		},
+		{
+			description: "invalid checkpoint name raises error",
+			annotations: map[string]string{
+				CheckpointDirectoryAnnotation: "true",
+				CheckpointNameAnnotation:      "../training-job",
+			},
+			expectedError: true,
+		},
+		{
+			description: "invalid pod uid raises error",
+			annotations: map[string]string{
+				CheckpointDirectoryAnnotation: "true",
+				"io.kubernetes.pod.uid":       "../pod-uid",
+			},
+			expectedError: true,
+		},
+		{
+			description:      "missing checkpoint directory raises error",
+			missingDirectory: true,
+			annotations:      map[string]string{CheckpointDirectoryAnnotation: "true"},
+			expectedError:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			hostPath := filepath.Join(t.TempDir(), "checkpoint")
+			if !tc.missingDirectory {
+				require.NoError(t, os.Mkdir(hostPath, 0755))
+			}
+			bundleDir := filepath.Join(t.TempDir(), "container-id")
+
+			m := &checkpointDirectoryMounter{
+				logger:    logger,
+				hostPath:  hostPath,
+				bundleDir: bundleDir,
+			}
+			spec := &specs.Spec{
+				Annotations: tc.annotations,
+				Process: &specs.Process{
+					User: specs.User{UID: uid, GID: gid},
+				},
+			}
+
+			err := m.Modify(spec)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			if tc.expectedSubdir == "" {
+				require.Empty(t, spec.Mounts)
+				require.Empty(t, spec.Process.Env)
+				return
+			}
+			require.EqualValues(t,
+				[]specs.Mount{
+					{
+						Destination: hostPath,
+						Type:        "bind",
+						Source:      filepath.Join(hostPath, tc.expectedSubdir),
+						Options:     []string{"rw", "nosuid", "nodev", "rbind", "rprivate"},
+					},
+				},
+				spec.Mounts,
+			)
+			require.EqualValues(t, []string{"NVIDIA_CHECKPOINT_DIR=" + hostPath}, spec.Process.Env)
+			require.DirExists(t, filepath.Join(hostPath, tc.expectedSubdir))
+		})
+	}
+}
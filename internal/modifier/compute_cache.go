@@ -0,0 +1,261 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config/image"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/units"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/oci"
+)
+
+const (
+	// PersistentComputeCacheAnnotation requests that the compute cache of a
+	// container is persisted in the configured host path.
+	PersistentComputeCacheAnnotation = "nvidia.com/persistent-compute-cache"
+
+	// computeCacheDir is the path of the CUDA JIT compute cache relative to
+	// the home directory of the container user.
+	computeCacheDir = ".nv/ComputeCache"
+
+	// envCUDACacheMaxSize is the envvar that limits the size of the CUDA JIT
+	// compute cache in bytes.
+	envCUDACacheMaxSize = "CUDA_CACHE_MAXSIZE"
+)
+
+// podNamespaceAnnotations are the annotations that container engines use to
+// specify the Kubernetes namespace of the pod that a container belongs to.
+var podNamespaceAnnotations = []string{
+	"io.kubernetes.pod.namespace",
+	"io.kubernetes.cri.sandbox-namespace",
+}
+
+// computeCacheMounter mounts a tmpfs or a persistent host directory at the
+// CUDA compute cache location.
+type computeCacheMounter struct {
+	logger   logger.Interface
+	size     string
+	hostPath string
+	maxSize  uint64
+}
+
+var _ oci.SpecModifier = (*computeCacheMounter)(nil)
+
+// NewComputeCacheMounter creates a modifier that mounts a compute cache at
+// ~/.nv/ComputeCache in the container. If a host path is configured and the
+// container requests this through the nvidia.com/persistent-compute-cache
+// annotation, a subdirectory of this path is bind-mounted so that
+// JIT-compiled kernels are reused by subsequent containers. Otherwise a tmpfs
+// of the configured size is mounted. This ensures that the CUDA JIT cache is
+// writable for containers with a read-only root filesystem.
+// A nil modifier is returned if neither a host path nor a size is configured
+// or if no devices are requested.
+func NewComputeCacheMounter(logger logger.Interface, cfg *config.Config, image image.CUDA) oci.SpecModifier {
+	size := cfg.NVIDIAContainerRuntimeConfig.ComputeCacheTmpfsSize
+	hostPath := cfg.NVIDIAContainerRuntimeConfig.ComputeCacheHostPath
+	if size == "" && hostPath == "" {
+		return nil
+	}
+	if devices := image.VisibleDevices(); len(devices) == 0 {
+		return nil
+	}
+
+	// The max size is validated when the config is loaded.
+	var maxSize uint64
+	if value := cfg.NVIDIAContainerRuntimeConfig.ComputeCacheMaxSize; value != "" {
+		parsed, err := units.ParseSize(value)
+		if err != nil {
+			logger.Warningf("Ignoring invalid compute cache max size: %v", err)
+		}
+		maxSize = parsed
+	}
+
+	return &computeCacheMounter{
+		logger:   logger,
+		size:     size,
+		hostPath: hostPath,
+		maxSize:  maxSize,
+	}
+}
+
+// Modify adds the mount for the compute cache to the spec. Containers
+// for which the home directory cannot be determined or that already include a
+// mount at the compute cache location are not modified.
+func (m *computeCacheMounter) Modify(spec *specs.Spec) error {
+	if spec == nil || spec.Process == nil {
+		return nil
+	}
+
+	home := getHomeDir(spec.Process)
+	if home == "" {
+		m.logger.Warningf("Skipping compute cache mount; could not determine home directory for user %d", spec.Process.User.UID)
+		return nil
+	}
+	destination := filepath.Join(home, computeCacheDir)
+
+	for _, mount := range spec.Mounts {
+		if filepath.Clean(mount.Destination) == destination {
+			m.logger.Debugf("Skipping compute cache mount; %v is already mounted", destination)
+			return nil
+		}
+	}
+
+	var mount *specs.Mount
+	switch {
+	case m.hostPath != "" && spec.Annotations[PersistentComputeCacheAnnotation] == "true":
+		mount = m.getHostPathMount(spec, destination)
+	case m.size != "":
+		mount = m.getTmpfsMount(spec, destination)
+	}
+	if mount == nil {
+		return nil
+	}
+	spec.Mounts = append(spec.Mounts, *mount)
+
+	if m.maxSize > 0 && !hasEnvvar(spec.Process.Env, envCUDACacheMaxSize) {
+		spec.Process.Env = append(spec.Process.Env, fmt.Sprintf("%s=%d", envCUDACacheMaxSize, m.maxSize))
+	}
+	return nil
+}
+
+// getTmpfsMount returns a tmpfs mount of the configured size that is owned by
+// the container user.
+func (m *computeCacheMounter) getTmpfsMount(spec *specs.Spec, destination string) *specs.Mount {
+	m.logger.Debugf("Mounting tmpfs of size %v at %v", m.size, destination)
+	return &specs.Mount{
+		Destination: destination,
+		Type:        "tmpfs",
+		Source:      "tmpfs",
+		Options: []string{
+			"nosuid",
+			"nodev",
+			"mode=0700",
+			"size=" + m.size,
+			fmt.Sprintf("uid=%d", spec.Process.User.UID),
+			fmt.Sprintf("gid=%d", spec.Process.User.GID),
+		},
+	}
+}
+
+// getHostPathMount returns a bind mount of the compute cache directory of the
+// container user in the configured host path. The directory is named after
+// the host UID of the container user so that containers in different user
+// namespaces do not share a cache unless they map to the same host user. For
+// containers in Kubernetes pods, the directory is additionally placed in a
+// per-namespace directory so that the cache is not shared between tenants.
+// The directory is created with the ownership of the container user if it
+// does not exist. Nil is returned if the directory cannot be created.
+func (m *computeCacheMounter) getHostPathMount(spec *specs.Spec, destination string) *specs.Mount {
+	var uidMappings, gidMappings []specs.LinuxIDMapping
+	if spec.Linux != nil {
+		uidMappings = spec.Linux.UIDMappings
+		gidMappings = spec.Linux.GIDMappings
+	}
+	uid := getHostID(spec.Process.User.UID, uidMappings)
+	gid := getHostID(spec.Process.User.GID, gidMappings)
+
+	root := m.hostPath
+	if namespace := getPodNamespace(spec); namespace != "" {
+		if filepath.Base(namespace) != namespace || namespace == "." || namespace == ".." {
+			m.logger.Warningf("Skipping compute cache mount: invalid namespace %q", namespace)
+			return nil
+		}
+		root = filepath.Join(m.hostPath, "namespaces", namespace)
+	}
+
+	source := filepath.Join(root, strconv.FormatUint(uint64(uid), 10))
+	if err := createUserDir(source, uid, gid); err != nil {
+		m.logger.Warningf("Skipping compute cache mount: %v", err)
+		return nil
+	}
+
+	m.logger.Debugf("Mounting %v at %v", source, destination)
+	return &specs.Mount{
+		Destination: destination,
+		Type:        "bind",
+		Source:      source,
+		Options:     []string{"rw", "nosuid", "nodev", "rbind"},
+	}
+}
+
+// getPodNamespace returns the Kubernetes namespace of the pod that the
+// container belongs to. An empty string is returned for containers that are
+// not part of a pod.
+func getPodNamespace(spec *specs.Spec) string {
+	for _, annotation := range podNamespaceAnnotations {
+		if namespace := spec.Annotations[annotation]; namespace != "" {
+			return namespace
+		}
+	}
+	return ""
+}
+
+// createUserDir creates the specified directory with the specified owner if
+// it does not already exist. The parent directory is created if required.
+func createUserDir(path string, uid uint32, gid uint32) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create parent of %v: %w", path, err)
+	}
+	err := os.Mkdir(path, 0700)
+	if errors.Is(err, os.ErrExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create %v: %w", path, err)
+	}
+	if err := os.Chown(path, int(uid), int(gid)); err != nil {
+		return fmt.Errorf("failed to set owner of %v: %w", path, err)
+	}
+	return nil
+}
+
+// getHostID returns the host ID that the specified container ID is mapped to.
+// If no mappings are specified, the container ID is returned.
+func getHostID(id uint32, mappings []specs.LinuxIDMapping) uint32 {
+	for _, mapping := range mappings {
+		if id >= mapping.ContainerID && id-mapping.ContainerID < mapping.Size {
+			return mapping.HostID + id - mapping.ContainerID
+		}
+	}
+	return id
+}
+
+// getHomeDir returns the home directory of the container process. This is
+// taken from the HOME environment variable if set. Since the container's
+// passwd database is not consulted, the home directory is only inferred for
+// the root user otherwise.
+func getHomeDir(process *specs.Process) string {
+	for _, env := range process.Env {
+		if home, ok := strings.CutPrefix(env, "HOME="); ok && filepath.IsAbs(home) {
+			return filepath.Clean(home)
+		}
+	}
+	if process.User.UID == 0 {
+		return "/root"
+	}
+	return ""
+}
@@ -0,0 +1,260 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config/image"
+)
+
+func TestComputeCacheMounter(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	testCases := []struct {
+		description    string
+		size           string
+		process        *specs.Process
+		mounts         []specs.Mount
+		expectedMounts []specs.Mount
+	}{
+		{
+			description: "no size configured does not mount",
+			process: &specs.Process{
+				Env: []string{"NVIDIA_VISIBLE_DEVICES=all"},
+			},
+		},
+		{
+			description: "no devices requested does not mount",
+			size:        "256m",
+			process:     &specs.Process{},
+		},
+		{
+			description: "root user uses /root",
+			size:        "256m",
+			process: &specs.Process{
+				Env: []string{"NVIDIA_VISIBLE_DEVICES=all"},
+			},
+			expectedMounts: []specs.Mount{
+				{
+					Destination: "/root/.nv/ComputeCache",
+					Type:        "tmpfs",
+					Source:      "tmpfs",
+					Options:     []string{"nosuid", "nodev", "mode=0700", "size=256m", "uid=0", "gid=0"},
+				},
+			},
+		},
+		{
+			description: "HOME is used for non-root user",
+			size:        "1g",
+			process: &specs.Process{
+				Env:  []string{"NVIDIA_VISIBLE_DEVICES=all", "HOME=/home/user/"},
+				User: specs.User{UID: 1000, GID: 100},
+			},
+			expectedMounts: []specs.Mount{
+				{
+					Destination: "/home/user/.nv/ComputeCache",
+					Type:        "tmpfs",
+					Source:      "tmpfs",
+					Options:     []string{"nosuid", "nodev", "mode=0700", "size=1g", "uid=1000", "gid=100"},
+				},
+			},
+		},
+		{
+			description: "non-root user without HOME does not mount",
+			size:        "256m",
+			process: &specs.Process{
+				Env:  []string{"NVIDIA_VISIBLE_DEVICES=all"},
+				User: specs.User{UID: 1000, GID: 100},
+			},
+		},
+		{
+			description: "existing mount is not replaced",
+			size:        "256m",
+			process: &specs.Process{
+				Env: []string{"NVIDIA_VISIBLE_DEVICES=all"},
+			},
+			mounts: []specs.Mount{
+				{Destination: "/root/.nv/ComputeCache/", Source: "/cache", Type: "bind"},
+			},
+			expectedMounts: []specs.Mount{
+				{Destination: "/root/.nv/ComputeCache/", Source: "/cache", Type: "bind"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			spec := &specs.Spec{
+				Process: tc.process,
+				Mounts:  tc.mounts,
+			}
+			cfg := &config.Config{}
+			cfg.NVIDIAContainerRuntimeConfig.ComputeCacheTmpfsSize = tc.size
+
+			image, err := image.NewCUDAImageFromSpec(spec)
+			require.NoError(t, err)
+
+			m := NewComputeCacheMounter(logger, cfg, image)
+			if m != nil {
+				require.NoError(t, m.Modify(spec))
+			}
+			require.EqualValues(t, tc.expectedMounts, spec.Mounts)
+		})
+	}
+}
+
+func TestComputeCacheMounterHostPath(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("setting the owner of the compute cache directory requires root")
+	}
+	logger, _ := testlog.NewNullLogger()
+
+	testCases := []struct {
+		description    string
+		maxSize        string
+		annotations    map[string]string
+		process        *specs.Process
+		linux          *specs.Linux
+		expectedSubdir string
+		expectedEnv    []string
+	}{
+		{
+			description: "root user uses subdirectory 0",
+			annotations: map[string]string{PersistentComputeCacheAnnotation: "true"},
+			process: &specs.Process{
+				Env: []string{"NVIDIA_VISIBLE_DEVICES=all"},
+			},
+			expectedSubdir: "0",
+			expectedEnv:    []string{"NVIDIA_VISIBLE_DEVICES=all"},
+		},
+		{
+			description: "mapped user uses host UID",
+			annotations: map[string]string{PersistentComputeCacheAnnotation: "true"},
+			process: &specs.Process{
+				Env:  []string{"NVIDIA_VISIBLE_DEVICES=all", "HOME=/home/user"},
+				User: specs.User{UID: 1000, GID: 100},
+			},
+			linux: &specs.Linux{
+				UIDMappings: []specs.LinuxIDMapping{{ContainerID: 0, HostID: 100000, Size: 65536}},
+				GIDMappings: []specs.LinuxIDMapping{{ContainerID: 0, HostID: 100000, Size: 65536}},
+			},
+			expectedSubdir: "101000",
+			expectedEnv:    []string{"NVIDIA_VISIBLE_DEVICES=all", "HOME=/home/user"},
+		},
+		{
+			description: "max size sets envvar",
+			maxSize:     "1g",
+			annotations: map[string]string{PersistentComputeCacheAnnotation: "true"},
+			process: &specs.Process{
+				Env: []string{"NVIDIA_VISIBLE_DEVICES=all"},
+			},
+			expectedSubdir: "0",
+			expectedEnv:    []string{"NVIDIA_VISIBLE_DEVICES=all", "CUDA_CACHE_MAXSIZE=1073741824"},
+		},
+		{
+			description: "max size does not override envvar",
+			maxSize:     "1g",
+			annotations: map[string]string{PersistentComputeCacheAnnotation: "true"},
+			process: &specs.Process{
+				Env: []string{"NVIDIA_VISIBLE_DEVICES=all", "CUDA_CACHE_MAXSIZE=1024"},
+			},
+			expectedSubdir: "0",
+			expectedEnv:    []string{"NVIDIA_VISIBLE_DEVICES=all", "CUDA_CACHE_MAXSIZE=1024"},
+		},
+		{
+			description: "invalid max size is ignored",
+			maxSize:     "lots",
+			annotations: map[string]string{PersistentComputeCacheAnnotation: "true"},
+			process: &specs.Process{
+				Env: []string{"NVIDIA_VISIBLE_DEVICES=all"},
+			},
+			expectedSubdir: "0",
+			expectedEnv:    []string{"NVIDIA_VISIBLE_DEVICES=all"},
+		},
+		{
+			description: "pod namespace is used as tenant directory",
+			annotations: map[string]string{
+				PersistentComputeCacheAnnotation: "true",
+				"io.kubernetes.pod.namespace":    "team-a",
+			},
+			process: &specs.Process{
+				Env: []string{"NVIDIA_VISIBLE_DEVICES=all"},
+			},
+			expectedSubdir: "namespaces/team-a/0",
+			expectedEnv:    []string{"NVIDIA_VISIBLE_DEVICES=all"},
+		},
+		{
+			description: "container without annotation uses tmpfs",
+			process: &specs.Process{
+				Env: []string{"NVIDIA_VISIBLE_DEVICES=all"},
+			},
+			expectedEnv: []string{"NVIDIA_VISIBLE_DEVICES=all"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			hostPath := filepath.Join(t.TempDir(), "cache")
+			spec := &specs.Spec{
+				Process:     tc.process,
+				Linux:       tc.linux,
+				Annotations: tc.annotations,
+			}
+			cfg := &config.Config{}
+			cfg.NVIDIAContainerRuntimeConfig.ComputeCacheTmpfsSize = "256m"
+			cfg.NVIDIAContainerRuntimeConfig.ComputeCacheHostPath = hostPath
+			cfg.NVIDIAContainerRuntimeConfig.ComputeCacheMaxSize = tc.maxSize
+
+			image, err := image.NewCUDAImageFromSpec(spec)
+			require.NoError(t, err)
+
+			m := NewComputeCacheMounter(logger, cfg, image)
+			require.NotNil(t, m)
+
+			err = m.Modify(spec)
+			require.NoError(t, err)
+			require.EqualValues(t, tc.expectedEnv, spec.Process.Env)
+			if tc.expectedSubdir == "" {
+				require.Len(t, spec.Mounts, 1)
+				require.Equal(t, "tmpfs", spec.Mounts[0].Type)
+				return
+			}
+
+			source := filepath.Join(hostPath, tc.expectedSubdir)
+			require.EqualValues(t, []specs.Mount{
+				{
+					Destination: filepath.Join(getHomeDir(tc.process), ".nv/ComputeCache"),
+					Type:        "bind",
+					Source:      source,
+					Options:     []string{"rw", "nosuid", "nodev", "rbind"},
+				},
+			}, spec.Mounts)
+
+			info, err := os.Stat(source)
+			require.NoError(t, err)
+			require.True(t, info.IsDir())
+		})
+	}
+}
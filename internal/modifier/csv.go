@@ -18,32 +18,96 @@ package modifier
 
 import (
 	"fmt"
+	"strings"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
 
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/config/image"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/cuda"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/modifier/cdi"
-	"github.com/NVIDIA/nvidia-container-toolkit/internal/oci"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/platform-support/tegra/csv"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/platform-support/tegra/nvpmodel"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/requirements"
 	"github.com/NVIDIA/nvidia-container-toolkit/pkg/nvcdi"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/oci"
 )
 
+// igpuDeviceName is the device name used to request the iGPU on hybrid systems.
+const igpuDeviceName = "igpu"
+
 // NewCSVModifier creates a modifier that applies modications to an OCI spec if required by the runtime wrapper.
 // The modifications are defined by CSV MountSpecs.
 func NewCSVModifier(logger logger.Interface, cfg *config.Config, container image.CUDA) (oci.SpecModifier, error) {
-	if devices := container.VisibleDevices(); len(devices) == 0 {
+	devices := container.VisibleDevices()
+	if len(devices) == 0 {
 		logger.Infof("No modification required; no devices requested")
 		return nil, nil
 	}
 	logger.Infof("Constructing modifier from config: %+v", *cfg)
 
-	if err := checkRequirements(logger, container); err != nil {
+	if cfg.NVIDIAContainerRuntimeConfig.Modes.CSV.Hybrid {
+		return newHybridModifier(logger, cfg, container, devices)
+	}
+	return newCSVModifier(logger, cfg, container)
+}
+
+// newHybridModifier creates a modifier for systems with both an iGPU and
+// dGPUs. The iGPU is injected based on the CSV mount specs, whereas dGPUs
+// are injected using an in-memory CDI spec generated using NVML.
+func newHybridModifier(logger logger.Interface, cfg *config.Config, container image.CUDA, devices []string) (oci.SpecModifier, error) {
+	includeIGPU, dGPUs := splitHybridDeviceRequests(devices)
+
+	var modifiers List
+	if includeIGPU {
+		csvModifier, err := newCSVModifier(logger, cfg, container)
+		if err != nil {
+			return nil, err
+		}
+		modifiers = append(modifiers, csvModifier)
+	}
+	if len(dGPUs) > 0 {
+		var automaticDevices []string
+		for _, dGPU := range dGPUs {
+			automaticDevices = append(automaticDevices, automaticDevicePrefix+dGPU)
+		}
+		dGPUModifier, err := newAutomaticCDISpecModifier(logger, cfg, automaticDevices, nvcdi.WithMode(nvcdi.ModeNvml))
+		if err != nil {
+			return nil, fmt.Errorf("failed to construct modifier for dGPUs: %w", err)
+		}
+		modifiers = append(modifiers, dGPUModifier)
+	}
+	return modifiers, nil
+}
+
+// splitHybridDeviceRequests splits the requested devices into a request for
+// the iGPU and requests for dGPUs. Requesting "all" devices includes both the
+// iGPU and all dGPUs.
+func splitHybridDeviceRequests(devices []string) (bool, []string) {
+	var includeIGPU bool
+	var dGPUs []string
+	for _, device := range devices {
+		switch device {
+		case igpuDeviceName:
+			includeIGPU = true
+		case "all":
+			includeIGPU = true
+			dGPUs = append(dGPUs, device)
+		default:
+			dGPUs = append(dGPUs, device)
+		}
+	}
+	return includeIGPU, dGPUs
+}
+
+// newCSVModifier creates a modifier for the iGPU based on the CSV mount specs.
+func newCSVModifier(logger logger.Interface, cfg *config.Config, container image.CUDA) (oci.SpecModifier, error) {
+	if err := checkRequirements(logger, container, cfg.NVIDIAContainerRuntimeConfig.ResourceConstrained); err != nil {
 		return nil, fmt.Errorf("requirements not met: %v", err)
 	}
 
-	csvFiles, err := csv.GetFileList(cfg.NVIDIAContainerRuntimeConfig.Modes.CSV.MountSpecPath)
+	csvFiles, err := csv.GetMountSpecFileList(logger, cfg.NVIDIAContainerRuntimeConfig.Modes.CSV.MountSpecPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get list of CSV files: %v", err)
 	}
@@ -52,13 +116,23 @@ func NewCSVModifier(logger logger.Interface, cfg *config.Config, container image
 		csvFiles = csv.BaseFilesOnly(csvFiles)
 	}
 
-	cdilib, err := nvcdi.New(
+	cdilibOptions := []nvcdi.Option{
 		nvcdi.WithLogger(logger),
 		nvcdi.WithDriverRoot(cfg.NVIDIAContainerCLIConfig.Root),
 		nvcdi.WithNVIDIACDIHookPath(cfg.NVIDIACTKConfig.Path),
 		nvcdi.WithMode(nvcdi.ModeCSV),
 		nvcdi.WithCSVFiles(csvFiles),
-	)
+		nvcdi.WithFeatureFlags(nvcdiFeatureFlags(cfg)...),
+	}
+	cdilibOptions = append(cdilibOptions, nvcdiHookPathOptions(cfg)...)
+	// We only filter the CSV entries by driver capability if these are
+	// explicitly requested to ensure that existing behaviour is maintained.
+	if container.HasEnvvar(image.EnvVarNvidiaDriverCapabilities) {
+		cdilibOptions = append(cdilibOptions, nvcdi.WithDriverCapabilities(container.GetDriverCapabilities().String()))
+		cdilibOptions = append(cdilibOptions, withLibraryFilter(cfg)...)
+	}
+
+	cdilib, err := nvcdi.New(cdilibOptions...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to construct CDI library: %v", err)
 	}
@@ -74,7 +148,14 @@ func NewCSVModifier(logger logger.Interface, cfg *config.Config, container image
 	)
 }
 
-func checkRequirements(logger logger.Interface, image image.CUDA) error {
+// checkRequirements checks the requirements of the specified image against the
+// properties of the host. On resource-constrained systems, the CUDA driver and
+// NVML are not initialized and the requirements that refer to the CUDA
+// version, compute capability, or device memory are not checked.
+func checkRequirements(logger logger.Interface, image image.CUDA, resourceConstrained bool) error {
+	if err := image.CheckRequirementConflicts(); err != nil {
+		return err
+	}
 	if image.HasDisableRequire() {
 		// TODO: We could print the real value here instead
 		logger.Debugf("NVIDIA_DISABLE_REQUIRE=%v; skipping requirement checks", true)
@@ -89,6 +170,32 @@ func checkRequirements(logger logger.Interface, image image.CUDA) error {
 
 	r := requirements.New(logger, imageRequirements)
 
+	if resourceConstrained {
+		logger.Debugf("Skipping CUDA and device memory requirement checks on resource-constrained system")
+	} else {
+		addDriverProperties(logger, r, image, imageRequirements)
+	}
+
+	if requiresProperty(imageRequirements, requirements.NVPMODEL) {
+		powerMode, err := nvpmodel.GetPowerMode("/")
+		if err != nil {
+			logger.Warningf("Failed to get nvpmodel power mode: %v", err)
+		} else {
+			r.AddStringProperty(requirements.NVPMODEL, powerMode.Name)
+		}
+	}
+
+	report, err := r.Assert()
+	if err != nil {
+		return err
+	}
+	logger.Debugf("Requirements %v satisfied:\n%v", imageRequirements, report)
+	return nil
+}
+
+// addDriverProperties adds the properties that require the CUDA driver or
+// NVML to be initialized to the specified requirements.
+func addDriverProperties(logger logger.Interface, r *requirements.Requirements, image image.CUDA, imageRequirements []string) {
 	cudaVersion, err := cuda.Version()
 	if err != nil {
 		logger.Warningf("Failed to get CUDA version: %v", err)
@@ -103,5 +210,25 @@ func checkRequirements(logger logger.Interface, image image.CUDA) error {
 		r.AddVersionProperty(requirements.ARCH, compteCapability)
 	}
 
-	return r.Assert()
+	if requiresProperty(imageRequirements, requirements.MEMORY) {
+		memory, err := getRequestedDeviceMemory(nvml.New(), image.VisibleDevices())
+		if err != nil {
+			logger.Warningf("Failed to get device memory: %v", err)
+		} else {
+			r.AddSizeProperty(requirements.MEMORY, memory)
+		}
+	}
+}
+
+// requiresProperty checks whether any of the specified requirements refer to
+// the named property.
+func requiresProperty(imageRequirements []string, property string) bool {
+	for _, requirement := range imageRequirements {
+		for _, term := range strings.FieldsFunc(requirement, func(c rune) bool { return c == ' ' || c == ',' }) {
+			if strings.HasPrefix(strings.TrimSpace(term), property) {
+				return true
+			}
+		}
+	}
+	return false
 }
@@ -73,3 +73,43 @@ func TestNewCSVModifier(t *testing.T) {
 		})
 	}
 }
+
+func TestSplitHybridDeviceRequests(t *testing.T) {
+	testCases := []struct {
+		description         string
+		devices             []string
+		expectedIncludeIGPU bool
+		expectedDGPUs       []string
+	}{
+		{
+			description:         "igpu only",
+			devices:             []string{"igpu"},
+			expectedIncludeIGPU: true,
+		},
+		{
+			description:   "dgpus only",
+			devices:       []string{"0", "GPU-0c4b7c3b"},
+			expectedDGPUs: []string{"0", "GPU-0c4b7c3b"},
+		},
+		{
+			description:         "igpu and dgpu",
+			devices:             []string{"igpu", "0"},
+			expectedIncludeIGPU: true,
+			expectedDGPUs:       []string{"0"},
+		},
+		{
+			description:         "all includes igpu and dgpus",
+			devices:             []string{"all"},
+			expectedIncludeIGPU: true,
+			expectedDGPUs:       []string{"all"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			includeIGPU, dGPUs := splitHybridDeviceRequests(tc.devices)
+			require.Equal(t, tc.expectedIncludeIGPU, includeIGPU)
+			require.EqualValues(t, tc.expectedDGPUs, dGPUs)
+		})
+	}
+}
@@ -0,0 +1,76 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"strings"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/oci"
+)
+
+// InjectedDevicesAnnotation is the OCI annotation that lists the GPUs that
+// were injected into a container.
+const InjectedDevicesAnnotation = "com.nvidia.devices.injected"
+
+// injectedDevicesAnnotator records the GPUs that are injected into a container
+// as an annotation in the OCI specification.
+type injectedDevicesAnnotator struct {
+	logger   logger.Interface
+	hostRoot string
+}
+
+var _ oci.SpecModifier = (*injectedDevicesAnnotator)(nil)
+
+// NewInjectedDevicesAnnotator creates a modifier that sets the
+// com.nvidia.devices.injected annotation for containers that GPUs are
+// injected into. Since the annotations are included in the state of a
+// container, this allows monitoring agents and admission auditors to map
+// running containers to GPUs.
+// A nil modifier is returned if the feature is not enabled.
+func NewInjectedDevicesAnnotator(logger logger.Interface, cfg *config.Config) oci.SpecModifier {
+	if !cfg.Features.AnnotateInjectedDevices.IsEnabled() {
+		return nil
+	}
+	return &injectedDevicesAnnotator{
+		logger:   logger,
+		hostRoot: "/",
+	}
+}
+
+// Modify sets the annotation to the comma-separated list of injected GPUs.
+// The GPUs are listed by UUID where this is known and by device minor number
+// otherwise. Containers without GPU device nodes are not modified.
+func (m *injectedDevicesAnnotator) Modify(spec *specs.Spec) error {
+	if spec == nil || spec.Linux == nil {
+		return nil
+	}
+
+	devices := getInjectedDevices(m.logger, m.hostRoot, spec.Linux.Devices)
+	if len(devices) == 0 {
+		return nil
+	}
+
+	if spec.Annotations == nil {
+		spec.Annotations = make(map[string]string)
+	}
+	spec.Annotations[InjectedDevicesAnnotation] = strings.Join(devices, ",")
+	return nil
+}
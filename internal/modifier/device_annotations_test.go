@@ -0,0 +1,93 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInjectedDevicesAnnotator(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	hostRoot := t.TempDir()
+	dir := filepath.Join(hostRoot, "proc/driver/nvidia/gpus", "0000:05:00.0")
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "information"), []byte("GPU UUID:        GPU-0\nBus Location:    0000:05:00.0\nDevice Minor:    0\n"), 0644))
+
+	testCases := []struct {
+		description         string
+		devices             []specs.LinuxDevice
+		annotations         map[string]string
+		expectedAnnotations map[string]string
+	}{
+		{
+			description: "no gpus sets no annotation",
+			devices: []specs.LinuxDevice{
+				{Path: "/dev/nvidiactl"},
+			},
+		},
+		{
+			description: "gpus are listed by uuid or minor",
+			devices: []specs.LinuxDevice{
+				{Path: "/dev/nvidiactl"},
+				{Path: "/dev/nvidia0"},
+				{Path: "/dev/nvidia1"},
+			},
+			expectedAnnotations: map[string]string{
+				"com.nvidia.devices.injected": "GPU-0,1",
+			},
+		},
+		{
+			description: "existing annotation is replaced",
+			devices: []specs.LinuxDevice{
+				{Path: "/dev/nvidia0"},
+			},
+			annotations: map[string]string{
+				"com.nvidia.devices.injected": "all",
+				"other":                       "value",
+			},
+			expectedAnnotations: map[string]string{
+				"com.nvidia.devices.injected": "GPU-0",
+				"other":                       "value",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			m := &injectedDevicesAnnotator{
+				logger:   logger,
+				hostRoot: hostRoot,
+			}
+
+			spec := &specs.Spec{
+				Annotations: tc.annotations,
+				Linux: &specs.Linux{
+					Devices: tc.devices,
+				},
+			}
+			require.NoError(t, m.Modify(spec))
+			require.EqualValues(t, tc.expectedAnnotations, spec.Annotations)
+		})
+	}
+}
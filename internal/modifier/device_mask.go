@@ -0,0 +1,230 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/opencontainers/runtime-spec/specs-go"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config/image"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/info/proc"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/nvcaps"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/oci"
+)
+
+// gpuDeviceNodeMasker masks the device nodes of GPUs that were not requested
+// in containers that have access to all host devices.
+type gpuDeviceNodeMasker struct {
+	logger   logger.Interface
+	nvmllib  nvml.Interface
+	hostRoot string
+	// requested are the devices requested by the container.
+	requested []string
+}
+
+var _ oci.SpecModifier = (*gpuDeviceNodeMasker)(nil)
+
+// NewGPUDeviceNodeMasker creates a modifier that adds the /dev/nvidiaN,
+// /dev/nvidia-caps, and /dev/dri device nodes of GPUs that were not requested
+// to the masked paths of containers that have access to all host devices. This is the case for privileged
+// containers, containers where the devices cgroup allows all devices, or
+// containers that mount the host /dev. The masked device nodes are replaced
+// by /dev/null in the container.
+// A nil modifier is returned if the feature is not enabled.
+func NewGPUDeviceNodeMasker(logger logger.Interface, cfg *config.Config, container image.CUDA) oci.SpecModifier {
+	if !cfg.Features.MaskUnrequestedGPUDeviceNodes.IsEnabled() {
+		return nil
+	}
+	return &gpuDeviceNodeMasker{
+		logger:    logger,
+		nvmllib:   nvml.New(),
+		hostRoot:  "/",
+		requested: container.VisibleDevices(),
+	}
+}
+
+// Modify masks the device nodes of the GPUs that were not requested. Containers
+// that do not request devices, request all devices, or only have access to the
+// devices in the spec are not modified.
+func (m *gpuDeviceNodeMasker) Modify(spec *specs.Spec) error {
+	if spec == nil || spec.Linux == nil {
+		return nil
+	}
+	if len(m.requested) == 0 || slices.Contains(m.requested, "void") || slices.Contains(m.requested, "all") {
+		return nil
+	}
+	if !hasAccessToAllHostDevices(spec) {
+		return nil
+	}
+
+	hostGPUs := getHostGPUs(m.logger, m.hostRoot)
+	requestedMinors, err := getRequestedGPUMinors(hostGPUs, m.requested, m.getMIGDeviceParentMinor)
+	if err != nil {
+		m.logger.Warningf("Not masking GPU device nodes: %v", err)
+		return nil
+	}
+
+	migCaps, err := nvcaps.NewMigCapsFromRoot(m.hostRoot)
+	if err != nil {
+		m.logger.Warningf("Not masking MIG capability device nodes: %v", err)
+	}
+
+	for _, gpu := range hostGPUs {
+		if requestedMinors[gpu.minor] {
+			continue
+		}
+		for _, path := range m.getGPUDeviceNodes(gpu, migCaps) {
+			if slices.Contains(spec.Linux.MaskedPaths, path) {
+				continue
+			}
+			m.logger.Debugf("Masking %v", path)
+			spec.Linux.MaskedPaths = append(spec.Linux.MaskedPaths, path)
+		}
+	}
+	return nil
+}
+
+// getGPUDeviceNodes returns the device nodes associated with the specified
+// host GPU. These include the /dev/nvidiaN device node, the MIG capability
+// device nodes of the GPU, and its DRM device nodes and their by-path links.
+func (m *gpuDeviceNodeMasker) getGPUDeviceNodes(gpu hostGPU, migCaps nvcaps.MigCaps) []string {
+	paths := []string{fmt.Sprintf("/dev/nvidia%d", gpu.minor)}
+
+	var capPaths []string
+	for cap, minor := range migCaps {
+		if gpuMinor, ok := cap.GPUMinor(); ok && gpuMinor == gpu.minor {
+			capPaths = append(capPaths, minor.DevicePath())
+		}
+	}
+	slices.Sort(capPaths)
+	paths = append(paths, capPaths...)
+
+	busID := strings.ToLower(gpu.info[proc.GPUInfoBusLocation])
+	if busID == "" {
+		return paths
+	}
+	for _, suffix := range []string{"card", "render"} {
+		byPathLink := fmt.Sprintf("/dev/dri/by-path/pci-%s-%s", busID, suffix)
+		target, err := os.Readlink(filepath.Join(m.hostRoot, byPathLink))
+		if err != nil {
+			continue
+		}
+		paths = append(paths, filepath.Join("/dev/dri", filepath.Base(target)), byPathLink)
+	}
+	return paths
+}
+
+// getMIGDeviceParentMinor returns the minor number of the parent GPU of the
+// MIG device with the specified UUID as reported by NVML.
+func (m *gpuDeviceNodeMasker) getMIGDeviceParentMinor(uuid string) (int, error) {
+	if ret := m.nvmllib.Init(); ret != nvml.SUCCESS {
+		return 0, fmt.Errorf("failed to initialize NVML: %v", ret)
+	}
+	defer func() {
+		_ = m.nvmllib.Shutdown()
+	}()
+
+	device, ret := m.nvmllib.DeviceGetHandleByUUID(uuid)
+	if ret != nvml.SUCCESS {
+		return 0, fmt.Errorf("failed to get device handle: %v", ret)
+	}
+	parent, ret := device.GetDeviceHandleFromMigDeviceHandle()
+	if ret != nvml.SUCCESS {
+		return 0, fmt.Errorf("failed to get parent device handle: %v", ret)
+	}
+	minor, ret := parent.GetMinorNumber()
+	if ret != nvml.SUCCESS {
+		return 0, fmt.Errorf("failed to get minor number: %v", ret)
+	}
+	return minor, nil
+}
+
+// hasAccessToAllHostDevices checks whether the container has access to the
+// device nodes of the host irrespective of the device nodes in the spec.
+func hasAccessToAllHostDevices(spec *specs.Spec) bool {
+	for _, mount := range spec.Mounts {
+		if mount.Destination == "/dev" && mount.Source == "/dev" {
+			return true
+		}
+	}
+	if spec.Linux.Resources == nil {
+		return false
+	}
+	for _, rule := range spec.Linux.Resources.Devices {
+		if rule.Allow && (rule.Type == "" || rule.Type == "a") && rule.Major == nil && rule.Minor == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// getRequestedGPUMinors returns the device minors of the host GPUs that
+// correspond to the requested devices. Devices are requested by index, UUID,
+// or PCI bus ID, optionally as fully-qualified CDI device names. A MIG device
+// requested as GPU:MIG or by its MIG- UUID selects its parent GPU. MIG- UUIDs
+// are resolved using the specified function. An error is returned if a
+// requested device cannot be resolved to a host GPU.
+func getRequestedGPUMinors(hostGPUs []hostGPU, requested []string, getMIGDeviceParentMinor func(string) (int, error)) (map[int]bool, error) {
+	minors := make(map[int]bool)
+	for _, device := range requested {
+		if device == image.NoneDeviceName {
+			continue
+		}
+		if _, name, ok := strings.Cut(device, "="); ok {
+			device = name
+		}
+
+		index := -1
+		switch {
+		case strings.HasPrefix(device, "MIG-"):
+			minor, err := getMIGDeviceParentMinor(device)
+			if err != nil {
+				return nil, fmt.Errorf("could not resolve MIG device %q: %w", device, err)
+			}
+			index = slices.IndexFunc(hostGPUs, func(gpu hostGPU) bool {
+				return gpu.minor == minor
+			})
+		case proc.IsPCIBusID(device):
+			index = slices.IndexFunc(hostGPUs, func(gpu hostGPU) bool {
+				return proc.NormalizePCIBusID(gpu.info[proc.GPUInfoBusLocation]) == proc.NormalizePCIBusID(device)
+			})
+		case strings.HasPrefix(device, "GPU-"):
+			index = slices.IndexFunc(hostGPUs, func(gpu hostGPU) bool {
+				return gpu.info[proc.GPUInfoGPUUUID] == device
+			})
+		default:
+			gpuIndex, _, _ := strings.Cut(device, ":")
+			if i, err := strconv.Atoi(gpuIndex); err == nil && i >= 0 && i < len(hostGPUs) {
+				index = i
+			}
+		}
+		if index == -1 {
+			return nil, fmt.Errorf("could not resolve device %q to a GPU", device)
+		}
+		minors[hostGPUs[index].minor] = true
+	}
+	return minors, nil
+}
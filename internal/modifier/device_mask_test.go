@@ -0,0 +1,168 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/NVIDIA/go-nvml/pkg/nvml/mock"
+	"github.com/opencontainers/runtime-spec/specs-go"
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGPUDeviceNodeMasker(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	hostRoot := t.TempDir()
+	for busID, information := range map[string]string{
+		"0000:02:00.0": "GPU UUID:        GPU-2\nBus Location:    0000:02:00.0\nDevice Minor:    2\n",
+		"0000:05:00.0": "GPU UUID:        GPU-0\nBus Location:    0000:05:00.0\nDevice Minor:    0\n",
+		"0000:81:00.0": "GPU UUID:        GPU-1\nBus Location:    0000:81:00.0\nDevice Minor:    1\n",
+	} {
+		dir := filepath.Join(hostRoot, "proc/driver/nvidia/gpus", busID)
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "information"), []byte(information), 0644))
+	}
+	migMinors := filepath.Join(hostRoot, "proc/driver/nvidia-caps/mig-minors")
+	require.NoError(t, os.MkdirAll(filepath.Dir(migMinors), 0755))
+	require.NoError(t, os.WriteFile(migMinors, []byte("config 1\ngpu0/gi2/access 21\ngpu1/gi1/access 12\ngpu1/gi1/ci0/access 13\n"), 0644))
+	byPath := filepath.Join(hostRoot, "dev/dri/by-path")
+	require.NoError(t, os.MkdirAll(byPath, 0755))
+	require.NoError(t, os.Symlink("../card1", filepath.Join(byPath, "pci-0000:81:00.0-card")))
+	require.NoError(t, os.Symlink("../renderD129", filepath.Join(byPath, "pci-0000:81:00.0-render")))
+
+	// The MIG device with UUID MIG-1 is a MIG device of the GPU with minor 1.
+	nvmllib := &mock.Interface{
+		InitFunc: func() nvml.Return {
+			return nvml.SUCCESS
+		},
+		ShutdownFunc: func() nvml.Return {
+			return nvml.SUCCESS
+		},
+		DeviceGetHandleByUUIDFunc: func(uuid string) (nvml.Device, nvml.Return) {
+			if uuid != "MIG-1" {
+				return nil, nvml.ERROR_NOT_FOUND
+			}
+			return &mock.Device{
+				GetDeviceHandleFromMigDeviceHandleFunc: func() (nvml.Device, nvml.Return) {
+					return &mock.Device{
+						GetMinorNumberFunc: func() (int, nvml.Return) {
+							return 1, nvml.SUCCESS
+						},
+					}, nvml.SUCCESS
+				},
+			}, nvml.SUCCESS
+		},
+	}
+	gpu0DeviceNodes := []string{"/dev/nvidia0", "/dev/nvidia-caps/nvidia-cap21"}
+	gpu1DeviceNodes := []string{
+		"/dev/nvidia1",
+		"/dev/nvidia-caps/nvidia-cap12",
+		"/dev/nvidia-caps/nvidia-cap13",
+		"/dev/dri/card1",
+		"/dev/dri/by-path/pci-0000:81:00.0-card",
+		"/dev/dri/renderD129",
+		"/dev/dri/by-path/pci-0000:81:00.0-render",
+	}
+
+	allowAllDevices := &specs.LinuxResources{
+		Devices: []specs.LinuxDeviceCgroup{{Allow: true, Access: "rwm"}},
+	}
+
+	testCases := []struct {
+		description         string
+		requested           []string
+		spec                *specs.Spec
+		expectedMaskedPaths []string
+	}{
+		{
+			description: "containers without access to all devices are not modified",
+			requested:   []string{"0"},
+			spec: &specs.Spec{
+				Linux: &specs.Linux{
+					Resources: &specs.LinuxResources{
+						Devices: []specs.LinuxDeviceCgroup{{Allow: false, Access: "rwm"}},
+					},
+				},
+			},
+		},
+		{
+			description: "request for all devices is not modified",
+			requested:   []string{"all"},
+			spec:        &specs.Spec{Linux: &specs.Linux{Resources: allowAllDevices}},
+		},
+		{
+			description: "container without requests is not modified",
+			spec:        &specs.Spec{Linux: &specs.Linux{Resources: allowAllDevices}},
+		},
+		{
+			description:         "device requested by index",
+			requested:           []string{"0"},
+			spec:                &specs.Spec{Linux: &specs.Linux{Resources: allowAllDevices}},
+			expectedMaskedPaths: append(append([]string{}, gpu0DeviceNodes...), gpu1DeviceNodes...),
+		},
+		{
+			description: "host /dev is mounted",
+			requested:   []string{"GPU-1", "0000:02:00.0"},
+			spec: &specs.Spec{
+				Mounts: []specs.Mount{{Destination: "/dev", Source: "/dev", Type: "bind"}},
+				Linux:  &specs.Linux{},
+			},
+			expectedMaskedPaths: gpu0DeviceNodes,
+		},
+		{
+			description:         "CDI device names and MIG devices",
+			requested:           []string{"nvidia.com/gpu=GPU-0", "nvidia.com/gpu=2:0"},
+			spec:                &specs.Spec{Linux: &specs.Linux{Resources: allowAllDevices}},
+			expectedMaskedPaths: []string{"/dev/nvidia2"},
+		},
+		{
+			description:         "MIG device requested by UUID selects its parent GPU",
+			requested:           []string{"nvidia.com/gpu=MIG-1"},
+			spec:                &specs.Spec{Linux: &specs.Linux{Resources: allowAllDevices}},
+			expectedMaskedPaths: append([]string{"/dev/nvidia2"}, gpu0DeviceNodes...),
+		},
+		{
+			description:         "none masks all GPUs",
+			requested:           []string{"none"},
+			spec:                &specs.Spec{Linux: &specs.Linux{MaskedPaths: []string{"/dev/nvidia1"}, Resources: allowAllDevices}},
+			expectedMaskedPaths: append(append([]string{"/dev/nvidia1", "/dev/nvidia2"}, gpu0DeviceNodes...), gpu1DeviceNodes[1:]...),
+		},
+		{
+			description: "unresolved devices disable masking",
+			requested:   []string{"0", "MIG-f2ebd5b0-6b2c-5c1e-9bda-2c2d2f1b7a6e"},
+			spec:        &specs.Spec{Linux: &specs.Linux{Resources: allowAllDevices}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			m := &gpuDeviceNodeMasker{
+				logger:    logger,
+				nvmllib:   nvmllib,
+				hostRoot:  hostRoot,
+				requested: tc.requested,
+			}
+			require.NoError(t, m.Modify(tc.spec))
+			require.EqualValues(t, tc.expectedMaskedPaths, tc.spec.Linux.MaskedPaths)
+		})
+	}
+}
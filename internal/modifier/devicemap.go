@@ -0,0 +1,183 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/info/proc"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/devicemap"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/oci"
+)
+
+var gpuDeviceNodePattern = regexp.MustCompile(`^/dev/nvidia([0-9]+)$`)
+
+// deviceMapWriter writes a device map for the GPUs in the modified spec to the
+// container bundle.
+type deviceMapWriter struct {
+	logger    logger.Interface
+	bundleDir string
+	// hostRoot is the root used to read the GPU information files.
+	hostRoot string
+}
+
+var _ oci.SpecModifier = (*deviceMapWriter)(nil)
+
+// NewDeviceMapWriter creates a modifier that writes a mapping between the
+// GPU ordinals in the container and the GPUs on the host to the specified
+// bundle directory. The spec itself is not modified.
+// A nil modifier is returned if the feature is not enabled.
+func NewDeviceMapWriter(logger logger.Interface, cfg *config.Config, bundleDir string) oci.SpecModifier {
+	if !cfg.Features.WriteDeviceMap.IsEnabled() {
+		return nil
+	}
+	return &deviceMapWriter{
+		logger:    logger,
+		bundleDir: bundleDir,
+		hostRoot:  "/",
+	}
+}
+
+// Modify writes the device map for the GPU device nodes in the spec. Failures
+// to write the device map are logged but do not prevent the container from
+// being created.
+func (m *deviceMapWriter) Modify(spec *specs.Spec) error {
+	if spec == nil || spec.Linux == nil {
+		return nil
+	}
+
+	deviceMap := m.getDeviceMap(spec.Linux.Devices)
+	if len(deviceMap.Devices) == 0 {
+		return nil
+	}
+	if err := deviceMap.Save(m.bundleDir); err != nil {
+		m.logger.Warningf("Failed to write device map: %v", err)
+	}
+	return nil
+}
+
+// getDeviceMap constructs the device map for the specified container devices.
+// The ordinals are assigned in PCI bus ID order to match the default ordering
+// of NVML. If the PCI bus ID is not known for any of the devices, the ordinals
+// of all devices are assigned in device minor number order instead.
+func (m *deviceMapWriter) getDeviceMap(containerDevices []specs.LinuxDevice) *devicemap.DeviceMap {
+	gpuInfoByMinor := m.getGPUInfoByMinor()
+
+	var devices []devicemap.Device
+	for _, minor := range getGPUDeviceMinors(containerDevices) {
+		info := gpuInfoByMinor[minor]
+		devices = append(devices, devicemap.Device{
+			Minor:    minor,
+			UUID:     info[proc.GPUInfoGPUUUID],
+			PCIBusID: info[proc.GPUInfoBusLocation],
+		})
+	}
+
+	byBusID := true
+	for _, device := range devices {
+		if device.PCIBusID == "" {
+			byBusID = false
+			break
+		}
+	}
+	sort.SliceStable(devices, func(i, j int) bool {
+		if byBusID && devices[i].PCIBusID != devices[j].PCIBusID {
+			return devices[i].PCIBusID < devices[j].PCIBusID
+		}
+		return devices[i].Minor < devices[j].Minor
+	})
+	for i := range devices {
+		devices[i].Index = i
+	}
+
+	return &devicemap.DeviceMap{Devices: devices}
+}
+
+// getGPUInfoByMinor returns the GPU information from the information files on
+// the host indexed by the device minor number.
+func (m *deviceMapWriter) getGPUInfoByMinor() map[int]proc.GPUInfo {
+	gpuInfoByMinor := make(map[int]proc.GPUInfo)
+	for _, gpu := range getHostGPUs(m.logger, m.hostRoot) {
+		gpuInfoByMinor[gpu.minor] = gpu.info
+	}
+	return gpuInfoByMinor
+}
+
+// getGPUDeviceMinors returns the unique minor numbers of the /dev/nvidiaN
+// device nodes in the specified devices.
+func getGPUDeviceMinors(devices []specs.LinuxDevice) []int {
+	var minors []int
+	seen := make(map[int]bool)
+	for _, d := range devices {
+		matches := gpuDeviceNodePattern.FindStringSubmatch(d.Path)
+		if len(matches) != 2 {
+			continue
+		}
+		minor, err := strconv.Atoi(matches[1])
+		if err != nil || seen[minor] {
+			continue
+		}
+		seen[minor] = true
+		minors = append(minors, minor)
+	}
+	return minors
+}
+
+// A hostGPU represents a GPU described by a procfs information file.
+type hostGPU struct {
+	// procPath is the /proc/driver/nvidia/gpus/* folder for the GPU relative
+	// to the host root.
+	procPath string
+	minor    int
+	info     proc.GPUInfo
+}
+
+// getHostGPUs returns the GPUs described by the information files under the
+// specified host root.
+func getHostGPUs(logger logger.Interface, hostRoot string) []hostGPU {
+	paths, err := proc.GetInformationFilePaths(hostRoot)
+	if err != nil {
+		logger.Debugf("Failed to get GPU information files: %v", err)
+		return nil
+	}
+
+	var gpus []hostGPU
+	for _, path := range paths {
+		info, err := proc.ParseGPUInformationFile(path)
+		if err != nil {
+			logger.Debugf("Failed to parse %v: %v", path, err)
+			continue
+		}
+		minor, err := strconv.Atoi(info[proc.GPUInfoDeviceMinor])
+		if err != nil {
+			continue
+		}
+		gpus = append(gpus, hostGPU{
+			procPath: filepath.Join("/proc/driver/nvidia/gpus", filepath.Base(filepath.Dir(path))),
+			minor:    minor,
+			info:     info,
+		})
+	}
+	return gpus
+}
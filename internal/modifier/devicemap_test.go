@@ -0,0 +1,124 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/devicemap"
+)
+
+func TestDeviceMapWriter(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	hostRoot := t.TempDir()
+	for busID, information := range map[string]string{
+		"0000:05:00.0": "GPU UUID:        GPU-0\nBus Location:    0000:05:00.0\nDevice Minor:    0\n",
+		"0000:02:00.0": "GPU UUID:        GPU-1\nBus Location:    0000:02:00.0\nDevice Minor:    1\n",
+	} {
+		dir := filepath.Join(hostRoot, "proc/driver/nvidia/gpus", busID)
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "information"), []byte(information), 0644))
+	}
+
+	testCases := []struct {
+		description       string
+		devices           []specs.LinuxDevice
+		expectedDeviceMap *devicemap.DeviceMap
+	}{
+		{
+			description: "no gpus does not write device map",
+			devices: []specs.LinuxDevice{
+				{Path: "/dev/nvidiactl"},
+			},
+		},
+		{
+			description: "gpus are ordered by bus id",
+			devices: []specs.LinuxDevice{
+				{Path: "/dev/nvidiactl"},
+				{Path: "/dev/nvidia0"},
+				{Path: "/dev/nvidia1"},
+			},
+			expectedDeviceMap: &devicemap.DeviceMap{
+				Devices: []devicemap.Device{
+					{Index: 0, Minor: 1, UUID: "GPU-1", PCIBusID: "0000:02:00.0"},
+					{Index: 1, Minor: 0, UUID: "GPU-0", PCIBusID: "0000:05:00.0"},
+				},
+			},
+		},
+		{
+			description: "gpus without information are ordered by minor",
+			devices: []specs.LinuxDevice{
+				{Path: "/dev/nvidia3"},
+				{Path: "/dev/nvidia2"},
+			},
+			expectedDeviceMap: &devicemap.DeviceMap{
+				Devices: []devicemap.Device{
+					{Index: 0, Minor: 2},
+					{Index: 1, Minor: 3},
+				},
+			},
+		},
+		{
+			description: "gpus are ordered by minor if any bus id is missing",
+			devices: []specs.LinuxDevice{
+				{Path: "/dev/nvidia1"},
+				{Path: "/dev/nvidia3"},
+				{Path: "/dev/nvidia0"},
+			},
+			expectedDeviceMap: &devicemap.DeviceMap{
+				Devices: []devicemap.Device{
+					{Index: 0, Minor: 0, UUID: "GPU-0", PCIBusID: "0000:05:00.0"},
+					{Index: 1, Minor: 1, UUID: "GPU-1", PCIBusID: "0000:02:00.0"},
+					{Index: 2, Minor: 3},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			bundleDir := t.TempDir()
+			m := &deviceMapWriter{
+				logger:    logger,
+				bundleDir: bundleDir,
+				hostRoot:  hostRoot,
+			}
+
+			spec := &specs.Spec{
+				Linux: &specs.Linux{
+					Devices: tc.devices,
+				},
+			}
+			require.NoError(t, m.Modify(spec))
+
+			deviceMap, err := devicemap.Load(bundleDir)
+			if tc.expectedDeviceMap == nil {
+				require.ErrorIs(t, err, os.ErrNotExist)
+				return
+			}
+			require.NoError(t, err)
+			require.EqualValues(t, tc.expectedDeviceMap, deviceMap)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"fmt"
+
+	"tags.cncf.io/container-device-interface/specs-go"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/manifest"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/nvcdi"
+)
+
+// NewDiscoveryManifest discovers the driver files and devices on the system
+// using the same options that are used to generate CDI specifications at
+// runtime. The devices are included under their indices and UUIDs.
+func NewDiscoveryManifest(logger logger.Interface, cfg *config.Config) (*manifest.Manifest, error) {
+	indexNamer, _ := nvcdi.NewDeviceNamer(nvcdi.DeviceNameStrategyIndex)
+	uuidNamer, _ := nvcdi.NewDeviceNamer(nvcdi.DeviceNameStrategyUUID)
+
+	cdilib, err := nvcdi.New(
+		append(automaticCDILibOptions(logger, cfg),
+			nvcdi.WithDeviceNamers(indexNamer, uuidNamer),
+		)...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct CDI library: %w", err)
+	}
+
+	cdiSpec, err := cdilib.GetSpec()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate CDI spec: %w", err)
+	}
+
+	return &manifest.Manifest{
+		Version:       manifest.Version,
+		DriverVersion: getDriverVersion(logger, cfg.NVIDIAContainerCLIConfig.Root),
+		Spec:          cdiSpec.Raw(),
+	}, nil
+}
+
+// getDiscoveryManifestSpec returns the CDI specification for the devices
+// with the specified IDs as recorded in the discovery manifest at the
+// specified path. An error is returned if the manifest was generated for a
+// driver version other than the specified one since the recorded driver files
+// may no longer exist.
+func getDiscoveryManifestSpec(logger logger.Interface, path string, driverVersion string, ids []string) (*specs.Spec, error) {
+	m, err := manifest.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	if m.DriverVersion != driverVersion {
+		return nil, fmt.Errorf("discovery manifest was generated for driver version %q but the driver version is %q", m.DriverVersion, driverVersion)
+	}
+	cdiSpec, err := m.GetSpec(ids...)
+	if err != nil {
+		return nil, err
+	}
+	logger.Debugf("Using discovery manifest %v for devices %v", path, ids)
+	return cdiSpec, nil
+}
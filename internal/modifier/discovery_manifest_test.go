@@ -0,0 +1,88 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+	"tags.cncf.io/container-device-interface/specs-go"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/manifest"
+)
+
+func TestGetDiscoveryManifestSpec(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	testCases := []struct {
+		description   string
+		driverVersion string
+		expectedError bool
+	}{
+		{
+			description:   "matching driver version",
+			driverVersion: "999.88.77",
+		},
+		{
+			description:   "different driver version",
+			driverVersion: "999.99.99",
+			expectedError: true,
+		},
+		{
+			description:   "unknown driver version",
+			driverVersion: "",
+			expectedError: true,
+		},
+	}
+
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	m := &manifest.Manifest{
+		Version:       manifest.Version,
+		DriverVersion: "999.88.77",
+		Spec: &specs.Spec{
+			Version: "0.5.0",
+			Kind:    "nvidia.com/gpu",
+			Devices: []specs.Device{
+				{
+					Name: "0",
+					ContainerEdits: specs.ContainerEdits{
+						DeviceNodes: []*specs.DeviceNode{{Path: "/dev/nvidia0"}},
+					},
+				},
+			},
+		},
+	}
+	require.NoError(t, m.Write(f))
+	require.NoError(t, f.Close())
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			spec, err := getDiscoveryManifestSpec(logger, path, tc.driverVersion, []string{"0"})
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, spec.Devices, 1)
+		})
+	}
+}
@@ -0,0 +1,90 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/info"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/oci"
+)
+
+// envvarScrubber removes envvars from the process of a container.
+type envvarScrubber struct {
+	logger   logger.Interface
+	patterns []string
+}
+
+var _ oci.SpecModifier = (*envvarScrubber)(nil)
+
+// NewEnvvarScrubber creates a modifier that removes the configured envvars
+// from the container environment. This is expected to be applied after all
+// other modifications so that tenants do not see the envvars used to request
+// devices or set by the NVIDIA Container Runtime.
+//
+// In legacy mode the NVIDIA Container Runtime Hook reads the requested
+// devices from the container environment when the container is created. A
+// nil modifier is returned in this case, or if no envvars are configured.
+func NewEnvvarScrubber(logger logger.Interface, cfg *config.Config) oci.SpecModifier {
+	patterns := cfg.NVIDIAContainerRuntimeConfig.ScrubEnvvars
+	if len(patterns) == 0 {
+		return nil
+	}
+	if info.RuntimeMode(cfg.NVIDIAContainerRuntimeConfig.Mode) == info.LegacyRuntimeMode {
+		logger.Warningf("Ignoring envvars to scrub in %q mode", info.LegacyRuntimeMode)
+		return nil
+	}
+	return &envvarScrubber{
+		logger:   logger,
+		patterns: patterns,
+	}
+}
+
+// Modify removes the envvars matching any of the configured patterns from
+// the process environment. A pattern is either the name of an envvar or a
+// shell pattern such as NVIDIA_*.
+func (m *envvarScrubber) Modify(spec *specs.Spec) error {
+	if spec == nil || spec.Process == nil {
+		return nil
+	}
+
+	var env []string
+	for _, e := range spec.Process.Env {
+		name, _, _ := strings.Cut(e, "=")
+		if m.matches(name) {
+			m.logger.Debugf("Removing envvar %v from container environment", name)
+			continue
+		}
+		env = append(env, e)
+	}
+	spec.Process.Env = env
+	return nil
+}
+
+func (m *envvarScrubber) matches(name string) bool {
+	for _, pattern := range m.patterns {
+		if match, _ := filepath.Match(pattern, name); match {
+			return true
+		}
+	}
+	return false
+}
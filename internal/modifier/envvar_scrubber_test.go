@@ -0,0 +1,103 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"testing"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
+)
+
+func TestEnvvarScrubber(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	testCases := []struct {
+		description      string
+		mode             string
+		scrubEnvvars     []string
+		spec             *specs.Spec
+		expectedModifier bool
+		expectedSpec     *specs.Spec
+	}{
+		{
+			description: "no envvars configured returns nil modifier",
+			mode:        "cdi",
+		},
+		{
+			description:  "legacy mode returns nil modifier",
+			mode:         "legacy",
+			scrubEnvvars: []string{"NVIDIA_VISIBLE_DEVICES"},
+		},
+		{
+			description:      "nil process is not modified",
+			mode:             "cdi",
+			scrubEnvvars:     []string{"NVIDIA_VISIBLE_DEVICES"},
+			spec:             &specs.Spec{},
+			expectedModifier: true,
+			expectedSpec:     &specs.Spec{},
+		},
+		{
+			description:  "matching envvars are removed",
+			mode:         "jit-cdi",
+			scrubEnvvars: []string{"NVIDIA_VISIBLE_DEVICES", "NVIDIA_DRIVER_*"},
+			spec: &specs.Spec{
+				Process: &specs.Process{
+					Env: []string{
+						"PATH=/usr/bin",
+						"NVIDIA_VISIBLE_DEVICES=all",
+						"NVIDIA_DRIVER_CAPABILITIES=compute,utility",
+						"NVIDIA_REQUIRE_CUDA=cuda>=12.0",
+						"NVIDIA_VISIBLE_DEVICES_EXTRA=foo",
+					},
+				},
+			},
+			expectedModifier: true,
+			expectedSpec: &specs.Spec{
+				Process: &specs.Process{
+					Env: []string{
+						"PATH=/usr/bin",
+						"NVIDIA_REQUIRE_CUDA=cuda>=12.0",
+						"NVIDIA_VISIBLE_DEVICES_EXTRA=foo",
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			cfg := &config.Config{
+				NVIDIAContainerRuntimeConfig: config.RuntimeConfig{
+					Mode:         tc.mode,
+					ScrubEnvvars: tc.scrubEnvvars,
+				},
+			}
+			m := NewEnvvarScrubber(logger, cfg)
+			if !tc.expectedModifier {
+				require.Nil(t, m)
+				return
+			}
+			require.NotNil(t, m)
+			require.NoError(t, m.Modify(tc.spec))
+			require.EqualValues(t, tc.expectedSpec, tc.spec)
+		})
+	}
+}
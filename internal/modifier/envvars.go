@@ -0,0 +1,55 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import "strings"
+
+// setEnvvar sets the specified envvar, replacing any existing values.
+func setEnvvar(env []string, name string, value string) []string {
+	var updated []string
+	for _, e := range env {
+		if strings.HasPrefix(e, name+"=") {
+			continue
+		}
+		updated = append(updated, e)
+	}
+	return append(updated, name+"="+value)
+}
+
+// hasEnvvar checks whether the specified envvar is set in the environment.
+func hasEnvvar(env []string, name string) bool {
+	for _, e := range env {
+		if strings.HasPrefix(e, name+"=") {
+			return true
+		}
+	}
+	return false
+}
+
+// envToMap converts the specified envvars to a map. If an envvar is repeated,
+// the first value is used.
+func envToMap(env []string) map[string]string {
+	envMap := make(map[string]string)
+	for _, e := range env {
+		key, value, _ := strings.Cut(e, "=")
+		if _, ok := envMap[key]; ok {
+			continue
+		}
+		envMap[key] = value
+	}
+	return envMap
+}
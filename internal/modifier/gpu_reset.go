@@ -0,0 +1,94 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"fmt"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/discover"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/oci"
+)
+
+// gpuResetter adds a hook that resets the injected GPUs before a container is
+// started.
+type gpuResetter struct {
+	logger      logger.Interface
+	hookCreator discover.HookCreator
+	mode        string
+	hostRoot    string
+}
+
+var _ oci.SpecModifier = (*gpuResetter)(nil)
+
+// NewGPUResetModifier creates a modifier that adds a reset-gpus createRuntime
+// hook for the GPUs injected into a container. This ensures that settings or
+// state left behind by a previous workload do not affect the next container.
+// A nil modifier is returned if GPU resets are not configured.
+func NewGPUResetModifier(logger logger.Interface, cfg *config.Config, hookCreator discover.HookCreator) (oci.SpecModifier, error) {
+	mode := cfg.NVIDIAContainerRuntimeConfig.GPUReset
+	switch mode {
+	case "":
+		return nil, nil
+	case "clean", "reset":
+	default:
+		return nil, fmt.Errorf("invalid gpu-reset mode %q", mode)
+	}
+	return &gpuResetter{
+		logger:      logger,
+		hookCreator: hookCreator,
+		mode:        mode,
+		hostRoot:    "/",
+	}, nil
+}
+
+// Modify adds the reset-gpus hook for the GPU device nodes in the spec. The
+// hook is added as a createRuntime hook since it must be run in the runtime
+// namespace. Containers without GPU device nodes are not modified.
+func (m *gpuResetter) Modify(spec *specs.Spec) error {
+	if spec == nil || spec.Linux == nil {
+		return nil
+	}
+
+	devices := getInjectedDevices(m.logger, m.hostRoot, spec.Linux.Devices)
+	if len(devices) == 0 {
+		return nil
+	}
+
+	args := []string{"--mode", m.mode}
+	for _, device := range devices {
+		args = append(args, "--device", device)
+	}
+	hook := m.hookCreator.Create(discover.ResetGPUsHook, args...)
+	if hook == nil {
+		return nil
+	}
+
+	m.logger.Debugf("Adding %v hook for devices %v", discover.ResetGPUsHook, devices)
+	if spec.Hooks == nil {
+		spec.Hooks = &specs.Hooks{}
+	}
+	spec.Hooks.CreateRuntime = append(spec.Hooks.CreateRuntime, specs.Hook{
+		Path: hook.Path,
+		Args: hook.Args,
+		Env:  hook.Env,
+	})
+	return nil
+}
@@ -0,0 +1,106 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/discover"
+)
+
+func TestGPUResetModifier(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	hostRoot := t.TempDir()
+	dir := filepath.Join(hostRoot, "proc/driver/nvidia/gpus", "0000:05:00.0")
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "information"), []byte("GPU UUID:        GPU-0\nBus Location:    0000:05:00.0\nDevice Minor:    0\n"), 0644))
+
+	testCases := []struct {
+		description   string
+		mode          string
+		devices       []specs.LinuxDevice
+		expectedError bool
+		expectedHooks *specs.Hooks
+	}{
+		{
+			description: "invalid mode returns error",
+			mode:        "reboot",
+			devices: []specs.LinuxDevice{
+				{Path: "/dev/nvidia0"},
+			},
+			expectedError: true,
+		},
+		{
+			description: "no gpus adds no hook",
+			mode:        "clean",
+			devices: []specs.LinuxDevice{
+				{Path: "/dev/nvidiactl"},
+			},
+		},
+		{
+			description: "hook is added for gpus",
+			mode:        "reset",
+			devices: []specs.LinuxDevice{
+				{Path: "/dev/nvidiactl"},
+				{Path: "/dev/nvidia0"},
+				{Path: "/dev/nvidia1"},
+			},
+			expectedHooks: &specs.Hooks{
+				CreateRuntime: []specs.Hook{
+					{
+						Path: "/usr/bin/nvidia-cdi-hook",
+						Args: []string{"nvidia-cdi-hook", "reset-gpus", "--mode", "reset", "--device", "GPU-0", "--device", "1"},
+						Env:  []string{"NVIDIA_CTK_DEBUG=false"},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			cfg := &config.Config{
+				NVIDIAContainerRuntimeConfig: config.RuntimeConfig{
+					GPUReset: tc.mode,
+				},
+			}
+			m, err := NewGPUResetModifier(logger, cfg, discover.NewHookCreator())
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			m.(*gpuResetter).hostRoot = hostRoot
+
+			spec := &specs.Spec{
+				Linux: &specs.Linux{
+					Devices: tc.devices,
+				},
+			}
+			require.NoError(t, m.Modify(spec))
+			require.EqualValues(t, tc.expectedHooks, spec.Hooks)
+		})
+	}
+}
@@ -24,7 +24,7 @@ import (
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/discover"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup/root"
-	"github.com/NVIDIA/nvidia-container-toolkit/internal/oci"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/oci"
 )
 
 // NewGraphicsModifier constructs a modifier that injects graphics-related modifications into an OCI runtime specification.
@@ -61,6 +61,20 @@ func NewGraphicsModifier(logger logger.Interface, cfg *config.Config, container
 		drmNodes,
 		mounts,
 	)
+
+	if cfg.Features.GenerateXorgConfig.IsEnabled() && container.GetDriverCapabilities().Has(image.DriverCapabilityDisplay) {
+		xorgConfig, err := discover.NewXorgConfigDiscoverer(
+			logger,
+			image.NewVisibleDevices(devices...),
+			driver,
+			devRoot,
+			hookCreator,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to construct Xorg config discoverer: %v", err)
+		}
+		d = discover.Merge(d, xorgConfig)
+	}
 	return NewModifierFromDiscoverer(logger, d)
 }
 
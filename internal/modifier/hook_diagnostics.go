@@ -0,0 +1,64 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"github.com/opencontainers/runtime-spec/specs-go"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/oci"
+)
+
+// hookDiagnosticsAnnotator enables the recording of diagnostics for failed
+// hooks through an annotation on the container.
+type hookDiagnosticsAnnotator struct {
+	logger    logger.Interface
+	bundleDir string
+}
+
+var _ oci.SpecModifier = (*hookDiagnosticsAnnotator)(nil)
+
+// NewHookDiagnosticsAnnotator creates a modifier that sets the hook
+// diagnostics annotation on a container. Since the annotation is included in
+// the container state that is passed to hooks, this enables the NVIDIA CDI
+// hooks to record their output in the container bundle if they fail.
+// A nil modifier is returned if the feature is not enabled.
+func NewHookDiagnosticsAnnotator(logger logger.Interface, cfg *config.Config, bundleDir string) oci.SpecModifier {
+	if !cfg.Features.HookDiagnostics.IsEnabled() {
+		return nil
+	}
+	return &hookDiagnosticsAnnotator{
+		logger:    logger,
+		bundleDir: bundleDir,
+	}
+}
+
+// Modify sets the hook diagnostics annotation to the path of the diagnostics
+// file in the container bundle.
+func (m *hookDiagnosticsAnnotator) Modify(spec *specs.Spec) error {
+	if spec == nil {
+		return nil
+	}
+	if spec.Annotations == nil {
+		spec.Annotations = make(map[string]string)
+	}
+	path := oci.GetHookDiagnosticsFilePath(m.bundleDir)
+	m.logger.Debugf("Recording hook diagnostics in %v", path)
+	spec.Annotations[oci.HookDiagnosticsAnnotation] = path
+	return nil
+}
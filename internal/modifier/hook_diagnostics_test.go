@@ -0,0 +1,52 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"testing"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
+)
+
+func TestHookDiagnosticsAnnotator(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	cfg, err := config.GetDefault()
+	require.NoError(t, err)
+	require.Nil(t, NewHookDiagnosticsAnnotator(logger, cfg, "/bundle"))
+
+	toml, err := config.New()
+	require.NoError(t, err)
+	toml.Set("features.hook-diagnostics", true)
+	cfg, err = toml.Config()
+	require.NoError(t, err)
+	m := NewHookDiagnosticsAnnotator(logger, cfg, "/bundle")
+	require.NotNil(t, m)
+
+	spec := &specs.Spec{
+		Annotations: map[string]string{"foo": "bar"},
+	}
+	require.NoError(t, m.Modify(spec))
+	require.Equal(t, map[string]string{
+		"foo":                         "bar",
+		"nvidia.com/hook-diagnostics": "/bundle/nvidia-hook-diagnostics.log",
+	}, spec.Annotations)
+}
@@ -17,27 +17,69 @@
 package modifier
 
 import (
+	"fmt"
 	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/opencontainers/runtime-spec/specs-go"
 
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
-	"github.com/NVIDIA/nvidia-container-toolkit/internal/oci"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/oci"
 )
 
+const (
+	// HookRemovalPolicyRemove removes existing NVIDIA Container Runtime hooks.
+	// This is the default.
+	HookRemovalPolicyRemove = "remove"
+	// HookRemovalPolicyKeepIfCompatible keeps existing NVIDIA Container
+	// Runtime hooks that do not inject devices (i.e. hooks that are not
+	// invoked with the prestart command) and removes all others.
+	HookRemovalPolicyKeepIfCompatible = "keep-if-compatible"
+	// HookRemovalPolicyFail fails the creation of a container if an existing
+	// NVIDIA Container Runtime hook is found.
+	HookRemovalPolicyFail = "fail"
+)
+
+// defaultHookPatterns are the patterns that identify NVIDIA Container Runtime
+// hooks.
+var defaultHookPatterns = []string{
+	config.NVIDIAContainerRuntimeHookExecutable,
+	config.NVIDIAContainerToolkitExecutable,
+}
+
 // nvidiaContainerRuntimeHookRemover is a spec modifer that detects and removes inserted nvidia-container-runtime hooks
 type nvidiaContainerRuntimeHookRemover struct {
 	logger logger.Interface
+	policy string
+	// patterns defines additional shell patterns that identify NVIDIA
+	// Container Runtime hooks.
+	patterns []string
 }
 
 var _ oci.SpecModifier = (*nvidiaContainerRuntimeHookRemover)(nil)
 
-// NewNvidiaContainerRuntimeHookRemover creates a modifier that removes any NVIDIA Container Runtime hooks from the provided spec.
-func NewNvidiaContainerRuntimeHookRemover(logger logger.Interface) oci.SpecModifier {
-	return nvidiaContainerRuntimeHookRemover{
-		logger: logger,
+// NewNvidiaContainerRuntimeHookRemover creates a modifier that handles any NVIDIA Container Runtime hooks in the
+// provided spec according to the configured policy.
+func NewNvidiaContainerRuntimeHookRemover(logger logger.Interface, cfg *config.Config) (oci.SpecModifier, error) {
+	existingHooks := cfg.NVIDIAContainerRuntimeConfig.ExistingHooks
+	switch existingHooks.Policy {
+	case "", HookRemovalPolicyRemove, HookRemovalPolicyKeepIfCompatible, HookRemovalPolicyFail:
+	default:
+		return nil, fmt.Errorf("invalid existing hook policy %q", existingHooks.Policy)
+	}
+	for _, pattern := range existingHooks.Patterns {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid existing hook pattern %q: %w", pattern, err)
+		}
 	}
+
+	return nvidiaContainerRuntimeHookRemover{
+		logger:   logger,
+		policy:   existingHooks.Policy,
+		patterns: existingHooks.Patterns,
+	}, nil
 }
 
 // Modify removes any NVIDIA Container Runtime hooks from the provided spec
@@ -50,24 +92,45 @@ func (m nvidiaContainerRuntimeHookRemover) Modify(spec *specs.Spec) error {
 		return nil
 	}
 
-	if len(spec.Hooks.Prestart) == 0 {
-		return nil
+	// The NVIDIA Container Runtime hook is only inserted as a prestart or
+	// createRuntime hook. Hooks in other lifecycles are not modified.
+	lifecycles := []struct {
+		name  string
+		hooks *[]specs.Hook
+	}{
+		{"prestart", &spec.Hooks.Prestart},
+		{"createRuntime", &spec.Hooks.CreateRuntime},
 	}
+	patterns := append(slices.Clone(defaultHookPatterns), m.patterns...)
+	for _, lifecycle := range lifecycles {
+		hooks := lifecycle.hooks
+		if len(*hooks) == 0 {
+			continue
+		}
 
-	var newPrestart []specs.Hook
-
-	for _, hook := range spec.Hooks.Prestart {
-		hook := hook
-		if isNVIDIAContainerRuntimeHook(&hook) {
+		var updated []specs.Hook
+		for _, hook := range *hooks {
+			if !matchesHookPatterns(&hook, patterns) {
+				updated = append(updated, hook)
+				continue
+			}
+			switch m.policy {
+			case HookRemovalPolicyFail:
+				return fmt.Errorf("existing NVIDIA Container Runtime hook found in %q hooks: %v", lifecycle.name, hook.Path)
+			case HookRemovalPolicyKeepIfCompatible:
+				if !slices.Contains(hook.Args, "prestart") {
+					m.logger.Debugf("Keeping compatible hook %v", hook)
+					updated = append(updated, hook)
+					continue
+				}
+			}
 			m.logger.Debugf("Removing hook %v", hook)
-			continue
 		}
-		newPrestart = append(newPrestart, hook)
-	}
 
-	if len(newPrestart) != len(spec.Hooks.Prestart) {
-		m.logger.Debugf("Updating 'prestart' hooks to %v", newPrestart)
-		spec.Hooks.Prestart = newPrestart
+		if len(updated) != len(*hooks) {
+			m.logger.Debugf("Updating %q hooks to %v", lifecycle.name, updated)
+			*hooks = updated
+		}
 	}
 
 	return nil
@@ -77,12 +140,29 @@ func (m nvidiaContainerRuntimeHookRemover) Modify(spec *specs.Spec) error {
 // or nvidia-container-toolkit hook. These are included, for example, by the non-experimental
 // nvidia-container-runtime or docker when specifying the --gpus flag.
 func isNVIDIAContainerRuntimeHook(hook *specs.Hook) bool {
-	bins := map[string]struct{}{
-		config.NVIDIAContainerRuntimeHookExecutable: {},
-		config.NVIDIAContainerToolkitExecutable:     {},
-	}
+	return matchesHookPatterns(hook, defaultHookPatterns)
+}
 
-	_, exists := bins[filepath.Base(hook.Path)]
+// matchesHookPatterns checks whether the path of the specified hook, or one
+// of its (non-flag) arguments, has a base name that matches one of the
+// specified shell patterns. Checking the arguments allows hooks that are
+// invoked through a wrapper (e.g. /usr/bin/env) to be detected.
+func matchesHookPatterns(hook *specs.Hook, patterns []string) bool {
+	candidates := []string{hook.Path}
+	for _, arg := range hook.Args {
+		if strings.HasPrefix(arg, "-") {
+			continue
+		}
+		candidates = append(candidates, arg)
+	}
 
-	return exists
+	for _, candidate := range candidates {
+		name := filepath.Base(candidate)
+		for _, pattern := range patterns {
+			if match, _ := filepath.Match(pattern, name); match {
+				return true
+			}
+		}
+	}
+	return false
 }
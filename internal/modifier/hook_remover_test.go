@@ -17,11 +17,14 @@
 package modifier
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/opencontainers/runtime-spec/specs-go"
 	testlog "github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
 )
 
 func TestHookRemover(t *testing.T) {
@@ -29,6 +32,8 @@ func TestHookRemover(t *testing.T) {
 
 	testCases := []struct {
 		description   string
+		policy        string
+		patterns      []string
 		spec          *specs.Spec
 		expectedError error
 		expectedSpec  *specs.Spec
@@ -92,11 +97,158 @@ func TestHookRemover(t *testing.T) {
 				},
 			},
 		},
+		{
+			description: "hook invoked through a wrapper is removed",
+			spec: &specs.Spec{
+				Hooks: &specs.Hooks{
+					Prestart: []specs.Hook{
+						{
+							Path: "/usr/bin/env",
+							Args: []string{"env", "/usr/local/bin/nvidia-container-runtime-hook", "prestart"},
+						},
+					},
+				},
+			},
+			expectedSpec: &specs.Spec{
+				Hooks: &specs.Hooks{
+					Prestart: nil,
+				},
+			},
+		},
+		{
+			description: "hook matching additional pattern is removed",
+			patterns:    []string{"nvidia-container-runtime-hook.*"},
+			spec: &specs.Spec{
+				Hooks: &specs.Hooks{
+					Prestart: []specs.Hook{
+						{
+							Path: "/opt/nvidia/nvidia-container-runtime-hook.real",
+							Args: []string{"nvidia-container-runtime-hook.real", "prestart"},
+						},
+						{
+							Path: "/hook/a",
+							Args: []string{"/hook/a", "--config=/etc/nvidia-container-runtime-hook.toml"},
+						},
+					},
+				},
+			},
+			expectedSpec: &specs.Spec{
+				Hooks: &specs.Hooks{
+					Prestart: []specs.Hook{
+						{
+							Path: "/hook/a",
+							Args: []string{"/hook/a", "--config=/etc/nvidia-container-runtime-hook.toml"},
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "hooks in createRuntime are removed",
+			spec: &specs.Spec{
+				Hooks: &specs.Hooks{
+					CreateRuntime: []specs.Hook{
+						{
+							Path: "/path/to/nvidia-container-runtime-hook",
+							Args: []string{"/path/to/nvidia-container-runtime-hook", "prestart"},
+						},
+					},
+				},
+			},
+			expectedSpec: &specs.Spec{
+				Hooks: &specs.Hooks{
+					CreateRuntime: nil,
+				},
+			},
+		},
+		{
+			description: "hooks in other lifecycles are maintained",
+			spec: &specs.Spec{
+				Hooks: &specs.Hooks{
+					Poststop: []specs.Hook{
+						{
+							Path: "/path/to/nvidia-container-runtime-hook",
+							Args: []string{"/path/to/nvidia-container-runtime-hook", "poststop"},
+						},
+					},
+				},
+			},
+			expectedSpec: &specs.Spec{
+				Hooks: &specs.Hooks{
+					Poststop: []specs.Hook{
+						{
+							Path: "/path/to/nvidia-container-runtime-hook",
+							Args: []string{"/path/to/nvidia-container-runtime-hook", "poststop"},
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "keep-if-compatible keeps hooks that do not inject devices",
+			policy:      HookRemovalPolicyKeepIfCompatible,
+			spec: &specs.Spec{
+				Hooks: &specs.Hooks{
+					Prestart: []specs.Hook{
+						{
+							Path: "/path/to/nvidia-container-runtime-hook",
+							Args: []string{"/path/to/nvidia-container-runtime-hook", "prestart"},
+						},
+					},
+					CreateRuntime: []specs.Hook{
+						{
+							Path: "/path/to/nvidia-container-runtime-hook",
+							Args: []string{"/path/to/nvidia-container-runtime-hook", "poststop"},
+						},
+					},
+				},
+			},
+			expectedSpec: &specs.Spec{
+				Hooks: &specs.Hooks{
+					Prestart: nil,
+					CreateRuntime: []specs.Hook{
+						{
+							Path: "/path/to/nvidia-container-runtime-hook",
+							Args: []string{"/path/to/nvidia-container-runtime-hook", "poststop"},
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "fail returns an error for existing hooks",
+			policy:      HookRemovalPolicyFail,
+			spec: &specs.Spec{
+				Hooks: &specs.Hooks{
+					Prestart: []specs.Hook{
+						{
+							Path: "/path/to/nvidia-container-runtime-hook",
+							Args: []string{"/path/to/nvidia-container-runtime-hook", "prestart"},
+						},
+					},
+				},
+			},
+			expectedError: fmt.Errorf("existing NVIDIA Container Runtime hook found"),
+			expectedSpec: &specs.Spec{
+				Hooks: &specs.Hooks{
+					Prestart: []specs.Hook{
+						{
+							Path: "/path/to/nvidia-container-runtime-hook",
+							Args: []string{"/path/to/nvidia-container-runtime-hook", "prestart"},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.description, func(t *testing.T) {
-			m := nvidiaContainerRuntimeHookRemover{logger: logger}
+			m := nvidiaContainerRuntimeHookRemover{
+				logger:   logger,
+				policy:   tc.policy,
+				patterns: tc.patterns,
+			}
 
 			err := m.Modify(tc.spec)
 			if tc.expectedError != nil {
@@ -109,3 +261,21 @@ func TestHookRemover(t *testing.T) {
 		})
 	}
 }
+
+func TestNewNvidiaContainerRuntimeHookRemover(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	cfg := &config.Config{}
+	cfg.NVIDIAContainerRuntimeConfig.ExistingHooks.Policy = "invalid"
+	_, err := NewNvidiaContainerRuntimeHookRemover(logger, cfg)
+	require.Error(t, err)
+
+	cfg.NVIDIAContainerRuntimeConfig.ExistingHooks.Policy = HookRemovalPolicyFail
+	cfg.NVIDIAContainerRuntimeConfig.ExistingHooks.Patterns = []string{"["}
+	_, err = NewNvidiaContainerRuntimeHookRemover(logger, cfg)
+	require.Error(t, err)
+
+	cfg.NVIDIAContainerRuntimeConfig.ExistingHooks.Patterns = []string{"nvidia-*-hook"}
+	_, err = NewNvidiaContainerRuntimeHookRemover(logger, cfg)
+	require.NoError(t, err)
+}
@@ -0,0 +1,99 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"strings"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/oci"
+)
+
+// InjectedEnvvarsAnnotation records the names of the envvars that were added
+// to or modified in the container process by the NVIDIA Container Runtime.
+// These are forwarded to processes started in the container using exec.
+const InjectedEnvvarsAnnotation = "nvidia.com/injected-envvars"
+
+// injectedEnvvarRecorder records the envvars that differ from the original
+// container process in the spec.
+type injectedEnvvarRecorder struct {
+	logger      logger.Interface
+	originalEnv map[string]string
+}
+
+var _ oci.SpecModifier = (*injectedEnvvarRecorder)(nil)
+
+// NewInjectedEnvvarRecorder creates a modifier that records the names of the
+// envvars that were injected into a container in the
+// nvidia.com/injected-envvars annotation. Envvars are considered injected if
+// they are not set in the specified original environment or have a different
+// value. Since the annotation is part of the container state, this allows the
+// envvars to be forwarded to processes started using exec, where container
+// engines only apply the environment of the original container config.
+func NewInjectedEnvvarRecorder(logger logger.Interface, originalEnv []string) oci.SpecModifier {
+	return &injectedEnvvarRecorder{
+		logger:      logger,
+		originalEnv: envToMap(originalEnv),
+	}
+}
+
+// Modify sets the annotation for the injected envvars. If no envvars were
+// injected, the spec is not modified.
+func (m *injectedEnvvarRecorder) Modify(spec *specs.Spec) error {
+	if spec == nil || spec.Process == nil {
+		return nil
+	}
+
+	var injected []string
+	seen := make(map[string]bool)
+	for _, env := range spec.Process.Env {
+		key, value, _ := strings.Cut(env, "=")
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		if original, ok := m.originalEnv[key]; ok && original == value {
+			continue
+		}
+		injected = append(injected, key)
+	}
+	if len(injected) == 0 {
+		return nil
+	}
+
+	m.logger.Debugf("Recording injected envvars %v", injected)
+	if spec.Annotations == nil {
+		spec.Annotations = make(map[string]string)
+	}
+	spec.Annotations[InjectedEnvvarsAnnotation] = strings.Join(injected, ",")
+	return nil
+}
+
+// GetInjectedEnvvars returns the names of the envvars recorded as injected in
+// the annotations of the specified spec.
+func GetInjectedEnvvars(spec *specs.Spec) []string {
+	if spec == nil {
+		return nil
+	}
+	value := spec.Annotations[InjectedEnvvarsAnnotation]
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
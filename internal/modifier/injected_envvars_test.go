@@ -0,0 +1,80 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"testing"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInjectedEnvvarRecorder(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	testCases := []struct {
+		description         string
+		originalEnv         []string
+		env                 []string
+		expectedAnnotations map[string]string
+	}{
+		{
+			description: "no injected envvars",
+			originalEnv: []string{"PATH=/usr/bin", "NVIDIA_VISIBLE_DEVICES=all"},
+			env:         []string{"PATH=/usr/bin", "NVIDIA_VISIBLE_DEVICES=all"},
+		},
+		{
+			description: "added and modified envvars are recorded",
+			originalEnv: []string{"PATH=/usr/bin", "LD_LIBRARY_PATH=/opt/lib"},
+			env: []string{
+				"PATH=/usr/bin",
+				"LD_LIBRARY_PATH=/usr/lib64:/opt/lib",
+				"NVIDIA_CHECKPOINT_DIR=/var/lib/nvidia-checkpoint",
+			},
+			expectedAnnotations: map[string]string{
+				"nvidia.com/injected-envvars": "LD_LIBRARY_PATH,NVIDIA_CHECKPOINT_DIR",
+			},
+		},
+		{
+			description: "removed envvars are not recorded",
+			originalEnv: []string{"PATH=/usr/bin", "SECRET=value"},
+			env:         []string{"PATH=/usr/bin"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			spec := &specs.Spec{
+				Process: &specs.Process{
+					Env: tc.env,
+				},
+			}
+			require.NoError(t, NewInjectedEnvvarRecorder(logger, tc.originalEnv).Modify(spec))
+			require.EqualValues(t, tc.expectedAnnotations, spec.Annotations)
+		})
+	}
+}
+
+func TestGetInjectedEnvvars(t *testing.T) {
+	require.Nil(t, GetInjectedEnvvars(&specs.Spec{}))
+	require.Equal(t, []string{"LD_LIBRARY_PATH", "OTHER"}, GetInjectedEnvvars(&specs.Spec{
+		Annotations: map[string]string{
+			"nvidia.com/injected-envvars": "LD_LIBRARY_PATH,OTHER",
+		},
+	}))
+}
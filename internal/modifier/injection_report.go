@@ -0,0 +1,165 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/info"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/info/proc"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/injectionreport"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/oci"
+)
+
+// injectionReportWriter writes a report of the modifications made to a
+// container to the container bundle and mounts it into the container.
+type injectionReportWriter struct {
+	logger    logger.Interface
+	recorder  *logger.WarningRecorder
+	mode      string
+	version   string
+	bundleDir string
+	hostRoot  string
+	// existingDevices and existingMounts are the device nodes and mount
+	// destinations in the incoming spec. These were not added by the NVIDIA
+	// Container Runtime and are not reported.
+	existingDevices map[string]bool
+	existingMounts  map[string]bool
+}
+
+var _ oci.SpecModifier = (*injectionReportWriter)(nil)
+
+// NewInjectionReportWriter creates a modifier that writes an injection report
+// for the container to the specified bundle directory and mounts it at
+// injectionreport.ContainerPath in the container. The warnings recorded by the
+// specified recorder up to the point where the spec is modified are included
+// in the report.
+// A nil modifier is returned if the feature is not enabled.
+func NewInjectionReportWriter(logger logger.Interface, cfg *config.Config, recorder *logger.WarningRecorder, bundleDir string, rawSpec *specs.Spec) oci.SpecModifier {
+	if !cfg.Features.WriteInjectionReport.IsEnabled() {
+		return nil
+	}
+	m := &injectionReportWriter{
+		logger:          logger,
+		recorder:        recorder,
+		mode:            cfg.NVIDIAContainerRuntimeConfig.Mode,
+		version:         info.GetVersion(),
+		bundleDir:       bundleDir,
+		hostRoot:        "/",
+		existingDevices: make(map[string]bool),
+		existingMounts:  make(map[string]bool),
+	}
+	if rawSpec != nil {
+		for _, mount := range rawSpec.Mounts {
+			m.existingMounts[mount.Destination] = true
+		}
+		if rawSpec.Linux != nil {
+			for _, device := range rawSpec.Linux.Devices {
+				m.existingDevices[device.Path] = true
+			}
+		}
+	}
+	return m
+}
+
+// Modify writes the injection report and adds a read-only mount for it to the
+// spec. Failures to write the report are logged and the spec is not modified
+// in this case.
+func (m *injectionReportWriter) Modify(spec *specs.Spec) error {
+	if spec == nil {
+		return nil
+	}
+
+	report := m.getReport(spec)
+	bundleDir, err := filepath.Abs(m.bundleDir)
+	if err != nil {
+		bundleDir = m.bundleDir
+	}
+	if err := report.Save(bundleDir); err != nil {
+		m.logger.Warningf("Failed to write injection report: %v", err)
+		return nil
+	}
+
+	spec.Mounts = append(spec.Mounts, specs.Mount{
+		Destination: injectionreport.ContainerPath,
+		Source:      injectionreport.GetPath(bundleDir),
+		Type:        "bind",
+		Options:     []string{"ro", "nosuid", "nodev", "noexec", "bind"},
+	})
+	return nil
+}
+
+// getReport constructs the injection report for the specified spec.
+func (m *injectionReportWriter) getReport(spec *specs.Spec) *injectionreport.Report {
+	report := &injectionreport.Report{
+		ToolkitVersion: m.version,
+		Mode:           m.mode,
+		Devices:        []injectionreport.Device{},
+		Libraries:      []injectionreport.Library{},
+		Skipped:        []string{},
+		Warnings:       []string{},
+	}
+
+	if spec.Linux != nil {
+		uuidsByMinor := make(map[int]string)
+		for _, gpu := range getHostGPUs(m.logger, m.hostRoot) {
+			uuidsByMinor[gpu.minor] = gpu.info[proc.GPUInfoGPUUUID]
+		}
+		for _, device := range spec.Linux.Devices {
+			if m.existingDevices[device.Path] {
+				continue
+			}
+			d := injectionreport.Device{Path: device.Path}
+			if matches := gpuDeviceNodePattern.FindStringSubmatch(device.Path); len(matches) == 2 {
+				minor, _ := strconv.Atoi(matches[1])
+				d.UUID = uuidsByMinor[minor]
+			}
+			report.Devices = append(report.Devices, d)
+		}
+	}
+
+	for _, mount := range spec.Mounts {
+		if m.existingMounts[mount.Destination] || !isLibraryPath(mount.Source) {
+			continue
+		}
+		report.Libraries = append(report.Libraries, injectionreport.Library{
+			Path:     mount.Destination,
+			HostPath: mount.Source,
+		})
+	}
+
+	if m.mode == string(info.LegacyRuntimeMode) {
+		report.Warnings = append(report.Warnings, "devices and libraries are injected by the nvidia-container-runtime-hook in legacy mode and are not listed")
+	}
+	if m.recorder != nil {
+		report.Skipped = append(report.Skipped, m.recorder.Skipped()...)
+		report.Warnings = append(report.Warnings, m.recorder.Warnings()...)
+	}
+	return report
+}
+
+// isLibraryPath checks whether the specified path refers to a shared library.
+func isLibraryPath(path string) bool {
+	base := filepath.Base(path)
+	return strings.HasSuffix(base, ".so") || strings.Contains(base, ".so.")
+}
@@ -0,0 +1,124 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/injectionreport"
+)
+
+func TestInjectionReportWriter(t *testing.T) {
+	testLogger, _ := testlog.NewNullLogger()
+
+	hostRoot := t.TempDir()
+	dir := filepath.Join(hostRoot, "proc/driver/nvidia/gpus/0000:05:00.0")
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "information"), []byte("GPU UUID:        GPU-0\nBus Location:    0000:05:00.0\nDevice Minor:    0\n"), 0644))
+
+	toml, err := config.New()
+	require.NoError(t, err)
+	toml.Set("features.write-injection-report", true)
+	toml.Set("nvidia-container-runtime.mode", "jit-cdi")
+	cfg, err := toml.Config()
+	require.NoError(t, err)
+
+	rawSpec := &specs.Spec{
+		Mounts: []specs.Mount{
+			{Destination: "/usr/lib/libexisting.so.1", Source: "/host/libexisting.so.1"},
+		},
+		Linux: &specs.Linux{
+			Devices: []specs.LinuxDevice{{Path: "/dev/fuse"}},
+		},
+	}
+
+	recorder := logger.NewWarningRecorder(testLogger)
+	bundleDir := t.TempDir()
+	m := NewInjectionReportWriter(recorder, cfg, recorder, bundleDir, rawSpec)
+	require.NotNil(t, m)
+	m.(*injectionReportWriter).hostRoot = hostRoot
+	m.(*injectionReportWriter).version = "1.2.3"
+
+	logger.Skippedf(recorder, "Could not locate %v: %v", "libnvidia-missing.so", "not found")
+	recorder.Warningf("Failed to get CUDA version")
+
+	spec := &specs.Spec{
+		Mounts: []specs.Mount{
+			{Destination: "/usr/lib/libexisting.so.1", Source: "/host/libexisting.so.1"},
+			{Destination: "/usr/lib/libcuda.so.550.54", Source: "/host/lib/libcuda.so.550.54"},
+			{Destination: "/usr/bin/nvidia-smi", Source: "/host/bin/nvidia-smi"},
+		},
+		Linux: &specs.Linux{
+			Devices: []specs.LinuxDevice{
+				{Path: "/dev/fuse"},
+				{Path: "/dev/nvidiactl"},
+				{Path: "/dev/nvidia0"},
+			},
+		},
+	}
+	require.NoError(t, m.Modify(spec))
+
+	require.Contains(t, spec.Mounts, specs.Mount{
+		Destination: injectionreport.ContainerPath,
+		Source:      injectionreport.GetPath(bundleDir),
+		Type:        "bind",
+		Options:     []string{"ro", "nosuid", "nodev", "noexec", "bind"},
+	})
+
+	report, err := injectionreport.Load(injectionreport.GetPath(bundleDir))
+	require.NoError(t, err)
+	require.EqualValues(t,
+		&injectionreport.Report{
+			ToolkitVersion: "1.2.3",
+			Mode:           "jit-cdi",
+			Devices: []injectionreport.Device{
+				{Path: "/dev/nvidiactl"},
+				{Path: "/dev/nvidia0", UUID: "GPU-0"},
+			},
+			Libraries: []injectionreport.Library{
+				{Path: "/usr/lib/libcuda.so.550.54", HostPath: "/host/lib/libcuda.so.550.54"},
+			},
+			Skipped:  []string{"Could not locate libnvidia-missing.so: not found"},
+			Warnings: []string{"Failed to get CUDA version"},
+		},
+		report,
+	)
+}
+
+func TestInjectionReportWriterSoftFails(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	toml, err := config.New()
+	require.NoError(t, err)
+	toml.Set("features.write-injection-report", true)
+	cfg, err := toml.Config()
+	require.NoError(t, err)
+
+	m := NewInjectionReportWriter(logger, cfg, nil, filepath.Join(t.TempDir(), "missing"), nil)
+
+	spec := &specs.Spec{}
+	require.NoError(t, m.Modify(spec))
+	require.Empty(t, spec.Mounts)
+}
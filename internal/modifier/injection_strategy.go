@@ -0,0 +1,148 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/discover"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/oci"
+)
+
+const (
+	// InjectionStrategyBind bind-mounts driver libraries into the container.
+	InjectionStrategyBind = "bind"
+	// InjectionStrategyCopy copies driver libraries into the container.
+	InjectionStrategyCopy = "copy"
+)
+
+// injectionStrategyModifier replaces the library bind mounts added by a
+// wrapped modifier with a hook that copies the libraries into the container
+// root filesystem.
+type injectionStrategyModifier struct {
+	logger      logger.Interface
+	hookCreator discover.HookCreator
+	modifier    oci.SpecModifier
+}
+
+var _ oci.SpecModifier = (*injectionStrategyModifier)(nil)
+
+// NewInjectionStrategyModifier wraps the specified modifier so that driver
+// libraries are injected using the configured injection strategy. For the
+// default bind strategy the specified modifier is returned as is.
+func NewInjectionStrategyModifier(logger logger.Interface, cfg *config.Config, hookCreator discover.HookCreator, modifier oci.SpecModifier) (oci.SpecModifier, error) {
+	strategy := cfg.NVIDIAContainerRuntimeConfig.InjectionStrategy
+	switch strategy {
+	case "", InjectionStrategyBind:
+		return modifier, nil
+	case InjectionStrategyCopy:
+	default:
+		return nil, fmt.Errorf("invalid injection strategy %q", strategy)
+	}
+
+	if modifier == nil {
+		return nil, nil
+	}
+
+	return &injectionStrategyModifier{
+		logger:      logger,
+		hookCreator: hookCreator,
+		modifier:    modifier,
+	}, nil
+}
+
+// Modify applies the wrapped modifier and replaces the library bind mounts
+// that it added with a createContainer hook. The hook is added before any
+// existing createContainer hooks so that the libraries are present when the
+// ldcache is updated. Since files cannot be copied into a read-only container
+// root filesystem, the library bind mounts are kept in this case.
+func (m *injectionStrategyModifier) Modify(spec *specs.Spec) error {
+	existing := make(map[string]bool)
+	for _, mount := range spec.Mounts {
+		existing[mountKey(mount)] = true
+	}
+
+	if err := m.modifier.Modify(spec); err != nil {
+		return err
+	}
+
+	if spec.Root != nil && spec.Root.Readonly {
+		m.logger.Infof("The container root filesystem is read-only; keeping library bind mounts")
+		return nil
+	}
+
+	var args []string
+	var mounts []specs.Mount
+	for _, mount := range spec.Mounts {
+		if existing[mountKey(mount)] || !IsLibraryMount(mount) {
+			mounts = append(mounts, mount)
+			continue
+		}
+		args = append(args, "--file", mount.Source+"::"+mount.Destination)
+	}
+	if len(args) == 0 {
+		return nil
+	}
+
+	hook := m.hookCreator.Create(discover.CopyFilesHook, args...)
+	if hook == nil {
+		m.logger.Warningf("The %v hook is disabled; keeping library bind mounts", discover.CopyFilesHook)
+		return nil
+	}
+
+	m.logger.Debugf("Replacing %d library bind mounts with the %v hook", len(spec.Mounts)-len(mounts), discover.CopyFilesHook)
+	spec.Mounts = mounts
+	if spec.Hooks == nil {
+		spec.Hooks = &specs.Hooks{}
+	}
+	spec.Hooks.CreateContainer = slices.Insert(spec.Hooks.CreateContainer, 0, specs.Hook{
+		Path: hook.Path,
+		Args: hook.Args,
+		Env:  hook.Env,
+	})
+	return nil
+}
+
+// mountKey returns a key that identifies the specified mount.
+func mountKey(mount specs.Mount) string {
+	return mount.Source + "::" + mount.Destination
+}
+
+// IsLibraryMount checks whether the specified mount is a bind mount of a
+// regular file that is a shared library.
+func IsLibraryMount(mount specs.Mount) bool {
+	if !slices.Contains(mount.Options, "bind") && !slices.Contains(mount.Options, "rbind") {
+		return false
+	}
+	base := filepath.Base(mount.Source)
+	if !strings.HasSuffix(base, ".so") && !strings.Contains(base, ".so.") {
+		return false
+	}
+	info, err := os.Stat(mount.Source)
+	if err != nil {
+		return false
+	}
+	return info.Mode().IsRegular()
+}
@@ -0,0 +1,150 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/discover"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/oci"
+)
+
+type addMounts []specs.Mount
+
+func (m addMounts) Modify(spec *specs.Spec) error {
+	spec.Mounts = append(spec.Mounts, m...)
+	return nil
+}
+
+func TestInjectionStrategyModifier(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+	hostRoot := t.TempDir()
+	library := filepath.Join(hostRoot, "libcuda.so.570.1")
+	require.NoError(t, os.WriteFile(library, []byte("libcuda"), 0644))
+	libraryMount := specs.Mount{
+		Source:      library,
+		Destination: "/usr/lib64/libcuda.so.570.1",
+		Options:     []string{"ro", "nosuid", "nodev", "rbind", "rprivate"},
+	}
+	binaryMount := specs.Mount{
+		Source:      filepath.Join(hostRoot, "nvidia-smi"),
+		Destination: "/usr/bin/nvidia-smi",
+		Options:     []string{"ro", "nosuid", "nodev", "rbind", "rprivate"},
+	}
+	existingHook := specs.Hook{Path: "/usr/bin/nvidia-cdi-hook", Args: []string{"nvidia-cdi-hook", "update-ldcache"}}
+
+	testCases := []struct {
+		description   string
+		strategy      string
+		disabledHooks []discover.HookName
+		spec          *specs.Spec
+		modifier      oci.SpecModifier
+		expectedError bool
+		expectedSpec  *specs.Spec
+	}{
+		{
+			description:  "bind strategy does not modify mounts",
+			strategy:     "bind",
+			spec:         &specs.Spec{},
+			modifier:     addMounts{libraryMount},
+			expectedSpec: &specs.Spec{Mounts: []specs.Mount{libraryMount}},
+		},
+		{
+			description:   "invalid strategy returns error",
+			strategy:      "symlink",
+			modifier:      addMounts{libraryMount},
+			expectedError: true,
+		},
+		{
+			description: "copy strategy replaces library mounts",
+			strategy:    "copy",
+			spec: &specs.Spec{
+				Hooks: &specs.Hooks{CreateContainer: []specs.Hook{existingHook}},
+			},
+			modifier: addMounts{libraryMount, binaryMount},
+			expectedSpec: &specs.Spec{
+				Mounts: []specs.Mount{binaryMount},
+				Hooks: &specs.Hooks{
+					CreateContainer: []specs.Hook{
+						{
+							Path: "/usr/bin/nvidia-cdi-hook",
+							Args: []string{"nvidia-cdi-hook", "copy-files", "--file", library + "::/usr/lib64/libcuda.so.570.1"},
+							Env:  []string{"NVIDIA_CTK_DEBUG=false"},
+						},
+						existingHook,
+					},
+				},
+			},
+		},
+		{
+			description:   "hardlink strategy is not supported",
+			strategy:      "hardlink",
+			modifier:      addMounts{libraryMount},
+			expectedError: true,
+		},
+		{
+			description:  "read-only root filesystem keeps library mounts",
+			strategy:     "copy",
+			spec:         &specs.Spec{Root: &specs.Root{Readonly: true}},
+			modifier:     addMounts{libraryMount},
+			expectedSpec: &specs.Spec{Root: &specs.Root{Readonly: true}, Mounts: []specs.Mount{libraryMount}},
+		},
+		{
+			description:  "existing mounts are not replaced",
+			strategy:     "copy",
+			spec:         &specs.Spec{Mounts: []specs.Mount{libraryMount}},
+			modifier:     addMounts{},
+			expectedSpec: &specs.Spec{Mounts: []specs.Mount{libraryMount}},
+		},
+		{
+			description:   "disabled hook keeps library mounts",
+			strategy:      "copy",
+			disabledHooks: []discover.HookName{discover.CopyFilesHook},
+			spec:          &specs.Spec{},
+			modifier:      addMounts{libraryMount},
+			expectedSpec:  &specs.Spec{Mounts: []specs.Mount{libraryMount}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			cfg := &config.Config{
+				NVIDIAContainerRuntimeConfig: config.RuntimeConfig{
+					InjectionStrategy: tc.strategy,
+				},
+			}
+			hookCreator := discover.NewHookCreator(discover.WithDisabledHooks(tc.disabledHooks...))
+
+			m, err := NewInjectionStrategyModifier(logger, cfg, hookCreator, tc.modifier)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			require.NoError(t, m.Modify(tc.spec))
+			require.EqualValues(t, tc.expectedSpec, tc.spec)
+		})
+	}
+}
@@ -0,0 +1,107 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/info"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/info/proc"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/oci"
+)
+
+const (
+	injectedDevicesEnvvar = "NVIDIA_INJECTED_DEVICES"
+	toolkitVersionEnvvar  = "NVIDIA_TOOLKIT_VERSION"
+)
+
+// injectionSummarizer sets envvars in the container that summarize the
+// modifications made by the NVIDIA Container Toolkit.
+type injectionSummarizer struct {
+	logger   logger.Interface
+	hostRoot string
+	version  string
+}
+
+var _ oci.SpecModifier = (*injectionSummarizer)(nil)
+
+// NewInjectionSummarizer creates a modifier that sets the
+// NVIDIA_INJECTED_DEVICES and NVIDIA_TOOLKIT_VERSION envvars for containers
+// that GPUs are injected into.
+// A nil modifier is returned if the feature is not enabled.
+func NewInjectionSummarizer(logger logger.Interface, cfg *config.Config) oci.SpecModifier {
+	if !cfg.Features.SetInjectionSummaryEnvvars.IsEnabled() {
+		return nil
+	}
+	return &injectionSummarizer{
+		logger:   logger,
+		hostRoot: "/",
+		version:  info.GetVersion(),
+	}
+}
+
+// Modify sets the summary envvars based on the GPU device nodes in the spec.
+// The injected devices are listed by UUID where this is known and by device
+// minor number otherwise. Containers without GPU device nodes are not
+// modified.
+func (m *injectionSummarizer) Modify(spec *specs.Spec) error {
+	if spec == nil || spec.Linux == nil {
+		return nil
+	}
+
+	devices := getInjectedDevices(m.logger, m.hostRoot, spec.Linux.Devices)
+	if len(devices) == 0 {
+		return nil
+	}
+
+	if spec.Process == nil {
+		spec.Process = &specs.Process{}
+	}
+	spec.Process.Env = setEnvvar(spec.Process.Env, injectedDevicesEnvvar, strings.Join(devices, ","))
+	spec.Process.Env = setEnvvar(spec.Process.Env, toolkitVersionEnvvar, m.version)
+	return nil
+}
+
+// getInjectedDevices returns the GPUs for the /dev/nvidiaN device nodes in
+// the specified devices. GPUs are identified by UUID where this is known and by
+// device minor number otherwise.
+func getInjectedDevices(logger logger.Interface, hostRoot string, containerDevices []specs.LinuxDevice) []string {
+	minors := getGPUDeviceMinors(containerDevices)
+	if len(minors) == 0 {
+		return nil
+	}
+
+	uuidsByMinor := make(map[int]string)
+	for _, gpu := range getHostGPUs(logger, hostRoot) {
+		uuidsByMinor[gpu.minor] = gpu.info[proc.GPUInfoGPUUUID]
+	}
+
+	var devices []string
+	for _, minor := range minors {
+		if uuid := uuidsByMinor[minor]; uuid != "" {
+			devices = append(devices, uuid)
+			continue
+		}
+		devices = append(devices, strconv.Itoa(minor))
+	}
+	return devices
+}
@@ -0,0 +1,99 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInjectionSummarizer(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	hostRoot := t.TempDir()
+	dir := filepath.Join(hostRoot, "proc/driver/nvidia/gpus", "0000:05:00.0")
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "information"), []byte("GPU UUID:        GPU-0\nBus Location:    0000:05:00.0\nDevice Minor:    0\n"), 0644))
+
+	testCases := []struct {
+		description string
+		devices     []specs.LinuxDevice
+		env         []string
+		expectedEnv []string
+	}{
+		{
+			description: "no gpus sets no envvars",
+			devices: []specs.LinuxDevice{
+				{Path: "/dev/nvidiactl"},
+			},
+			env:         []string{"PATH=/usr/bin"},
+			expectedEnv: []string{"PATH=/usr/bin"},
+		},
+		{
+			description: "gpus are listed by uuid or minor",
+			devices: []specs.LinuxDevice{
+				{Path: "/dev/nvidiactl"},
+				{Path: "/dev/nvidia0"},
+				{Path: "/dev/nvidia1"},
+			},
+			env: []string{"PATH=/usr/bin"},
+			expectedEnv: []string{
+				"PATH=/usr/bin",
+				"NVIDIA_INJECTED_DEVICES=GPU-0,1",
+				"NVIDIA_TOOLKIT_VERSION=1.2.3",
+			},
+		},
+		{
+			description: "existing envvars are replaced",
+			devices: []specs.LinuxDevice{
+				{Path: "/dev/nvidia0"},
+			},
+			env: []string{"NVIDIA_INJECTED_DEVICES=all", "PATH=/usr/bin"},
+			expectedEnv: []string{
+				"PATH=/usr/bin",
+				"NVIDIA_INJECTED_DEVICES=GPU-0",
+				"NVIDIA_TOOLKIT_VERSION=1.2.3",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			m := &injectionSummarizer{
+				logger:   logger,
+				hostRoot: hostRoot,
+				version:  "1.2.3",
+			}
+
+			spec := &specs.Spec{
+				Process: &specs.Process{
+					Env: tc.env,
+				},
+				Linux: &specs.Linux{
+					Devices: tc.devices,
+				},
+			}
+			require.NoError(t, m.Modify(spec))
+			require.EqualValues(t, tc.expectedEnv, spec.Process.Env)
+		})
+	}
+}
@@ -0,0 +1,117 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/info"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/system/nvdevices"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/system/nvmodules"
+)
+
+// usesNVIDIAKernelModules is used to check whether the system uses the NVIDIA
+// kernel modules. This is a variable to allow it to be overridden in tests.
+var usesNVIDIAKernelModules = info.UsesNVIDIAKernelModules
+
+// requiredKernelModules lists the kernel modules that are required for the
+// control device nodes to be created.
+var requiredKernelModules = []string{"nvidia", "nvidia-uvm"}
+
+// errKernelModulesNotLoaded is returned if the required kernel modules are
+// not loaded. Since this is the case while the driver is being installed,
+// this is considered a transient error.
+var errKernelModulesNotLoaded = errors.New("kernel modules are not loaded")
+
+// checkKernelModules ensures that the NVIDIA kernel modules are loaded if the
+// /dev/nvidiactl device node does not exist. This allows for an actionable
+// error to be returned instead of failing while discovering device nodes.
+// If enabled in the config, an attempt is made to load missing modules. If a
+// device node helper socket is configured, the helper is used to create the
+// control device nodes once the kernel modules are loaded.
+// The check is skipped on systems that do not use these kernel modules (WSL
+// and Tegra-based systems).
+func checkKernelModules(logger logger.Interface, cfg *config.Config) error {
+	driverRoot := cfg.NVIDIAContainerCLIConfig.Root
+	if driverRoot == "" {
+		driverRoot = "/"
+	}
+
+	nvidiactlPath := filepath.Join(driverRoot, "dev/nvidiactl")
+	if _, err := os.Stat(nvidiactlPath); err == nil {
+		return nil
+	}
+	if !usesNVIDIAKernelModules(logger, driverRoot) {
+		return nil
+	}
+
+	modules := nvmodules.New(
+		nvmodules.WithLogger(logger),
+		nvmodules.WithRoot(driverRoot),
+	)
+	missing, err := modules.Missing(requiredKernelModules...)
+	if err != nil {
+		logger.Warningf("Failed to check loaded kernel modules: %v", err)
+		return fmt.Errorf("%v not found", nvidiactlPath)
+	}
+	if len(missing) == 0 {
+		logger.Debugf("%v not found but kernel modules %v are loaded", nvidiactlPath, requiredKernelModules)
+		return createControlDeviceNodes(logger, cfg, driverRoot)
+	}
+
+	if !cfg.NVIDIAContainerRuntimeConfig.Modes.CDI.LoadKernelModules {
+		return fmt.Errorf("%v not found: %w: %v; load them using modprobe or set nvidia-container-runtime.modes.cdi.load-kernel-modules = true", nvidiactlPath, errKernelModulesNotLoaded, missing)
+	}
+
+	for _, module := range missing {
+		logger.Infof("Loading missing kernel module %v", module)
+		if err := modules.Load(module); err != nil {
+			return fmt.Errorf("%v not found: failed to load kernel module %v: %w", nvidiactlPath, module, err)
+		}
+	}
+	return createControlDeviceNodes(logger, cfg, driverRoot)
+}
+
+// createControlDeviceNodes requests the creation of the NVIDIA control device
+// nodes from the device node helper listening on the configured socket. This
+// allows the device nodes to be created without the runtime requiring the
+// privileges to do so. If no socket is configured, this is a no-op.
+func createControlDeviceNodes(logger logger.Interface, cfg *config.Config, driverRoot string) error {
+	socket := cfg.NVIDIAContainerRuntimeConfig.Modes.CDI.DeviceNodeHelperSocket
+	if socket == "" {
+		return nil
+	}
+
+	devices, err := nvdevices.New(
+		nvdevices.WithLogger(logger),
+		nvdevices.WithDevRoot(driverRoot),
+		nvdevices.WithHelperSocket(socket),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create device node library: %w", err)
+	}
+	logger.Infof("Creating control device nodes using helper at %v", socket)
+	if err := devices.CreateNVIDIAControlDevices(); err != nil {
+		return fmt.Errorf("failed to create control device nodes: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"path/filepath"
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+)
+
+func TestCheckKernelModules(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	testCases := []struct {
+		description             string
+		usesNVIDIAKernelModules bool
+		expectedError           bool
+	}{
+		{
+			description:             "kernel modules are not checked on wsl or tegra",
+			usesNVIDIAKernelModules: false,
+		},
+		{
+			description:             "missing kernel modules raise error",
+			usesNVIDIAKernelModules: true,
+			expectedError:           true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			defer setUsesNVIDIAKernelModules(tc.usesNVIDIAKernelModules)()
+
+			// An empty driver root contains neither /dev/nvidiactl nor any
+			// loaded kernel modules.
+			cfg := &config.Config{}
+			cfg.NVIDIAContainerCLIConfig.Root = t.TempDir()
+
+			err := checkKernelModules(logger, cfg)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestCreateControlDeviceNodes(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	testCases := []struct {
+		description   string
+		helperSocket  string
+		expectedError bool
+	}{
+		{
+			description: "no helper socket is a no-op",
+		},
+		{
+			description:   "unreachable helper socket raises error",
+			helperSocket:  filepath.Join(t.TempDir(), "missing.sock"),
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.NVIDIAContainerRuntimeConfig.Modes.CDI.DeviceNodeHelperSocket = tc.helperSocket
+
+			err := createControlDeviceNodes(logger, cfg, t.TempDir())
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func setUsesNVIDIAKernelModules(uses bool) func() {
+	original := usesNVIDIAKernelModules
+	usesNVIDIAKernelModules = func(logger.Interface, string) bool {
+		return uses
+	}
+	return func() {
+		usesNVIDIAKernelModules = original
+	}
+}
@@ -0,0 +1,167 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/ldconfig"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/oci"
+)
+
+// ldcacheFoldersFilename is the name of the file in the container bundle
+// that lists the folders of an update-ldcache hook.
+const ldcacheFoldersFilename = "nvidia-ldcache-folders"
+
+// ldcacheUpdateMerger merges the update-ldcache hooks in an OCI spec.
+type ldcacheUpdateMerger struct {
+	logger    logger.Interface
+	bundleDir string
+}
+
+var _ oci.SpecModifier = (*ldcacheUpdateMerger)(nil)
+
+// NewLDCacheUpdateMerger creates a modifier that merges update-ldcache hooks
+// that only differ in the folders that they add to the ldcache. This is the
+// case if multiple sources (e.g. CSV files and the driver libraries of
+// discrete GPUs) inject libraries into a container. The folders are
+// deduplicated so that the merged hook has as few arguments as possible and
+// ldconfig is only run once.
+//
+// If a bundle directory is specified, the folders of each update-ldcache hook
+// are written to a file in the bundle and passed to the hook using the
+// --folders-file flag instead of as arguments. This ensures that the argument
+// limits are not exceeded if a large number of folders is injected.
+func NewLDCacheUpdateMerger(logger logger.Interface, bundleDir string) oci.SpecModifier {
+	if bundleDir != "" {
+		if absBundleDir, err := filepath.Abs(bundleDir); err == nil {
+			bundleDir = absBundleDir
+		}
+	}
+	return &ldcacheUpdateMerger{
+		logger:    logger,
+		bundleDir: bundleDir,
+	}
+}
+
+// Modify merges the update-ldcache hooks of each lifecycle stage. The merged
+// hook replaces the last of the merged hooks so that it runs after all the
+// hooks that preceded the original hooks.
+func (m *ldcacheUpdateMerger) Modify(spec *specs.Spec) error {
+	if spec == nil || spec.Hooks == nil {
+		return nil
+	}
+	spec.Hooks.Prestart = m.merge(spec.Hooks.Prestart) //nolint:staticcheck
+	spec.Hooks.CreateRuntime = m.merge(spec.Hooks.CreateRuntime)
+	spec.Hooks.CreateContainer = m.merge(spec.Hooks.CreateContainer)
+	spec.Hooks.StartContainer = m.merge(spec.Hooks.StartContainer)
+
+	if m.bundleDir == "" {
+		return nil
+	}
+	var filesWritten int
+	for _, hooks := range [][]specs.Hook{
+		spec.Hooks.Prestart, //nolint:staticcheck
+		spec.Hooks.CreateRuntime,
+		spec.Hooks.CreateContainer,
+		spec.Hooks.StartContainer,
+	} {
+		for i := range hooks {
+			if m.useFoldersFile(&hooks[i], filesWritten) {
+				filesWritten++
+			}
+		}
+	}
+	return nil
+}
+
+// useFoldersFile writes the folders of the specified update-ldcache hook to a
+// file in the bundle directory and replaces the --folder arguments of the
+// hook by a --folders-file argument. If the file cannot be written, the hook
+// is not modified.
+func (m *ldcacheUpdateMerger) useFoldersFile(hook *specs.Hook, index int) bool {
+	args, folders, isUpdate := splitLDCacheUpdateArgs(*hook)
+	if !isUpdate || len(folders) == 0 {
+		return false
+	}
+
+	filename := ldcacheFoldersFilename
+	if index > 0 {
+		filename = fmt.Sprintf("%s.%d", filename, index)
+	}
+	path := filepath.Join(m.bundleDir, filename)
+	if err := ldconfig.WriteFoldersFile(path, ldconfig.UniqueFolders(folders)); err != nil {
+		m.logger.Warningf("Failed to write update-ldcache folders to the bundle: %v", err)
+		return false
+	}
+	hook.Args = append(args, "--folders-file", path)
+	return true
+}
+
+func (m *ldcacheUpdateMerger) merge(hooks []specs.Hook) []specs.Hook {
+	type group struct {
+		args    []string
+		folders []string
+		last    int
+		count   int
+	}
+	groups := make(map[string]*group)
+	keys := make([]string, len(hooks))
+	for i, hook := range hooks {
+		args, folders, isUpdate := splitLDCacheUpdateArgs(hook)
+		if !isUpdate {
+			continue
+		}
+		key := strings.Join(append([]string{hook.Path}, args...), "\x00") + "\x01" + strings.Join(hook.Env, "\x00")
+		keys[i] = key
+		g, ok := groups[key]
+		if !ok {
+			g = &group{args: args}
+			groups[key] = g
+		}
+		g.folders = append(g.folders, folders...)
+		g.last = i
+		g.count++
+	}
+
+	var merged []specs.Hook
+	for i, hook := range hooks {
+		g := groups[keys[i]]
+		if g == nil || g.count == 1 && len(ldconfig.UniqueFolders(g.folders)) == len(g.folders) {
+			merged = append(merged, hook)
+			continue
+		}
+		if i != g.last {
+			continue
+		}
+		folders := ldconfig.UniqueFolders(g.folders)
+		m.logger.Debugf("Merging %d update-ldcache hooks for %d folders", g.count, len(folders))
+		args := slices.Clone(g.args)
+		for _, folder := range folders {
+			args = append(args, "--folder", folder)
+		}
+		hook.Args = args
+		merged = append(merged, hook)
+	}
+	return merged
+}
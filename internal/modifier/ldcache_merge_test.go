@@ -0,0 +1,173 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/ldconfig"
+)
+
+func TestLDCacheUpdateMerger(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	symlinksHook := specs.Hook{
+		Path: "/usr/bin/nvidia-cdi-hook",
+		Args: []string{"nvidia-cdi-hook", "create-symlinks", "--link", "libcuda.so.1::/usr/lib64/libcuda.so"},
+	}
+
+	testCases := []struct {
+		description   string
+		hooks         []specs.Hook
+		expectedHooks []specs.Hook
+	}{
+		{
+			description: "single hook is not modified",
+			hooks: []specs.Hook{
+				{
+					Path: "/usr/bin/nvidia-cdi-hook",
+					Args: []string{"nvidia-cdi-hook", "update-ldcache", "--folder", "/usr/lib64", "--ldconfig-path", "/sbin/ldconfig"},
+				},
+				symlinksHook,
+			},
+			expectedHooks: []specs.Hook{
+				{
+					Path: "/usr/bin/nvidia-cdi-hook",
+					Args: []string{"nvidia-cdi-hook", "update-ldcache", "--folder", "/usr/lib64", "--ldconfig-path", "/sbin/ldconfig"},
+				},
+				symlinksHook,
+			},
+		},
+		{
+			description: "duplicate folders in single hook are removed",
+			hooks: []specs.Hook{
+				{
+					Path: "/usr/bin/nvidia-cdi-hook",
+					Args: []string{"nvidia-cdi-hook", "update-ldcache", "--folder", "/usr/lib64", "--folder", "/usr/lib64/"},
+				},
+			},
+			expectedHooks: []specs.Hook{
+				{
+					Path: "/usr/bin/nvidia-cdi-hook",
+					Args: []string{"nvidia-cdi-hook", "update-ldcache", "--folder", "/usr/lib64"},
+				},
+			},
+		},
+		{
+			description: "matching hooks are merged into last hook",
+			hooks: []specs.Hook{
+				{
+					Path: "/usr/bin/nvidia-cdi-hook",
+					Args: []string{"nvidia-cdi-hook", "update-ldcache", "--folder", "/usr/lib/aarch64-linux-gnu/tegra", "--folder", "/usr/lib/aarch64-linux-gnu"},
+				},
+				symlinksHook,
+				{
+					Path: "/usr/bin/nvidia-cdi-hook",
+					Args: []string{"nvidia-cdi-hook", "update-ldcache", "--folder", "/usr/lib/aarch64-linux-gnu", "--folder=/usr/lib/aarch64-linux-gnu/nvidia"},
+				},
+			},
+			expectedHooks: []specs.Hook{
+				symlinksHook,
+				{
+					Path: "/usr/bin/nvidia-cdi-hook",
+					Args: []string{
+						"nvidia-cdi-hook", "update-ldcache",
+						"--folder", "/usr/lib/aarch64-linux-gnu/tegra",
+						"--folder", "/usr/lib/aarch64-linux-gnu",
+						"--folder", "/usr/lib/aarch64-linux-gnu/nvidia",
+					},
+				},
+			},
+		},
+		{
+			description: "hooks with different args are not merged",
+			hooks: []specs.Hook{
+				{
+					Path: "/usr/bin/nvidia-cdi-hook",
+					Args: []string{"nvidia-cdi-hook", "update-ldcache", "--folder", "/usr/lib64"},
+				},
+				{
+					Path: "/usr/bin/nvidia-cdi-hook",
+					Args: []string{"nvidia-cdi-hook", "update-ldcache", "--folder", "/usr/lib", "--skip-ldcache-creation"},
+				},
+			},
+			expectedHooks: []specs.Hook{
+				{
+					Path: "/usr/bin/nvidia-cdi-hook",
+					Args: []string{"nvidia-cdi-hook", "update-ldcache", "--folder", "/usr/lib64"},
+				},
+				{
+					Path: "/usr/bin/nvidia-cdi-hook",
+					Args: []string{"nvidia-cdi-hook", "update-ldcache", "--folder", "/usr/lib", "--skip-ldcache-creation"},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			spec := &specs.Spec{
+				Hooks: &specs.Hooks{
+					CreateContainer: tc.hooks,
+				},
+			}
+			require.NoError(t, NewLDCacheUpdateMerger(logger, "").Modify(spec))
+			require.EqualValues(t, tc.expectedHooks, spec.Hooks.CreateContainer)
+		})
+	}
+}
+
+func TestLDCacheUpdateMergerWithBundleDir(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	bundleDir := t.TempDir()
+	spec := &specs.Spec{
+		Hooks: &specs.Hooks{
+			CreateContainer: []specs.Hook{
+				{
+					Path: "/usr/bin/nvidia-cdi-hook",
+					Args: []string{"nvidia-cdi-hook", "update-ldcache", "--folder", "/usr/lib64", "--ldconfig-path", "/sbin/ldconfig"},
+				},
+				{
+					Path: "/usr/bin/nvidia-cdi-hook",
+					Args: []string{"nvidia-cdi-hook", "update-ldcache", "--folder", "/usr/lib64", "--folder", "/usr/lib/aarch64-linux-gnu/tegra", "--ldconfig-path", "/sbin/ldconfig"},
+				},
+			},
+		},
+	}
+	require.NoError(t, NewLDCacheUpdateMerger(logger, bundleDir).Modify(spec))
+
+	foldersFile := filepath.Join(bundleDir, ldcacheFoldersFilename)
+	require.EqualValues(t,
+		[]specs.Hook{
+			{
+				Path: "/usr/bin/nvidia-cdi-hook",
+				Args: []string{"nvidia-cdi-hook", "update-ldcache", "--ldconfig-path", "/sbin/ldconfig", "--folders-file", foldersFile},
+			},
+		},
+		spec.Hooks.CreateContainer,
+	)
+
+	folders, err := ldconfig.ReadFoldersFile(foldersFile)
+	require.NoError(t, err)
+	require.EqualValues(t, []string{"/usr/lib64", "/usr/lib/aarch64-linux-gnu/tegra"}, folders)
+}
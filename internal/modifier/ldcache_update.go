@@ -0,0 +1,181 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/discover"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/info"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/oci"
+)
+
+const (
+	// DisableLDCacheHookAnnotation overrides whether the update-ldcache hook
+	// is disabled for a container. Image labels can be propagated to this
+	// annotation by the container engine or orchestrator.
+	DisableLDCacheHookAnnotation = "nvidia.com/disable-ldcache-hook"
+	// DisableLDCacheHookEnvvar overrides whether the update-ldcache hook is
+	// disabled for a container. This is typically set in the image.
+	DisableLDCacheHookEnvvar = "NVIDIA_DISABLE_LDCACHE_HOOK"
+
+	ldLibraryPathEnvvar = "LD_LIBRARY_PATH"
+)
+
+// ldcacheHookDisabler replaces the update-ldcache hook with LD_LIBRARY_PATH
+// edits for containers that are known to honor LD_LIBRARY_PATH.
+type ldcacheHookDisabler struct {
+	logger         logger.Interface
+	disableDefault bool
+}
+
+var _ oci.SpecModifier = (*ldcacheHookDisabler)(nil)
+
+// NewLDCacheHookDisabler creates a modifier that removes the update-ldcache
+// hook from the OCI spec and instead adds the injected library folders to
+// LD_LIBRARY_PATH in the container. Whether this is done is controlled by the
+// disable-ldcache-hook feature and can be overridden for a specific container
+// using the NVIDIA_DISABLE_LDCACHE_HOOK envvar or the
+// nvidia.com/disable-ldcache-hook annotation.
+//
+// In legacy mode the ldcache is updated by the nvidia-container-cli and a nil
+// modifier is returned.
+func NewLDCacheHookDisabler(logger logger.Interface, cfg *config.Config) oci.SpecModifier {
+	if info.RuntimeMode(cfg.NVIDIAContainerRuntimeConfig.Mode) == info.LegacyRuntimeMode {
+		return nil
+	}
+	return &ldcacheHookDisabler{
+		logger:         logger,
+		disableDefault: cfg.Features.DisableLDCacheHook.IsEnabled(),
+	}
+}
+
+// Modify removes the update-ldcache hooks from the spec if the ldcache update
+// hook is disabled and prepends the folders that these hooks would have
+// added to the ldcache to LD_LIBRARY_PATH.
+func (m *ldcacheHookDisabler) Modify(spec *specs.Spec) error {
+	if spec == nil || spec.Hooks == nil || spec.Process == nil {
+		return nil
+	}
+	if !m.disableLDCacheHook(spec) {
+		return nil
+	}
+
+	var folders []string
+	removeHooks := func(hooks []specs.Hook) []specs.Hook {
+		var kept []specs.Hook
+		for _, hook := range hooks {
+			hookFolders, isUpdate := getLDCacheUpdateFolders(hook)
+			if !isUpdate {
+				kept = append(kept, hook)
+				continue
+			}
+			folders = append(folders, hookFolders...)
+		}
+		return kept
+	}
+	spec.Hooks.Prestart = removeHooks(spec.Hooks.Prestart) //nolint:staticcheck
+	spec.Hooks.CreateRuntime = removeHooks(spec.Hooks.CreateRuntime)
+	spec.Hooks.CreateContainer = removeHooks(spec.Hooks.CreateContainer)
+	spec.Hooks.StartContainer = removeHooks(spec.Hooks.StartContainer)
+
+	if len(folders) == 0 {
+		return nil
+	}
+
+	var existing []string
+	for _, e := range spec.Process.Env {
+		if value, ok := strings.CutPrefix(e, ldLibraryPathEnvvar+"="); ok && value != "" {
+			existing = strings.Split(value, ":")
+		}
+	}
+	var paths []string
+	for _, path := range append(folders, existing...) {
+		if slices.Contains(paths, path) {
+			continue
+		}
+		paths = append(paths, path)
+	}
+
+	m.logger.Debugf("Disabling update-ldcache hook; setting %v to %v", ldLibraryPathEnvvar, paths)
+	spec.Process.Env = setEnvvar(spec.Process.Env, ldLibraryPathEnvvar, strings.Join(paths, ":"))
+	return nil
+}
+
+// disableLDCacheHook determines whether the update-ldcache hook should be
+// disabled for the specified spec. The annotation takes precedence over the envvar,
+// which takes precedence over the configured default.
+func (m *ldcacheHookDisabler) disableLDCacheHook(spec *specs.Spec) bool {
+	if value, ok := spec.Annotations[DisableLDCacheHookAnnotation]; ok {
+		if skip, err := strconv.ParseBool(value); err == nil {
+			return skip
+		}
+		m.logger.Warningf("Ignoring invalid %v annotation %q", DisableLDCacheHookAnnotation, value)
+	}
+	for _, e := range spec.Process.Env {
+		value, ok := strings.CutPrefix(e, DisableLDCacheHookEnvvar+"=")
+		if !ok {
+			continue
+		}
+		if skip, err := strconv.ParseBool(value); err == nil {
+			return skip
+		}
+		m.logger.Warningf("Ignoring invalid %v envvar %q", DisableLDCacheHookEnvvar, value)
+	}
+	return m.disableDefault
+}
+
+// getLDCacheUpdateFolders checks whether the specified hook is an
+// update-ldcache hook and returns the folders that it adds to the ldcache.
+func getLDCacheUpdateFolders(hook specs.Hook) ([]string, bool) {
+	_, folders, isUpdate := splitLDCacheUpdateArgs(hook)
+	return folders, isUpdate
+}
+
+// splitLDCacheUpdateArgs checks whether the specified hook is an
+// update-ldcache hook and splits its arguments into the folders that are
+// added to the ldcache and the remaining arguments.
+func splitLDCacheUpdateArgs(hook specs.Hook) ([]string, []string, bool) {
+	// The hook name follows the executable name and, for the nvidia-ctk, the
+	// hook subcommand.
+	idx := slices.Index(hook.Args[:min(3, len(hook.Args))], string(discover.UpdateLDCacheHook))
+	if idx < 0 {
+		return nil, nil, false
+	}
+	args := slices.Clone(hook.Args[:idx+1])
+	var folders []string
+	for i := idx + 1; i < len(hook.Args); i++ {
+		arg := hook.Args[i]
+		if folder, ok := strings.CutPrefix(arg, "--folder="); ok {
+			folders = append(folders, folder)
+			continue
+		}
+		if arg == "--folder" && i+1 < len(hook.Args) {
+			folders = append(folders, hook.Args[i+1])
+			i++
+			continue
+		}
+		args = append(args, arg)
+	}
+	return args, folders, true
+}
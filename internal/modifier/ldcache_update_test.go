@@ -0,0 +1,156 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"testing"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
+)
+
+func TestLDCacheHookDisabler(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	updateHook := specs.Hook{
+		Path: "/usr/bin/nvidia-cdi-hook",
+		Args: []string{"nvidia-cdi-hook", "update-ldcache", "--folder", "/usr/lib64", "--folder=/usr/lib64/vdpau"},
+	}
+	symlinksHook := specs.Hook{
+		Path: "/usr/bin/nvidia-cdi-hook",
+		Args: []string{"nvidia-cdi-hook", "create-symlinks", "--link", "libcuda.so.1::/usr/lib64/libcuda.so"},
+	}
+	nvidiaCTKUpdateHook := specs.Hook{
+		Path: "/usr/bin/nvidia-ctk",
+		Args: []string{"nvidia-ctk", "hook", "update-ldcache", "--folder", "/usr/lib/x86_64-linux-gnu"},
+	}
+
+	testCases := []struct {
+		description    string
+		mode           string
+		disableDefault bool
+		spec           *specs.Spec
+		expectedNil    bool
+		expectedSpec   *specs.Spec
+	}{
+		{
+			description:    "legacy mode returns nil modifier",
+			mode:           "legacy",
+			disableDefault: true,
+			expectedNil:    true,
+		},
+		{
+			description: "hook is kept by default",
+			mode:        "cdi",
+			spec: &specs.Spec{
+				Process: &specs.Process{Env: []string{"PATH=/usr/bin"}},
+				Hooks:   &specs.Hooks{CreateContainer: []specs.Hook{symlinksHook, updateHook}},
+			},
+			expectedSpec: &specs.Spec{
+				Process: &specs.Process{Env: []string{"PATH=/usr/bin"}},
+				Hooks:   &specs.Hooks{CreateContainer: []specs.Hook{symlinksHook, updateHook}},
+			},
+		},
+		{
+			description:    "feature replaces hook with LD_LIBRARY_PATH",
+			mode:           "cdi",
+			disableDefault: true,
+			spec: &specs.Spec{
+				Process: &specs.Process{Env: []string{"PATH=/usr/bin"}},
+				Hooks:   &specs.Hooks{CreateContainer: []specs.Hook{symlinksHook, updateHook}},
+			},
+			expectedSpec: &specs.Spec{
+				Process: &specs.Process{Env: []string{"PATH=/usr/bin", "LD_LIBRARY_PATH=/usr/lib64:/usr/lib64/vdpau"}},
+				Hooks:   &specs.Hooks{CreateContainer: []specs.Hook{symlinksHook}},
+			},
+		},
+		{
+			description:    "existing LD_LIBRARY_PATH is preserved",
+			mode:           "jit-cdi",
+			disableDefault: true,
+			spec: &specs.Spec{
+				Process: &specs.Process{Env: []string{"LD_LIBRARY_PATH=/opt/lib:/usr/lib64"}},
+				Hooks:   &specs.Hooks{CreateContainer: []specs.Hook{updateHook, nvidiaCTKUpdateHook}},
+			},
+			expectedSpec: &specs.Spec{
+				Process: &specs.Process{Env: []string{"LD_LIBRARY_PATH=/usr/lib64:/usr/lib64/vdpau:/usr/lib/x86_64-linux-gnu:/opt/lib"}},
+				Hooks:   &specs.Hooks{},
+			},
+		},
+		{
+			description: "envvar disables hook",
+			mode:        "cdi",
+			spec: &specs.Spec{
+				Process: &specs.Process{Env: []string{"NVIDIA_DISABLE_LDCACHE_HOOK=true"}},
+				Hooks:   &specs.Hooks{CreateContainer: []specs.Hook{updateHook}},
+			},
+			expectedSpec: &specs.Spec{
+				Process: &specs.Process{Env: []string{"NVIDIA_DISABLE_LDCACHE_HOOK=true", "LD_LIBRARY_PATH=/usr/lib64:/usr/lib64/vdpau"}},
+				Hooks:   &specs.Hooks{},
+			},
+		},
+		{
+			description: "annotation takes precedence over envvar",
+			mode:        "cdi",
+			spec: &specs.Spec{
+				Annotations: map[string]string{"nvidia.com/disable-ldcache-hook": "false"},
+				Process:     &specs.Process{Env: []string{"NVIDIA_DISABLE_LDCACHE_HOOK=true"}},
+				Hooks:       &specs.Hooks{CreateContainer: []specs.Hook{updateHook}},
+			},
+			expectedSpec: &specs.Spec{
+				Annotations: map[string]string{"nvidia.com/disable-ldcache-hook": "false"},
+				Process:     &specs.Process{Env: []string{"NVIDIA_DISABLE_LDCACHE_HOOK=true"}},
+				Hooks:       &specs.Hooks{CreateContainer: []specs.Hook{updateHook}},
+			},
+		},
+		{
+			description:    "invalid envvar uses default",
+			mode:           "cdi",
+			disableDefault: true,
+			spec: &specs.Spec{
+				Process: &specs.Process{Env: []string{"NVIDIA_DISABLE_LDCACHE_HOOK=sometimes"}},
+				Hooks:   &specs.Hooks{CreateContainer: []specs.Hook{updateHook}},
+			},
+			expectedSpec: &specs.Spec{
+				Process: &specs.Process{Env: []string{"NVIDIA_DISABLE_LDCACHE_HOOK=sometimes", "LD_LIBRARY_PATH=/usr/lib64:/usr/lib64/vdpau"}},
+				Hooks:   &specs.Hooks{},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			toml, err := config.New()
+			require.NoError(t, err)
+			toml.Set("nvidia-container-runtime.mode", tc.mode)
+			toml.Set("features.disable-ldcache-hook", tc.disableDefault)
+			cfg, err := toml.Config()
+			require.NoError(t, err)
+
+			m := NewLDCacheHookDisabler(logger, cfg)
+			if tc.expectedNil {
+				require.Nil(t, m)
+				return
+			}
+			require.NoError(t, m.Modify(tc.spec))
+			require.EqualValues(t, tc.expectedSpec, tc.spec)
+		})
+	}
+}
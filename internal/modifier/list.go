@@ -19,7 +19,7 @@ package modifier
 import (
 	"github.com/opencontainers/runtime-spec/specs-go"
 
-	"github.com/NVIDIA/nvidia-container-toolkit/internal/oci"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/oci"
 )
 
 type List []oci.SpecModifier
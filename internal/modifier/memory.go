@@ -0,0 +1,94 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+)
+
+// getRequestedDeviceMemory returns the total memory of the requested device
+// with the least memory as reported by NVML. Device requests that cannot be
+// mapped to an NVML device (e.g. CDI device names) are ignored.
+func getRequestedDeviceMemory(nvmllib nvml.Interface, devices []string) (uint64, error) {
+	if ret := nvmllib.Init(); ret != nvml.SUCCESS {
+		return 0, fmt.Errorf("failed to initialize NVML: %v", ret)
+	}
+	defer func() {
+		_ = nvmllib.Shutdown()
+	}()
+
+	handles, err := getRequestedDeviceHandles(nvmllib, devices)
+	if err != nil {
+		return 0, err
+	}
+	if len(handles) == 0 {
+		return 0, fmt.Errorf("no NVML devices found for %v", devices)
+	}
+
+	var minimum uint64
+	for i, handle := range handles {
+		memory, ret := handle.GetMemoryInfo()
+		if ret != nvml.SUCCESS {
+			return 0, fmt.Errorf("failed to get memory info: %v", ret)
+		}
+		if i == 0 || memory.Total < minimum {
+			minimum = memory.Total
+		}
+	}
+	return minimum, nil
+}
+
+func getRequestedDeviceHandles(nvmllib nvml.Interface, devices []string) ([]nvml.Device, error) {
+	var handles []nvml.Device
+	for _, device := range devices {
+		switch {
+		case device == "all":
+			count, ret := nvmllib.DeviceGetCount()
+			if ret != nvml.SUCCESS {
+				return nil, fmt.Errorf("failed to get device count: %v", ret)
+			}
+			for i := 0; i < count; i++ {
+				handle, ret := nvmllib.DeviceGetHandleByIndex(i)
+				if ret != nvml.SUCCESS {
+					return nil, fmt.Errorf("failed to get device %d: %v", i, ret)
+				}
+				handles = append(handles, handle)
+			}
+		case strings.HasPrefix(device, "GPU-"), strings.HasPrefix(device, "MIG-"):
+			handle, ret := nvmllib.DeviceGetHandleByUUID(device)
+			if ret != nvml.SUCCESS {
+				return nil, fmt.Errorf("failed to get device %v: %v", device, ret)
+			}
+			handles = append(handles, handle)
+		default:
+			index, err := strconv.Atoi(device)
+			if err != nil {
+				continue
+			}
+			handle, ret := nvmllib.DeviceGetHandleByIndex(index)
+			if ret != nvml.SUCCESS {
+				return nil, fmt.Errorf("failed to get device %v: %v", device, ret)
+			}
+			handles = append(handles, handle)
+		}
+	}
+	return handles, nil
+}
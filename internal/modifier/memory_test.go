@@ -0,0 +1,100 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"testing"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/NVIDIA/go-nvml/pkg/nvml/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRequestedDeviceMemory(t *testing.T) {
+	newDevice := func(total uint64) nvml.Device {
+		return &mock.Device{
+			GetMemoryInfoFunc: func() (nvml.Memory, nvml.Return) {
+				return nvml.Memory{Total: total}, nvml.SUCCESS
+			},
+		}
+	}
+	devices := []nvml.Device{newDevice(80 << 30), newDevice(16 << 30)}
+	nvmllib := &mock.Interface{
+		InitFunc:     func() nvml.Return { return nvml.SUCCESS },
+		ShutdownFunc: func() nvml.Return { return nvml.SUCCESS },
+		DeviceGetCountFunc: func() (int, nvml.Return) {
+			return len(devices), nvml.SUCCESS
+		},
+		DeviceGetHandleByIndexFunc: func(n int) (nvml.Device, nvml.Return) {
+			if n >= len(devices) {
+				return nil, nvml.ERROR_INVALID_ARGUMENT
+			}
+			return devices[n], nvml.SUCCESS
+		},
+		DeviceGetHandleByUUIDFunc: func(uuid string) (nvml.Device, nvml.Return) {
+			if uuid != "GPU-0" {
+				return nil, nvml.ERROR_NOT_FOUND
+			}
+			return devices[0], nvml.SUCCESS
+		},
+	}
+
+	testCases := []struct {
+		description    string
+		devices        []string
+		expectedError  bool
+		expectedMemory uint64
+	}{
+		{
+			description:    "single device by index",
+			devices:        []string{"0"},
+			expectedMemory: 80 << 30,
+		},
+		{
+			description:    "single device by UUID",
+			devices:        []string{"GPU-0"},
+			expectedMemory: 80 << 30,
+		},
+		{
+			description:    "minimum across all devices",
+			devices:        []string{"all"},
+			expectedMemory: 16 << 30,
+		},
+		{
+			description:   "unknown device is an error",
+			devices:       []string{"GPU-1"},
+			expectedError: true,
+		},
+		{
+			description:   "no NVML devices is an error",
+			devices:       []string{"nvidia.com/gpu=0"},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			memory, err := getRequestedDeviceMemory(nvmllib, tc.devices)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedMemory, memory)
+		})
+	}
+}
@@ -0,0 +1,196 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config/image"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/librarygroups"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup/root"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/oci"
+)
+
+const (
+	// MissingCapabilityPolicyFail rejects containers that request a driver
+	// capability for which no libraries are found.
+	MissingCapabilityPolicyFail = "fail"
+	// MissingCapabilityPolicyDowngrade removes driver capabilities for which
+	// no libraries are found from the requested capabilities.
+	MissingCapabilityPolicyDowngrade = "downgrade"
+
+	// DowngradedDriverCapabilitiesAnnotation records the requested driver
+	// capabilities that were removed since their libraries are missing on the
+	// host.
+	DowngradedDriverCapabilitiesAnnotation = "nvidia.com/downgraded-driver-capabilities"
+)
+
+// capabilityDowngrader updates the requested driver capabilities of a
+// container.
+type capabilityDowngrader struct {
+	logger       logger.Interface
+	capabilities image.DriverCapabilities
+	downgraded   []string
+}
+
+var _ oci.SpecModifier = (*capabilityDowngrader)(nil)
+
+// NewCapabilityDowngrader applies the configured missing capability policy
+// to the driver capabilities requested by the specified container. A
+// capability is considered missing if none of the libraries in its library
+// group are found in the specified driver root.
+//
+// If capabilities are downgraded, an image with the remaining capabilities is
+// returned together with a modifier that updates the NVIDIA_DRIVER_CAPABILITIES
+// envvar and records the downgraded capabilities as an annotation. Otherwise
+// the container is returned as is and the modifier is nil.
+func NewCapabilityDowngrader(logger logger.Interface, cfg *config.Config, driver *root.Driver, container image.CUDA) (image.CUDA, oci.SpecModifier, error) {
+	policies := cfg.NVIDIAContainerRuntimeConfig.MissingCapabilityPolicy
+	if len(policies) == 0 {
+		return container, nil, nil
+	}
+	if err := validateMissingCapabilityPolicy(policies); err != nil {
+		return container, nil, err
+	}
+	if len(container.VisibleDevices()) == 0 {
+		return container, nil, nil
+	}
+
+	groups, err := librarygroups.Load(cfg.NVIDIAContainerRuntimeConfig.LibraryGroupsFile)
+	if err != nil {
+		return container, nil, err
+	}
+
+	requested := getRequestedDriverCapabilities(cfg, container)
+	libraries := driver.Libraries()
+
+	var missing []string
+	var downgraded []string
+	remaining := make(image.DriverCapabilities)
+	for _, c := range requested.List() {
+		capability := image.DriverCapability(c)
+		remaining[capability] = true
+
+		policy, ok := policies[c]
+		if !ok {
+			continue
+		}
+		if hasLibraries(logger, libraries, groups[capability]) {
+			continue
+		}
+		if policy == MissingCapabilityPolicyFail {
+			missing = append(missing, c)
+			continue
+		}
+		logger.Warningf("Removing driver capability %q since no libraries were found", c)
+		delete(remaining, capability)
+		downgraded = append(downgraded, c)
+	}
+	if len(missing) > 0 {
+		return container, nil, fmt.Errorf("no libraries found for requested driver capabilities %v", missing)
+	}
+	if len(downgraded) == 0 {
+		return container, nil, nil
+	}
+	if len(remaining) == 0 {
+		remaining[image.DriverCapabilityNone] = true
+	}
+
+	m := &capabilityDowngrader{
+		logger:       logger,
+		capabilities: remaining,
+		downgraded:   downgraded,
+	}
+	return container.WithDriverCapabilities(remaining), m, nil
+}
+
+// Modify sets the NVIDIA_DRIVER_CAPABILITIES envvar to the remaining
+// capabilities and records the downgraded capabilities as an annotation.
+func (m *capabilityDowngrader) Modify(spec *specs.Spec) error {
+	if spec == nil {
+		return nil
+	}
+	if spec.Process != nil {
+		spec.Process.Env = setEnvvar(spec.Process.Env, image.EnvVarNvidiaDriverCapabilities, m.capabilities.String())
+	}
+	if spec.Annotations == nil {
+		spec.Annotations = make(map[string]string)
+	}
+	spec.Annotations[DowngradedDriverCapabilitiesAnnotation] = strings.Join(m.downgraded, ",")
+	return nil
+}
+
+// validateMissingCapabilityPolicy checks that the policies are defined for
+// supported driver capabilities and are valid.
+func validateMissingCapabilityPolicy(policies map[string]string) error {
+	for c, policy := range policies {
+		if c == string(image.DriverCapabilityAll) || !image.SupportedDriverCapabilities.Has(image.DriverCapability(c)) {
+			return fmt.Errorf("unsupported driver capability %q in missing capability policy", c)
+		}
+		switch policy {
+		case MissingCapabilityPolicyFail, MissingCapabilityPolicyDowngrade:
+		default:
+			return fmt.Errorf("invalid missing capability policy %q for driver capability %q", policy, c)
+		}
+	}
+	return nil
+}
+
+// getRequestedDriverCapabilities returns the driver capabilities requested by
+// the container. A request for all capabilities is expanded to the supported
+// capabilities and the default capabilities are returned if none are
+// requested.
+func getRequestedDriverCapabilities(cfg *config.Config, container image.CUDA) image.DriverCapabilities {
+	supported := image.NewDriverCapabilities(cfg.SupportedDriverCapabilities)
+	if supported.IsAll() || len(supported) == 0 {
+		supported = image.SupportedDriverCapabilities
+	}
+
+	requested := container.GetDriverCapabilities()
+	delete(requested, "")
+	switch {
+	case requested.IsAll():
+		return supported
+	case len(requested) == 0:
+		return supported.Intersection(image.DefaultDriverCapabilities)
+	}
+	return requested
+}
+
+// hasLibraries checks whether any of the libraries matching the specified
+// patterns can be located. Capabilities without libraries (e.g. display) are
+// always considered present.
+func hasLibraries(logger logger.Interface, libraries lookup.Locator, patterns []string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(patterns, func(pattern string) bool {
+		located, err := libraries.Locate(pattern)
+		if err != nil {
+			logger.Debugf("Failed to locate %v: %v", pattern, err)
+			return false
+		}
+		return len(located) > 0
+	})
+}
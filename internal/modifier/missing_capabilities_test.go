@@ -0,0 +1,158 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config/image"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup/root"
+)
+
+func TestCapabilityDowngrader(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	driverRoot := t.TempDir()
+	libDir := filepath.Join(driverRoot, "usr/lib64")
+	require.NoError(t, os.MkdirAll(libDir, 0755))
+	for _, lib := range []string{"libcuda.so.999.88.77", "libnvidia-ml.so.999.88.77"} {
+		require.NoError(t, os.WriteFile(filepath.Join(libDir, lib), nil, 0644))
+	}
+	driver := root.New(
+		root.WithLogger(logger),
+		root.WithDriverRoot(driverRoot),
+	)
+
+	testCases := []struct {
+		description          string
+		policy               map[string]string
+		env                  []string
+		expectedError        bool
+		expectedCapabilities string
+		expectedSpec         *specs.Spec
+	}{
+		{
+			description:          "no policy is not checked",
+			env:                  []string{"NVIDIA_VISIBLE_DEVICES=all", "NVIDIA_DRIVER_CAPABILITIES=compute,video"},
+			expectedCapabilities: "compute,video",
+		},
+		{
+			description:          "no devices is not checked",
+			policy:               map[string]string{"video": "fail"},
+			env:                  []string{"NVIDIA_DRIVER_CAPABILITIES=compute,video"},
+			expectedCapabilities: "compute,video",
+		},
+		{
+			description:          "present capabilities are not downgraded",
+			policy:               map[string]string{"compute": "fail", "utility": "downgrade"},
+			env:                  []string{"NVIDIA_VISIBLE_DEVICES=all", "NVIDIA_DRIVER_CAPABILITIES=compute,utility"},
+			expectedCapabilities: "compute,utility",
+		},
+		{
+			description:   "missing capability fails",
+			policy:        map[string]string{"video": "fail"},
+			env:           []string{"NVIDIA_VISIBLE_DEVICES=all", "NVIDIA_DRIVER_CAPABILITIES=compute,video"},
+			expectedError: true,
+		},
+		{
+			description:          "missing capability is downgraded",
+			policy:               map[string]string{"video": "downgrade"},
+			env:                  []string{"NVIDIA_VISIBLE_DEVICES=all", "NVIDIA_DRIVER_CAPABILITIES=compute,video"},
+			expectedCapabilities: "compute",
+			expectedSpec: &specs.Spec{
+				Annotations: map[string]string{"nvidia.com/downgraded-driver-capabilities": "video"},
+				Process: &specs.Process{
+					Env: []string{"NVIDIA_VISIBLE_DEVICES=all", "NVIDIA_DRIVER_CAPABILITIES=compute"},
+				},
+			},
+		},
+		{
+			description:          "all capabilities are expanded",
+			policy:               map[string]string{"video": "downgrade", "ngx": "downgrade"},
+			env:                  []string{"NVIDIA_VISIBLE_DEVICES=all", "NVIDIA_DRIVER_CAPABILITIES=all"},
+			expectedCapabilities: "compat32,compute,display,graphics,profiling,utility",
+			expectedSpec: &specs.Spec{
+				Annotations: map[string]string{"nvidia.com/downgraded-driver-capabilities": "ngx,video"},
+				Process: &specs.Process{
+					Env: []string{"NVIDIA_VISIBLE_DEVICES=all", "NVIDIA_DRIVER_CAPABILITIES=compat32,compute,display,graphics,profiling,utility"},
+				},
+			},
+		},
+		{
+			description:          "all capabilities downgraded requests none",
+			policy:               map[string]string{"video": "downgrade"},
+			env:                  []string{"NVIDIA_VISIBLE_DEVICES=all", "NVIDIA_DRIVER_CAPABILITIES=video"},
+			expectedCapabilities: "none",
+			expectedSpec: &specs.Spec{
+				Annotations: map[string]string{"nvidia.com/downgraded-driver-capabilities": "video"},
+				Process: &specs.Process{
+					Env: []string{"NVIDIA_VISIBLE_DEVICES=all", "NVIDIA_DRIVER_CAPABILITIES=none"},
+				},
+			},
+		},
+		{
+			description:   "invalid policy raises error",
+			policy:        map[string]string{"video": "ignore"},
+			env:           []string{"NVIDIA_VISIBLE_DEVICES=all"},
+			expectedError: true,
+		},
+		{
+			description:   "unsupported capability raises error",
+			policy:        map[string]string{"vidoe": "fail"},
+			env:           []string{"NVIDIA_VISIBLE_DEVICES=all"},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			cfg := &config.Config{
+				SupportedDriverCapabilities: "all",
+				NVIDIAContainerRuntimeConfig: config.RuntimeConfig{
+					MissingCapabilityPolicy: tc.policy,
+				},
+			}
+			spec := &specs.Spec{
+				Process: &specs.Process{Env: tc.env},
+			}
+			container, err := image.NewCUDAImageFromSpec(spec, image.WithLogger(logger))
+			require.NoError(t, err)
+
+			downgraded, m, err := NewCapabilityDowngrader(logger, cfg, driver, container)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedCapabilities, downgraded.Getenv(image.EnvVarNvidiaDriverCapabilities))
+
+			if tc.expectedSpec == nil {
+				require.Nil(t, m)
+				return
+			}
+			require.NoError(t, m.Modify(spec))
+			require.EqualValues(t, tc.expectedSpec, spec)
+		})
+	}
+}
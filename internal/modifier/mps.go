@@ -0,0 +1,131 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config/image"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/oci"
+)
+
+const (
+	// GPUShareAnnotation requests that a container shares the GPUs using the
+	// CUDA Multi-Process Service (MPS). The value is the percentage (1-100) of
+	// the GPU threads that the container may use.
+	GPUShareAnnotation = "nvidia.com/gpu.share"
+
+	defaultMPSPipeDirectory = "/tmp/nvidia-mps"
+
+	mpsActiveThreadPercentageEnvvar = "CUDA_MPS_ACTIVE_THREAD_PERCENTAGE"
+	mpsPipeDirectoryEnvvar          = "CUDA_MPS_PIPE_DIRECTORY"
+)
+
+// mpsSharing configures containers that request a share of the GPUs as MPS
+// clients.
+type mpsSharing struct {
+	logger        logger.Interface
+	pipeDirectory string
+}
+
+var _ oci.SpecModifier = (*mpsSharing)(nil)
+
+// NewMPSSharingModifier creates a modifier that configures containers with
+// the nvidia.com/gpu.share annotation as clients of the MPS control daemon on
+// the host.
+// A nil modifier is returned if the feature is not enabled or if no devices
+// are requested.
+func NewMPSSharingModifier(logger logger.Interface, cfg *config.Config, image image.CUDA) oci.SpecModifier {
+	if !cfg.Features.MPSSharing.IsEnabled() {
+		return nil
+	}
+	if devices := image.VisibleDevices(); len(devices) == 0 {
+		return nil
+	}
+	pipeDirectory := cfg.NVIDIAContainerRuntimeConfig.MPSPipeDirectory
+	if pipeDirectory == "" {
+		pipeDirectory = defaultMPSPipeDirectory
+	}
+	return &mpsSharing{
+		logger:        logger,
+		pipeDirectory: filepath.Clean(pipeDirectory),
+	}
+}
+
+// Modify sets the MPS envvars for containers that request a share of the GPUs
+// and mounts the MPS pipe directory into the container. Since MPS clients
+// communicate with the control daemon using shared memory, an error is
+// returned if the container does not share the IPC namespace of the host.
+func (m *mpsSharing) Modify(spec *specs.Spec) error {
+	if spec == nil || spec.Process == nil {
+		return nil
+	}
+	value, ok := spec.Annotations[GPUShareAnnotation]
+	if !ok {
+		return nil
+	}
+
+	percentage, err := parseGPUShare(value)
+	if err != nil {
+		return fmt.Errorf("invalid %v annotation: %w", GPUShareAnnotation, err)
+	}
+
+	if spec.Linux != nil && hasPrivateNamespace(spec.Linux.Namespaces, specs.IPCNamespace) {
+		return fmt.Errorf("MPS clients require the host IPC namespace (e.g. --ipc=host), but the container has a private IPC namespace")
+	}
+
+	if info, err := os.Stat(m.pipeDirectory); err != nil || !info.IsDir() {
+		return fmt.Errorf("MPS pipe directory %v not found; is the MPS control daemon running?", m.pipeDirectory)
+	}
+
+	m.logger.Debugf("Configuring container as MPS client with %d%% of GPU threads", percentage)
+	spec.Process.Env = setEnvvar(spec.Process.Env, mpsActiveThreadPercentageEnvvar, strconv.Itoa(percentage))
+	spec.Process.Env = setEnvvar(spec.Process.Env, mpsPipeDirectoryEnvvar, m.pipeDirectory)
+
+	for _, mount := range spec.Mounts {
+		if filepath.Clean(mount.Destination) == m.pipeDirectory {
+			return nil
+		}
+	}
+	spec.Mounts = append(spec.Mounts, specs.Mount{
+		Source:      m.pipeDirectory,
+		Destination: m.pipeDirectory,
+		Type:        "bind",
+		Options:     []string{"ro", "nosuid", "nodev", "rbind", "noexec"},
+	})
+	return nil
+}
+
+// parseGPUShare parses a GPU share such as 25 or 25% as a percentage.
+func parseGPUShare(value string) (int, error) {
+	percentage, err := strconv.Atoi(strings.TrimSuffix(strings.TrimSpace(value), "%"))
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse %q as a percentage: %w", value, err)
+	}
+	if percentage < 1 || percentage > 100 {
+		return 0, fmt.Errorf("percentage %d is not in the range 1-100", percentage)
+	}
+	return percentage, nil
+}
@@ -0,0 +1,152 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMPSSharing(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	pipeDirectory := t.TempDir()
+
+	testCases := []struct {
+		description   string
+		pipeDirectory string
+		spec          *specs.Spec
+		expectedError bool
+		expectedSpec  *specs.Spec
+	}{
+		{
+			description:   "no annotation is not modified",
+			pipeDirectory: pipeDirectory,
+			spec: &specs.Spec{
+				Process: &specs.Process{Env: []string{"NVIDIA_VISIBLE_DEVICES=all"}},
+			},
+			expectedSpec: &specs.Spec{
+				Process: &specs.Process{Env: []string{"NVIDIA_VISIBLE_DEVICES=all"}},
+			},
+		},
+		{
+			description:   "share sets envvars and mounts pipe directory",
+			pipeDirectory: pipeDirectory,
+			spec: &specs.Spec{
+				Annotations: map[string]string{"nvidia.com/gpu.share": "25%"},
+				Process: &specs.Process{Env: []string{
+					"NVIDIA_VISIBLE_DEVICES=all",
+					"CUDA_MPS_ACTIVE_THREAD_PERCENTAGE=100",
+				}},
+			},
+			expectedSpec: &specs.Spec{
+				Annotations: map[string]string{"nvidia.com/gpu.share": "25%"},
+				Process: &specs.Process{Env: []string{
+					"NVIDIA_VISIBLE_DEVICES=all",
+					"CUDA_MPS_ACTIVE_THREAD_PERCENTAGE=25",
+					"CUDA_MPS_PIPE_DIRECTORY=" + pipeDirectory,
+				}},
+				Mounts: []specs.Mount{
+					{
+						Source:      pipeDirectory,
+						Destination: pipeDirectory,
+						Type:        "bind",
+						Options:     []string{"ro", "nosuid", "nodev", "rbind", "noexec"},
+					},
+				},
+			},
+		},
+		{
+			description:   "existing pipe directory mount is not duplicated",
+			pipeDirectory: pipeDirectory,
+			spec: &specs.Spec{
+				Annotations: map[string]string{"nvidia.com/gpu.share": "50"},
+				Process:     &specs.Process{},
+				Mounts: []specs.Mount{
+					{Source: pipeDirectory, Destination: pipeDirectory + "/"},
+				},
+				Linux: &specs.Linux{
+					Namespaces: []specs.LinuxNamespace{{Type: specs.IPCNamespace, Path: "/proc/1/ns/ipc"}},
+				},
+			},
+			expectedSpec: &specs.Spec{
+				Annotations: map[string]string{"nvidia.com/gpu.share": "50"},
+				Linux: &specs.Linux{
+					Namespaces: []specs.LinuxNamespace{{Type: specs.IPCNamespace, Path: "/proc/1/ns/ipc"}},
+				},
+				Process: &specs.Process{Env: []string{
+					"CUDA_MPS_ACTIVE_THREAD_PERCENTAGE=50",
+					"CUDA_MPS_PIPE_DIRECTORY=" + pipeDirectory,
+				}},
+				Mounts: []specs.Mount{
+					{Source: pipeDirectory, Destination: pipeDirectory + "/"},
+				},
+			},
+		},
+		{
+			description:   "invalid share returns error",
+			pipeDirectory: pipeDirectory,
+			spec: &specs.Spec{
+				Annotations: map[string]string{"nvidia.com/gpu.share": "150"},
+				Process:     &specs.Process{},
+			},
+			expectedError: true,
+		},
+		{
+			description:   "private IPC namespace returns error",
+			pipeDirectory: pipeDirectory,
+			spec: &specs.Spec{
+				Annotations: map[string]string{"nvidia.com/gpu.share": "25"},
+				Process:     &specs.Process{},
+				Linux: &specs.Linux{
+					Namespaces: []specs.LinuxNamespace{{Type: specs.IPCNamespace}},
+				},
+			},
+			expectedError: true,
+		},
+		{
+			description:   "missing pipe directory returns error",
+			pipeDirectory: filepath.Join(pipeDirectory, "missing"),
+			spec: &specs.Spec{
+				Annotations: map[string]string{"nvidia.com/gpu.share": "25"},
+				Process:     &specs.Process{},
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			m := &mpsSharing{
+				logger:        logger,
+				pipeDirectory: tc.pipeDirectory,
+			}
+
+			err := m.Modify(tc.spec)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.EqualValues(t, tc.expectedSpec, tc.spec)
+		})
+	}
+}
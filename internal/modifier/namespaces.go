@@ -0,0 +1,150 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/oci"
+)
+
+const (
+	// CUDAIPCAnnotation indicates that a container uses CUDA IPC to share GPU
+	// memory with processes in other containers.
+	CUDAIPCAnnotation = "nvidia.com/cuda-ipc"
+	// UnmetNamespaceRequirementsAnnotation records the namespaces (e.g. ipc or
+	// pid) that must be shared with the host for the features requested by a
+	// container, but are private to the container.
+	UnmetNamespaceRequirementsAnnotation = "nvidia.com/unmet-namespace-requirements"
+)
+
+// A namespaceRequirement describes the host namespaces required by a feature.
+type namespaceRequirement struct {
+	feature    string
+	namespaces []specs.LinuxNamespaceType
+	// isRequested checks whether the feature is requested for the container
+	// described by the specified spec.
+	isRequested func(*specs.Spec) bool
+}
+
+var namespaceRequirements = []namespaceRequirement{
+	{
+		feature:     "MPS clients",
+		namespaces:  []specs.LinuxNamespaceType{specs.IPCNamespace},
+		isRequested: isMPSClient,
+	},
+	{
+		feature:     "CUDA IPC between containers",
+		namespaces:  []specs.LinuxNamespaceType{specs.IPCNamespace, specs.PIDNamespace},
+		isRequested: isCUDAIPCRequested,
+	},
+}
+
+// namespaceRequirementChecker checks whether the features requested by a
+// container require namespaces that are private to the container.
+type namespaceRequirementChecker struct {
+	logger logger.Interface
+}
+
+var _ oci.SpecModifier = (*namespaceRequirementChecker)(nil)
+
+// NewNamespaceRequirementChecker creates a modifier that detects containers
+// that request features such as MPS or CUDA IPC between containers without
+// sharing the required namespaces with the host. A warning is logged for
+// each unmet requirement and the affected namespaces are recorded in the
+// nvidia.com/unmet-namespace-requirements annotation. This allows users to
+// understand why, for example, cross-container CUDA IPC fails.
+func NewNamespaceRequirementChecker(logger logger.Interface) oci.SpecModifier {
+	return &namespaceRequirementChecker{
+		logger: logger,
+	}
+}
+
+// Modify checks the namespaces of the container against the requirements of
+// the requested features. The container is not rejected if a requirement is
+// not met.
+func (m *namespaceRequirementChecker) Modify(spec *specs.Spec) error {
+	if spec == nil || spec.Linux == nil {
+		return nil
+	}
+
+	var unmet []string
+	for _, requirement := range namespaceRequirements {
+		if !requirement.isRequested(spec) {
+			continue
+		}
+		for _, namespace := range requirement.namespaces {
+			if !hasPrivateNamespace(spec.Linux.Namespaces, namespace) {
+				continue
+			}
+			m.logger.Warningf("%v require the host %v namespace, but the container has a private %v namespace", requirement.feature, namespace, namespace)
+			if !slices.Contains(unmet, string(namespace)) {
+				unmet = append(unmet, string(namespace))
+			}
+		}
+	}
+	if len(unmet) == 0 {
+		return nil
+	}
+
+	if spec.Annotations == nil {
+		spec.Annotations = make(map[string]string)
+	}
+	spec.Annotations[UnmetNamespaceRequirementsAnnotation] = strings.Join(unmet, ",")
+	return nil
+}
+
+// hasPrivateNamespace checks whether a new namespace of the specified type is
+// created for the container. Namespaces that are joined using a path (e.g.
+// the namespace of another container) are not considered private.
+func hasPrivateNamespace(namespaces []specs.LinuxNamespace, namespaceType specs.LinuxNamespaceType) bool {
+	for _, namespace := range namespaces {
+		if namespace.Type == namespaceType && namespace.Path == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// isMPSClient checks whether the container is configured as an MPS client.
+// This is the case if the MPS pipe directory is set in the container
+// environment (e.g. by the MPS sharing modifier) or if a share of the GPUs is
+// requested.
+func isMPSClient(spec *specs.Spec) bool {
+	if _, ok := spec.Annotations[GPUShareAnnotation]; ok {
+		return true
+	}
+	if spec.Process == nil {
+		return false
+	}
+	for _, e := range spec.Process.Env {
+		if strings.HasPrefix(e, mpsPipeDirectoryEnvvar+"=") {
+			return true
+		}
+	}
+	return false
+}
+
+// isCUDAIPCRequested checks whether the container requests CUDA IPC between
+// containers using the nvidia.com/cuda-ipc annotation.
+func isCUDAIPCRequested(spec *specs.Spec) bool {
+	return spec.Annotations[CUDAIPCAnnotation] == "true"
+}
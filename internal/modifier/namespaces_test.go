@@ -0,0 +1,119 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"testing"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamespaceRequirementChecker(t *testing.T) {
+	logger, hook := testlog.NewNullLogger()
+
+	privateNamespaces := []specs.LinuxNamespace{
+		{Type: specs.PIDNamespace},
+		{Type: specs.IPCNamespace},
+		{Type: specs.MountNamespace},
+	}
+
+	testCases := []struct {
+		description         string
+		spec                *specs.Spec
+		expectedAnnotations map[string]string
+		expectedWarnings    int
+	}{
+		{
+			description: "no features requested",
+			spec: &specs.Spec{
+				Process: &specs.Process{Env: []string{"NVIDIA_VISIBLE_DEVICES=all"}},
+				Linux:   &specs.Linux{Namespaces: privateNamespaces},
+			},
+		},
+		{
+			description: "mps client with private ipc namespace",
+			spec: &specs.Spec{
+				Process: &specs.Process{Env: []string{"CUDA_MPS_PIPE_DIRECTORY=/tmp/nvidia-mps"}},
+				Linux:   &specs.Linux{Namespaces: privateNamespaces},
+			},
+			expectedAnnotations: map[string]string{
+				"nvidia.com/unmet-namespace-requirements": "ipc",
+			},
+			expectedWarnings: 1,
+		},
+		{
+			description: "mps client with host ipc namespace",
+			spec: &specs.Spec{
+				Annotations: map[string]string{"nvidia.com/gpu.share": "50"},
+				Process:     &specs.Process{},
+				Linux: &specs.Linux{Namespaces: []specs.LinuxNamespace{
+					{Type: specs.PIDNamespace},
+					{Type: specs.MountNamespace},
+				}},
+			},
+			expectedAnnotations: map[string]string{"nvidia.com/gpu.share": "50"},
+		},
+		{
+			description: "mps client joining namespace of other container",
+			spec: &specs.Spec{
+				Process: &specs.Process{Env: []string{"CUDA_MPS_PIPE_DIRECTORY=/tmp/nvidia-mps"}},
+				Linux: &specs.Linux{Namespaces: []specs.LinuxNamespace{
+					{Type: specs.IPCNamespace, Path: "/proc/1234/ns/ipc"},
+				}},
+			},
+		},
+		{
+			description: "cuda ipc with private namespaces",
+			spec: &specs.Spec{
+				Annotations: map[string]string{"nvidia.com/cuda-ipc": "true"},
+				Process:     &specs.Process{},
+				Linux:       &specs.Linux{Namespaces: privateNamespaces},
+			},
+			expectedAnnotations: map[string]string{
+				"nvidia.com/cuda-ipc":                     "true",
+				"nvidia.com/unmet-namespace-requirements": "ipc,pid",
+			},
+			expectedWarnings: 2,
+		},
+		{
+			description: "mps and cuda ipc with private namespaces",
+			spec: &specs.Spec{
+				Annotations: map[string]string{"nvidia.com/cuda-ipc": "true"},
+				Process:     &specs.Process{Env: []string{"CUDA_MPS_PIPE_DIRECTORY=/tmp/nvidia-mps"}},
+				Linux: &specs.Linux{Namespaces: []specs.LinuxNamespace{
+					{Type: specs.IPCNamespace},
+				}},
+			},
+			expectedAnnotations: map[string]string{
+				"nvidia.com/cuda-ipc":                     "true",
+				"nvidia.com/unmet-namespace-requirements": "ipc",
+			},
+			expectedWarnings: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			hook.Reset()
+			require.NoError(t, NewNamespaceRequirementChecker(logger).Modify(tc.spec))
+			require.EqualValues(t, tc.expectedAnnotations, tc.spec.Annotations)
+			require.Len(t, hook.AllEntries(), tc.expectedWarnings)
+		})
+	}
+}
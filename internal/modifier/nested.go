@@ -0,0 +1,302 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/discover"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/oci"
+)
+
+const (
+	// nestedToolkitDir is the directory in the container at which the
+	// toolkit config of the host is mounted.
+	nestedToolkitDir = "/run/nvidia-container-toolkit"
+	// defaultNestedContainersRuntime is the low-level runtime for which
+	// containers are prepared if no runtimes are configured.
+	defaultNestedContainersRuntime = "sysbox-runc"
+	// nestedContainersPreparedAnnotation marks an OCI spec that has already
+	// been prepared for nested containers.
+	nestedContainersPreparedAnnotation = "nvidia.com/nested-containers-prepared"
+	// nestedContainersPreparedFileName is the name of the file that is
+	// created in the container bundle when the OCI spec is prepared for
+	// nested containers. Since the annotation can be set by the user, this
+	// file is used to verify that the spec was prepared by the runtime.
+	nestedContainersPreparedFileName = "nvidia-nested-containers-prepared"
+)
+
+// nestedToolkitExecutables are the executables that an inner container
+// engine requires to inject GPUs into nested containers.
+var nestedToolkitExecutables = []string{
+	"nvidia-container-runtime",
+	"nvidia-container-runtime-hook",
+	"nvidia-ctk",
+	"nvidia-cdi-hook",
+	"nvidia-container-cli",
+}
+
+// nestedToolkitLibraries are the libraries required by the
+// nvidia-container-cli.
+var nestedToolkitLibraries = []string{
+	"libnvidia-container.so.1",
+	"libnvidia-container-go.so.1",
+}
+
+type nestedContainers struct {
+	logger          logger.Interface
+	runtimes        []string
+	lowLevelRuntime string
+	bundleDir       string
+	configFilePath  string
+	discoverer      discover.Discover
+}
+
+// nestedLibraries discovers the libraries required by the
+// nvidia-container-cli. The libraries are located in the same way as the
+// driver libraries and are mounted at their soname in the directory that
+// contains them on the host.
+type nestedLibraries struct {
+	discover.None
+	logger  logger.Interface
+	locator lookup.Locator
+}
+
+var _ oci.SpecModifier = (*nestedContainers)(nil)
+
+// NewNestedContainersModifier creates a modifier that prepares containers
+// that run nested GPU containers. The toolkit config file of the host is
+// mounted read-only at /run/nvidia-container-toolkit/config.toml and the
+// NVIDIA_CTK_CONFIG_FILE_PATH envvar is set so that an inner container engine
+// can inject GPUs into its containers. The toolkit executables and libraries
+// of the host are only mounted if the container image does not contain them.
+//
+// Since annotations can be set by users, a container is only prepared if the
+// low-level runtime is one of the configured nested-containers-runtimes
+// (sysbox-runc by default).
+// A nil modifier is returned if the feature is not enabled.
+func NewNestedContainersModifier(logger logger.Interface, cfg *config.Config, lowLevelRuntime string, bundleDir string) oci.SpecModifier {
+	if !cfg.Features.NestedContainers.IsEnabled() {
+		return nil
+	}
+
+	runtimes := cfg.NVIDIAContainerRuntimeConfig.NestedContainersRuntimes
+	if len(runtimes) == 0 {
+		runtimes = []string{defaultNestedContainersRuntime}
+	}
+
+	d := discover.Merge(
+		discover.NewMounts(
+			logger,
+			lookup.NewExecutableLocator(logger, "/"),
+			"/",
+			nestedToolkitExecutables,
+		),
+		&nestedLibraries{
+			logger:  logger,
+			locator: lookup.NewLibraryLocator(lookup.WithLogger(logger)),
+		},
+	)
+
+	return &nestedContainers{
+		logger:          logger,
+		runtimes:        runtimes,
+		lowLevelRuntime: lowLevelRuntime,
+		bundleDir:       bundleDir,
+		configFilePath:  config.GetConfigFilePath(),
+		discoverer:      d,
+	}
+}
+
+// Modify adds the toolkit mounts to containers that run nested containers
+// and marks the spec as prepared.
+func (m *nestedContainers) Modify(spec *specs.Spec) error {
+	if spec == nil || !m.requiresNestedContainers() {
+		return nil
+	}
+	if isPreparedBundle(spec, m.bundleDir) {
+		m.logger.Debugf("OCI spec is already prepared for nested containers")
+		return nil
+	}
+
+	d := &nestedMounts{
+		logger:         m.logger,
+		containerRoot:  getContainerRoot(spec, m.bundleDir),
+		configFilePath: m.configFilePath,
+		discoverer:     m.discoverer,
+	}
+	mounts, err := NewModifierFromDiscoverer(m.logger, d)
+	if err != nil {
+		return fmt.Errorf("failed to construct modifier for nested containers: %w", err)
+	}
+	if err := mounts.Modify(spec); err != nil {
+		return fmt.Errorf("failed to prepare OCI spec for nested containers: %w", err)
+	}
+	if spec.Process != nil && !hasEnvvar(spec.Process.Env, config.FilePathOverrideEnvVar) {
+		spec.Process.Env = append(spec.Process.Env, config.FilePathOverrideEnvVar+"="+nestedConfigFilePath())
+	}
+
+	if spec.Annotations == nil {
+		spec.Annotations = make(map[string]string)
+	}
+	spec.Annotations[nestedContainersPreparedAnnotation] = "true"
+
+	if m.bundleDir == "" {
+		return nil
+	}
+	if err := os.WriteFile(filepath.Join(m.bundleDir, nestedContainersPreparedFileName), nil, 0600); err != nil {
+		m.logger.Warningf("Failed to mark bundle as prepared for nested containers: %v", err)
+	}
+	return nil
+}
+
+// requiresNestedContainers checks whether the container is expected to run
+// nested containers. This is the case if the low-level runtime is one of the
+// configured runtimes.
+func (m *nestedContainers) requiresNestedContainers() bool {
+	name := filepath.Base(m.lowLevelRuntime)
+	for _, runtime := range m.runtimes {
+		if runtime != "" && strings.HasPrefix(name, runtime) {
+			return true
+		}
+	}
+	return false
+}
+
+// nestedConfigFilePath returns the path at which the toolkit config file is
+// mounted in the container.
+func nestedConfigFilePath() string {
+	return filepath.Join(nestedToolkitDir, "config.toml")
+}
+
+// nestedMounts discovers the mounts for a container that runs nested
+// containers. The toolkit config file is mounted under a dedicated directory
+// and mounts that would shadow files of the container image are skipped.
+type nestedMounts struct {
+	discover.None
+	logger         logger.Interface
+	containerRoot  string
+	configFilePath string
+	discoverer     discover.Discover
+}
+
+// Mounts returns the config file mount followed by the discovered toolkit
+// mounts whose paths do not exist in the container root.
+func (d *nestedMounts) Mounts() ([]discover.Mount, error) {
+	var mounts []discover.Mount
+	if info, err := os.Stat(d.configFilePath); err == nil && info.Mode().IsRegular() {
+		mounts = append(mounts, discover.Mount{
+			HostPath: d.configFilePath,
+			Path:     nestedConfigFilePath(),
+			Options:  []string{"ro", "nosuid", "nodev", "rbind", "rprivate"},
+		})
+	} else {
+		logger.Skippedf(d.logger, "Could not locate config file %v: %v", d.configFilePath, err)
+	}
+
+	discovered, err := d.discoverer.Mounts()
+	if err != nil {
+		return nil, err
+	}
+	if d.containerRoot == "" {
+		d.logger.Warningf("Skipping toolkit mounts for nested containers; the container root is unknown")
+		return mounts, nil
+	}
+	for _, mount := range discovered {
+		if _, err := os.Lstat(filepath.Join(d.containerRoot, mount.Path)); err == nil {
+			d.logger.Debugf("Skipping mount of %v; the path exists in the container image", mount.Path)
+			continue
+		}
+		mounts = append(mounts, mount)
+	}
+	return mounts, nil
+}
+
+// getContainerRoot returns the root filesystem of the container. A relative
+// root path is resolved against the bundle directory.
+func getContainerRoot(spec *specs.Spec, bundleDir string) string {
+	if spec.Root == nil || spec.Root.Path == "" {
+		return ""
+	}
+	if filepath.IsAbs(spec.Root.Path) {
+		return spec.Root.Path
+	}
+	if bundleDir == "" {
+		return ""
+	}
+	return filepath.Join(bundleDir, spec.Root.Path)
+}
+
+// Mounts returns the mounts for the libraries required by the
+// nvidia-container-cli. Libraries that cannot be located are skipped.
+func (d *nestedLibraries) Mounts() ([]discover.Mount, error) {
+	var mounts []discover.Mount
+	for _, library := range nestedToolkitLibraries {
+		candidates, err := d.locator.Locate(library)
+		if err != nil || len(candidates) == 0 {
+			logger.Skippedf(d.logger, "Could not locate %v: %v", library, err)
+			continue
+		}
+		mounts = append(mounts, discover.Mount{
+			HostPath: candidates[0],
+			Path:     filepath.Join(filepath.Dir(candidates[0]), library),
+			Options:  []string{"ro", "nosuid", "nodev", "rbind", "rprivate"},
+		})
+	}
+	return mounts, nil
+}
+
+// IsPreparedForNestedContainers checks whether the specified OCI spec has
+// already been prepared for nested containers by the runtime. Since this is
+// the case if a container is created again from the same bundle, no further
+// modifications should be applied to avoid conflicting edits.
+//
+// Since skipping the modifications also skips the checks applied to the
+// requested devices, the nvidia.com/nested-containers-prepared annotation is
+// only trusted if the nested-containers feature is enabled and the bundle
+// was marked as prepared by the runtime.
+func IsPreparedForNestedContainers(cfg *config.Config, spec *specs.Spec, bundleDir string) bool {
+	if !cfg.Features.NestedContainers.IsEnabled() {
+		return false
+	}
+	return isPreparedBundle(spec, bundleDir)
+}
+
+// isPreparedBundle checks whether the specified OCI spec is annotated as
+// prepared and whether the bundle contains the file written when the spec
+// was prepared.
+func isPreparedBundle(spec *specs.Spec, bundleDir string) bool {
+	if spec == nil || bundleDir == "" {
+		return false
+	}
+	if spec.Annotations[nestedContainersPreparedAnnotation] != "true" {
+		return false
+	}
+	info, err := os.Lstat(filepath.Join(bundleDir, nestedContainersPreparedFileName))
+	if err != nil {
+		return false
+	}
+	return info.Mode().IsRegular()
+}
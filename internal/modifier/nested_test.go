@@ -0,0 +1,256 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/discover"
+)
+
+func TestNestedContainersModifier(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	configFilePath := filepath.Join(t.TempDir(), "config.toml")
+	require.NoError(t, os.WriteFile(configFilePath, nil, 0600))
+
+	toolkitMounts := &discover.DiscoverMock{
+		MountsFunc: func() ([]discover.Mount, error) {
+			mounts := []discover.Mount{
+				{
+					HostPath: "/usr/bin/nvidia-ctk",
+					Path:     "/usr/bin/nvidia-ctk",
+					Options:  []string{"ro", "nosuid", "nodev", "rbind", "rprivate"},
+				},
+			}
+			return mounts, nil
+		},
+	}
+	configMount := specs.Mount{
+		Source:      configFilePath,
+		Destination: "/run/nvidia-container-toolkit/config.toml",
+		Options:     []string{"ro", "nosuid", "nodev", "rbind", "rprivate"},
+	}
+	executableMount := specs.Mount{
+		Source:      "/usr/bin/nvidia-ctk",
+		Destination: "/usr/bin/nvidia-ctk",
+		Options:     []string{"ro", "nosuid", "nodev", "rbind", "rprivate"},
+	}
+	configEnv := []string{"NVIDIA_CTK_CONFIG_FILE_PATH=/run/nvidia-container-toolkit/config.toml"}
+
+	testCases := []struct {
+		description     string
+		runtimes        []string
+		lowLevelRuntime string
+		preparedBundle  bool
+		imageFiles      []string
+		spec            *specs.Spec
+		expectedSpec    *specs.Spec
+	}{
+		{
+			description:     "runc container is not modified",
+			lowLevelRuntime: "/usr/bin/runc",
+			spec:            &specs.Spec{Process: &specs.Process{}},
+			expectedSpec:    &specs.Spec{Process: &specs.Process{}},
+		},
+		{
+			description:     "annotation does not prepare container",
+			lowLevelRuntime: "/usr/bin/runc",
+			spec: &specs.Spec{
+				Process:     &specs.Process{},
+				Annotations: map[string]string{"nvidia.com/nested-containers": "true"},
+			},
+			expectedSpec: &specs.Spec{
+				Process:     &specs.Process{},
+				Annotations: map[string]string{"nvidia.com/nested-containers": "true"},
+			},
+		},
+		{
+			description:     "sysbox runtime prepares container",
+			lowLevelRuntime: "/usr/bin/sysbox-runc",
+			spec:            &specs.Spec{Process: &specs.Process{}},
+			expectedSpec: &specs.Spec{
+				Process: &specs.Process{Env: configEnv},
+				Annotations: map[string]string{
+					nestedContainersPreparedAnnotation: "true",
+				},
+				Mounts: []specs.Mount{configMount, executableMount},
+			},
+		},
+		{
+			description:     "configured runtime prepares container",
+			runtimes:        []string{"runc-dind"},
+			lowLevelRuntime: "/usr/local/bin/runc-dind",
+			spec:            &specs.Spec{Process: &specs.Process{}},
+			expectedSpec: &specs.Spec{
+				Process: &specs.Process{Env: configEnv},
+				Annotations: map[string]string{
+					nestedContainersPreparedAnnotation: "true",
+				},
+				Mounts: []specs.Mount{configMount, executableMount},
+			},
+		},
+		{
+			description:     "configured runtimes replace sysbox-runc",
+			runtimes:        []string{"runc-dind"},
+			lowLevelRuntime: "/usr/bin/sysbox-runc",
+			spec:            &specs.Spec{Process: &specs.Process{}},
+			expectedSpec:    &specs.Spec{Process: &specs.Process{}},
+		},
+		{
+			description:     "executables of the image are not shadowed",
+			lowLevelRuntime: "/usr/bin/sysbox-runc",
+			imageFiles:      []string{"/usr/bin/nvidia-ctk"},
+			spec:            &specs.Spec{Process: &specs.Process{}},
+			expectedSpec: &specs.Spec{
+				Process: &specs.Process{Env: configEnv},
+				Annotations: map[string]string{
+					nestedContainersPreparedAnnotation: "true",
+				},
+				Mounts: []specs.Mount{configMount},
+			},
+		},
+		{
+			description:     "config file path set by container is kept",
+			lowLevelRuntime: "/usr/bin/sysbox-runc",
+			spec: &specs.Spec{
+				Process: &specs.Process{Env: []string{"NVIDIA_CTK_CONFIG_FILE_PATH=/etc/custom.toml"}},
+			},
+			expectedSpec: &specs.Spec{
+				Process: &specs.Process{Env: []string{"NVIDIA_CTK_CONFIG_FILE_PATH=/etc/custom.toml"}},
+				Annotations: map[string]string{
+					nestedContainersPreparedAnnotation: "true",
+				},
+				Mounts: []specs.Mount{configMount, executableMount},
+			},
+		},
+		{
+			description:     "prepared container is not modified",
+			lowLevelRuntime: "/usr/bin/sysbox-runc",
+			preparedBundle:  true,
+			spec: &specs.Spec{
+				Process: &specs.Process{},
+				Annotations: map[string]string{
+					nestedContainersPreparedAnnotation: "true",
+				},
+			},
+			expectedSpec: &specs.Spec{
+				Process: &specs.Process{},
+				Annotations: map[string]string{
+					nestedContainersPreparedAnnotation: "true",
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			bundleDir := t.TempDir()
+			if tc.preparedBundle {
+				require.NoError(t, os.WriteFile(filepath.Join(bundleDir, nestedContainersPreparedFileName), nil, 0600))
+			}
+			for _, file := range tc.imageFiles {
+				path := filepath.Join(bundleDir, "rootfs", file)
+				require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+				require.NoError(t, os.WriteFile(path, nil, 0755))
+			}
+			tc.spec.Root = &specs.Root{Path: "rootfs"}
+			tc.expectedSpec.Root = &specs.Root{Path: "rootfs"}
+
+			runtimes := tc.runtimes
+			if len(runtimes) == 0 {
+				runtimes = []string{defaultNestedContainersRuntime}
+			}
+			m := &nestedContainers{
+				logger:          logger,
+				runtimes:        runtimes,
+				lowLevelRuntime: tc.lowLevelRuntime,
+				bundleDir:       bundleDir,
+				configFilePath:  configFilePath,
+				discoverer:      toolkitMounts,
+			}
+			require.NoError(t, m.Modify(tc.spec))
+			require.EqualValues(t, tc.expectedSpec, tc.spec)
+			if IsPreparedForNestedContainers(&config.Config{}, tc.spec, bundleDir) {
+				t.Fatal("prepared spec must not be trusted if the feature is disabled")
+			}
+		})
+	}
+}
+
+func TestIsPreparedForNestedContainers(t *testing.T) {
+	toml, err := config.New()
+	require.NoError(t, err)
+	toml.Set("features.nested-containers", true)
+	cfg, err := toml.Config()
+	require.NoError(t, err)
+	prepared := &specs.Spec{
+		Annotations: map[string]string{
+			nestedContainersPreparedAnnotation: "true",
+		},
+	}
+
+	testCases := []struct {
+		description    string
+		cfg            *config.Config
+		preparedBundle bool
+		spec           *specs.Spec
+		expected       bool
+	}{
+		{
+			description:    "prepared spec and bundle is trusted",
+			cfg:            cfg,
+			preparedBundle: true,
+			spec:           prepared,
+			expected:       true,
+		},
+		{
+			description: "annotation without prepared bundle is not trusted",
+			cfg:         cfg,
+			spec:        prepared,
+		},
+		{
+			description:    "prepared bundle without annotation is not trusted",
+			cfg:            cfg,
+			preparedBundle: true,
+			spec:           &specs.Spec{},
+		},
+		{
+			description:    "disabled feature is not trusted",
+			cfg:            &config.Config{},
+			preparedBundle: true,
+			spec:           prepared,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			bundleDir := t.TempDir()
+			if tc.preparedBundle {
+				require.NoError(t, os.WriteFile(filepath.Join(bundleDir, nestedContainersPreparedFileName), nil, 0600))
+			}
+			require.Equal(t, tc.expected, IsPreparedForNestedContainers(tc.cfg, tc.spec, bundleDir))
+		})
+	}
+}
@@ -0,0 +1,228 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/opencontainers/runtime-spec/specs-go"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/oci"
+)
+
+const numaNodesPath = "/sys/devices/system/node"
+
+// gpuMemoryNUMANodes adds the NUMA nodes of the GPU memory on Grace Hopper
+// systems to the allowed memory nodes of a container.
+type gpuMemoryNUMANodes struct {
+	logger        logger.Interface
+	nvmllib       nvml.Interface
+	numaNodesPath string
+}
+
+var _ oci.SpecModifier = (*gpuMemoryNUMANodes)(nil)
+
+// NewGPUMemoryNUMAModifier creates a modifier that adds the NUMA nodes of the
+// memory of the requested GPUs to the cpuset memory nodes of a container. On
+// Grace Hopper systems the GPU memory is exposed as CPU-less NUMA nodes, and
+// restricting a container to the memory nodes of its CPUs would prevent
+// unified memory allocations on the GPU memory.
+// A nil modifier is returned if the feature is not enabled.
+func NewGPUMemoryNUMAModifier(logger logger.Interface, cfg *config.Config) oci.SpecModifier {
+	if !cfg.Features.EnableEGM.IsEnabled() {
+		return nil
+	}
+	return &gpuMemoryNUMANodes{
+		logger:        logger,
+		nvmllib:       nvml.New(),
+		numaNodesPath: numaNodesPath,
+	}
+}
+
+// Modify adds the GPU memory NUMA nodes of the requested GPUs to the memory
+// nodes of the container. The nodes of the memory of other GPUs or of other
+// CPU-less memory such as CXL memory are not added. Containers without GPU
+// device nodes or without restricted memory nodes are not modified.
+func (m *gpuMemoryNUMANodes) Modify(spec *specs.Spec) error {
+	if spec == nil || spec.Linux == nil || spec.Linux.Resources == nil || spec.Linux.Resources.CPU == nil {
+		return nil
+	}
+	minors := getGPUDeviceMinors(spec.Linux.Devices)
+	if spec.Linux.Resources.CPU.Mems == "" || len(minors) == 0 {
+		return nil
+	}
+
+	mems, err := parseNodeList(spec.Linux.Resources.CPU.Mems)
+	if err != nil {
+		return fmt.Errorf("failed to parse memory nodes: %w", err)
+	}
+
+	gpuMemoryNodes, err := m.getGPUMemoryNodes(minors)
+	if err != nil {
+		m.logger.Warningf("Ignoring GPU memory NUMA nodes: %v", err)
+		return nil
+	}
+
+	modified := false
+	for _, node := range gpuMemoryNodes {
+		if slices.Contains(mems, node) {
+			continue
+		}
+		mems = append(mems, node)
+		modified = true
+	}
+	if !modified {
+		return nil
+	}
+
+	slices.Sort(mems)
+	spec.Linux.Resources.CPU.Mems = formatNodeList(mems)
+	m.logger.Debugf("Updated memory nodes to %v", spec.Linux.Resources.CPU.Mems)
+	return nil
+}
+
+// getGPUMemoryNodes returns the NUMA nodes of the memory of the GPUs with the
+// specified minor numbers as reported by NVML. Only CPU-less nodes are
+// returned to ensure that the memory nodes of the CPUs are not changed.
+func (m *gpuMemoryNUMANodes) getGPUMemoryNodes(minors []int) ([]int, error) {
+	cpuLessNodes, err := m.getCPULessNodes()
+	if err != nil {
+		return nil, err
+	}
+	if len(cpuLessNodes) == 0 {
+		return nil, nil
+	}
+
+	if ret := m.nvmllib.Init(); ret != nvml.SUCCESS {
+		return nil, fmt.Errorf("failed to initialize NVML: %v", ret)
+	}
+	defer func() {
+		_ = m.nvmllib.Shutdown()
+	}()
+
+	count, ret := m.nvmllib.DeviceGetCount()
+	if ret != nvml.SUCCESS {
+		return nil, fmt.Errorf("failed to get device count: %v", ret)
+	}
+
+	var nodes []int
+	for i := 0; i < count; i++ {
+		device, ret := m.nvmllib.DeviceGetHandleByIndex(i)
+		if ret != nvml.SUCCESS {
+			return nil, fmt.Errorf("failed to get device %d: %v", i, ret)
+		}
+		minor, ret := device.GetMinorNumber()
+		if ret != nvml.SUCCESS {
+			return nil, fmt.Errorf("failed to get minor number of device %d: %v", i, ret)
+		}
+		if !slices.Contains(minors, minor) {
+			continue
+		}
+		node, ret := device.GetNumaNodeId()
+		if ret == nvml.ERROR_NOT_SUPPORTED {
+			continue
+		}
+		if ret != nvml.SUCCESS {
+			return nil, fmt.Errorf("failed to get memory NUMA node of device %d: %v", i, ret)
+		}
+		if slices.Contains(cpuLessNodes, node) && !slices.Contains(nodes, node) {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes, nil
+}
+
+// getCPULessNodes returns the NUMA nodes that have memory but no CPUs.
+func (m *gpuMemoryNUMANodes) getCPULessNodes() ([]int, error) {
+	hasMemory, err := readNodeList(filepath.Join(m.numaNodesPath, "has_memory"))
+	if err != nil {
+		return nil, err
+	}
+	hasCPU, err := readNodeList(filepath.Join(m.numaNodesPath, "has_cpu"))
+	if err != nil {
+		return nil, err
+	}
+
+	var nodes []int
+	for _, node := range hasMemory {
+		if !slices.Contains(hasCPU, node) {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes, nil
+}
+
+func readNodeList(path string) ([]int, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseNodeList(string(contents))
+}
+
+// parseNodeList parses a node list such as 0-3,8 as used by cpusets.
+func parseNodeList(list string) ([]int, error) {
+	var nodes []int
+	for _, part := range strings.Split(strings.TrimSpace(list), ",") {
+		if part == "" {
+			continue
+		}
+		first, last, isRange := strings.Cut(part, "-")
+		start, err := strconv.Atoi(first)
+		if err != nil {
+			return nil, fmt.Errorf("invalid node %q: %w", part, err)
+		}
+		end := start
+		if isRange {
+			end, err = strconv.Atoi(last)
+			if err != nil || end < start {
+				return nil, fmt.Errorf("invalid node range %q", part)
+			}
+		}
+		for node := start; node <= end; node++ {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes, nil
+}
+
+// formatNodeList formats a sorted list of nodes as a cpuset node list,
+// combining consecutive nodes into ranges.
+func formatNodeList(nodes []int) string {
+	var parts []string
+	for i := 0; i < len(nodes); {
+		j := i
+		for j+1 < len(nodes) && nodes[j+1] == nodes[j]+1 {
+			j++
+		}
+		if i == j {
+			parts = append(parts, strconv.Itoa(nodes[i]))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d-%d", nodes[i], nodes[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,156 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/NVIDIA/go-nvml/pkg/nvml/mock"
+	"github.com/opencontainers/runtime-spec/specs-go"
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGPUMemoryNUMAModifier(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	gpuDevices := []specs.LinuxDevice{{Path: "/dev/nvidia0"}}
+
+	testCases := []struct {
+		description  string
+		hasMemory    string
+		hasCPU       string
+		devices      []specs.LinuxDevice
+		mems         string
+		expectedMems string
+	}{
+		{
+			description:  "GPU memory nodes are added",
+			hasMemory:    "0-1,4\n",
+			hasCPU:       "0\n",
+			devices:      gpuDevices,
+			mems:         "0",
+			expectedMems: "0-1",
+		},
+		{
+			description:  "memory nodes of all requested GPUs are added",
+			hasMemory:    "0-1,4\n",
+			hasCPU:       "0\n",
+			devices:      []specs.LinuxDevice{{Path: "/dev/nvidia0"}, {Path: "/dev/nvidia1"}},
+			mems:         "0",
+			expectedMems: "0-1,4",
+		},
+		{
+			description:  "GPU memory nodes with CPUs are not added",
+			hasMemory:    "0-1,4\n",
+			hasCPU:       "0-1\n",
+			devices:      gpuDevices,
+			mems:         "0",
+			expectedMems: "0",
+		},
+		{
+			description:  "unrestricted memory nodes are not modified",
+			hasMemory:    "0-1\n",
+			hasCPU:       "0\n",
+			devices:      gpuDevices,
+			mems:         "",
+			expectedMems: "",
+		},
+		{
+			description:  "containers without GPUs are not modified",
+			hasMemory:    "0-1\n",
+			hasCPU:       "0\n",
+			mems:         "0",
+			expectedMems: "0",
+		},
+		{
+			description:  "systems without CPU-less nodes are not modified",
+			hasMemory:    "0-1\n",
+			hasCPU:       "0-1\n",
+			devices:      gpuDevices,
+			mems:         "1",
+			expectedMems: "1",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			nodesPath := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(nodesPath, "has_memory"), []byte(tc.hasMemory), 0644))
+			require.NoError(t, os.WriteFile(filepath.Join(nodesPath, "has_cpu"), []byte(tc.hasCPU), 0644))
+
+			// The memory of GPU 0 is on node 1 and the memory of GPU 1 is
+			// on node 4.
+			devices := []nvml.Device{newNUMADevice(0, 1), newNUMADevice(1, 4)}
+			m := &gpuMemoryNUMANodes{
+				logger: logger,
+				nvmllib: &mock.Interface{
+					InitFunc: func() nvml.Return {
+						return nvml.SUCCESS
+					},
+					ShutdownFunc: func() nvml.Return {
+						return nvml.SUCCESS
+					},
+					DeviceGetCountFunc: func() (int, nvml.Return) {
+						return len(devices), nvml.SUCCESS
+					},
+					DeviceGetHandleByIndexFunc: func(n int) (nvml.Device, nvml.Return) {
+						return devices[n], nvml.SUCCESS
+					},
+				},
+				numaNodesPath: nodesPath,
+			}
+
+			spec := &specs.Spec{
+				Linux: &specs.Linux{
+					Devices: tc.devices,
+					Resources: &specs.LinuxResources{
+						CPU: &specs.LinuxCPU{
+							Mems: tc.mems,
+						},
+					},
+				},
+			}
+			require.NoError(t, m.Modify(spec))
+			require.Equal(t, tc.expectedMems, spec.Linux.Resources.CPU.Mems)
+		})
+	}
+}
+
+func newNUMADevice(minor int, numaNode int) nvml.Device {
+	return &mock.Device{
+		GetMinorNumberFunc: func() (int, nvml.Return) {
+			return minor, nvml.SUCCESS
+		},
+		GetNumaNodeIdFunc: func() (int, nvml.Return) {
+			return numaNode, nvml.SUCCESS
+		},
+	}
+}
+
+func TestParseNodeList(t *testing.T) {
+	nodes, err := parseNodeList("0-2,5,7-8\n")
+	require.NoError(t, err)
+	require.EqualValues(t, []int{0, 1, 2, 5, 7, 8}, nodes)
+	require.Equal(t, "0-2,5,7-8", formatNodeList(nodes))
+
+	_, err = parseNodeList("3-1")
+	require.Error(t, err)
+}
@@ -0,0 +1,149 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"path/filepath"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/discover"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/info/proc"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/oci"
+)
+
+const (
+	// wrappedNvidiaSMIPath is the path in the container where nvidia-smi is
+	// mounted if it is replaced by a wrapper.
+	wrappedNvidiaSMIPath = "/usr/local/nvidia-container-toolkit/bin/nvidia-smi"
+)
+
+// nvidiaSMIWrapper replaces the nvidia-smi executable in the container by a
+// wrapper that only reports on the injected GPUs by default. This is cosmetic
+// and does not isolate GPUs since the wrapped executable can still be invoked
+// directly and other GPUs can be selected explicitly.
+type nvidiaSMIWrapper struct {
+	logger      logger.Interface
+	hookCreator discover.HookCreator
+	hostRoot    string
+}
+
+var _ oci.SpecModifier = (*nvidiaSMIWrapper)(nil)
+
+// NewNvidiaSMIWrapper creates a modifier that mounts nvidia-smi at a different
+// path in the container and adds a create-nvidia-smi-wrapper hook that
+// creates a wrapper at the original path. The wrapper restricts the default
+// output of nvidia-smi to the injected GPUs.
+// A nil modifier is returned if the feature is not enabled.
+func NewNvidiaSMIWrapper(logger logger.Interface, cfg *config.Config, hookCreator discover.HookCreator) oci.SpecModifier {
+	if !cfg.Features.WrapNvidiaSMI.IsEnabled() {
+		return nil
+	}
+	return &nvidiaSMIWrapper{
+		logger:      logger,
+		hookCreator: hookCreator,
+		hostRoot:    "/",
+	}
+}
+
+// Modify updates the nvidia-smi mount in the spec and adds the hook to create
+// the wrapper. Containers without an nvidia-smi mount or GPU device nodes are
+// not modified. If the UUID of an injected GPU cannot be determined, the spec
+// is also not modified since the wrapper would hide this GPU.
+func (m *nvidiaSMIWrapper) Modify(spec *specs.Spec) error {
+	if spec == nil || spec.Linux == nil {
+		return nil
+	}
+
+	mount := findNvidiaSMIMount(spec.Mounts)
+	if mount == nil {
+		return nil
+	}
+
+	devices, ok := m.getInjectedDeviceUUIDs(spec.Linux.Devices)
+	if !ok {
+		m.logger.Warningf("Not wrapping nvidia-smi: failed to determine the UUIDs of the injected GPUs")
+		return nil
+	}
+	if len(devices) == 0 {
+		return nil
+	}
+
+	args := []string{"--path", mount.Destination, "--nvidia-smi", wrappedNvidiaSMIPath}
+	for _, device := range devices {
+		args = append(args, "--device", device)
+	}
+	hook := m.hookCreator.Create(discover.CreateNvidiaSMIWrapperHook, args...)
+	if hook == nil {
+		return nil
+	}
+
+	m.logger.Debugf("Wrapping %v for devices %v", mount.Destination, devices)
+	mount.Destination = wrappedNvidiaSMIPath
+	if spec.Hooks == nil {
+		spec.Hooks = &specs.Hooks{}
+	}
+	spec.Hooks.CreateContainer = append(spec.Hooks.CreateContainer, specs.Hook{
+		Path: hook.Path,
+		Args: hook.Args,
+		Env:  hook.Env,
+	})
+	return nil
+}
+
+// getInjectedDeviceUUIDs returns the UUIDs of the GPUs for the GPU device nodes
+// in the specified devices. If the UUID of a GPU is not known, false is
+// returned.
+func (m *nvidiaSMIWrapper) getInjectedDeviceUUIDs(containerDevices []specs.LinuxDevice) ([]string, bool) {
+	minors := getGPUDeviceMinors(containerDevices)
+	if len(minors) == 0 {
+		return nil, true
+	}
+
+	uuidsByMinor := make(map[int]string)
+	for _, gpu := range getHostGPUs(m.logger, m.hostRoot) {
+		uuidsByMinor[gpu.minor] = gpu.info[proc.GPUInfoGPUUUID]
+	}
+
+	var uuids []string
+	for _, minor := range minors {
+		uuid := uuidsByMinor[minor]
+		if uuid == "" {
+			return nil, false
+		}
+		uuids = append(uuids, uuid)
+	}
+	return uuids, true
+}
+
+// findNvidiaSMIMount returns the mount of the nvidia-smi executable in the
+// specified mounts. If nvidia-smi is not mounted into the container, or has
+// already been moved to the wrapped path, nil is returned.
+func findNvidiaSMIMount(mounts []specs.Mount) *specs.Mount {
+	var nvidiaSMIMount *specs.Mount
+	for i, mount := range mounts {
+		if mount.Destination == wrappedNvidiaSMIPath {
+			return nil
+		}
+		if nvidiaSMIMount == nil && filepath.Base(mount.Destination) == "nvidia-smi" {
+			nvidiaSMIMount = &mounts[i]
+		}
+	}
+	return nvidiaSMIMount
+}
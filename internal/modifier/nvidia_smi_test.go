@@ -0,0 +1,151 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/discover"
+)
+
+func TestNvidiaSMIWrapper(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	hostRoot := t.TempDir()
+	dir := filepath.Join(hostRoot, "proc/driver/nvidia/gpus", "0000:05:00.0")
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "information"), []byte("GPU UUID:        GPU-0\nBus Location:    0000:05:00.0\nDevice Minor:    0\n"), 0644))
+
+	nvidiaSMIMount := specs.Mount{
+		Source:      "/usr/bin/nvidia-smi",
+		Destination: "/usr/bin/nvidia-smi",
+		Options:     []string{"ro", "nosuid", "nodev", "rbind", "rprivate"},
+	}
+
+	testCases := []struct {
+		description    string
+		devices        []specs.LinuxDevice
+		mounts         []specs.Mount
+		expectedMounts []specs.Mount
+		expectedHooks  *specs.Hooks
+	}{
+		{
+			description: "no nvidia-smi mount is not modified",
+			devices: []specs.LinuxDevice{
+				{Path: "/dev/nvidia0"},
+			},
+			mounts: []specs.Mount{
+				{Source: "/usr/lib64/libcuda.so.1", Destination: "/usr/lib64/libcuda.so.1"},
+			},
+			expectedMounts: []specs.Mount{
+				{Source: "/usr/lib64/libcuda.so.1", Destination: "/usr/lib64/libcuda.so.1"},
+			},
+		},
+		{
+			description: "no gpus is not modified",
+			devices: []specs.LinuxDevice{
+				{Path: "/dev/nvidiactl"},
+			},
+			mounts:         []specs.Mount{nvidiaSMIMount},
+			expectedMounts: []specs.Mount{nvidiaSMIMount},
+		},
+		{
+			description: "unknown gpu uuid is not modified",
+			devices: []specs.LinuxDevice{
+				{Path: "/dev/nvidia0"},
+				{Path: "/dev/nvidia1"},
+			},
+			mounts:         []specs.Mount{nvidiaSMIMount},
+			expectedMounts: []specs.Mount{nvidiaSMIMount},
+		},
+		{
+			description: "nvidia-smi is wrapped",
+			devices: []specs.LinuxDevice{
+				{Path: "/dev/nvidiactl"},
+				{Path: "/dev/nvidia0"},
+			},
+			mounts: []specs.Mount{nvidiaSMIMount},
+			expectedMounts: []specs.Mount{
+				{
+					Source:      "/usr/bin/nvidia-smi",
+					Destination: "/usr/local/nvidia-container-toolkit/bin/nvidia-smi",
+					Options:     []string{"ro", "nosuid", "nodev", "rbind", "rprivate"},
+				},
+			},
+			expectedHooks: &specs.Hooks{
+				CreateContainer: []specs.Hook{
+					{
+						Path: "/usr/bin/nvidia-cdi-hook",
+						Args: []string{"nvidia-cdi-hook", "create-nvidia-smi-wrapper", "--path", "/usr/bin/nvidia-smi", "--nvidia-smi", "/usr/local/nvidia-container-toolkit/bin/nvidia-smi", "--device", "GPU-0"},
+						Env:  []string{"NVIDIA_CTK_DEBUG=false"},
+					},
+				},
+			},
+		},
+		{
+			description: "wrapped nvidia-smi is not modified",
+			devices: []specs.LinuxDevice{
+				{Path: "/dev/nvidia0"},
+			},
+			mounts: []specs.Mount{
+				nvidiaSMIMount,
+				{Source: "/usr/bin/nvidia-smi", Destination: "/usr/local/nvidia-container-toolkit/bin/nvidia-smi"},
+			},
+			expectedMounts: []specs.Mount{
+				nvidiaSMIMount,
+				{Source: "/usr/bin/nvidia-smi", Destination: "/usr/local/nvidia-container-toolkit/bin/nvidia-smi"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			toml, err := config.New()
+			require.NoError(t, err)
+			toml.Set("features.wrap-nvidia-smi", true)
+			cfg, err := toml.Config()
+			require.NoError(t, err)
+
+			m := NewNvidiaSMIWrapper(logger, cfg, discover.NewHookCreator())
+			require.NotNil(t, m)
+			m.(*nvidiaSMIWrapper).hostRoot = hostRoot
+
+			spec := &specs.Spec{
+				Mounts: tc.mounts,
+				Linux: &specs.Linux{
+					Devices: tc.devices,
+				},
+			}
+			require.NoError(t, m.Modify(spec))
+			require.EqualValues(t, tc.expectedMounts, spec.Mounts)
+			require.EqualValues(t, tc.expectedHooks, spec.Hooks)
+		})
+	}
+}
+
+func TestNvidiaSMIWrapperDisabled(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	require.Nil(t, NewNvidiaSMIWrapper(logger, &config.Config{}, discover.NewHookCreator()))
+}
@@ -0,0 +1,86 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"strings"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"golang.org/x/mod/semver"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/oci"
+)
+
+// createHooksVersion is the runtime-spec version that added the
+// createRuntime, createContainer, and startContainer hooks.
+const createHooksVersion = "v1.0.2"
+
+// ociVersionCompat adapts the modifications made to an OCI specification to
+// the runtime-spec version declared in the specification.
+type ociVersionCompat struct {
+	logger logger.Interface
+}
+
+var _ oci.SpecModifier = (*ociVersionCompat)(nil)
+
+// NewOCIVersionCompatModifier creates a modifier that ensures that the hooks in
+// an OCI specification are supported by the runtime-spec version declared by
+// its ociVersion field. This is required since the edits applied by other
+// modifiers target the vendored runtime-spec version.
+func NewOCIVersionCompatModifier(logger logger.Interface) oci.SpecModifier {
+	return &ociVersionCompat{
+		logger: logger,
+	}
+}
+
+// Modify converts createRuntime and createContainer hooks to prestart hooks
+// for specifications that declare a runtime-spec version that does not
+// support these. Specifications with an unknown version are not modified.
+func (m *ociVersionCompat) Modify(spec *specs.Spec) error {
+	if spec == nil || spec.Hooks == nil {
+		return nil
+	}
+	if !isBeforeVersion(spec.Version, createHooksVersion) {
+		return nil
+	}
+
+	if len(spec.Hooks.CreateRuntime) > 0 || len(spec.Hooks.CreateContainer) > 0 {
+		m.logger.Debugf("Converting createRuntime and createContainer hooks to prestart hooks for OCI version %v", spec.Version)
+		spec.Hooks.Prestart = append(spec.Hooks.Prestart, spec.Hooks.CreateRuntime...)
+		spec.Hooks.Prestart = append(spec.Hooks.Prestart, spec.Hooks.CreateContainer...)
+		spec.Hooks.CreateRuntime = nil
+		spec.Hooks.CreateContainer = nil
+	}
+	if len(spec.Hooks.StartContainer) > 0 {
+		m.logger.Warningf("OCI version %v does not support startContainer hooks; these may be ignored", spec.Version)
+	}
+	return nil
+}
+
+// isBeforeVersion checks whether the specified OCI version is before the
+// specified semantic version. Pre-release suffixes such as -dev are ignored,
+// since these are used for development versions of the same release.
+// False is returned for invalid versions.
+func isBeforeVersion(ociVersion string, version string) bool {
+	v := semver.Canonical("v" + strings.TrimPrefix(ociVersion, "v"))
+	if v == "" {
+		return false
+	}
+	v, _, _ = strings.Cut(v, "-")
+	return semver.Compare(v, version) < 0
+}
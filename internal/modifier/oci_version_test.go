@@ -0,0 +1,90 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"testing"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOCIVersionCompatModifier(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	prestart := specs.Hook{Path: "/usr/bin/prestart"}
+	createRuntime := specs.Hook{Path: "/usr/bin/create-runtime"}
+	createContainer := specs.Hook{Path: "/usr/bin/create-container"}
+
+	testCases := []struct {
+		description   string
+		version       string
+		expectedHooks *specs.Hooks
+	}{
+		{
+			description: "supported version is not modified",
+			version:     "1.2.0",
+			expectedHooks: &specs.Hooks{
+				Prestart:        []specs.Hook{prestart},
+				CreateRuntime:   []specs.Hook{createRuntime},
+				CreateContainer: []specs.Hook{createContainer},
+			},
+		},
+		{
+			description: "development version of supported version is not modified",
+			version:     "1.0.2-dev",
+			expectedHooks: &specs.Hooks{
+				Prestart:        []specs.Hook{prestart},
+				CreateRuntime:   []specs.Hook{createRuntime},
+				CreateContainer: []specs.Hook{createContainer},
+			},
+		},
+		{
+			description: "invalid version is not modified",
+			version:     "",
+			expectedHooks: &specs.Hooks{
+				Prestart:        []specs.Hook{prestart},
+				CreateRuntime:   []specs.Hook{createRuntime},
+				CreateContainer: []specs.Hook{createContainer},
+			},
+		},
+		{
+			description: "hooks are converted for older version",
+			version:     "1.0.1",
+			expectedHooks: &specs.Hooks{
+				Prestart: []specs.Hook{prestart, createRuntime, createContainer},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			spec := &specs.Spec{
+				Version: tc.version,
+				Hooks: &specs.Hooks{
+					Prestart:        []specs.Hook{prestart},
+					CreateRuntime:   []specs.Hook{createRuntime},
+					CreateContainer: []specs.Hook{createContainer},
+				},
+			}
+
+			require.NoError(t, NewOCIVersionCompatModifier(logger).Modify(spec))
+			require.EqualValues(t, tc.expectedHooks, spec.Hooks)
+		})
+	}
+}
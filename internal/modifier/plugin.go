@@ -0,0 +1,98 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"time"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/oci"
+)
+
+// pluginModifier is a spec modifier that delegates the modification of the
+// OCI runtime specification to an external executable.
+type pluginModifier struct {
+	logger  logger.Interface
+	path    string
+	timeout time.Duration
+}
+
+var _ oci.SpecModifier = (*pluginModifier)(nil)
+
+// NewPluginModifiers creates a modifier for each of the specified plugin
+// executables. The modifiers are applied in the order specified and a plugin
+// that does not complete within the specified timeout is killed.
+func NewPluginModifiers(logger logger.Interface, timeout time.Duration, paths ...string) oci.SpecModifier {
+	var modifiers List
+	for _, path := range paths {
+		modifiers = append(modifiers, pluginModifier{logger: logger, path: path, timeout: timeout})
+	}
+	return modifiers
+}
+
+// Modify runs the plugin executable with the JSON-encoded spec as input and
+// replaces the spec with the JSON-encoded spec output by the plugin. Since the
+// output is decoded as an OCI runtime specification, fields that are not
+// supported by the vendored runtime-spec version are dropped. This differs
+// from the handling of the spec that is read from the bundle where these
+// fields are preserved.
+func (m pluginModifier) Modify(spec *specs.Spec) error {
+	if spec == nil {
+		return nil
+	}
+
+	input, err := json.Marshal(spec)
+	if err != nil {
+		return fmt.Errorf("failed to encode OCI spec: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), m.timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, m.path)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// Processes started by the plugin may hold its output open after it is
+	// killed. We bound the time that we wait for the output in this case.
+	cmd.WaitDelay = time.Second
+
+	m.logger.Debugf("Running modifier plugin %v", m.path)
+	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("modifier plugin %v did not complete within %v", m.path, m.timeout)
+		}
+		return fmt.Errorf("modifier plugin %v failed: %w: %s", m.path, err, bytes.TrimSpace(stderr.Bytes()))
+	}
+
+	var modified specs.Spec
+	if err := json.Unmarshal(stdout.Bytes(), &modified); err != nil {
+		return fmt.Errorf("failed to decode OCI spec from modifier plugin %v: %w", m.path, err)
+	}
+	*spec = modified
+
+	return nil
+}
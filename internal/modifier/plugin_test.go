@@ -0,0 +1,100 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPluginModifiers(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	testCases := []struct {
+		description   string
+		plugins       []string
+		spec          *specs.Spec
+		expectedError bool
+		expectedSpec  *specs.Spec
+	}{
+		{
+			description:  "no plugins leaves spec unchanged",
+			spec:         &specs.Spec{Hostname: "host"},
+			expectedSpec: &specs.Spec{Hostname: "host"},
+		},
+		{
+			description:  "passthrough plugin leaves spec unchanged",
+			plugins:      []string{"cat"},
+			spec:         &specs.Spec{Hostname: "host"},
+			expectedSpec: &specs.Spec{Hostname: "host"},
+		},
+		{
+			description: "plugin output replaces spec",
+			plugins: []string{
+				`cat > /dev/null; echo '{"ociVersion": "1.0.0", "hostname": "plugin"}'`,
+			},
+			spec:         &specs.Spec{Hostname: "host"},
+			expectedSpec: &specs.Spec{Version: "1.0.0", Hostname: "plugin"},
+		},
+		{
+			description:   "failing plugin returns error",
+			plugins:       []string{"exit 1"},
+			spec:          &specs.Spec{Hostname: "host"},
+			expectedError: true,
+			expectedSpec:  &specs.Spec{Hostname: "host"},
+		},
+		{
+			description:   "invalid plugin output returns error",
+			plugins:       []string{"cat > /dev/null; echo invalid"},
+			spec:          &specs.Spec{Hostname: "host"},
+			expectedError: true,
+			expectedSpec:  &specs.Spec{Hostname: "host"},
+		},
+		{
+			description:   "plugin that does not complete returns error",
+			plugins:       []string{"sleep 10"},
+			spec:          &specs.Spec{Hostname: "host"},
+			expectedError: true,
+			expectedSpec:  &specs.Spec{Hostname: "host"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			var paths []string
+			for i, plugin := range tc.plugins {
+				path := filepath.Join(t.TempDir(), "plugin")
+				require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+plugin+"\n"), 0755), "plugin %d", i)
+				paths = append(paths, path)
+			}
+
+			err := NewPluginModifiers(logger, 100*time.Millisecond, paths...).Modify(tc.spec)
+			if tc.expectedError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.EqualValues(t, tc.expectedSpec, tc.spec)
+		})
+	}
+}
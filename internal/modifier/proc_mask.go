@@ -0,0 +1,76 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"slices"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/oci"
+)
+
+// gpuProcMasker masks the /proc/driver/nvidia/gpus entries of GPUs that are
+// not injected into a container.
+type gpuProcMasker struct {
+	logger   logger.Interface
+	hostRoot string
+}
+
+var _ oci.SpecModifier = (*gpuProcMasker)(nil)
+
+// NewGPUProcMasker creates a modifier that adds the /proc/driver/nvidia/gpus
+// folders of GPUs that are not injected into a container to the masked paths
+// of the container. This prevents containers with a subset of the GPUs from
+// enumerating sibling devices through procfs.
+// A nil modifier is returned if the feature is not enabled.
+func NewGPUProcMasker(logger logger.Interface, cfg *config.Config) oci.SpecModifier {
+	if !cfg.Features.MaskUnrequestedGPUProcEntries.IsEnabled() {
+		return nil
+	}
+	return &gpuProcMasker{
+		logger:   logger,
+		hostRoot: "/",
+	}
+}
+
+// Modify adds the procfs entries of GPUs that are not in the spec to the
+// masked paths. Containers without GPU device nodes are not modified.
+func (m *gpuProcMasker) Modify(spec *specs.Spec) error {
+	if spec == nil || spec.Linux == nil {
+		return nil
+	}
+
+	minors := getGPUDeviceMinors(spec.Linux.Devices)
+	if len(minors) == 0 {
+		return nil
+	}
+
+	for _, gpu := range getHostGPUs(m.logger, m.hostRoot) {
+		if slices.Contains(minors, gpu.minor) {
+			continue
+		}
+		if slices.Contains(spec.Linux.MaskedPaths, gpu.procPath) {
+			continue
+		}
+		m.logger.Debugf("Masking %v", gpu.procPath)
+		spec.Linux.MaskedPaths = append(spec.Linux.MaskedPaths, gpu.procPath)
+	}
+	return nil
+}
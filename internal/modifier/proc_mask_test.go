@@ -0,0 +1,102 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGPUProcMasker(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	hostRoot := t.TempDir()
+	for busID, information := range map[string]string{
+		"0000:05:00.0": "GPU UUID:        GPU-0\nBus Location:    0000:05:00.0\nDevice Minor:    0\n",
+		"0000:02:00.0": "GPU UUID:        GPU-1\nBus Location:    0000:02:00.0\nDevice Minor:    1\n",
+	} {
+		dir := filepath.Join(hostRoot, "proc/driver/nvidia/gpus", busID)
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "information"), []byte(information), 0644))
+	}
+
+	testCases := []struct {
+		description         string
+		devices             []specs.LinuxDevice
+		maskedPaths         []string
+		expectedMaskedPaths []string
+	}{
+		{
+			description: "no gpus are not masked",
+			devices: []specs.LinuxDevice{
+				{Path: "/dev/nvidiactl"},
+			},
+		},
+		{
+			description: "all gpus are not masked",
+			devices: []specs.LinuxDevice{
+				{Path: "/dev/nvidia0"},
+				{Path: "/dev/nvidia1"},
+			},
+		},
+		{
+			description: "unrequested gpu is masked",
+			devices: []specs.LinuxDevice{
+				{Path: "/dev/nvidiactl"},
+				{Path: "/dev/nvidia0"},
+			},
+			maskedPaths: []string{"/proc/kcore"},
+			expectedMaskedPaths: []string{
+				"/proc/kcore",
+				"/proc/driver/nvidia/gpus/0000:02:00.0",
+			},
+		},
+		{
+			description: "existing masked path is not duplicated",
+			devices: []specs.LinuxDevice{
+				{Path: "/dev/nvidia1"},
+			},
+			maskedPaths: []string{"/proc/driver/nvidia/gpus/0000:05:00.0"},
+			expectedMaskedPaths: []string{
+				"/proc/driver/nvidia/gpus/0000:05:00.0",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			m := &gpuProcMasker{
+				logger:   logger,
+				hostRoot: hostRoot,
+			}
+
+			spec := &specs.Spec{
+				Linux: &specs.Linux{
+					Devices:     tc.devices,
+					MaskedPaths: tc.maskedPaths,
+				},
+			}
+			require.NoError(t, m.Modify(spec))
+			require.EqualValues(t, tc.expectedMaskedPaths, spec.Linux.MaskedPaths)
+		})
+	}
+}
@@ -0,0 +1,140 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config/image"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/discover"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup/root"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/oci"
+)
+
+const (
+	// profilingAdminOnlyParam is the name of the parameter in
+	// /proc/driver/nvidia/params that reflects the value of the
+	// NVreg_RestrictProfilingToAdminUsers module parameter.
+	profilingAdminOnlyParam = "RmProfilingAdminOnly"
+)
+
+// profilingModifier injects the libraries required by profiling tools and
+// checks whether access to the GPU performance counters is possible in the
+// container.
+type profilingModifier struct {
+	logger      logger.Interface
+	libraries   oci.SpecModifier
+	adminOnly   bool
+	paramsError error
+}
+
+// NewProfilingModifier creates a modifier that injects the CUPTI and libnvperf
+// libraries of the CUDA Toolkit installed in the driver root into containers
+// that explicitly request the profiling driver capability. Since these
+// libraries are not part of the driver, they are not injected if all driver
+// capabilities are requested.
+//
+// Access to the GPU performance counters is not changed by this modifier.
+// Since this is restricted to admin users by default, a warning is logged if
+// the container does not have the CAP_SYS_ADMIN capability in this case.
+func NewProfilingModifier(logger logger.Interface, container image.CUDA, driver *root.Driver, hookCreator discover.HookCreator) (oci.SpecModifier, error) {
+	if len(container.VisibleDevices()) == 0 {
+		logger.Infof("No profiling modifier required; no devices requested")
+		return nil, nil
+	}
+	// We explicitly index the capabilities instead of using Has so that the
+	// profiling capability is not included in "all".
+	if !container.GetDriverCapabilities()[image.DriverCapabilityProfiling] {
+		logger.Infof("No profiling modifier required; profiling capability not requested")
+		return nil, nil
+	}
+
+	libraries, err := discover.NewProfilingDiscoverer(logger, driver.Root, hookCreator, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct discoverer for profiling libraries: %w", err)
+	}
+	librariesModifier, err := NewModifierFromDiscoverer(logger, libraries)
+	if err != nil {
+		return nil, err
+	}
+
+	adminOnly, err := isProfilingAdminOnly("/")
+	m := profilingModifier{
+		logger:      logger,
+		libraries:   librariesModifier,
+		adminOnly:   adminOnly,
+		paramsError: err,
+	}
+	return &m, nil
+}
+
+// Modify injects the profiling libraries into the specified OCI spec.
+func (m *profilingModifier) Modify(spec *specs.Spec) error {
+	switch {
+	case m.paramsError != nil:
+		m.logger.Warningf("Failed to check whether profiling is restricted to admin users: %v", m.paramsError)
+	case m.adminOnly && !hasCapability(spec, "CAP_SYS_ADMIN"):
+		m.logger.Warningf("Access to GPU performance counters is restricted to admin users (NVreg_RestrictProfilingToAdminUsers=1); " +
+			"profiling tools in the container require CAP_SYS_ADMIN or the module parameter must be set to 0 on the host")
+	}
+
+	return m.libraries.Modify(spec)
+}
+
+// isProfilingAdminOnly checks whether access to the GPU performance counters
+// is restricted to admin users as reported in /proc/driver/nvidia/params.
+func isProfilingAdminOnly(hostRoot string) (bool, error) {
+	params, err := os.Open(filepath.Join(hostRoot, "/proc/driver/nvidia/params"))
+	if err != nil {
+		return false, err
+	}
+	defer params.Close()
+
+	scanner := bufio.NewScanner(params)
+	for scanner.Scan() {
+		name, value, found := strings.Cut(scanner.Text(), ":")
+		if !found || strings.TrimSpace(name) != profilingAdminOnlyParam {
+			continue
+		}
+		return strings.TrimSpace(value) != "0", nil
+	}
+	if err := scanner.Err(); err != nil {
+		return false, err
+	}
+	return false, fmt.Errorf("%v not found", profilingAdminOnlyParam)
+}
+
+// hasCapability checks whether the specified capability is in the effective
+// set of the container process.
+func hasCapability(spec *specs.Spec, capability string) bool {
+	if spec == nil || spec.Process == nil || spec.Process.Capabilities == nil {
+		return false
+	}
+	for _, c := range spec.Process.Capabilities.Effective {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config/image"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/discover"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup/root"
+)
+
+func TestIsProfilingAdminOnly(t *testing.T) {
+	testCases := []struct {
+		description       string
+		params            string
+		expectedAdminOnly bool
+		expectedError     bool
+	}{
+		{
+			description:       "restricted to admin users",
+			params:            "ResmanDebugLevel: 4294967295\nRmProfilingAdminOnly: 1\n",
+			expectedAdminOnly: true,
+		},
+		{
+			description: "not restricted",
+			params:      "RmProfilingAdminOnly: 0\n",
+		},
+		{
+			description:   "parameter missing",
+			params:        "ResmanDebugLevel: 4294967295\n",
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			hostRoot := t.TempDir()
+			require.NoError(t, os.MkdirAll(filepath.Join(hostRoot, "proc/driver/nvidia"), 0755))
+			require.NoError(t, os.WriteFile(filepath.Join(hostRoot, "proc/driver/nvidia/params"), []byte(tc.params), 0644))
+
+			adminOnly, err := isProfilingAdminOnly(hostRoot)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedAdminOnly, adminOnly)
+		})
+	}
+}
+
+func TestNewProfilingModifier(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+	driver := root.New(root.WithDriverRoot(t.TempDir()))
+
+	testCases := []struct {
+		description      string
+		env              []string
+		expectedModifier bool
+	}{
+		{
+			description: "no devices requested",
+			env:         []string{"NVIDIA_DRIVER_CAPABILITIES=profiling"},
+		},
+		{
+			description: "all capabilities do not include profiling",
+			env:         []string{"NVIDIA_VISIBLE_DEVICES=all", "NVIDIA_DRIVER_CAPABILITIES=all"},
+		},
+		{
+			description:      "profiling capability requested",
+			env:              []string{"NVIDIA_VISIBLE_DEVICES=all", "NVIDIA_DRIVER_CAPABILITIES=compute,profiling"},
+			expectedModifier: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			container, err := image.New(image.WithLogger(logger), image.WithEnv(tc.env))
+			require.NoError(t, err)
+
+			m, err := NewProfilingModifier(logger, container, driver, discover.NewHookCreator())
+			require.NoError(t, err)
+			if tc.expectedModifier {
+				require.NotNil(t, m)
+			} else {
+				require.Nil(t, m)
+			}
+		})
+	}
+}
@@ -58,23 +58,35 @@ func NewComputeInstanceCap(gpu, gi, ci int) MigCap {
 }
 
 // GetCapDevicePath returns the path to the cap device for the specified cap.
+// If the cap is not included in the MIG minors file, the minor is read from
+// the associated file in /proc/driver/nvidia/capabilities instead.
 // An error is returned if the cap is invalid.
 func (m MigCaps) GetCapDevicePath(cap MigCap) (string, error) {
 	minor, exists := m[cap]
-	if !exists {
-		return "", fmt.Errorf("invalid MIG capability path %v", cap)
+	if exists {
+		return minor.DevicePath(), nil
+	}
+	minor, err := cap.minorFromProc()
+	if err != nil {
+		return "", fmt.Errorf("invalid MIG capability path %v: %w", cap, err)
 	}
 	return minor.DevicePath(), nil
 }
 
 // NewMigCaps creates a MigCaps structure based on the contents of the MIG minors file.
 func NewMigCaps() (MigCaps, error) {
+	return NewMigCapsFromRoot("/")
+}
+
+// NewMigCapsFromRoot creates a MigCaps structure based on the contents of the
+// MIG minors file in the specified root.
+func NewMigCapsFromRoot(root string) (MigCaps, error) {
 	// Open nvcapsMigMinorsPath for walking.
 	// If the nvcapsMigMinorsPath does not exist, then we are not on a MIG
 	// capable machine, so there is nothing to do.
 	// The format of this file is discussed in:
 	//     https://docs.nvidia.com/datacenter/tesla/mig-user-guide/index.html#unique_1576522674
-	minorsFile, err := os.Open(nvcapsMigMinorsPath)
+	minorsFile, err := os.Open(filepath.Join(root, nvcapsMigMinorsPath))
 	if os.IsNotExist(err) {
 		return nil, nil
 	}
@@ -144,6 +156,16 @@ func (m MigCap) isValid() bool {
 	return false
 }
 
+// GPUMinor returns the minor number of the GPU that the MIG capability belongs
+// to. The config and monitor capabilities do not belong to a GPU.
+func (m MigCap) GPUMinor() (int, bool) {
+	var gpu int
+	if n, _ := fmt.Sscanf(string(m), "gpu%d/", &gpu); n != 1 {
+		return 0, false
+	}
+	return gpu, true
+}
+
 // ProcPath returns the proc path associated with the MIG capability
 func (m MigCap) ProcPath() string {
 	id := string(m)
@@ -159,6 +181,46 @@ func (m MigCap) ProcPath() string {
 	return filepath.Join(nvidiaCapabilitiesPath, path)
 }
 
+// minorFromProc reads the device file minor for the MIG capability from its
+// proc path.
+func (m MigCap) minorFromProc() (MigMinor, error) {
+	if !m.isValid() {
+		return 0, fmt.Errorf("invalid MIG capability")
+	}
+	capFile, err := os.Open(m.ProcPath())
+	if err != nil {
+		return 0, fmt.Errorf("error opening capability file: %w", err)
+	}
+	defer capFile.Close()
+
+	return processCapabilityFile(capFile)
+}
+
+// processCapabilityFile extracts the device file minor from the contents of a
+// capability file. Such a file has the form:
+//
+//	DeviceFileMinor: 21
+//	DeviceFileMode: 292
+//	DeviceFileModify: 1
+func processCapabilityFile(capFile io.Reader) (MigMinor, error) {
+	scanner := bufio.NewScanner(capFile)
+	for scanner.Scan() {
+		key, value, found := strings.Cut(scanner.Text(), ":")
+		if !found || strings.TrimSpace(key) != "DeviceFileMinor" {
+			continue
+		}
+		minor, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return 0, fmt.Errorf("error reading device file minor from '%v': %v", value, err)
+		}
+		return MigMinor(minor), nil
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("error reading capability file: %w", err)
+	}
+	return 0, fmt.Errorf("no device file minor found")
+}
+
 // DevicePath returns the path for the nvidia-caps device with the specified
 // minor number
 func (m MigMinor) DevicePath() string {
@@ -94,7 +94,64 @@ func TestMigCapProcPaths(t *testing.T) {
 	}
 }
 
+func TestMigCapGPUMinor(t *testing.T) {
+	testCases := []struct {
+		input         string
+		expectedMinor int
+		expectedOk    bool
+	}{
+		{"config", 0, false},
+		{"monitor", 0, false},
+		{"gpu0/gi0/access", 0, true},
+		{"gpu3/gi1/ci0/access", 3, true},
+	}
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("testcase %d", i), func(t *testing.T) {
+			minor, ok := MigCap(tc.input).GPUMinor()
+			require.Equal(t, tc.expectedOk, ok)
+			require.Equal(t, tc.expectedMinor, minor)
+		})
+	}
+}
+
 func TestMigMinorDevicePath(t *testing.T) {
 	m := MigMinor(0)
 	require.Equal(t, "/dev/nvidia-caps/nvidia-cap0", m.DevicePath())
 }
+
+func TestProcessCapabilityFile(t *testing.T) {
+	testCases := []struct {
+		description   string
+		lines         []string
+		expected      MigMinor
+		expectedError bool
+	}{
+		{
+			description: "valid file",
+			lines:       []string{"DeviceFileMinor: 21", "DeviceFileMode: 292", "DeviceFileModify: 1"},
+			expected:    21,
+		},
+		{
+			description:   "empty file",
+			lines:         []string{},
+			expectedError: true,
+		},
+		{
+			description:   "invalid minor",
+			lines:         []string{"DeviceFileMinor: notanint"},
+			expectedError: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			contents := strings.NewReader(strings.Join(tc.lines, "\n"))
+			minor, err := processCapabilityFile(contents)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, minor)
+		})
+	}
+}
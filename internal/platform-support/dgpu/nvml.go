@@ -78,7 +78,9 @@ type requiredMigInfo interface {
 }
 
 func (o *options) newNvmlMigDiscoverer(d requiredMigInfo) (discover.Discover, error) {
-	if o.migCaps == nil || o.migCapsError != nil {
+	// If the MIG minors file is not present, the cap device paths are read
+	// from /proc/driver/nvidia/capabilities instead.
+	if o.migCapsError != nil {
 		return nil, fmt.Errorf("error getting MIG capability device paths: %v", o.migCapsError)
 	}
 
@@ -87,6 +89,7 @@ func (o *options) newNvmlMigDiscoverer(d requiredMigInfo) (discover.Discover, er
 		return nil, fmt.Errorf("error getting placement info: %w", err)
 	}
 
+	// Only the caps associated with the requested MIG device are injected.
 	giCap := nvcaps.NewGPUInstanceCap(gpu, gi)
 	giCapDevicePath, err := o.migCaps.GetCapDevicePath(giCap)
 	if err != nil {
@@ -19,6 +19,7 @@ package tegra
 import (
 	"fmt"
 
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config/image"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/discover"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup"
@@ -34,7 +35,7 @@ func (o tegraOptions) newDiscovererFromCSVFiles() (discover.Discover, error) {
 		return discover.None{}, nil
 	}
 
-	targetsByType := getTargetsFromCSVFiles(o.logger, o.csvFiles)
+	targetsByType := getTargetsFromCSVFiles(o.logger, o.csvFiles, o.driverCapabilities)
 
 	devices := discover.NewCharDeviceDiscoverer(
 		o.logger,
@@ -53,11 +54,16 @@ func (o tegraOptions) newDiscovererFromCSVFiles() (discover.Discover, error) {
 	// symlinks for the driver.
 	libraries := discover.WithDriverDotSoSymlinks(
 		o.logger,
-		discover.NewMounts(
+		discover.NewCapabilityFilter(
 			o.logger,
-			o.symlinkLocator,
-			o.driverRoot,
-			targetsByType[csv.MountSpecLib],
+			discover.NewMounts(
+				o.logger,
+				o.symlinkLocator,
+				o.driverRoot,
+				targetsByType[csv.MountSpecLib],
+			),
+			o.libraryGroups,
+			o.driverCapabilities,
 		),
 		"",
 		o.hookCreator,
@@ -86,10 +92,11 @@ func (o tegraOptions) newDiscovererFromCSVFiles() (discover.Discover, error) {
 }
 
 // getTargetsFromCSVFiles returns the list of mount specs from the specified CSV files.
-// These are aggregated by mount spec type.
+// These are aggregated by mount spec type. Mount specs that are not required
+// for the specified driver capabilities are skipped.
 // TODO: We use a function variable here to allow this to be overridden for testing.
 // This should be properly mocked.
-var getTargetsFromCSVFiles = func(logger logger.Interface, files []string) map[csv.MountSpecType][]string {
+var getTargetsFromCSVFiles = func(logger logger.Interface, files []string, capabilities image.DriverCapabilities) map[csv.MountSpecType][]string {
 	targetsByType := make(map[csv.MountSpecType][]string)
 	for _, filename := range files {
 		targets, err := loadCSVFile(logger, filename)
@@ -98,6 +105,10 @@ var getTargetsFromCSVFiles = func(logger logger.Interface, files []string) map[c
 			continue
 		}
 		for _, t := range targets {
+			if !t.IsRequiredFor(capabilities) {
+				logger.Debugf("Skipping %v; not required for driver capabilities %v", t.Path, capabilities)
+				continue
+			}
 			targetsByType[t.Type] = append(targetsByType[t.Type], t.Path)
 		}
 	}
@@ -107,7 +118,7 @@ var getTargetsFromCSVFiles = func(logger logger.Interface, files []string) map[c
 // loadCSVFile loads the specified CSV file and returns the list of mount specs
 func loadCSVFile(logger logger.Interface, filename string) ([]*csv.MountSpec, error) {
 	// Create a discoverer for each file-kind combination
-	targets, err := csv.NewCSVFileParser(logger, filename).Parse()
+	targets, err := csv.NewFileParser(logger, filename).Parse()
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse CSV file: %v", err)
 	}
@@ -49,9 +49,11 @@ func DefaultFileList() []string {
 	return paths
 }
 
-// GetFileList returns the (non-recursive) list of CSV files in the specified
-// folder
-func GetFileList(root string) ([]string, error) {
+// GetFileList returns the (non-recursive) list of CSV and YAML mount spec
+// files in the specified folder. If both a CSV and a YAML file exist for the
+// same base name (e.g. after converting a CSV file), only the YAML file is
+// returned and a warning is logged.
+func GetFileList(logger logger.Interface, root string) ([]string, error) {
 	contents, err := os.ReadDir(root)
 	if err != nil && errors.Is(err, os.ErrNotExist) {
 		return nil, nil
@@ -60,36 +62,85 @@ func GetFileList(root string) ([]string, error) {
 	}
 
 	var csvFilePaths []string
+	indices := make(map[string]int)
 	for _, c := range contents {
 		if c.IsDir() {
 			continue
 		}
-		if c.Name() == ".csv" {
+		ext := strings.ToLower(filepath.Ext(c.Name()))
+		if c.Name() == ext {
 			continue
 		}
-		ext := strings.ToLower(filepath.Ext(c.Name()))
-		if ext != ".csv" {
+		if ext != ".csv" && !IsYAMLFile(c.Name()) {
 			continue
 		}
 
-		csvFilePaths = append(csvFilePaths, filepath.Join(root, c.Name()))
+		file := filepath.Join(root, c.Name())
+		name := baseName(file)
+		i, ok := indices[name]
+		if !ok {
+			indices[name] = len(csvFilePaths)
+			csvFilePaths = append(csvFilePaths, file)
+			continue
+		}
+		existing := csvFilePaths[i]
+		if IsYAMLFile(file) && !IsYAMLFile(existing) {
+			csvFilePaths[i] = file
+			file, existing = existing, file
+		}
+		logger.Warningf("Ignoring mount spec file %v since %v has the same name", file, existing)
 	}
 
 	return csvFilePaths, nil
 }
 
+// baseName returns the name of the specified file without its extension.
+func baseName(file string) string {
+	base := filepath.Base(file)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// GetMountSpecFileList returns the list of CSV files in the directories of the
+// specified comma-separated mount spec path. The directories are scanned in
+// order with files in later directories overriding files with the same base
+// name in earlier directories, regardless of their format.
+func GetMountSpecFileList(logger logger.Interface, mountSpecPath string) ([]string, error) {
+	var csvFilePaths []string
+	indices := make(map[string]int)
+	for _, root := range strings.Split(mountSpecPath, ",") {
+		root = strings.TrimSpace(root)
+		if root == "" {
+			continue
+		}
+		files, err := GetFileList(logger, root)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			name := baseName(file)
+			if i, ok := indices[name]; ok {
+				csvFilePaths[i] = file
+				continue
+			}
+			indices[name] = len(csvFilePaths)
+			csvFilePaths = append(csvFilePaths, file)
+		}
+	}
+	return csvFilePaths, nil
+}
+
 // BaseFilesOnly filters out non-base CSV files from the list of CSV files.
+// YAML files with the same base names are also considered base files.
 func BaseFilesOnly(filenames []string) []string {
 	filter := map[string]bool{
-		"l4t.csv":     true,
-		"drivers.csv": true,
-		"devices.csv": true,
+		"l4t":     true,
+		"drivers": true,
+		"devices": true,
 	}
 
 	var selected []string
 	for _, file := range filenames {
-		base := filepath.Base(file)
-		if filter[base] {
+		if filter[baseName(file)] {
 			selected = append(selected, file)
 		}
 	}
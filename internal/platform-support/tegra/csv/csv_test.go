@@ -17,15 +17,18 @@
 package csv
 
 import (
+	"os"
 	"path/filepath"
 	"testing"
 
+	testlog "github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/require"
 
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/test"
 )
 
 func TestGetFileList(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
 	moduleRoot, _ := test.GetModuleRoot()
 
 	testCases := []struct {
@@ -61,7 +64,7 @@ func TestGetFileList(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.description, func(t *testing.T) {
 			root := filepath.Join(moduleRoot, tc.root)
-			files, err := GetFileList(root)
+			files, err := GetFileList(logger, root)
 
 			if tc.expectedError != nil {
 				require.Error(t, err)
@@ -82,3 +85,59 @@ func TestGetFileList(t *testing.T) {
 		})
 	}
 }
+
+func TestGetFileListPrefersYAML(t *testing.T) {
+	logger, hook := testlog.NewNullLogger()
+	root := t.TempDir()
+	for _, file := range []string{"drivers.csv", "drivers.yaml", "devices.csv"} {
+		require.NoError(t, os.WriteFile(filepath.Join(root, file), nil, 0644))
+	}
+
+	files, err := GetFileList(logger, root)
+	require.NoError(t, err)
+	require.ElementsMatch(t,
+		[]string{
+			filepath.Join(root, "devices.csv"),
+			filepath.Join(root, "drivers.yaml"),
+		},
+		files,
+	)
+	require.Len(t, hook.AllEntries(), 1)
+}
+
+func TestGetMountSpecFileList(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+	vendorDir := t.TempDir()
+	userDir := t.TempDir()
+	for _, file := range []string{
+		filepath.Join(vendorDir, "devices.csv"),
+		filepath.Join(vendorDir, "drivers.csv"),
+		filepath.Join(userDir, "drivers.csv"),
+		filepath.Join(userDir, "extra.csv"),
+		filepath.Join(userDir, "l4t.yaml"),
+		filepath.Join(vendorDir, "l4t.csv"),
+	} {
+		require.NoError(t, os.WriteFile(file, nil, 0644))
+	}
+
+	files, err := GetMountSpecFileList(logger, vendorDir+", "+userDir+",/NONEXISTENT")
+	require.NoError(t, err)
+	require.EqualValues(t,
+		[]string{
+			filepath.Join(vendorDir, "devices.csv"),
+			filepath.Join(userDir, "drivers.csv"),
+			filepath.Join(userDir, "l4t.yaml"),
+			filepath.Join(userDir, "extra.csv"),
+		},
+		files,
+	)
+
+	require.EqualValues(t,
+		[]string{
+			filepath.Join(vendorDir, "devices.csv"),
+			filepath.Join(userDir, "drivers.csv"),
+			filepath.Join(userDir, "l4t.yaml"),
+		},
+		BaseFilesOnly(files),
+	)
+}
@@ -19,6 +19,8 @@ package csv
 import (
 	"fmt"
 	"strings"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config/image"
 )
 
 // MountSpecType defines the mount types allowed in a CSV file
@@ -36,21 +38,73 @@ const (
 )
 
 // MountSpec represents a Jetson mount consisting of a type and a path.
+// A mount spec can optionally be associated with a set of driver capabilities,
+// in which case it is only required if one of these is requested.
 type MountSpec struct {
-	Type MountSpecType
-	Path string
+	Type         MountSpecType
+	Path         string
+	Capabilities []string
 }
 
-// NewMountSpecFromLine parses the specified line and returns the MountSpec or an error if the line is malformed
+// NewMountSpecFromLine parses the specified line and returns the MountSpec or an error if the line is malformed.
+// A line has the form:
+//
+//	type, path[, capability[;capability...]]
+//
+// where the optional last column lists the driver capabilities (e.g. video;graphics)
+// that the mount is associated with.
 func NewMountSpecFromLine(line string) (*MountSpec, error) {
 	parts := strings.SplitN(strings.TrimSpace(line), ",", 2)
 	if len(parts) < 2 {
 		return nil, fmt.Errorf("failed to parse line: %v", line)
 	}
 	mountType := strings.TrimSpace(parts[0])
-	path := strings.TrimSpace(parts[1])
+	path, capabilities := splitCapabilities(strings.TrimSpace(parts[1]))
+
+	mountSpec, err := NewMountSpec(mountType, path)
+	if err != nil {
+		return nil, err
+	}
+	mountSpec.Capabilities = capabilities
+
+	return mountSpec, nil
+}
+
+// splitCapabilities splits the optional capability column from the specified
+// path. Since paths may contain commas, the last column is only considered
+// to be a capability column if it consists solely of supported driver
+// capabilities.
+func splitCapabilities(path string) (string, []string) {
+	idx := strings.LastIndex(path, ",")
+	if idx < 0 {
+		return path, nil
+	}
 
-	return NewMountSpec(mountType, path)
+	var capabilities []string
+	for _, c := range strings.Split(path[idx+1:], ";") {
+		capability := strings.TrimSpace(c)
+		if !image.SupportedDriverCapabilities.Has(image.DriverCapability(capability)) {
+			return path, nil
+		}
+		capabilities = append(capabilities, capability)
+	}
+
+	return strings.TrimSpace(path[:idx]), capabilities
+}
+
+// IsRequiredFor checks whether the mount spec is required for the specified
+// driver capabilities. Mount specs with no associated capabilities are always
+// required.
+func (m MountSpec) IsRequiredFor(capabilities image.DriverCapabilities) bool {
+	if len(m.Capabilities) == 0 || capabilities == nil {
+		return true
+	}
+	for _, c := range m.Capabilities {
+		if capabilities.Has(image.DriverCapability(c)) {
+			return true
+		}
+	}
+	return false
 }
 
 // NewMountSpec creates a MountSpec with the specified type and path. An error is returned if the type is invalid.
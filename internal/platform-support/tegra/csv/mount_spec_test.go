@@ -21,6 +21,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config/image"
 )
 
 func TestNewMountSpecFromLine(t *testing.T) {
@@ -62,6 +64,29 @@ func TestNewMountSpecFromLine(t *testing.T) {
 				Type: "dev",
 			},
 		},
+		{
+			line: "lib, /a/path, video",
+			expectedValue: MountSpec{
+				Path:         "/a/path",
+				Type:         "lib",
+				Capabilities: []string{"video"},
+			},
+		},
+		{
+			line: "lib, /a/path, video;graphics",
+			expectedValue: MountSpec{
+				Path:         "/a/path",
+				Type:         "lib",
+				Capabilities: []string{"video", "graphics"},
+			},
+		},
+		{
+			line: "lib, /a/path,video;not-a-capability",
+			expectedValue: MountSpec{
+				Path: "/a/path,video;not-a-capability",
+				Type: "lib",
+			},
+		},
 		{
 			line:          "not-dev ,/a/path",
 			expectedError: unexpectedError,
@@ -81,3 +106,48 @@ func TestNewMountSpecFromLine(t *testing.T) {
 		})
 	}
 }
+
+func TestMountSpecIsRequiredFor(t *testing.T) {
+	testCases := []struct {
+		description  string
+		mountSpec    MountSpec
+		capabilities image.DriverCapabilities
+		expected     bool
+	}{
+		{
+			description:  "no mount spec capabilities is always required",
+			mountSpec:    MountSpec{Type: "lib", Path: "/a/path"},
+			capabilities: image.NewDriverCapabilities("compute"),
+			expected:     true,
+		},
+		{
+			description: "nil capabilities is always required",
+			mountSpec:   MountSpec{Type: "lib", Path: "/a/path", Capabilities: []string{"video"}},
+			expected:    true,
+		},
+		{
+			description:  "matching capability is required",
+			mountSpec:    MountSpec{Type: "lib", Path: "/a/path", Capabilities: []string{"video", "graphics"}},
+			capabilities: image.NewDriverCapabilities("compute,graphics"),
+			expected:     true,
+		},
+		{
+			description:  "all capabilities is required",
+			mountSpec:    MountSpec{Type: "lib", Path: "/a/path", Capabilities: []string{"video"}},
+			capabilities: image.NewDriverCapabilities("all"),
+			expected:     true,
+		},
+		{
+			description:  "non-matching capability is not required",
+			mountSpec:    MountSpec{Type: "lib", Path: "/a/path", Capabilities: []string{"video"}},
+			capabilities: image.NewDriverCapabilities("compute,utility"),
+			expected:     false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.mountSpec.IsRequiredFor(tc.capabilities))
+		})
+	}
+}
@@ -0,0 +1,170 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package csv
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config/image"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+)
+
+// MountSpecFile represents the YAML alternative to a CSV file. Mount specs
+// from included files are processed before the mounts of the including file.
+// The definitions are not processed and may be used to define YAML anchors
+// that are referenced by the mounts.
+type MountSpecFile struct {
+	Include     []string         `yaml:"include,omitempty"`
+	Definitions any              `yaml:"definitions,omitempty"`
+	Mounts      []MountSpecEntry `yaml:"mounts"`
+}
+
+// MountSpecEntry defines a set of paths of the same type. The entry is only
+// considered on the listed architectures (as reported by GOARCH) and, if
+// capabilities are specified, is only required for these driver
+// capabilities.
+type MountSpecEntry struct {
+	Type         MountSpecType `yaml:"type"`
+	Paths        []string      `yaml:"paths"`
+	Capabilities []string      `yaml:"capabilities,flow,omitempty"`
+	Arch         []string      `yaml:"arch,flow,omitempty"`
+}
+
+type yamlParser struct {
+	logger   logger.Interface
+	filename string
+	arch     string
+}
+
+// NewYAMLFileParser creates a new parser for reading MountSpecs from the
+// specified YAML file.
+func NewYAMLFileParser(logger logger.Interface, filename string) Parser {
+	p := yamlParser{
+		logger:   logger,
+		filename: filename,
+		arch:     runtime.GOARCH,
+	}
+
+	return &p
+}
+
+// NewFileParser creates a parser for the specified mount spec file based on
+// its extension. Files with a .yaml or .yml extension are parsed as YAML with
+// all other files being parsed as CSV.
+func NewFileParser(logger logger.Interface, filename string) Parser {
+	if IsYAMLFile(filename) {
+		return NewYAMLFileParser(logger, filename)
+	}
+	return NewCSVFileParser(logger, filename)
+}
+
+// IsYAMLFile checks whether the specified mount spec file is a YAML file.
+func IsYAMLFile(filename string) bool {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".yaml", ".yml":
+		return true
+	default:
+		return false
+	}
+}
+
+// Parse parses the YAML file and its includes and returns the list of
+// MountSpecs for the current architecture.
+func (p yamlParser) Parse() ([]*MountSpec, error) {
+	return p.parseFile(p.filename, nil)
+}
+
+func (p yamlParser) parseFile(filename string, parents []string) ([]*MountSpec, error) {
+	filename = filepath.Clean(filename)
+	if slices.Contains(parents, filename) {
+		return nil, fmt.Errorf("include cycle detected: %v", strings.Join(append(parents, filename), " -> "))
+	}
+
+	contents, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %v: %w", filename, err)
+	}
+
+	var file MountSpecFile
+	if err := yaml.Unmarshal(contents, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse %v: %w", filename, err)
+	}
+
+	var targets []*MountSpec
+	for _, include := range file.Include {
+		if !filepath.IsAbs(include) {
+			include = filepath.Join(filepath.Dir(filename), include)
+		}
+		included, err := p.parseFile(include, append(parents, filename))
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, included...)
+	}
+
+	for i, entry := range file.Mounts {
+		if len(entry.Arch) > 0 && !slices.Contains(entry.Arch, p.arch) {
+			p.logger.Debugf("Skipping mount entry %d in %v; not required for architecture %v", i, filename, p.arch)
+			continue
+		}
+		for _, c := range entry.Capabilities {
+			if !image.SupportedDriverCapabilities.Has(image.DriverCapability(c)) {
+				return nil, fmt.Errorf("invalid capability %q for mount entry %d in %v", c, i, filename)
+			}
+		}
+		for _, path := range entry.Paths {
+			target, err := NewMountSpec(string(entry.Type), path)
+			if err != nil {
+				return nil, fmt.Errorf("invalid mount entry %d in %v: %w", i, filename, err)
+			}
+			target.Capabilities = slices.Clone(entry.Capabilities)
+			targets = append(targets, target)
+		}
+	}
+
+	return targets, nil
+}
+
+// ToYAML converts the specified mount specs to the YAML mount spec format.
+// Consecutive mount specs with the same type and capabilities are grouped
+// into a single entry.
+func ToYAML(targets []*MountSpec) ([]byte, error) {
+	var file MountSpecFile
+	for _, target := range targets {
+		if n := len(file.Mounts); n > 0 {
+			last := &file.Mounts[n-1]
+			if last.Type == target.Type && slices.Equal(last.Capabilities, target.Capabilities) {
+				last.Paths = append(last.Paths, target.Path)
+				continue
+			}
+		}
+		file.Mounts = append(file.Mounts, MountSpecEntry{
+			Type:         target.Type,
+			Paths:        []string{target.Path},
+			Capabilities: slices.Clone(target.Capabilities),
+		})
+	}
+
+	return yaml.Marshal(&file)
+}
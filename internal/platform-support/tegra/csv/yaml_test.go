@@ -0,0 +1,202 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package csv
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestYAMLFileParser(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	testCases := []struct {
+		description   string
+		files         map[string]string
+		arch          string
+		expectedError bool
+		expected      []*MountSpec
+	}{
+		{
+			description: "lists of paths are expanded",
+			files: map[string]string{
+				"l4t.yaml": `
+mounts:
+- type: dev
+  paths:
+  - /dev/nvhost-ctrl
+  - /dev/nvmap
+- type: lib
+  paths:
+  - /usr/lib/libcuda.so.1
+  capabilities: [compute]
+`,
+			},
+			arch: "arm64",
+			expected: []*MountSpec{
+				{Type: MountSpecDev, Path: "/dev/nvhost-ctrl"},
+				{Type: MountSpecDev, Path: "/dev/nvmap"},
+				{Type: MountSpecLib, Path: "/usr/lib/libcuda.so.1", Capabilities: []string{"compute"}},
+			},
+		},
+		{
+			description: "anchors are resolved",
+			files: map[string]string{
+				"l4t.yaml": `
+definitions:
+  video: &video
+    type: lib
+    capabilities: [video]
+mounts:
+- <<: *video
+  paths: [/usr/lib/libnvmedia.so]
+- <<: *video
+  type: sym
+  paths: [/usr/lib/libnvmedia.so.1]
+`,
+			},
+			arch: "arm64",
+			expected: []*MountSpec{
+				{Type: MountSpecLib, Path: "/usr/lib/libnvmedia.so", Capabilities: []string{"video"}},
+				{Type: MountSpecSym, Path: "/usr/lib/libnvmedia.so.1", Capabilities: []string{"video"}},
+			},
+		},
+		{
+			description: "entries for other architectures are skipped",
+			files: map[string]string{
+				"l4t.yaml": `
+mounts:
+- type: lib
+  paths: [/usr/lib/aarch64-linux-gnu/libcuda.so]
+  arch: [arm64]
+- type: lib
+  paths: [/usr/lib/x86_64-linux-gnu/libcuda.so]
+  arch: [amd64]
+`,
+			},
+			arch: "amd64",
+			expected: []*MountSpec{
+				{Type: MountSpecLib, Path: "/usr/lib/x86_64-linux-gnu/libcuda.so"},
+			},
+		},
+		{
+			description: "included files are processed first",
+			files: map[string]string{
+				"l4t.yaml": `
+include: [common/devices.yaml]
+mounts:
+- type: lib
+  paths: [/usr/lib/libcuda.so]
+`,
+				"common/devices.yaml": `
+mounts:
+- type: dev
+  paths: [/dev/nvmap]
+`,
+			},
+			arch: "arm64",
+			expected: []*MountSpec{
+				{Type: MountSpecDev, Path: "/dev/nvmap"},
+				{Type: MountSpecLib, Path: "/usr/lib/libcuda.so"},
+			},
+		},
+		{
+			description: "include cycle is an error",
+			files: map[string]string{
+				"l4t.yaml":   "include: [other.yaml]\n",
+				"other.yaml": "include: [l4t.yaml]\n",
+			},
+			expectedError: true,
+		},
+		{
+			description: "invalid type is an error",
+			files: map[string]string{
+				"l4t.yaml": "mounts:\n- type: invalid\n  paths: [/foo]\n",
+			},
+			expectedError: true,
+		},
+		{
+			description: "invalid capability is an error",
+			files: map[string]string{
+				"l4t.yaml": "mounts:\n- type: lib\n  paths: [/foo]\n  capabilities: [invalid]\n",
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			root := t.TempDir()
+			for name, contents := range tc.files {
+				filename := filepath.Join(root, name)
+				require.NoError(t, os.MkdirAll(filepath.Dir(filename), 0755))
+				require.NoError(t, os.WriteFile(filename, []byte(contents), 0600))
+			}
+
+			p := yamlParser{
+				logger:   logger,
+				filename: filepath.Join(root, "l4t.yaml"),
+				arch:     tc.arch,
+			}
+			mountSpecs, err := p.Parse()
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.EqualValues(t, tc.expected, mountSpecs)
+		})
+	}
+}
+
+func TestToYAML(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	mountSpecs := []*MountSpec{
+		{Type: MountSpecDev, Path: "/dev/nvhost-ctrl"},
+		{Type: MountSpecDev, Path: "/dev/nvmap"},
+		{Type: MountSpecLib, Path: "/usr/lib/libnvmedia.so", Capabilities: []string{"video"}},
+		{Type: MountSpecLib, Path: "/usr/lib/libcuda.so"},
+	}
+
+	contents, err := ToYAML(mountSpecs)
+	require.NoError(t, err)
+	require.Equal(t, `mounts:
+    - type: dev
+      paths:
+        - /dev/nvhost-ctrl
+        - /dev/nvmap
+    - type: lib
+      paths:
+        - /usr/lib/libnvmedia.so
+      capabilities: [video]
+    - type: lib
+      paths:
+        - /usr/lib/libcuda.so
+`, string(contents))
+
+	filename := filepath.Join(t.TempDir(), "l4t.yaml")
+	require.NoError(t, os.WriteFile(filename, contents, 0600))
+
+	parsed, err := NewYAMLFileParser(logger, filename).Parse()
+	require.NoError(t, err)
+	require.EqualValues(t, mountSpecs, parsed)
+}
@@ -23,6 +23,7 @@ import (
 	testlog "github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/require"
 
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config/image"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/discover"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup"
@@ -215,7 +216,7 @@ func TestDiscovererFromCSVFiles(t *testing.T) {
 
 func setGetTargetsFromCSVFiles(override map[csv.MountSpecType][]string) func() {
 	original := getTargetsFromCSVFiles
-	getTargetsFromCSVFiles = func(logger logger.Interface, files []string) map[csv.MountSpecType][]string {
+	getTargetsFromCSVFiles = func(logger logger.Interface, files []string, capabilities image.DriverCapabilities) map[csv.MountSpecType][]string {
 		return override
 	}
 
@@ -0,0 +1,190 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package tegra
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/platform-support/tegra/csv"
+)
+
+// An EntryResult describes how an entry in a CSV file is processed.
+type EntryResult string
+
+const (
+	// EntryDevice indicates that the entry resolved to a device node.
+	EntryDevice = EntryResult("device")
+	// EntryMount indicates that the entry resolved to a mount.
+	EntryMount = EntryResult("mount")
+	// EntrySymlink indicates that the entry resolved to a mount and a symlink
+	// that is created in the container.
+	EntrySymlink = EntryResult("symlink")
+	// EntrySkipped indicates that the entry was skipped.
+	EntrySkipped = EntryResult("skipped")
+)
+
+// An EntryExplanation describes the processing of a single line in a CSV
+// file.
+type EntryExplanation struct {
+	Line     string
+	Result   EntryResult
+	Resolved []string
+	Reason   string
+}
+
+// A FileExplanation describes the processing of a CSV file.
+type FileExplanation struct {
+	Filename string
+	Err      error
+	Entries  []EntryExplanation
+}
+
+// Explain processes the configured CSV files in the same way as the
+// discoverer returned by New and returns, for each file, which entries
+// resolved to devices, mounts, or symlinks and which entries were skipped
+// together with the reason.
+func Explain(opts ...Option) []FileExplanation {
+	o := newOptions(opts...)
+
+	var explanations []FileExplanation
+	for _, filename := range o.csvFiles {
+		explanations = append(explanations, o.explainFile(filename))
+	}
+	return explanations
+}
+
+func (o tegraOptions) explainFile(filename string) FileExplanation {
+	explanation := FileExplanation{
+		Filename: filename,
+	}
+
+	if csv.IsYAMLFile(filename) {
+		return o.explainYAMLFile(filename)
+	}
+
+	file, err := os.Open(filename)
+	if err != nil {
+		explanation.Err = err
+		return explanation
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		explanation.Entries = append(explanation.Entries, o.explainLine(line))
+	}
+	if err := scanner.Err(); err != nil {
+		explanation.Err = err
+	}
+	return explanation
+}
+
+// explainYAMLFile explains the mount specs defined in a YAML file. Since the
+// file may include other files and define entries for other architectures,
+// each explained entry describes a single mount spec using the CSV format.
+func (o tegraOptions) explainYAMLFile(filename string) FileExplanation {
+	explanation := FileExplanation{
+		Filename: filename,
+	}
+
+	mountSpecs, err := csv.NewYAMLFileParser(o.logger, filename).Parse()
+	if err != nil {
+		explanation.Err = err
+		return explanation
+	}
+	for _, mountSpec := range mountSpecs {
+		line := fmt.Sprintf("%v, %v", mountSpec.Type, mountSpec.Path)
+		if len(mountSpec.Capabilities) > 0 {
+			line += ", " + strings.Join(mountSpec.Capabilities, ";")
+		}
+		explanation.Entries = append(explanation.Entries, o.explainMountSpec(line, mountSpec))
+	}
+	return explanation
+}
+
+func (o tegraOptions) explainLine(line string) EntryExplanation {
+	mountSpec, err := csv.NewMountSpecFromLine(line)
+	if err != nil {
+		return EntryExplanation{
+			Line:   line,
+			Result: EntrySkipped,
+			Reason: fmt.Sprintf("invalid entry: %v", err),
+		}
+	}
+	return o.explainMountSpec(line, mountSpec)
+}
+
+func (o tegraOptions) explainMountSpec(line string, mountSpec *csv.MountSpec) EntryExplanation {
+	entry := EntryExplanation{
+		Line:   line,
+		Result: EntrySkipped,
+	}
+
+	if !mountSpec.IsRequiredFor(o.driverCapabilities) {
+		entry.Reason = fmt.Sprintf("not required for driver capabilities %v", o.driverCapabilities)
+		return entry
+	}
+
+	var locator lookup.Locator
+	var result EntryResult
+	switch mountSpec.Type {
+	case csv.MountSpecDev:
+		locator = lookup.NewCharDeviceLocator(
+			lookup.WithLogger(o.logger),
+			lookup.WithRoot(o.devRoot),
+		)
+		result = EntryDevice
+	case csv.MountSpecDir:
+		locator = lookup.NewDirectoryLocator(
+			lookup.WithLogger(o.logger),
+			lookup.WithRoot(o.driverRoot),
+		)
+		result = EntryMount
+	case csv.MountSpecLib:
+		locator = o.symlinkLocator
+		result = EntryMount
+	case csv.MountSpecSym:
+		if o.ignorePatterns.Match(mountSpec.Path) {
+			entry.Reason = "matches an ignore pattern"
+			return entry
+		}
+		locator = o.symlinkLocator
+		result = EntrySymlink
+	}
+
+	resolved, err := locator.Locate(mountSpec.Path)
+	if err != nil {
+		entry.Reason = fmt.Sprintf("not found: %v", err)
+		return entry
+	}
+	if len(resolved) == 0 {
+		entry.Reason = "not found"
+		return entry
+	}
+
+	entry.Result = result
+	entry.Resolved = resolved
+	return entry
+}
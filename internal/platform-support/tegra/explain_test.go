@@ -0,0 +1,80 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package tegra
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config/image"
+)
+
+func TestExplain(t *testing.T) {
+	t.Setenv("__NVCT_TESTING_DEVICES_ARE_FILES", "true")
+	logger, _ := testlog.NewNullLogger()
+
+	driverRoot := t.TempDir()
+	for _, path := range []string{"/dev/nvhost-ctrl", "/usr/lib/libcuda.so.1"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(driverRoot, filepath.Dir(path)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(driverRoot, path), nil, 0644))
+	}
+	require.NoError(t, os.MkdirAll(filepath.Join(driverRoot, "/usr/share/nvidia"), 0755))
+	require.NoError(t, os.Symlink("libcuda.so.1", filepath.Join(driverRoot, "/usr/lib/libcuda.so")))
+
+	csvFile := filepath.Join(t.TempDir(), "devices.csv")
+	contents := `dev, /dev/nvhost-ctrl
+dir, /usr/share/nvidia
+lib, /usr/lib/libcuda.so.1
+lib, /usr/lib/libmissing.so
+sym, /usr/lib/libcuda.so
+sym, /usr/lib/libignored.so
+lib, /usr/lib/libnvjpeg.so, video
+
+invalid line
+`
+	require.NoError(t, os.WriteFile(csvFile, []byte(contents), 0644))
+
+	explanations := Explain(
+		WithLogger(logger),
+		WithDriverRoot(driverRoot),
+		WithCSVFiles([]string{csvFile, filepath.Join(driverRoot, "missing.csv")}),
+		WithIngorePatterns("**/libignored.so"),
+		WithDriverCapabilities(image.NewDriverCapabilities("compute")),
+	)
+	require.Len(t, explanations, 2)
+
+	require.NoError(t, explanations[0].Err)
+	require.EqualValues(t,
+		[]EntryExplanation{
+			{Line: "dev, /dev/nvhost-ctrl", Result: EntryDevice, Resolved: []string{filepath.Join(driverRoot, "/dev/nvhost-ctrl")}},
+			{Line: "dir, /usr/share/nvidia", Result: EntryMount, Resolved: []string{filepath.Join(driverRoot, "/usr/share/nvidia")}},
+			{Line: "lib, /usr/lib/libcuda.so.1", Result: EntryMount, Resolved: []string{filepath.Join(driverRoot, "/usr/lib/libcuda.so.1")}},
+			{Line: "lib, /usr/lib/libmissing.so", Result: EntrySkipped, Reason: "not found: pattern /usr/lib/libmissing.so not found"},
+			{Line: "sym, /usr/lib/libcuda.so", Result: EntrySymlink, Resolved: []string{filepath.Join(driverRoot, "/usr/lib/libcuda.so.1")}},
+			{Line: "sym, /usr/lib/libignored.so", Result: EntrySkipped, Reason: "matches an ignore pattern"},
+			{Line: "lib, /usr/lib/libnvjpeg.so, video", Result: EntrySkipped, Reason: "not required for driver capabilities compute"},
+			{Line: "invalid line", Result: EntrySkipped, Reason: "invalid entry: failed to parse line: invalid line"},
+		},
+		explanations[0].Entries,
+	)
+
+	require.Error(t, explanations[1].Err)
+}
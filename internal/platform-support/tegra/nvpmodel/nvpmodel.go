@@ -0,0 +1,127 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+// Package nvpmodel determines the power mode of Tegra-based systems as
+// configured by the nvpmodel tool.
+package nvpmodel
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// statusFile records the power mode selected using nvpmodel.
+	statusFile = "/var/lib/nvpmodel/status"
+	// configFile defines the power modes supported by the system.
+	configFile = "/etc/nvpmodel.conf"
+)
+
+var (
+	powerModelPattern = regexp.MustCompile(`^<\s*POWER_MODEL\s+ID=(\d+)\s+NAME=(\S+)\s*>`)
+	defaultPattern    = regexp.MustCompile(`^<\s*PM_CONFIG\s+DEFAULT=(\d+)\s*>`)
+)
+
+// A PowerMode represents an nvpmodel power mode.
+type PowerMode struct {
+	ID   int
+	Name string
+}
+
+// GetPowerMode returns the current power mode of the system at the specified
+// root. If no power mode has been selected using nvpmodel, the default power
+// mode is returned.
+func GetPowerMode(root string) (*PowerMode, error) {
+	config, err := os.Open(filepath.Join(root, configFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open nvpmodel config: %w", err)
+	}
+	defer config.Close()
+
+	names, defaultID, err := parseConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse nvpmodel config: %w", err)
+	}
+
+	id := defaultID
+	status, err := os.ReadFile(filepath.Join(root, statusFile))
+	switch {
+	case err == nil:
+		id, err = parseStatus(string(status))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse nvpmodel status: %w", err)
+		}
+	case !os.IsNotExist(err):
+		return nil, fmt.Errorf("failed to read nvpmodel status: %w", err)
+	}
+
+	if id < 0 {
+		return nil, fmt.Errorf("no power mode selected and no default power mode defined")
+	}
+	name, ok := names[id]
+	if !ok {
+		return nil, fmt.Errorf("power mode %d is not defined", id)
+	}
+	return &PowerMode{ID: id, Name: name}, nil
+}
+
+// parseConfig returns the names of the power modes defined in an nvpmodel
+// config and the ID of the default power mode. If no default is defined, -1 is
+// returned.
+func parseConfig(r io.Reader) (map[int]string, int, error) {
+	names := make(map[int]string)
+	defaultID := -1
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if m := powerModelPattern.FindStringSubmatch(line); m != nil {
+			id, _ := strconv.Atoi(m[1])
+			names[id] = m[2]
+			continue
+		}
+		if m := defaultPattern.FindStringSubmatch(line); m != nil {
+			defaultID, _ = strconv.Atoi(m[1])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, -1, err
+	}
+	return names, defaultID, nil
+}
+
+// parseStatus returns the ID of the power mode recorded in an nvpmodel status
+// file. The file contains space-separated fields such as pmode:0000.
+func parseStatus(contents string) (int, error) {
+	for _, field := range strings.Fields(contents) {
+		value, ok := strings.CutPrefix(field, "pmode:")
+		if !ok {
+			continue
+		}
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			return -1, fmt.Errorf("invalid power mode %q: %w", value, err)
+		}
+		return id, nil
+	}
+	return -1, fmt.Errorf("no power mode found")
+}
@@ -0,0 +1,92 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package nvpmodel
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testConfig = `
+< PARAM TYPE=FILE NAME=CPU_ONLINE >
+CORE_0 /sys/devices/system/cpu/cpu0/online
+
+< POWER_MODEL ID=0 NAME=MAXN >
+CPU_ONLINE CORE_0 1
+
+< POWER_MODEL ID=1 NAME=15W >
+CPU_ONLINE CORE_0 1
+
+< PM_CONFIG DEFAULT=1 >
+`
+
+func TestGetPowerMode(t *testing.T) {
+	testCases := []struct {
+		description       string
+		config            string
+		status            string
+		expectedPowerMode *PowerMode
+		expectedError     bool
+	}{
+		{
+			description:       "status selects power mode",
+			config:            testConfig,
+			status:            "pmode:0000 fmode:quiet\n",
+			expectedPowerMode: &PowerMode{ID: 0, Name: "MAXN"},
+		},
+		{
+			description:       "missing status returns default",
+			config:            testConfig,
+			expectedPowerMode: &PowerMode{ID: 1, Name: "15W"},
+		},
+		{
+			description:   "undefined power mode is an error",
+			config:        testConfig,
+			status:        "pmode:0007\n",
+			expectedError: true,
+		},
+		{
+			description:   "missing config is an error",
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			root := t.TempDir()
+			if tc.config != "" {
+				require.NoError(t, os.MkdirAll(filepath.Join(root, filepath.Dir(configFile)), 0755))
+				require.NoError(t, os.WriteFile(filepath.Join(root, configFile), []byte(tc.config), 0644))
+			}
+			if tc.status != "" {
+				require.NoError(t, os.MkdirAll(filepath.Join(root, filepath.Dir(statusFile)), 0755))
+				require.NoError(t, os.WriteFile(filepath.Join(root, statusFile), []byte(tc.status), 0644))
+			}
+
+			powerMode, err := GetPowerMode(root)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.EqualValues(t, tc.expectedPowerMode, powerMode)
+		})
+	}
+}
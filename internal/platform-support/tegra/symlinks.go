@@ -57,7 +57,7 @@ func (d symlinkHook) getSymlinkCandidates() []string {
 	for _, target := range d.targets {
 		reslovedSymlinkChain, err := d.symlinkChainLocator.Locate(target)
 		if err != nil {
-			d.logger.Warningf("Failed to locate symlink %v", target)
+			logger.Skippedf(d.logger, "Failed to locate symlink %v", target)
 			continue
 		}
 		candidates = append(candidates, reslovedSymlinkChain...)
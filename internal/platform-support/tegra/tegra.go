@@ -19,7 +19,9 @@ package tegra
 import (
 	"fmt"
 
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config/image"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/discover"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/librarygroups"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup/symlinks"
@@ -34,6 +36,10 @@ type tegraOptions struct {
 	ldconfigPath       string
 	librarySearchPaths []string
 	ignorePatterns     ignoreMountSpecPatterns
+	driverCapabilities image.DriverCapabilities
+	// libraryGroups are used to filter the libraries in the CSV files by the
+	// driver capabilities that they are required for.
+	libraryGroups librarygroups.Groups
 
 	// The following can be overridden for testing
 	symlinkLocator      lookup.Locator
@@ -47,33 +53,7 @@ type Option func(*tegraOptions)
 
 // New creates a new tegra discoverer using the supplied options.
 func New(opts ...Option) (discover.Discover, error) {
-	o := &tegraOptions{}
-	for _, opt := range opts {
-		opt(o)
-	}
-
-	if o.devRoot == "" {
-		o.devRoot = o.driverRoot
-	}
-
-	if o.symlinkLocator == nil {
-		o.symlinkLocator = lookup.NewSymlinkLocator(
-			lookup.WithLogger(o.logger),
-			lookup.WithRoot(o.driverRoot),
-			lookup.WithSearchPaths(append(o.librarySearchPaths, "/")...),
-		)
-	}
-
-	if o.symlinkChainLocator == nil {
-		o.symlinkChainLocator = lookup.NewSymlinkChainLocator(
-			lookup.WithLogger(o.logger),
-			lookup.WithRoot(o.driverRoot),
-		)
-	}
-
-	if o.resolveSymlink == nil {
-		o.resolveSymlink = symlinks.Resolve
-	}
+	o := newOptions(opts...)
 
 	csvDiscoverer, err := o.newDiscovererFromCSVFiles()
 	if err != nil {
@@ -104,6 +84,40 @@ func New(opts ...Option) (discover.Discover, error) {
 	return d, nil
 }
 
+// newOptions applies the specified options and sets the defaults for
+// options that were not specified.
+func newOptions(opts ...Option) *tegraOptions {
+	o := &tegraOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.devRoot == "" {
+		o.devRoot = o.driverRoot
+	}
+
+	if o.symlinkLocator == nil {
+		o.symlinkLocator = lookup.NewSymlinkLocator(
+			lookup.WithLogger(o.logger),
+			lookup.WithRoot(o.driverRoot),
+			lookup.WithSearchPaths(append(o.librarySearchPaths, "/")...),
+		)
+	}
+
+	if o.symlinkChainLocator == nil {
+		o.symlinkChainLocator = lookup.NewSymlinkChainLocator(
+			lookup.WithLogger(o.logger),
+			lookup.WithRoot(o.driverRoot),
+		)
+	}
+
+	if o.resolveSymlink == nil {
+		o.resolveSymlink = symlinks.Resolve
+	}
+
+	return o
+}
+
 // WithLogger sets the logger for the discoverer.
 func WithLogger(logger logger.Interface) Option {
 	return func(o *tegraOptions) {
@@ -160,3 +174,22 @@ func WithIngorePatterns(ignorePatterns ...string) Option {
 		o.ignorePatterns = ignoreMountSpecPatterns(ignorePatterns)
 	}
 }
+
+// WithDriverCapabilities sets the driver capabilities used to filter the
+// entries in the CSV files. If this is not set, no filtering is performed.
+func WithDriverCapabilities(driverCapabilities image.DriverCapabilities) Option {
+	return func(o *tegraOptions) {
+		o.driverCapabilities = driverCapabilities
+	}
+}
+
+// WithLibraryGroups sets the library groups used to filter the libraries in
+// the CSV files. Libraries that are assigned to library groups are only
+// included if they are required for one of the driver capabilities set using
+// WithDriverCapabilities. This applies in addition to the capabilities
+// specified for the entries in the CSV files.
+func WithLibraryGroups(groups librarygroups.Groups) Option {
+	return func(o *tegraOptions) {
+		o.libraryGroups = groups
+	}
+}
@@ -14,7 +14,7 @@
 # limitations under the License.
 **/
 
-package nvcdi
+package wsl
 
 import (
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/discover"
@@ -22,15 +22,17 @@ import (
 )
 
 const (
-	dxgDeviceNode = "/dev/dxg"
+	// DXGDeviceNode is the device node through which GPUs are accessed in
+	// WSL2.
+	DXGDeviceNode = "/dev/dxg"
 )
 
-// newDXGDeviceDiscoverer returns a Discoverer for DXG devices under WSL2.
-func newDXGDeviceDiscoverer(logger logger.Interface, devRoot string) discover.Discover {
+// NewDXGDeviceDiscoverer returns a Discoverer for DXG devices under WSL2.
+func NewDXGDeviceDiscoverer(logger logger.Interface, devRoot string) discover.Discover {
 	deviceNodes := discover.NewCharDeviceDiscoverer(
 		logger,
 		devRoot,
-		[]string{dxgDeviceNode},
+		[]string{DXGDeviceNode},
 	)
 
 	return deviceNodes
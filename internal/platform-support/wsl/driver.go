@@ -0,0 +1,179 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package wsl
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/discover"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup"
+)
+
+const (
+	// DriverStoresPath is the path at which the driver stores of the host
+	// are made available in a WSL2 distribution.
+	DriverStoresPath = "/usr/lib/wsl/drivers"
+	// LibPath is the path at which the WSL2 libraries (e.g. libdxcore.so) are
+	// made available in a WSL2 distribution.
+	LibPath = "/usr/lib/wsl/lib"
+)
+
+var requiredDriverStoreFiles = []string{
+	"libcuda.so.1.1",                /* Core library for cuda support */
+	"libcuda_loader.so",             /* Core library for cuda support on WSL */
+	"libnvidia-ptxjitcompiler.so.1", /* Core library for PTX Jit support */
+	"libnvidia-ml.so.1",             /* Core library for nvml */
+	"libnvidia-ml_loader.so",        /* Core library for nvml on WSL */
+	"libdxcore.so",                  /* Core library for dxcore support */
+	"libnvdxgdmal.so.1",             /* dxgdmal library for cuda */
+	"nvcubins.bin",                  /* Binary containing GPU code for cuda */
+	"nvidia-smi",                    /* nvidia-smi binary*/
+}
+
+// NewDriverStoreLocator returns a locator for the driver files in the
+// specified driver store and the WSL2 library path. The paths are searched
+// relative to the specified root.
+func NewDriverStoreLocator(logger logger.Interface, root string, driverStorePath string) lookup.Locator {
+	return lookup.NewFileLocator(
+		lookup.WithLogger(logger),
+		lookup.WithRoot(root),
+		lookup.WithSearchPaths(
+			driverStorePath,
+			LibPath,
+		),
+		lookup.WithCount(1),
+	)
+}
+
+// NewDriverDiscoverer returns a Discoverer for the WSL2 driver files located
+// by the specified locator.
+func NewDriverDiscoverer(logger logger.Interface, locator lookup.Locator, driverRoot string, hookCreator discover.HookCreator, ldconfigPath string) discover.Discover {
+	driverStoreMounts := discover.NewMounts(
+		logger,
+		locator,
+		driverRoot,
+		requiredDriverStoreFiles,
+	)
+
+	symlinkHook := nvidiaSMISimlinkHook{
+		logger:      logger,
+		mountsFrom:  driverStoreMounts,
+		hookCreator: hookCreator,
+	}
+
+	ldcacheHook, _ := discover.NewLDCacheUpdateHook(logger, driverStoreMounts, hookCreator, ldconfigPath)
+
+	return discover.Merge(
+		driverStoreMounts,
+		symlinkHook,
+		ldcacheHook,
+	)
+}
+
+// FindDriverStorePaths returns the paths of the driver stores that are
+// available in the WSL2 distribution with the specified root. The returned
+// paths are relative to the root.
+func FindDriverStorePaths(root string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(root, DriverStoresPath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read driver stores: %w", err)
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		paths = append(paths, DriverStoresPath+"/"+entry.Name())
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// SelectDriverStorePath selects a single driver store from the specified
+// paths. Multiple driver stores may be present if the driver was upgraded
+// without a restart. In this case, stores that do not contain the CUDA
+// library are ignored and the most recently installed store is selected.
+// The paths are checked relative to the specified root.
+func SelectDriverStorePath(logger logger.Interface, root string, paths []string) (string, error) {
+	if len(paths) == 0 {
+		return "", fmt.Errorf("no driver store paths found")
+	}
+	if len(paths) == 1 {
+		return paths[0], nil
+	}
+	logger.Warningf("Found multiple driver store paths: %v", paths)
+
+	var selected string
+	var selectedModTime time.Time
+	for _, path := range paths {
+		info, err := os.Stat(filepath.Join(root, path, "libcuda.so.1.1"))
+		if err != nil {
+			logger.Debugf("Ignoring driver store %v: %v", path, err)
+			continue
+		}
+		if selected == "" || info.ModTime().After(selectedModTime) {
+			selected = path
+			selectedModTime = info.ModTime()
+		}
+	}
+	if selected == "" {
+		return "", fmt.Errorf("no valid driver store found in %v", paths)
+	}
+	return selected, nil
+}
+
+type nvidiaSMISimlinkHook struct {
+	discover.None
+	logger      logger.Interface
+	mountsFrom  discover.Discover
+	hookCreator discover.HookCreator
+}
+
+// Hooks returns a hook that creates a symlink to nvidia-smi in the driver store.
+// On WSL2 the driver store location is used unchanged, for this reason we need
+// to create a symlink from /usr/bin/nvidia-smi to the nvidia-smi binary in the
+// driver store.
+func (m nvidiaSMISimlinkHook) Hooks() ([]discover.Hook, error) {
+	mounts, err := m.mountsFrom.Mounts()
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover mounts: %w", err)
+	}
+
+	var target string
+	for _, mount := range mounts {
+		if filepath.Base(mount.Path) == "nvidia-smi" {
+			target = mount.Path
+			break
+		}
+	}
+
+	if target == "" {
+		m.logger.Warningf("Failed to find nvidia-smi in mounts: %v", mounts)
+		return nil, nil
+	}
+	link := "/usr/bin/nvidia-smi"
+	links := []string{fmt.Sprintf("%s::%s", target, link)}
+	symlinkHook := m.hookCreator.Create(discover.CreateSymlinksHook, links...)
+
+	return symlinkHook.Hooks()
+}
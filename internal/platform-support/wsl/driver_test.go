@@ -14,11 +14,14 @@
 # limitations under the License.
 **/
 
-package nvcdi
+package wsl
 
 import (
 	"errors"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -166,3 +169,96 @@ func TestNvidiaSMISymlinkHook(t *testing.T) {
 		})
 	}
 }
+
+func TestSelectDriverStorePath(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	driverStores := t.TempDir()
+	now := time.Now()
+	for name, modTime := range map[string]time.Time{
+		"nv_dispi.inf_amd64_old": now.Add(-time.Hour),
+		"nv_dispi.inf_amd64_new": now,
+	} {
+		dir := filepath.Join(driverStores, name)
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		libcuda := filepath.Join(dir, "libcuda.so.1.1")
+		require.NoError(t, os.WriteFile(libcuda, nil, 0644))
+		require.NoError(t, os.Chtimes(libcuda, modTime, modTime))
+	}
+	emptyStore := filepath.Join(driverStores, "nv_dispi.inf_amd64_empty")
+	require.NoError(t, os.MkdirAll(emptyStore, 0755))
+
+	testCases := []struct {
+		description   string
+		paths         []string
+		expectedPath  string
+		expectedError bool
+	}{
+		{
+			description:   "no paths returns error",
+			expectedError: true,
+		},
+		{
+			description:  "single path is selected",
+			paths:        []string{emptyStore},
+			expectedPath: emptyStore,
+		},
+		{
+			description: "newest store is selected",
+			paths: []string{
+				filepath.Join(driverStores, "nv_dispi.inf_amd64_old"),
+				filepath.Join(driverStores, "nv_dispi.inf_amd64_new"),
+			},
+			expectedPath: filepath.Join(driverStores, "nv_dispi.inf_amd64_new"),
+		},
+		{
+			description: "store without libcuda is ignored",
+			paths: []string{
+				emptyStore,
+				filepath.Join(driverStores, "nv_dispi.inf_amd64_old"),
+			},
+			expectedPath: filepath.Join(driverStores, "nv_dispi.inf_amd64_old"),
+		},
+		{
+			description: "no valid store returns error",
+			paths: []string{
+				emptyStore,
+				filepath.Join(driverStores, "missing"),
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			path, err := SelectDriverStorePath(logger, "", tc.paths)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedPath, path)
+		})
+	}
+}
+
+func TestFindDriverStorePaths(t *testing.T) {
+	root := t.TempDir()
+	for _, store := range []string{"nv_dispi.inf_amd64_b", "nv_dispi.inf_amd64_a"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, DriverStoresPath, store), 0755))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(root, DriverStoresPath, "not-a-store"), nil, 0644))
+
+	paths, err := FindDriverStorePaths(root)
+	require.NoError(t, err)
+	require.Equal(t,
+		[]string{
+			"/usr/lib/wsl/drivers/nv_dispi.inf_amd64_a",
+			"/usr/lib/wsl/drivers/nv_dispi.inf_amd64_b",
+		},
+		paths,
+	)
+
+	_, err = FindDriverStorePaths(t.TempDir())
+	require.Error(t, err)
+}
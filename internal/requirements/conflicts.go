@@ -0,0 +1,190 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package requirements
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/requirements/constraints"
+)
+
+// A Requirement is a requirement string along with a description of where it
+// was defined; for example the environment variable that sets it.
+type Requirement struct {
+	Value  string
+	Source string
+}
+
+// A Conflict describes a set of requirements on the same property that cannot
+// be satisfied at the same time.
+type Conflict struct {
+	Property string
+	// Constraints lists the conflicting constraints along with their sources.
+	Constraints []string
+}
+
+// String returns a human-readable description of the conflict.
+func (c Conflict) String() string {
+	return fmt.Sprintf("no %v satisfies all of %v", c.Property, strings.Join(c.Constraints, ", "))
+}
+
+// A condition is a single comparison that must hold for a requirement to be
+// satisfied.
+type condition struct {
+	property string
+	operator string
+	value    string
+	source   string
+}
+
+func (c condition) String() string {
+	return fmt.Sprintf("%v%v%v from %v", c.property, c.operator, c.value, c.source)
+}
+
+// holdsFor checks whether the condition is satisfied if the property has the
+// specified value.
+func (c condition) holdsFor(value string) bool {
+	satisfied, err := constraints.Compare(newProperty(c.property, value), c.operator, c.value)
+	return err == nil && satisfied
+}
+
+// FindConflicts returns the conflicts between the specified requirements.
+// Only conditions that always apply are considered. That is, a requirement
+// consisting of alternatives (separated by spaces) does not conflict with
+// other requirements since its alternatives cannot be checked in isolation.
+func FindConflicts(requirements []Requirement) []Conflict {
+	var properties []string
+	conditions := make(map[string][]condition)
+	for _, r := range requirements {
+		terms := strings.Fields(r.Value)
+		if len(terms) != 1 {
+			continue
+		}
+		for _, factor := range strings.Split(terms[0], ",") {
+			property, operator, value, err := constraints.ParseCondition(factor)
+			if err != nil || operator == "" {
+				continue
+			}
+			// Unsupported properties and invalid values are reported when
+			// the requirements are asserted.
+			if p := newProperty(property, ""); p == nil || p.Validate(value) != nil {
+				continue
+			}
+			if _, ok := conditions[property]; !ok {
+				properties = append(properties, property)
+			}
+			conditions[property] = append(conditions[property], condition{
+				property: property,
+				operator: operator,
+				value:    value,
+				source:   r.Source,
+			})
+		}
+	}
+
+	var conflicts []Conflict
+	for _, property := range properties {
+		conflicting := findConflictingConditions(conditions[property])
+		if len(conflicting) == 0 {
+			continue
+		}
+		conflict := Conflict{Property: property}
+		for _, c := range conflicting {
+			conflict.Constraints = append(conflict.Constraints, c.String())
+		}
+		conflicts = append(conflicts, conflict)
+	}
+	return conflicts
+}
+
+// findConflictingConditions returns the conditions on a single property that
+// cannot be satisfied together. If the conditions can be satisfied, nil is
+// returned.
+func findConflictingConditions(conditions []condition) []condition {
+	var lower, upper *condition
+	for i, c := range conditions {
+		switch c.operator {
+		case "=":
+			// An equality condition determines the only possible value.
+			conflicting := []condition{c}
+			for _, other := range conditions {
+				if !other.holdsFor(c.value) {
+					conflicting = append(conflicting, other)
+				}
+			}
+			if len(conflicting) > 1 {
+				return conflicting
+			}
+			return nil
+		case ">", ">=":
+			if lower == nil {
+				lower = &conditions[i]
+				continue
+			}
+			if d := compare(c.property, c.value, lower.value); d > 0 || (d == 0 && c.operator == ">") {
+				lower = &conditions[i]
+			}
+		case "<", "<=":
+			if upper == nil {
+				upper = &conditions[i]
+				continue
+			}
+			if d := compare(c.property, c.value, upper.value); d < 0 || (d == 0 && c.operator == "<") {
+				upper = &conditions[i]
+			}
+		}
+	}
+	if lower == nil || upper == nil {
+		return nil
+	}
+
+	d := compare(lower.property, lower.value, upper.value)
+	switch {
+	case d < 0:
+		return nil
+	case d > 0 || lower.operator == ">" || upper.operator == "<":
+		return []condition{*lower, *upper}
+	}
+	// The bounds only allow a single value which may still be excluded.
+	for _, c := range conditions {
+		if c.operator == "!=" && !c.holdsFor(lower.value) {
+			return []condition{*lower, *upper, c}
+		}
+	}
+	return nil
+}
+
+// compare compares two values of the specified property.
+func compare(property string, value string, other string) int {
+	d, _ := newProperty(property, value).CompareTo(other)
+	return d
+}
+
+// newProperty creates a property of the kind used for the specified name. Nil
+// is returned for unsupported properties.
+func newProperty(name string, value string) constraints.Property {
+	switch name {
+	case CUDA, ARCH, DRIVER:
+		return constraints.NewVersionProperty(name, value)
+	case BRAND, NVPMODEL:
+		return constraints.NewStringProperty(name, value)
+	case MEMORY:
+		return constraints.NewSizeProperty(name, value)
+	}
+	return nil
+}
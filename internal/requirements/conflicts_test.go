@@ -0,0 +1,121 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package requirements
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindConflicts(t *testing.T) {
+	testCases := []struct {
+		description       string
+		requirements      []Requirement
+		expectedConflicts []Conflict
+	}{
+		{
+			description: "no requirements",
+		},
+		{
+			description: "compatible requirements",
+			requirements: []Requirement{
+				{Value: "cuda>=12.0", Source: "A"},
+				{Value: "cuda<13.0,driver>=550", Source: "B"},
+				{Value: "brand=tesla", Source: "C"},
+			},
+		},
+		{
+			description: "contradicting bounds",
+			requirements: []Requirement{
+				{Value: "cuda>=12.0", Source: "A"},
+				{Value: "cuda<11.8", Source: "B"},
+			},
+			expectedConflicts: []Conflict{
+				{Property: "cuda", Constraints: []string{"cuda>=12.0 from A", "cuda<11.8 from B"}},
+			},
+		},
+		{
+			description: "strongest bounds are reported",
+			requirements: []Requirement{
+				{Value: "cuda>=11.0,cuda>=12.4", Source: "A"},
+				{Value: "cuda<12.4", Source: "B"},
+				{Value: "cuda<13", Source: "C"},
+			},
+			expectedConflicts: []Conflict{
+				{Property: "cuda", Constraints: []string{"cuda>=12.4 from A", "cuda<12.4 from B"}},
+			},
+		},
+		{
+			description: "inclusive bounds allow a single value",
+			requirements: []Requirement{
+				{Value: "driver>=550", Source: "A"},
+				{Value: "driver<=550", Source: "B"},
+			},
+		},
+		{
+			description: "single allowed value is excluded",
+			requirements: []Requirement{
+				{Value: "driver>=550,driver<=550", Source: "A"},
+				{Value: "driver!=550", Source: "B"},
+			},
+			expectedConflicts: []Conflict{
+				{Property: "driver", Constraints: []string{"driver>=550 from A", "driver<=550 from A", "driver!=550 from B"}},
+			},
+		},
+		{
+			description: "different equality requirements",
+			requirements: []Requirement{
+				{Value: "brand=tesla", Source: "A"},
+				{Value: "brand=geforce", Source: "B"},
+			},
+			expectedConflicts: []Conflict{
+				{Property: "brand", Constraints: []string{"brand=tesla from A", "brand=geforce from B"}},
+			},
+		},
+		{
+			description: "equality requirement violates bound",
+			requirements: []Requirement{
+				{Value: "memory=16g", Source: "A"},
+				{Value: "memory>=24g", Source: "B"},
+			},
+			expectedConflicts: []Conflict{
+				{Property: "memory", Constraints: []string{"memory=16g from A", "memory>=24g from B"}},
+			},
+		},
+		{
+			description: "alternatives are not checked",
+			requirements: []Requirement{
+				{Value: "cuda>=12.0", Source: "A"},
+				{Value: "cuda<11.0 brand=tesla", Source: "B"},
+			},
+		},
+		{
+			description: "unsupported properties are ignored",
+			requirements: []Requirement{
+				{Value: "unknown=a", Source: "A"},
+				{Value: "unknown=b", Source: "B"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			require.EqualValues(t, tc.expectedConflicts, FindConflicts(tc.requirements))
+		})
+	}
+}
@@ -22,4 +22,10 @@ const (
 	BRAND  = "brand"
 	CUDA   = "cuda"
 	DRIVER = "driver"
+	// MEMORY is the total memory of the requested devices. This is only
+	// evaluated on Tegra-based systems in csv mode.
+	MEMORY = "memory"
+	// NVPMODEL is the name of the current nvpmodel power mode on
+	// Tegra-based systems (e.g. MAXN).
+	NVPMODEL = "nvpmodel"
 )
@@ -44,7 +44,36 @@ func (c binary) Assert() error {
 	}
 
 	// error_setx(err, "unsatisfied condition: %s, please update your driver to a newer version, or use an earlier cuda container", predicate_format);
-	return fmt.Errorf("unsatisfied condition: %v (%v)", c.String(), c.left.String())
+	return fmt.Errorf("unsatisfied condition: %v", c.result())
+}
+
+// result evaluates the binary operation and records the host value that the
+// requirement was compared against.
+func (c binary) result() Result {
+	r := Result{
+		Constraint: c.String(),
+	}
+	if c.left == nil {
+		r.Satisfied = true
+		return r
+	}
+	r.Property = c.left.Name()
+	if value, err := c.left.Value(); err == nil {
+		r.HostValue = value
+	}
+	r.Satisfied, r.Err = c.eval()
+	return r
+}
+
+// Compare checks whether the specified property satisfies the comparison with
+// the specified value using the supplied operator.
+func Compare(p Property, operator string, value string) (bool, error) {
+	c := binary{
+		left:     p,
+		operator: operator,
+		right:    value,
+	}
+	return c.eval()
 }
 
 func (c binary) eval() (bool, error) {
@@ -103,6 +103,27 @@ func (r factory) parse(condition string) (Constraint, error) {
 		return nil, nil
 	}
 
+	property, op, value, err := ParseCondition(condition)
+	if err != nil {
+		return nil, err
+	}
+
+	p, ok := r.properties[property]
+	if !ok || p == nil {
+		return nil, nil
+	}
+
+	c := binary{
+		left:     p,
+		right:    value,
+		operator: op,
+	}
+	return c, p.Validate(value)
+}
+
+// ParseCondition splits a condition of the form [PROPERTY][OPERATOR][VALUE]
+// into its property name, operator, and value.
+func ParseCondition(condition string) (string, string, string, error) {
 	operators := []string{
 		notEqual,
 		lessEqual,
@@ -114,17 +135,12 @@ func (r factory) parse(condition string) (Constraint, error) {
 
 	propertyEnd := strings.IndexAny(condition, "<>=!")
 	if propertyEnd == -1 {
-		return nil, fmt.Errorf("invalid constraint: %v", condition)
+		return "", "", "", fmt.Errorf("invalid constraint: %v", condition)
 	}
 
 	property := condition[:propertyEnd]
 	condition = strings.TrimPrefix(condition, property)
 
-	p, ok := r.properties[property]
-	if !ok || p == nil {
-		return nil, nil
-	}
-
 	var op string
 	for _, o := range operators {
 		if strings.HasPrefix(condition, o) {
@@ -134,10 +150,5 @@ func (r factory) parse(condition string) (Constraint, error) {
 	}
 	value := strings.TrimPrefix(condition, op)
 
-	c := binary{
-		left:     p,
-		right:    value,
-		operator: op,
-	}
-	return c, p.Validate(value)
+	return property, op, value, nil
 }
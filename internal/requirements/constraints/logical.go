@@ -51,13 +51,16 @@ func OR(constraints []Constraint) Constraint {
 }
 
 func (operands or) Assert() error {
+	var errs []string
 	for _, o := range operands {
 		// We stop on the first nil
-		if err := o.Assert(); err == nil {
+		err := o.Assert()
+		if err == nil {
 			return nil
 		}
+		errs = append(errs, err.Error())
 	}
-	return fmt.Errorf("%v not met", operands)
+	return fmt.Errorf("%v not met: %v", operands.String(), strings.Join(errs, "; "))
 }
 
 func (operands or) String() string {
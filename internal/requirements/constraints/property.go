@@ -21,6 +21,8 @@ import (
 	"strings"
 
 	"golang.org/x/mod/semver"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/units"
 )
 
 // Property represents a property that is used to check requirements
@@ -56,6 +58,20 @@ func NewVersionProperty(name string, value string) Property {
 	return p
 }
 
+// NewSizeProperty creates a property representing a size (e.g. an amount of
+// memory) based on the name-value pair. Values are specified as an integer
+// with an optional binary unit suffix such as 512m or 24g.
+func NewSizeProperty(name string, value string) Property {
+	p := sizeProperty{
+		stringProperty: stringProperty{
+			name:  name,
+			value: value,
+		},
+	}
+
+	return p
+}
+
 // stringProperty represents a property that is used to check requirements
 type stringProperty struct {
 	name  string
@@ -66,6 +82,10 @@ type versionProperty struct {
 	stringProperty
 }
 
+type sizeProperty struct {
+	stringProperty
+}
+
 // Name returns a stringProperty's name
 func (p stringProperty) Name() string {
 	return p.name
@@ -128,3 +148,33 @@ func (p versionProperty) Validate(value string) error {
 func ensurePrefix(s string, prefix string) string {
 	return prefix + strings.TrimPrefix(s, prefix)
 }
+
+// CompareTo compares two sizes to each other. A property without a value is
+// considered smaller than any size.
+func (p sizeProperty) CompareTo(other string) (int, error) {
+	otherSize, err := units.ParseSize(other)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value for %v: %v", p.name, err)
+	}
+	if p.value == "" {
+		return -1, nil
+	}
+	size, err := units.ParseSize(p.value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %v: %v", p.name, err)
+	}
+
+	switch {
+	case size < otherSize:
+		return -1, nil
+	case size > otherSize:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// Validate checks whether the supplied value is a valid size
+func (p sizeProperty) Validate(value string) error {
+	_, err := units.ParseSize(value)
+	return err
+}
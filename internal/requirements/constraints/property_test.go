@@ -0,0 +1,77 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package constraints
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSizeProperty(t *testing.T) {
+	testCases := []struct {
+		description   string
+		value         string
+		other         string
+		expectedError bool
+		expected      int
+	}{
+		{
+			description: "equal sizes with different units",
+			value:       "24g",
+			other:       "24576m",
+			expected:    0,
+		},
+		{
+			description: "unit suffixes are case insensitive",
+			value:       "80GiB",
+			other:       "24G",
+			expected:    1,
+		},
+		{
+			description: "plain bytes are supported",
+			value:       "1023",
+			other:       "1k",
+			expected:    -1,
+		},
+		{
+			description: "missing value is less than any size",
+			value:       "",
+			other:       "0",
+			expected:    -1,
+		},
+		{
+			description:   "invalid size is an error",
+			value:         "24g",
+			other:         "lots",
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			p := NewSizeProperty("memory", tc.value)
+			c, err := p.CompareTo(tc.other)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, c)
+		})
+	}
+}
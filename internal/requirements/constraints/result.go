@@ -0,0 +1,77 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package constraints
+
+import "fmt"
+
+// A Result records the outcome of evaluating a single comparison against the
+// host properties.
+type Result struct {
+	// Constraint is the string representation of the evaluated comparison;
+	// for example cuda>=12.4.
+	Constraint string
+	// Property is the name of the host property that was compared.
+	Property string
+	// HostValue is the value of the property on the host. This is empty if
+	// the value could not be determined.
+	HostValue string
+	// Satisfied indicates whether the comparison holds for the host.
+	Satisfied bool
+	// Err is set if the comparison could not be evaluated.
+	Err error
+}
+
+// String returns a human-readable description of the result.
+func (r Result) String() string {
+	if r.Satisfied {
+		return fmt.Sprintf("requires %v and host has %v", r.Constraint, r.hostValue())
+	}
+	if r.Err != nil {
+		return fmt.Sprintf("requires %v but could not be evaluated: %v", r.Constraint, r.Err)
+	}
+	return fmt.Sprintf("requires %v but host has %v", r.Constraint, r.hostValue())
+}
+
+func (r Result) hostValue() string {
+	if r.HostValue == "" {
+		return fmt.Sprintf("no %v", r.Property)
+	}
+	return r.HostValue
+}
+
+// Evaluate returns the result of each comparison that makes up the specified
+// constraint. Unlike Assert, all comparisons are evaluated so that the
+// outcome of each can be reported.
+func Evaluate(c Constraint) []Result {
+	switch c := c.(type) {
+	case binary:
+		return []Result{c.result()}
+	case and:
+		return evaluateAll(c)
+	case or:
+		return evaluateAll(c)
+	}
+	return nil
+}
+
+func evaluateAll(constraints []Constraint) []Result {
+	var results []Result
+	for _, c := range constraints {
+		results = append(results, Evaluate(c)...)
+	}
+	return results
+}
@@ -0,0 +1,71 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package requirements
+
+import (
+	"strings"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/requirements/constraints"
+)
+
+// A Report describes the outcome of evaluating a set of requirements against
+// the host properties.
+type Report struct {
+	// Requirements are the requirements that were evaluated.
+	Requirements []string
+	// Results contains the outcome of each individual comparison.
+	Results []constraints.Result
+}
+
+// Passed returns the comparisons that are satisfied by the host.
+func (r *Report) Passed() []constraints.Result {
+	return r.filter(true)
+}
+
+// Failed returns the comparisons that are not satisfied by the host.
+func (r *Report) Failed() []constraints.Result {
+	return r.filter(false)
+}
+
+func (r *Report) filter(satisfied bool) []constraints.Result {
+	if r == nil {
+		return nil
+	}
+	var results []constraints.Result
+	for _, result := range r.Results {
+		if result.Satisfied == satisfied {
+			results = append(results, result)
+		}
+	}
+	return results
+}
+
+// String returns a summary of the report with one comparison per line.
+func (r *Report) String() string {
+	if r == nil {
+		return ""
+	}
+	var lines []string
+	for _, result := range r.Results {
+		status := "FAIL"
+		if result.Satisfied {
+			status = "PASS"
+		}
+		lines = append(lines, status+": "+result.String())
+	}
+	return strings.Join(lines, "\n")
+}
@@ -19,6 +19,7 @@ package requirements
 import (
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/requirements/constraints"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/units"
 )
 
 // Requirements represents a collection of requirements that can be compared to properties
@@ -39,6 +40,9 @@ func New(logger logger.Interface, requirements []string) *Requirements {
 			ARCH:   constraints.NewVersionProperty(ARCH, ""),
 			DRIVER: constraints.NewVersionProperty(DRIVER, ""),
 			BRAND:  constraints.NewStringProperty(BRAND, ""),
+			MEMORY: constraints.NewSizeProperty(MEMORY, ""),
+			// The nvpmodel power mode only applies to Tegra-based systems.
+			NVPMODEL: constraints.NewStringProperty(NVPMODEL, ""),
 		},
 	}
 
@@ -55,16 +59,34 @@ func (r *Requirements) AddStringProperty(name string, value string) {
 	r.properties[name] = constraints.NewStringProperty(name, value)
 }
 
-// Assert checks the specified requirements
-func (r Requirements) Assert() error {
+// AddSizeProperty adds the specified property (name, size in bytes) to the requirements
+func (r *Requirements) AddSizeProperty(name string, size uint64) {
+	r.properties[name] = constraints.NewSizeProperty(name, units.FormatSize(size))
+}
+
+// Assert checks the specified requirements and returns a report of which
+// comparisons passed or failed along with the host values they were checked
+// against. An error is returned if the requirements are not met.
+// Note that the report includes the comparisons of all alternatives of ORed
+// constraints, so failed comparisons do not imply that the requirements are
+// not met.
+func (r Requirements) Assert() (*Report, error) {
+	report := &Report{
+		Requirements: r.requirements,
+	}
 	if len(r.requirements) == 0 {
-		return nil
+		return report, nil
 	}
 
 	r.logger.Debugf("Checking properties %+v against requirements %v", r.properties, r.requirements)
 	c, err := constraints.New(r.logger, r.requirements, r.properties)
 	if err != nil {
-		return err
+		return report, err
+	}
+
+	report.Results = constraints.Evaluate(c)
+	if err := c.Assert(); err != nil {
+		return report, err
 	}
-	return c.Assert()
+	return report, nil
 }
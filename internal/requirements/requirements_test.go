@@ -0,0 +1,132 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package requirements
+
+import (
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssert(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	testCases := []struct {
+		description   string
+		requirements  []string
+		cudaVersion   string
+		brand         string
+		memory        uint64
+		expectedError string
+		expectedPass  []string
+		expectedFail  []string
+	}{
+		{
+			description: "no requirements",
+		},
+		{
+			description:  "satisfied requirement",
+			requirements: []string{"cuda>=12.2"},
+			cudaVersion:  "12.4",
+			expectedPass: []string{"requires cuda>=12.2 and host has 12.4"},
+		},
+		{
+			description:   "unsatisfied requirement includes host value",
+			requirements:  []string{"cuda>=12.4"},
+			cudaVersion:   "12.2",
+			expectedError: "unsatisfied condition: requires cuda>=12.4 but host has 12.2",
+			expectedFail:  []string{"requires cuda>=12.4 but host has 12.2"},
+		},
+		{
+			description:   "all comparisons are reported",
+			requirements:  []string{"cuda>=12.4", "brand=tesla"},
+			cudaVersion:   "12.2",
+			brand:         "tesla",
+			expectedError: "unsatisfied condition: requires cuda>=12.4 but host has 12.2",
+			expectedPass:  []string{"requires brand=tesla and host has tesla"},
+			expectedFail:  []string{"requires cuda>=12.4 but host has 12.2"},
+		},
+		{
+			description:   "alternatives are listed in the error",
+			requirements:  []string{"cuda>=12.4 brand=tesla"},
+			cudaVersion:   "12.2",
+			brand:         "geforce",
+			expectedError: "cuda>=12.4||brand=tesla not met: unsatisfied condition: requires cuda>=12.4 but host has 12.2; unsatisfied condition: requires brand=tesla but host has geforce",
+			expectedFail: []string{
+				"requires cuda>=12.4 but host has 12.2",
+				"requires brand=tesla but host has geforce",
+			},
+		},
+		{
+			description:  "satisfied memory requirement",
+			requirements: []string{"memory>=24g"},
+			memory:       80 << 30,
+			expectedPass: []string{"requires memory>=24g and host has 80g"},
+		},
+		{
+			description:   "unsatisfied memory requirement",
+			requirements:  []string{"memory>=24g"},
+			memory:        16 << 30,
+			expectedError: "unsatisfied condition: requires memory>=24g but host has 16g",
+			expectedFail:  []string{"requires memory>=24g but host has 16g"},
+		},
+		{
+			description:   "missing host value is reported",
+			requirements:  []string{"brand=tesla"},
+			expectedError: "unsatisfied condition: requires brand=tesla but host has no brand",
+			expectedFail:  []string{"requires brand=tesla but host has no brand"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			r := New(logger, tc.requirements)
+			if tc.cudaVersion != "" {
+				r.AddVersionProperty(CUDA, tc.cudaVersion)
+			}
+			if tc.brand != "" {
+				r.AddStringProperty(BRAND, tc.brand)
+			}
+			if tc.memory != 0 {
+				r.AddSizeProperty(MEMORY, tc.memory)
+			}
+
+			report, err := r.Assert()
+			if tc.expectedError == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tc.expectedError)
+			}
+
+			require.NotNil(t, report)
+			require.EqualValues(t, tc.requirements, report.Requirements)
+
+			var passed []string
+			for _, result := range report.Passed() {
+				passed = append(passed, result.String())
+			}
+			require.EqualValues(t, tc.expectedPass, passed)
+
+			var failed []string
+			for _, result := range report.Failed() {
+				failed = append(failed, result.String())
+			}
+			require.EqualValues(t, tc.expectedFail, failed)
+		})
+	}
+}
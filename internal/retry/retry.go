@@ -0,0 +1,142 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+// Package retry allows operations that may fail due to transient conditions
+// to be retried. This is the case during node startup, for example, where the
+// driver may still be loading or udev may not yet have created the device
+// nodes when the first containers are started.
+package retry
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+)
+
+const (
+	defaultBackoff    = 500 * time.Millisecond
+	defaultMaxBackoff = 5 * time.Second
+)
+
+// A Retrier retries operations with a capped exponential backoff.
+type Retrier struct {
+	logger      logger.Interface
+	attempts    int
+	backoff     time.Duration
+	maxBackoff  time.Duration
+	isTransient func(error) bool
+	sleep       func(time.Duration)
+}
+
+// Option is a functional option for configuring a Retrier.
+type Option func(*Retrier)
+
+// New creates a Retrier with the specified options. By default, operations
+// are attempted once.
+func New(opts ...Option) *Retrier {
+	r := &Retrier{}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if r.logger == nil {
+		r.logger = logger.New()
+	}
+	if r.attempts < 1 {
+		r.attempts = 1
+	}
+	if r.backoff <= 0 {
+		r.backoff = defaultBackoff
+	}
+	if r.maxBackoff <= 0 {
+		r.maxBackoff = defaultMaxBackoff
+	}
+	if r.maxBackoff < r.backoff {
+		r.maxBackoff = r.backoff
+	}
+	if r.isTransient == nil {
+		r.isTransient = func(error) bool { return true }
+	}
+	if r.sleep == nil {
+		r.sleep = time.Sleep
+	}
+	return r
+}
+
+// WithLogger sets the logger for the Retrier.
+func WithLogger(logger logger.Interface) Option {
+	return func(r *Retrier) {
+		r.logger = logger
+	}
+}
+
+// WithAttempts sets the maximum number of times an operation is attempted.
+func WithAttempts(attempts int) Option {
+	return func(r *Retrier) {
+		r.attempts = attempts
+	}
+}
+
+// WithBackoff sets the time to wait before the first retry. The time is
+// doubled for each subsequent retry.
+func WithBackoff(backoff time.Duration) Option {
+	return func(r *Retrier) {
+		r.backoff = backoff
+	}
+}
+
+// WithMaxBackoff sets the maximum time to wait between retries.
+func WithMaxBackoff(maxBackoff time.Duration) Option {
+	return func(r *Retrier) {
+		r.maxBackoff = maxBackoff
+	}
+}
+
+// WithIsTransient sets the function used to check whether an error is due to
+// a transient condition. Errors that are not transient are returned without
+// retrying the operation. By default, all errors are considered transient.
+func WithIsTransient(isTransient func(error) bool) Option {
+	return func(r *Retrier) {
+		r.isTransient = isTransient
+	}
+}
+
+// Do calls the specified function until it succeeds, fails with an error that
+// is not transient, or the maximum number of attempts is reached. The error
+// from the last attempt is returned.
+func (r *Retrier) Do(description string, f func() error) error {
+	backoff := r.backoff
+	var err error
+	for attempt := 1; attempt <= r.attempts; attempt++ {
+		err = f()
+		if err == nil {
+			return nil
+		}
+		if !r.isTransient(err) {
+			return err
+		}
+		if attempt == r.attempts {
+			break
+		}
+		r.logger.Warningf("Failed to %v (attempt %d of %d): %v; retrying in %v", description, attempt, r.attempts, err, backoff)
+		r.sleep(backoff)
+		backoff = min(2*backoff, r.maxBackoff)
+	}
+	if r.attempts > 1 {
+		return fmt.Errorf("failed to %v after %d attempts: %w", description, r.attempts, err)
+	}
+	return err
+}
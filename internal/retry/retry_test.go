@@ -0,0 +1,133 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package retry
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetrier(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	errTransient := errors.New("transient")
+	errPermanent := errors.New("permanent")
+
+	testCases := []struct {
+		description      string
+		attempts         int
+		maxBackoff       time.Duration
+		failures         int
+		failureErr       error
+		expectedCalls    int
+		expectedSleeps   []time.Duration
+		expectedErrorIs  error
+		expectedErrorMsg string
+	}{
+		{
+			description:   "success is not retried",
+			attempts:      3,
+			expectedCalls: 1,
+		},
+		{
+			description:     "default is a single attempt",
+			failures:        1,
+			expectedCalls:   1,
+			expectedErrorIs: errTransient,
+			// The error is returned as is if no retries are configured.
+			expectedErrorMsg: "transient",
+		},
+		{
+			description:    "transient failure is retried with backoff",
+			attempts:       3,
+			failures:       2,
+			expectedCalls:  3,
+			expectedSleeps: []time.Duration{time.Second, 2 * time.Second},
+		},
+		{
+			description:      "persistent failure returns last error",
+			attempts:         3,
+			failures:         5,
+			expectedCalls:    3,
+			expectedSleeps:   []time.Duration{time.Second, 2 * time.Second},
+			expectedErrorIs:  errTransient,
+			expectedErrorMsg: "failed to do something after 3 attempts: transient",
+		},
+		{
+			description:      "backoff is capped",
+			attempts:         5,
+			maxBackoff:       3 * time.Second,
+			failures:         5,
+			expectedCalls:    5,
+			expectedSleeps:   []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second},
+			expectedErrorIs:  errTransient,
+			expectedErrorMsg: "failed to do something after 5 attempts: transient",
+		},
+		{
+			description:      "permanent failure is not retried",
+			attempts:         3,
+			failures:         5,
+			failureErr:       errPermanent,
+			expectedCalls:    1,
+			expectedErrorIs:  errPermanent,
+			expectedErrorMsg: "permanent",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			var sleeps []time.Duration
+			r := New(
+				WithLogger(logger),
+				WithAttempts(tc.attempts),
+				WithBackoff(time.Second),
+				WithMaxBackoff(tc.maxBackoff),
+				WithIsTransient(func(err error) bool {
+					return errors.Is(err, errTransient)
+				}),
+			)
+			r.sleep = func(d time.Duration) {
+				sleeps = append(sleeps, d)
+			}
+
+			var calls int
+			err := r.Do("do something", func() error {
+				calls++
+				if calls <= tc.failures {
+					if tc.failureErr != nil {
+						return tc.failureErr
+					}
+					return errTransient
+				}
+				return nil
+			})
+
+			require.Equal(t, tc.expectedCalls, calls)
+			require.EqualValues(t, tc.expectedSleeps, sleeps)
+			if tc.expectedErrorIs == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, tc.expectedErrorIs)
+			require.EqualError(t, err, tc.expectedErrorMsg)
+		})
+	}
+}
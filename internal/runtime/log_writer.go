@@ -0,0 +1,93 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package runtime
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"sync"
+
+	"golang.org/x/sys/unix"
+)
+
+// A lineWriter writes complete lines to a log file that may be shared by
+// concurrent invocations of the runtime.
+// Writes are buffered until a complete line is available. All complete lines
+// are then written to the file in a single write while holding an exclusive
+// advisory lock on the file. Since the file is opened with O_APPEND, this
+// ensures that the lines from different processes are not interleaved.
+type lineWriter struct {
+	sync.Mutex
+	file *os.File
+	buf  []byte
+}
+
+var _ io.WriteCloser = (*lineWriter)(nil)
+
+func newLineWriter(file *os.File) *lineWriter {
+	return &lineWriter{
+		file: file,
+	}
+}
+
+// Write buffers the specified bytes and writes all complete lines to the
+// underlying file.
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.Lock()
+	defer w.Unlock()
+
+	w.buf = append(w.buf, p...)
+	end := bytes.LastIndexByte(w.buf, '\n')
+	if end < 0 {
+		return len(p), nil
+	}
+	err := w.writeLocked(w.buf[:end+1])
+	w.buf = append(w.buf[:0], w.buf[end+1:]...)
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close writes any remaining partial line and closes the underlying file.
+func (w *lineWriter) Close() error {
+	w.Lock()
+	defer w.Unlock()
+
+	var err error
+	if len(w.buf) > 0 {
+		err = w.writeLocked(w.buf)
+		w.buf = nil
+	}
+	return errors.Join(err, w.file.Close())
+}
+
+func (w *lineWriter) writeLocked(p []byte) error {
+	fd := int(w.file.Fd())
+	if err := unix.Flock(fd, unix.LOCK_EX); err != nil {
+		// If the file cannot be locked we still rely on O_APPEND.
+		_, err := w.file.Write(p)
+		return err
+	}
+	defer func() {
+		_ = unix.Flock(fd, unix.LOCK_UN)
+	}()
+	_, err := w.file.Write(p)
+	return err
+}
@@ -35,7 +35,7 @@ import (
 type Logger struct {
 	logger.Interface
 	previousLogger logger.Interface
-	logFiles       []*os.File
+	logFiles       []*lineWriter
 }
 
 // NewLogger creates an empty logger
@@ -57,7 +57,7 @@ func (l *Logger) Update(filename string, logLevel string, argv []string) {
 		}
 	}()
 
-	var logFiles []*os.File
+	var logFiles []*lineWriter
 	var argLogFileError error
 
 	// We don't create log files if the version argument is supplied
@@ -67,12 +67,12 @@ func (l *Logger) Update(filename string, logLevel string, argv []string) {
 			argLogFileError = errors.Join(argLogFileError, err)
 		}
 		if configLogFile != nil {
-			logFiles = append(logFiles, configLogFile)
+			logFiles = append(logFiles, newLineWriter(configLogFile))
 		}
 
 		argLogFile, err := createLogFile(configFromArgs.file)
 		if argLogFile != nil {
-			logFiles = append(logFiles, argLogFile)
+			logFiles = append(logFiles, newLineWriter(argLogFile))
 		}
 		argLogFileError = errors.Join(argLogFileError, err)
 	}
@@ -17,6 +17,8 @@
 package runtime
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/sirupsen/logrus"
@@ -34,3 +36,33 @@ func TestLogger(t *testing.T) {
 	lp := l.previousLogger.(*logrus.Logger)
 	require.Equal(t, logrus.InfoLevel, lp.Level)
 }
+
+func TestLineWriter(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "runtime.log")
+
+	var writers []*lineWriter
+	for i := 0; i < 2; i++ {
+		f, err := createLogFile(filename)
+		require.NoError(t, err)
+		writers = append(writers, newLineWriter(f))
+	}
+
+	_, err := writers[0].Write([]byte("first "))
+	require.NoError(t, err)
+	_, err = writers[1].Write([]byte("second line\n"))
+	require.NoError(t, err)
+	_, err = writers[0].Write([]byte("line\npartial"))
+	require.NoError(t, err)
+
+	contents, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	require.Equal(t, "second line\nfirst line\n", string(contents))
+
+	for _, w := range writers {
+		require.NoError(t, w.Close())
+	}
+
+	contents, err = os.ReadFile(filename)
+	require.NoError(t, err)
+	require.Equal(t, "second line\nfirst line\npartial", string(contents))
+}
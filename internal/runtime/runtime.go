@@ -27,6 +27,8 @@ import (
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/info"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup/root"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/warnings"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/oci"
 )
 
 // Run is an entry point that allows for idiomatic handling of errors
@@ -43,7 +45,7 @@ func (r rt) Run(argv []string) (rerr error) {
 		fmt.Printf("%v version %v\n", "NVIDIA Container Runtime", info.GetVersionString(fmt.Sprintf("spec: %v", specs.Version)))
 	}
 
-	cfg, err := config.GetConfig()
+	cfg, configWarnings, err := config.GetConfigWithWarnings()
 	if err != nil {
 		return fmt.Errorf("error loading config: %v", err)
 	}
@@ -55,6 +57,10 @@ func (r rt) Run(argv []string) (rerr error) {
 	defer func() {
 		if rerr != nil {
 			r.logger.Errorf("%v", rerr)
+			if !cfg.NVIDIAContainerRuntimeConfig.ResourceConstrained {
+				recordError(r.logger, cfg, rerr)
+				recordTelemetryFailure(r.logger, cfg, rerr)
+			}
 		}
 		if err := r.logger.Reset(); err != nil {
 			rerr = errors.Join(rerr, fmt.Errorf("failed to reset logger: %v", err))
@@ -70,6 +76,15 @@ func (r rt) Run(argv []string) (rerr error) {
 	cfg.NVIDIACTKConfig.Path = config.ResolveNVIDIACTKPath(&logger.NullLogger{}, cfg.NVIDIACTKConfig.Path)
 	cfg.NVIDIAContainerRuntimeHookConfig.Path = config.ResolveNVIDIAContainerRuntimeHookPath(&logger.NullLogger{}, cfg.NVIDIAContainerRuntimeHookConfig.Path)
 
+	// Since the runtime is invoked for each OCI runtime command, warnings for
+	// the config are only emitted when a container is created.
+	if oci.HasCreateSubcommand(argv) {
+		if userConfigFilePath := config.GetUserConfigOverlayFilePath(); userConfigFilePath != "" {
+			r.logger.Infof("Applied per-user config %v over the system config", userConfigFilePath)
+		}
+		warnings.Emit(r.logger, configWarnings...)
+	}
+
 	// Log the config at Trace to allow for debugging if required.
 	r.logger.Tracef("Running with config: %+v", cfg)
 
@@ -81,7 +96,7 @@ func (r rt) Run(argv []string) (rerr error) {
 	r.logger.Tracef("Command line arguments: %v", argv)
 	runtime, err := newNVIDIAContainerRuntime(r.logger, cfg, argv, driver)
 	if err != nil {
-		return fmt.Errorf("failed to create NVIDIA Container Runtime: %v", err)
+		return fmt.Errorf("failed to create NVIDIA Container Runtime: %w", err)
 	}
 
 	if printVersion {
@@ -0,0 +1,319 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package runtime
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/modifier"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/oci"
+)
+
+// adjustingRuntime wraps a low-level runtime and adjusts the file passed to
+// an exec or update subcommand based on the spec of the (already created)
+// container before invoking the low-level runtime.
+type adjustingRuntime struct {
+	logger  logger.Interface
+	runtime oci.Runtime
+
+	subcommand string
+	flagNames  []string
+	adjust     func([]byte, *specs.Spec) ([]byte, bool, error)
+}
+
+var _ oci.Runtime = (*adjustingRuntime)(nil)
+
+// newAdjustingRuntime returns a runtime that applies the adjustments required
+// for the exec and update subcommands in containers with NVIDIA devices:
+//   - for exec, environment variables such as NVIDIA_VISIBLE_DEVICES and
+//     CUDA_VISIBLE_DEVICES that are set in the container, as well as the
+//     environment variables recorded as injected when the container was
+//     created, are added to the process if not already set.
+//   - for update, device cgroup rules for NVIDIA devices that were added when
+//     the container was created are retained if the resources being applied
+//     include device rules.
+//
+// If the arguments do not contain a supported subcommand, the low-level
+// runtime is returned unmodified.
+func newAdjustingRuntime(logger logger.Interface, lowLevelRuntime oci.Runtime, argv []string) oci.Runtime {
+	switch {
+	case oci.HasSubcommand(argv, "exec"):
+		return &adjustingRuntime{
+			logger:     logger,
+			runtime:    lowLevelRuntime,
+			subcommand: "exec",
+			flagNames:  []string{"process", "p"},
+			adjust:     adjustExecProcess,
+		}
+	case oci.HasSubcommand(argv, "update"):
+		return &adjustingRuntime{
+			logger:     logger,
+			runtime:    lowLevelRuntime,
+			subcommand: "update",
+			flagNames:  []string{"resources", "r"},
+			adjust:     adjustUpdateResources,
+		}
+	}
+	return lowLevelRuntime
+}
+
+// Exec applies the adjustments and then execs into the wrapped runtime.
+// Failures to apply the adjustments are logged but are not fatal.
+func (r *adjustingRuntime) Exec(args []string) error {
+	if err := r.applyAdjustments(args); err != nil {
+		r.logger.Warningf("Skipping adjustments for %v: %v", r.subcommand, err)
+	}
+	return r.runtime.Exec(args)
+}
+
+// String returns the string representation of the wrapped runtime.
+func (r *adjustingRuntime) String() string {
+	return r.runtime.String()
+}
+
+func (r *adjustingRuntime) applyAdjustments(args []string) error {
+	subcommandIndex := oci.GetSubcommandIndex(args, r.subcommand)
+	if subcommandIndex < 0 || subcommandIndex == len(args)-1 {
+		return nil
+	}
+	path := oci.GetFlagValue(args[subcommandIndex+1:], r.flagNames...)
+	if path == "" || path == "-" {
+		r.logger.Debugf("No file specified for %v; no adjustments required", r.subcommand)
+		return nil
+	}
+
+	containerID := args[len(args)-1]
+	state, err := r.getContainerState(args[1:subcommandIndex], containerID)
+	if err != nil {
+		return err
+	}
+	if !hasInjectionAnnotations(state.Annotations) {
+		r.logger.Debugf("Container %v was not modified by the NVIDIA Container Runtime; no adjustments required", containerID)
+		return nil
+	}
+	containerSpec, err := state.LoadSpec()
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	adjusted, modified, err := r.adjust(contents, containerSpec)
+	if err != nil {
+		return err
+	}
+	if !modified {
+		return nil
+	}
+	r.logger.Debugf("Updating %v for %v", path, r.subcommand)
+	return os.WriteFile(path, adjusted, info.Mode().Perm())
+}
+
+// getContainerState queries the state of the specified container from the
+// low-level runtime using the specified global flags.
+func (r *adjustingRuntime) getContainerState(globalFlags []string, containerID string) (*oci.State, error) {
+	stateArgs := append(append([]string{}, globalFlags...), "state", containerID)
+
+	var stderr bytes.Buffer
+	cmd := exec.Command(r.runtime.String(), stateArgs...)
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get state of container %v: %w: %s", containerID, err, bytes.TrimSpace(stderr.Bytes()))
+	}
+
+	return oci.ReadContainerState(bytes.NewReader(output))
+}
+
+// hasInjectionAnnotations checks whether the specified container annotations
+// include the annotations that are set when devices or envvars are injected
+// into a container. Since the annotations are included in the container
+// state, this allows the bundle of containers without NVIDIA devices to be
+// ignored.
+func hasInjectionAnnotations(annotations map[string]string) bool {
+	for _, annotation := range []string{modifier.InjectedEnvvarsAnnotation, modifier.InjectedDevicesAnnotation} {
+		if _, ok := annotations[annotation]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// adjustExecProcess adds environment variables relevant to NVIDIA devices from
+// the container spec to the process for an exec subcommand. This includes the
+// NVIDIA_* and CUDA_* envvars and the envvars recorded as injected in the
+// container spec. An injected envvar that is already set for the process is
+// only replaced if the injected value extends the value of the process (e.g.
+// if folders were prepended to LD_LIBRARY_PATH) since this indicates that the
+// value of the original container config was passed by the container engine.
+func adjustExecProcess(contents []byte, containerSpec *specs.Spec) ([]byte, bool, error) {
+	if containerSpec == nil || containerSpec.Process == nil {
+		return contents, false, nil
+	}
+
+	// We only update the env field to ensure that other fields are
+	// maintained as is.
+	var process map[string]json.RawMessage
+	if err := json.Unmarshal(contents, &process); err != nil {
+		return nil, false, fmt.Errorf("failed to decode process: %w", err)
+	}
+	var processEnv []string
+	if raw, ok := process["env"]; ok {
+		if err := json.Unmarshal(raw, &processEnv); err != nil {
+			return nil, false, fmt.Errorf("failed to decode process env: %w", err)
+		}
+	}
+
+	existing := make(map[string]int)
+	for i, env := range processEnv {
+		key := strings.SplitN(env, "=", 2)[0]
+		if _, ok := existing[key]; !ok {
+			existing[key] = i
+		}
+	}
+
+	injected := make(map[string]bool)
+	for _, key := range modifier.GetInjectedEnvvars(containerSpec) {
+		injected[key] = true
+	}
+
+	var modified bool
+	for _, env := range containerSpec.Process.Env {
+		key, value, _ := strings.Cut(env, "=")
+		if !injected[key] && !strings.HasPrefix(key, "NVIDIA_") && !strings.HasPrefix(key, "CUDA_") {
+			continue
+		}
+		if i, ok := existing[key]; ok {
+			_, processValue, _ := strings.Cut(processEnv[i], "=")
+			if injected[key] && value != processValue && extendsPathList(value, processValue) {
+				processEnv[i] = env
+				modified = true
+			}
+			continue
+		}
+		processEnv = append(processEnv, env)
+		existing[key] = len(processEnv) - 1
+		modified = true
+	}
+	if !modified {
+		return contents, false, nil
+	}
+
+	return updateField(process, "env", processEnv)
+}
+
+// extendsPathList checks whether the specified value is a colon-separated list
+// of paths that ends or starts with the specified list.
+func extendsPathList(value string, list string) bool {
+	if list == "" {
+		return true
+	}
+	return strings.HasSuffix(value, ":"+list) || strings.HasPrefix(value, list+":")
+}
+
+// adjustUpdateResources adds the device cgroup rules for NVIDIA devices from
+// the container spec to the resources for an update subcommand. This is only
+// required if the resources include device rules since these replace the
+// existing rules.
+func adjustUpdateResources(contents []byte, containerSpec *specs.Spec) ([]byte, bool, error) {
+	if containerSpec == nil || containerSpec.Linux == nil || containerSpec.Linux.Resources == nil {
+		return contents, false, nil
+	}
+
+	// We only update the devices field to ensure that other fields are
+	// maintained as is.
+	var resources map[string]json.RawMessage
+	if err := json.Unmarshal(contents, &resources); err != nil {
+		return nil, false, fmt.Errorf("failed to decode resources: %w", err)
+	}
+	var deviceRules []specs.LinuxDeviceCgroup
+	if raw, ok := resources["devices"]; ok {
+		if err := json.Unmarshal(raw, &deviceRules); err != nil {
+			return nil, false, fmt.Errorf("failed to decode device rules: %w", err)
+		}
+	}
+	if len(deviceRules) == 0 {
+		return contents, false, nil
+	}
+
+	nvidiaDevices := make(map[string]bool)
+	for _, device := range containerSpec.Linux.Devices {
+		if !strings.HasPrefix(device.Path, "/dev/nvidia") {
+			continue
+		}
+		nvidiaDevices[deviceRuleKey(device.Type, &device.Major, &device.Minor)] = true
+	}
+
+	existing := make(map[string]bool)
+	for _, rule := range deviceRules {
+		existing[deviceRuleKey(rule.Type, rule.Major, rule.Minor)] = true
+	}
+
+	var modified bool
+	for _, rule := range containerSpec.Linux.Resources.Devices {
+		key := deviceRuleKey(rule.Type, rule.Major, rule.Minor)
+		if !rule.Allow || !nvidiaDevices[key] || existing[key] {
+			continue
+		}
+		deviceRules = append(deviceRules, rule)
+		existing[key] = true
+		modified = true
+	}
+	if !modified {
+		return contents, false, nil
+	}
+
+	return updateField(resources, "devices", deviceRules)
+}
+
+// updateField sets the specified field in the raw JSON object and returns
+// the encoded object.
+func updateField(object map[string]json.RawMessage, field string, value interface{}) ([]byte, bool, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to encode %v: %w", field, err)
+	}
+	object[field] = raw
+
+	contents, err := json.Marshal(object)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to encode %v: %w", field, err)
+	}
+	return contents, true, nil
+}
+
+func deviceRuleKey(deviceType string, major *int64, minor *int64) string {
+	if major == nil || minor == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d:%d", deviceType, *major, *minor)
+}
@@ -0,0 +1,206 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package runtime
+
+import (
+	"testing"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/modifier"
+)
+
+func TestAdjustExecProcess(t *testing.T) {
+	containerSpec := &specs.Spec{
+		Process: &specs.Process{
+			Env: []string{
+				"PATH=/usr/bin",
+				"NVIDIA_VISIBLE_DEVICES=0",
+				"CUDA_VISIBLE_DEVICES=0",
+			},
+		},
+	}
+
+	testCases := []struct {
+		description      string
+		process          string
+		expectedModified bool
+		expectedProcess  string
+	}{
+		{
+			description:      "missing envvars are added",
+			process:          `{"cwd":"/","env":["PATH=/bin"]}`,
+			expectedModified: true,
+			expectedProcess:  `{"cwd":"/","env":["PATH=/bin","NVIDIA_VISIBLE_DEVICES=0","CUDA_VISIBLE_DEVICES=0"]}`,
+		},
+		{
+			description:      "existing envvars are not overridden",
+			process:          `{"cwd":"/","env":["NVIDIA_VISIBLE_DEVICES=1"]}`,
+			expectedModified: true,
+			expectedProcess:  `{"cwd":"/","env":["NVIDIA_VISIBLE_DEVICES=1","CUDA_VISIBLE_DEVICES=0"]}`,
+		},
+		{
+			description:     "no missing envvars is not modified",
+			process:         `{"cwd":"/","env":["NVIDIA_VISIBLE_DEVICES=0","CUDA_VISIBLE_DEVICES=0"]}`,
+			expectedProcess: `{"cwd":"/","env":["NVIDIA_VISIBLE_DEVICES=0","CUDA_VISIBLE_DEVICES=0"]}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			adjusted, modified, err := adjustExecProcess([]byte(tc.process), containerSpec)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedModified, modified)
+			require.JSONEq(t, tc.expectedProcess, string(adjusted))
+		})
+	}
+}
+
+func TestAdjustExecProcessInjectedEnvvars(t *testing.T) {
+	containerSpec := &specs.Spec{
+		Annotations: map[string]string{
+			"nvidia.com/injected-envvars": "LD_LIBRARY_PATH,OTHER",
+		},
+		Process: &specs.Process{
+			Env: []string{
+				"PATH=/usr/bin",
+				"LD_LIBRARY_PATH=/usr/lib/aarch64-linux-gnu/tegra:/opt/lib",
+				"OTHER=injected",
+			},
+		},
+	}
+
+	testCases := []struct {
+		description      string
+		process          string
+		expectedModified bool
+		expectedProcess  string
+	}{
+		{
+			description:      "missing injected envvars are added",
+			process:          `{"cwd":"/","env":["PATH=/bin"]}`,
+			expectedModified: true,
+			expectedProcess:  `{"cwd":"/","env":["PATH=/bin","LD_LIBRARY_PATH=/usr/lib/aarch64-linux-gnu/tegra:/opt/lib","OTHER=injected"]}`,
+		},
+		{
+			description:      "extended path list is replaced",
+			process:          `{"cwd":"/","env":["LD_LIBRARY_PATH=/opt/lib","OTHER=injected"]}`,
+			expectedModified: true,
+			expectedProcess:  `{"cwd":"/","env":["LD_LIBRARY_PATH=/usr/lib/aarch64-linux-gnu/tegra:/opt/lib","OTHER=injected"]}`,
+		},
+		{
+			description:     "explicitly set envvars are not overridden",
+			process:         `{"cwd":"/","env":["LD_LIBRARY_PATH=/custom","OTHER=custom"]}`,
+			expectedProcess: `{"cwd":"/","env":["LD_LIBRARY_PATH=/custom","OTHER=custom"]}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			adjusted, modified, err := adjustExecProcess([]byte(tc.process), containerSpec)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedModified, modified)
+			require.JSONEq(t, tc.expectedProcess, string(adjusted))
+		})
+	}
+}
+
+func TestAdjustUpdateResources(t *testing.T) {
+	major, minor := int64(195), int64(0)
+	otherMajor, otherMinor := int64(1), int64(3)
+	containerSpec := &specs.Spec{
+		Linux: &specs.Linux{
+			Devices: []specs.LinuxDevice{
+				{Path: "/dev/nvidia0", Type: "c", Major: major, Minor: minor},
+				{Path: "/dev/null", Type: "c", Major: otherMajor, Minor: otherMinor},
+			},
+			Resources: &specs.LinuxResources{
+				Devices: []specs.LinuxDeviceCgroup{
+					{Allow: false, Access: "rwm"},
+					{Allow: true, Type: "c", Major: &major, Minor: &minor, Access: "rw"},
+					{Allow: true, Type: "c", Major: &otherMajor, Minor: &otherMinor, Access: "rwm"},
+				},
+			},
+		},
+	}
+
+	testCases := []struct {
+		description       string
+		resources         string
+		expectedModified  bool
+		expectedResources string
+	}{
+		{
+			description:       "resources without device rules are not modified",
+			resources:         `{"memory":{"limit":1024}}`,
+			expectedResources: `{"memory":{"limit":1024}}`,
+		},
+		{
+			description:       "nvidia device rules are added",
+			resources:         `{"devices":[{"allow":false,"access":"rwm"}]}`,
+			expectedModified:  true,
+			expectedResources: `{"devices":[{"allow":false,"access":"rwm"},{"allow":true,"type":"c","major":195,"minor":0,"access":"rw"}]}`,
+		},
+		{
+			description:       "existing nvidia device rules are not modified",
+			resources:         `{"devices":[{"allow":true,"type":"c","major":195,"minor":0,"access":"r"}]}`,
+			expectedResources: `{"devices":[{"allow":true,"type":"c","major":195,"minor":0,"access":"r"}]}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			adjusted, modified, err := adjustUpdateResources([]byte(tc.resources), containerSpec)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedModified, modified)
+			require.JSONEq(t, tc.expectedResources, string(adjusted))
+		})
+	}
+}
+
+func TestHasInjectionAnnotations(t *testing.T) {
+	testCases := []struct {
+		description string
+		annotations map[string]string
+		expected    bool
+	}{
+		{
+			description: "no annotations",
+		},
+		{
+			description: "unrelated annotations",
+			annotations: map[string]string{"io.kubernetes.cri.container-type": "container"},
+		},
+		{
+			description: "injected envvars annotation",
+			annotations: map[string]string{modifier.InjectedEnvvarsAnnotation: "LD_LIBRARY_PATH"},
+			expected:    true,
+		},
+		{
+			description: "injected devices annotation",
+			annotations: map[string]string{modifier.InjectedDevicesAnnotation: "GPU-0"},
+			expected:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			require.Equal(t, tc.expected, hasInjectionAnnotations(tc.annotations))
+		})
+	}
+}
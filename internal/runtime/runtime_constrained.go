@@ -0,0 +1,38 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package runtime
+
+import (
+	"fmt"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/info"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+)
+
+// resolveResourceConstrainedMode resolves the runtime mode if the runtime is
+// configured for resource-constrained systems. Since the platform is not
+// probed, the auto mode always resolves to the CSV mode. Other modes require
+// NVML or the nvidia-container-cli and are not supported.
+func resolveResourceConstrainedMode(logger logger.Interface, mode string) (info.RuntimeMode, error) {
+	switch mode {
+	case "", "auto", string(info.CSVRuntimeMode):
+		logger.Debugf("Using %q mode for resource-constrained system", info.CSVRuntimeMode)
+		return info.CSVRuntimeMode, nil
+	default:
+		return "", fmt.Errorf("mode %q is not supported if resource-constrained is enabled; use %q or \"auto\"", mode, info.CSVRuntimeMode)
+	}
+}
@@ -0,0 +1,70 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package runtime
+
+import (
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/info"
+)
+
+func TestResolveResourceConstrainedMode(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	testCases := []struct {
+		description   string
+		mode          string
+		expectedMode  info.RuntimeMode
+		expectedError bool
+	}{
+		{
+			description:  "auto resolves to csv",
+			mode:         "auto",
+			expectedMode: info.CSVRuntimeMode,
+		},
+		{
+			description:  "csv is supported",
+			mode:         "csv",
+			expectedMode: info.CSVRuntimeMode,
+		},
+		{
+			description:   "jit-cdi is not supported",
+			mode:          "jit-cdi",
+			expectedError: true,
+		},
+		{
+			description:   "legacy is not supported",
+			mode:          "legacy",
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			mode, err := resolveResourceConstrainedMode(logger, tc.mode)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedMode, mode)
+		})
+	}
+}
@@ -17,85 +17,236 @@
 package runtime
 
 import (
+	"errors"
 	"fmt"
+	"os"
 
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/config/image"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/discover"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/edits"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/info"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup/root"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/modifier"
-	"github.com/NVIDIA/nvidia-container-toolkit/internal/oci"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/warnings"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/oci"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/policy"
+)
+
+var (
+	// errLowLevelRuntime is returned if the low-level runtime cannot be
+	// located or constructed.
+	errLowLevelRuntime = errors.New("error constructing low-level runtime")
+	// errOCISpec is returned if the OCI spec of the container cannot be
+	// loaded.
+	errOCISpec = errors.New("error constructing OCI specification")
+	// errSpecModifier is returned if the modifications required for the
+	// container cannot be constructed.
+	errSpecModifier = errors.New("failed to construct OCI spec modifier")
 )
 
 // newNVIDIAContainerRuntime is a factory method that constructs a runtime based on the selected configuration and specified logger
 func newNVIDIAContainerRuntime(logger logger.Interface, cfg *config.Config, argv []string, driver *root.Driver) (oci.Runtime, error) {
 	lowLevelRuntime, err := oci.NewLowLevelRuntime(logger, cfg.NVIDIAContainerRuntimeConfig.Runtimes)
 	if err != nil {
-		return nil, fmt.Errorf("error constructing low-level runtime: %v", err)
+		return nil, fmt.Errorf("%w: %v", errLowLevelRuntime, err)
 	}
 
 	logger.Tracef("Using low-level runtime %v", lowLevelRuntime.String())
 	if !oci.HasCreateSubcommand(argv) {
 		logger.Tracef("Skipping modifier for non-create subcommand")
-		return lowLevelRuntime, nil
+		return newAdjustingRuntime(logger, lowLevelRuntime, argv), nil
+	}
+
+	// The version of the low-level runtime is only checked on create to avoid
+	// executing the runtime for every other subcommand.
+	if err := oci.CheckRuntimeVersion(logger, lowLevelRuntime.String(), cfg.NVIDIAContainerRuntimeConfig.MinimumRuntimeVersion); err != nil {
+		return nil, fmt.Errorf("%w: %v", errLowLevelRuntime, err)
+	}
+
+	warningRecorder := newWarningRecorder(logger, cfg)
+	if warningRecorder != nil {
+		logger = warningRecorder
 	}
 
 	ociSpec, err := oci.NewSpec(logger, argv)
 	if err != nil {
-		return nil, fmt.Errorf("error constructing OCI specification: %v", err)
+		return nil, fmt.Errorf("%w: %v", errOCISpec, err)
 	}
 
-	specModifier, err := newSpecModifier(logger, cfg, ociSpec, driver)
+	rawSpec, err := ociSpec.Load()
 	if err != nil {
-		return nil, fmt.Errorf("failed to construct OCI spec modifier: %v", err)
+		return nil, fmt.Errorf("%w: failed to load OCI spec: %v", errOCISpec, err)
 	}
 
+	bundleDir, err := oci.GetBundleDir(argv)
+	if err != nil {
+		return nil, err
+	}
+	if bundleDir == "" {
+		// As is the case for runc, the current directory is used as the
+		// bundle if none is specified.
+		bundleDir = "."
+	}
+	if modifier.IsPreparedForNestedContainers(cfg, rawSpec, bundleDir) {
+		logger.Infof("OCI spec has already been prepared for nested containers; skipping modifications")
+		return lowLevelRuntime, nil
+	}
+
+	specModifier, err := newSpecModifier(logger, cfg, ociSpec, driver, config.IsRootless())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errSpecModifier, err)
+	}
+
+	if cfg.Features.HookDiagnostics.IsEnabled() && !cfg.NVIDIAContainerRuntimeConfig.ResourceConstrained {
+		lowLevelRuntime = oci.NewHookDiagnosticsRuntimeWrapper(logger, lowLevelRuntime, bundleDir)
+	}
+
+	modifiers := newModifiers(logger, cfg, specModifier, driver, containerState{
+		rawSpec:         rawSpec,
+		bundleDir:       bundleDir,
+		lowLevelRuntime: lowLevelRuntime.String(),
+		warningRecorder: warningRecorder,
+	})
+
 	// Create the wrapping runtime with the specified modifier.
 	r := oci.NewModifyingRuntimeWrapper(
 		logger,
 		lowLevelRuntime,
 		ociSpec,
-		specModifier,
+		modifiers,
 	)
 
 	return r, nil
 }
 
+// newWarningRecorder returns a logger that records the warnings raised while
+// modifying the container if injection reports are enabled. Otherwise nil is
+// returned.
+func newWarningRecorder(l logger.Interface, cfg *config.Config) *logger.WarningRecorder {
+	if !cfg.Features.WriteInjectionReport.IsEnabled() || cfg.NVIDIAContainerRuntimeConfig.ResourceConstrained {
+		return nil
+	}
+	return logger.NewWarningRecorder(l)
+}
+
 // newSpecModifier is a factory method that creates constructs an OCI spec modifer based on the provided config.
-func newSpecModifier(logger logger.Interface, cfg *config.Config, ociSpec oci.Spec, driver *root.Driver) (oci.SpecModifier, error) {
+// The rootless flag indicates whether the container is created by a rootless container engine.
+func newSpecModifier(logger logger.Interface, cfg *config.Config, ociSpec oci.Spec, driver *root.Driver, rootless bool) (oci.SpecModifier, error) {
 	mode, image, err := initRuntimeModeAndImage(logger, cfg, ociSpec)
 	if err != nil {
 		return nil, err
 	}
+	warnings.Emit(logger, image.Warnings()...)
+
+	if err := policy.FromConfig(cfg).CheckDevices(image.VisibleDevices()); err != nil {
+		return nil, err
+	}
+
+	downgradedImage, capabilityDowngrader, err := modifier.NewCapabilityDowngrader(logger, cfg, driver, *image)
+	if err != nil {
+		return nil, err
+	}
+	image = &downgradedImage
 
 	modeModifier, err := newModeModifier(logger, mode, cfg, *image)
 	if err != nil {
 		return nil, err
 	}
 
-	hookCreator := discover.NewHookCreator(discover.WithNVIDIACDIHookPath(cfg.NVIDIACTKConfig.Path))
+	hookCreatorOptions := []discover.Option{
+		discover.WithNVIDIACDIHookPath(cfg.NVIDIACTKConfig.Path),
+		discover.WithSkipLDCacheCreation(cfg.Features.SkipLDCacheCreation.IsEnabled()),
+		discover.WithSandboxedHooks(cfg.Features.SandboxHooks.IsEnabled()),
+		discover.WithStableHookPaths(cfg.Features.StableHookPaths.IsEnabled()),
+	}
+	for name, path := range cfg.NVIDIACTKConfig.HookPaths {
+		if !discover.IsValidHookName(name) {
+			return nil, fmt.Errorf("invalid hook name %q in nvidia-ctk.hook-paths", name)
+		}
+		hookCreatorOptions = append(hookCreatorOptions, discover.WithHookPath(discover.HookName(name), path))
+	}
+	hookCreator := discover.NewHookCreator(hookCreatorOptions...)
+	modifierPlugins := cfg.NVIDIAContainerRuntimeConfig.ModifierPlugins
+
 	var modifiers modifier.List
-	for _, modifierType := range supportedModifierTypes(mode) {
+	if len(modifierPlugins.Pre) > 0 {
+		modifiers = append(modifiers, modifier.NewPluginModifiers(logger, modifierPlugins.GetTimeout(), modifierPlugins.Pre...))
+	}
+	modifiers = append(modifiers, capabilityDowngrader)
+	var nvidiaModifiers modifier.List
+	for _, modifierType := range supportedModifierTypes(mode, cfg.NVIDIAContainerRuntimeConfig.ResourceConstrained) {
 		switch modifierType {
 		case "mode":
-			modifiers = append(modifiers, modeModifier)
+			nvidiaModifiers = append(nvidiaModifiers, modeModifier)
 		case "nvidia-hook-remover":
-			modifiers = append(modifiers, modifier.NewNvidiaContainerRuntimeHookRemover(logger))
+			hookRemover, err := modifier.NewNvidiaContainerRuntimeHookRemover(logger, cfg)
+			if err != nil {
+				return nil, err
+			}
+			nvidiaModifiers = append(nvidiaModifiers, hookRemover)
 		case "graphics":
 			graphicsModifier, err := modifier.NewGraphicsModifier(logger, cfg, *image, driver, hookCreator)
 			if err != nil {
 				return nil, err
 			}
-			modifiers = append(modifiers, graphicsModifier)
+			nvidiaModifiers = append(nvidiaModifiers, graphicsModifier)
+		case "profiling":
+			profilingModifier, err := modifier.NewProfilingModifier(logger, *image, driver, hookCreator)
+			if err != nil {
+				return nil, err
+			}
+			nvidiaModifiers = append(nvidiaModifiers, profilingModifier)
 		case "feature-gated":
 			featureGatedModifier, err := modifier.NewFeatureGatedModifier(logger, cfg, *image, driver, hookCreator)
 			if err != nil {
 				return nil, err
 			}
-			modifiers = append(modifiers, featureGatedModifier)
+			nvidiaModifiers = append(nvidiaModifiers, featureGatedModifier)
+		case "additional-device-nodes":
+			additionalDeviceNodesModifier, err := modifier.NewAdditionalDeviceNodesModifier(logger, cfg, *image)
+			if err != nil {
+				return nil, err
+			}
+			nvidiaModifiers = append(nvidiaModifiers, additionalDeviceNodesModifier)
+		}
+	}
+	injectionModifier, err := modifier.NewInjectionStrategyModifier(logger, cfg, hookCreator, nvidiaModifiers)
+	if err != nil {
+		return nil, err
+	}
+	modifiers = append(modifiers, injectionModifier)
+	if mode != info.LegacyRuntimeMode {
+		rawSpec, err := ociSpec.Load()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load OCI spec: %v", err)
+		}
+		modifiers = append(modifiers, edits.NewRootlessModifier(logger, rootless, driver.Root, rawSpec))
+	}
+	if cfg.NVIDIAContainerRuntimeConfig.ResourceConstrained {
+		if len(modifierPlugins.Post) > 0 {
+			modifiers = append(modifiers, modifier.NewPluginModifiers(logger, modifierPlugins.GetTimeout(), modifierPlugins.Post...))
+		}
+		return modifiers, nil
+	}
+	modifiers = append(modifiers, modifier.NewComputeCacheMounter(logger, cfg, *image))
+	modifiers = append(modifiers, modifier.NewGPUDeviceNodeMasker(logger, cfg, *image))
+	modifiers = append(modifiers, modifier.NewMPSSharingModifier(logger, cfg, *image))
+	modifiers = append(modifiers, modifier.NewNamespaceRequirementChecker(logger))
+	// The nvidia-smi wrapper and GPU resets rely on the mounts and device
+	// nodes in the OCI spec and are skipped in the legacy mode.
+	if mode != info.LegacyRuntimeMode {
+		modifiers = append(modifiers, modifier.NewNvidiaSMIWrapper(logger, cfg, hookCreator))
+		gpuResetModifier, err := modifier.NewGPUResetModifier(logger, cfg, hookCreator)
+		if err != nil {
+			return nil, err
 		}
+		modifiers = append(modifiers, gpuResetModifier)
+	}
+	if len(modifierPlugins.Post) > 0 {
+		modifiers = append(modifiers, modifier.NewPluginModifiers(logger, modifierPlugins.GetTimeout(), modifierPlugins.Post...))
 	}
 
 	return modifiers, nil
@@ -130,17 +281,32 @@ func initRuntimeModeAndImage(logger logger.Interface, cfg *config.Config, ociSpe
 		image.WithLogger(logger),
 		image.WithAcceptDeviceListAsVolumeMounts(cfg.AcceptDeviceListAsVolumeMounts),
 		image.WithAcceptEnvvarUnprivileged(cfg.AcceptEnvvarUnprivileged),
+		image.WithVisibleDevicesAll(cfg.VisibleDevicesAll),
 		image.WithAnnotationsPrefixes(cfg.NVIDIAContainerRuntimeConfig.Modes.CDI.AnnotationPrefixes),
 	)
 	if err != nil {
 		return "", nil, err
 	}
 
-	modeResolver := info.NewRuntimeModeResolver(
-		info.WithLogger(logger),
-		info.WithImage(&image),
-	)
-	mode := modeResolver.ResolveRuntimeMode(cfg.NVIDIAContainerRuntimeConfig.Mode)
+	var mode info.RuntimeMode
+	if cfg.NVIDIAContainerRuntimeConfig.ResourceConstrained {
+		mode, err = resolveResourceConstrainedMode(logger, cfg.NVIDIAContainerRuntimeConfig.Mode)
+		if err != nil {
+			return "", nil, err
+		}
+	} else {
+		modeResolver := info.NewRuntimeModeResolver(
+			info.WithLogger(logger),
+			info.WithImage(&image),
+			info.WithPlatform(info.Platform(cfg.NVIDIAContainerRuntimeConfig.Platform)),
+			info.WithEngine(info.DetectEngine(rawSpec)),
+		)
+		mode = modeResolver.ResolveRuntimeMode(cfg.NVIDIAContainerRuntimeConfig.Mode)
+	}
+	if mode == info.LegacyRuntimeMode && !hasNVIDIAContainerCLI(logger, cfg.NVIDIAContainerCLIConfig) {
+		logger.Warningf("nvidia-container-cli not found; falling back from %q to %q mode", mode, info.JitCDIRuntimeMode)
+		mode = info.JitCDIRuntimeMode
+	}
 	// We update the mode here so that we can continue passing just the config to other functions.
 	cfg.NVIDIAContainerRuntimeConfig.Mode = string(mode)
 
@@ -157,16 +323,41 @@ func initRuntimeModeAndImage(logger logger.Interface, cfg *config.Config, ociSpe
 	return initRuntimeModeAndImage(logger, cfg, ociSpec)
 }
 
+// hasNVIDIAContainerCLI checks whether the nvidia-container-cli required by
+// the legacy mode is available. If an explicit path is configured, this is
+// checked instead of searching the PATH.
+func hasNVIDIAContainerCLI(logger logger.Interface, cfg config.ContainerCLIConfig) bool {
+	if cfg.Path != "" {
+		fi, err := os.Stat(cfg.Path)
+		return err == nil && !fi.IsDir()
+	}
+	locator := lookup.NewExecutableLocator(logger, cfg.Root)
+	if _, err := locator.Locate("nvidia-container-cli"); err != nil {
+		logger.Debugf("Failed to locate nvidia-container-cli: %v", err)
+		return false
+	}
+	return true
+}
+
 // supportedModifierTypes returns the modifiers supported for a specific runtime mode.
-func supportedModifierTypes(mode info.RuntimeMode) []string {
+// On resource-constrained systems only the modifiers that are required to
+// inject the requested devices are supported.
+func supportedModifierTypes(mode info.RuntimeMode, resourceConstrained bool) []string {
+	if resourceConstrained {
+		return []string{"nvidia-hook-remover", "mode", "additional-device-nodes"}
+	}
 	switch mode {
-	case info.CDIRuntimeMode, info.JitCDIRuntimeMode:
+	case info.CDIRuntimeMode:
 		// For CDI mode we make no additional modifications.
 		return []string{"nvidia-hook-remover", "mode"}
+	case info.JitCDIRuntimeMode:
+		// For JIT-CDI mode we also inject the profiling libraries since these
+		// are not part of the driver.
+		return []string{"nvidia-hook-remover", "mode", "profiling", "additional-device-nodes"}
 	case info.CSVRuntimeMode:
 		// For CSV mode we support mode and feature-gated modification.
-		return []string{"nvidia-hook-remover", "feature-gated", "mode"}
+		return []string{"nvidia-hook-remover", "feature-gated", "mode", "additional-device-nodes"}
 	default:
-		return []string{"feature-gated", "graphics", "mode"}
+		return []string{"feature-gated", "graphics", "profiling", "mode", "additional-device-nodes"}
 	}
 }
@@ -30,8 +30,8 @@ import (
 
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup/root"
-	"github.com/NVIDIA/nvidia-container-toolkit/internal/oci"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/test"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/oci"
 )
 
 const (
@@ -135,6 +135,21 @@ func TestFactoryMethod(t *testing.T) {
 				},
 			},
 		},
+		{
+			description: "nested containers annotation is not trusted",
+			cfg: &config.Config{
+				NVIDIAContainerRuntimeConfig: config.RuntimeConfig{
+					Runtimes: []string{"runc"},
+					Mode:     "non-legacy",
+				},
+			},
+			spec: &specs.Spec{
+				Annotations: map[string]string{
+					"nvidia.com/nested-containers-prepared": "true",
+				},
+			},
+			expectedError: true,
+		},
 		{
 			description: "empty mode raises error",
 			cfg: &config.Config{
@@ -167,6 +182,61 @@ func TestFactoryMethod(t *testing.T) {
 	}
 }
 
+func TestFactoryMethodChecksRuntimeVersionOnCreate(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+	driver := root.New(
+		root.WithDriverRoot("/nvidia/driver/root"),
+	)
+
+	// The mock runc does not report a version and the version check fails
+	// if it is performed.
+	cfg := &config.Config{
+		NVIDIAContainerRuntimeConfig: config.RuntimeConfig{
+			Runtimes:              []string{"runc"},
+			MinimumRuntimeVersion: "1.0.0",
+			Mode:                  "legacy",
+		},
+	}
+
+	testCases := []struct {
+		description   string
+		subcommand    string
+		expectedError bool
+	}{
+		{
+			description: "version is not checked for state",
+			subcommand:  "state",
+		},
+		{
+			description: "version is not checked for delete",
+			subcommand:  "delete",
+		},
+		{
+			description:   "version is checked for create",
+			subcommand:    "create",
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			bundleDir := t.TempDir()
+			specFile, err := os.Create(filepath.Join(bundleDir, "config.json"))
+			require.NoError(t, err)
+			require.NoError(t, json.NewEncoder(specFile).Encode(&specs.Spec{}))
+
+			argv := []string{"--bundle", bundleDir, tc.subcommand}
+
+			_, err = newNVIDIAContainerRuntime(logger, cfg, argv, driver)
+			if tc.expectedError {
+				require.ErrorIs(t, err, errLowLevelRuntime)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestNewSpecModifier(t *testing.T) {
 	logger, _ := testlog.NewNullLogger()
 	driver := root.New(
@@ -326,6 +396,32 @@ func TestNewSpecModifier(t *testing.T) {
 				},
 			},
 		},
+		{
+			description: "legacy mode without nvidia-container-cli removes nvidia-container-runtime-hook",
+			config: &config.Config{
+				NVIDIAContainerCLIConfig: config.ContainerCLIConfig{
+					Path: "/does/not/exist/nvidia-container-cli",
+				},
+				NVIDIAContainerRuntimeConfig: config.RuntimeConfig{
+					Mode: "legacy",
+				},
+			},
+			spec: &specs.Spec{
+				Hooks: &specs.Hooks{
+					Prestart: []specs.Hook{
+						{
+							Path: "/path/to/nvidia-container-runtime-hook",
+							Args: []string{"/path/to/nvidia-container-runtime-hook", "prestart"},
+						},
+					},
+				},
+			},
+			expectedSpec: &specs.Spec{
+				Hooks: &specs.Hooks{
+					Prestart: nil,
+				},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -335,7 +431,7 @@ func TestNewSpecModifier(t *testing.T) {
 					return tc.spec, nil
 				},
 			}
-			m, err := newSpecModifier(logger, tc.config, spec, driver)
+			m, err := newSpecModifier(logger, tc.config, spec, driver, false)
 			require.NoError(t, err)
 
 			err = m.Modify(tc.spec)
@@ -0,0 +1,105 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package runtime
+
+import (
+	"path/filepath"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/injections"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup/root"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/modifier"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/oci"
+)
+
+// libraryRecorder is a spec modifier that records the driver libraries that
+// are mounted into a container in the configured injected libraries file.
+// Like the injectionRecorder it is expected to be the last modifier that is
+// applied.
+type libraryRecorder struct {
+	logger    logger.Interface
+	store     *injections.Store
+	driver    *root.Driver
+	bundleDir string
+	// existingSources are the sources of the mounts in the incoming spec.
+	// These were not added by the NVIDIA Container Runtime and are not
+	// recorded.
+	existingSources map[string]bool
+}
+
+var _ oci.SpecModifier = (*libraryRecorder)(nil)
+
+// newLibraryRecorder returns a library recorder if an injected libraries
+// file is configured.
+func newLibraryRecorder(logger logger.Interface, cfg *config.Config, driver *root.Driver, bundleDir string, rawSpec *specs.Spec) oci.SpecModifier {
+	if cfg.NVIDIAContainerRuntimeConfig.InjectedLibrariesFilePath == "" {
+		return nil
+	}
+	existingSources := make(map[string]bool)
+	if rawSpec != nil {
+		for _, mount := range rawSpec.Mounts {
+			existingSources[mount.Source] = true
+		}
+	}
+	return &libraryRecorder{
+		logger:          logger,
+		store:           injections.NewStore(cfg.NVIDIAContainerRuntimeConfig.InjectedLibrariesFilePath),
+		driver:          driver,
+		bundleDir:       bundleDir,
+		existingSources: existingSources,
+	}
+}
+
+// Modify records the injected driver libraries. Failures to record the
+// libraries are logged and do not prevent the container from being created.
+func (m *libraryRecorder) Modify(spec *specs.Spec) error {
+	if spec == nil {
+		return nil
+	}
+
+	var libraries []string
+	for _, mount := range spec.Mounts {
+		if m.existingSources[mount.Source] || !modifier.IsLibraryMount(mount) {
+			continue
+		}
+		libraries = append(libraries, mount.Source)
+	}
+	if len(libraries) == 0 {
+		return nil
+	}
+
+	var driverVersion string
+	if m.driver != nil {
+		version, err := m.driver.Version()
+		if err != nil {
+			m.logger.Debugf("Could not determine driver version: %v", err)
+		}
+		driverVersion = version
+	}
+
+	bundleDir, err := filepath.Abs(m.bundleDir)
+	if err != nil {
+		bundleDir = m.bundleDir
+	}
+	if err := m.store.RecordContainer(bundleDir, driverVersion, libraries); err != nil {
+		m.logger.Warningf("Failed to record injected libraries: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package runtime
+
+import (
+	"github.com/opencontainers/runtime-spec/specs-go"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/metrics"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/oci"
+)
+
+// injectionRecorder is a spec modifier that records a successful injection
+// in the configured metrics file. It is expected to be the last modifier
+// applied so that only containers that were modified successfully are
+// counted.
+type injectionRecorder struct {
+	logger logger.Interface
+	store  *metrics.Store
+	// original is the size of the spec before it was modified. This is used
+	// to only count containers that devices, mounts, or hooks were injected
+	// into.
+	original specSize
+}
+
+var _ oci.SpecModifier = (*injectionRecorder)(nil)
+
+// specSize records the number of entries in an OCI spec that are added when
+// GPUs are injected.
+type specSize struct {
+	devices int
+	mounts  int
+	hooks   int
+}
+
+func newInjectionRecorder(logger logger.Interface, cfg *config.Config, rawSpec *specs.Spec) oci.SpecModifier {
+	if cfg.NVIDIAContainerRuntimeConfig.MetricsFilePath == "" {
+		return nil
+	}
+	return &injectionRecorder{
+		logger:   logger,
+		store:    metrics.NewStore(cfg.NVIDIAContainerRuntimeConfig.MetricsFilePath),
+		original: getSpecSize(rawSpec),
+	}
+}
+
+// Modify records the injection if the spec was modified. Failures to record
+// the metric are logged and do not prevent the container from being created.
+func (m *injectionRecorder) Modify(spec *specs.Spec) error {
+	if getSpecSize(spec) == m.original {
+		return nil
+	}
+	if err := m.store.RecordInjection(); err != nil {
+		m.logger.Warningf("Failed to record injection metric: %v", err)
+	}
+	return nil
+}
+
+// recordError records the specified error in the configured metrics file.
+func recordError(logger logger.Interface, cfg *config.Config, err error) {
+	if cfg.NVIDIAContainerRuntimeConfig.MetricsFilePath == "" {
+		return
+	}
+	store := metrics.NewStore(cfg.NVIDIAContainerRuntimeConfig.MetricsFilePath)
+	if recordErr := store.RecordError(string(classifyFailure(err))); recordErr != nil {
+		logger.Warningf("Failed to record error metric: %v", recordErr)
+	}
+}
+
+// getSpecSize returns the number of device nodes, mounts, and hooks in the
+// specified spec.
+func getSpecSize(spec *specs.Spec) specSize {
+	var size specSize
+	if spec == nil {
+		return size
+	}
+	size.mounts = len(spec.Mounts)
+	if spec.Linux != nil {
+		size.devices = len(spec.Linux.Devices)
+	}
+	if spec.Hooks != nil {
+		size.hooks = len(spec.Hooks.Prestart) + //nolint:staticcheck
+			len(spec.Hooks.CreateRuntime) +
+			len(spec.Hooks.CreateContainer) +
+			len(spec.Hooks.StartContainer)
+	}
+	return size
+}
@@ -0,0 +1,139 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package runtime
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/info"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup/root"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/modifier"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/oci"
+)
+
+// containerState describes the container that is being modified. Fields that
+// are not available, for example if the modifications are determined without
+// exec'ing the runtime wrapper, are left empty and the modifiers that require
+// them are skipped.
+type containerState struct {
+	// rawSpec is the OCI spec of the container before it is modified.
+	rawSpec *specs.Spec
+	// bundleDir is the path to the bundle directory of the container.
+	bundleDir string
+	// lowLevelRuntime is the path to the low-level runtime.
+	lowLevelRuntime string
+	// warningRecorder records the warnings raised while modifying the spec.
+	warningRecorder *logger.WarningRecorder
+}
+
+// newModifiers returns the modifiers that are applied to the OCI spec of a
+// container in addition to the specified spec modifier. On
+// resource-constrained systems, modifiers that inspect the GPUs of discrete
+// GPU systems, collect diagnostics, or record metrics are skipped since these
+// add overhead to each container creation. In the legacy mode, modifiers that
+// rely on the device nodes or mounts injected into the OCI spec are skipped.
+func newModifiers(logger logger.Interface, cfg *config.Config, specModifier oci.SpecModifier, driver *root.Driver, state containerState) modifier.List {
+	var originalEnv []string
+	if state.rawSpec != nil && state.rawSpec.Process != nil {
+		originalEnv = slices.Clone(state.rawSpec.Process.Env)
+	}
+
+	modifiers := modifier.List{
+		specModifier,
+		modifier.NewLDCacheHookDisabler(logger, cfg),
+		modifier.NewLDCacheUpdateMerger(logger, state.bundleDir),
+	}
+	if cfg.NVIDIAContainerRuntimeConfig.ResourceConstrained {
+		return append(modifiers,
+			modifier.NewEnvvarScrubber(logger, cfg),
+			modifier.NewOCIVersionCompatModifier(logger),
+			modifier.NewInjectedEnvvarRecorder(logger, originalEnv),
+		)
+	}
+
+	legacy := cfg.NVIDIAContainerRuntimeConfig.Mode == string(info.LegacyRuntimeMode)
+	if legacy {
+		if ignored := getIgnoredLegacyModeSettings(cfg); len(ignored) > 0 {
+			logger.Warningf("Ignoring %v in %q mode", strings.Join(ignored, ", "), info.LegacyRuntimeMode)
+		}
+	} else {
+		modifiers = append(modifiers,
+			modifier.NewGPUProcMasker(logger, cfg),
+			modifier.NewGPUMemoryNUMAModifier(logger, cfg),
+		)
+	}
+	if state.bundleDir != "" {
+		modifiers = append(modifiers, modifier.NewCheckpointDirectoryMounter(logger, cfg, state.bundleDir))
+		if !legacy {
+			modifiers = append(modifiers, modifier.NewDeviceMapWriter(logger, cfg, state.bundleDir))
+		}
+		modifiers = append(modifiers, modifier.NewHookDiagnosticsAnnotator(logger, cfg, state.bundleDir))
+	}
+	if !legacy {
+		modifiers = append(modifiers,
+			modifier.NewInjectionSummarizer(logger, cfg),
+			modifier.NewInjectedDevicesAnnotator(logger, cfg),
+		)
+	}
+	modifiers = append(modifiers, modifier.NewEnvvarScrubber(logger, cfg))
+	if state.bundleDir != "" {
+		modifiers = append(modifiers,
+			modifier.NewNestedContainersModifier(logger, cfg, state.lowLevelRuntime, state.bundleDir),
+			modifier.NewInjectionReportWriter(logger, cfg, state.warningRecorder, state.bundleDir, state.rawSpec),
+		)
+	}
+	modifiers = append(modifiers,
+		modifier.NewOCIVersionCompatModifier(logger),
+		modifier.NewInjectedEnvvarRecorder(logger, originalEnv),
+		newInjectionRecorder(logger, cfg, state.rawSpec),
+	)
+	if state.bundleDir != "" && !legacy {
+		modifiers = append(modifiers, newLibraryRecorder(logger, cfg, driver, state.bundleDir, state.rawSpec))
+	}
+	modifiers = append(modifiers, newTelemetryRecorder(logger, cfg))
+
+	return modifiers
+}
+
+// getIgnoredLegacyModeSettings returns the configured features and options
+// that have no effect in the legacy mode. These rely on the device nodes or
+// mounts in the OCI spec, while in the legacy mode devices and libraries are
+// injected by the nvidia-container-runtime-hook.
+func getIgnoredLegacyModeSettings(cfg *config.Config) []string {
+	var ignored []string
+	for name, enabled := range map[string]bool{
+		"features.annotate-injected-devices":               cfg.Features.AnnotateInjectedDevices.IsEnabled(),
+		"features.enable-egm":                              cfg.Features.EnableEGM.IsEnabled(),
+		"features.mask-unrequested-gpu-proc-entries":       cfg.Features.MaskUnrequestedGPUProcEntries.IsEnabled(),
+		"features.set-injection-summary-envvars":           cfg.Features.SetInjectionSummaryEnvvars.IsEnabled(),
+		"features.wrap-nvidia-smi":                         cfg.Features.WrapNvidiaSMI.IsEnabled(),
+		"features.write-device-map":                        cfg.Features.WriteDeviceMap.IsEnabled(),
+		"nvidia-container-runtime.gpu-reset":               cfg.NVIDIAContainerRuntimeConfig.GPUReset != "",
+		"nvidia-container-runtime.injected-libraries-file": cfg.NVIDIAContainerRuntimeConfig.InjectedLibrariesFilePath != "",
+	} {
+		if enabled {
+			ignored = append(ignored, name)
+		}
+	}
+	slices.Sort(ignored)
+	return ignored
+}
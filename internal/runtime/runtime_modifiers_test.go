@@ -0,0 +1,71 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
+)
+
+func TestGetIgnoredLegacyModeSettings(t *testing.T) {
+	testCases := []struct {
+		description string
+		settings    map[string]interface{}
+		expected    []string
+	}{
+		{
+			description: "no settings",
+		},
+		{
+			description: "settings that apply to all modes are not ignored",
+			settings: map[string]interface{}{
+				"features.write-injection-report": true,
+				"features.enable-egm":             false,
+			},
+		},
+		{
+			description: "settings that rely on the OCI spec are ignored",
+			settings: map[string]interface{}{
+				"features.write-device-map":          true,
+				"features.enable-egm":                true,
+				"nvidia-container-runtime.gpu-reset": "clean",
+			},
+			expected: []string{
+				"features.enable-egm",
+				"features.write-device-map",
+				"nvidia-container-runtime.gpu-reset",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			toml, err := config.New()
+			require.NoError(t, err)
+			for key, value := range tc.settings {
+				toml.Set(key, value)
+			}
+			cfg, err := toml.Config()
+			require.NoError(t, err)
+
+			require.EqualValues(t, tc.expected, getIgnoredLegacyModeSettings(cfg))
+		})
+	}
+}
@@ -0,0 +1,55 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package runtime
+
+import (
+	"fmt"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup/root"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/oci"
+)
+
+// ModifySpec applies the modifications that the NVIDIA Container Runtime
+// would make to the specified OCI spec in place. This allows the
+// modifications to be determined without exec'ing the runtime wrapper.
+//
+// The same modifiers as in the runtime wrapper are applied, except for those
+// that require access to the container bundle or the low-level runtime (e.g.
+// device map files, hook diagnostics, injection reports, recorded libraries,
+// and nested container support). Since the process calling this is not
+// necessarily the container engine, the caller specifies whether the
+// container is created by a rootless container engine instead of this being
+// detected from the user namespace of the current process. Note that the
+// config may be updated to reflect the resolved runtime mode and callers
+// should pass a copy if the config is reused.
+func ModifySpec(logger logger.Interface, cfg *config.Config, spec *specs.Spec, driver *root.Driver, rootless bool) error {
+	if spec == nil {
+		return fmt.Errorf("cannot modify nil spec")
+	}
+
+	ociSpec := oci.NewMemorySpec(spec)
+	specModifier, err := newSpecModifier(logger, cfg, ociSpec, driver, rootless)
+	if err != nil {
+		return fmt.Errorf("failed to construct OCI spec modifier: %w", err)
+	}
+
+	return ociSpec.Modify(newModifiers(logger, cfg, specModifier, driver, containerState{rawSpec: spec}))
+}
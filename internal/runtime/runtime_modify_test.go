@@ -0,0 +1,128 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package runtime
+
+import (
+	"testing"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup/root"
+)
+
+func TestModifySpec(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+	driver := root.New(
+		root.WithDriverRoot("/nvidia/driver/root"),
+	)
+
+	testCases := []struct {
+		description   string
+		cfg           *config.Config
+		spec          *specs.Spec
+		expectedSpec  *specs.Spec
+		expectedError string
+	}{
+		{
+			description: "nil spec raises error",
+			cfg: &config.Config{
+				NVIDIAContainerRuntimeConfig: config.RuntimeConfig{
+					Mode: "cdi",
+				},
+			},
+			expectedError: "cannot modify nil spec",
+		},
+		{
+			description: "invalid mode raises error",
+			cfg: &config.Config{
+				NVIDIAContainerRuntimeConfig: config.RuntimeConfig{
+					Mode: "non-legacy",
+				},
+			},
+			spec:          &specs.Spec{},
+			expectedError: "invalid runtime mode",
+		},
+		{
+			description: "nested containers annotation is not trusted",
+			cfg: &config.Config{
+				NVIDIAContainerRuntimeConfig: config.RuntimeConfig{
+					Mode: "non-legacy",
+				},
+			},
+			spec: &specs.Spec{
+				Annotations: map[string]string{
+					"nvidia.com/nested-containers-prepared": "true",
+				},
+			},
+			expectedError: "invalid runtime mode",
+		},
+		{
+			description: "devices exceeding max devices raises error",
+			cfg: &config.Config{
+				AcceptEnvvarUnprivileged: true,
+				NVIDIAContainerRuntimeConfig: config.RuntimeConfig{
+					Mode:       "cdi",
+					MaxDevices: 1,
+				},
+			},
+			spec: &specs.Spec{
+				Process: &specs.Process{
+					Env: []string{"NVIDIA_VISIBLE_DEVICES=0,1"},
+				},
+			},
+			expectedError: "at most 1 devices are allowed",
+		},
+		{
+			description: "cdi mode removes nvidia-container-runtime-hook",
+			cfg: &config.Config{
+				NVIDIAContainerRuntimeConfig: config.RuntimeConfig{
+					Mode: "cdi",
+				},
+			},
+			spec: &specs.Spec{
+				Hooks: &specs.Hooks{
+					Prestart: []specs.Hook{
+						{
+							Path: "/path/to/nvidia-container-runtime-hook",
+							Args: []string{"/path/to/nvidia-container-runtime-hook", "prestart"},
+						},
+					},
+				},
+			},
+			expectedSpec: &specs.Spec{
+				Hooks: &specs.Hooks{
+					Prestart: nil,
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			err := ModifySpec(logger, tc.cfg, tc.spec, driver, false)
+			if tc.expectedError != "" {
+				require.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.EqualValues(t, tc.expectedSpec, tc.spec)
+		})
+	}
+}
@@ -0,0 +1,97 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package runtime
+
+import (
+	"errors"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/telemetry"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/oci"
+)
+
+// telemetryRecorder is a spec modifier that records the runtime mode used to
+// modify a container in the telemetry file. Like the injectionRecorder it is
+// expected to be the last modifier that is applied.
+type telemetryRecorder struct {
+	logger logger.Interface
+	store  *telemetry.Store
+	mode   string
+}
+
+var _ oci.SpecModifier = (*telemetryRecorder)(nil)
+
+// newTelemetryRecorder returns a telemetry recorder if telemetry is enabled.
+// Note that the mode in the config is expected to have been resolved.
+func newTelemetryRecorder(logger logger.Interface, cfg *config.Config) oci.SpecModifier {
+	if !cfg.Telemetry.Enabled {
+		return nil
+	}
+	return &telemetryRecorder{
+		logger: logger,
+		store:  telemetry.NewStore(getTelemetryFilePath(cfg)),
+		mode:   cfg.NVIDIAContainerRuntimeConfig.Mode,
+	}
+}
+
+// Modify records the invocation. Failures to record the invocation are
+// logged and do not prevent the container from being created.
+func (m *telemetryRecorder) Modify(_ *specs.Spec) error {
+	if err := m.store.RecordInvocation(m.mode); err != nil {
+		m.logger.Debugf("Failed to record telemetry: %v", err)
+	}
+	return nil
+}
+
+// recordTelemetryFailure records the class of the specified error in the
+// telemetry file if telemetry is enabled.
+func recordTelemetryFailure(logger logger.Interface, cfg *config.Config, err error) {
+	if !cfg.Telemetry.Enabled {
+		return
+	}
+	store := telemetry.NewStore(getTelemetryFilePath(cfg))
+	if recordErr := store.RecordFailure(classifyFailure(err)); recordErr != nil {
+		logger.Debugf("Failed to record telemetry: %v", recordErr)
+	}
+}
+
+func getTelemetryFilePath(cfg *config.Config) string {
+	if cfg.Telemetry.FilePath != "" {
+		return cfg.Telemetry.FilePath
+	}
+	return telemetry.DefaultFilePath
+}
+
+// classifyFailure maps an error returned by the NVIDIA Container Runtime to a
+// failure class based on the errors that it wraps.
+func classifyFailure(err error) telemetry.FailureClass {
+	switch {
+	case errors.Is(err, errLowLevelRuntime):
+		return telemetry.FailureLowLevelRuntime
+	case errors.Is(err, errSpecModifier),
+		errors.Is(err, oci.ErrModifySpec):
+		return telemetry.FailureModifier
+	case errors.Is(err, errOCISpec),
+		errors.Is(err, oci.ErrLoadSpec),
+		errors.Is(err, oci.ErrFlushSpec):
+		return telemetry.FailureOCISpec
+	}
+	return telemetry.FailureOther
+}
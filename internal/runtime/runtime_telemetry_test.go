@@ -0,0 +1,68 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/telemetry"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/oci"
+)
+
+func TestClassifyFailure(t *testing.T) {
+	testCases := []struct {
+		description   string
+		err           error
+		expectedClass telemetry.FailureClass
+	}{
+		{
+			description:   "low-level runtime",
+			err:           fmt.Errorf("failed to create NVIDIA Container Runtime: %w", fmt.Errorf("%w: not found", errLowLevelRuntime)),
+			expectedClass: telemetry.FailureLowLevelRuntime,
+		},
+		{
+			description:   "spec modifier construction",
+			err:           fmt.Errorf("%w: requirements not met", errSpecModifier),
+			expectedClass: telemetry.FailureModifier,
+		},
+		{
+			description:   "spec modification",
+			err:           fmt.Errorf("could not apply required modification to OCI specification: %w", fmt.Errorf("%w: failed", oci.ErrModifySpec)),
+			expectedClass: telemetry.FailureModifier,
+		},
+		{
+			description:   "spec flush",
+			err:           fmt.Errorf("%w: read-only file system", oci.ErrFlushSpec),
+			expectedClass: telemetry.FailureOCISpec,
+		},
+		{
+			description:   "message matching is not used",
+			err:           errors.New("error modifying OCI spec"),
+			expectedClass: telemetry.FailureOther,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			require.Equal(t, tc.expectedClass, classifyFailure(tc.err))
+		})
+	}
+}
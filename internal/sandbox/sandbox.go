@@ -0,0 +1,45 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package sandbox
+
+import (
+	"os"
+
+	"github.com/syndtr/gocapability/capability"
+)
+
+// sandboxedEnvvar is set for processes started by Run to indicate that the
+// sandbox has already been applied.
+const sandboxedEnvvar = "__NVCT_SANDBOXED"
+
+// A Profile defines the restrictions that are applied to a sandboxed
+// process.
+type Profile struct {
+	// Capabilities are the capabilities that the sandboxed process retains.
+	// All other capabilities are dropped.
+	Capabilities []capability.Cap
+	// PrivateMounts indicates that the sandboxed process is started in a new
+	// mount namespace with private mount propagation. This must not be set if
+	// the process creates mounts that are required to be visible to other
+	// processes.
+	PrivateMounts bool
+}
+
+// IsSandboxed checks whether the current process was started by Run.
+func IsSandboxed() bool {
+	return os.Getenv(sandboxedEnvvar) == "true"
+}
@@ -0,0 +1,83 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package sandbox
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"syscall"
+
+	"github.com/syndtr/gocapability/capability"
+	"golang.org/x/sys/unix"
+)
+
+// Run runs the current executable with the specified arguments in a sandbox
+// defined by the profile and returns its exit code.
+//
+// The no_new_privs flag is set and the capabilities not included in the
+// profile are dropped from the bounding and inheritable sets of the calling
+// thread before the process is started. Since the process is started from
+// this thread and is executed as a new program, it only retains the
+// capabilities in the profile. Note that the calling thread is not unlocked
+// and is discarded when the calling goroutine exits.
+func Run(profile Profile, args []string) (int, error) {
+	runtime.LockOSThread()
+
+	if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+		return -1, fmt.Errorf("failed to set no_new_privs: %w", err)
+	}
+
+	caps, err := capability.NewPid2(0)
+	if err != nil {
+		return -1, fmt.Errorf("failed to get capabilities: %w", err)
+	}
+	if err := caps.Load(); err != nil {
+		return -1, fmt.Errorf("failed to load capabilities: %w", err)
+	}
+	caps.Clear(capability.BOUNDS | capability.AMBS)
+	caps.Unset(capability.INHERITABLE, capability.List()...)
+	caps.Set(capability.BOUNDING, profile.Capabilities...)
+	if err := caps.Apply(capability.CAPS | capability.BOUNDS | capability.AMBS); err != nil {
+		return -1, fmt.Errorf("failed to drop capabilities: %w", err)
+	}
+
+	cmd := exec.Command("/proc/self/exe", args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), sandboxedEnvvar+"=true")
+	if profile.PrivateMounts {
+		// The go runtime sets the propagation of all mounts in the new mount
+		// namespace to private.
+		cmd.SysProcAttr = &syscall.SysProcAttr{
+			Unshareflags: syscall.CLONE_NEWNS,
+		}
+	}
+
+	err = cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), nil
+	}
+	if err != nil {
+		return -1, fmt.Errorf("failed to run sandboxed process: %w", err)
+	}
+	return 0, nil
+}
@@ -37,6 +37,9 @@ type Interface struct {
 	dryRun bool
 	// devRoot is the root directory where device nodes are expected to exist.
 	devRoot string
+	// helperSocket is the socket of the device node helper used to create
+	// device nodes. If this is empty, device nodes are created directly.
+	helperSocket string
 
 	mknoder
 }
@@ -89,6 +92,10 @@ func (m *Interface) CreateNVIDIADevice(node string) error {
 		return fmt.Errorf("invalid device node %q: %w", node, errInvalidDeviceNode)
 	}
 
+	if m.helperSocket != "" {
+		return m.createNVIDIADeviceWithHelper(node)
+	}
+
 	major, err := m.Major(node)
 	if err != nil {
 		return fmt.Errorf("failed to determine major: %w", err)
@@ -0,0 +1,123 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package nvdevices
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// DefaultHelperSocketPath is the default path of the socket on which the
+// device node helper accepts requests.
+const DefaultHelperSocketPath = "/run/nvidia-container-toolkit/device-node-helper.sock"
+
+const helperTimeout = 10 * time.Second
+
+// helperRequest is sent to the device node helper to request the creation of
+// a device node. Only the name of the device node is included so that the
+// helper determines the path and the major and minor numbers itself.
+type helperRequest struct {
+	Node string `json:"node"`
+}
+
+// helperResponse is returned by the device node helper.
+type helperResponse struct {
+	Error string `json:"error,omitempty"`
+}
+
+// ServeHelper handles device node creation requests on the specified
+// listener until the listener is closed. This allows a privileged process to
+// create device nodes on behalf of unprivileged clients. Only the NVIDIA
+// device nodes supported by CreateNVIDIADevice can be created and these are
+// always created at the devRoot of the serving Interface.
+func (m *Interface) ServeHelper(listener net.Listener) error {
+	var lock sync.Mutex
+	for {
+		conn, err := listener.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to accept connection: %w", err)
+		}
+		go func() {
+			defer conn.Close()
+			m.handleHelperRequest(conn, &lock)
+		}()
+	}
+}
+
+// handleHelperRequest reads a request from the specified connection and
+// creates the requested device node. The request is decoded before the lock
+// is taken so that a client that does not send a request does not block
+// other clients. Device node creation is serialized to avoid concurrent
+// requests for the same device node racing.
+func (m *Interface) handleHelperRequest(conn net.Conn, lock *sync.Mutex) {
+	_ = conn.SetDeadline(time.Now().Add(helperTimeout))
+
+	var response helperResponse
+	var request helperRequest
+	if err := json.NewDecoder(conn).Decode(&request); err != nil {
+		response.Error = fmt.Sprintf("failed to decode request: %v", err)
+	} else if err := m.createNVIDIADeviceLocked(request.Node, lock); err != nil {
+		m.logger.Warningf("Failed to create device node %q: %v", request.Node, err)
+		response.Error = err.Error()
+	}
+
+	if err := json.NewEncoder(conn).Encode(&response); err != nil {
+		m.logger.Warningf("Failed to send response: %v", err)
+	}
+}
+
+func (m *Interface) createNVIDIADeviceLocked(node string, lock *sync.Mutex) error {
+	lock.Lock()
+	defer lock.Unlock()
+	return m.CreateNVIDIADevice(node)
+}
+
+// createNVIDIADeviceWithHelper requests the creation of the specified device
+// node from the device node helper listening on the configured socket.
+func (m *Interface) createNVIDIADeviceWithHelper(node string) error {
+	if m.dryRun {
+		m.logger.Infof("Requesting creation of %s from helper at %s", node, m.helperSocket)
+		return nil
+	}
+
+	conn, err := net.DialTimeout("unix", m.helperSocket, helperTimeout)
+	if err != nil {
+		return fmt.Errorf("failed to connect to device node helper: %w", err)
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(helperTimeout))
+
+	if err := json.NewEncoder(conn).Encode(&helperRequest{Node: node}); err != nil {
+		return fmt.Errorf("failed to send request to device node helper: %w", err)
+	}
+
+	var response helperResponse
+	if err := json.NewDecoder(conn).Decode(&response); err != nil {
+		return fmt.Errorf("failed to read response from device node helper: %w", err)
+	}
+	if response.Error != "" {
+		return fmt.Errorf("device node helper failed to create %s: %s", node, response.Error)
+	}
+	return nil
+}
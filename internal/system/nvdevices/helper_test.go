@@ -0,0 +1,96 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package nvdevices
+
+import (
+	"net"
+	"path/filepath"
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/info/proc/devices"
+)
+
+func TestHelper(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	nvidiaDevices := devices.New(
+		devices.WithDeviceToMajor(map[string]int{
+			"nvidia":     195,
+			"nvidia-uvm": 243,
+		}),
+	)
+
+	mknode := &mknoderMock{
+		MknodeFunc: func(string, int, int) error {
+			return nil
+		},
+	}
+	server, err := New(
+		WithLogger(logger),
+		WithDevRoot("/some/root"),
+		WithDevices(nvidiaDevices),
+	)
+	require.NoError(t, err)
+	server.mknoder = mknode
+
+	socket := filepath.Join(t.TempDir(), "helper.sock")
+	listener, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+
+	serveErr := make(chan error)
+	go func() {
+		serveErr <- server.ServeHelper(listener)
+	}()
+
+	// A client that does not send a request must not block other clients.
+	idle, err := net.Dial("unix", socket)
+	require.NoError(t, err)
+	defer idle.Close()
+
+	client, err := New(
+		WithLogger(logger),
+		WithDevRoot("/ignored/root"),
+		WithDevices(nvidiaDevices),
+		WithHelperSocket(socket),
+	)
+	require.NoError(t, err)
+	client.mknoder = &mknoderMock{}
+
+	require.NoError(t, client.CreateNVIDIAControlDevices())
+	require.ErrorContains(t, client.CreateNVIDIADevice("nvidia-unknown"), "invalid device node")
+	require.ErrorIs(t, client.CreateNVIDIADevice("not-nvidia"), errInvalidDeviceNode)
+
+	require.EqualValues(t,
+		[]struct {
+			S  string
+			N1 int
+			N2 int
+		}{
+			{"/some/root/dev/nvidiactl", 195, 255},
+			{"/some/root/dev/nvidia-modeset", 195, 254},
+			{"/some/root/dev/nvidia-uvm", 243, 0},
+			{"/some/root/dev/nvidia-uvm-tools", 243, 1},
+		},
+		mknode.MknodeCalls(),
+	)
+
+	require.NoError(t, listener.Close())
+	require.NoError(t, <-serveErr)
+}
@@ -45,6 +45,16 @@ func WithDevRoot(devRoot string) Option {
 	}
 }
 
+// WithHelperSocket configures the Interface to request the creation of
+// device nodes from the device node helper listening on the specified socket
+// instead of creating them directly. This allows device nodes to be created
+// without the caller requiring the privileges to do so.
+func WithHelperSocket(socketPath string) Option {
+	return func(i *Interface) {
+		i.helperSocket = socketPath
+	}
+}
+
 // WithDevices sets the devices for the Interface struct.
 func WithDevices(devices devices.Devices) Option {
 	return func(i *Interface) {
@@ -17,7 +17,10 @@
 package nvmodules
 
 import (
+	"bufio"
 	"fmt"
+	"io"
+	"os"
 	"strings"
 
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
@@ -30,6 +33,8 @@ type Interface struct {
 	dryRun bool
 	root   string
 
+	procModulesPath string
+
 	cmder
 }
 
@@ -46,6 +51,9 @@ func New(opts ...Option) *Interface {
 	if m.root == "" {
 		m.root = "/"
 	}
+	if m.procModulesPath == "" {
+		m.procModulesPath = "/proc/modules"
+	}
 
 	if m.dryRun {
 		m.cmder = &cmderLogger{m.logger}
@@ -91,3 +99,46 @@ func (m *Interface) Load(module string) error {
 
 	return nil
 }
+
+// Missing returns the subset of the specified kernel modules that are not
+// currently loaded. Module names are compared as they appear in /proc/modules
+// where hyphens are replaced by underscores.
+func (m *Interface) Missing(modules ...string) ([]string, error) {
+	f, err := os.Open(m.procModulesPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %v: %w", m.procModulesPath, err)
+	}
+	defer f.Close()
+
+	loaded, err := parseProcModules(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %v: %w", m.procModulesPath, err)
+	}
+
+	var missing []string
+	for _, module := range modules {
+		if loaded[strings.ReplaceAll(module, "-", "_")] {
+			continue
+		}
+		missing = append(missing, module)
+	}
+	return missing, nil
+}
+
+// parseProcModules returns the set of module names from the contents of
+// /proc/modules. The first field of each line is the module name.
+func parseProcModules(r io.Reader) (map[string]bool, error) {
+	loaded := make(map[string]bool)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		loaded[fields[0]] = true
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return loaded, nil
+}
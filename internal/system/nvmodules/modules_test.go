@@ -18,6 +18,8 @@ package nvmodules
 
 import (
 	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 
 	testlog "github.com/sirupsen/logrus/hooks/test"
@@ -176,3 +178,50 @@ func TestLoad(t *testing.T) {
 		})
 	}
 }
+
+func TestMissing(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	testCases := []struct {
+		description     string
+		procModules     string
+		modules         []string
+		expectedMissing []string
+	}{
+		{
+			description: "all modules loaded",
+			procModules: `nvidia_uvm 1904640 0 - Live 0x0000000000000000 (POE)
+nvidia 62984192 1 nvidia_uvm, Live 0x0000000000000000 (POE)
+`,
+			modules: []string{"nvidia", "nvidia-uvm"},
+		},
+		{
+			description:     "uvm module not loaded",
+			procModules:     "nvidia 62984192 1 - Live 0x0000000000000000 (POE)\n",
+			modules:         []string{"nvidia", "nvidia-uvm"},
+			expectedMissing: []string{"nvidia-uvm"},
+		},
+		{
+			description:     "no modules loaded",
+			procModules:     "",
+			modules:         []string{"nvidia", "nvidia_uvm"},
+			expectedMissing: []string{"nvidia", "nvidia_uvm"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			procModulesPath := filepath.Join(t.TempDir(), "modules")
+			require.NoError(t, os.WriteFile(procModulesPath, []byte(tc.procModules), 0644))
+
+			m := New(
+				WithLogger(logger),
+			)
+			m.procModulesPath = procModulesPath
+
+			missing, err := m.Missing(tc.modules...)
+			require.NoError(t, err)
+			require.EqualValues(t, tc.expectedMissing, missing)
+		})
+	}
+}
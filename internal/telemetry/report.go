@@ -0,0 +1,61 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// A Report is the payload that is sent to the configured telemetry endpoint.
+// It contains only aggregate counts and version information.
+type Report struct {
+	// ToolkitVersion is the version of the NVIDIA Container Toolkit.
+	ToolkitVersion string `json:"toolkitVersion"`
+	// DriverVersion is the version of the NVIDIA driver on the host, if
+	// this could be determined.
+	DriverVersion string `json:"driverVersion,omitempty"`
+	Counts
+}
+
+// Send posts the specified report to the endpoint as JSON.
+func Send(ctx context.Context, client *http.Client, endpoint string, report *Report) error {
+	payload, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to encode report: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send report: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response from %v: %v", endpoint, resp.Status)
+	}
+	return nil
+}
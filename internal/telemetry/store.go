@@ -0,0 +1,134 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package telemetry
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lockedfile"
+)
+
+// DefaultFilePath is the default path of the file used to aggregate the
+// usage statistics recorded by the NVIDIA Container Runtime.
+const DefaultFilePath = "/var/lib/nvidia-container-toolkit/telemetry.json"
+
+// A FailureClass is a coarse classification of a failed invocation of the
+// NVIDIA Container Runtime. Error messages are not recorded since these may
+// contain identifying information such as paths or container IDs.
+type FailureClass string
+
+const (
+	// FailureLowLevelRuntime indicates that the low-level runtime could not
+	// be located or constructed.
+	FailureLowLevelRuntime = FailureClass("low-level-runtime")
+	// FailureOCISpec indicates that the OCI runtime specification could not
+	// be loaded or written.
+	FailureOCISpec = FailureClass("oci-spec")
+	// FailureModifier indicates that the modifications required for the
+	// requested devices could not be constructed or applied.
+	FailureModifier = FailureClass("modifier")
+	// FailureOther is used for all other failures.
+	FailureOther = FailureClass("other")
+)
+
+// Counts represents the aggregate usage statistics.
+type Counts struct {
+	// Modes counts the invocations per (resolved) runtime mode.
+	Modes map[string]uint64 `json:"modes,omitempty"`
+	// Failures counts the failed invocations per failure class.
+	Failures map[FailureClass]uint64 `json:"failures,omitempty"`
+}
+
+// A Store aggregates the usage statistics recorded by the NVIDIA Container
+// Runtime in a file.
+type Store struct {
+	file *lockedfile.JSONFile[Counts]
+}
+
+// NewStore creates a store for the specified file.
+func NewStore(path string) *Store {
+	return &Store{
+		file: lockedfile.NewJSONFile[Counts](path, "telemetry"),
+	}
+}
+
+// Load returns the aggregated counts. If the file does not exist, empty counts
+// are returned.
+func (s *Store) Load() (*Counts, error) {
+	return s.file.Load()
+}
+
+// RecordInvocation increments the count for the specified mode.
+func (s *Store) RecordInvocation(mode string) error {
+	return s.file.Update(func(counts *Counts) error {
+		counts.add(&Counts{Modes: map[string]uint64{mode: 1}})
+		return nil
+	})
+}
+
+// RecordFailure increments the count for the specified failure class.
+func (s *Store) RecordFailure(class FailureClass) error {
+	return s.file.Update(func(counts *Counts) error {
+		counts.add(&Counts{Failures: map[FailureClass]uint64{class: 1}})
+		return nil
+	})
+}
+
+// Flush calls the specified function with the aggregated counts and resets
+// the counts. The counts are reset before the function is called so that the
+// lock on the file is not held while the counts are sent. If the function
+// fails, the counts are added back so that no counts are lost.
+func (s *Store) Flush(flushFn func(*Counts) error) error {
+	var snapshot Counts
+	err := s.file.Update(func(counts *Counts) error {
+		snapshot = *counts
+		*counts = Counts{}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := flushFn(&snapshot); err != nil {
+		restoreErr := s.file.Update(func(counts *Counts) error {
+			counts.add(&snapshot)
+			return nil
+		})
+		if restoreErr != nil {
+			return errors.Join(err, fmt.Errorf("failed to restore telemetry: %w", restoreErr))
+		}
+		return err
+	}
+	return nil
+}
+
+// add adds the specified counts to the counts.
+func (c *Counts) add(other *Counts) {
+	for mode, count := range other.Modes {
+		if c.Modes == nil {
+			c.Modes = make(map[string]uint64)
+		}
+		c.Modes[mode] += count
+	}
+	for class, count := range other.Failures {
+		if c.Failures == nil {
+			c.Failures = make(map[FailureClass]uint64)
+		}
+		c.Failures[class] += count
+	}
+}
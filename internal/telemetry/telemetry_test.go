@@ -0,0 +1,98 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "telemetry", "telemetry.json"))
+
+	counts, err := store.Load()
+	require.NoError(t, err)
+	require.EqualValues(t, &Counts{}, counts)
+
+	require.NoError(t, store.RecordInvocation("cdi"))
+	require.NoError(t, store.RecordInvocation("cdi"))
+	require.NoError(t, store.RecordInvocation("legacy"))
+	require.NoError(t, store.RecordFailure(FailureModifier))
+
+	expected := &Counts{
+		Modes: map[string]uint64{
+			"cdi":    2,
+			"legacy": 1,
+		},
+		Failures: map[FailureClass]uint64{
+			FailureModifier: 1,
+		},
+	}
+	counts, err = store.Load()
+	require.NoError(t, err)
+	require.EqualValues(t, expected, counts)
+
+	// A failed flush retains the counts.
+	err = store.Flush(func(c *Counts) error {
+		require.EqualValues(t, expected, c)
+		return errors.New("failed")
+	})
+	require.Error(t, err)
+	counts, err = store.Load()
+	require.NoError(t, err)
+	require.EqualValues(t, expected, counts)
+
+	// A successful flush resets the counts.
+	require.NoError(t, store.Flush(func(*Counts) error { return nil }))
+	counts, err = store.Load()
+	require.NoError(t, err)
+	require.EqualValues(t, &Counts{}, counts)
+}
+
+func TestSend(t *testing.T) {
+	var received Report
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	report := &Report{
+		ToolkitVersion: "1.18.0",
+		DriverVersion:  "570.124.06",
+		Counts: Counts{
+			Modes: map[string]uint64{"jit-cdi": 3},
+		},
+	}
+	require.NoError(t, Send(context.Background(), server.Client(), server.URL, report))
+	require.EqualValues(t, *report, received)
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+	require.Error(t, Send(context.Background(), failing.Client(), failing.URL, report))
+}
@@ -0,0 +1,64 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package units
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// sizeUnits maps the supported (binary) unit suffixes to their multipliers.
+var sizeUnits = map[byte]uint64{
+	'k': 1 << 10,
+	'm': 1 << 20,
+	'g': 1 << 30,
+	't': 1 << 40,
+}
+
+// ParseSize parses a size string such as 24g, 512MiB, or 1024 to a number of
+// bytes. Unit suffixes are case insensitive and always refer to binary units.
+func ParseSize(value string) (uint64, error) {
+	s := strings.ToLower(strings.TrimSpace(value))
+	s = strings.TrimSuffix(strings.TrimSuffix(s, "b"), "i")
+
+	multiplier := uint64(1)
+	if len(s) > 0 {
+		if m, ok := sizeUnits[s[len(s)-1]]; ok {
+			multiplier = m
+			s = s[:len(s)-1]
+		}
+	}
+
+	size, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q; expected an integer with an optional unit such as 24g", value)
+	}
+	return size * multiplier, nil
+}
+
+// FormatSize returns the string representation of the specified number of
+// bytes using the largest unit that represents the value exactly.
+func FormatSize(size uint64) string {
+	for _, unit := range []byte{'t', 'g', 'm', 'k'} {
+		m := sizeUnits[unit]
+		if size >= m && size%m == 0 {
+			return fmt.Sprintf("%d%c", size/m, unit)
+		}
+	}
+	return strconv.FormatUint(size, 10)
+}
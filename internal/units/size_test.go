@@ -0,0 +1,56 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package units
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSize(t *testing.T) {
+	testCases := []struct {
+		value         string
+		expected      uint64
+		expectedError bool
+	}{
+		{value: "1024", expected: 1024},
+		{value: "24g", expected: 24 << 30},
+		{value: "512MiB", expected: 512 << 20},
+		{value: "1T", expected: 1 << 40},
+		{value: "lots", expectedError: true},
+		{value: "", expectedError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			size, err := ParseSize(tc.value)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, size)
+		})
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	require.Equal(t, "24g", FormatSize(24<<30))
+	require.Equal(t, "81559m", FormatSize(81559<<20))
+	require.Equal(t, "1000", FormatSize(1000))
+}
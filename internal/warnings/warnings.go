@@ -0,0 +1,80 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package warnings
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+)
+
+// A Kind categorizes a warning.
+type Kind string
+
+const (
+	// DeprecatedConfig indicates that a config option that is no longer
+	// supported is set.
+	DeprecatedConfig = Kind("deprecated-config")
+	// LegacyEnvvar indicates that a container relies on a legacy environment
+	// variable convention.
+	LegacyEnvvar = Kind("legacy-envvar")
+)
+
+// A Warning describes a deprecation together with the action that a user can
+// take to address it.
+type Warning struct {
+	// ID uniquely identifies the warning.
+	ID string
+	// Kind categorizes the warning.
+	Kind Kind
+	// Message describes the deprecation or change.
+	Message string
+	// Remediation describes how the warning can be addressed.
+	Remediation string
+}
+
+// String returns the single-line representation of the warning.
+func (w Warning) String() string {
+	s := fmt.Sprintf("%v (%v): %v", w.Kind, w.ID, w.Message)
+	if w.Remediation != "" {
+		s += "; " + w.Remediation
+	}
+	return s
+}
+
+var emitted = struct {
+	sync.Mutex
+	ids map[string]bool
+}{
+	ids: make(map[string]bool),
+}
+
+// Emit logs the specified warnings. Each warning is logged at most once per
+// process, even if it is emitted multiple times.
+func Emit(logger logger.Interface, warnings ...Warning) {
+	emitted.Lock()
+	defer emitted.Unlock()
+
+	for _, w := range warnings {
+		if emitted.ids[w.ID] {
+			continue
+		}
+		emitted.ids[w.ID] = true
+		logger.Warningf("%v", w)
+	}
+}
@@ -0,0 +1,41 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package warnings
+
+import (
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmit(t *testing.T) {
+	logger, hook := testlog.NewNullLogger()
+
+	w := Warning{
+		ID:          "test-emit",
+		Kind:        DeprecatedConfig,
+		Message:     "the foo config option is no longer supported",
+		Remediation: "remove the option",
+	}
+
+	Emit(logger, w)
+	Emit(logger, w)
+
+	require.Len(t, hook.AllEntries(), 1)
+	require.Equal(t, "deprecated-config (test-emit): the foo config option is no longer supported; remove the option", hook.LastEntry().Message)
+}
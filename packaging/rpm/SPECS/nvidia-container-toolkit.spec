@@ -20,6 +20,8 @@ Source6: nvidia-cdi-hook
 Source7: nvidia-cdi-refresh.service
 Source8: nvidia-cdi-refresh.path
 Source9: nvidia-cdi-refresh.env
+Source10: nvidia-device-node-helper.service
+Source11: nvidia-device-node-helper.socket
 
 Obsoletes: nvidia-container-runtime <= 3.5.0-1, nvidia-container-runtime-hook <= 1.4.0-2
 Provides: nvidia-container-runtime
@@ -31,7 +33,7 @@ Requires: nvidia-container-toolkit-base == %{version}-%{release}
 Provides tools and utilities to enable GPU support in containers.
 
 %prep
-cp %{SOURCE0} %{SOURCE1} %{SOURCE2} %{SOURCE3} %{SOURCE4} %{SOURCE5} %{SOURCE6} %{SOURCE7} %{SOURCE8} %{SOURCE9} .
+cp %{SOURCE0} %{SOURCE1} %{SOURCE2} %{SOURCE3} %{SOURCE4} %{SOURCE5} %{SOURCE6} %{SOURCE7} %{SOURCE8} %{SOURCE9} %{SOURCE10} %{SOURCE11} .
 
 %install
 mkdir -p %{buildroot}%{_bindir}
@@ -46,8 +48,14 @@ install -m 755 -t %{buildroot}%{_bindir} nvidia-ctk
 install -m 755 -t %{buildroot}%{_bindir} nvidia-cdi-hook
 install -m 644 -t %{buildroot}%{_sysconfdir}/systemd/system nvidia-cdi-refresh.service
 install -m 644 -t %{buildroot}%{_sysconfdir}/systemd/system nvidia-cdi-refresh.path
+install -m 644 -t %{buildroot}%{_sysconfdir}/systemd/system nvidia-device-node-helper.service
+install -m 644 -t %{buildroot}%{_sysconfdir}/systemd/system nvidia-device-node-helper.socket
 install -m 644 -t %{buildroot}%{_sysconfdir}/nvidia-container-toolkit nvidia-cdi-refresh.env
 
+# A symlink at a well-known path is used for the nvidia-cdi-hook if stable hook paths are enabled.
+mkdir -p %{buildroot}%{_prefix}/libexec/nvidia-container-toolkit
+ln -s %{_bindir}/nvidia-cdi-hook %{buildroot}%{_prefix}/libexec/nvidia-container-toolkit/nvidia-cdi-hook
+
 %post
 if [ $1 -gt 1 ]; then  # only on package upgrade
   mkdir -p %{_localstatedir}/lib/rpm-state/nvidia-container-toolkit
@@ -111,8 +119,12 @@ fi
 %{_bindir}/nvidia-container-runtime
 %{_bindir}/nvidia-ctk
 %{_bindir}/nvidia-cdi-hook
+%dir %{_prefix}/libexec/nvidia-container-toolkit
+%{_prefix}/libexec/nvidia-container-toolkit/nvidia-cdi-hook
 %{_sysconfdir}/systemd/system/nvidia-cdi-refresh.service
 %{_sysconfdir}/systemd/system/nvidia-cdi-refresh.path
+%{_sysconfdir}/systemd/system/nvidia-device-node-helper.service
+%{_sysconfdir}/systemd/system/nvidia-device-node-helper.socket
 %config(noreplace) %{_sysconfdir}/nvidia-container-toolkit/nvidia-cdi-refresh.env
 
 # The OPERATOR EXTENSIONS package consists of components that are required to enable GPU support in Kubernetes.
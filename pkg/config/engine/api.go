@@ -21,6 +21,7 @@ type Interface interface {
 	AddRuntime(string, string, bool) error
 	DefaultRuntime() string
 	EnableCDI()
+	SetCDISpecDirs(...string)
 	GetRuntimeConfig(string) (RuntimeConfig, error)
 	RemoveRuntime(string) error
 	Save(string) (int64, error)
@@ -0,0 +1,158 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package buildkit
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/config/engine"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/config/toml"
+)
+
+// Config represents the buildkitd config.
+// The OCI worker of buildkitd uses a single low-level runtime binary instead
+// of a set of named runtimes.
+type Config struct {
+	*toml.Tree
+	Logger logger.Interface
+}
+
+type buildkitRuntime struct {
+	binaryPath string
+}
+
+var _ engine.RuntimeConfig = (*buildkitRuntime)(nil)
+
+// GetBinaryPath retrieves the path to the low-level runtime binary of the OCI
+// worker. If no path is available, the empty string is returned.
+func (c *buildkitRuntime) GetBinaryPath() string {
+	return c.binaryPath
+}
+
+var _ engine.Interface = (*Config)(nil)
+
+var ociWorkerBinaryPath = []string{"worker", "oci", "binary"}
+
+// New creates a buildkitd config with the specified options
+func New(opts ...Option) (engine.Interface, error) {
+	b := &builder{}
+	for _, opt := range opts {
+		opt(b)
+	}
+	if b.logger == nil {
+		b.logger = logger.New()
+	}
+	if b.configSource == nil {
+		b.configSource = toml.FromFile(b.path)
+	}
+
+	tomlConfig, err := b.configSource.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := Config{
+		Tree:   tomlConfig,
+		Logger: b.logger,
+	}
+	return &cfg, nil
+}
+
+// AddRuntime sets the binary of the buildkitd OCI worker to the specified
+// path. Since the OCI worker only supports a single runtime, the runtime name
+// is ignored and the runtime can only be added as the default.
+func (c *Config) AddRuntime(name string, path string, setAsDefault bool) error {
+	if c == nil {
+		return fmt.Errorf("config is nil")
+	}
+	if !setAsDefault {
+		return fmt.Errorf("the buildkitd OCI worker only supports a single runtime; %v must be set as the default", name)
+	}
+
+	config := *c.Tree
+	config.SetPath(ociWorkerBinaryPath, path)
+	*c.Tree = config
+	return nil
+}
+
+// DefaultRuntime returns the empty string since the buildkitd OCI worker
+// does not support named runtimes.
+func (c *Config) DefaultRuntime() string {
+	return ""
+}
+
+// RemoveRuntime removes the binary of the OCI worker from the buildkitd config
+// if it refers to an NVIDIA runtime.
+func (c *Config) RemoveRuntime(name string) error {
+	if c == nil || c.Tree == nil {
+		return nil
+	}
+
+	config := *c.Tree
+	binaryPath, ok := config.GetPath(ociWorkerBinaryPath).(string)
+	if !ok || !strings.HasPrefix(filepath.Base(binaryPath), "nvidia") {
+		return nil
+	}
+	config.DeletePath(ociWorkerBinaryPath)
+	for i := 1; i < len(ociWorkerBinaryPath); i++ {
+		remainingPath := ociWorkerBinaryPath[:len(ociWorkerBinaryPath)-i]
+		if entry, ok := config.GetPath(remainingPath).(*toml.Tree); ok {
+			if len(entry.Keys()) != 0 {
+				break
+			}
+			config.DeletePath(remainingPath)
+		}
+	}
+
+	*c.Tree = config
+	return nil
+}
+
+// GetRuntimeConfig returns the runtime config of the OCI worker. The runtime
+// name is ignored.
+func (c *Config) GetRuntimeConfig(name string) (engine.RuntimeConfig, error) {
+	if c == nil || c.Tree == nil {
+		return nil, fmt.Errorf("config is nil")
+	}
+	binaryPath, _ := c.GetPath(ociWorkerBinaryPath).(string)
+	return &buildkitRuntime{
+		binaryPath: binaryPath,
+	}, nil
+}
+
+// EnableCDI enables CDI device injection in buildkitd.
+// This allows devices to be requested using RUN --device in Dockerfiles.
+func (c *Config) EnableCDI() {
+	config := *c.Tree
+	config.SetPath([]string{"cdi", "disabled"}, false)
+	*c.Tree = config
+}
+
+// SetCDISpecDirs sets the specDirs field in the buildkitd config.
+// If no directories are specified, the buildkitd defaults are used.
+func (c *Config) SetCDISpecDirs(dirs ...string) {
+	config := *c.Tree
+	if len(dirs) == 0 {
+		config.DeletePath([]string{"cdi", "specDirs"})
+	} else {
+		config.SetPath([]string{"cdi", "specDirs"}, dirs)
+	}
+	*c.Tree = config
+}
@@ -0,0 +1,171 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package buildkit
+
+import (
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/config/toml"
+)
+
+func TestAddRuntime(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+	testCases := []struct {
+		description    string
+		config         string
+		expectedConfig string
+	}{
+		{
+			description: "empty config",
+			expectedConfig: `
+			[worker.oci]
+			binary = "/usr/bin/test"
+			`,
+		},
+		{
+			description: "existing binary is replaced",
+			config: `
+			[worker.oci]
+			enabled = true
+			binary = "/usr/bin/runc"
+			`,
+			expectedConfig: `
+			[worker.oci]
+			enabled = true
+			binary = "/usr/bin/test"
+			`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			cfg, err := toml.Load(tc.config)
+			require.NoError(t, err)
+			expectedConfig, err := toml.Load(tc.expectedConfig)
+			require.NoError(t, err)
+
+			c := &Config{
+				Logger: logger,
+				Tree:   cfg,
+			}
+
+			err = c.AddRuntime("test", "/usr/bin/test", true)
+			require.NoError(t, err)
+
+			require.EqualValues(t, expectedConfig.String(), cfg.String())
+		})
+	}
+}
+
+func TestAddRuntimeRequiresDefault(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+	cfg, err := toml.Load(`
+	[worker.oci]
+	binary = "/usr/bin/crun"
+	`)
+	require.NoError(t, err)
+
+	c := &Config{
+		Logger: logger,
+		Tree:   cfg,
+	}
+
+	require.Error(t, c.AddRuntime("test", "/usr/bin/test", false))
+	require.Equal(t, "/usr/bin/crun", cfg.GetPath([]string{"worker", "oci", "binary"}))
+}
+
+func TestRemoveRuntime(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+	testCases := []struct {
+		description    string
+		config         string
+		expectedConfig string
+	}{
+		{
+			description: "nvidia runtime is removed",
+			config: `
+			[worker.oci]
+			binary = "/usr/bin/nvidia-container-runtime"
+			`,
+			expectedConfig: ``,
+		},
+		{
+			description: "other worker options are kept",
+			config: `
+			[worker.oci]
+			enabled = true
+			binary = "/usr/bin/nvidia-container-runtime"
+			`,
+			expectedConfig: `
+			[worker.oci]
+			enabled = true
+			`,
+		},
+		{
+			description: "other runtime is not removed",
+			config: `
+			[worker.oci]
+			binary = "/usr/bin/crun"
+			`,
+			expectedConfig: `
+			[worker.oci]
+			binary = "/usr/bin/crun"
+			`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			cfg, err := toml.Load(tc.config)
+			require.NoError(t, err)
+			expectedConfig, err := toml.Load(tc.expectedConfig)
+			require.NoError(t, err)
+
+			c := &Config{
+				Logger: logger,
+				Tree:   cfg,
+			}
+
+			err = c.RemoveRuntime("nvidia")
+			require.NoError(t, err)
+
+			require.EqualValues(t, expectedConfig.String(), cfg.String())
+		})
+	}
+}
+
+func TestEnableCDI(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+	cfg, err := toml.Load(`
+	[cdi]
+	disabled = true
+	`)
+	require.NoError(t, err)
+
+	c := &Config{
+		Logger: logger,
+		Tree:   cfg,
+	}
+	c.EnableCDI()
+	c.SetCDISpecDirs("/etc/cdi", "/var/run/cdi")
+
+	require.Equal(t, false, cfg.GetPath([]string{"cdi", "disabled"}))
+	require.EqualValues(t, []string{"/etc/cdi", "/var/run/cdi"}, cfg.GetPath([]string{"cdi", "specDirs"}))
+}
@@ -111,6 +111,18 @@ func (c *Config) EnableCDI() {
 	*c.Tree = config
 }
 
+// SetCDISpecDirs sets the cdi_spec_dirs field in the Containerd config.
+// If no directories are specified, the containerd defaults are used.
+func (c *Config) SetCDISpecDirs(dirs ...string) {
+	config := *c.Tree
+	if len(dirs) == 0 {
+		config.DeletePath([]string{"plugins", c.CRIRuntimePluginName, "cdi_spec_dirs"})
+	} else {
+		config.SetPath([]string{"plugins", c.CRIRuntimePluginName, "cdi_spec_dirs"}, dirs)
+	}
+	*c.Tree = config
+}
+
 // RemoveRuntime removes a runtime from the docker config
 func (c *Config) RemoveRuntime(name string) error {
 	if c == nil || c.Tree == nil {
@@ -164,3 +164,15 @@ func (c *ConfigV1) EnableCDI() {
 	config.SetPath([]string{"plugins", "cri", "containerd", "enable_cdi"}, true)
 	*c.Tree = config
 }
+
+// SetCDISpecDirs sets the cdi_spec_dirs field in the Containerd config.
+// If no directories are specified, the containerd defaults are used.
+func (c *ConfigV1) SetCDISpecDirs(dirs ...string) {
+	config := *c.Tree
+	if len(dirs) == 0 {
+		config.DeletePath([]string{"plugins", "cri", "containerd", "cdi_spec_dirs"})
+	} else {
+		config.SetPath([]string{"plugins", "cri", "containerd", "cdi_spec_dirs"}, dirs)
+	}
+	*c.Tree = config
+}
@@ -153,8 +153,24 @@ func (c *Config) GetRuntimeConfig(name string) (engine.RuntimeConfig, error) {
 	}, nil
 }
 
-// EnableCDI is a no-op for CRI-O since it always enabled where supported.
-func (c *Config) EnableCDI() {}
+// EnableCDI sets the enable_cdi field in the CRI-O config to true.
+func (c *Config) EnableCDI() {
+	config := *c.Tree
+	config.SetPath([]string{"crio", "runtime", "enable_cdi"}, true)
+	*c.Tree = config
+}
+
+// SetCDISpecDirs sets the cdi_spec_dirs field in the CRI-O config.
+// If no directories are specified, the CRI-O defaults are used.
+func (c *Config) SetCDISpecDirs(dirs ...string) {
+	config := *c.Tree
+	if len(dirs) == 0 {
+		config.DeletePath([]string{"crio", "runtime", "cdi_spec_dirs"})
+	} else {
+		config.SetPath([]string{"crio", "runtime", "cdi_spec_dirs"}, dirs)
+	}
+	*c.Tree = config
+}
 
 // CommandLineSource returns the CLI-based crio config loader
 func CommandLineSource(hostRoot string, executablePath string) toml.Loader {
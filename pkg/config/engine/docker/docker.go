@@ -121,6 +121,21 @@ func (c *Config) EnableCDI() {
 	*c = config
 }
 
+// SetCDISpecDirs sets cdi-spec-dirs in the docker config.
+// If no directories are specified, the docker defaults are used.
+func (c *Config) SetCDISpecDirs(dirs ...string) {
+	if c == nil {
+		return
+	}
+	config := *c
+	if len(dirs) == 0 {
+		delete(config, "cdi-spec-dirs")
+	} else {
+		config["cdi-spec-dirs"] = dirs
+	}
+	*c = config
+}
+
 // RemoveRuntime removes a runtime from the docker config
 func (c *Config) RemoveRuntime(name string) error {
 	if c == nil {
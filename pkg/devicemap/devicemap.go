@@ -0,0 +1,99 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package devicemap
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// FileName is the name of the file in the container bundle that stores the
+// device map.
+const FileName = "nvidia-device-map.json"
+
+// A DeviceMap associates the GPU ordinals in a container with the GPUs on the
+// host. This allows tools such as monitoring agents or checkpoint / restore
+// tooling to correlate device indices in a container with host GPUs.
+type DeviceMap struct {
+	Devices []Device `json:"devices"`
+}
+
+// A Device represents a single GPU in a container.
+type Device struct {
+	// Index is the ordinal of the device in the container.
+	Index int `json:"index"`
+	// Minor is the minor number of the /dev/nvidiaN device node on the host.
+	Minor int `json:"minor"`
+	// UUID is the UUID of the GPU on the host.
+	UUID string `json:"uuid,omitempty"`
+	// PCIBusID is the PCI bus ID of the GPU on the host.
+	PCIBusID string `json:"pciBusID,omitempty"`
+}
+
+// GetPath returns the path of the device map file for the specified bundle
+// directory.
+func GetPath(bundleDir string) string {
+	return filepath.Join(bundleDir, FileName)
+}
+
+// Load loads the device map from the specified bundle directory.
+func Load(bundleDir string) (*DeviceMap, error) {
+	contents, err := os.ReadFile(GetPath(bundleDir))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read device map: %w", err)
+	}
+
+	var m DeviceMap
+	if err := json.Unmarshal(contents, &m); err != nil {
+		return nil, fmt.Errorf("failed to decode device map: %w", err)
+	}
+	return &m, nil
+}
+
+// Save writes the device map to the specified bundle directory.
+func (m *DeviceMap) Save(bundleDir string) error {
+	contents, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode device map: %w", err)
+	}
+	if err := os.WriteFile(GetPath(bundleDir), contents, 0644); err != nil {
+		return fmt.Errorf("failed to write device map: %w", err)
+	}
+	return nil
+}
+
+// ByUUID returns the device with the specified host UUID.
+func (m *DeviceMap) ByUUID(uuid string) (*Device, bool) {
+	for i := range m.Devices {
+		if m.Devices[i].UUID == uuid {
+			return &m.Devices[i], true
+		}
+	}
+	return nil, false
+}
+
+// ByIndex returns the device with the specified container ordinal.
+func (m *DeviceMap) ByIndex(index int) (*Device, bool) {
+	for i := range m.Devices {
+		if m.Devices[i].Index == index {
+			return &m.Devices[i], true
+		}
+	}
+	return nil, false
+}
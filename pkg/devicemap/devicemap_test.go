@@ -0,0 +1,50 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package devicemap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveAndLoad(t *testing.T) {
+	bundleDir := t.TempDir()
+
+	m := &DeviceMap{
+		Devices: []Device{
+			{Index: 0, Minor: 2, UUID: "GPU-2", PCIBusID: "0000:02:00.0"},
+			{Index: 1, Minor: 0, UUID: "GPU-0", PCIBusID: "0000:05:00.0"},
+		},
+	}
+	require.NoError(t, m.Save(bundleDir))
+
+	loaded, err := Load(bundleDir)
+	require.NoError(t, err)
+	require.EqualValues(t, m, loaded)
+
+	device, ok := loaded.ByUUID("GPU-0")
+	require.True(t, ok)
+	require.Equal(t, 1, device.Index)
+
+	device, ok = loaded.ByIndex(0)
+	require.True(t, ok)
+	require.Equal(t, "GPU-2", device.UUID)
+
+	_, ok = loaded.ByIndex(2)
+	require.False(t, ok)
+}
@@ -0,0 +1,103 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package injectionreport
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// FileName is the name of the file in the container bundle that stores
+	// the injection report.
+	FileName = "nvidia-injection-report.json"
+	// ContainerPath is the path at which the injection report is available
+	// in the container.
+	ContainerPath = "/run/nvidia-container-toolkit/injection.json"
+)
+
+// A Report describes the modifications that the NVIDIA Container Runtime made
+// to a container. This allows entrypoint scripts in the container to verify
+// their GPU environment before starting a workload.
+type Report struct {
+	// ToolkitVersion is the version of the NVIDIA Container Toolkit that
+	// created the container.
+	ToolkitVersion string `json:"toolkitVersion"`
+	// Mode is the mode of the NVIDIA Container Runtime.
+	Mode string `json:"mode"`
+	// Devices lists the device nodes that were injected.
+	Devices []Device `json:"devices"`
+	// Libraries lists the libraries that were mounted into the container.
+	Libraries []Library `json:"libraries"`
+	// Skipped lists the entries that were requested or discovered but were
+	// not injected.
+	Skipped []string `json:"skipped"`
+	// Warnings lists the other warnings raised while modifying the container.
+	Warnings []string `json:"warnings"`
+}
+
+// A Device represents an injected device node.
+type Device struct {
+	// Path is the path of the device node in the container.
+	Path string `json:"path"`
+	// UUID is the UUID of the GPU for /dev/nvidiaN device nodes where this is
+	// known.
+	UUID string `json:"uuid,omitempty"`
+}
+
+// A Library represents an injected library.
+type Library struct {
+	// Path is the path of the library in the container.
+	Path string `json:"path"`
+	// HostPath is the path of the library on the host.
+	HostPath string `json:"hostPath"`
+}
+
+// GetPath returns the path of the injection report for the specified bundle
+// directory.
+func GetPath(bundleDir string) string {
+	return filepath.Join(bundleDir, FileName)
+}
+
+// Load loads the injection report from the specified path. In a container
+// this is ContainerPath.
+func Load(path string) (*Report, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read injection report: %w", err)
+	}
+
+	var r Report
+	if err := json.Unmarshal(contents, &r); err != nil {
+		return nil, fmt.Errorf("failed to decode injection report: %w", err)
+	}
+	return &r, nil
+}
+
+// Save writes the injection report to the specified bundle directory.
+func (r *Report) Save(bundleDir string) error {
+	contents, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode injection report: %w", err)
+	}
+	if err := os.WriteFile(GetPath(bundleDir), contents, 0644); err != nil {
+		return fmt.Errorf("failed to write injection report: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package injectionreport
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveAndLoad(t *testing.T) {
+	bundleDir := t.TempDir()
+
+	r := &Report{
+		ToolkitVersion: "1.2.3",
+		Mode:           "jit-cdi",
+		Devices: []Device{
+			{Path: "/dev/nvidiactl"},
+			{Path: "/dev/nvidia0", UUID: "GPU-0"},
+		},
+		Libraries: []Library{
+			{Path: "/usr/lib/libcuda.so.1", HostPath: "/usr/lib/x86_64-linux-gnu/libcuda.so.1"},
+		},
+		Skipped:  []string{"Could not locate libnvidia-missing.so"},
+		Warnings: []string{},
+	}
+	require.NoError(t, r.Save(bundleDir))
+
+	loaded, err := Load(GetPath(bundleDir))
+	require.NoError(t, err)
+	require.EqualValues(t, r, loaded)
+}
@@ -72,6 +72,12 @@ const (
 	HookUpdateLDCache = UpdateLDCacheHook
 )
 
+// IsValidHookName checks whether the specified name refers to one of the
+// predefined hooks.
+func IsValidHookName[T string | HookName](name T) bool {
+	return discover.IsValidHookName(name)
+}
+
 // A FeatureFlag refers to a specific feature that can be toggled in the CDI api.
 // All features are off by default.
 type FeatureFlag string
@@ -80,4 +86,23 @@ const (
 	// FeatureDisableNvsandboxUtils disables the use of nvsandboxutils when
 	// querying devices.
 	FeatureDisableNvsandboxUtils = FeatureFlag("disable-nvsandbox-utils")
+	// FeatureSkipLDCacheCreation configures the update-ldcache hook to only
+	// refresh the ldcache in a container if it already exists.
+	FeatureSkipLDCacheCreation = FeatureFlag("skip-ldcache-creation")
+	// FeatureSandboxHooks configures the generated hooks to run with reduced
+	// privileges.
+	FeatureSandboxHooks = FeatureFlag("sandbox-hooks")
+	// FeatureStableHookPaths configures the generated hooks to reference the
+	// nvidia-cdi-hook at a well-known path.
+	FeatureStableHookPaths = FeatureFlag("stable-hook-paths")
+	// FeatureInjectDriverParams includes the NVIDIA kernel module parameters
+	// (e.g. /sys/module/nvidia/parameters) as read-only mounts in the
+	// generated specification.
+	FeatureInjectDriverParams = FeatureFlag("inject-driver-params")
+	// FeatureInjectGPUSysfs includes the sysfs folders of each full GPU as
+	// read-only mounts in the specification of the GPU.
+	FeatureInjectGPUSysfs = FeatureFlag("inject-gpu-sysfs")
+	// FeatureEnableEGM includes the extended GPU memory (EGM) device nodes of
+	// Grace Hopper systems in the generated specification.
+	FeatureEnableEGM = FeatureFlag("enable-egm")
 )
@@ -0,0 +1,133 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package nvcdi
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/NVIDIA/go-nvlib/pkg/nvlib/device"
+)
+
+// A DeviceOrder determines how GPUs are enumerated when resolving device
+// indices and when generating the specs for all devices.
+type DeviceOrder string
+
+const (
+	// DeviceOrderNVML enumerates GPUs in the order reported by NVML. This is
+	// the default.
+	DeviceOrderNVML = DeviceOrder("nvml")
+	// DeviceOrderPCIBusID enumerates GPUs in ascending PCI bus ID order. This
+	// matches the enumeration of CUDA applications that set
+	// CUDA_DEVICE_ORDER=PCI_BUS_ID and is stable across nodes with the same
+	// topology.
+	DeviceOrderPCIBusID = DeviceOrder("pci-bus-id")
+)
+
+// cudaDeviceOrderEnvvar is the envvar used to select the device enumeration
+// order of the CUDA runtime.
+const cudaDeviceOrderEnvvar = "CUDA_DEVICE_ORDER"
+
+// IsValidDeviceOrder checks whether the specified device order is supported.
+// An empty device order is valid and is equivalent to DeviceOrderNVML.
+func IsValidDeviceOrder[T string | DeviceOrder](o T) bool {
+	switch DeviceOrder(o) {
+	case "", DeviceOrderNVML, DeviceOrderPCIBusID:
+		return true
+	}
+	return false
+}
+
+// cudaDeviceOrder returns the value of CUDA_DEVICE_ORDER that matches the
+// device order. An empty string is returned if the envvar should not be set.
+func (o DeviceOrder) cudaDeviceOrder() string {
+	if o == DeviceOrderPCIBusID {
+		return "PCI_BUS_ID"
+	}
+	return ""
+}
+
+// getOrderedDevices returns the GPUs on the system in the configured order.
+func (l *nvmllib) getOrderedDevices() ([]device.Device, error) {
+	devices, err := l.devicelib.GetDevices()
+	if err != nil {
+		return nil, err
+	}
+	if l.deviceOrder != DeviceOrderPCIBusID {
+		return devices, nil
+	}
+
+	busIDs := make([]string, len(devices))
+	for i, d := range devices {
+		busID, err := d.GetPCIBusID()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get PCI bus ID: %w", err)
+		}
+		busIDs[i] = busID
+	}
+	order := make([]int, len(devices))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return busIDs[order[i]] < busIDs[order[j]]
+	})
+
+	var ordered []device.Device
+	for _, i := range order {
+		ordered = append(ordered, devices[i])
+	}
+	return ordered, nil
+}
+
+// visitDevices visits the GPUs on the system in the configured order. The
+// index passed to the visitor is the position of the device in this order.
+func (l *nvmllib) visitDevices(visit func(int, device.Device) error) error {
+	devices, err := l.getOrderedDevices()
+	if err != nil {
+		return fmt.Errorf("error getting devices: %w", err)
+	}
+	for i, d := range devices {
+		if err := visit(i, d); err != nil {
+			return fmt.Errorf("error visiting device: %w", err)
+		}
+	}
+	return nil
+}
+
+// visitMigDevices visits the MIG devices of the GPUs on the system with the
+// parent GPUs in the configured order.
+func (l *nvmllib) visitMigDevices(visit func(int, device.Device, int, device.MigDevice) error) error {
+	return l.visitDevices(func(i int, d device.Device) error {
+		return d.VisitMigDevices(func(j int, m device.MigDevice) error {
+			return visit(i, d, j, m)
+		})
+	})
+}
+
+// getDeviceByIndex returns the GPU at the specified index in the configured
+// order.
+func (l *nvmllib) getDeviceByIndex(idx int) (device.Device, error) {
+	devices, err := l.getOrderedDevices()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get devices: %w", err)
+	}
+	if idx < 0 || idx >= len(devices) {
+		return nil, fmt.Errorf("device index %d out of range", idx)
+	}
+	return devices[idx], nil
+}
@@ -0,0 +1,100 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package nvcdi
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"tags.cncf.io/container-device-interface/pkg/cdi"
+	"tags.cncf.io/container-device-interface/specs-go"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/discover"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/edits"
+)
+
+// A driverarchivelib generates CDI specifications for an offline driver
+// package such as an extracted driver package or a driver installed to an
+// image. Since NVML is not used, the driver version is determined from the
+// libraries in the driver root and a single all device is generated for the
+// device nodes that the driver creates on the system where the spec is used.
+type driverarchivelib nvcdilib
+
+var _ deviceSpecGeneratorFactory = (*driverarchivelib)(nil)
+
+func (l *driverarchivelib) DeviceSpecGenerators(...string) (DeviceSpecGenerator, error) {
+	return l, nil
+}
+
+// GetDeviceSpecs returns the CDI device specs for a single all device.
+// The device nodes are not discovered on the host since these may not exist
+// on the system where the spec is generated. Instead, the device nodes for
+// the configured number of GPUs and the control device nodes are included
+// without their device numbers, which are determined by the CDI runtime when
+// the device is injected.
+func (l *driverarchivelib) GetDeviceSpecs() ([]specs.Device, error) {
+	if l.driverArchiveGPUCount < 1 {
+		return nil, fmt.Errorf("invalid GPU count %d; at least one GPU is required", l.driverArchiveGPUCount)
+	}
+
+	var paths []string
+	for i := 0; i < l.driverArchiveGPUCount; i++ {
+		paths = append(paths, fmt.Sprintf("/dev/nvidia%d", i))
+	}
+	paths = append(paths,
+		"/dev/nvidiactl",
+		"/dev/nvidia-uvm",
+		"/dev/nvidia-uvm-tools",
+	)
+
+	var deviceNodes []*specs.DeviceNode
+	for _, path := range paths {
+		deviceNodes = append(deviceNodes, &specs.DeviceNode{
+			Path:     path,
+			HostPath: filepath.Join(l.devRoot, path),
+		})
+	}
+
+	device := specs.Device{
+		Name: "all",
+		ContainerEdits: specs.ContainerEdits{
+			DeviceNodes: deviceNodes,
+		},
+	}
+	return []specs.Device{device}, nil
+}
+
+// GetCommonEdits returns the common edits for the driver files in the offline
+// driver package.
+func (l *driverarchivelib) GetCommonEdits() (*cdi.ContainerEdits, error) {
+	graphicsMounts, err := discover.NewGraphicsMountsDiscoverer(l.logger, l.driver, l.hookCreator)
+	if err != nil {
+		l.logger.Warningf("failed to create discoverer for graphics mounts: %v", err)
+	}
+
+	driverFiles, err := (*nvcdilib)(l).newDriverVersionDiscoverer()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create discoverer for driver files: %v", err)
+	}
+
+	return edits.FromDiscoverer(
+		discover.Merge(
+			graphicsMounts,
+			driverFiles,
+		),
+	)
+}
@@ -0,0 +1,69 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package nvcdi
+
+import (
+	"debug/elf"
+	"fmt"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/discover"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup"
+)
+
+// compat32LibrarySearchPaths are the folders that are searched for 32-bit
+// driver libraries on multi-arch systems. These cover the Debian-based
+// (i386-linux-gnu), Arch-based (lib32), and RPM-based (lib) layouts.
+var compat32LibrarySearchPaths = []string{
+	"/usr/lib/i386-linux-gnu",
+	"/usr/lib32",
+	"/usr/lib",
+}
+
+// getCompat32DriverLibraryMounts returns a discoverer for the 32-bit driver
+// libraries with the specified version. Since the search paths may also
+// contain native libraries, only 32-bit ELF files are included.
+func (l *nvcdilib) getCompat32DriverLibraryMounts(version string) discover.Discover {
+	locator := lookup.NewFileLocator(
+		lookup.WithLogger(l.logger),
+		lookup.WithRoot(l.driver.Root),
+		lookup.WithSearchPaths(compat32LibrarySearchPaths...),
+		lookup.WithFilter(assert32BitLibrary),
+		lookup.WithOptional(true),
+	)
+	return l.withLibraryFilter(
+		discover.NewMounts(
+			l.logger,
+			locator,
+			l.driver.Root,
+			[]string{"*.so." + version},
+		),
+	)
+}
+
+// assert32BitLibrary checks whether the specified file is a 32-bit ELF file.
+func assert32BitLibrary(filename string) error {
+	f, err := elf.Open(filename)
+	if err != nil {
+		return fmt.Errorf("failed to open %v as ELF file: %w", filename, err)
+	}
+	defer f.Close()
+
+	if f.Class != elf.ELFCLASS32 {
+		return fmt.Errorf("%v is not a 32-bit library", filename)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package nvcdi
+
+import (
+	"debug/elf"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup/root"
+)
+
+func TestCompat32DriverLibraryMounts(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	driverRoot := t.TempDir()
+	writeELFHeader(t, filepath.Join(driverRoot, "usr/lib/i386-linux-gnu/libcuda.so.570.124.06"), elf.ELFCLASS32)
+	writeELFHeader(t, filepath.Join(driverRoot, "usr/lib/i386-linux-gnu/libcuda.so.535.0.0"), elf.ELFCLASS32)
+	writeELFHeader(t, filepath.Join(driverRoot, "usr/lib32/libnvidia-ml.so.570.124.06"), elf.ELFCLASS32)
+	// Native libraries in the search paths are ignored.
+	writeELFHeader(t, filepath.Join(driverRoot, "usr/lib/libnvidia-ml.so.570.124.06"), elf.ELFCLASS64)
+
+	l := &nvcdilib{
+		logger: logger,
+		driver: root.New(root.WithDriverRoot(driverRoot)),
+	}
+
+	mounts, err := l.getCompat32DriverLibraryMounts("570.124.06").Mounts()
+	require.NoError(t, err)
+
+	var paths []string
+	for _, m := range mounts {
+		require.Equal(t, filepath.Join(driverRoot, m.Path), m.HostPath)
+		paths = append(paths, m.Path)
+	}
+	require.ElementsMatch(t,
+		[]string{
+			"/usr/lib/i386-linux-gnu/libcuda.so.570.124.06",
+			"/usr/lib32/libnvidia-ml.so.570.124.06",
+		},
+		paths,
+	)
+}
+
+// writeELFHeader writes a minimal little-endian ELF header of the specified
+// class to the file.
+func writeELFHeader(t *testing.T, filename string, class elf.Class) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(filename), 0755))
+
+	ident := make([]byte, elf.EI_NIDENT)
+	copy(ident, elf.ELFMAG)
+	ident[elf.EI_CLASS] = byte(class)
+	ident[elf.EI_DATA] = byte(elf.ELFDATA2LSB)
+	ident[elf.EI_VERSION] = byte(elf.EV_CURRENT)
+
+	var header any
+	switch class {
+	case elf.ELFCLASS32:
+		header = elf.Header32{
+			Ident:   [elf.EI_NIDENT]byte(ident),
+			Type:    uint16(elf.ET_DYN),
+			Machine: uint16(elf.EM_386),
+			Version: uint32(elf.EV_CURRENT),
+			Ehsize:  52,
+		}
+	default:
+		header = elf.Header64{
+			Ident:   [elf.EI_NIDENT]byte(ident),
+			Type:    uint16(elf.ET_DYN),
+			Machine: uint16(elf.EM_X86_64),
+			Version: uint32(elf.EV_CURRENT),
+			Ehsize:  64,
+		}
+	}
+
+	f, err := os.Create(filename)
+	require.NoError(t, err)
+	defer f.Close()
+	require.NoError(t, binary.Write(f, binary.LittleEndian, header))
+}
@@ -64,11 +64,17 @@ func (l *nvcdilib) newDriverVersionDiscoverer() (discover.Discover, error) {
 
 	binaries := NewDriverBinariesDiscoverer(l.logger, l.driver.Root)
 
+	var params discover.Discover
+	if l.featureFlags[FeatureInjectDriverParams] {
+		params = discover.NewDriverParamsDiscoverer(l.logger, "/")
+	}
+
 	d := discover.Merge(
 		libraries,
 		ipcs,
 		firmwares,
 		binaries,
+		params,
 	)
 
 	return d, nil
@@ -89,6 +95,12 @@ func (l *nvcdilib) NewDriverLibraryDiscoverer(version string, libcudaSoParentDir
 		versionSuffixLibraryMounts,
 		explicitLibraryMounts,
 	)
+	if l.compat32Libraries {
+		libraries = discover.Merge(
+			libraries,
+			l.getCompat32DriverLibraryMounts(version),
+		)
+	}
 
 	var discoverers []discover.Discover
 
@@ -141,7 +153,14 @@ func (l *nvcdilib) getVersionSuffixDriverLibraryMounts(version string) (discover
 		versionSuffixLibraryPaths,
 	)
 
-	return mounts, nil
+	return l.withLibraryFilter(mounts), nil
+}
+
+// withLibraryFilter wraps the specified discoverer so that only the libraries
+// that are required for the requested driver capabilities are returned. If no
+// library filter is enabled, the discoverer is returned as is.
+func (l *nvcdilib) withLibraryFilter(d discover.Discover) discover.Discover {
+	return discover.NewCapabilityFilter(l.logger, d, l.libraryGroups, l.driverCapabilities)
 }
 
 func (l *nvcdilib) getExplicitDriverLibraryMounts() (discover.Discover, error) {
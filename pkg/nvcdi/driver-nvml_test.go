@@ -0,0 +1,105 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package nvcdi
+
+import (
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config/image"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/discover"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/librarygroups"
+)
+
+func TestWithLibraryFilter(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	libraries := []string{
+		"/usr/lib64/libcuda.so.570.124.06",
+		"/usr/lib64/libnvidia-ml.so.570.124.06",
+		"/usr/lib64/libnvcuvid.so.570.124.06",
+		"/usr/lib64/libnvidia-unclassified.so.570.124.06",
+	}
+	var mounts []discover.Mount
+	for _, library := range libraries {
+		mounts = append(mounts, discover.Mount{HostPath: library, Path: library})
+	}
+
+	testCases := []struct {
+		description        string
+		libraryGroups      librarygroups.Groups
+		driverCapabilities image.DriverCapabilities
+		expectedLibraries  []string
+	}{
+		{
+			description:       "no filter includes all libraries",
+			expectedLibraries: libraries,
+		},
+		{
+			description:        "default groups filter by capability",
+			libraryGroups:      librarygroups.Default(),
+			driverCapabilities: image.NewDriverCapabilities("compute,utility"),
+			expectedLibraries: []string{
+				"/usr/lib64/libcuda.so.570.124.06",
+				"/usr/lib64/libnvidia-ml.so.570.124.06",
+				"/usr/lib64/libnvidia-unclassified.so.570.124.06",
+			},
+		},
+		{
+			description:        "all capabilities includes all libraries",
+			libraryGroups:      librarygroups.Default(),
+			driverCapabilities: image.NewDriverCapabilities("all"),
+			expectedLibraries:  libraries,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			l := &nvcdilib{
+				logger:             logger,
+				libraryGroups:      tc.libraryGroups,
+				driverCapabilities: tc.driverCapabilities,
+			}
+			d := l.withLibraryFilter(&discover.DiscoverMock{
+				MountsFunc: func() ([]discover.Mount, error) {
+					return mounts, nil
+				},
+			})
+
+			filtered, err := d.Mounts()
+			require.NoError(t, err)
+
+			var filteredLibraries []string
+			for _, mount := range filtered {
+				filteredLibraries = append(filteredLibraries, mount.Path)
+			}
+			require.EqualValues(t, tc.expectedLibraries, filteredLibraries)
+		})
+	}
+}
+
+func TestNewWithMissingLibraryGroupsFile(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	_, err := New(
+		WithLogger(logger),
+		WithLibraryFilter("/does/not/exist.toml"),
+	)
+	require.Error(t, err)
+}
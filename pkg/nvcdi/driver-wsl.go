@@ -18,110 +18,39 @@ package nvcdi
 
 import (
 	"fmt"
-	"path/filepath"
 
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/discover"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/dxcore"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
-	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/platform-support/wsl"
 )
 
-var requiredDriverStoreFiles = []string{
-	"libcuda.so.1.1",                /* Core library for cuda support */
-	"libcuda_loader.so",             /* Core library for cuda support on WSL */
-	"libnvidia-ptxjitcompiler.so.1", /* Core library for PTX Jit support */
-	"libnvidia-ml.so.1",             /* Core library for nvml */
-	"libnvidia-ml_loader.so",        /* Core library for nvml on WSL */
-	"libdxcore.so",                  /* Core library for dxcore support */
-	"libnvdxgdmal.so.1",             /* dxgdmal library for cuda */
-	"nvcubins.bin",                  /* Binary containing GPU code for cuda */
-	"nvidia-smi",                    /* nvidia-smi binary*/
-}
-
 // newWSLDriverDiscoverer returns a Discoverer for WSL2 drivers.
-func newWSLDriverDiscoverer(logger logger.Interface, driverRoot string, hookCreator discover.HookCreator, ldconfigPath string) (discover.Discover, error) {
-	if err := dxcore.Init(); err != nil {
-		return nil, fmt.Errorf("failed to initialize dxcore: %w", err)
-	}
-	defer func() {
-		if err := dxcore.Shutdown(); err != nil {
-			logger.Warningf("failed to shutdown dxcore: %w", err)
+func newWSLDriverDiscoverer(logger logger.Interface, driverRoot string, driverStorePath string, hookCreator discover.HookCreator, ldconfigPath string) (discover.Discover, error) {
+	if driverStorePath == "" {
+		selected, err := getWSLDriverStorePath(logger)
+		if err != nil {
+			return nil, err
 		}
-	}()
-
-	driverStorePaths := dxcore.GetDriverStorePaths()
-	if len(driverStorePaths) == 0 {
-		return nil, fmt.Errorf("no driver store paths found")
-	}
-	if len(driverStorePaths) > 1 {
-		logger.Warningf("Found multiple driver store paths: %v", driverStorePaths)
-	}
-	logger.Infof("Using WSL driver store paths: %v", driverStorePaths)
-
-	driverStorePaths = append(driverStorePaths, "/usr/lib/wsl/lib")
-
-	driverStoreMounts := discover.NewMounts(
-		logger,
-		lookup.NewFileLocator(
-			lookup.WithLogger(logger),
-			lookup.WithSearchPaths(
-				driverStorePaths...,
-			),
-			lookup.WithCount(1),
-		),
-		driverRoot,
-		requiredDriverStoreFiles,
-	)
-
-	symlinkHook := nvidiaSMISimlinkHook{
-		logger:      logger,
-		mountsFrom:  driverStoreMounts,
-		hookCreator: hookCreator,
+		driverStorePath = selected
 	}
+	logger.Infof("Using WSL driver store path: %v", driverStorePath)
 
-	ldcacheHook, _ := discover.NewLDCacheUpdateHook(logger, driverStoreMounts, hookCreator, ldconfigPath)
-
-	d := discover.Merge(
-		driverStoreMounts,
-		symlinkHook,
-		ldcacheHook,
-	)
-
-	return d, nil
-}
-
-type nvidiaSMISimlinkHook struct {
-	discover.None
-	logger      logger.Interface
-	mountsFrom  discover.Discover
-	hookCreator discover.HookCreator
+	locator := wsl.NewDriverStoreLocator(logger, "", driverStorePath)
+	return wsl.NewDriverDiscoverer(logger, locator, driverRoot, hookCreator, ldconfigPath), nil
 }
 
-// Hooks returns a hook that creates a symlink to nvidia-smi in the driver store.
-// On WSL2 the driver store location is used unchanged, for this reason we need
-// to create a symlink from /usr/bin/nvidia-smi to the nvidia-smi binary in the
-// driver store.
-func (m nvidiaSMISimlinkHook) Hooks() ([]discover.Hook, error) {
-	mounts, err := m.mountsFrom.Mounts()
-	if err != nil {
-		return nil, fmt.Errorf("failed to discover mounts: %w", err)
+// getWSLDriverStorePath returns the driver store path for the adapters
+// exposed by the loaded dxg kernel interface.
+func getWSLDriverStorePath(logger logger.Interface) (string, error) {
+	if err := dxcore.Init(); err != nil {
+		return "", fmt.Errorf("failed to initialize dxcore: %w", err)
 	}
-
-	var target string
-	for _, mount := range mounts {
-		if filepath.Base(mount.Path) == "nvidia-smi" {
-			target = mount.Path
-			break
+	defer func() {
+		if err := dxcore.Shutdown(); err != nil {
+			logger.Warningf("failed to shutdown dxcore: %v", err)
 		}
-	}
-
-	if target == "" {
-		m.logger.Warningf("Failed to find nvidia-smi in mounts: %v", mounts)
-		return nil, nil
-	}
-	link := "/usr/bin/nvidia-smi"
-	links := []string{fmt.Sprintf("%s::%s", target, link)}
-	symlinkHook := m.hookCreator.Create(CreateSymlinksHook, links...)
+	}()
 
-	return symlinkHook.Hooks()
+	return wsl.SelectDriverStorePath(logger, "", dxcore.GetDriverStorePaths())
 }
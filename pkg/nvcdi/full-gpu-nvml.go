@@ -142,9 +142,25 @@ func (l *fullGPUDeviceSpecGenerator) newFullGPUDiscoverer(d device.Device) (disc
 		deviceNodes,
 	)
 
+	var sysfs, egmDevices discover.Discover
+	if l.featureFlags[FeatureInjectGPUSysfs] || l.featureFlags[FeatureEnableEGM] {
+		busID, err := d.GetPCIBusID()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get PCI bus ID: %w", err)
+		}
+		if l.featureFlags[FeatureInjectGPUSysfs] {
+			sysfs = discover.NewGPUSysfsDiscoverer(l.logger, "/", busID)
+		}
+		if l.featureFlags[FeatureEnableEGM] {
+			egmDevices = discover.NewEGMDiscoverer(l.logger, l.devRoot, "/", busID)
+		}
+	}
+
 	dd := discover.Merge(
 		deviceNodes,
 		deviceFolderPermissionHooks,
+		sysfs,
+		egmDevices,
 	)
 
 	return dd, nil
@@ -27,6 +27,7 @@ import (
 	"tags.cncf.io/container-device-interface/specs-go"
 
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/edits"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/info/proc"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/nvsandboxutils"
 )
 
@@ -41,7 +42,18 @@ func (l *nvmllib) GetCommonEdits() (*cdi.ContainerEdits, error) {
 		return nil, fmt.Errorf("failed to create discoverer for common entities: %v", err)
 	}
 
-	return edits.FromDiscoverer(common)
+	commonEdits, err := edits.FromDiscoverer(common)
+	if err != nil {
+		return nil, err
+	}
+	if order := l.deviceOrder.cudaDeviceOrder(); order != "" {
+		commonEdits.Append(&cdi.ContainerEdits{
+			ContainerEdits: &specs.ContainerEdits{
+				Env: []string{cudaDeviceOrderEnvvar + "=" + order},
+			},
+		})
+	}
+	return commonEdits, nil
 }
 
 // DeviceSpecGenerators returns the CDI device spec generators for NVML devices
@@ -107,15 +119,21 @@ func (l *nvmllib) newDeviceSpecGeneratorFromNVMLDevice(id string, nvmlDevice nvm
 
 // getDeviceSpecGeneratorsForAllDevices returns the CDI device spec generators
 // for all NVML devices detected on the system.
-// This includes full GPUs as well as MIG devices.
+// This includes full GPUs as well as MIG devices as selected by the configured
+// MIG strategy.
 func (l *nvmllib) getDeviceSpecGeneratorsForAllDevices() (DeviceSpecGenerator, error) {
+	anyMigEnabled, err := l.anyMigEnabled()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check MIG mode: %w", err)
+	}
+
 	var DeviceSpecGenerators DeviceSpecGenerators
-	err := l.devicelib.VisitDevices(func(i int, d device.Device) error {
+	err = l.visitDevices(func(i int, d device.Device) error {
 		isMigEnabled, err := d.IsMigEnabled()
 		if err != nil {
 			return err
 		}
-		if isMigEnabled {
+		if !l.migStrategy.includeFullGPU(isMigEnabled, anyMigEnabled) {
 			return nil
 		}
 		fullGPU, err := l.newFullGPUDeviceSpecGeneratorFromDevice(i, d)
@@ -129,7 +147,11 @@ func (l *nvmllib) getDeviceSpecGeneratorsForAllDevices() (DeviceSpecGenerator, e
 		return nil, fmt.Errorf("failed to get full GPU device editors: %w", err)
 	}
 
-	err = l.devicelib.VisitMigDevices(func(i int, d device.Device, j int, mig device.MigDevice) error {
+	if !l.migStrategy.includeMigDevices() {
+		return DeviceSpecGenerators, nil
+	}
+
+	err = l.visitMigDevices(func(i int, d device.Device, j int, mig device.MigDevice) error {
 		migDevice, err := l.newMIGDeviceSpecGeneratorFromDevice(i, d, j, mig)
 		if err != nil {
 			return err
@@ -144,6 +166,25 @@ func (l *nvmllib) getDeviceSpecGeneratorsForAllDevices() (DeviceSpecGenerator, e
 	return DeviceSpecGenerators, nil
 }
 
+// anyMigEnabled checks whether MIG is enabled on any device. This is only
+// required for the single MIG strategy and false is returned for other
+// strategies.
+func (l *nvmllib) anyMigEnabled() (bool, error) {
+	if l.migStrategy != MIGStrategySingle {
+		return false, nil
+	}
+	var anyMigEnabled bool
+	err := l.visitDevices(func(i int, d device.Device) error {
+		isMigEnabled, err := d.IsMigEnabled()
+		if err != nil {
+			return err
+		}
+		anyMigEnabled = anyMigEnabled || isMigEnabled
+		return nil
+	})
+	return anyMigEnabled, err
+}
+
 // TODO: move this to go-nvlib?
 // normalizeDeviceID returns the UUIDs of the devices specified by the identifier.
 func (l *nvmllib) normalizeDeviceIDs(identifiers ...device.Identifier) ([]device.Identifier, error) {
@@ -170,9 +211,21 @@ func (l *nvmllib) normalizeDeviceID(id device.Identifier) (device.Identifier, er
 		if err != nil {
 			return "", fmt.Errorf("failed to convert device index to an int: %w", err)
 		}
-		dev, ret := l.nvmllib.DeviceGetHandleByIndex(idx)
+		dev, err := l.getDeviceByIndex(idx)
+		if err != nil {
+			return "", fmt.Errorf("failed to get device handle from index: %w", err)
+		}
+		uuid, ret := dev.GetUUID()
+		if ret != nvml.SUCCESS {
+			return "", fmt.Errorf("failed to get device UUID: %v", ret)
+		}
+		return device.Identifier(uuid), nil
+	}
+
+	if proc.IsPCIBusID(string(id)) {
+		dev, ret := l.nvmllib.DeviceGetHandleByPciBusId(string(id))
 		if ret != nvml.SUCCESS {
-			return "", fmt.Errorf("failed to get device handle from index: %v", ret)
+			return "", fmt.Errorf("failed to get device handle from PCI bus ID: %v", ret)
 		}
 		uuid, ret := dev.GetUUID()
 		if ret != nvml.SUCCESS {
@@ -191,9 +244,9 @@ func (l *nvmllib) normalizeDeviceID(id device.Identifier) (device.Identifier, er
 		if migIdx, err = strconv.Atoi(split[1]); err != nil {
 			return "", fmt.Errorf("failed to convert device index to an int: %w", err)
 		}
-		parent, ret := l.nvmllib.DeviceGetHandleByIndex(gpuIdx)
-		if ret != nvml.SUCCESS {
-			return "", fmt.Errorf("failed to get parent device handle: %v", ret)
+		parent, err := l.getDeviceByIndex(gpuIdx)
+		if err != nil {
+			return "", fmt.Errorf("failed to get parent device handle: %w", err)
 		}
 		mig, ret := parent.GetMigDeviceHandleByIndex(migIdx)
 		if ret != nvml.SUCCESS {
@@ -206,7 +259,7 @@ func (l *nvmllib) normalizeDeviceID(id device.Identifier) (device.Identifier, er
 		return device.Identifier(uuid), nil
 	}
 
-	return "", fmt.Errorf("identifier is not a valid UUID or index: %q", id)
+	return "", fmt.Errorf("identifier is not a valid UUID, index, or PCI bus ID: %q", id)
 }
 
 func (l *nvmllib) init() error {
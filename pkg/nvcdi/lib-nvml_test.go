@@ -18,6 +18,7 @@
 package nvcdi
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/NVIDIA/go-nvml/pkg/nvml"
@@ -77,6 +78,26 @@ func TestNvmllibGetDeviceSpecGeneratorsForIDs(t *testing.T) {
 			expectedError:  nil,
 			expectedLength: 1,
 		},
+		{
+			name: "single PCI bus ID",
+			ids:  []string{"0000:65:00.0"},
+			setupMock: func(server *dgxa100.Server) {
+				for _, d := range server.Devices {
+					// TODO: This is not implemented in the mock.
+					(d.(*dgxa100.Device)).IsMigDeviceHandleFunc = func() (bool, nvml.Return) {
+						return false, nvml.SUCCESS
+					}
+				}
+				server.DeviceGetHandleByPciBusIdFunc = func(s string) (nvml.Device, nvml.Return) {
+					if s == "0000:65:00.0" {
+						return server.Devices[2], nvml.SUCCESS
+					}
+					return nil, nvml.ERROR_NOT_FOUND
+				}
+			},
+			expectedError:  nil,
+			expectedLength: 1,
+		},
 		{
 			name: "MIG device index",
 			ids:  []string{"0:0"},
@@ -155,3 +176,85 @@ func mockOverrides(server *dgxa100.Server) {
 		}
 	}
 }
+
+func TestNvmllibDeviceOrder(t *testing.T) {
+	testCases := []struct {
+		name                string
+		deviceOrder         DeviceOrder
+		id                  string
+		expectedOrder       []int
+		expectedDeviceIndex int
+	}{
+		{
+			name:                "nvml order",
+			deviceOrder:         DeviceOrderNVML,
+			id:                  "0",
+			expectedOrder:       []int{0, 1, 2, 3, 4, 5, 6, 7},
+			expectedDeviceIndex: 0,
+		},
+		{
+			name:                "pci bus id order",
+			deviceOrder:         DeviceOrderPCIBusID,
+			id:                  "0",
+			expectedOrder:       []int{7, 6, 5, 4, 3, 2, 1, 0},
+			expectedDeviceIndex: 7,
+		},
+		{
+			name:                "pci bus id order last index",
+			deviceOrder:         DeviceOrderPCIBusID,
+			id:                  "7",
+			expectedOrder:       []int{7, 6, 5, 4, 3, 2, 1, 0},
+			expectedDeviceIndex: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockNvml := dgxa100.New()
+			mockOverrides(mockNvml)
+			// Assign PCI bus IDs in the reverse of the NVML enumeration order.
+			for i, d := range mockNvml.Devices {
+				busID := fmt.Sprintf("00000000:%02x:00.0", len(mockNvml.Devices)-i)
+				(d.(*dgxa100.Device)).GetPciInfoFunc = func() (nvml.PciInfo, nvml.Return) {
+					var p nvml.PciInfo
+					for j, c := range busID {
+						p.BusId[j] = int8(c)
+					}
+					return p, nvml.SUCCESS
+				}
+			}
+
+			l := &nvmllib{
+				nvmllib:     mockNvml,
+				devicelib:   device.New(mockNvml),
+				deviceOrder: tc.deviceOrder,
+			}
+
+			var uuids []string
+			err := l.visitDevices(func(i int, d device.Device) error {
+				uuid, ret := d.GetUUID()
+				require.Equal(t, nvml.SUCCESS, ret)
+				uuids = append(uuids, uuid)
+				return nil
+			})
+			require.NoError(t, err)
+
+			var expectedUUIDs []string
+			for _, i := range tc.expectedOrder {
+				expectedUUIDs = append(expectedUUIDs, mockNvml.Devices[i].(*dgxa100.Device).UUID)
+			}
+			require.Equal(t, expectedUUIDs, uuids)
+
+			uuid, err := l.normalizeDeviceID(device.Identifier(tc.id))
+			require.NoError(t, err)
+			require.EqualValues(t, mockNvml.Devices[tc.expectedDeviceIndex].(*dgxa100.Device).UUID, uuid)
+		})
+	}
+}
+
+func TestIsValidDeviceOrder(t *testing.T) {
+	require.True(t, IsValidDeviceOrder(""))
+	require.True(t, IsValidDeviceOrder(DeviceOrderNVML))
+	require.True(t, IsValidDeviceOrder(DeviceOrderPCIBusID))
+	require.False(t, IsValidDeviceOrder("invalid"))
+}
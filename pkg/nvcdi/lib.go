@@ -23,7 +23,9 @@ import (
 	"github.com/NVIDIA/go-nvlib/pkg/nvlib/info"
 	"github.com/NVIDIA/go-nvml/pkg/nvml"
 
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config/image"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/discover"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/librarygroups"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup/root"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/nvsandboxutils"
@@ -38,16 +40,38 @@ type nvcdilib struct {
 	mode               Mode
 	devicelib          device.Interface
 	deviceNamers       DeviceNamers
+	migStrategy        MIGStrategy
+	deviceOrder        DeviceOrder
 	driverRoot         string
 	devRoot            string
 	nvidiaCDIHookPath  string
 	ldconfigPath       string
 	configSearchPaths  []string
 	librarySearchPaths []string
+	// compat32Libraries indicates whether 32-bit driver libraries are
+	// included in addition to the native driver libraries.
+	compat32Libraries bool
+	// driverCapabilities are the driver capabilities requested by a
+	// container. These are used to filter the entries in the CSV files and,
+	// if a library filter is enabled, the driver libraries.
+	driverCapabilities image.DriverCapabilities
+	// filterLibraries indicates whether the driver libraries are filtered by
+	// the driver capabilities that they are required for using the library
+	// groups loaded from libraryGroupsFile.
+	filterLibraries   bool
+	libraryGroupsFile string
+	libraryGroups     librarygroups.Groups
 
 	csvFiles          []string
 	csvIgnorePatterns []string
 
+	// wslDriverStorePath overrides the driver store used in WSL mode.
+	wslDriverStorePath string
+
+	// driverArchiveGPUCount is the number of GPUs for which device nodes are
+	// included when generating a spec for a driver archive.
+	driverArchiveGPUCount int
+
 	vendor string
 	class  string
 
@@ -59,6 +83,7 @@ type nvcdilib struct {
 	featureFlags map[FeatureFlag]bool
 
 	disabledHooks []discover.HookName
+	hookPaths     map[discover.HookName]string
 	hookCreator   discover.HookCreator
 }
 
@@ -74,6 +99,17 @@ func New(opts ...Option) (Interface, error) {
 	if l.logger == nil {
 		l.logger = logger.New()
 	}
+	if !IsValidDeviceOrder(l.deviceOrder) {
+		return nil, fmt.Errorf("invalid device order %q", l.deviceOrder)
+	}
+	for name := range l.hookPaths {
+		if !IsValidHookName(name) {
+			return nil, fmt.Errorf("invalid hook name %q for hook path", name)
+		}
+	}
+	if l.deviceOrder == "" {
+		l.deviceOrder = DeviceOrderNVML
+	}
 	if len(l.deviceNamers) == 0 {
 		indexNamer, _ := NewDeviceNamer(DeviceNameStrategyIndex)
 		l.deviceNamers = []DeviceNamer{indexNamer}
@@ -87,9 +123,23 @@ func New(opts ...Option) (Interface, error) {
 	if l.devRoot == "" {
 		l.devRoot = l.driverRoot
 	}
+	if l.driverArchiveGPUCount == 0 {
+		l.driverArchiveGPUCount = 1
+	}
+	if l.filterLibraries {
+		groups, err := librarygroups.Load(l.libraryGroupsFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load library groups: %w", err)
+		}
+		l.libraryGroups = groups
+	}
 
-	l.nvmllib = l.getNvmlLib()
-	l.nvsandboxutilslib = l.getNvsandboxUtilsLib()
+	if l.mode != ModeDriverArchive {
+		// The libraries in an offline driver package do not match the loaded
+		// kernel modules (if any) and are not used to query the driver.
+		l.nvmllib = l.getNvmlLib()
+		l.nvsandboxutilslib = l.getNvsandboxUtilsLib()
+	}
 	l.driver = l.getDriver(
 		root.WithVersioner(
 			root.FirstOf(
@@ -127,6 +177,8 @@ func New(opts ...Option) (Interface, error) {
 		factory = (*managementlib)(l)
 	case ModeNvml:
 		factory = (*nvmllib)(l)
+	case ModeDriverArchive:
+		factory = (*driverarchivelib)(l)
 	case ModeWsl:
 		factory = (*wsllib)(l)
 	case ModeGds:
@@ -139,6 +191,11 @@ func New(opts ...Option) (Interface, error) {
 			l.class = "mofed"
 		}
 		factory = (*mofedlib)(l)
+	case ModeDpu:
+		if l.class == "" {
+			l.class = "dpu"
+		}
+		factory = (*mofedlib)(l)
 	case ModeImex:
 		if l.class == "" {
 			l.class = classImexChannel
@@ -149,10 +206,17 @@ func New(opts ...Option) (Interface, error) {
 	}
 
 	// create hookCreator
-	l.hookCreator = discover.NewHookCreator(
+	hookCreatorOptions := []discover.Option{
 		discover.WithNVIDIACDIHookPath(l.nvidiaCDIHookPath),
 		discover.WithDisabledHooks(l.disabledHooks...),
-	)
+		discover.WithSkipLDCacheCreation(l.featureFlags[FeatureSkipLDCacheCreation]),
+		discover.WithSandboxedHooks(l.featureFlags[FeatureSandboxHooks]),
+		discover.WithStableHookPaths(l.featureFlags[FeatureStableHookPaths]),
+	}
+	for name, path := range l.hookPaths {
+		hookCreatorOptions = append(hookCreatorOptions, discover.WithHookPath(name, path))
+	}
+	l.hookCreator = discover.NewHookCreator(hookCreatorOptions...)
 
 	w := wrapper{
 		factory:             factory,
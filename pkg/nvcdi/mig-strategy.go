@@ -0,0 +1,63 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package nvcdi
+
+// A MIGStrategy determines which devices are included when generating the
+// specs for all devices. The strategies match the MIG strategies of the
+// NVIDIA Kubernetes device plugin so that the generated device names match
+// the device IDs advertised by the device plugin.
+type MIGStrategy string
+
+const (
+	// MIGStrategyNone includes all full GPUs, regardless of whether MIG
+	// is enabled, and no MIG devices.
+	MIGStrategyNone = MIGStrategy("none")
+	// MIGStrategySingle includes only the MIG devices if MIG is enabled on
+	// any GPU and all full GPUs otherwise.
+	MIGStrategySingle = MIGStrategy("single")
+	// MIGStrategyMixed includes the full GPUs that do not have MIG enabled
+	// and all MIG devices. This is the default.
+	MIGStrategyMixed = MIGStrategy("mixed")
+)
+
+// IsValidMIGStrategy checks whether the specified MIG strategy is supported.
+// An empty strategy is valid and is equivalent to MIGStrategyMixed.
+func IsValidMIGStrategy[T string | MIGStrategy](s T) bool {
+	switch MIGStrategy(s) {
+	case "", MIGStrategyNone, MIGStrategySingle, MIGStrategyMixed:
+		return true
+	}
+	return false
+}
+
+// includeFullGPU checks whether a full GPU is included for the strategy.
+func (s MIGStrategy) includeFullGPU(isMigEnabled bool, anyMigEnabled bool) bool {
+	switch s {
+	case MIGStrategyNone:
+		return true
+	case MIGStrategySingle:
+		return !anyMigEnabled
+	default:
+		return !isMigEnabled
+	}
+}
+
+// includeMigDevices checks whether MIG devices are included for the
+// strategy.
+func (s MIGStrategy) includeMigDevices() bool {
+	return s != MIGStrategyNone
+}
@@ -0,0 +1,80 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package nvcdi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMIGStrategy(t *testing.T) {
+	testCases := []struct {
+		description               string
+		strategy                  MIGStrategy
+		anyMigEnabled             bool
+		expectedMigEnabledGPU     bool
+		expectedMigDisabledGPU    bool
+		expectedIncludeMigDevices bool
+	}{
+		{
+			description:               "default includes non-MIG GPUs and MIG devices",
+			anyMigEnabled:             true,
+			expectedMigDisabledGPU:    true,
+			expectedIncludeMigDevices: true,
+		},
+		{
+			description:               "mixed includes non-MIG GPUs and MIG devices",
+			strategy:                  MIGStrategyMixed,
+			anyMigEnabled:             true,
+			expectedMigDisabledGPU:    true,
+			expectedIncludeMigDevices: true,
+		},
+		{
+			description:            "none includes all full GPUs",
+			strategy:               MIGStrategyNone,
+			anyMigEnabled:          true,
+			expectedMigEnabledGPU:  true,
+			expectedMigDisabledGPU: true,
+		},
+		{
+			description:               "single with MIG enabled includes only MIG devices",
+			strategy:                  MIGStrategySingle,
+			anyMigEnabled:             true,
+			expectedIncludeMigDevices: true,
+		},
+		{
+			description:               "single without MIG enabled includes full GPUs",
+			strategy:                  MIGStrategySingle,
+			anyMigEnabled:             false,
+			expectedMigDisabledGPU:    true,
+			expectedMigEnabledGPU:     true,
+			expectedIncludeMigDevices: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			require.True(t, IsValidMIGStrategy(tc.strategy))
+			require.Equal(t, tc.expectedMigEnabledGPU, tc.strategy.includeFullGPU(true, tc.anyMigEnabled))
+			require.Equal(t, tc.expectedMigDisabledGPU, tc.strategy.includeFullGPU(false, tc.anyMigEnabled))
+			require.Equal(t, tc.expectedIncludeMigDevices, tc.strategy.includeMigDevices())
+		})
+	}
+
+	require.False(t, IsValidMIGStrategy("invalid"))
+}
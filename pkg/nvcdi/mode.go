@@ -37,11 +37,19 @@ const (
 	ModeGds = Mode("gds")
 	// ModeMofed configures the CDI spec generator to generate a MOFED spec.
 	ModeMofed = Mode("mofed")
+	// ModeDpu configures the CDI spec generator to generate a spec for the
+	// RDMA devices and hugepages required by DOCA applications on systems with
+	// BlueField DPUs or converged accelerators.
+	ModeDpu = Mode("dpu")
 	// ModeCSV configures the CDI spec generator to generate a spec based on the contents of CSV
 	// mountspec files.
 	ModeCSV = Mode("csv")
 	// ModeImex configures the CDI spec generated to generate a spec for the available IMEX channels.
 	ModeImex = Mode("imex")
+	// ModeDriverArchive configures the CDI spec generator to generate a spec
+	// for an offline driver package (e.g. an extracted driver package) located
+	// at the driver root. The driver on the host (if any) is not queried.
+	ModeDriverArchive = Mode("driver-archive")
 )
 
 type modeConstraint interface {
@@ -65,6 +73,7 @@ func getModes() modes {
 			ModeManagement,
 			ModeGds,
 			ModeMofed,
+			ModeDpu,
 			ModeCSV,
 		}
 		lookup := make(map[Mode]bool)
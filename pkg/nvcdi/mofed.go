@@ -36,13 +36,13 @@ func (l *mofedlib) DeviceSpecGenerators(...string) (DeviceSpecGenerator, error)
 
 // GetDeviceSpecs returns the CDI device specs for a single all device.
 func (l *mofedlib) GetDeviceSpecs() ([]specs.Device, error) {
-	discoverer, err := discover.NewMOFEDDiscoverer(l.logger, l.driverRoot)
+	discoverer, err := l.newDiscoverer()
 	if err != nil {
-		return nil, fmt.Errorf("failed to create MOFED discoverer: %v", err)
+		return nil, fmt.Errorf("failed to create %v discoverer: %v", l.mode, err)
 	}
 	edits, err := edits.FromDiscoverer(discoverer)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create container edits for MOFED devices: %v", err)
+		return nil, fmt.Errorf("failed to create container edits for %v devices: %v", l.mode, err)
 	}
 
 	deviceSpec := specs.Device{
@@ -53,6 +53,16 @@ func (l *mofedlib) GetDeviceSpecs() ([]specs.Device, error) {
 	return []specs.Device{deviceSpec}, nil
 }
 
+// newDiscoverer returns the discoverer for the devices of the mode. In dpu
+// mode, the MOFED devices are extended with the devices and mounts required by
+// DPU applications.
+func (l *mofedlib) newDiscoverer() (discover.Discover, error) {
+	if l.mode == ModeDpu {
+		return discover.NewDPUDiscoverer(l.logger, l.devRoot)
+	}
+	return discover.NewMOFEDDiscoverer(l.logger, l.driverRoot)
+}
+
 // GetCommonEdits generates a CDI specification that can be used for ANY devices
 func (l *mofedlib) GetCommonEdits() (*cdi.ContainerEdits, error) {
 	return edits.FromDiscoverer(discover.None{})
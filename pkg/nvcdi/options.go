@@ -21,6 +21,7 @@ import (
 	"github.com/NVIDIA/go-nvlib/pkg/nvlib/info"
 	"github.com/NVIDIA/go-nvml/pkg/nvml"
 
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config/image"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/discover"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
 	"github.com/NVIDIA/nvidia-container-toolkit/pkg/nvcdi/transform"
@@ -142,6 +143,42 @@ func WithCSVIgnorePatterns(csvIgnorePatterns []string) Option {
 	}
 }
 
+// WithMIGStrategy sets the strategy that determines which full GPUs and MIG
+// devices are included in the generated specs.
+func WithMIGStrategy[T string | MIGStrategy](migStrategy T) Option {
+	return func(o *nvcdilib) {
+		o.migStrategy = MIGStrategy(migStrategy)
+	}
+}
+
+// WithDeviceOrder sets the order in which GPUs are enumerated when resolving
+// device indices and when generating the specs for all devices.
+func WithDeviceOrder[T string | DeviceOrder](deviceOrder T) Option {
+	return func(o *nvcdilib) {
+		o.deviceOrder = DeviceOrder(deviceOrder)
+	}
+}
+
+// WithDriverCapabilities sets the driver capabilities requested by a
+// container. Entries in the CSV files that are associated with a set of
+// capabilities are only included if one of these capabilities is requested.
+// If a library filter is enabled, these capabilities are also used to filter
+// the driver libraries. If this is not set, all entries are included.
+func WithDriverCapabilities(driverCapabilities string) Option {
+	return func(o *nvcdilib) {
+		o.driverCapabilities = image.NewDriverCapabilities(driverCapabilities)
+	}
+}
+
+// WithWSLDriverStorePath sets the driver store to use in WSL mode. If this is
+// not set, the driver store is selected from the paths reported for the
+// adapters by dxcore.
+func WithWSLDriverStorePath(path string) Option {
+	return func(o *nvcdilib) {
+		o.wslDriverStorePath = path
+	}
+}
+
 // WithConfigSearchPaths sets the search paths for config files.
 func WithConfigSearchPaths(paths []string) Option {
 	return func(o *nvcdilib) {
@@ -165,6 +202,46 @@ func WithDisabledHook[T string | HookName](hook T) Option {
 	}
 }
 
+// WithHookPath sets the executable used for the specified hook. This
+// overrides the nvidia-cdi-hook path for the hook.
+// This option can be specified multiple times for each hook.
+func WithHookPath[T string | HookName](hook T, path string) Option {
+	return func(o *nvcdilib) {
+		if o.hookPaths == nil {
+			o.hookPaths = make(map[discover.HookName]string)
+		}
+		o.hookPaths[discover.HookName(hook)] = path
+	}
+}
+
+// WithCompat32Libraries sets whether the 32-bit driver libraries on a
+// multi-arch system are included in the generated spec.
+func WithCompat32Libraries(compat32Libraries bool) Option {
+	return func(o *nvcdilib) {
+		o.compat32Libraries = compat32Libraries
+	}
+}
+
+// WithLibraryFilter enables filtering the driver libraries by the driver
+// capabilities set using WithDriverCapabilities. Only the libraries that are
+// required for these capabilities, or that are not in any of the library
+// groups, are included in the generated spec. The specified file optionally
+// overrides the default library groups.
+func WithLibraryFilter(libraryGroupsFile string) Option {
+	return func(o *nvcdilib) {
+		o.filterLibraries = true
+		o.libraryGroupsFile = libraryGroupsFile
+	}
+}
+
+// WithDriverArchiveGPUCount sets the number of GPUs for which device nodes
+// are included in a spec generated for a driver archive.
+func WithDriverArchiveGPUCount(count int) Option {
+	return func(o *nvcdilib) {
+		o.driverArchiveGPUCount = count
+	}
+}
+
 // WithFeatureFlag allows specified features to be toggled on.
 // This option can be specified multiple times for each feature flag.
 func WithFeatureFlag(featureFlag FeatureFlag) Option {
@@ -175,3 +252,12 @@ func WithFeatureFlag(featureFlag FeatureFlag) Option {
 		o.featureFlags[featureFlag] = true
 	}
 }
+
+// WithFeatureFlags allows multiple feature flags to be toggled on.
+func WithFeatureFlags(featureFlags ...FeatureFlag) Option {
+	return func(o *nvcdilib) {
+		for _, featureFlag := range featureFlags {
+			WithFeatureFlag(featureFlag)(o)
+		}
+	}
+}
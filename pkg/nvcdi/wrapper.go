@@ -18,6 +18,7 @@ package nvcdi
 
 import (
 	"fmt"
+	"slices"
 
 	"tags.cncf.io/container-device-interface/pkg/cdi"
 	"tags.cncf.io/container-device-interface/specs-go"
@@ -74,8 +75,18 @@ func (l *wrapper) GetSpec(devices ...string) (spec.Interface, error) {
 
 // GetDeviceSpecsByID returns the CDI device specs for devices with the
 // specified IDs.
-// The device IDs are interpreted by the configured factory.
+// The device IDs are interpreted by the configured factory. The special
+// "none" ID selects no devices and is ignored. This allows a spec containing
+// only the common edits to be generated.
 func (l *wrapper) GetDeviceSpecsByID(devices ...string) ([]specs.Device, error) {
+	if slices.Contains(devices, image.NoneDeviceName) {
+		devices = slices.DeleteFunc(slices.Clone(devices), func(d string) bool {
+			return d == image.NoneDeviceName
+		})
+		if len(devices) == 0 {
+			return nil, nil
+		}
+	}
 	generators, err := l.factory.DeviceSpecGenerators(devices...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to construct device spec generators: %w", err)
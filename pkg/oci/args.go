@@ -94,8 +94,19 @@ func IsBundleFlag(arg string) bool {
 
 // HasCreateSubcommand checks the supplied arguments for a 'create' subcommand
 func HasCreateSubcommand(args []string) bool {
+	return HasSubcommand(args, "create")
+}
+
+// HasSubcommand checks the supplied arguments for the specified subcommand.
+func HasSubcommand(args []string, subcommand string) bool {
+	return GetSubcommandIndex(args, subcommand) >= 0
+}
+
+// GetSubcommandIndex returns the index of the specified subcommand in the
+// supplied arguments or -1 if the subcommand is not present.
+func GetSubcommandIndex(args []string, subcommand string) int {
 	var previousWasBundle bool
-	for _, a := range args {
+	for i, a := range args {
 		// We check for '--bundle create' explicitly to ensure that we
 		// don't inadvertently trigger a modification if the bundle directory
 		// is specified as `create`
@@ -104,12 +115,38 @@ func HasCreateSubcommand(args []string) bool {
 			continue
 		}
 
-		if !previousWasBundle && a == "create" {
-			return true
+		if !previousWasBundle && a == subcommand {
+			return i
 		}
 
 		previousWasBundle = false
 	}
 
-	return false
+	return -1
+}
+
+// GetFlagValue returns the value of the first flag in the supplied arguments
+// matching one of the specified names. Flags can be specified as:
+// --name{{SEP}}VALUE
+// -name{{SEP}}VALUE
+// where {{SEP}} is either ' ' or '='
+func GetFlagValue(args []string, names ...string) string {
+	for i, arg := range args {
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		parts := strings.SplitN(strings.TrimLeft(arg, "-"), "=", 2)
+		for _, name := range names {
+			if parts[0] != name {
+				continue
+			}
+			if len(parts) == 2 {
+				return parts[1]
+			}
+			if i+1 < len(args) {
+				return args[i+1]
+			}
+		}
+	}
+	return ""
 }
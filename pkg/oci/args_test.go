@@ -182,3 +182,69 @@ func TestHasCreateSubcommand(t *testing.T) {
 		require.Equal(t, tc.shouldModify, HasCreateSubcommand(tc.args), "%d: %v", i, tc)
 	}
 }
+
+func TestHasSubcommand(t *testing.T) {
+	testCases := []struct {
+		args          []string
+		subcommand    string
+		expectedIndex int
+	}{
+		{
+			subcommand:    "exec",
+			expectedIndex: -1,
+		},
+		{
+			args:          []string{"runc", "--root", "/run/runc", "exec", "--process", "/tmp/process.json", "id"},
+			subcommand:    "exec",
+			expectedIndex: 3,
+		},
+		{
+			args:          []string{"runc", "--bundle", "update", "create"},
+			subcommand:    "update",
+			expectedIndex: -1,
+		},
+		{
+			args:          []string{"runc", "update", "--resources", "/tmp/resources.json", "id"},
+			subcommand:    "update",
+			expectedIndex: 1,
+		},
+	}
+
+	for i, tc := range testCases {
+		require.Equal(t, tc.expectedIndex, GetSubcommandIndex(tc.args, tc.subcommand), "%d: %v", i, tc)
+		require.Equal(t, tc.expectedIndex >= 0, HasSubcommand(tc.args, tc.subcommand), "%d: %v", i, tc)
+	}
+}
+
+func TestGetFlagValue(t *testing.T) {
+	testCases := []struct {
+		args          []string
+		expectedValue string
+	}{
+		{},
+		{
+			args:          []string{"exec", "--process", "/tmp/process.json", "id"},
+			expectedValue: "/tmp/process.json",
+		},
+		{
+			args:          []string{"exec", "--process=/tmp/process.json", "id"},
+			expectedValue: "/tmp/process.json",
+		},
+		{
+			args:          []string{"exec", "-p", "/tmp/process.json", "id"},
+			expectedValue: "/tmp/process.json",
+		},
+		{
+			args:          []string{"exec", "--process"},
+			expectedValue: "",
+		},
+		{
+			args:          []string{"exec", "--tty", "id", "process"},
+			expectedValue: "",
+		},
+	}
+
+	for i, tc := range testCases {
+		require.Equal(t, tc.expectedValue, GetFlagValue(tc.args, "process", "p"), "%d: %v", i, tc)
+	}
+}
@@ -0,0 +1,119 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package oci
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// HookDiagnosticsAnnotation is the annotation that enables the recording
+	// of hook diagnostics for a container. Its value is the path of the file
+	// in the container bundle that diagnostics are recorded in.
+	HookDiagnosticsAnnotation = "nvidia.com/hook-diagnostics"
+	// HookDiagnosticsFileName is the name of the file in the container bundle
+	// that diagnostics for failed hooks are recorded in.
+	HookDiagnosticsFileName = "nvidia-hook-diagnostics.log"
+
+	hookDiagnosticsErrorPrefix = "error: "
+)
+
+// GetHookDiagnosticsFilePath returns the path of the hook diagnostics file
+// for the specified bundle directory.
+func GetHookDiagnosticsFilePath(bundleDir string) string {
+	return filepath.Join(bundleDir, HookDiagnosticsFileName)
+}
+
+// HookDiagnosticsFilePath returns the path of the file that diagnostics for
+// failed hooks are recorded in. If the recording of diagnostics is not enabled
+// for the container, an empty string is returned.
+//
+// Note that the value of the annotation is not used to construct the path to
+// ensure that a hook never writes outside of the container bundle.
+func (s *State) HookDiagnosticsFilePath() string {
+	if s == nil || s.Bundle == "" {
+		return ""
+	}
+	if _, ok := s.Annotations[HookDiagnosticsAnnotation]; !ok {
+		return ""
+	}
+	return GetHookDiagnosticsFilePath(s.Bundle)
+}
+
+// WriteHookDiagnostics appends a record for a failed hook to the specified
+// diagnostics file. The record includes the error returned by the hook as
+// well as the output of the hook.
+func WriteHookDiagnostics(path string, hook string, hookErr error, output []byte) error {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "hook: %s\n", hook)
+	fmt.Fprintf(&b, "%s%s\n", hookDiagnosticsErrorPrefix, strings.ReplaceAll(hookErr.Error(), "\n", " "))
+	// The output is indented so that it cannot be mistaken for a record.
+	if output := strings.TrimRight(string(output), "\n"); output != "" {
+		b.WriteString("output:\n")
+		for _, line := range strings.Split(output, "\n") {
+			fmt.Fprintf(&b, "  %s\n", line)
+		}
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open diagnostics file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(b.Bytes()); err != nil {
+		return fmt.Errorf("failed to write diagnostics: %w", err)
+	}
+	return nil
+}
+
+// ReadHookDiagnosticsError returns the error of the first failed hook
+// recorded in the specified diagnostics file. If the file does not exist or no
+// hook failure is recorded, an empty string is returned.
+func ReadHookDiagnosticsError(path string) (string, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to open diagnostics file: %w", err)
+	}
+	defer f.Close()
+
+	var hook string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if name, ok := strings.CutPrefix(line, "hook: "); ok {
+			hook = name
+			continue
+		}
+		if message, ok := strings.CutPrefix(line, hookDiagnosticsErrorPrefix); ok {
+			return fmt.Sprintf("%s: %s", hook, message), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("failed to read diagnostics file: %w", err)
+	}
+	return "", nil
+}
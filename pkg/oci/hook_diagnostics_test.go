@@ -0,0 +1,162 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package oci
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHookDiagnosticsFilePath(t *testing.T) {
+	testCases := []struct {
+		description  string
+		state        *State
+		expectedPath string
+	}{
+		{
+			description: "no annotation",
+			state:       &State{Bundle: "/bundle"},
+		},
+		{
+			description: "annotation without bundle",
+			state: &State{
+				Annotations: map[string]string{HookDiagnosticsAnnotation: "/bundle/nvidia-hook-diagnostics.log"},
+			},
+		},
+		{
+			description: "annotation value is not used as path",
+			state: &State{
+				Bundle:      "/bundle",
+				Annotations: map[string]string{HookDiagnosticsAnnotation: "/etc/passwd"},
+			},
+			expectedPath: "/bundle/nvidia-hook-diagnostics.log",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			require.Equal(t, tc.expectedPath, tc.state.HookDiagnosticsFilePath())
+		})
+	}
+}
+
+func TestHookDiagnostics(t *testing.T) {
+	path := filepath.Join(t.TempDir(), HookDiagnosticsFileName)
+
+	hookErr, err := ReadHookDiagnosticsError(path)
+	require.NoError(t, err)
+	require.Empty(t, hookErr)
+
+	output := []byte("error: this is not a record\nlevel=info\n")
+	require.NoError(t, WriteHookDiagnostics(path, "create-symlinks", errors.New("failed to create\nlink"), output))
+	require.NoError(t, WriteHookDiagnostics(path, "update-ldcache", errors.New("second"), nil))
+
+	contents, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, `hook: create-symlinks
+error: failed to create link
+output:
+  error: this is not a record
+  level=info
+hook: update-ldcache
+error: second
+`, string(contents))
+
+	hookErr, err = ReadHookDiagnosticsError(path)
+	require.NoError(t, err)
+	require.Equal(t, "create-symlinks: failed to create link", hookErr)
+}
+
+func TestHookDiagnosticsRuntime(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	testCases := []struct {
+		description   string
+		execError     error
+		hookError     error
+		expectedError string
+	}{
+		{
+			description: "success",
+		},
+		{
+			description:   "runtime error without hook diagnostics",
+			execError:     errors.New("exit status 1"),
+			expectedError: "exit status 1",
+		},
+		{
+			description:   "runtime error with hook diagnostics",
+			execError:     errors.New("exit status 1"),
+			hookError:     errors.New("failed to create link"),
+			expectedError: "exit status 1: hook create-symlinks: failed to create link",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			bundleDir := t.TempDir()
+			path := GetHookDiagnosticsFilePath(bundleDir)
+			// Diagnostics from a previous attempt are removed.
+			require.NoError(t, WriteHookDiagnostics(path, "stale", errors.New("stale"), nil))
+
+			r := hookDiagnosticsRuntime{
+				logger:    logger,
+				bundleDir: bundleDir,
+				runtime: &RuntimeMock{
+					ExecFunc: func(_ []string) error {
+						if tc.hookError != nil {
+							s := &State{Bundle: bundleDir, Annotations: map[string]string{HookDiagnosticsAnnotation: path}}
+							require.NoError(t, WriteHookDiagnostics(s.HookDiagnosticsFilePath(), "create-symlinks", tc.hookError, nil))
+						}
+						return tc.execError
+					},
+				},
+			}
+
+			err := r.Exec([]string{"runtime", "create"})
+			if tc.hookError == nil {
+				require.NoFileExists(t, path)
+			}
+			if tc.expectedError == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tc.expectedError)
+			require.ErrorIs(t, err, tc.execError)
+		})
+	}
+}
+
+func TestHookDiagnosticsRuntimeWrapper(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	mock := &RuntimeMock{}
+	require.Same(t, mock, NewHookDiagnosticsRuntimeWrapper(logger, mock, "/bundle"))
+
+	pr, err := NewRuntimeForPath(logger, "/bin/sh")
+	require.NoError(t, err)
+	wrapped := NewHookDiagnosticsRuntimeWrapper(logger, pr, "/bundle")
+	require.IsType(t, &hookDiagnosticsRuntime{}, wrapped)
+	require.Equal(t, "/bin/sh", wrapped.String())
+	// The wrapped runtime is not modified.
+	require.IsType(t, syscallExec{}, pr.(*pathRuntime).execRuntime)
+}
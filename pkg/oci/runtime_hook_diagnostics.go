@@ -0,0 +1,109 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package oci
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+)
+
+type hookDiagnosticsRuntime struct {
+	logger    logger.Interface
+	runtime   Runtime
+	bundleDir string
+}
+
+var _ Runtime = (*hookDiagnosticsRuntime)(nil)
+
+// NewHookDiagnosticsRuntimeWrapper creates a runtime wrapper that surfaces
+// the diagnostics recorded by failed hooks in the specified bundle directory.
+// Since this requires the low-level runtime to be run as a child process
+// instead of being exec'd into, this is only supported for runtimes
+// constructed for a path. For other runtimes the input runtime is returned.
+func NewHookDiagnosticsRuntimeWrapper(logger logger.Interface, runtime Runtime, bundleDir string) Runtime {
+	pr, ok := runtime.(*pathRuntime)
+	if !ok {
+		logger.Warningf("Hook diagnostics are not supported for runtime %v", runtime.String())
+		return runtime
+	}
+
+	child := *pr
+	child.execRuntime = childProcessExec{}
+
+	r := hookDiagnosticsRuntime{
+		logger:    logger,
+		runtime:   &child,
+		bundleDir: bundleDir,
+	}
+	return &r
+}
+
+// Exec runs the wrapped runtime. If this fails, the diagnostics file in the
+// bundle directory is checked for failed hooks and the first error that was
+// recorded is included in the returned error.
+func (r *hookDiagnosticsRuntime) Exec(args []string) error {
+	path := GetHookDiagnosticsFilePath(r.bundleDir)
+	// We remove diagnostics from a previous attempt to create the container.
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		r.logger.Warningf("Failed to remove existing hook diagnostics: %v", err)
+	}
+
+	err := r.runtime.Exec(args)
+	if err == nil {
+		return nil
+	}
+
+	hookErr, readErr := ReadHookDiagnosticsError(path)
+	if readErr != nil {
+		r.logger.Warningf("Failed to read hook diagnostics: %v", readErr)
+	}
+	if hookErr == "" {
+		return err
+	}
+	return fmt.Errorf("%w: hook %s (see %v)", err, hookErr, path)
+}
+
+// String returns the string representation of the wrapped runtime.
+func (r *hookDiagnosticsRuntime) String() string {
+	return r.runtime.String()
+}
+
+// childProcessExec runs the specified command as a child process with the
+// standard streams of the current process.
+type childProcessExec struct{}
+
+var _ Runtime = (*childProcessExec)(nil)
+
+func (r childProcessExec) Exec(args []string) error {
+	//nolint:gosec // TODO: Can we harden this so that there is less risk of command injection
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to run '%v': %w", args[0], err)
+	}
+	return nil
+}
+
+func (r childProcessExec) String() string {
+	return "child process"
+}
@@ -17,7 +17,10 @@
 package oci
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup"
@@ -29,7 +32,7 @@ import (
 func NewLowLevelRuntime(logger logger.Interface, candidates []string) (Runtime, error) {
 	runtimePath, err := findRuntime(logger, candidates)
 	if err != nil {
-		return nil, fmt.Errorf("error locating runtime: %v", err)
+		return nil, fmt.Errorf("error locating runtime: %w", err)
 	}
 	return NewRuntimeForPath(logger, runtimePath)
 }
@@ -41,6 +44,7 @@ func findRuntime(logger logger.Interface, candidates []string) (string, error) {
 		return "", fmt.Errorf("at least one runtime candidate must be specified")
 	}
 
+	var errs []error
 	locator := lookup.NewExecutableLocator(logger, "/")
 	for _, candidate := range candidates {
 		logger.Tracef("Looking for runtime binary '%v'", candidate)
@@ -49,7 +53,27 @@ func findRuntime(logger logger.Interface, candidates []string) (string, error) {
 			logger.Tracef("Found runtime binary '%v'", targets)
 			return targets[0], nil
 		}
+		errs = append(errs, validateRuntimeCandidate(candidate))
 	}
 
-	return "", fmt.Errorf("no runtime binary found from candidate list: %v", candidates)
+	return "", fmt.Errorf("no runtime binary found from candidate list %v: %w", candidates, errors.Join(errs...))
+}
+
+// validateRuntimeCandidate returns an error describing why the specified
+// candidate could not be used as a low-level runtime.
+func validateRuntimeCandidate(candidate string) error {
+	if !strings.Contains(candidate, "/") {
+		return fmt.Errorf("configured runtime %v not found in PATH", candidate)
+	}
+	info, err := os.Stat(candidate)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("configured runtime %v not found", candidate)
+	}
+	if err != nil {
+		return fmt.Errorf("configured runtime %v is invalid: %w", candidate, err)
+	}
+	if info.IsDir() || info.Mode()&0111 == 0 {
+		return fmt.Errorf("configured runtime %v is not executable", candidate)
+	}
+	return fmt.Errorf("configured runtime %v could not be located", candidate)
 }
@@ -17,11 +17,24 @@
 package oci
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
 )
 
+var (
+	// ErrLoadSpec is returned if the OCI specification cannot be loaded for
+	// modification.
+	ErrLoadSpec = errors.New("error loading OCI specification for modification")
+	// ErrModifySpec is returned if the modifications cannot be applied to the
+	// OCI specification.
+	ErrModifySpec = errors.New("error modifying OCI spec")
+	// ErrFlushSpec is returned if the modified OCI specification cannot be
+	// written.
+	ErrFlushSpec = errors.New("error writing modified OCI specification")
+)
+
 type modifyingRuntimeWrapper struct {
 	logger   logger.Interface
 	runtime  Runtime
@@ -68,17 +81,17 @@ func (r *modifyingRuntimeWrapper) Exec(args []string) error {
 func (r *modifyingRuntimeWrapper) modify() error {
 	_, err := r.ociSpec.Load()
 	if err != nil {
-		return fmt.Errorf("error loading OCI specification for modification: %v", err)
+		return fmt.Errorf("%w: %v", ErrLoadSpec, err)
 	}
 
 	err = r.ociSpec.Modify(r.modifier)
 	if err != nil {
-		return fmt.Errorf("error modifying OCI spec: %v", err)
+		return fmt.Errorf("%w: %v", ErrModifySpec, err)
 	}
 
 	err = r.ociSpec.Flush()
 	if err != nil {
-		return fmt.Errorf("error writing modified OCI specification: %v", err)
+		return fmt.Errorf("%w: %v", ErrFlushSpec, err)
 	}
 	return nil
 }
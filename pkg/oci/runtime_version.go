@@ -0,0 +1,72 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package oci
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"golang.org/x/mod/semver"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+)
+
+var runtimeVersionPattern = regexp.MustCompile(`(?m)version:?\s+v?([0-9]+\.[0-9]+(?:\.[0-9]+)?)`)
+
+// CheckRuntimeVersion ensures that the version of the runtime at the specified
+// path is at least the specified minimum version. If no minimum version is
+// specified, no check is performed. Since this executes the runtime, callers
+// should only perform this check when a container is created.
+func CheckRuntimeVersion(logger logger.Interface, path string, minimumVersion string) error {
+	if minimumVersion == "" {
+		return nil
+	}
+	minimum := "v" + strings.TrimPrefix(minimumVersion, "v")
+	if !semver.IsValid(minimum) {
+		return fmt.Errorf("invalid minimum runtime version %q", minimumVersion)
+	}
+
+	output, err := exec.Command(path, "--version").Output()
+	if err != nil {
+		return fmt.Errorf("failed to determine version of configured runtime %v: %w", path, err)
+	}
+	version, err := parseRuntimeVersion(string(output))
+	if err != nil {
+		return fmt.Errorf("failed to determine version of configured runtime %v: %w", path, err)
+	}
+	logger.Debugf("Found runtime %v with version %v", path, version)
+
+	if semver.Compare("v"+version, minimum) < 0 {
+		return fmt.Errorf("configured runtime %v has version %v; at least %v is required", path, version, strings.TrimPrefix(minimum, "v"))
+	}
+	return nil
+}
+
+// parseRuntimeVersion extracts the version from the output of a runtime's
+// --version flag. The first line containing a version is used:
+//
+//	runc version 1.1.12
+//	commit: v1.1.12-0-g51d5e946
+func parseRuntimeVersion(output string) (string, error) {
+	matches := runtimeVersionPattern.FindStringSubmatch(output)
+	if len(matches) != 2 {
+		return "", fmt.Errorf("no version found in %q", strings.TrimSpace(output))
+	}
+	return matches[1], nil
+}
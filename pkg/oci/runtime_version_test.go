@@ -0,0 +1,119 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package oci
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRuntimeVersion(t *testing.T) {
+	testCases := []struct {
+		description     string
+		output          string
+		expectedVersion string
+		expectedError   bool
+	}{
+		{
+			description: "runc",
+			output: `runc version 1.1.12
+commit: v1.1.12-0-g51d5e946
+spec: 1.0.2-dev
+go: go1.20.13
+libseccomp: 2.5.4
+`,
+			expectedVersion: "1.1.12",
+		},
+		{
+			description:     "crun",
+			output:          "crun version 1.14.1\ncommit: de537a7965bfbe9992e2cfae0baeb56a08128171\n",
+			expectedVersion: "1.14.1",
+		},
+		{
+			description:     "major minor only",
+			output:          "runtime version v2.1\n",
+			expectedVersion: "2.1",
+		},
+		{
+			description:   "no version",
+			output:        "unknown flag: --version\n",
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			version, err := parseRuntimeVersion(tc.output)
+			if tc.expectedError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tc.expectedVersion, version)
+		})
+	}
+}
+
+func TestCheckRuntimeVersion(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	runtimePath := filepath.Join(t.TempDir(), "runc")
+	require.NoError(t, os.WriteFile(runtimePath, []byte("#!/bin/sh\necho runc version 1.1.12\n"), 0755))
+
+	testCases := []struct {
+		description    string
+		minimumVersion string
+		expectedError  bool
+	}{
+		{
+			description: "no minimum version",
+		},
+		{
+			description:    "older minimum version",
+			minimumVersion: "1.1.0",
+		},
+		{
+			description:    "equal minimum version",
+			minimumVersion: "v1.1.12",
+		},
+		{
+			description:    "newer minimum version",
+			minimumVersion: "1.2.0",
+			expectedError:  true,
+		},
+		{
+			description:    "invalid minimum version",
+			minimumVersion: "latest",
+			expectedError:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			err := CheckRuntimeVersion(logger, runtimePath, tc.minimumVersion)
+			if tc.expectedError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -17,6 +17,7 @@
 package oci
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -28,6 +29,10 @@ import (
 type fileSpec struct {
 	memorySpec
 	path string
+	// original is the JSON encoding of the spec as loaded from file. This is
+	// used to preserve fields that are not supported by the vendored
+	// runtime-spec version when the spec is flushed.
+	original []byte
 }
 
 var _ Spec = (*fileSpec)(nil)
@@ -46,17 +51,17 @@ func NewFileSpec(filepath string) Spec {
 // Load reads the contents of an OCI spec from file to be referenced internally.
 // The file is opened "read-only"
 func (s *fileSpec) Load() (*specs.Spec, error) {
-	specFile, err := os.Open(s.path)
+	contents, err := os.ReadFile(s.path)
 	if err != nil {
 		return nil, fmt.Errorf("error opening OCI specification file: %v", err)
 	}
-	defer specFile.Close()
 
-	spec, err := LoadFrom(specFile)
+	spec, err := LoadFrom(bytes.NewReader(contents))
 	if err != nil {
 		return nil, fmt.Errorf("error loading OCI specification from file: %v", err)
 	}
 	s.Spec = spec
+	s.original = contents
 	return s.Spec, nil
 }
 
@@ -92,6 +97,9 @@ func (s fileSpec) Flush() error {
 	}
 	defer specFile.Close()
 
+	if s.original != nil {
+		return flushWithUnknownFields(s.original, s.Spec, specFile)
+	}
 	return flushTo(s.Spec, specFile)
 }
 
@@ -0,0 +1,138 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package oci
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+)
+
+// flushWithUnknownFields writes the specified OCI specification to the
+// specified io.Writer, preserving the fields of the original JSON encoding
+// that are not supported by the vendored runtime-spec version. This ensures
+// that fields added in newer runtime-spec versions are not dropped when the
+// specification is modified.
+// If the original JSON encoding contains no unsupported fields, the
+// specification is written as is.
+func flushWithUnknownFields(original []byte, spec *specs.Spec, writer io.Writer) error {
+	if spec == nil {
+		return nil
+	}
+
+	var originalValue interface{}
+	if err := json.Unmarshal(original, &originalValue); err != nil {
+		return flushTo(spec, writer)
+	}
+	var originalSpec specs.Spec
+	if err := json.Unmarshal(original, &originalSpec); err != nil {
+		return flushTo(spec, writer)
+	}
+	knownValue, err := toGeneric(&originalSpec)
+	if err != nil {
+		return fmt.Errorf("error encoding OCI specification: %v", err)
+	}
+	modifiedValue, err := toGeneric(spec)
+	if err != nil {
+		return fmt.Errorf("error encoding OCI specification: %v", err)
+	}
+
+	merged, hasUnknownFields := addUnknownFields(modifiedValue, originalValue, knownValue)
+	if !hasUnknownFields {
+		return flushTo(spec, writer)
+	}
+
+	if err := json.NewEncoder(writer).Encode(merged); err != nil {
+		return fmt.Errorf("error writing OCI specification: %v", err)
+	}
+	return nil
+}
+
+// toGeneric converts the specified value to its generic JSON representation.
+func toGeneric(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, err
+	}
+	return generic, nil
+}
+
+// addUnknownFields adds the fields from the original value that are not
+// present in the known value (i.e. the original value as decoded and encoded
+// using the vendored runtime-spec types) to the modified value. For lists, the
+// unknown fields of an entry are only preserved if the entry was not modified.
+// The returned boolean indicates whether any unknown fields were added.
+func addUnknownFields(modified interface{}, original interface{}, known interface{}) (interface{}, bool) {
+	switch modifiedValue := modified.(type) {
+	case map[string]interface{}:
+		originalMap, ok := original.(map[string]interface{})
+		if !ok {
+			return modified, false
+		}
+		knownMap, _ := known.(map[string]interface{})
+
+		var added bool
+		for key, originalEntry := range originalMap {
+			knownEntry, isKnown := knownMap[key]
+			modifiedEntry, isModified := modifiedValue[key]
+			switch {
+			case !isKnown && !isModified:
+				modifiedValue[key] = originalEntry
+				added = true
+			case isKnown && isModified:
+				var entryAdded bool
+				modifiedValue[key], entryAdded = addUnknownFields(modifiedEntry, originalEntry, knownEntry)
+				added = added || entryAdded
+			}
+		}
+		return modifiedValue, added
+	case []interface{}:
+		originalList, ok := original.([]interface{})
+		if !ok {
+			return modified, false
+		}
+		knownList, ok := known.([]interface{})
+		if !ok || len(knownList) != len(originalList) {
+			return modified, false
+		}
+
+		var added bool
+		used := make([]bool, len(knownList))
+		for i, modifiedEntry := range modifiedValue {
+			for j, knownEntry := range knownList {
+				if used[j] || !reflect.DeepEqual(modifiedEntry, knownEntry) {
+					continue
+				}
+				used[j] = true
+				if !reflect.DeepEqual(originalList[j], knownEntry) {
+					modifiedValue[i] = originalList[j]
+					added = true
+				}
+				break
+			}
+		}
+		return modifiedValue, added
+	}
+	return modified, false
+}
@@ -0,0 +1,84 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package oci
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlushWithUnknownFields(t *testing.T) {
+	testCases := []struct {
+		description string
+		original    string
+		modify      func(*specs.Spec)
+		expected    string
+	}{
+		{
+			description: "spec without unknown fields",
+			original:    `{"ociVersion":"1.2.0","hostname":"test"}`,
+			modify: func(s *specs.Spec) {
+				s.Hostname = "modified"
+			},
+			expected: `{"ociVersion":"1.2.0","hostname":"modified"}`,
+		},
+		{
+			description: "unknown top-level and nested fields are preserved",
+			original:    `{"ociVersion":"1.3.0","future":{"a":1},"linux":{"memoryPolicy":{"mode":"MPOL_BIND"}}}`,
+			modify: func(s *specs.Spec) {
+				s.Linux.MaskedPaths = []string{"/proc/masked"}
+			},
+			expected: `{"ociVersion":"1.3.0","future":{"a":1},"linux":{"maskedPaths":["/proc/masked"],"memoryPolicy":{"mode":"MPOL_BIND"}}}`,
+		},
+		{
+			description: "unknown fields of unmodified list entries are preserved",
+			original:    `{"ociVersion":"1.3.0","mounts":[{"destination":"/a","future":true}]}`,
+			modify: func(s *specs.Spec) {
+				s.Mounts = append(s.Mounts, specs.Mount{Destination: "/b"})
+			},
+			expected: `{"ociVersion":"1.3.0","mounts":[{"destination":"/a","future":true},{"destination":"/b"}]}`,
+		},
+		{
+			description: "removed list entries are not restored",
+			original:    `{"ociVersion":"1.3.0","future":true,"mounts":[{"destination":"/a","future":true}]}`,
+			modify: func(s *specs.Spec) {
+				s.Mounts = nil
+			},
+			expected: `{"ociVersion":"1.3.0","future":true}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			spec, err := LoadFrom(bytes.NewReader([]byte(tc.original)))
+			require.NoError(t, err)
+			tc.modify(spec)
+
+			var buffer bytes.Buffer
+			require.NoError(t, flushWithUnknownFields([]byte(tc.original), spec, &buffer))
+
+			var expected, actual interface{}
+			require.NoError(t, json.Unmarshal([]byte(tc.expected), &expected))
+			require.NoError(t, json.Unmarshal(buffer.Bytes(), &actual))
+			require.EqualValues(t, expected, actual)
+		})
+	}
+}
@@ -0,0 +1,227 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+// Package policy evaluates the rules that the NVIDIA Container Toolkit applies
+// to the GPU requests of containers on a node. This allows admission webhooks
+// to reject containers that would be rejected, or whose requests would be
+// ignored, by the NVIDIA Container Runtime.
+package policy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config/image"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+)
+
+// A Policy defines the rules for the GPU requests of containers on a node.
+type Policy struct {
+	// SupportedDriverCapabilities are the driver capabilities that a container
+	// may request.
+	SupportedDriverCapabilities []string `json:"supportedDriverCapabilities"`
+	// AcceptEnvvarUnprivileged indicates whether devices requested through
+	// envvars are accepted for unprivileged containers.
+	AcceptEnvvarUnprivileged bool `json:"acceptEnvvarUnprivileged"`
+	// AcceptDeviceListAsVolumeMounts indicates whether devices can be
+	// requested through volume mounts.
+	AcceptDeviceListAsVolumeMounts bool `json:"acceptDeviceListAsVolumeMounts"`
+	// AnnotationPrefixes are the prefixes of the annotations that are used to
+	// request CDI devices.
+	AnnotationPrefixes []string `json:"annotationPrefixes,omitempty"`
+	// VisibleDevicesAll defines the devices that a request for all devices is
+	// mapped to.
+	VisibleDevicesAll string `json:"visibleDevicesAll,omitempty"`
+	// IgnoreImexChannelRequests indicates whether IMEX channel requests are
+	// ignored.
+	IgnoreImexChannelRequests bool `json:"ignoreImexChannelRequests"`
+	// MaxDevices is the maximum number of devices that a container may
+	// request. If this is 0, the number of devices is not limited.
+	MaxDevices int `json:"maxDevices,omitempty"`
+}
+
+// A Container describes the properties of a container that are relevant to
+// the evaluation of a Policy.
+type Container struct {
+	// Env is the environment of the container as a list of NAME=VALUE pairs.
+	// This should include the environment defined in the image.
+	Env []string
+	// Privileged indicates whether the container is privileged.
+	Privileged bool
+	// Annotations are the annotations of the container.
+	Annotations map[string]string
+	// Mounts are the paths in the container at which /dev/null is mounted to
+	// request devices (e.g. /var/run/nvidia-container-devices/GPU-<uuid>).
+	Mounts []string
+}
+
+// A Result is the outcome of evaluating a Policy for a container.
+type Result struct {
+	// Devices are the devices that are injected into the container.
+	Devices []string `json:"devices,omitempty"`
+	// Violations are the reasons why the container would be rejected.
+	Violations []string `json:"violations,omitempty"`
+	// Warnings describe requests that would be ignored.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// Allowed indicates whether the container is allowed by the policy.
+func (r *Result) Allowed() bool {
+	return len(r.Violations) == 0
+}
+
+// Load loads the policy of the node from the NVIDIA Container Toolkit config
+// file at the specified path. If the path is empty, the default config file
+// is used.
+func Load(configFilePath string) (*Policy, error) {
+	if configFilePath == "" {
+		configFilePath = config.GetConfigFilePath()
+	}
+	configToml, err := config.New(
+		config.WithConfigFile(configFilePath),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	cfg, err := configToml.Config()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	return FromConfig(cfg), nil
+}
+
+// FromConfig returns the policy defined by the specified config.
+func FromConfig(cfg *config.Config) *Policy {
+	supported := image.NewDriverCapabilities(cfg.SupportedDriverCapabilities)
+	if supported.IsAll() {
+		supported = image.SupportedDriverCapabilities
+	}
+	return &Policy{
+		SupportedDriverCapabilities:    supported.List(),
+		AcceptEnvvarUnprivileged:       cfg.AcceptEnvvarUnprivileged,
+		AcceptDeviceListAsVolumeMounts: cfg.AcceptDeviceListAsVolumeMounts,
+		AnnotationPrefixes:             cfg.NVIDIAContainerRuntimeConfig.Modes.CDI.AnnotationPrefixes,
+		VisibleDevicesAll:              cfg.VisibleDevicesAll,
+		IgnoreImexChannelRequests:      cfg.Features.IgnoreImexChannelRequests.IsEnabled(),
+		MaxDevices:                     cfg.NVIDIAContainerRuntimeConfig.MaxDevices,
+	}
+}
+
+// Check evaluates the policy for the specified container.
+func (p *Policy) Check(c Container) (*Result, error) {
+	var mounts []specs.Mount
+	for _, m := range c.Mounts {
+		mounts = append(mounts, specs.Mount{Source: "/dev/null", Destination: m})
+	}
+	cudaImage, err := image.New(
+		image.WithLogger(&logger.NullLogger{}),
+		image.WithEnv(c.Env),
+		image.WithPrivileged(c.Privileged),
+		image.WithAnnotations(c.Annotations),
+		image.WithMounts(mounts),
+		image.WithAcceptDeviceListAsVolumeMounts(p.AcceptDeviceListAsVolumeMounts),
+		image.WithAcceptEnvvarUnprivileged(p.AcceptEnvvarUnprivileged),
+		image.WithAnnotationsPrefixes(p.AnnotationPrefixes),
+		image.WithVisibleDevicesAll(p.VisibleDevicesAll),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct container image: %w", err)
+	}
+
+	result := &Result{}
+	result.Devices = requestedDevices(cudaImage.VisibleDevices())
+	if len(result.Devices) == 0 && !c.Privileged && !p.AcceptEnvvarUnprivileged {
+		if envDevices := requestedDevices(image.NewVisibleDevices(cudaImage.Getenv(image.EnvVarNvidiaVisibleDevices)).List()); len(envDevices) > 0 {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("devices %v requested through envvars are ignored for unprivileged containers", envDevices))
+		}
+	}
+	if err := p.CheckDevices(result.Devices); err != nil {
+		result.Violations = append(result.Violations, err.Error())
+	}
+	if err := p.checkDriverCapabilities(cudaImage); err != nil {
+		result.Violations = append(result.Violations, err.Error())
+	}
+	if p.IgnoreImexChannelRequests {
+		channels := cudaImage.ImexChannelsFromEnvVar()
+		if p.AcceptDeviceListAsVolumeMounts {
+			channels = append(channels, cudaImage.ImexChannelsFromMounts()...)
+		}
+		if len(channels) > 0 {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("IMEX channel requests %v are ignored", channels))
+		}
+	}
+	return result, nil
+}
+
+// CheckDevices checks whether the specified device requests are allowed by
+// the policy. A request for all devices exceeds any device limit since the
+// number of devices on the node is not known.
+func (p *Policy) CheckDevices(devices []string) error {
+	if p.MaxDevices <= 0 {
+		return nil
+	}
+	devices = requestedDevices(devices)
+	for _, device := range devices {
+		if device == "all" || strings.HasSuffix(device, "=all") {
+			return fmt.Errorf("requesting all devices is not allowed with a limit of %d devices", p.MaxDevices)
+		}
+	}
+	if len(devices) > p.MaxDevices {
+		return fmt.Errorf("%d devices requested; at most %d devices are allowed", len(devices), p.MaxDevices)
+	}
+	return nil
+}
+
+// checkDriverCapabilities checks whether the driver capabilities requested
+// by the container are supported. As is the case for the
+// nvidia-container-runtime-hook, a request for all capabilities is allowed.
+func (p *Policy) checkDriverCapabilities(cudaImage image.CUDA) error {
+	requested := cudaImage.Getenv(image.EnvVarNvidiaDriverCapabilities)
+	if requested == "" {
+		return nil
+	}
+	capabilities := image.NewDriverCapabilities(requested)
+	if capabilities.IsAll() {
+		return nil
+	}
+	supported := image.NewDriverCapabilities(p.SupportedDriverCapabilities...)
+	var unsupported []string
+	for _, capability := range capabilities.List() {
+		if !supported.Has(image.DriverCapability(capability)) {
+			unsupported = append(unsupported, capability)
+		}
+	}
+	if len(unsupported) > 0 {
+		return fmt.Errorf("unsupported driver capabilities %v requested (supported: %v)", unsupported, supported.String())
+	}
+	return nil
+}
+
+// requestedDevices filters the empty device that represents a request for no
+// devices.
+func requestedDevices(devices []string) []string {
+	var requested []string
+	for _, device := range devices {
+		if device == "" {
+			continue
+		}
+		requested = append(requested, device)
+	}
+	return requested
+}
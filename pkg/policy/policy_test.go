@@ -0,0 +1,134 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package policy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheck(t *testing.T) {
+	testCases := []struct {
+		description    string
+		policy         Policy
+		container      Container
+		expectedResult *Result
+	}{
+		{
+			description: "no devices requested is allowed",
+			policy:      Policy{MaxDevices: 1},
+			container:   Container{Env: []string{"PATH=/usr/bin"}},
+			expectedResult: &Result{
+				Devices: nil,
+			},
+		},
+		{
+			description: "devices within limit are allowed",
+			policy:      Policy{MaxDevices: 2, AcceptEnvvarUnprivileged: true},
+			container:   Container{Env: []string{"NVIDIA_VISIBLE_DEVICES=0,1"}},
+			expectedResult: &Result{
+				Devices: []string{"0", "1"},
+			},
+		},
+		{
+			description: "devices exceeding limit are rejected",
+			policy:      Policy{MaxDevices: 1, AcceptEnvvarUnprivileged: true},
+			container:   Container{Env: []string{"NVIDIA_VISIBLE_DEVICES=0,1"}},
+			expectedResult: &Result{
+				Devices:    []string{"0", "1"},
+				Violations: []string{"2 devices requested; at most 1 devices are allowed"},
+			},
+		},
+		{
+			description: "all devices is rejected with limit",
+			policy:      Policy{MaxDevices: 4},
+			container:   Container{Env: []string{"NVIDIA_VISIBLE_DEVICES=all"}, Privileged: true},
+			expectedResult: &Result{
+				Devices:    []string{"all"},
+				Violations: []string{"requesting all devices is not allowed with a limit of 4 devices"},
+			},
+		},
+		{
+			description: "all devices is mapped to visible devices all",
+			policy:      Policy{MaxDevices: 2, VisibleDevicesAll: "0,1", AcceptEnvvarUnprivileged: true},
+			container:   Container{Env: []string{"NVIDIA_VISIBLE_DEVICES=all"}},
+			expectedResult: &Result{
+				Devices: []string{"0", "1"},
+			},
+		},
+		{
+			description: "envvar requests in unprivileged container are ignored",
+			policy:      Policy{},
+			container:   Container{Env: []string{"NVIDIA_VISIBLE_DEVICES=0"}},
+			expectedResult: &Result{
+				Warnings: []string{"devices [0] requested through envvars are ignored for unprivileged containers"},
+			},
+		},
+		{
+			description: "volume mount requests are accepted if enabled",
+			policy:      Policy{AcceptDeviceListAsVolumeMounts: true},
+			container:   Container{Mounts: []string{"/var/run/nvidia-container-devices/GPU-1"}},
+			expectedResult: &Result{
+				Devices: []string{"GPU-1"},
+			},
+		},
+		{
+			description: "unsupported driver capabilities are rejected",
+			policy:      Policy{SupportedDriverCapabilities: []string{"compute", "utility"}},
+			container:   Container{Env: []string{"NVIDIA_DRIVER_CAPABILITIES=compute,graphics,video"}},
+			expectedResult: &Result{
+				Violations: []string{"unsupported driver capabilities [graphics video] requested (supported: compute,utility)"},
+			},
+		},
+		{
+			description:    "all driver capabilities are allowed",
+			policy:         Policy{SupportedDriverCapabilities: []string{"compute", "utility"}},
+			container:      Container{Env: []string{"NVIDIA_DRIVER_CAPABILITIES=all"}},
+			expectedResult: &Result{},
+		},
+		{
+			description: "imex channel requests are ignored",
+			policy:      Policy{IgnoreImexChannelRequests: true},
+			container:   Container{Env: []string{"NVIDIA_IMEX_CHANNELS=0"}},
+			expectedResult: &Result{
+				Warnings: []string{"IMEX channel requests [0] are ignored"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			result, err := tc.policy.Check(tc.container)
+			require.NoError(t, err)
+			require.EqualValues(t, tc.expectedResult, result)
+			require.Equal(t, len(tc.expectedResult.Violations) == 0, result.Allowed())
+		})
+	}
+}
+
+func TestCheckDevices(t *testing.T) {
+	p := Policy{MaxDevices: 1}
+	require.NoError(t, p.CheckDevices(nil))
+	require.NoError(t, p.CheckDevices([]string{""}))
+	require.NoError(t, p.CheckDevices([]string{"nvidia.com/gpu=0"}))
+	require.Error(t, p.CheckDevices([]string{"nvidia.com/gpu=all"}))
+	require.Error(t, p.CheckDevices([]string{"0", "1"}))
+
+	unlimited := Policy{}
+	require.NoError(t, unlimited.CheckDevices([]string{"all"}))
+}